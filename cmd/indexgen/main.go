@@ -0,0 +1,110 @@
+// Command indexgen adds the composite indexes the sortable list endpoints
+// need to firestore.indexes.json. Indexes already in the file are kept, so
+// hand-written ones for other queries are untouched; run it after changing
+// a model.ListSchema:
+//
+//	go run ./cmd/indexgen [-file firestore.indexes.json]
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/pandasWhoCode/paintbar/internal/repository"
+)
+
+// indexFile is the layout of firestore.indexes.json.
+type indexFile struct {
+	Indexes        []repository.Index `json:"indexes"`
+	FieldOverrides json.RawMessage    `json:"fieldOverrides"`
+}
+
+// Coverage: thin command wrapper — merge and formatting are deterministic
+// and checked by TestFirestoreIndexes_CoverListSchemas.
+func main() {
+	path := flag.String("file", "firestore.indexes.json", "index file to update")
+	flag.Parse()
+
+	raw, err := os.ReadFile(*path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "indexgen:", err)
+		os.Exit(1)
+	}
+	var file indexFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		fmt.Fprintf(os.Stderr, "indexgen: parse %s: %v\n", *path, err)
+		os.Exit(1)
+	}
+
+	added := 0
+	for _, idx := range repository.ListIndexes() {
+		if !hasIndex(file.Indexes, idx) {
+			file.Indexes = insertIndex(file.Indexes, idx)
+			added++
+		}
+	}
+
+	if err := os.WriteFile(*path, format(file), 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "indexgen:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("indexgen: %d indexes added to %s\n", added, *path)
+}
+
+func hasIndex(indexes []repository.Index, idx repository.Index) bool {
+	for _, existing := range indexes {
+		if reflect.DeepEqual(existing, idx) {
+			return true
+		}
+	}
+	return false
+}
+
+// insertIndex places idx after the last index on the same collection, or at
+// the end, so the file stays grouped by collection.
+func insertIndex(indexes []repository.Index, idx repository.Index) []repository.Index {
+	at := len(indexes)
+	for i, existing := range indexes {
+		if existing.CollectionGroup == idx.CollectionGroup {
+			at = i + 1
+		}
+	}
+	indexes = append(indexes, repository.Index{})
+	copy(indexes[at+1:], indexes[at:])
+	indexes[at] = idx
+	return indexes
+}
+
+// format writes the file in its hand-maintained style: one line per field.
+func format(file indexFile) []byte {
+	var b bytes.Buffer
+	b.WriteString("{\n  \"indexes\": [\n")
+	for i, idx := range file.Indexes {
+		fmt.Fprintf(&b, "    {\n      \"collectionGroup\": %q,\n      \"queryScope\": %q,\n      \"fields\": [\n", idx.CollectionGroup, idx.QueryScope)
+		for j, f := range idx.Fields {
+			line, _ := json.Marshal(f)
+			line = bytes.ReplaceAll(line, []byte(`":"`), []byte(`": "`))
+			line = bytes.ReplaceAll(line, []byte(`","`), []byte(`", "`))
+			fmt.Fprintf(&b, "        { %s }", bytes.Trim(line, "{}"))
+			if j < len(idx.Fields)-1 {
+				b.WriteString(",")
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("      ]\n    }")
+		if i < len(file.Indexes)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	overrides := file.FieldOverrides
+	if len(overrides) == 0 {
+		overrides = json.RawMessage("[]")
+	}
+	fmt.Fprintf(&b, "  ],\n  \"fieldOverrides\": %s\n}\n", overrides)
+	return b.Bytes()
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pandasWhoCode/paintbar/pkg/client"
+)
+
+// credentials are the API URL and token saved by login.
+type credentials struct {
+	URL   string `json:"url"`
+	Token string `json:"token"`
+}
+
+// configPath is PAINTBAR_CONFIG, or credentials.json in the user's config
+// directory (~/.config/paintbar on Linux).
+func (c *cli) configPath() (string, error) {
+	if p := c.getenv("PAINTBAR_CONFIG"); p != "" {
+		return p, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "paintbar", "credentials.json"), nil
+}
+
+// credentials reads the saved credentials; PAINTBAR_URL and PAINTBAR_TOKEN
+// override them.
+func (c *cli) credentials() (*credentials, error) {
+	creds := &credentials{}
+	path, err := c.configPath()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("read credentials: %w", err)
+	default:
+		if err := json.Unmarshal(raw, creds); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+	}
+	if v := c.getenv("PAINTBAR_URL"); v != "" {
+		creds.URL = v
+	}
+	if v := c.getenv("PAINTBAR_TOKEN"); v != "" {
+		creds.Token = v
+	}
+	if creds.URL == "" {
+		creds.URL = DefaultURL
+	}
+	return creds, nil
+}
+
+// login checks a personal access token against the API and saves it. The
+// token comes from --token or the first line of stdin.
+func (c *cli) login(ctx context.Context, args []string) error {
+	flags := c.flags("login")
+	baseURL := flags.String("url", "", "API URL (default $PAINTBAR_URL or "+DefaultURL+")")
+	token := flags.String("token", "", "personal access token (default: read from stdin)")
+	if _, err := c.parse(flags, args, 0, 0); err != nil {
+		return err
+	}
+
+	creds, err := c.credentials()
+	if err != nil {
+		return err
+	}
+	if *baseURL != "" {
+		creds.URL = *baseURL
+	}
+	if u, err := url.Parse(creds.URL); err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid URL %q", creds.URL)
+	}
+	creds.Token = *token
+	if creds.Token == "" {
+		fmt.Fprintf(c.stderr, "Paste a personal access token for %s: ", creds.URL)
+		line, err := bufio.NewReader(c.stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return errors.New("no token given")
+		}
+		creds.Token = strings.TrimSpace(line)
+	}
+
+	if err := c.newAPI(creds.URL, creds.Token).Ping(ctx); err != nil {
+		if errors.Is(err, client.ErrUnauthorized) {
+			return errors.New("the token was rejected")
+		}
+		return err
+	}
+
+	path, err := c.configPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("save credentials: %w", err)
+	}
+	raw, _ := json.MarshalIndent(creds, "", "  ")
+	if err := os.WriteFile(path, append(raw, '\n'), 0o600); err != nil {
+		return fmt.Errorf("save credentials: %w", err)
+	}
+	return c.print(map[string]string{"url": creds.URL, "config": path},
+		[]string{"URL", "CONFIG"}, [][]string{{creds.URL, path}})
+}
+
+// logout deletes the saved credentials.
+func (c *cli) logout(args []string) error {
+	flags := c.flags("logout")
+	if _, err := c.parse(flags, args, 0, 0); err != nil {
+		return err
+	}
+	path, err := c.configPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("remove credentials: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pandasWhoCode/paintbar/pkg/client"
+)
+
+// exportResult is the output of export: how many records of each kind were
+// written.
+type exportResult struct {
+	Dir      string `json:"dir"`
+	Projects int    `json:"projects"`
+	Blobs    int    `json:"blobs"`
+	Gallery  int    `json:"gallery"`
+	NFTs     int    `json:"nfts"`
+}
+
+// export writes the user's projects, their PNGs, gallery items and NFTs to
+// a directory:
+//
+//	projects.json       full project records
+//	projects/<id>.png   project blobs
+//	gallery.json        gallery items
+//	nfts.json           NFT records
+//
+// Sections the token's scopes do not allow are skipped with a warning.
+func (c *cli) export(ctx context.Context, args []string) error {
+	flags := c.flags("export")
+	dir := flags.String("o", "paintbar-export", "output directory")
+	noBlobs := flags.Bool("no-blobs", false, "skip the project PNGs")
+	if _, err := c.parse(flags, args, 0, 0); err != nil {
+		return err
+	}
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(*dir, "projects"), 0o755); err != nil {
+		return err
+	}
+	out := exportResult{Dir: *dir}
+
+	projects, err := collect(ctx, api.AllProjects, api.GetProject, func(p client.ProjectSummary) string { return p.ID })
+	if err = c.section(err, "projects"); err != nil {
+		return err
+	}
+	if projects != nil {
+		if err := writeJSON(filepath.Join(*dir, "projects.json"), projects); err != nil {
+			return err
+		}
+		out.Projects = len(projects)
+	}
+	for _, p := range projects {
+		if *noBlobs || p.StorageURL == "" {
+			continue
+		}
+		if _, err := c.downloadFile(ctx, api, p.ID, filepath.Join(*dir, "projects", p.ID+".png")); err != nil {
+			if !errors.Is(err, client.ErrNotFound) {
+				return err
+			}
+			fmt.Fprintf(c.stderr, "paintbar: project %s has no blob, skipped\n", p.ID)
+			continue
+		}
+		out.Blobs++
+	}
+
+	gallery, err := collect(ctx, api.AllGalleryItems, api.GetGalleryItem, func(g client.GallerySummary) string { return g.ID })
+	if err = c.section(err, "gallery items"); err != nil {
+		return err
+	}
+	if gallery != nil {
+		if err := writeJSON(filepath.Join(*dir, "gallery.json"), gallery); err != nil {
+			return err
+		}
+		out.Gallery = len(gallery)
+	}
+
+	nfts, err := collect(ctx, api.AllNFTs, api.GetNFT, func(n client.NFTSummary) string { return n.ID })
+	if err = c.section(err, "NFTs"); err != nil {
+		return err
+	}
+	if nfts != nil {
+		if err := writeJSON(filepath.Join(*dir, "nfts.json"), nfts); err != nil {
+			return err
+		}
+		out.NFTs = len(nfts)
+	}
+
+	return c.print(out, []string{"DIR", "PROJECTS", "BLOBS", "GALLERY", "NFTS"}, [][]string{{
+		out.Dir, strconv.Itoa(out.Projects), strconv.Itoa(out.Blobs), strconv.Itoa(out.Gallery), strconv.Itoa(out.NFTs),
+	}})
+}
+
+// section turns a forbidden section into a warning.
+func (c *cli) section(err error, name string) error {
+	if errors.Is(err, client.ErrForbidden) {
+		fmt.Fprintf(c.stderr, "paintbar: the token cannot read %s, skipped\n", name)
+		return nil
+	}
+	return err
+}
+
+// collect lists every summary and fetches its full record.
+func collect[S, T any](ctx context.Context,
+	all func(context.Context, *client.ListOptions) iter.Seq2[S, error],
+	get func(context.Context, string) (*T, error),
+	id func(S) string,
+) ([]*T, error) {
+	records := []*T{}
+	for s, err := range all(ctx, &client.ListOptions{Sort: "createdAt", Order: "asc"}) {
+		if err != nil {
+			return nil, err
+		}
+		record, err := get(ctx, id(s))
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func writeJSON(path string, v interface{}) error {
+	raw, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(raw, '\n'), 0o644)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+
+	"github.com/pandasWhoCode/paintbar/pkg/client"
+)
+
+// galleryShare shares a project to the gallery with its thumbnail, size and
+// tags.
+func (c *cli) galleryShare(ctx context.Context, args []string) error {
+	flags := c.flags("gallery share")
+	name := flags.String("name", "", "gallery item name (default: the project title)")
+	description := flags.String("description", "", "gallery item description")
+	var tags stringList
+	flags.Var(&tags, "tag", "tag, repeatable or comma-separated (default: the project's tags)")
+	ids, err := c.parse(flags, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+	p, err := api.GetProject(ctx, ids[0])
+	if err != nil {
+		return err
+	}
+
+	item := &client.GalleryItemCreate{
+		Name:          p.Title,
+		Description:   *description,
+		ProjectID:     p.ID,
+		ThumbnailData: p.ThumbnailData,
+		Width:         p.Width,
+		Height:        p.Height,
+		Tags:          p.Tags,
+	}
+	if *name != "" {
+		item.Name = *name
+	}
+	if len(tags) > 0 {
+		item.Tags = splitTags(tags)
+	}
+	id, err := api.ShareToGallery(ctx, item)
+	if err != nil {
+		return err
+	}
+	out := map[string]string{"id": id, "projectId": p.ID, "name": item.Name}
+	return c.print(out, []string{"ID", "PROJECT", "NAME"}, [][]string{{id, p.ID, item.Name}})
+}
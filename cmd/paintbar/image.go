@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// thumbnailMaxSize is the longest side of a pushed project's thumbnail,
+// matching the canvas's THUMBNAIL_MAX_SIZE.
+const thumbnailMaxSize = 256
+
+// pngFile is a PNG read for push.
+type pngFile struct {
+	data          []byte
+	contentHash   string // lowercase hex SHA-256 of data
+	width, height int
+	thumbnailData string // data URL
+}
+
+// readPNG decodes data, hashes it and renders its thumbnail.
+func readPNG(data []byte) (*pngFile, error) {
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("not a PNG: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	thumb, err := thumbnail(img, thumbnailMaxSize)
+	if err != nil {
+		return nil, err
+	}
+	b := img.Bounds()
+	return &pngFile{
+		data:          data,
+		contentHash:   hex.EncodeToString(sum[:]),
+		width:         b.Dx(),
+		height:        b.Dy(),
+		thumbnailData: thumb,
+	}, nil
+}
+
+// thumbnail scales img to fit in limit×limit, averaging the source pixels
+// under each thumbnail pixel, and returns it as a PNG data URL. Images
+// already small enough are only re-encoded.
+func thumbnail(img image.Image, limit int) (string, error) {
+	b := img.Bounds()
+	scale := min(float64(limit)/float64(b.Dx()), float64(limit)/float64(b.Dy()), 1)
+	w := max(int(float64(b.Dx())*scale+0.5), 1)
+	h := max(int(float64(b.Dy())*scale+0.5), 1)
+
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0, y1 := b.Min.Y+y*b.Dy()/h, b.Min.Y+(y+1)*b.Dy()/h
+		for x := 0; x < w; x++ {
+			x0, x1 := b.Min.X+x*b.Dx()/w, b.Min.X+(x+1)*b.Dx()/w
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < max(y1, y0+1); sy++ {
+				for sx := x0; sx < max(x1, x0+1); sx++ {
+					// Premultiplied, so transparent pixels do not darken edges
+					pr, pg, pb, pa := img.At(sx, sy).RGBA()
+					r, g, bl, a, n = r+uint64(pr), g+uint64(pg), bl+uint64(pb), a+uint64(pa), n+1
+				}
+			}
+			dst.Set(x, y, color.RGBA64{uint16(r / n), uint16(g / n), uint16(bl / n), uint16(a / n)})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, dst); err != nil {
+		return "", fmt.Errorf("encode thumbnail: %w", err)
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
@@ -0,0 +1,192 @@
+// Command paintbar manages PaintBar projects from the command line:
+//
+//	paintbar login --token pbt_...
+//	paintbar projects push sunset.png --title Sunset --tag sky
+//	paintbar projects ls --format json
+//	paintbar projects pull <id> -o sunset.png
+//	paintbar gallery share <project-id>
+//	paintbar export -o backup/
+//
+// It talks to the API through pkg/client, authenticated with a personal
+// access token saved by login or given in PAINTBAR_TOKEN.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/pandasWhoCode/paintbar/pkg/client"
+)
+
+// DefaultURL is the API used when neither login nor PAINTBAR_URL set one.
+const DefaultURL = "https://paintbar.art"
+
+const usage = `Usage: paintbar <command> [flags] [args]
+
+Commands:
+  login                        save a personal access token
+  logout                       forget the saved token
+  projects ls                  list projects
+  projects get <id>            show a project
+  projects rm <id>...          delete projects
+  projects tag <id> <tag>...   add tags (--remove to take them off)
+  projects publish <id>        make a project public (--private to undo)
+  projects push <file.png>     create or update a project from a PNG
+  projects pull <id>           download a project's PNG
+  gallery share <project-id>   share a project to the gallery
+  export                       save all projects and metadata to a directory
+
+Every command accepts --format table|json.
+Environment: PAINTBAR_URL, PAINTBAR_TOKEN, PAINTBAR_CONFIG.
+`
+
+// errUsage is returned for a malformed command line; main prints the usage
+// and exits with status 2.
+var errUsage = errors.New("usage")
+
+// cli is one invocation. Tests build it with fake streams and environment.
+type cli struct {
+	stdout, stderr io.Writer
+	stdin          io.Reader
+	getenv         func(string) string
+
+	format string // table or json
+	newAPI func(baseURL, token string) *client.Client
+}
+
+// Coverage: process wiring only; commands run through cli.run in tests.
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	c := &cli{
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+		stdin:  os.Stdin,
+		getenv: os.Getenv,
+		newAPI: func(baseURL, token string) *client.Client {
+			return client.New(baseURL, client.WithToken(token), client.WithUserAgent("paintbar-cli"))
+		},
+	}
+	if err := c.run(ctx, os.Args[1:]); err != nil {
+		if errors.Is(err, errUsage) {
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "paintbar:", err)
+		os.Exit(1)
+	}
+}
+
+// run dispatches a command line.
+func (c *cli) run(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		return errUsage
+	}
+	cmd, args := args[0], args[1:]
+	switch cmd {
+	case "login":
+		return c.login(ctx, args)
+	case "logout":
+		return c.logout(args)
+	case "export":
+		return c.export(ctx, args)
+	}
+
+	if len(args) == 0 {
+		return errUsage
+	}
+	sub, args := args[0], args[1:]
+	switch cmd + " " + sub {
+	case "projects ls":
+		return c.projectsList(ctx, args)
+	case "projects get":
+		return c.projectsGet(ctx, args)
+	case "projects rm":
+		return c.projectsRemove(ctx, args)
+	case "projects tag":
+		return c.projectsTag(ctx, args)
+	case "projects publish":
+		return c.projectsPublish(ctx, args)
+	case "projects push":
+		return c.projectsPush(ctx, args)
+	case "projects pull":
+		return c.projectsPull(ctx, args)
+	case "gallery share":
+		return c.galleryShare(ctx, args)
+	}
+	return errUsage
+}
+
+// flags returns a flag set for a command with the shared --format flag.
+func (c *cli) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.StringVar(&c.format, "format", "table", "output format: table or json")
+	return fs
+}
+
+// parse parses args, allowing flags after positional arguments, and checks
+// the number of positional arguments is between min and max (-1 for any).
+func (c *cli) parse(fs *flag.FlagSet, args []string, min, max int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, errUsage
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(positional) < min || (max >= 0 && len(positional) > max) {
+		fmt.Fprintf(c.stderr, "paintbar %s: wrong number of arguments\n", fs.Name())
+		return nil, errUsage
+	}
+	if c.format != "table" && c.format != "json" {
+		return nil, fmt.Errorf("unknown --format %q: use table or json", c.format)
+	}
+	return positional, nil
+}
+
+// api returns a client for the saved or environment credentials.
+func (c *cli) api() (*client.Client, error) {
+	creds, err := c.credentials()
+	if err != nil {
+		return nil, err
+	}
+	if creds.Token == "" {
+		return nil, errors.New("not logged in: run `paintbar login` or set PAINTBAR_TOKEN")
+	}
+	return c.newAPI(creds.URL, creds.Token), nil
+}
+
+// splitTags splits comma-separated --tag values.
+func splitTags(values []string) []string {
+	var tags []string
+	for _, v := range values {
+		for _, tag := range strings.Split(v, ",") {
+			if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
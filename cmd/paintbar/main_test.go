@@ -0,0 +1,535 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pandasWhoCode/paintbar/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAPI is an in-memory PaintBar API covering what the CLI calls.
+type fakeAPI struct {
+	t  *testing.T
+	mu sync.Mutex
+
+	token      string
+	projects   map[string]*client.Project
+	blobs      map[string][]byte
+	gallery    map[string]*client.GalleryItem
+	nftsDenied bool
+
+	uploads  map[string][]byte // resumable upload buffers by project
+	requests []string          // "METHOD /path" of each request
+	nextID   int
+}
+
+func newFakeAPI(t *testing.T) *fakeAPI {
+	return &fakeAPI{
+		t:        t,
+		token:    "pbt_test",
+		projects: make(map[string]*client.Project),
+		blobs:    make(map[string][]byte),
+		gallery:  make(map[string]*client.GalleryItem),
+		uploads:  make(map[string][]byte),
+	}
+}
+
+func (f *fakeAPI) id(prefix string) string {
+	f.nextID++
+	return fmt.Sprintf("%s%d", prefix, f.nextID)
+}
+
+func (f *fakeAPI) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/ping", func(w http.ResponseWriter, r *http.Request) {
+		writeJSONResponse(w, 200, map[string]string{"data": "pong"})
+	})
+	mux.HandleFunc("GET /api/v1/projects", func(w http.ResponseWriter, r *http.Request) {
+		var items []client.ProjectSummary
+		for _, p := range f.sortedProjects() {
+			items = append(items, client.ProjectSummary{ID: p.ID, Title: p.Title, Width: p.Width, Height: p.Height, IsPublic: p.IsPublic, Tags: p.Tags})
+		}
+		// Two per page, cursor is the index of the next page
+		start := 0
+		fmt.Sscan(r.URL.Query().Get("startAfter"), &start)
+		end := min(start+2, len(items))
+		page := client.Page[client.ProjectSummary]{Items: items[start:end], Total: int64(len(items)), HasMore: end < len(items)}
+		if page.HasMore {
+			page.NextCursor = fmt.Sprint(end)
+		}
+		writeJSONResponse(w, 200, page)
+	})
+	mux.HandleFunc("POST /api/v1/projects", func(w http.ResponseWriter, r *http.Request) {
+		var in client.ProjectCreate
+		require.NoError(f.t, json.NewDecoder(r.Body).Decode(&in))
+		for _, p := range f.projects {
+			if p.ContentHash == in.ContentHash {
+				writeJSONResponse(w, 201, client.CreateProjectResult{ProjectID: p.ID, Duplicate: true})
+				return
+			}
+		}
+		p := &client.Project{ID: f.id("p"), Title: in.Title, ContentHash: in.ContentHash, ThumbnailData: in.ThumbnailData,
+			Width: in.Width, Height: in.Height, IsPublic: in.IsPublic, Tags: in.Tags}
+		f.projects[p.ID] = p
+		writeJSONResponse(w, 201, client.CreateProjectResult{ProjectID: p.ID})
+	})
+	mux.HandleFunc("GET /api/v1/projects/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if p := f.project(w, r); p != nil {
+			writeJSONResponse(w, 200, p)
+		}
+	})
+	mux.HandleFunc("PUT /api/v1/projects/{id}", func(w http.ResponseWriter, r *http.Request) {
+		p := f.project(w, r)
+		if p == nil {
+			return
+		}
+		var in map[string]json.RawMessage
+		require.NoError(f.t, json.NewDecoder(r.Body).Decode(&in))
+		if raw, ok := in["tags"]; ok {
+			p.Tags = nil
+			require.NoError(f.t, json.Unmarshal(raw, &p.Tags))
+		}
+		if raw, ok := in["isPublic"]; ok {
+			require.NoError(f.t, json.Unmarshal(raw, &p.IsPublic))
+		}
+		writeJSONResponse(w, 200, map[string]string{"status": "updated"})
+	})
+	mux.HandleFunc("DELETE /api/v1/projects/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if p := f.project(w, r); p != nil {
+			delete(f.projects, p.ID)
+			writeJSONResponse(w, 200, map[string]string{"status": "deleted"})
+		}
+	})
+	mux.HandleFunc("POST /api/v1/projects/{id}/upload-blob", func(w http.ResponseWriter, r *http.Request) {
+		if p := f.project(w, r); p != nil {
+			assert.Equal(f.t, "image/png", r.Header.Get("Content-Type"))
+			f.blobs[p.ID], _ = io.ReadAll(r.Body)
+			writeJSONResponse(w, 200, map[string]string{"status": "uploaded"})
+		}
+	})
+	mux.HandleFunc("POST /api/v1/projects/{id}/confirm-upload", func(w http.ResponseWriter, r *http.Request) {
+		if p := f.project(w, r); p != nil {
+			p.StorageURL = "gs://blobs/" + p.ContentHash
+			writeJSONResponse(w, 200, map[string]string{"status": "confirmed"})
+		}
+	})
+	mux.HandleFunc("GET /api/v1/projects/{id}/blob", func(w http.ResponseWriter, r *http.Request) {
+		p := f.project(w, r)
+		if p == nil {
+			return
+		}
+		blob, ok := f.blobs[p.ID]
+		if !ok {
+			writeJSONResponse(w, 404, map[string]string{"error": "blob not found"})
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("ETag", `"`+p.ContentHash+`"`)
+		w.Write(blob)
+	})
+	mux.HandleFunc("POST /api/v1/projects/{id}/uploads", func(w http.ResponseWriter, r *http.Request) {
+		p := f.project(w, r)
+		if p == nil {
+			return
+		}
+		var in struct {
+			Size int64 `json:"size"`
+		}
+		require.NoError(f.t, json.NewDecoder(r.Body).Decode(&in))
+		f.uploads[p.ID] = nil
+		writeJSONResponse(w, 201, client.UploadSession{UploadID: "u-" + p.ID, ProjectID: p.ID, Size: in.Size, ContentHash: p.ContentHash, Status: "open"})
+	})
+	mux.HandleFunc("PUT /api/v1/projects/{id}/uploads/{uploadId}", func(w http.ResponseWriter, r *http.Request) {
+		p := f.project(w, r)
+		if p == nil {
+			return
+		}
+		var start, end, total int64
+		_, err := fmt.Sscanf(r.Header.Get("Content-Range"), "bytes %d-%d/%d", &start, &end, &total)
+		require.NoError(f.t, err)
+		require.Equal(f.t, int64(len(f.uploads[p.ID])), start, "chunks arrive in order")
+		chunk, _ := io.ReadAll(r.Body)
+		require.Equal(f.t, end-start+1, int64(len(chunk)))
+		f.uploads[p.ID] = append(f.uploads[p.ID], chunk...)
+		writeJSONResponse(w, 200, client.UploadSession{UploadID: r.PathValue("uploadId"), ProjectID: p.ID, Size: total, Offset: end + 1, Status: "open"})
+	})
+	mux.HandleFunc("POST /api/v1/projects/{id}/uploads/{uploadId}/finalize", func(w http.ResponseWriter, r *http.Request) {
+		if p := f.project(w, r); p != nil {
+			f.blobs[p.ID] = f.uploads[p.ID]
+			p.StorageURL = "gs://blobs/" + p.ContentHash
+			writeJSONResponse(w, 202, client.UploadSession{UploadID: r.PathValue("uploadId"), ProjectID: p.ID, Status: "finalizing", JobID: "j-" + p.ID})
+		}
+	})
+	mux.HandleFunc("GET /api/v1/jobs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		writeJSONResponse(w, 200, client.Job{ID: r.PathValue("id"), Type: "upload.finalize", Status: client.JobSucceeded})
+	})
+	mux.HandleFunc("GET /api/v1/gallery", func(w http.ResponseWriter, r *http.Request) {
+		page := client.Page[client.GallerySummary]{Items: []client.GallerySummary{}}
+		for _, g := range f.gallery {
+			page.Items = append(page.Items, client.GallerySummary{ID: g.ID, Name: g.Name})
+		}
+		page.Total = int64(len(page.Items))
+		writeJSONResponse(w, 200, page)
+	})
+	mux.HandleFunc("POST /api/v1/gallery", func(w http.ResponseWriter, r *http.Request) {
+		var item client.GalleryItem
+		require.NoError(f.t, json.NewDecoder(r.Body).Decode(&item))
+		item.ID = f.id("g")
+		f.gallery[item.ID] = &item
+		writeJSONResponse(w, 201, map[string]string{"id": item.ID})
+	})
+	mux.HandleFunc("GET /api/v1/gallery/{id}", func(w http.ResponseWriter, r *http.Request) {
+		writeJSONResponse(w, 200, f.gallery[r.PathValue("id")])
+	})
+	mux.HandleFunc("GET /api/v1/nfts", func(w http.ResponseWriter, r *http.Request) {
+		if f.nftsDenied {
+			writeJSONResponse(w, 403, map[string]string{"error": "not available to API tokens"})
+			return
+		}
+		writeJSONResponse(w, 200, client.Page[client.NFTSummary]{Items: []client.NFTSummary{}})
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+		if r.Header.Get("Authorization") != "Bearer "+f.token {
+			writeJSONResponse(w, 401, map[string]string{"error": "invalid token"})
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func (f *fakeAPI) project(w http.ResponseWriter, r *http.Request) *client.Project {
+	p, ok := f.projects[r.PathValue("id")]
+	if !ok {
+		writeJSONResponse(w, 404, map[string]string{"error": "project not found"})
+		return nil
+	}
+	return p
+}
+
+func (f *fakeAPI) sortedProjects() []*client.Project {
+	var ps []*client.Project
+	for i := 1; i <= f.nextID; i++ {
+		if p, ok := f.projects[fmt.Sprintf("p%d", i)]; ok {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
+
+func writeJSONResponse(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// harness runs CLI commands against a fakeAPI with a private config file.
+type harness struct {
+	t      *testing.T
+	api    *fakeAPI
+	srv    *httptest.Server
+	dir    string
+	env    map[string]string
+	stdin  string
+	stdout bytes.Buffer
+	stderr bytes.Buffer
+}
+
+func newHarness(t *testing.T) *harness {
+	h := &harness{t: t, api: newFakeAPI(t), dir: t.TempDir()}
+	h.srv = httptest.NewServer(h.api.handler())
+	t.Cleanup(h.srv.Close)
+	h.env = map[string]string{
+		"PAINTBAR_CONFIG": filepath.Join(h.dir, "config", "credentials.json"),
+		"PAINTBAR_URL":    h.srv.URL,
+		"PAINTBAR_TOKEN":  h.api.token,
+	}
+	return h
+}
+
+// run runs a command line and returns its error; output accumulates in
+// h.stdout and h.stderr, reset per call.
+func (h *harness) run(args ...string) error {
+	h.stdout.Reset()
+	h.stderr.Reset()
+	c := &cli{
+		stdout: &h.stdout,
+		stderr: &h.stderr,
+		stdin:  strings.NewReader(h.stdin),
+		getenv: func(k string) string { return h.env[k] },
+		newAPI: func(baseURL, token string) *client.Client {
+			return client.New(baseURL, client.WithToken(token))
+		},
+	}
+	return c.run(context.Background(), args)
+}
+
+// writePNG writes a w×h PNG with a gradient, so each size hashes
+// differently, and returns its path and bytes.
+func (h *harness) writePNG(name string, w, ht int) (string, []byte) {
+	img := image.NewNRGBA(image.Rect(0, 0, w, ht))
+	for y := 0; y < ht; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.NRGBA{uint8(x), uint8(y), uint8(x ^ y), 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(h.t, png.Encode(&buf, img))
+	path := filepath.Join(h.dir, name)
+	require.NoError(h.t, os.WriteFile(path, buf.Bytes(), 0o644))
+	return path, buf.Bytes()
+}
+
+func TestRun_Usage(t *testing.T) {
+	h := newHarness(t)
+	for _, args := range [][]string{nil, {"help"}, {"projects"}, {"projects", "frobnicate"}, {"nope"}, {"projects", "get"}, {"projects", "get", "a", "b"}} {
+		assert.ErrorIs(t, h.run(args...), errUsage, args)
+	}
+	assert.ErrorContains(t, h.run("projects", "ls", "--format", "yaml"), "unknown --format")
+}
+
+func TestLogin_SavesCredentials(t *testing.T) {
+	h := newHarness(t)
+	delete(h.env, "PAINTBAR_TOKEN")
+	path := h.env["PAINTBAR_CONFIG"]
+
+	h.stdin = "pbt_wrong\n"
+	assert.ErrorContains(t, h.run("login"), "rejected")
+	assert.NoFileExists(t, path)
+
+	assert.ErrorContains(t, h.run("projects", "ls"), "not logged in")
+
+	h.stdin = "pbt_test\n"
+	require.NoError(t, h.run("login", "--format", "json"))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	var creds credentials
+	raw, _ := os.ReadFile(path)
+	require.NoError(t, json.Unmarshal(raw, &creds))
+	assert.Equal(t, credentials{URL: h.srv.URL, Token: "pbt_test"}, creds)
+
+	// The saved URL is used once PAINTBAR_URL is gone
+	delete(h.env, "PAINTBAR_URL")
+	require.NoError(t, h.run("projects", "ls"))
+
+	require.NoError(t, h.run("logout"))
+	assert.NoFileExists(t, path)
+	assert.ErrorContains(t, h.run("projects", "ls"), "not logged in")
+}
+
+func TestProjectsPush_CreatesAndUploads(t *testing.T) {
+	h := newHarness(t)
+	path, data := h.writePNG("sunset.png", 600, 300)
+	sum := sha256.Sum256(data)
+
+	require.NoError(t, h.run("projects", "push", path, "--tag", "Sky,sea", "--tag", "warm", "--public", "--format", "json"))
+	var out pushResult
+	require.NoError(t, json.Unmarshal(h.stdout.Bytes(), &out))
+	assert.Equal(t, "sunset", out.Title, "the title defaults to the file name")
+	assert.Equal(t, hex.EncodeToString(sum[:]), out.ContentHash)
+	assert.False(t, out.Duplicate)
+
+	p := h.api.projects[out.ProjectID]
+	require.NotNil(t, p)
+	assert.Equal(t, []string{"sky", "sea", "warm"}, p.Tags)
+	assert.True(t, p.IsPublic)
+	assert.Equal(t, 600, p.Width)
+	assert.Equal(t, data, h.api.blobs[p.ID])
+	assert.NotEmpty(t, p.StorageURL, "the upload is confirmed")
+
+	thumb, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(p.ThumbnailData, "data:image/png;base64,"))
+	require.NoError(t, err)
+	cfg, err := png.DecodeConfig(bytes.NewReader(thumb))
+	require.NoError(t, err)
+	assert.Equal(t, [2]int{256, 128}, [2]int{cfg.Width, cfg.Height})
+
+	// Pushing the same content again uploads nothing
+	h.api.requests = nil
+	require.NoError(t, h.run("projects", "push", "--title", "Again", path))
+	assert.Contains(t, h.stdout.String(), "yes")
+	assert.Equal(t, []string{"POST /api/v1/projects"}, h.api.requests)
+
+	assert.ErrorContains(t, h.run("projects", "push", filepath.Join(h.dir, "missing.png")), "no such file")
+	require.NoError(t, os.WriteFile(filepath.Join(h.dir, "fake.png"), []byte("not a png"), 0o644))
+	assert.ErrorContains(t, h.run("projects", "push", filepath.Join(h.dir, "fake.png")), "not a PNG")
+}
+
+func TestProjectsPush_ResumableUpload(t *testing.T) {
+	h := newHarness(t)
+	defer func(blob, chunk int, poll time.Duration) {
+		maxBlobSize, uploadChunkSize, jobPollInterval = blob, chunk, poll
+	}(maxBlobSize, uploadChunkSize, jobPollInterval)
+	maxBlobSize, uploadChunkSize, jobPollInterval = 100, 64, time.Millisecond
+
+	path, data := h.writePNG("big.png", 40, 40)
+	require.Greater(t, len(data), 2*uploadChunkSize)
+	require.NoError(t, h.run("projects", "push", path))
+
+	p := h.api.sortedProjects()[0]
+	assert.Equal(t, data, h.api.blobs[p.ID])
+	assert.Contains(t, h.api.requests, "GET /api/v1/jobs/j-"+p.ID)
+	assert.NotContains(t, h.api.requests, "POST /api/v1/projects/"+p.ID+"/upload-blob")
+}
+
+func TestProjectsPull_WritesVerifiedFile(t *testing.T) {
+	h := newHarness(t)
+	path, data := h.writePNG("sky.png", 8, 8)
+	require.NoError(t, h.run("projects", "push", path))
+	id := h.api.sortedProjects()[0].ID
+
+	out := filepath.Join(h.dir, "out.png")
+	require.NoError(t, h.run("projects", "pull", id, "-o", out))
+	got, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+
+	require.NoError(t, h.run("projects", "pull", id, "-o", "-"))
+	assert.Equal(t, data, h.stdout.Bytes())
+
+	// A blob that does not match its hash is not written
+	h.api.blobs[id] = []byte("corrupt")
+	bad := filepath.Join(h.dir, "bad.png")
+	assert.ErrorContains(t, h.run("projects", "pull", id, "-o", bad), "does not match")
+	assert.NoFileExists(t, bad)
+	entries, _ := os.ReadDir(h.dir)
+	for _, e := range entries {
+		assert.False(t, strings.HasPrefix(e.Name(), ".paintbar-"), "temp file %s left behind", e.Name())
+	}
+}
+
+func TestProjectsList_TableAndJSON(t *testing.T) {
+	h := newHarness(t)
+	for i, name := range []string{"a", "b", "c"} {
+		path, _ := h.writePNG(name+".png", 4+i, 4)
+		require.NoError(t, h.run("projects", "push", path, "--tag", name))
+	}
+
+	require.NoError(t, h.run("projects", "ls"))
+	lines := strings.Split(strings.TrimSpace(h.stdout.String()), "\n")
+	require.Len(t, lines, 4, "a header and a row per project across pages")
+	assert.Regexp(t, `^ID\s+TITLE\s+SIZE\s+PUBLIC\s+TAGS\s+UPDATED$`, lines[0])
+	assert.Regexp(t, `^p\d+\s+a\s+4x4\s+no\s+a\s+-$`, lines[1])
+
+	require.NoError(t, h.run("projects", "ls", "--limit", "2", "--format", "json"))
+	var projects []client.ProjectSummary
+	require.NoError(t, json.Unmarshal(h.stdout.Bytes(), &projects))
+	assert.Len(t, projects, 2)
+
+	assert.ErrorContains(t, h.run("projects", "ls", "--visibility", "hidden"), "visibility")
+}
+
+func TestProjectsTagPublishRemove(t *testing.T) {
+	h := newHarness(t)
+	path, _ := h.writePNG("sky.png", 4, 4)
+	require.NoError(t, h.run("projects", "push", path, "--tag", "one"))
+	id := h.api.sortedProjects()[0].ID
+
+	require.NoError(t, h.run("projects", "tag", id, "two", "One", "--remove", "x"))
+	assert.Equal(t, []string{"one", "two"}, h.api.projects[id].Tags)
+	require.NoError(t, h.run("projects", "tag", id, "--remove", "one,two"))
+	assert.Equal(t, []string{}, h.api.projects[id].Tags, "removing every tag sends an empty list")
+	assert.Error(t, h.run("projects", "tag", id))
+
+	require.NoError(t, h.run("projects", "publish", id))
+	assert.True(t, h.api.projects[id].IsPublic)
+	require.NoError(t, h.run("projects", "publish", "--private", id))
+	assert.False(t, h.api.projects[id].IsPublic)
+
+	require.NoError(t, h.run("projects", "get", id, "--format", "json"))
+	assert.Contains(t, h.stdout.String(), `"title": "sky"`)
+
+	require.NoError(t, h.run("projects", "rm", id))
+	assert.Empty(t, h.api.projects)
+	err := h.run("projects", "get", id)
+	assert.ErrorIs(t, err, client.ErrNotFound)
+}
+
+func TestGalleryShare_CopiesProject(t *testing.T) {
+	h := newHarness(t)
+	path, _ := h.writePNG("sky.png", 4, 4)
+	require.NoError(t, h.run("projects", "push", path, "--tag", "sky"))
+	p := h.api.sortedProjects()[0]
+
+	require.NoError(t, h.run("gallery", "share", p.ID, "--description", "Blue"))
+	require.Len(t, h.api.gallery, 1)
+	for _, item := range h.api.gallery {
+		assert.Equal(t, "sky", item.Name)
+		assert.Equal(t, "Blue", item.Description)
+		assert.Equal(t, p.ID, item.ProjectID)
+		assert.Equal(t, p.ThumbnailData, item.ThumbnailData)
+		assert.Equal(t, []string{"sky"}, item.Tags)
+	}
+}
+
+func TestExport_WritesRecordsAndBlobs(t *testing.T) {
+	h := newHarness(t)
+	path, data := h.writePNG("sky.png", 4, 4)
+	require.NoError(t, h.run("projects", "push", path))
+	p := h.api.sortedProjects()[0]
+	require.NoError(t, h.run("gallery", "share", p.ID))
+	h.api.nftsDenied = true
+
+	dir := filepath.Join(h.dir, "backup")
+	require.NoError(t, h.run("export", "-o", dir, "--format", "json"))
+	var out exportResult
+	require.NoError(t, json.Unmarshal(h.stdout.Bytes(), &out))
+	assert.Equal(t, exportResult{Dir: dir, Projects: 1, Blobs: 1, Gallery: 1}, out)
+	assert.Contains(t, h.stderr.String(), "cannot read NFTs")
+
+	blob, err := os.ReadFile(filepath.Join(dir, "projects", p.ID+".png"))
+	require.NoError(t, err)
+	assert.Equal(t, data, blob)
+	var projects []client.Project
+	raw, _ := os.ReadFile(filepath.Join(dir, "projects.json"))
+	require.NoError(t, json.Unmarshal(raw, &projects))
+	require.Len(t, projects, 1)
+	assert.Equal(t, p.ThumbnailData, projects[0].ThumbnailData, "full records, not summaries")
+	assert.FileExists(t, filepath.Join(dir, "gallery.json"))
+	assert.NoFileExists(t, filepath.Join(dir, "nfts.json"))
+}
+
+func TestThumbnail_FitsAndAverages(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 2; y++ {
+			if x%2 == 0 {
+				img.Set(x, y, color.NRGBA{255, 255, 255, 255})
+			} else {
+				img.Set(x, y, color.NRGBA{0, 0, 0, 255})
+			}
+		}
+	}
+	data, err := thumbnail(img, 2)
+	require.NoError(t, err)
+	raw, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(data, "data:image/png;base64,"))
+	thumb, err := png.Decode(bytes.NewReader(raw))
+	require.NoError(t, err)
+
+	assert.Equal(t, image.Rect(0, 0, 2, 1), thumb.Bounds())
+	r, _, _, a := thumb.At(0, 0).RGBA()
+	assert.InDelta(t, 0x7fff, r, 0x200, "black and white columns average to grey")
+	assert.Equal(t, uint32(0xffff), a)
+}
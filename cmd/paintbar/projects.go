@@ -0,0 +1,379 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pandasWhoCode/paintbar/pkg/client"
+)
+
+// Upload limits, mirroring the server's: blobs up to maxBlobSize go in one
+// request, larger ones through a resumable upload in uploadChunkSize chunks.
+// Variables so tests can take the resumable path with small files.
+var (
+	maxBlobSize      = 10 << 20
+	maxResumableSize = 50 << 20
+	uploadChunkSize  = 8 << 20
+
+	// jobPollInterval is how often push checks on the finalize job
+	jobPollInterval = time.Second
+)
+
+var projectHeader = []string{"ID", "TITLE", "SIZE", "PUBLIC", "TAGS", "UPDATED"}
+
+func (c *cli) projectsList(ctx context.Context, args []string) error {
+	flags := c.flags("projects ls")
+	limit := flags.Int("limit", 0, "show at most this many projects (0 for all)")
+	sort := flags.String("sort", "", "sort by createdAt, updatedAt or title")
+	order := flags.String("order", "", "asc or desc")
+	tag := flags.String("tag", "", "only projects with this tag")
+	visibility := flags.String("visibility", "", "only public or private projects")
+	if _, err := c.parse(flags, args, 0, 0); err != nil {
+		return err
+	}
+	opts := &client.ListOptions{Sort: *sort, Order: *order, Tag: *tag}
+	switch *visibility {
+	case "":
+	case "public", "private":
+		public := *visibility == "public"
+		opts.IsPublic = &public
+	default:
+		return fmt.Errorf("unknown --visibility %q: use public or private", *visibility)
+	}
+	if *limit > 0 && *limit < 50 {
+		opts.Limit = *limit
+	}
+
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+	projects := []client.ProjectSummary{}
+	for p, err := range api.AllProjects(ctx, opts) {
+		if err != nil {
+			return err
+		}
+		projects = append(projects, p)
+		if len(projects) == *limit {
+			break
+		}
+	}
+
+	rows := make([][]string, len(projects))
+	for i, p := range projects {
+		rows[i] = []string{p.ID, p.Title, size(p.Width, p.Height), yesNo(p.IsPublic), list(p.Tags), when(p.UpdatedAt)}
+	}
+	return c.print(projects, projectHeader, rows)
+}
+
+func (c *cli) projectsGet(ctx context.Context, args []string) error {
+	flags := c.flags("projects get")
+	ids, err := c.parse(flags, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+	p, err := api.GetProject(ctx, ids[0])
+	if err != nil {
+		return err
+	}
+	return c.printProject(p)
+}
+
+func (c *cli) printProject(p *client.Project) error {
+	return c.print(p, projectHeader, [][]string{
+		{p.ID, p.Title, size(p.Width, p.Height), yesNo(p.IsPublic), list(p.Tags), when(p.UpdatedAt)},
+	})
+}
+
+func (c *cli) projectsRemove(ctx context.Context, args []string) error {
+	flags := c.flags("projects rm")
+	ids, err := c.parse(flags, args, 1, -1)
+	if err != nil {
+		return err
+	}
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+	var rows [][]string
+	for _, id := range ids {
+		if err := api.DeleteProject(ctx, id); err != nil {
+			return err
+		}
+		rows = append(rows, []string{id})
+	}
+	return c.print(map[string][]string{"deleted": ids}, []string{"DELETED"}, rows)
+}
+
+// projectsTag adds tags to a project, or removes the --remove ones.
+func (c *cli) projectsTag(ctx context.Context, args []string) error {
+	flags := c.flags("projects tag")
+	var remove stringList
+	flags.Var(&remove, "remove", "tag to remove, repeatable or comma-separated")
+	pos, err := c.parse(flags, args, 1, -1)
+	if err != nil {
+		return err
+	}
+	add := splitTags(pos[1:])
+	if len(add) == 0 && len(remove) == 0 {
+		return errors.New("give tags to add, or --remove")
+	}
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+	p, err := api.GetProject(ctx, pos[0])
+	if err != nil {
+		return err
+	}
+
+	removed := splitTags(remove)
+	tags := []string{}
+	for _, tag := range p.Tags {
+		if !slices.Contains(removed, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	for _, tag := range add {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	if err := api.UpdateProject(ctx, p.ID, &client.ProjectUpdate{Tags: tags}); err != nil {
+		return err
+	}
+	p.Tags = tags
+	return c.printProject(p)
+}
+
+func (c *cli) projectsPublish(ctx context.Context, args []string) error {
+	flags := c.flags("projects publish")
+	private := flags.Bool("private", false, "make the project private instead")
+	ids, err := c.parse(flags, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+	public := !*private
+	if err := api.UpdateProject(ctx, ids[0], &client.ProjectUpdate{IsPublic: &public}); err != nil {
+		return err
+	}
+	p, err := api.GetProject(ctx, ids[0])
+	if err != nil {
+		return err
+	}
+	return c.printProject(p)
+}
+
+// pushResult is the output of projects push.
+type pushResult struct {
+	ProjectID   string `json:"projectId"`
+	Title       string `json:"title"`
+	ContentHash string `json:"contentHash"`
+	Size        int    `json:"size"`
+	// Duplicate is set when a project with the same content already
+	// existed, so nothing was uploaded
+	Duplicate bool `json:"duplicate"`
+}
+
+// projectsPush creates or updates the project titled --title from a PNG
+// file and uploads the file as its blob.
+func (c *cli) projectsPush(ctx context.Context, args []string) error {
+	flags := c.flags("projects push")
+	title := flags.String("title", "", "project title (default: the file name without .png)")
+	public := flags.Bool("public", false, "make the project public")
+	var tags stringList
+	flags.Var(&tags, "tag", "tag, repeatable or comma-separated")
+	files, err := c.parse(flags, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	path := files[0]
+	if *title == "" {
+		*title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	if info, err := os.Stat(path); err != nil {
+		return err
+	} else if info.Size() > int64(maxResumableSize) {
+		return fmt.Errorf("%s is %d bytes; the limit is %d", path, info.Size(), maxResumableSize)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	img, err := readPNG(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+	res, err := api.CreateProject(ctx, &client.ProjectCreate{
+		Title:         *title,
+		ContentHash:   img.contentHash,
+		ThumbnailData: img.thumbnailData,
+		Width:         img.width,
+		Height:        img.height,
+		IsPublic:      *public,
+		Tags:          splitTags(tags),
+	})
+	if err != nil {
+		return err
+	}
+	if !res.Duplicate {
+		if err := c.uploadBlob(ctx, api, res.ProjectID, img); err != nil {
+			return fmt.Errorf("project %s saved, but its upload failed: %w", res.ProjectID, err)
+		}
+	}
+
+	out := pushResult{
+		ProjectID:   res.ProjectID,
+		Title:       *title,
+		ContentHash: img.contentHash,
+		Size:        len(img.data),
+		Duplicate:   res.Duplicate,
+	}
+	return c.print(out, []string{"ID", "TITLE", "BYTES", "DUPLICATE"}, [][]string{
+		{out.ProjectID, out.Title, strconv.Itoa(out.Size), yesNo(out.Duplicate)},
+	})
+}
+
+// uploadBlob stores a pushed PNG: in one request when it is small enough,
+// otherwise through a resumable upload and its finalize job.
+func (c *cli) uploadBlob(ctx context.Context, api *client.Client, projectID string, img *pngFile) error {
+	if len(img.data) <= maxBlobSize {
+		if err := api.UploadBlob(ctx, projectID, bytes.NewReader(img.data)); err != nil {
+			return err
+		}
+		return api.ConfirmUpload(ctx, projectID)
+	}
+
+	session, err := api.InitiateUpload(ctx, projectID, int64(len(img.data)), img.contentHash)
+	if err != nil {
+		return err
+	}
+	for session.Offset < session.Size {
+		end := min(session.Offset+int64(uploadChunkSize), session.Size)
+		if session, err = api.WriteUploadChunk(ctx, session, session.Offset, img.data[session.Offset:end]); err != nil {
+			return err
+		}
+	}
+	if session, err = api.FinalizeUpload(ctx, projectID, session.UploadID); err != nil {
+		return err
+	}
+	for {
+		job, err := api.GetJob(ctx, session.JobID)
+		if err != nil {
+			return err
+		}
+		switch job.Status {
+		case client.JobSucceeded:
+			return nil
+		case client.JobFailed:
+			return fmt.Errorf("finalize job %s failed: %s", job.ID, job.LastError)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(jobPollInterval):
+		}
+	}
+}
+
+// pullResult is the output of projects pull.
+type pullResult struct {
+	ProjectID string `json:"projectId"`
+	File      string `json:"file"`
+	Size      int64  `json:"size"`
+}
+
+// projectsPull downloads a project's PNG to -o, checking it against the
+// project's content hash.
+func (c *cli) projectsPull(ctx context.Context, args []string) error {
+	flags := c.flags("projects pull")
+	output := flags.String("o", "", "output file, or - for stdout (default <id>.png)")
+	ids, err := c.parse(flags, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	id := ids[0]
+	if *output == "" {
+		*output = id + ".png"
+	}
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	if *output == "-" {
+		_, err := c.download(ctx, api, id, c.stdout)
+		return err
+	}
+	n, err := c.downloadFile(ctx, api, id, *output)
+	if err != nil {
+		return err
+	}
+	out := pullResult{ProjectID: id, File: *output, Size: n}
+	return c.print(out, []string{"ID", "FILE", "BYTES"}, [][]string{{id, out.File, strconv.FormatInt(n, 10)}})
+}
+
+// downloadFile downloads a project's PNG next to path and renames it into
+// place once complete, so an interrupted pull leaves no partial file.
+func (c *cli) downloadFile(ctx context.Context, api *client.Client, id, path string) (int64, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".paintbar-*.png")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+	n, err := c.download(ctx, api, id, tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+	return n, os.Rename(tmp.Name(), path)
+}
+
+// download streams a project's PNG to w. The blob's strong ETag is its
+// quoted content hash, which the downloaded bytes must match.
+func (c *cli) download(ctx context.Context, api *client.Client, id string, w io.Writer) (int64, error) {
+	blob, err := api.DownloadBlob(ctx, id, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer blob.Body.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(w, h), blob.Body)
+	if err != nil {
+		return n, fmt.Errorf("download %s: %w", id, err)
+	}
+	if strings.HasPrefix(blob.ETag, `"`) {
+		if want := strings.Trim(blob.ETag, `"`); want != hex.EncodeToString(h.Sum(nil)) {
+			return n, fmt.Errorf("download %s: content does not match hash %s", id, want)
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,110 @@
+// Command recount recomputes the project, gallery, NFT and follow counters
+// on user documents from count aggregations and repairs the ones that have
+// drifted, e.g. after documents were written around the API. It reads the
+// same environment and CONFIG_FILE as the server:
+//
+//	go run ./cmd/recount [-uid UID] [-dry-run]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/pandasWhoCode/paintbar/internal/config"
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"github.com/pandasWhoCode/paintbar/internal/repository"
+	"github.com/pandasWhoCode/paintbar/internal/service"
+)
+
+// Coverage: thin command wrapper — the recount walk is covered by the
+// StatsService tests.
+func main() {
+	uid := flag.String("uid", "", "recount only this user")
+	dryRun := flag.Bool("dry-run", false, "report drift without repairing it")
+	flag.Parse()
+
+	// Keep the Firebase client's logging off stdout, which carries the report
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "recount: load config:", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	fbClients, err := repository.NewFirebaseClients(ctx,
+		cfg.FirebaseProjectID,
+		cfg.FirebaseServiceAccountPath,
+		cfg.FirebaseStorageBucket,
+		cfg.FirestoreEmulatorHost,
+		cfg.FirebaseAuthEmulatorHost,
+		cfg.FirebaseStorageEmulatorHost,
+	)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "recount:", err)
+		os.Exit(1)
+	}
+	defer fbClients.Close()
+
+	stats := service.NewStatsService(
+		repository.NewUserRepository(fbClients.Firestore),
+		repository.NewStatsRepository(fbClients.Firestore),
+	)
+
+	drifted := 0
+	report := func(r *model.CounterRepair) {
+		if !r.Drifted() {
+			return
+		}
+		drifted++
+		fmt.Printf("%s: %s\n", r.UID, describeDrift(r))
+	}
+
+	checked := 1
+	if *uid != "" {
+		var repair *model.CounterRepair
+		if repair, err = stats.RepairCounters(ctx, *uid, *dryRun); err == nil {
+			report(repair)
+		}
+	} else {
+		checked, err = stats.RepairAllCounters(ctx, *dryRun, report)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "recount:", err)
+		os.Exit(1)
+	}
+
+	verb := "repaired"
+	if *dryRun {
+		verb = "would repair"
+	}
+	fmt.Printf("recount: %d users checked, %s %d\n", checked, verb, drifted)
+}
+
+// describeDrift lists the counters that differ, as stored -> actual.
+func describeDrift(r *model.CounterRepair) string {
+	s, a := r.Stored, r.Actual
+	var out string
+	for _, c := range []struct {
+		name           string
+		stored, actual int64
+	}{
+		{"projects", s.Projects, a.Projects},
+		{"galleryItems", s.GalleryItems, a.GalleryItems},
+		{"nfts", s.NFTs, a.NFTs},
+		{"followers", s.Followers, a.Followers},
+		{"following", s.Following, a.Following},
+	} {
+		if c.stored != c.actual {
+			if out != "" {
+				out += ", "
+			}
+			out += fmt.Sprintf("%s %d -> %d", c.name, c.stored, c.actual)
+		}
+	}
+	return out
+}
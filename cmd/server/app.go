@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/pandasWhoCode/paintbar/api"
+	"github.com/pandasWhoCode/paintbar/internal/config"
+	"github.com/pandasWhoCode/paintbar/internal/graph"
+	"github.com/pandasWhoCode/paintbar/internal/handler"
+	"github.com/pandasWhoCode/paintbar/internal/health"
+	"github.com/pandasWhoCode/paintbar/internal/jobs"
+	"github.com/pandasWhoCode/paintbar/internal/metrics"
+	mw "github.com/pandasWhoCode/paintbar/internal/middleware"
+	"github.com/pandasWhoCode/paintbar/internal/openapi"
+	"github.com/pandasWhoCode/paintbar/internal/repository"
+	"github.com/pandasWhoCode/paintbar/internal/service"
+	"github.com/pandasWhoCode/paintbar/web"
+)
+
+// identityProvider verifies ID tokens and session cookies and manages the
+// accounts behind them. Implemented by service.AuthService.
+type identityProvider interface {
+	mw.TokenVerifier
+	handler.SessionManager
+	service.AccountManager
+}
+
+// backends are the stores and identity provider the server runs on. main
+// wires Firestore and Firebase Auth; the contract tests use in-memory ones.
+type backends struct {
+	users          repository.UserRepository
+	projects       repository.ProjectRepository
+	gallery        repository.GalleryRepository
+	nfts           repository.NFTRepository
+	follows        repository.FollowRepository
+	comments       repository.CommentRepository
+	reactions      repository.ReactionRepository
+	reports        repository.ReportRepository
+	stats          repository.StatsRepository
+	apiTokens      repository.APITokenRepository
+	webhooks       repository.WebhookRepository
+	uploadSessions repository.UploadSessionRepository
+	audit          repository.AuditLogger
+	jobs           jobs.Store
+	storage        service.StorageClient
+	identity       identityProvider
+
+	// healthChecks are the dependencies /readyz checks.
+	healthChecks []health.Check
+}
+
+// app is the wired-up server: the router, the metrics it records, the
+// readiness checker and the background workers the caller starts and drains
+// around it.
+type app struct {
+	router    http.Handler
+	metrics   *metrics.Metrics
+	health    *health.Checker
+	jobRunner *jobs.Runner
+	webhooks  *service.WebhookService
+}
+
+// handlers are the HTTP handlers the router dispatches to.
+type handlers struct {
+	profile    *handler.ProfileHandler
+	project    *handler.ProjectHandler
+	gallery    *handler.GalleryHandler
+	nft        *handler.NFTHandler
+	follow     *handler.FollowHandler
+	comment    *handler.CommentHandler
+	reaction   *handler.ReactionHandler
+	moderation *handler.ModerationHandler
+	admin      *handler.AdminHandler
+	token      *handler.TokenHandler
+	webhook    *handler.WebhookHandler
+	job        *handler.JobHandler
+	upload     *handler.UploadHandler
+	docs       *handler.DocsHandler
+	page       *handler.PageHandler
+	session    *handler.SessionHandler
+	graphql    *handler.GraphQLHandler
+	stats      *handler.StatsHandler
+	health     *handler.HealthHandler
+}
+
+// newApp builds the services, handlers and router on top of b.
+func newApp(cfg *config.Config, b *backends, logger *slog.Logger) (*app, error) {
+	m := metrics.New()
+	b = b.instrument(m)
+
+	// Handlers are registered by the features that enqueue jobs, before
+	// the runner is started
+	jobRunner := jobs.NewRunner(b.jobs, jobs.Config{Workers: cfg.JobWorkers})
+
+	// Initialize services
+	userService := service.NewUserService(b.users, b.audit)
+	webhookService := service.NewWebhookService(b.webhooks, b.audit, cfg.IsLocal())
+	webhookService.SetMaxPerUser(cfg.Quotas.WebhooksPerUser)
+	projectService := service.NewProjectService(b.projects, b.storage, b.audit, webhookService)
+	galleryService := service.NewGalleryService(b.gallery, b.audit, webhookService)
+	nftService := service.NewNFTService(b.nfts, b.audit, webhookService)
+	followService := service.NewFollowService(b.users, b.follows, b.gallery)
+	commentService := service.NewCommentService(b.gallery, b.comments, b.users)
+	reactionService := service.NewReactionService(b.gallery, b.reactions)
+	moderationService := service.NewModerationService(b.reports, b.users, b.gallery, b.comments, b.nfts, b.audit)
+	adminService := service.NewAdminService(b.identity, b.users, b.stats, b.audit)
+	apiTokenService := service.NewAPITokenService(b.apiTokens, b.audit)
+	apiTokenService.SetMaxPerUser(cfg.Quotas.APITokensPerUser)
+	jobService := service.NewJobService(jobRunner)
+	uploadService := service.NewUploadService(b.projects, b.uploadSessions, b.storage, jobRunner)
+	statsService := service.NewStatsService(b.users, b.stats)
+	// List cursors are signed with CURSOR_SECRET so they stay valid across
+	// instances and restarts; locally an unset secret uses a per-process key.
+	cursorCodec := service.NewCursorCodec([]byte(cfg.CursorSecret))
+	// CSRF tokens are keyed from the same secret, so a page rendered by one
+	// instance posts to any other.
+	csrfKey := mw.NewCSRFKey([]byte(cfg.CursorSecret))
+	healthChecker := health.NewChecker(b.healthChecks, health.Config{})
+
+	graphQL, err := graph.New(graph.Services{
+		Users:    userService,
+		Follows:  followService,
+		Projects: projectService,
+		Gallery:  galleryService,
+		NFTs:     nftService,
+		Cursors:  cursorCodec,
+	}, graph.Limits{
+		MaxDepth:      cfg.Limits.GraphQLMaxDepth,
+		MaxComplexity: cfg.Limits.GraphQLMaxComplexity,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize template renderer
+	renderer, err := handler.NewTemplateRenderer(web.TemplatesFS)
+	if err != nil {
+		return nil, fmt.Errorf("parse templates: %w", err)
+	}
+
+	// The request/response validator runs outside production, so drift
+	// between the handlers and the spec shows up in local and preview
+	var spec *openapi.Spec
+	if !cfg.IsProduction() {
+		if spec, err = openapi.Load(api.OpenAPISpec); err != nil {
+			return nil, fmt.Errorf("load openapi spec: %w", err)
+		}
+	}
+
+	h := &handlers{
+		profile:    handler.NewProfileHandler(userService),
+		project:    handler.NewProjectHandler(projectService, cursorCodec),
+		gallery:    handler.NewGalleryHandler(galleryService, cursorCodec),
+		nft:        handler.NewNFTHandler(nftService, cursorCodec),
+		follow:     handler.NewFollowHandler(followService, cursorCodec),
+		comment:    handler.NewCommentHandler(commentService),
+		reaction:   handler.NewReactionHandler(reactionService),
+		moderation: handler.NewModerationHandler(moderationService),
+		admin:      handler.NewAdminHandler(adminService),
+		token:      handler.NewTokenHandler(apiTokenService),
+		webhook:    handler.NewWebhookHandler(webhookService),
+		job:        handler.NewJobHandler(jobService),
+		upload:     handler.NewUploadHandler(uploadService),
+		docs:       handler.NewDocsHandler(api.OpenAPISpec),
+		page:       handler.NewPageHandler(renderer, cfg.Env, userService),
+		session:    handler.NewSessionHandler(b.identity, handler.DefaultSessionTTL, !cfg.IsLocal()),
+		graphql:    handler.NewGraphQLHandler(graphQL),
+		stats:      handler.NewStatsHandler(statsService),
+		health:     handler.NewHealthHandler(healthChecker),
+	}
+
+	return &app{
+		router: newRouter(cfg, logger, h, &routerServices{
+			auth:       b.identity,
+			apiTokens:  apiTokenService,
+			moderation: moderationService,
+			spec:       spec,
+			metrics:    m,
+			csrfKey:    csrfKey,
+		}),
+		metrics:   m,
+		health:    healthChecker,
+		jobRunner: jobRunner,
+		webhooks:  webhookService,
+	}, nil
+}
@@ -0,0 +1,642 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pandasWhoCode/paintbar/api"
+	"github.com/pandasWhoCode/paintbar/internal/config"
+	"github.com/pandasWhoCode/paintbar/internal/health"
+	"github.com/pandasWhoCode/paintbar/internal/jobs"
+	"github.com/pandasWhoCode/paintbar/internal/metrics"
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"github.com/pandasWhoCode/paintbar/internal/openapi"
+	"github.com/pandasWhoCode/paintbar/internal/service"
+	"github.com/pandasWhoCode/paintbar/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// contract drives the router with in-memory backends and checks every
+// response against the operation api/openapi.yaml documents for it.
+type contract struct {
+	t       *testing.T
+	router  http.Handler
+	metrics *metrics.Metrics
+	health  *health.Checker
+	spec    *openapi.Spec
+	seen    map[string]bool // operations exercised, as "METHOD /path"
+	calls   int
+
+	alice, bob, admin string // ID tokens
+}
+
+// newContract builds the router with the local defaults, adjusted by
+// configure.
+func newContract(t *testing.T, configure ...func(*config.Config)) *contract {
+	t.Helper()
+
+	spec, err := openapi.Load(api.OpenAPISpec)
+	require.NoError(t, err)
+
+	identity := newMemIdentity()
+	users := newMemUserRepo()
+	gallery := newMemGalleryRepo(users)
+	c := &contract{
+		t:     t,
+		spec:  spec,
+		seen:  make(map[string]bool),
+		alice: identity.add(&model.Account{UID: "alice", Email: "alice@example.com", EmailVerified: true}),
+		bob:   identity.add(&model.Account{UID: "bob", Email: "bob@example.com", EmailVerified: true}),
+		admin: identity.add(&model.Account{UID: "admin", Email: "admin@example.com", Roles: []string{service.RoleAdmin}}),
+	}
+	users.Create(context.Background(), &model.User{UID: "alice", Email: "alice@example.com", Username: "alice"})
+	users.usernames["alice"] = "alice"
+	users.Create(context.Background(), &model.User{UID: "bob", Email: "bob@example.com"})
+
+	cfg := config.Defaults(config.EnvLocal)
+	cfg.CursorSecret = strings.Repeat("s", 32)
+	for _, fn := range configure {
+		fn(cfg)
+	}
+	a, err := newApp(cfg, &backends{
+		users:          users,
+		projects:       newMemProjectRepo(users),
+		gallery:        gallery,
+		nfts:           newMemNFTRepo(users),
+		follows:        newMemFollowRepo(users),
+		comments:       newMemCommentRepo(gallery),
+		reactions:      newMemReactionRepo(gallery),
+		reports:        newMemReportRepo(),
+		stats:          &memStatsRepo{},
+		apiTokens:      newMemAPITokenRepo(),
+		webhooks:       newMemWebhookRepo(),
+		uploadSessions: newMemUploadSessionRepo(),
+		audit:          newMemAuditLogger(),
+		jobs:           jobs.NewMemoryStore(),
+		storage:        newMemStorageClient(),
+		identity:       identity,
+		healthChecks: []health.Check{
+			{Name: "firestore", Critical: true, Run: func(context.Context) error { return nil }},
+		},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	c.router = a.router
+	c.metrics = a.metrics
+	c.health = a.health
+	return c
+}
+
+// apiCall is one request in the contract walk.
+type apiCall struct {
+	method, path string
+	token        string
+	contentType  string // defaults to application/json when body is set
+	body         string
+	header       map[string]string
+	want         int
+}
+
+// do sends call, checks its status and validates the response against the
+// documented operation.
+func (c *contract) do(call apiCall) *httptest.ResponseRecorder {
+	c.t.Helper()
+
+	var body io.Reader
+	if call.body != "" {
+		body = strings.NewReader(call.body)
+	}
+	req := httptest.NewRequest(call.method, call.path, body)
+	// Each request comes from its own address so the rate limiters never
+	// trip over the length of the walk
+	c.calls++
+	req.RemoteAddr = fmt.Sprintf("10.0.%d.%d:1234", c.calls/250, c.calls%250+1)
+	if call.body != "" {
+		ct := call.contentType
+		if ct == "" {
+			ct = "application/json"
+		}
+		req.Header.Set("Content-Type", ct)
+	}
+	if call.token != "" {
+		req.Header.Set("Authorization", "Bearer "+call.token)
+	}
+	for k, v := range call.header {
+		req.Header.Set(k, v)
+	}
+
+	rec := httptest.NewRecorder()
+	c.router.ServeHTTP(rec, req)
+
+	name := call.method + " " + call.path
+	require.Equal(c.t, call.want, rec.Code, "%s: %s", name, rec.Body.String())
+
+	op, _, ok := c.spec.Find(call.method, req.URL.Path)
+	require.True(c.t, ok, "%s is not documented", name)
+	c.seen[op.String()] = true
+	assert.NoError(c.t, op.ValidateResponse(rec.Code, rec.Header(), rec.Body.Bytes()), name)
+	return rec
+}
+
+// field returns a top-level string field of a JSON response.
+func (c *contract) field(rec *httptest.ResponseRecorder, name string) string {
+	c.t.Helper()
+	var v map[string]interface{}
+	require.NoError(c.t, json.Unmarshal(rec.Body.Bytes(), &v))
+	s, _ := v[name].(string)
+	require.NotEmpty(c.t, s, "response has no %q: %s", name, rec.Body.String())
+	return s
+}
+
+// testPNG returns a small PNG and its data URL.
+func testPNG(t *testing.T) ([]byte, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2))))
+	return buf.Bytes(), "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestContract_EveryOperation(t *testing.T) {
+	c := newContract(t)
+	blob, dataURL := testPNG(t)
+	sum := sha256.Sum256(blob)
+	hash := hex.EncodeToString(sum[:])
+
+	hooks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer hooks.Close()
+
+	// Health and session cookies
+	c.do(apiCall{method: "GET", path: "/livez", want: 200})
+	c.do(apiCall{method: "GET", path: "/readyz", want: 200})
+	c.do(apiCall{method: "GET", path: "/health", want: 200})
+	sameSite := map[string]string{"Origin": "http://example.com"}
+	c.do(apiCall{method: "POST", path: "/auth/session", body: `{"idToken":"` + c.alice + `"}`, header: sameSite, want: 200})
+	c.do(apiCall{method: "POST", path: "/auth/session", body: `{"idToken":"forged"}`, header: sameSite, want: 401})
+	c.do(apiCall{method: "POST", path: "/auth/session", body: `{"idToken":"` + c.alice + `"}`, header: map[string]string{"Origin": "https://evil.example"}, want: 403})
+	c.do(apiCall{method: "POST", path: "/auth/logout", header: sameSite, want: 200})
+	c.do(apiCall{method: "GET", path: "/api/v1/ping", want: 401})
+	c.do(apiCall{method: "GET", path: "/api/v1/ping", token: c.alice, want: 200})
+
+	// Profiles
+	c.do(apiCall{method: "GET", path: "/api/v1/profile", token: c.alice, want: 200})
+	c.do(apiCall{method: "PUT", path: "/api/v1/profile", token: c.alice, body: `{"displayName":"Alice","website":"https://alice.example.com"}`, want: 200})
+	c.do(apiCall{method: "PUT", path: "/api/v1/profile", token: c.alice, body: `{"displayName":7}`, want: 400})
+	c.do(apiCall{method: "POST", path: "/api/v1/claim-username", token: c.bob, body: `{"username":"bobby"}`, want: 200})
+	c.do(apiCall{method: "POST", path: "/api/v1/claim-username", token: c.bob, body: `{"username":"alice"}`, want: 409})
+	c.do(apiCall{method: "GET", path: "/api/v1/account/activity", token: c.alice, want: 200})
+
+	// Projects
+	create := `{"title":"Sky","contentHash":"` + hash + `","thumbnailData":"` + dataURL + `","width":2,"height":2,"tags":["sky"]}`
+	projectID := c.field(c.do(apiCall{method: "POST", path: "/api/v1/projects", token: c.alice, body: create, want: 201}), "projectId")
+	c.do(apiCall{method: "GET", path: "/api/v1/projects?sort=title&order=asc", token: c.alice, want: 200})
+	c.do(apiCall{method: "GET", path: "/api/v1/projects?limit=0", token: c.alice, want: 400})
+	c.do(apiCall{method: "GET", path: "/api/v1/projects/count", token: c.alice, want: 200})
+	c.do(apiCall{method: "GET", path: "/api/v1/projects/by-title?title=Sky", token: c.alice, want: 200})
+	c.do(apiCall{method: "GET", path: "/api/v1/projects/by-title?title=Sea", token: c.alice, want: 404})
+	c.do(apiCall{method: "GET", path: "/api/v1/projects/" + projectID, token: c.alice, want: 200})
+	c.do(apiCall{method: "GET", path: "/api/v1/projects/" + projectID, token: c.bob, want: 403})
+	c.do(apiCall{method: "PUT", path: "/api/v1/projects/" + projectID, token: c.alice, body: `{"isPublic":true}`, want: 200})
+	c.do(apiCall{method: "POST", path: "/api/v1/projects/" + projectID + "/upload-blob", token: c.alice, contentType: "image/png", body: string(blob), want: 200})
+	c.do(apiCall{method: "POST", path: "/api/v1/projects/" + projectID + "/confirm-upload", token: c.alice, want: 200})
+	c.do(apiCall{method: "GET", path: "/api/v1/projects/" + projectID + "/blob", token: c.alice, want: 200})
+	c.do(apiCall{method: "GET", path: "/api/v1/projects/" + projectID + "/blob", token: c.alice, header: map[string]string{"Range": "bytes=0-3"}, want: 206})
+	c.do(apiCall{method: "GET", path: "/api/v1/projects/" + projectID + "/thumbnail", token: c.alice, want: 200})
+
+	// Resumable uploads and the job that finalizes them
+	upload := c.do(apiCall{method: "POST", path: "/api/v1/projects/" + projectID + "/uploads", token: c.alice, body: fmt.Sprintf(`{"size":%d,"contentHash":"%s"}`, len(blob), hash), want: 201})
+	uploadPath := "/api/v1/projects/" + projectID + "/uploads/" + c.field(upload, "uploadId")
+	c.do(apiCall{method: "GET", path: uploadPath, token: c.alice, want: 200})
+	c.do(apiCall{method: "PUT", path: uploadPath, token: c.alice, contentType: "application/octet-stream", body: string(blob), header: map[string]string{"Content-Range": fmt.Sprintf("bytes 0-%d/%d", len(blob)-1, len(blob))}, want: 200})
+	finalize := c.do(apiCall{method: "POST", path: uploadPath + "/finalize", token: c.alice, want: 202})
+	c.do(apiCall{method: "GET", path: "/api/v1/jobs/" + c.field(finalize, "jobId"), token: c.alice, want: 200})
+
+	// Gallery, comments and reactions
+	share := `{"name":"Sky","projectId":"` + projectID + `","thumbnailData":"` + dataURL + `","tags":["sky"]}`
+	itemID := c.field(c.do(apiCall{method: "POST", path: "/api/v1/gallery", token: c.alice, body: share, want: 201}), "id")
+	otherItemID := c.field(c.do(apiCall{method: "POST", path: "/api/v1/gallery", token: c.alice, body: `{"name":"Sea"}`, want: 201}), "id")
+	c.do(apiCall{method: "GET", path: "/api/v1/gallery", token: c.alice, want: 200})
+	c.do(apiCall{method: "GET", path: "/api/v1/gallery/count", token: c.alice, want: 200})
+	c.do(apiCall{method: "GET", path: "/api/v1/gallery/" + itemID, token: c.alice, want: 200})
+	c.do(apiCall{method: "GET", path: "/api/v1/gallery/" + itemID + "/thumbnail", token: c.alice, want: 200})
+	commentsPath := "/api/v1/gallery/" + itemID + "/comments"
+	commentID := c.field(c.do(apiCall{method: "POST", path: commentsPath, token: c.alice, body: `{"body":"Nice"}`, want: 201}), "id")
+	otherCommentID := c.field(c.do(apiCall{method: "POST", path: commentsPath, token: c.alice, body: `{"body":"Again"}`, want: 201}), "id")
+	c.do(apiCall{method: "GET", path: commentsPath, token: c.alice, want: 200})
+	c.do(apiCall{method: "PUT", path: commentsPath + "/" + commentID, token: c.alice, body: `{"body":"Very nice"}`, want: 200})
+	c.do(apiCall{method: "POST", path: "/api/v1/gallery/" + itemID + "/reactions", token: c.alice, body: `{"reaction":"heart"}`, want: 200})
+	c.do(apiCall{method: "POST", path: "/api/v1/gallery/" + itemID + "/reactions", token: c.alice, body: `{"reaction":"meh"}`, want: 400})
+	c.do(apiCall{method: "GET", path: "/api/v1/gallery/" + itemID + "/reactions", token: c.alice, want: 200})
+	c.do(apiCall{method: "DELETE", path: "/api/v1/gallery/" + itemID + "/reactions/heart", token: c.alice, want: 200})
+	c.do(apiCall{method: "DELETE", path: commentsPath + "/" + commentID, token: c.alice, want: 200})
+
+	// NFTs
+	nftID := c.field(c.do(apiCall{method: "POST", path: "/api/v1/nfts", token: c.alice, body: `{"name":"Sky","imageData":"` + dataURL + `","price":1.5}`, want: 201}), "id")
+	otherNFTID := c.field(c.do(apiCall{method: "POST", path: "/api/v1/nfts", token: c.alice, body: `{"name":"Sea"}`, want: 201}), "id")
+	c.do(apiCall{method: "GET", path: "/api/v1/nfts", token: c.alice, want: 200})
+	c.do(apiCall{method: "GET", path: "/api/v1/nfts/count", token: c.alice, want: 200})
+	c.do(apiCall{method: "GET", path: "/api/v1/nfts/" + nftID, token: c.alice, want: 200})
+	c.do(apiCall{method: "GET", path: "/api/v1/nfts/" + nftID + "/thumbnail", token: c.alice, want: 200})
+
+	// Counters follow the creates above
+	stats := c.do(apiCall{method: "GET", path: "/api/v1/stats", token: c.alice, want: 200})
+	assert.JSONEq(t, `{"projects":1,"galleryItems":2,"nfts":2,"followers":0,"following":0}`, stats.Body.String())
+
+	// Follow graph and feed
+	c.do(apiCall{method: "POST", path: "/api/v1/users/alice/follow", token: c.bob, want: 200})
+	c.do(apiCall{method: "GET", path: "/api/v1/users/alice", token: c.bob, want: 200})
+	c.do(apiCall{method: "GET", path: "/api/v1/users/nobody", token: c.bob, want: 404})
+	c.do(apiCall{method: "GET", path: "/api/v1/users/alice/followers", token: c.bob, want: 200})
+	c.do(apiCall{method: "GET", path: "/api/v1/users/bobby/following", token: c.bob, want: 200})
+	c.do(apiCall{method: "GET", path: "/api/v1/feed/following", token: c.bob, want: 200})
+	c.do(apiCall{method: "DELETE", path: "/api/v1/users/alice/follow", token: c.bob, want: 200})
+
+	// GraphQL
+	profilePage := `{"query":"{ me { username email projectCount galleryCount nftCount projects(first: 5) { items { title owner { username } } total } gallery { items { name project { title } } } nfts { total } } }"}`
+	c.do(apiCall{method: "POST", path: "/api/v1/graphql", token: c.alice, body: profilePage, want: 200})
+	c.do(apiCall{method: "POST", path: "/api/v1/graphql", token: c.bob, body: `{"query":"{ user(username: \"alice\") { projects { total } gallery { total } } }"}`, want: 200})
+	c.do(apiCall{method: "POST", path: "/api/v1/graphql", token: c.alice, body: `{"query":"{ me { nope } }"}`, want: 400})
+
+	// Personal access tokens
+	tokenID := c.field(c.do(apiCall{method: "POST", path: "/api/v1/tokens", token: c.alice, body: `{"name":"ci","scopes":["projects:read"],"expiresInDays":30}`, want: 201}), "id")
+	c.do(apiCall{method: "GET", path: "/api/v1/tokens", token: c.alice, want: 200})
+	c.do(apiCall{method: "DELETE", path: "/api/v1/tokens/" + tokenID, token: c.alice, want: 200})
+
+	// Webhooks
+	webhookID := c.field(c.do(apiCall{method: "POST", path: "/api/v1/webhooks", token: c.alice, body: `{"url":"` + hooks.URL + `","events":["project.created"]}`, want: 201}), "id")
+	c.do(apiCall{method: "GET", path: "/api/v1/webhooks", token: c.alice, want: 200})
+	c.do(apiCall{method: "POST", path: "/api/v1/webhooks/" + webhookID + "/ping", token: c.alice, want: 200})
+	c.do(apiCall{method: "GET", path: "/api/v1/webhooks/" + webhookID + "/deliveries", token: c.alice, want: 200})
+	c.do(apiCall{method: "DELETE", path: "/api/v1/webhooks/" + webhookID, token: c.alice, want: 200})
+
+	// Reports and moderation
+	reportID := c.field(c.do(apiCall{method: "POST", path: "/api/v1/reports", token: c.bob, body: `{"targetType":"gallery","targetId":"` + itemID + `","reason":"spam"}`, want: 201}), "id")
+	c.do(apiCall{method: "GET", path: "/api/v1/admin/reports", token: c.alice, want: 403})
+	c.do(apiCall{method: "GET", path: "/api/v1/admin/reports?status=open", token: c.admin, want: 200})
+	c.do(apiCall{method: "PUT", path: "/api/v1/admin/reports/" + reportID, token: c.admin, body: `{"status":"dismissed","note":"fine"}`, want: 200})
+	c.do(apiCall{method: "PUT", path: "/api/v1/admin/gallery/" + itemID + "/hidden", token: c.admin, body: `{"hidden":true}`, want: 200})
+	c.do(apiCall{method: "PUT", path: "/api/v1/admin/gallery/" + itemID + "/comments/" + otherCommentID + "/hidden", token: c.admin, body: `{"hidden":true}`, want: 200})
+	c.do(apiCall{method: "DELETE", path: "/api/v1/admin/gallery/" + itemID + "/comments/" + otherCommentID, token: c.admin, want: 200})
+	c.do(apiCall{method: "PUT", path: "/api/v1/admin/nfts/" + otherNFTID + "/hidden", token: c.admin, body: `{"hidden":true}`, want: 200})
+	c.do(apiCall{method: "DELETE", path: "/api/v1/admin/nfts/" + otherNFTID, token: c.admin, want: 200})
+	c.do(apiCall{method: "DELETE", path: "/api/v1/admin/gallery/" + otherItemID, token: c.admin, want: 200})
+	c.do(apiCall{method: "PUT", path: "/api/v1/admin/users/bob/suspended", token: c.admin, body: `{"suspended":false}`, want: 200})
+
+	// Account administration
+	c.do(apiCall{method: "GET", path: "/api/v1/admin/users?uid=alice", token: c.admin, want: 200})
+	c.do(apiCall{method: "GET", path: "/api/v1/admin/users?email=nobody@example.com", token: c.admin, want: 404})
+	c.do(apiCall{method: "PUT", path: "/api/v1/admin/users/bob/disabled", token: c.admin, body: `{"disabled":false}`, want: 200})
+	c.do(apiCall{method: "DELETE", path: "/api/v1/admin/usernames/bobby", token: c.admin, want: 200})
+	c.do(apiCall{method: "GET", path: "/api/v1/admin/stats", token: c.admin, want: 200})
+	c.do(apiCall{method: "GET", path: "/api/v1/admin/audit", token: c.admin, want: 200})
+
+	// Deletes
+	c.do(apiCall{method: "DELETE", path: "/api/v1/gallery/" + itemID, token: c.bob, want: 403})
+	c.do(apiCall{method: "DELETE", path: "/api/v1/gallery/" + itemID, token: c.alice, want: 200})
+	c.do(apiCall{method: "DELETE", path: "/api/v1/nfts/" + nftID, token: c.alice, want: 200})
+	c.do(apiCall{method: "DELETE", path: "/api/v1/projects/" + projectID, token: c.alice, want: 200})
+	c.do(apiCall{method: "GET", path: "/api/v1/projects/" + projectID, token: c.alice, want: 404})
+
+	var missed []string
+	for _, op := range c.spec.Operations() {
+		if !c.seen[op.String()] {
+			missed = append(missed, op.String())
+		}
+	}
+	assert.Empty(t, missed, "documented operations the contract walk does not exercise")
+}
+
+// TestContract_RoutesAreDocumented checks the other direction: every route
+// the router serves under /api/v1 appears in the spec.
+func TestContract_RoutesAreDocumented(t *testing.T) {
+	c := newContract(t)
+	routes, ok := c.router.(chi.Routes)
+	require.True(t, ok)
+
+	var undocumented []string
+	err := chi.Walk(routes, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if !strings.HasPrefix(route, apiVersionPrefix+"/") {
+			return nil
+		}
+		route = strings.TrimSuffix(route, "/")
+		if _, _, ok := c.spec.Find(method, route); !ok {
+			undocumented = append(undocumented, method+" "+route)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	sort.Strings(undocumented)
+	assert.Empty(t, undocumented)
+}
+
+func TestContract_DeprecatedAlias(t *testing.T) {
+	c := newContract(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/projects/count", nil)
+	req.Header.Set("Authorization", "Bearer "+c.alice)
+	rec := httptest.NewRecorder()
+	c.router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, fmt.Sprintf("@%d", apiDeprecatedSince.Unix()), rec.Header().Get("Deprecation"))
+	assert.Equal(t, apiSunset.Format(http.TimeFormat), rec.Header().Get("Sunset"))
+	assert.Contains(t, rec.Header().Values("Link"), `</api/v1/projects/count>; rel="successor-version"`)
+
+	// The current version carries no deprecation headers
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/projects/count", nil)
+	req.Header.Set("Authorization", "Bearer "+c.alice)
+	rec = httptest.NewRecorder()
+	c.router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("Deprecation"))
+	assert.Empty(t, rec.Header().Get("Sunset"))
+}
+
+func TestContract_Metrics(t *testing.T) {
+	c := newContract(t)
+	blob, dataURL := testPNG(t)
+	sum := sha256.Sum256(blob)
+
+	create := `{"title":"Sky","contentHash":"` + hex.EncodeToString(sum[:]) + `","thumbnailData":"` + dataURL + `","width":2,"height":2}`
+	projectID := c.field(c.do(apiCall{method: "POST", path: "/api/v1/projects", token: c.alice, body: create, want: 201}), "projectId")
+	c.do(apiCall{method: "GET", path: "/api/v1/projects/" + projectID, token: c.alice, want: 200})
+	c.do(apiCall{method: "GET", path: "/api/v1/projects/missing", token: c.alice, want: 404})
+	c.do(apiCall{method: "POST", path: "/api/v1/projects/" + projectID + "/upload-blob", token: c.alice, contentType: "image/png", body: string(blob), want: 200})
+
+	rec := httptest.NewRecorder()
+	c.metrics.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	out := rec.Body.String()
+
+	// Requests are labelled by route pattern, never by raw path
+	assert.Contains(t, out, `paintbar_http_requests_total{code="200",method="GET",route="/api/v1/projects/{id}"} 1`)
+	assert.Contains(t, out, `paintbar_http_requests_total{code="404",method="GET",route="/api/v1/projects/{id}"} 1`)
+	assert.NotContains(t, out, projectID)
+
+	// Repository calls are timed per method; a missing document is not an error
+	assert.Contains(t, out, `paintbar_repository_call_duration_seconds_count{method="GetByID",repository="projects"}`)
+	assert.Contains(t, out, `paintbar_repository_call_duration_seconds_count{method="WriteObject",repository="storage"} 1`)
+	assert.NotContains(t, out, `paintbar_repository_call_errors_total{method="GetByID"`)
+
+	assert.Contains(t, out, fmt.Sprintf(`paintbar_upload_bytes_total{upload="blob"} %d`, len(blob)))
+	assert.Contains(t, out, `paintbar_rate_limit_visitors{limiter="global"}`)
+}
+
+func TestContract_ReadinessDrains(t *testing.T) {
+	c := newContract(t)
+	assert.Equal(t, "ok", c.field(c.do(apiCall{method: "GET", path: "/readyz", want: 200}), "status"))
+
+	c.health.Drain()
+	assert.Equal(t, "draining", c.field(c.do(apiCall{method: "GET", path: "/readyz", want: 503}), "status"))
+	c.do(apiCall{method: "GET", path: "/health", want: 503})
+	c.do(apiCall{method: "GET", path: "/livez", want: 200})
+}
+
+func TestContract_Config(t *testing.T) {
+	c := newContract(t, func(cfg *config.Config) {
+		cfg.Features.GraphQL = false
+		cfg.Features.APIDocs = false
+		cfg.CORS.AllowedOrigins = []string{"https://paintbar.example"}
+	})
+
+	// Switched-off endpoints are not routed
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/graphql", strings.NewReader(`{"query":"{ me { uid } }"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.alice)
+	rec := httptest.NewRecorder()
+	c.router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	c.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/docs", nil))
+	assert.NotEqual(t, http.StatusOK, rec.Code)
+
+	// Configured origins replace the built-in ones
+	for origin, allowed := range map[string]bool{"https://paintbar.example": true, "http://localhost:5173": false} {
+		req := httptest.NewRequest(http.MethodOptions, "/api/v1/projects", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		rec := httptest.NewRecorder()
+		c.router.ServeHTTP(rec, req)
+		if allowed {
+			assert.Equal(t, origin, rec.Header().Get("Access-Control-Allow-Origin"))
+		} else {
+			assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"), origin)
+		}
+	}
+}
+
+func TestContract_Tracing(t *testing.T) {
+	tp, prop := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	spans := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer func() {
+		otel.SetTracerProvider(tp)
+		otel.SetTextMapPropagator(prop)
+	}()
+
+	c := newContract(t)
+	_, dataURL := testPNG(t)
+	create := `{"title":"Sky","contentHash":"` + strings.Repeat("a", 64) + `","thumbnailData":"` + dataURL + `","width":2,"height":2}`
+	projectID := c.field(c.do(apiCall{method: "POST", path: "/api/v1/projects", token: c.alice, body: create, want: 201}), "projectId")
+	spans.Reset()
+
+	c.do(apiCall{method: "GET", path: "/api/v1/projects/" + projectID, token: c.alice, want: 200})
+	c.do(apiCall{method: "GET", path: "/api/v1/projects/missing", token: c.alice, want: 404})
+
+	var servers []sdktrace.ReadOnlySpan
+	children := make(map[string][]sdktrace.ReadOnlySpan) // by parent span ID
+	for _, s := range spans.Ended() {
+		if s.Name() == "GET /api/v1/projects/{id}" {
+			servers = append(servers, s)
+		}
+		children[s.Parent().SpanID().String()] = append(children[s.Parent().SpanID().String()], s)
+	}
+	require.Len(t, servers, 2, "one server span per request, named by route pattern")
+
+	// Each request's project lookup is a child span of its server span, and
+	// a missing project is not a failed call
+	for _, server := range servers {
+		var names []string
+		for _, child := range children[server.SpanContext().SpanID().String()] {
+			names = append(names, child.Name())
+			if child.Name() == "projects.GetByID" {
+				assert.Equal(t, server.SpanContext().TraceID(), child.SpanContext().TraceID())
+				assert.NotEqual(t, codes.Error, child.Status().Code)
+			}
+		}
+		assert.Contains(t, names, "projects.GetByID")
+	}
+}
+
+func TestContract_RejectsRequestsOutsideSpec(t *testing.T) {
+	c := newContract(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tokens", strings.NewReader(`{"name":"ci","scopes":["everything"]}`))
+	req.Header.Set("Authorization", "Bearer "+c.alice)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	c.router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "scopes[0]")
+
+	// Anonymous callers are turned away before the spec is consulted
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/tokens", strings.NewReader(`{"name":"ci","scopes":["everything"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	c.router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "scopes[0]")
+}
+
+// TestContract_GoClient drives the router through pkg/client, checking the
+// client's requests pass the spec validation middleware and its responses
+// decode and match the spec.
+func TestContract_GoClient(t *testing.T) {
+	c := newContract(t)
+	blob, dataURL := testPNG(t)
+	sum := sha256.Sum256(blob)
+	hash := hex.EncodeToString(sum[:])
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.calls++
+		r.RemoteAddr = fmt.Sprintf("10.1.%d.%d:1234", c.calls/250, c.calls%250+1)
+		rec := httptest.NewRecorder()
+		c.router.ServeHTTP(rec, r)
+
+		op, _, ok := c.spec.Find(r.Method, r.URL.Path)
+		if assert.True(t, ok, "%s %s is not documented", r.Method, r.URL.Path) {
+			assert.NoError(t, op.ValidateResponse(rec.Code, rec.Header(), rec.Body.Bytes()), op.String())
+		}
+		for k, v := range rec.Header() {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(rec.Code)
+		w.Write(rec.Body.Bytes())
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	alice := client.New(srv.URL, client.WithToken(c.alice))
+	bob := client.New(srv.URL, client.WithToken(c.bob))
+
+	// Profile
+	name := "Alice"
+	require.NoError(t, alice.UpdateProfile(ctx, &client.UserUpdate{DisplayName: &name}))
+	profile, err := alice.GetProfile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", profile.DisplayName)
+	assert.ErrorIs(t, bob.ClaimUsername(ctx, "alice"), client.ErrConflict)
+
+	// Projects, blobs and resumable uploads
+	var ids []string
+	for i, title := range []string{"Sky", "Sea", "Sun"} {
+		contentHash := hash
+		if i > 0 {
+			contentHash = fmt.Sprintf("%064x", i) // not a duplicate of the first
+		}
+		res, err := alice.CreateProject(ctx, &client.ProjectCreate{
+			Title: title, ContentHash: contentHash, ThumbnailData: dataURL, Width: 2, Height: 2,
+		})
+		require.NoError(t, err)
+		ids = append(ids, res.ProjectID)
+	}
+	require.NoError(t, alice.UploadBlob(ctx, ids[0], bytes.NewReader(blob)))
+	require.NoError(t, alice.ConfirmUpload(ctx, ids[0]))
+
+	b, err := alice.DownloadBlob(ctx, ids[0], &client.BlobOptions{Version: hash})
+	require.NoError(t, err)
+	got, _ := io.ReadAll(b.Body)
+	b.Body.Close()
+	assert.Equal(t, blob, got)
+	b, err = alice.DownloadBlob(ctx, ids[0], &client.BlobOptions{Offset: 4})
+	require.NoError(t, err)
+	got, _ = io.ReadAll(b.Body)
+	b.Body.Close()
+	assert.True(t, b.Partial)
+	assert.Equal(t, blob[4:], got)
+
+	var titles []string
+	for p, err := range alice.AllProjects(ctx, &client.ListOptions{Limit: 2, Sort: "title", Order: "asc", Fields: []string{"id", "title"}}) {
+		require.NoError(t, err)
+		titles = append(titles, p.Title)
+	}
+	assert.Equal(t, []string{"Sea", "Sky", "Sun"}, titles)
+	count, err := alice.CountProjects(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	_, err = bob.GetProject(ctx, ids[0])
+	assert.ErrorIs(t, err, client.ErrForbidden)
+
+	session, err := alice.InitiateUpload(ctx, ids[0], int64(len(blob)), hash)
+	require.NoError(t, err)
+	session, err = alice.WriteUploadChunk(ctx, session, 0, blob)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(blob)), session.Offset)
+	session, err = alice.FinalizeUpload(ctx, session.ProjectID, session.UploadID)
+	require.NoError(t, err)
+	job, err := alice.GetJob(ctx, session.JobID)
+	require.NoError(t, err)
+	assert.Equal(t, "upload.finalize", job.Type)
+
+	// Gallery, comments and reactions
+	itemID, err := alice.ShareToGallery(ctx, &client.GalleryItemCreate{Name: "Sky", ProjectID: ids[0], ThumbnailData: dataURL})
+	require.NoError(t, err)
+	thumb, err := alice.GetGalleryThumbnail(ctx, itemID, nil)
+	require.NoError(t, err)
+	thumb.Body.Close()
+	thumb, err = alice.GetGalleryThumbnail(ctx, itemID, &client.BlobOptions{IfNoneMatch: thumb.ETag})
+	require.NoError(t, err)
+	thumb.Body.Close()
+	assert.True(t, thumb.NotModified)
+	for _, body := range []string{"one", "two", "three"} {
+		_, err := alice.CreateComment(ctx, itemID, &client.CommentCreate{Body: body})
+		require.NoError(t, err)
+	}
+	var bodies []string
+	for cm, err := range alice.AllComments(ctx, itemID, &client.PageOptions{Limit: 2}) {
+		require.NoError(t, err)
+		bodies = append(bodies, cm.Body)
+	}
+	assert.Equal(t, []string{"one", "two", "three"}, bodies)
+	require.NoError(t, alice.AddReaction(ctx, itemID, client.ReactionHeart))
+	reactions, err := alice.GetReactions(ctx, itemID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{client.ReactionHeart}, reactions.Mine)
+	assert.ErrorIs(t, alice.AddReaction(ctx, itemID, "meh"), client.ErrBadRequest)
+
+	// NFTs
+	nftID, err := alice.CreateNFT(ctx, &client.NFTCreate{Name: "Sky", ImageData: dataURL, Price: 1.5})
+	require.NoError(t, err)
+	nft, err := alice.GetNFT(ctx, nftID)
+	require.NoError(t, err)
+	assert.Equal(t, 1.5, nft.Price)
+	page, err := alice.ListNFTs(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+	require.NoError(t, alice.DeleteNFT(ctx, nftID))
+	_, err = alice.GetNFT(ctx, nftID)
+	assert.ErrorIs(t, err, client.ErrNotFound)
+}
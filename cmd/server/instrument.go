@@ -0,0 +1,576 @@
+package main
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/pandasWhoCode/paintbar/internal/jobs"
+	"github.com/pandasWhoCode/paintbar/internal/metrics"
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"github.com/pandasWhoCode/paintbar/internal/repository"
+	"github.com/pandasWhoCode/paintbar/internal/service"
+	"github.com/pandasWhoCode/paintbar/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrument returns a copy of b whose repositories, job store and storage
+// record each call's latency and failures in m, labelled with the
+// repository and method, and trace each call as a child span of the request
+// or job making it. The wrappers list every method explicitly so a method
+// added to an interface fails to compile until it is instrumented.
+func (b *backends) instrument(m *metrics.Metrics) *backends {
+	out := *b
+	out.users = instrumentedUsers{b.users, m}
+	out.projects = instrumentedProjects{b.projects, m}
+	out.gallery = instrumentedGallery{b.gallery, m}
+	out.nfts = instrumentedNFTs{b.nfts, m}
+	out.follows = instrumentedFollows{b.follows, m}
+	out.comments = instrumentedComments{b.comments, m}
+	out.reactions = instrumentedReactions{b.reactions, m}
+	out.reports = instrumentedReports{b.reports, m}
+	out.stats = instrumentedStats{b.stats, m}
+	out.apiTokens = instrumentedAPITokens{b.apiTokens, m}
+	out.webhooks = instrumentedWebhooks{b.webhooks, m}
+	out.uploadSessions = instrumentedUploadSessions{b.uploadSessions, m}
+	out.audit = instrumentedAudit{b.audit, m}
+	out.jobs = instrumentedJobs{b.jobs, m}
+	out.storage = instrumentedStorage{b.storage, m}
+	return &out
+}
+
+// failed reports whether err is a failure worth counting. Lookups of
+// missing documents are part of normal operation.
+func failed(err error) bool {
+	return err != nil && !repository.IsNotFoundError(err)
+}
+
+// call is one instrumented call in progress.
+type call struct {
+	m            *metrics.Metrics
+	repo, method string
+	span         trace.Span
+	start        time.Time
+}
+
+// startCall begins a call to repo.method. The span is only started inside
+// an existing trace, so the job runner's polling does not start a trace of
+// its own every few seconds.
+func startCall(ctx context.Context, m *metrics.Metrics, repo, method string) (context.Context, *call) {
+	c := &call{m: m, repo: repo, method: method}
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		ctx, c.span = tracing.Tracer().Start(ctx, repo+"."+method, trace.WithAttributes(
+			attribute.String("paintbar.repository", repo),
+			attribute.String("paintbar.method", method),
+		))
+	}
+	c.start = time.Now()
+	return ctx, c
+}
+
+// end records the call's outcome.
+func (c *call) end(err error) {
+	c.m.ObserveCall(c.repo, c.method, time.Since(c.start), failed(err))
+	if c.span == nil {
+		return
+	}
+	if failed(err) {
+		c.span.RecordError(err)
+		c.span.SetStatus(codes.Error, err.Error())
+	}
+	c.span.End()
+}
+
+// observe runs fn as a call to repo.method.
+func observe[T any](ctx context.Context, m *metrics.Metrics, repo, method string, fn func(ctx context.Context) (T, error)) (T, error) {
+	ctx, c := startCall(ctx, m, repo, method)
+	v, err := fn(ctx)
+	c.end(err)
+	return v, err
+}
+
+// observeErr is observe for calls that only return an error.
+func observeErr(ctx context.Context, m *metrics.Metrics, repo, method string, fn func(ctx context.Context) error) error {
+	ctx, c := startCall(ctx, m, repo, method)
+	err := fn(ctx)
+	c.end(err)
+	return err
+}
+
+type instrumentedUsers struct {
+	next repository.UserRepository
+	m    *metrics.Metrics
+}
+
+func (r instrumentedUsers) GetByID(ctx context.Context, uid string) (*model.User, error) {
+	return observe(ctx, r.m, "users", "GetByID", func(ctx context.Context) (*model.User, error) { return r.next.GetByID(ctx, uid) })
+}
+
+func (r instrumentedUsers) GetByIDs(ctx context.Context, uids []string) ([]*model.User, error) {
+	return observe(ctx, r.m, "users", "GetByIDs", func(ctx context.Context) ([]*model.User, error) { return r.next.GetByIDs(ctx, uids) })
+}
+
+func (r instrumentedUsers) GetByUsername(ctx context.Context, username string) (*model.User, error) {
+	return observe(ctx, r.m, "users", "GetByUsername", func(ctx context.Context) (*model.User, error) { return r.next.GetByUsername(ctx, username) })
+}
+
+func (r instrumentedUsers) Create(ctx context.Context, user *model.User) error {
+	return observeErr(ctx, r.m, "users", "Create", func(ctx context.Context) error { return r.next.Create(ctx, user) })
+}
+
+func (r instrumentedUsers) Update(ctx context.Context, uid string, update *model.UserUpdate) error {
+	return observeErr(ctx, r.m, "users", "Update", func(ctx context.Context) error { return r.next.Update(ctx, uid, update) })
+}
+
+func (r instrumentedUsers) ClaimUsername(ctx context.Context, uid string, username string) error {
+	return observeErr(ctx, r.m, "users", "ClaimUsername", func(ctx context.Context) error { return r.next.ClaimUsername(ctx, uid, username) })
+}
+
+func (r instrumentedUsers) SetSuspended(ctx context.Context, uid string, suspended bool) error {
+	return observeErr(ctx, r.m, "users", "SetSuspended", func(ctx context.Context) error { return r.next.SetSuspended(ctx, uid, suspended) })
+}
+
+func (r instrumentedUsers) ReleaseUsername(ctx context.Context, username string) (string, error) {
+	return observe(ctx, r.m, "users", "ReleaseUsername", func(ctx context.Context) (string, error) { return r.next.ReleaseUsername(ctx, username) })
+}
+
+type instrumentedProjects struct {
+	next repository.ProjectRepository
+	m    *metrics.Metrics
+}
+
+func (r instrumentedProjects) GetByID(ctx context.Context, projectID string) (*model.Project, error) {
+	return observe(ctx, r.m, "projects", "GetByID", func(ctx context.Context) (*model.Project, error) { return r.next.GetByID(ctx, projectID) })
+}
+
+func (r instrumentedProjects) GetByIDs(ctx context.Context, projectIDs []string) ([]*model.Project, error) {
+	return observe(ctx, r.m, "projects", "GetByIDs", func(ctx context.Context) ([]*model.Project, error) { return r.next.GetByIDs(ctx, projectIDs) })
+}
+
+func (r instrumentedProjects) FindByContentHash(ctx context.Context, userID, contentHash string) (*model.Project, error) {
+	return observe(ctx, r.m, "projects", "FindByContentHash", func(ctx context.Context) (*model.Project, error) {
+		return r.next.FindByContentHash(ctx, userID, contentHash)
+	})
+}
+
+func (r instrumentedProjects) FindByTitle(ctx context.Context, userID, title string) (*model.Project, error) {
+	return observe(ctx, r.m, "projects", "FindByTitle", func(ctx context.Context) (*model.Project, error) { return r.next.FindByTitle(ctx, userID, title) })
+}
+
+func (r instrumentedProjects) List(ctx context.Context, userID string, limit int, opts *model.ListOptions, fields []string) ([]*model.Project, *model.Cursor, error) {
+	ctx, c := startCall(ctx, r.m, "projects", "List")
+	projects, next, err := r.next.List(ctx, userID, limit, opts, fields)
+	c.end(err)
+	return projects, next, err
+}
+
+func (r instrumentedProjects) CountList(ctx context.Context, userID string, opts *model.ListOptions) (int64, error) {
+	return observe(ctx, r.m, "projects", "CountList", func(ctx context.Context) (int64, error) { return r.next.CountList(ctx, userID, opts) })
+}
+
+func (r instrumentedProjects) Count(ctx context.Context, userID string) (int64, error) {
+	return observe(ctx, r.m, "projects", "Count", func(ctx context.Context) (int64, error) { return r.next.Count(ctx, userID) })
+}
+
+func (r instrumentedProjects) Create(ctx context.Context, project *model.Project) (string, error) {
+	return observe(ctx, r.m, "projects", "Create", func(ctx context.Context) (string, error) { return r.next.Create(ctx, project) })
+}
+
+func (r instrumentedProjects) Update(ctx context.Context, projectID string, update *model.ProjectUpdate) error {
+	return observeErr(ctx, r.m, "projects", "Update", func(ctx context.Context) error { return r.next.Update(ctx, projectID, update) })
+}
+
+func (r instrumentedProjects) UpdateRaw(ctx context.Context, projectID string, fields map[string]interface{}) error {
+	return observeErr(ctx, r.m, "projects", "UpdateRaw", func(ctx context.Context) error { return r.next.UpdateRaw(ctx, projectID, fields) })
+}
+
+func (r instrumentedProjects) Delete(ctx context.Context, projectID string) error {
+	return observeErr(ctx, r.m, "projects", "Delete", func(ctx context.Context) error { return r.next.Delete(ctx, projectID) })
+}
+
+type instrumentedGallery struct {
+	next repository.GalleryRepository
+	m    *metrics.Metrics
+}
+
+func (r instrumentedGallery) GetByID(ctx context.Context, itemID string) (*model.GalleryItem, error) {
+	return observe(ctx, r.m, "gallery", "GetByID", func(ctx context.Context) (*model.GalleryItem, error) { return r.next.GetByID(ctx, itemID) })
+}
+
+func (r instrumentedGallery) GetByIDs(ctx context.Context, itemIDs []string) ([]*model.GalleryItem, error) {
+	return observe(ctx, r.m, "gallery", "GetByIDs", func(ctx context.Context) ([]*model.GalleryItem, error) { return r.next.GetByIDs(ctx, itemIDs) })
+}
+
+func (r instrumentedGallery) List(ctx context.Context, userID string, limit int, opts *model.ListOptions, fields []string) ([]*model.GalleryItem, *model.Cursor, error) {
+	ctx, c := startCall(ctx, r.m, "gallery", "List")
+	items, next, err := r.next.List(ctx, userID, limit, opts, fields)
+	c.end(err)
+	return items, next, err
+}
+
+func (r instrumentedGallery) CountList(ctx context.Context, userID string, opts *model.ListOptions) (int64, error) {
+	return observe(ctx, r.m, "gallery", "CountList", func(ctx context.Context) (int64, error) { return r.next.CountList(ctx, userID, opts) })
+}
+
+func (r instrumentedGallery) ListByUsers(ctx context.Context, userIDs []string, limit int, after *model.Cursor) ([]*model.GalleryItem, error) {
+	return observe(ctx, r.m, "gallery", "ListByUsers", func(ctx context.Context) ([]*model.GalleryItem, error) {
+		return r.next.ListByUsers(ctx, userIDs, limit, after)
+	})
+}
+
+func (r instrumentedGallery) Count(ctx context.Context, userID string) (int64, error) {
+	return observe(ctx, r.m, "gallery", "Count", func(ctx context.Context) (int64, error) { return r.next.Count(ctx, userID) })
+}
+
+func (r instrumentedGallery) Create(ctx context.Context, item *model.GalleryItem) (string, error) {
+	return observe(ctx, r.m, "gallery", "Create", func(ctx context.Context) (string, error) { return r.next.Create(ctx, item) })
+}
+
+func (r instrumentedGallery) Delete(ctx context.Context, itemID string) error {
+	return observeErr(ctx, r.m, "gallery", "Delete", func(ctx context.Context) error { return r.next.Delete(ctx, itemID) })
+}
+
+func (r instrumentedGallery) SetHidden(ctx context.Context, itemID string, hidden bool) error {
+	return observeErr(ctx, r.m, "gallery", "SetHidden", func(ctx context.Context) error { return r.next.SetHidden(ctx, itemID, hidden) })
+}
+
+type instrumentedNFTs struct {
+	next repository.NFTRepository
+	m    *metrics.Metrics
+}
+
+func (r instrumentedNFTs) GetByID(ctx context.Context, nftID string) (*model.NFT, error) {
+	return observe(ctx, r.m, "nfts", "GetByID", func(ctx context.Context) (*model.NFT, error) { return r.next.GetByID(ctx, nftID) })
+}
+
+func (r instrumentedNFTs) GetByIDs(ctx context.Context, nftIDs []string) ([]*model.NFT, error) {
+	return observe(ctx, r.m, "nfts", "GetByIDs", func(ctx context.Context) ([]*model.NFT, error) { return r.next.GetByIDs(ctx, nftIDs) })
+}
+
+func (r instrumentedNFTs) List(ctx context.Context, userID string, limit int, opts *model.ListOptions, fields []string) ([]*model.NFT, *model.Cursor, error) {
+	ctx, c := startCall(ctx, r.m, "nfts", "List")
+	nfts, next, err := r.next.List(ctx, userID, limit, opts, fields)
+	c.end(err)
+	return nfts, next, err
+}
+
+func (r instrumentedNFTs) CountList(ctx context.Context, userID string, opts *model.ListOptions) (int64, error) {
+	return observe(ctx, r.m, "nfts", "CountList", func(ctx context.Context) (int64, error) { return r.next.CountList(ctx, userID, opts) })
+}
+
+func (r instrumentedNFTs) Count(ctx context.Context, userID string) (int64, error) {
+	return observe(ctx, r.m, "nfts", "Count", func(ctx context.Context) (int64, error) { return r.next.Count(ctx, userID) })
+}
+
+func (r instrumentedNFTs) Create(ctx context.Context, nft *model.NFT) (string, error) {
+	return observe(ctx, r.m, "nfts", "Create", func(ctx context.Context) (string, error) { return r.next.Create(ctx, nft) })
+}
+
+func (r instrumentedNFTs) Update(ctx context.Context, nftID string, updates map[string]interface{}) error {
+	return observeErr(ctx, r.m, "nfts", "Update", func(ctx context.Context) error { return r.next.Update(ctx, nftID, updates) })
+}
+
+func (r instrumentedNFTs) Delete(ctx context.Context, nftID string) error {
+	return observeErr(ctx, r.m, "nfts", "Delete", func(ctx context.Context) error { return r.next.Delete(ctx, nftID) })
+}
+
+type instrumentedFollows struct {
+	next repository.FollowRepository
+	m    *metrics.Metrics
+}
+
+func (r instrumentedFollows) Follow(ctx context.Context, follower, followee *model.User) error {
+	return observeErr(ctx, r.m, "follows", "Follow", func(ctx context.Context) error { return r.next.Follow(ctx, follower, followee) })
+}
+
+func (r instrumentedFollows) Unfollow(ctx context.Context, followerUID, followeeUID string) error {
+	return observeErr(ctx, r.m, "follows", "Unfollow", func(ctx context.Context) error { return r.next.Unfollow(ctx, followerUID, followeeUID) })
+}
+
+func (r instrumentedFollows) IsFollowing(ctx context.Context, followerUID, followeeUID string) (bool, error) {
+	return observe(ctx, r.m, "follows", "IsFollowing", func(ctx context.Context) (bool, error) { return r.next.IsFollowing(ctx, followerUID, followeeUID) })
+}
+
+func (r instrumentedFollows) ListFollowers(ctx context.Context, uid string, limit int, startAfter string) ([]*model.Follow, error) {
+	return observe(ctx, r.m, "follows", "ListFollowers", func(ctx context.Context) ([]*model.Follow, error) {
+		return r.next.ListFollowers(ctx, uid, limit, startAfter)
+	})
+}
+
+func (r instrumentedFollows) ListFollowing(ctx context.Context, uid string, limit int, startAfter string) ([]*model.Follow, error) {
+	return observe(ctx, r.m, "follows", "ListFollowing", func(ctx context.Context) ([]*model.Follow, error) {
+		return r.next.ListFollowing(ctx, uid, limit, startAfter)
+	})
+}
+
+func (r instrumentedFollows) FollowingIDs(ctx context.Context, uid string) ([]string, error) {
+	return observe(ctx, r.m, "follows", "FollowingIDs", func(ctx context.Context) ([]string, error) { return r.next.FollowingIDs(ctx, uid) })
+}
+
+type instrumentedComments struct {
+	next repository.CommentRepository
+	m    *metrics.Metrics
+}
+
+func (r instrumentedComments) GetByID(ctx context.Context, itemID, commentID string) (*model.Comment, error) {
+	return observe(ctx, r.m, "comments", "GetByID", func(ctx context.Context) (*model.Comment, error) { return r.next.GetByID(ctx, itemID, commentID) })
+}
+
+func (r instrumentedComments) List(ctx context.Context, itemID string, limit int, startAfter string) ([]*model.Comment, error) {
+	return observe(ctx, r.m, "comments", "List", func(ctx context.Context) ([]*model.Comment, error) {
+		return r.next.List(ctx, itemID, limit, startAfter)
+	})
+}
+
+func (r instrumentedComments) Create(ctx context.Context, itemID string, comment *model.Comment) (string, error) {
+	return observe(ctx, r.m, "comments", "Create", func(ctx context.Context) (string, error) { return r.next.Create(ctx, itemID, comment) })
+}
+
+func (r instrumentedComments) UpdateBody(ctx context.Context, itemID, commentID, body string) error {
+	return observeErr(ctx, r.m, "comments", "UpdateBody", func(ctx context.Context) error { return r.next.UpdateBody(ctx, itemID, commentID, body) })
+}
+
+func (r instrumentedComments) Delete(ctx context.Context, itemID, commentID string) error {
+	return observeErr(ctx, r.m, "comments", "Delete", func(ctx context.Context) error { return r.next.Delete(ctx, itemID, commentID) })
+}
+
+func (r instrumentedComments) SetHidden(ctx context.Context, itemID, commentID string, hidden bool) error {
+	return observeErr(ctx, r.m, "comments", "SetHidden", func(ctx context.Context) error { return r.next.SetHidden(ctx, itemID, commentID, hidden) })
+}
+
+type instrumentedReactions struct {
+	next repository.ReactionRepository
+	m    *metrics.Metrics
+}
+
+func (r instrumentedReactions) Add(ctx context.Context, itemID, uid, reaction string) error {
+	return observeErr(ctx, r.m, "reactions", "Add", func(ctx context.Context) error { return r.next.Add(ctx, itemID, uid, reaction) })
+}
+
+func (r instrumentedReactions) Remove(ctx context.Context, itemID, uid, reaction string) error {
+	return observeErr(ctx, r.m, "reactions", "Remove", func(ctx context.Context) error { return r.next.Remove(ctx, itemID, uid, reaction) })
+}
+
+func (r instrumentedReactions) ListByUser(ctx context.Context, itemID, uid string) ([]string, error) {
+	return observe(ctx, r.m, "reactions", "ListByUser", func(ctx context.Context) ([]string, error) { return r.next.ListByUser(ctx, itemID, uid) })
+}
+
+type instrumentedReports struct {
+	next repository.ReportRepository
+	m    *metrics.Metrics
+}
+
+func (r instrumentedReports) GetByID(ctx context.Context, reportID string) (*model.Report, error) {
+	return observe(ctx, r.m, "reports", "GetByID", func(ctx context.Context) (*model.Report, error) { return r.next.GetByID(ctx, reportID) })
+}
+
+func (r instrumentedReports) List(ctx context.Context, status string, limit int, startAfter string) ([]*model.Report, error) {
+	return observe(ctx, r.m, "reports", "List", func(ctx context.Context) ([]*model.Report, error) { return r.next.List(ctx, status, limit, startAfter) })
+}
+
+func (r instrumentedReports) Create(ctx context.Context, report *model.Report) (string, error) {
+	return observe(ctx, r.m, "reports", "Create", func(ctx context.Context) (string, error) { return r.next.Create(ctx, report) })
+}
+
+func (r instrumentedReports) Resolve(ctx context.Context, reportID, status, resolvedBy, note string) error {
+	return observeErr(ctx, r.m, "reports", "Resolve", func(ctx context.Context) error { return r.next.Resolve(ctx, reportID, status, resolvedBy, note) })
+}
+
+type instrumentedStats struct {
+	next repository.StatsRepository
+	m    *metrics.Metrics
+}
+
+func (r instrumentedStats) Usage(ctx context.Context) (*model.UsageStats, error) {
+	return observe(ctx, r.m, "stats", "Usage", func(ctx context.Context) (*model.UsageStats, error) { return r.next.Usage(ctx) })
+}
+
+func (r instrumentedStats) RecountUser(ctx context.Context, uid string, apply bool) (*model.CounterRepair, error) {
+	return observe(ctx, r.m, "stats", "RecountUser", func(ctx context.Context) (*model.CounterRepair, error) { return r.next.RecountUser(ctx, uid, apply) })
+}
+
+func (r instrumentedStats) ListUserIDs(ctx context.Context, limit int, startAfter string) ([]string, error) {
+	return observe(ctx, r.m, "stats", "ListUserIDs", func(ctx context.Context) ([]string, error) { return r.next.ListUserIDs(ctx, limit, startAfter) })
+}
+
+type instrumentedAPITokens struct {
+	next repository.APITokenRepository
+	m    *metrics.Metrics
+}
+
+func (r instrumentedAPITokens) GetByID(ctx context.Context, tokenID string) (*model.APIToken, error) {
+	return observe(ctx, r.m, "apiTokens", "GetByID", func(ctx context.Context) (*model.APIToken, error) { return r.next.GetByID(ctx, tokenID) })
+}
+
+func (r instrumentedAPITokens) GetByHash(ctx context.Context, hash string) (*model.APIToken, error) {
+	return observe(ctx, r.m, "apiTokens", "GetByHash", func(ctx context.Context) (*model.APIToken, error) { return r.next.GetByHash(ctx, hash) })
+}
+
+func (r instrumentedAPITokens) ListByUser(ctx context.Context, uid string) ([]*model.APIToken, error) {
+	return observe(ctx, r.m, "apiTokens", "ListByUser", func(ctx context.Context) ([]*model.APIToken, error) { return r.next.ListByUser(ctx, uid) })
+}
+
+func (r instrumentedAPITokens) Create(ctx context.Context, token *model.APIToken) (string, error) {
+	return observe(ctx, r.m, "apiTokens", "Create", func(ctx context.Context) (string, error) { return r.next.Create(ctx, token) })
+}
+
+func (r instrumentedAPITokens) Delete(ctx context.Context, tokenID string) error {
+	return observeErr(ctx, r.m, "apiTokens", "Delete", func(ctx context.Context) error { return r.next.Delete(ctx, tokenID) })
+}
+
+func (r instrumentedAPITokens) TouchLastUsed(ctx context.Context, tokenID string, at time.Time) error {
+	return observeErr(ctx, r.m, "apiTokens", "TouchLastUsed", func(ctx context.Context) error { return r.next.TouchLastUsed(ctx, tokenID, at) })
+}
+
+type instrumentedWebhooks struct {
+	next repository.WebhookRepository
+	m    *metrics.Metrics
+}
+
+func (r instrumentedWebhooks) GetByID(ctx context.Context, webhookID string) (*model.Webhook, error) {
+	return observe(ctx, r.m, "webhooks", "GetByID", func(ctx context.Context) (*model.Webhook, error) { return r.next.GetByID(ctx, webhookID) })
+}
+
+func (r instrumentedWebhooks) ListByUser(ctx context.Context, uid string) ([]*model.Webhook, error) {
+	return observe(ctx, r.m, "webhooks", "ListByUser", func(ctx context.Context) ([]*model.Webhook, error) { return r.next.ListByUser(ctx, uid) })
+}
+
+func (r instrumentedWebhooks) Create(ctx context.Context, webhook *model.Webhook) (string, error) {
+	return observe(ctx, r.m, "webhooks", "Create", func(ctx context.Context) (string, error) { return r.next.Create(ctx, webhook) })
+}
+
+func (r instrumentedWebhooks) Delete(ctx context.Context, webhookID string) error {
+	return observeErr(ctx, r.m, "webhooks", "Delete", func(ctx context.Context) error { return r.next.Delete(ctx, webhookID) })
+}
+
+func (r instrumentedWebhooks) LogDelivery(ctx context.Context, webhookID string, delivery *model.WebhookDelivery) error {
+	return observeErr(ctx, r.m, "webhooks", "LogDelivery", func(ctx context.Context) error { return r.next.LogDelivery(ctx, webhookID, delivery) })
+}
+
+func (r instrumentedWebhooks) ListDeliveries(ctx context.Context, webhookID string, limit int, startAfter string) ([]*model.WebhookDelivery, error) {
+	return observe(ctx, r.m, "webhooks", "ListDeliveries", func(ctx context.Context) ([]*model.WebhookDelivery, error) {
+		return r.next.ListDeliveries(ctx, webhookID, limit, startAfter)
+	})
+}
+
+type instrumentedUploadSessions struct {
+	next repository.UploadSessionRepository
+	m    *metrics.Metrics
+}
+
+func (r instrumentedUploadSessions) Create(ctx context.Context, session *model.UploadSession) (string, error) {
+	return observe(ctx, r.m, "uploadSessions", "Create", func(ctx context.Context) (string, error) { return r.next.Create(ctx, session) })
+}
+
+func (r instrumentedUploadSessions) GetByID(ctx context.Context, uploadID string) (*model.UploadSession, error) {
+	return observe(ctx, r.m, "uploadSessions", "GetByID", func(ctx context.Context) (*model.UploadSession, error) { return r.next.GetByID(ctx, uploadID) })
+}
+
+func (r instrumentedUploadSessions) AppendChunk(ctx context.Context, uploadID string, chunk model.UploadChunk) (*model.UploadSession, error) {
+	return observe(ctx, r.m, "uploadSessions", "AppendChunk", func(ctx context.Context) (*model.UploadSession, error) {
+		return r.next.AppendChunk(ctx, uploadID, chunk)
+	})
+}
+
+func (r instrumentedUploadSessions) MarkFinalizing(ctx context.Context, uploadID, jobID string) error {
+	return observeErr(ctx, r.m, "uploadSessions", "MarkFinalizing", func(ctx context.Context) error { return r.next.MarkFinalizing(ctx, uploadID, jobID) })
+}
+
+func (r instrumentedUploadSessions) Delete(ctx context.Context, uploadID string) error {
+	return observeErr(ctx, r.m, "uploadSessions", "Delete", func(ctx context.Context) error { return r.next.Delete(ctx, uploadID) })
+}
+
+type instrumentedAudit struct {
+	next repository.AuditLogger
+	m    *metrics.Metrics
+}
+
+func (r instrumentedAudit) Log(ctx context.Context, entry *model.AuditEntry) error {
+	return observeErr(ctx, r.m, "audit", "Log", func(ctx context.Context) error { return r.next.Log(ctx, entry) })
+}
+
+func (r instrumentedAudit) List(ctx context.Context, actorUID string, limit int, startAfter string) ([]*model.AuditEntry, error) {
+	return observe(ctx, r.m, "audit", "List", func(ctx context.Context) ([]*model.AuditEntry, error) {
+		return r.next.List(ctx, actorUID, limit, startAfter)
+	})
+}
+
+type instrumentedJobs struct {
+	next jobs.Store
+	m    *metrics.Metrics
+}
+
+func (r instrumentedJobs) Enqueue(ctx context.Context, job *jobs.Job) (*jobs.Job, bool, error) {
+	ctx, c := startCall(ctx, r.m, "jobs", "Enqueue")
+	stored, created, err := r.next.Enqueue(ctx, job)
+	c.end(err)
+	return stored, created, err
+}
+
+func (r instrumentedJobs) Get(ctx context.Context, jobID string) (*jobs.Job, error) {
+	return observe(ctx, r.m, "jobs", "Get", func(ctx context.Context) (*jobs.Job, error) { return r.next.Get(ctx, jobID) })
+}
+
+func (r instrumentedJobs) Claim(ctx context.Context, owner string, now, leaseUntil time.Time) (*jobs.Job, error) {
+	return observe(ctx, r.m, "jobs", "Claim", func(ctx context.Context) (*jobs.Job, error) { return r.next.Claim(ctx, owner, now, leaseUntil) })
+}
+
+func (r instrumentedJobs) Heartbeat(ctx context.Context, jobID, owner string, leaseUntil time.Time) error {
+	return observeErr(ctx, r.m, "jobs", "Heartbeat", func(ctx context.Context) error { return r.next.Heartbeat(ctx, jobID, owner, leaseUntil) })
+}
+
+func (r instrumentedJobs) Complete(ctx context.Context, jobID, owner string, result map[string]interface{}) error {
+	return observeErr(ctx, r.m, "jobs", "Complete", func(ctx context.Context) error { return r.next.Complete(ctx, jobID, owner, result) })
+}
+
+func (r instrumentedJobs) Retry(ctx context.Context, jobID, owner string, runAt time.Time, lastError string) error {
+	return observeErr(ctx, r.m, "jobs", "Retry", func(ctx context.Context) error { return r.next.Retry(ctx, jobID, owner, runAt, lastError) })
+}
+
+func (r instrumentedJobs) Fail(ctx context.Context, jobID, owner string, lastError string) error {
+	return observeErr(ctx, r.m, "jobs", "Fail", func(ctx context.Context) error { return r.next.Fail(ctx, jobID, owner, lastError) })
+}
+
+// instrumentedStorage records Storage calls. Reads are timed until the
+// object is opened, not until the caller has streamed it. URLs are signed
+// locally, without a context, so they are timed but not traced.
+type instrumentedStorage struct {
+	next service.StorageClient
+	m    *metrics.Metrics
+}
+
+func (s instrumentedStorage) GenerateUploadURL(objectPath string, expiry time.Duration) (string, error) {
+	return observe(context.Background(), s.m, "storage", "GenerateUploadURL", func(context.Context) (string, error) { return s.next.GenerateUploadURL(objectPath, expiry) })
+}
+
+func (s instrumentedStorage) GenerateDownloadURL(objectPath string, expiry time.Duration) (string, error) {
+	return observe(context.Background(), s.m, "storage", "GenerateDownloadURL", func(context.Context) (string, error) { return s.next.GenerateDownloadURL(objectPath, expiry) })
+}
+
+func (s instrumentedStorage) ObjectExists(ctx context.Context, objectPath string) (bool, error) {
+	return observe(ctx, s.m, "storage", "ObjectExists", func(ctx context.Context) (bool, error) { return s.next.ObjectExists(ctx, objectPath) })
+}
+
+func (s instrumentedStorage) ReadObject(ctx context.Context, objectPath string) (io.ReadCloser, error) {
+	return observe(ctx, s.m, "storage", "ReadObject", func(ctx context.Context) (io.ReadCloser, error) { return s.next.ReadObject(ctx, objectPath) })
+}
+
+func (s instrumentedStorage) ReadObjectFrom(ctx context.Context, objectPath string, offset int64) (io.ReadCloser, error) {
+	return observe(ctx, s.m, "storage", "ReadObjectFrom", func(ctx context.Context) (io.ReadCloser, error) {
+		return s.next.ReadObjectFrom(ctx, objectPath, offset)
+	})
+}
+
+func (s instrumentedStorage) StatObject(ctx context.Context, objectPath string) (*repository.ObjectAttrs, error) {
+	return observe(ctx, s.m, "storage", "StatObject", func(ctx context.Context) (*repository.ObjectAttrs, error) { return s.next.StatObject(ctx, objectPath) })
+}
+
+func (s instrumentedStorage) WriteObject(ctx context.Context, objectPath string, data io.Reader, contentType string) error {
+	return observeErr(ctx, s.m, "storage", "WriteObject", func(ctx context.Context) error { return s.next.WriteObject(ctx, objectPath, data, contentType) })
+}
+
+func (s instrumentedStorage) DeleteObject(ctx context.Context, objectPath string) error {
+	return observeErr(ctx, s.m, "storage", "DeleteObject", func(ctx context.Context) error { return s.next.DeleteObject(ctx, objectPath) })
+}
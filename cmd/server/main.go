@@ -2,36 +2,42 @@ package main
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"flag"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 	"time"
 
-	"github.com/go-chi/chi/v5"
-	chimiddleware "github.com/go-chi/chi/v5/middleware"
-	"github.com/pandasWhoCode/paintbar/api"
 	"github.com/pandasWhoCode/paintbar/internal/config"
-	"github.com/pandasWhoCode/paintbar/internal/handler"
-	mw "github.com/pandasWhoCode/paintbar/internal/middleware"
+	"github.com/pandasWhoCode/paintbar/internal/health"
+	"github.com/pandasWhoCode/paintbar/internal/jobs"
 	"github.com/pandasWhoCode/paintbar/internal/repository"
 	"github.com/pandasWhoCode/paintbar/internal/service"
-	"github.com/pandasWhoCode/paintbar/web"
+	"github.com/pandasWhoCode/paintbar/internal/tracing"
 )
 
 // Coverage: application entry point — not unit-testable. Exercised by
 // integration tests and manual verification via `task run`.
 func main() {
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML or TOML config file; environment variables override it (default $CONFIG_FILE)")
+	printConfig := flag.Bool("print-config", false, "print the resolved configuration, secrets redacted, and exit")
+	flag.Parse()
+
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.LoadFile(*configFile)
 	if err != nil {
 		slog.Error("failed to load config", "error", err)
 		os.Exit(1)
 	}
+	if *printConfig {
+		if err := cfg.WriteYAML(os.Stdout); err != nil {
+			slog.Error("failed to print config", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	// Set up structured logging
 	logLevel := slog.LevelInfo
@@ -46,6 +52,13 @@ func main() {
 		"port", cfg.Port,
 	)
 
+	// Tracing is set up before the clients so their spans are exported too
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.TraceExporter, cfg.Env)
+	if err != nil {
+		slog.Error("failed to set up tracing", "error", err)
+		os.Exit(1)
+	}
+
 	// Initialize Firebase clients
 	ctx := context.Background()
 	fbClients, err := repository.NewFirebaseClients(ctx,
@@ -62,142 +75,62 @@ func main() {
 	}
 	defer fbClients.Close()
 
-	// Initialize Firestore repositories
-	userRepo := repository.NewUserRepository(fbClients.Firestore)
-	projectRepo := repository.NewProjectRepository(fbClients.Firestore)
-	galleryRepo := repository.NewGalleryRepository(fbClients.Firestore)
-	nftRepo := repository.NewNFTRepository(fbClients.Firestore)
-
-	// Initialize Storage service
-	storageSvc := repository.NewStorageService(cfg.FirebaseStorageBucket, cfg.FirebaseStorageEmulatorHost)
-
-	// Initialize services
-	authService := service.NewAuthService(fbClients.Auth)
-	userService := service.NewUserService(userRepo)
-	projectService := service.NewProjectService(projectRepo, storageSvc)
-	galleryService := service.NewGalleryService(galleryRepo)
-	nftService := service.NewNFTService(nftRepo)
-
-	// Initialize handlers
-	profileHandler := handler.NewProfileHandler(userService)
-	projectHandler := handler.NewProjectHandler(projectService)
-	galleryHandler := handler.NewGalleryHandler(galleryService)
-	nftHandler := handler.NewNFTHandler(nftService)
-	docsHandler := handler.NewDocsHandler(api.OpenAPISpec)
-
-	// Initialize template renderer
-	renderer, err := handler.NewTemplateRenderer(web.TemplatesFS)
-	if err != nil {
-		slog.Error("failed to parse templates", "error", err)
-		os.Exit(1)
+	// Initialize the audit log sink
+	auditLogger := repository.NewFirestoreAuditLogger(fbClients.Firestore)
+	if cfg.AuditLogSink == config.AuditSinkJSONL {
+		auditLogger, err = repository.NewJSONLAuditLogger(cfg.AuditLogPath)
+		if err != nil {
+			slog.Error("failed to open audit log", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("audit log writing to file", "path", cfg.AuditLogPath)
 	}
-	pageHandler := handler.NewPageHandler(renderer, cfg.Env)
 
-	// Set up rate limiters (relaxed in local env for development)
-	rateLimiter := mw.NewRateLimiter(100, time.Minute)
-	sensitiveRate := 20
-	if cfg.Env == "local" {
-		sensitiveRate = 60
-	}
-	sensitiveLimiter := mw.NewRateLimiter(sensitiveRate, time.Minute)
-
-	// Set up router
-	r := chi.NewRouter()
-
-	// Global middleware stack (order matters)
-	r.Use(chimiddleware.RequestID)
-	r.Use(chimiddleware.RealIP)
-	r.Use(mw.Recovery(logger))
-	r.Use(mw.SecurityHeaders(cfg.Env))
-	r.Use(mw.RequestLogger(logger))
-	r.Use(rateLimiter.Handler())
-
-	// Static files (directory listing disabled)
-	fileServer := http.FileServer(http.Dir("web/static"))
-	r.Handle("/static/*", http.StripPrefix("/static/", noDirListing(fileServer)))
-
-	// Favicon (browsers request /favicon.ico at root)
-	r.Get("/favicon.ico", func(w http.ResponseWriter, r *http.Request) {
-		http.ServeFile(w, r, "web/static/images/favicon.ico")
-	})
-
-	// Page routes (SSR via Go templates)
-	r.Get("/", pageHandler.Login)
-	r.Get("/login", pageHandler.Login)
-	r.Get("/profile", pageHandler.Profile)
-	r.Get("/projects", pageHandler.Projects)
-	r.Get("/canvas", pageHandler.Canvas)
-	r.NotFound(pageHandler.NotFound)
-
-	// Health check (no rate limiting, no auth)
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-
-		fsStatus := "ok"
-		if err := repository.FirestoreHealthCheck(r.Context(), fbClients.Firestore); err != nil {
-			slog.Error("health check: firestore", "error", err)
-			fsStatus = "error"
-		}
+	// Readiness depends on Firestore and, outside the emulator, on fetching
+	// the keys ID tokens are verified with. Storage only backs uploads and
+	// downloads, so an outage there degrades the server but leaves it ready.
+	storage := repository.NewStorageService(cfg.FirebaseStorageBucket, cfg.FirebaseStorageEmulatorHost)
+	healthChecks := []health.Check{
+		{Name: "firestore", Critical: true, Run: func(ctx context.Context) error {
+			return repository.FirestoreHealthCheck(ctx, fbClients.Firestore)
+		}},
+		{Name: "storage", Run: storage.HealthCheck},
+	}
+	if cfg.FirebaseAuthEmulatorHost == "" {
+		healthChecks = append(healthChecks, health.Check{Name: "auth", Critical: true, Run: func(ctx context.Context) error {
+			return service.AuthKeysHealthCheck(ctx, http.DefaultClient, service.IDTokenCertsURL)
+		}})
+	}
 
-		json.NewEncoder(w).Encode(map[string]string{
-			"status":    "ok",
-			"firestore": fsStatus,
-		})
-	})
-
-	// API docs (Swagger UI) — local development only
-	if cfg.IsLocal() {
-		r.Get("/api/docs", docsHandler.ServeUI)
-		r.Get("/api/docs/openapi.yaml", docsHandler.ServeSpec)
-		r.Get("/api/docs/init.js", docsHandler.ServeInitJS)
-	}
-
-	// API routes with CORS and auth
-	r.Route("/api", func(r chi.Router) {
-		r.Use(mw.CORS(mw.DefaultCORSConfig(cfg.Env)))
-		r.Use(mw.Auth(authService))
-
-		r.Get("/ping", func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Content-Type", "application/json")
-			fmt.Fprintln(w, `{"data":"pong"}`)
-		})
-
-		// Profile
-		r.Get("/profile", profileHandler.GetProfile)
-		r.Put("/profile", profileHandler.UpdateProfile)
-		r.With(mw.SensitiveEndpoint(sensitiveLimiter)).Post("/claim-username", profileHandler.ClaimUsername)
-
-		// Projects
-		r.Get("/projects", projectHandler.ListProjects)
-		r.With(mw.SensitiveEndpoint(sensitiveLimiter)).Post("/projects", projectHandler.CreateProject)
-		r.Get("/projects/count", projectHandler.CountProjects)
-		r.Get("/projects/by-title", projectHandler.GetProjectByTitle)
-		r.Get("/projects/{id}", projectHandler.GetProject)
-		r.Put("/projects/{id}", projectHandler.UpdateProject)
-		r.Delete("/projects/{id}", projectHandler.DeleteProject)
-		r.With(mw.SensitiveEndpoint(sensitiveLimiter)).Post("/projects/{id}/confirm-upload", projectHandler.ConfirmUpload)
-		r.With(mw.SensitiveEndpoint(sensitiveLimiter)).Post("/projects/{id}/upload-blob", projectHandler.UploadBlob)
-		r.Get("/projects/{id}/blob", projectHandler.DownloadBlob)
-
-		// Gallery
-		r.Get("/gallery", galleryHandler.ListItems)
-		r.Post("/gallery", galleryHandler.ShareToGallery)
-		r.Get("/gallery/count", galleryHandler.CountItems)
-		r.Get("/gallery/{id}", galleryHandler.GetItem)
-		r.Delete("/gallery/{id}", galleryHandler.DeleteItem)
-
-		// NFTs
-		r.Get("/nfts", nftHandler.ListNFTs)
-		r.Post("/nfts", nftHandler.CreateNFT)
-		r.Get("/nfts/count", nftHandler.CountNFTs)
-		r.Get("/nfts/{id}", nftHandler.GetNFT)
-		r.Delete("/nfts/{id}", nftHandler.DeleteNFT)
-	})
+	// Firestore repositories, Firebase Storage and Firebase Auth
+	app, err := newApp(cfg, &backends{
+		users:          repository.NewUserRepository(fbClients.Firestore),
+		projects:       repository.NewProjectRepository(fbClients.Firestore),
+		gallery:        repository.NewGalleryRepository(fbClients.Firestore),
+		nfts:           repository.NewNFTRepository(fbClients.Firestore),
+		follows:        repository.NewFollowRepository(fbClients.Firestore),
+		comments:       repository.NewCommentRepository(fbClients.Firestore),
+		reactions:      repository.NewReactionRepository(fbClients.Firestore),
+		reports:        repository.NewReportRepository(fbClients.Firestore),
+		stats:          repository.NewStatsRepository(fbClients.Firestore),
+		apiTokens:      repository.NewAPITokenRepository(fbClients.Firestore),
+		webhooks:       repository.NewWebhookRepository(fbClients.Firestore),
+		uploadSessions: repository.NewUploadSessionRepository(fbClients.Firestore),
+		audit:          auditLogger,
+		jobs:           jobs.NewFirestoreStore(fbClients.Firestore),
+		storage:        storage,
+		identity:       service.NewAuthService(fbClients.Auth),
+		healthChecks:   healthChecks,
+	}, logger)
+	if err != nil {
+		slog.Error("failed to initialize server", "error", err)
+		os.Exit(1)
+	}
 
 	// Create HTTP server
 	srv := &http.Server{
 		Addr:              ":" + cfg.Port,
-		Handler:           r,
+		Handler:           app.router,
 		ReadTimeout:       15 * time.Second,
 		ReadHeaderTimeout: 5 * time.Second,
 		WriteTimeout:      15 * time.Second,
@@ -205,6 +138,18 @@ func main() {
 		MaxHeaderBytes:    1 << 20, // 1 MB
 	}
 
+	// Prometheus metrics on their own port, which is not exposed publicly
+	var metricsSrv *http.Server
+	if cfg.MetricsPort != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", app.metrics.Handler())
+		metricsSrv = &http.Server{
+			Addr:              ":" + cfg.MetricsPort,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+	}
+
 	// Graceful shutdown
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
@@ -216,11 +161,29 @@ func main() {
 		}
 	}()
 
+	// A metrics listener failure is logged, not fatal: the API still serves
+	if metricsSrv != nil {
+		go func() {
+			if err := metricsSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				slog.Error("metrics listen error", "error", err)
+			}
+		}()
+	}
+
+	if cfg.JobWorkers > 0 {
+		app.jobRunner.Start()
+	}
+
 	slog.Info("server started", "addr", srv.Addr)
 
 	<-done
 	slog.Info("server shutting down...")
 
+	// Fail readiness first and keep serving while load balancers notice,
+	// so in-flight traffic drains instead of hitting a closed listener
+	app.health.Drain()
+	time.Sleep(cfg.DrainDelay)
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -229,17 +192,26 @@ func main() {
 		os.Exit(1)
 	}
 
-	slog.Info("server stopped gracefully")
-}
-
-// noDirListing wraps an http.Handler to return 404 for directory requests,
-// preventing exposure of application file structure.
-func noDirListing(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if strings.HasSuffix(r.URL.Path, "/") {
-			http.NotFound(w, r)
-			return
+	if metricsSrv != nil {
+		if err := metricsSrv.Shutdown(ctx); err != nil {
+			slog.Warn("metrics server forced to shutdown", "error", err)
 		}
-		next.ServeHTTP(w, r)
-	})
+	}
+
+	// Drain background jobs; unfinished ones are retried by another
+	// instance once their lease expires.
+	if err := app.jobRunner.Shutdown(ctx); err != nil {
+		slog.Warn("background jobs still running at shutdown", "error", err)
+	}
+
+	if err := app.webhooks.Wait(ctx); err != nil {
+		slog.Warn("webhook deliveries still in flight at shutdown", "error", err)
+	}
+
+	// Flush the spans of the last requests and jobs
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Warn("trace export did not finish at shutdown", "error", err)
+	}
+
+	slog.Info("server stopped gracefully")
 }
@@ -0,0 +1,1409 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"github.com/pandasWhoCode/paintbar/internal/repository"
+	"github.com/pandasWhoCode/paintbar/internal/service"
+)
+
+// In-memory backends for the contract tests. They follow the mocks in the
+// service package, keeping just enough behaviour for every documented
+// operation to succeed against them.
+
+// memObjectUpdated is the modification time reported for stored objects.
+var memObjectUpdated = time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+
+// --- In-memory UserRepository ---
+
+type memUserRepo struct {
+	mu        sync.Mutex
+	users     map[string]*model.User
+	usernames map[string]string // username -> uid
+}
+
+func newMemUserRepo() *memUserRepo {
+	return &memUserRepo{
+		users:     make(map[string]*model.User),
+		usernames: make(map[string]string),
+	}
+}
+
+func (r *memUserRepo) GetByID(_ context.Context, uid string) (*model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[uid]
+	if !ok {
+		return nil, fmt.Errorf("user %s not found", uid)
+	}
+	copy := *u
+	return &copy, nil
+}
+func (r *memUserRepo) GetByIDs(_ context.Context, uids []string) ([]*model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.User
+	for _, id := range uids {
+		if u, ok := r.users[id]; ok {
+			copy := *u
+			result = append(result, &copy)
+		}
+	}
+	return result, nil
+}
+
+func (r *memUserRepo) GetByUsername(_ context.Context, username string) (*model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.users {
+		if u.Username == username {
+			copy := *u
+			return &copy, nil
+		}
+	}
+	return nil, fmt.Errorf("user %q not found", username)
+}
+
+func (r *memUserRepo) Create(_ context.Context, user *model.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.users[user.UID] = user
+	return nil
+}
+
+func (r *memUserRepo) Update(_ context.Context, uid string, update *model.UserUpdate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[uid]
+	if !ok {
+		return fmt.Errorf("user %s not found", uid)
+	}
+	m := update.ToUpdateMap()
+	if v, ok := m["displayName"]; ok {
+		u.DisplayName = v.(string)
+	}
+	if v, ok := m["bio"]; ok {
+		u.Bio = v.(string)
+	}
+	if v, ok := m["location"]; ok {
+		u.Location = v.(string)
+	}
+	if v, ok := m["website"]; ok {
+		u.Website = v.(string)
+	}
+	if v, ok := m["twitterHandle"]; ok {
+		u.TwitterHandle = v.(string)
+	}
+	return nil
+}
+
+func (r *memUserRepo) ClaimUsername(_ context.Context, uid string, username string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, taken := r.usernames[username]; taken {
+		return fmt.Errorf("username %q is already taken", username)
+	}
+	r.usernames[username] = uid
+	if u, ok := r.users[uid]; ok {
+		u.Username = username
+	}
+	return nil
+}
+
+func (r *memUserRepo) SetSuspended(_ context.Context, uid string, suspended bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[uid]
+	if !ok {
+		return fmt.Errorf("user %s not found", uid)
+	}
+	u.Suspended = suspended
+	return nil
+}
+
+func (r *memUserRepo) ReleaseUsername(_ context.Context, username string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	uid, ok := r.usernames[username]
+	if !ok {
+		return "", fmt.Errorf("username %q not found", username)
+	}
+	delete(r.usernames, username)
+	if u, ok := r.users[uid]; ok && u.Username == username {
+		u.Username = ""
+	}
+	return uid, nil
+}
+
+// count adds delta to one of uid's counters, creating the user document as
+// the Firestore merge does.
+func (r *memUserRepo) count(uid string, counter func(*model.User) *int64, delta int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[uid]
+	if !ok {
+		u = &model.User{UID: uid}
+		r.users[uid] = u
+	}
+	*counter(u) += delta
+}
+
+// --- In-memory ProjectRepository ---
+
+type memProjectRepo struct {
+	mu       sync.Mutex
+	users    *memUserRepo
+	projects map[string]*model.Project
+	nextID   int
+}
+
+func newMemProjectRepo(users *memUserRepo) *memProjectRepo {
+	return &memProjectRepo{
+		users:    users,
+		projects: make(map[string]*model.Project),
+	}
+}
+
+func (r *memProjectRepo) GetByID(_ context.Context, projectID string) (*model.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.projects[projectID]
+	if !ok {
+		return nil, fmt.Errorf("project %s not found", projectID)
+	}
+	copy := *p
+	return &copy, nil
+}
+func (r *memProjectRepo) GetByIDs(_ context.Context, projectIDs []string) ([]*model.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.Project
+	for _, id := range projectIDs {
+		if p, ok := r.projects[id]; ok {
+			copy := *p
+			result = append(result, &copy)
+		}
+	}
+	return result, nil
+}
+
+func (r *memProjectRepo) FindByContentHash(_ context.Context, userID, contentHash string) (*model.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.projects {
+		if p.UserID == userID && p.ContentHash == contentHash {
+			copy := *p
+			return &copy, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memProjectRepo) FindByTitle(_ context.Context, userID, title string) (*model.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.projects {
+		if p.UserID == userID && p.Title == title {
+			copy := *p
+			return &copy, nil
+		}
+	}
+	return nil, nil
+}
+
+// List applies the tag and isPublic filters and sorts by title or
+// createdAt, with the ID as tiebreak, so cursors can be exercised.
+func (r *memProjectRepo) List(_ context.Context, userID string, limit int, opts *model.ListOptions, _ []string) ([]*model.Project, *model.Cursor, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.Project
+	for _, p := range r.projects {
+		if listMatches(p, userID, opts) {
+			copy := *p
+			result = append(result, &copy)
+		}
+	}
+
+	key := func(p *model.Project) string {
+		if opts.Sort == "title" {
+			return p.Title
+		}
+		return p.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		ki, kj := key(result[i])+"\x00"+result[i].ID, key(result[j])+"\x00"+result[j].ID
+		if opts.Order == model.SortAsc {
+			return ki < kj
+		}
+		return ki > kj
+	})
+	if c := opts.Cursor; c != nil {
+		after := c.Value + "\x00" + c.ID
+		for len(result) > 0 {
+			k := key(result[0]) + "\x00" + result[0].ID
+			if (opts.Order == model.SortAsc && k > after) || (opts.Order != model.SortAsc && k < after) {
+				break
+			}
+			result = result[1:]
+		}
+	}
+
+	if len(result) <= limit {
+		return result, nil, nil
+	}
+	result = result[:limit]
+	last := result[limit-1]
+	return result, &model.Cursor{Sort: opts.Sort, Order: opts.Order, Filter: opts.FilterKey(), Value: key(last), ID: last.ID}, nil
+}
+
+func (r *memProjectRepo) CountList(_ context.Context, userID string, opts *model.ListOptions) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var count int64
+	for _, p := range r.projects {
+		if listMatches(p, userID, opts) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// listMatches reports whether p passes the tag and isPublic filters.
+func listMatches(p *model.Project, userID string, opts *model.ListOptions) bool {
+	return p.UserID == userID &&
+		(opts.Tag == "" || slices.Contains(p.Tags, opts.Tag)) &&
+		(opts.IsPublic == nil || p.IsPublic == *opts.IsPublic)
+}
+
+func (r *memProjectRepo) Count(_ context.Context, userID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var count int64
+	for _, p := range r.projects {
+		if p.UserID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memProjectRepo) Create(_ context.Context, project *model.Project) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	id := fmt.Sprintf("proj_%d", r.nextID)
+	project.ID = id
+	r.projects[id] = project
+	r.users.count(project.UserID, func(u *model.User) *int64 { return &u.ProjectCount }, 1)
+	return id, nil
+}
+
+func (r *memProjectRepo) Update(_ context.Context, projectID string, _ *model.ProjectUpdate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.projects[projectID]; !ok {
+		return fmt.Errorf("project %s not found", projectID)
+	}
+	return nil
+}
+
+func (r *memProjectRepo) UpdateRaw(_ context.Context, projectID string, fields map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.projects[projectID]
+	if !ok {
+		return fmt.Errorf("project %s not found", projectID)
+	}
+	if v, ok := fields["storageURL"]; ok {
+		p.StorageURL = v.(string)
+	}
+	if v, ok := fields["contentHash"]; ok {
+		p.ContentHash = v.(string)
+	}
+	if v, ok := fields["thumbnailData"]; ok {
+		p.ThumbnailData = v.(string)
+	}
+	if v, ok := fields["width"]; ok {
+		p.Width = v.(int)
+	}
+	if v, ok := fields["height"]; ok {
+		p.Height = v.(int)
+	}
+	if v, ok := fields["isPublic"]; ok {
+		p.IsPublic = v.(bool)
+	}
+	if v, ok := fields["tags"]; ok {
+		p.Tags = v.([]string)
+	}
+	return nil
+}
+
+func (r *memProjectRepo) Delete(_ context.Context, projectID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	project, ok := r.projects[projectID]
+	if !ok {
+		return fmt.Errorf("project %s not found", projectID)
+	}
+	delete(r.projects, projectID)
+	r.users.count(project.UserID, func(u *model.User) *int64 { return &u.ProjectCount }, -1)
+	return nil
+}
+
+// --- In-memory GalleryRepository ---
+
+type memGalleryRepo struct {
+	mu sync.Mutex
+	// subcollections clear an item's comments and reactions on Delete.
+	subcollections []func(itemID string)
+	users          *memUserRepo
+	items          map[string]*model.GalleryItem
+	nextID         int
+}
+
+func newMemGalleryRepo(users *memUserRepo) *memGalleryRepo {
+	return &memGalleryRepo{
+		users: users,
+		items: make(map[string]*model.GalleryItem),
+	}
+}
+
+func (r *memGalleryRepo) GetByID(_ context.Context, itemID string) (*model.GalleryItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.items[itemID]
+	if !ok {
+		return nil, fmt.Errorf("gallery item %s not found", itemID)
+	}
+	copy := *item
+	return &copy, nil
+}
+func (r *memGalleryRepo) GetByIDs(_ context.Context, itemIDs []string) ([]*model.GalleryItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.GalleryItem
+	for _, id := range itemIDs {
+		if item, ok := r.items[id]; ok {
+			copy := *item
+			result = append(result, &copy)
+		}
+	}
+	return result, nil
+}
+
+func (r *memGalleryRepo) List(_ context.Context, userID string, limit int, _ *model.ListOptions, _ []string) ([]*model.GalleryItem, *model.Cursor, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.GalleryItem
+	for _, item := range r.items {
+		if item.UserID == userID {
+			copy := *item
+			result = append(result, &copy)
+			if len(result) >= limit {
+				break
+			}
+		}
+	}
+	return result, nil, nil
+}
+
+func (r *memGalleryRepo) ListByUsers(_ context.Context, userIDs []string, limit int, after *model.Cursor) ([]*model.GalleryItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	wanted := make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		wanted[id] = true
+	}
+	var result []*model.GalleryItem
+	for _, item := range r.items {
+		if wanted[item.UserID] && feedAfter(item, after) {
+			copy := *item
+			result = append(result, &copy)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return feedAfter(result[j], feedPosition(result[i])) })
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// feedAfter reports whether item comes after position c in a feed ordered
+// newest first, then by descending ID. A nil position is the feed's start.
+func feedAfter(item *model.GalleryItem, c *model.Cursor) bool {
+	if c == nil {
+		return true
+	}
+	t, _ := time.Parse(time.RFC3339Nano, c.Value)
+	if !item.CreatedAt.Equal(t) {
+		return item.CreatedAt.Before(t)
+	}
+	return item.ID < c.ID
+}
+
+// feedPosition returns item's position in a feed.
+func feedPosition(item *model.GalleryItem) *model.Cursor {
+	return &model.Cursor{Value: item.CreatedAt.Format(time.RFC3339Nano), ID: item.ID}
+}
+
+func (r *memGalleryRepo) CountList(ctx context.Context, userID string, _ *model.ListOptions) (int64, error) {
+	return r.Count(ctx, userID)
+}
+
+func (r *memGalleryRepo) Count(_ context.Context, userID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var count int64
+	for _, item := range r.items {
+		if item.UserID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memGalleryRepo) Create(_ context.Context, item *model.GalleryItem) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	id := fmt.Sprintf("gal_%d", r.nextID)
+	item.ID = id
+	r.items[id] = item
+	r.users.count(item.UserID, func(u *model.User) *int64 { return &u.GalleryCount }, 1)
+	return id, nil
+}
+
+func (r *memGalleryRepo) Delete(_ context.Context, itemID string) error {
+	r.mu.Lock()
+	item, ok := r.items[itemID]
+	if !ok {
+		r.mu.Unlock()
+		return fmt.Errorf("gallery item %s not found", itemID)
+	}
+	delete(r.items, itemID)
+	r.users.count(item.UserID, func(u *model.User) *int64 { return &u.GalleryCount }, -1)
+	r.mu.Unlock()
+	for _, clear := range r.subcollections {
+		clear(itemID)
+	}
+	return nil
+}
+
+func (r *memGalleryRepo) SetHidden(_ context.Context, itemID string, hidden bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.items[itemID]
+	if !ok {
+		return fmt.Errorf("gallery item %s not found", itemID)
+	}
+	item.Hidden = hidden
+	return nil
+}
+
+// --- In-memory NFTRepository ---
+
+type memNFTRepo struct {
+	mu     sync.Mutex
+	users  *memUserRepo
+	nfts   map[string]*model.NFT
+	nextID int
+}
+
+func newMemNFTRepo(users *memUserRepo) *memNFTRepo {
+	return &memNFTRepo{
+		users: users,
+		nfts:  make(map[string]*model.NFT),
+	}
+}
+
+func (r *memNFTRepo) GetByID(_ context.Context, nftID string) (*model.NFT, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	nft, ok := r.nfts[nftID]
+	if !ok {
+		return nil, fmt.Errorf("nft %s not found", nftID)
+	}
+	copy := *nft
+	return &copy, nil
+}
+func (r *memNFTRepo) GetByIDs(_ context.Context, nftIDs []string) ([]*model.NFT, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.NFT
+	for _, id := range nftIDs {
+		if nft, ok := r.nfts[id]; ok {
+			copy := *nft
+			result = append(result, &copy)
+		}
+	}
+	return result, nil
+}
+
+func (r *memNFTRepo) List(_ context.Context, userID string, limit int, _ *model.ListOptions, _ []string) ([]*model.NFT, *model.Cursor, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.NFT
+	for _, nft := range r.nfts {
+		if nft.UserID == userID {
+			copy := *nft
+			result = append(result, &copy)
+			if len(result) >= limit {
+				break
+			}
+		}
+	}
+	return result, nil, nil
+}
+
+func (r *memNFTRepo) CountList(ctx context.Context, userID string, _ *model.ListOptions) (int64, error) {
+	return r.Count(ctx, userID)
+}
+
+func (r *memNFTRepo) Count(_ context.Context, userID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var count int64
+	for _, nft := range r.nfts {
+		if nft.UserID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memNFTRepo) Create(_ context.Context, nft *model.NFT) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	id := fmt.Sprintf("nft_%d", r.nextID)
+	nft.ID = id
+	r.nfts[id] = nft
+	r.users.count(nft.UserID, func(u *model.User) *int64 { return &u.NFTCount }, 1)
+	return id, nil
+}
+
+func (r *memNFTRepo) Update(_ context.Context, nftID string, updates map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n, ok := r.nfts[nftID]
+	if !ok {
+		return fmt.Errorf("nft %s not found", nftID)
+	}
+	if v, ok := updates["hidden"]; ok {
+		n.Hidden = v.(bool)
+	}
+	return nil
+}
+
+func (r *memNFTRepo) Delete(_ context.Context, nftID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	nft, ok := r.nfts[nftID]
+	if !ok {
+		return fmt.Errorf("nft %s not found", nftID)
+	}
+	delete(r.nfts, nftID)
+	r.users.count(nft.UserID, func(u *model.User) *int64 { return &u.NFTCount }, -1)
+	return nil
+}
+
+// --- In-memory FollowRepository ---
+
+type memFollowRepo struct {
+	mu        sync.Mutex
+	users     *memUserRepo
+	following map[string]map[string]time.Time // follower -> followee -> since
+}
+
+func newMemFollowRepo(users *memUserRepo) *memFollowRepo {
+	return &memFollowRepo{
+		users:     users,
+		following: make(map[string]map[string]time.Time),
+	}
+}
+
+func (r *memFollowRepo) Follow(_ context.Context, follower, followee *model.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.following[follower.UID] == nil {
+		r.following[follower.UID] = make(map[string]time.Time)
+	}
+	if _, ok := r.following[follower.UID][followee.UID]; ok {
+		return nil
+	}
+	r.users.mu.Lock()
+	defer r.users.mu.Unlock()
+	if r.users.users[follower.UID].FollowingCount >= repository.MaxFollowing {
+		return fmt.Errorf("validation: cannot follow more than %d users", repository.MaxFollowing)
+	}
+	r.following[follower.UID][followee.UID] = time.Now()
+	r.users.users[follower.UID].FollowingCount++
+	r.users.users[followee.UID].FollowerCount++
+	return nil
+}
+
+func (r *memFollowRepo) Unfollow(_ context.Context, followerUID, followeeUID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.following[followerUID][followeeUID]; !ok {
+		return nil
+	}
+	delete(r.following[followerUID], followeeUID)
+	r.users.mu.Lock()
+	r.users.users[followerUID].FollowingCount--
+	r.users.users[followeeUID].FollowerCount--
+	r.users.mu.Unlock()
+	return nil
+}
+
+func (r *memFollowRepo) IsFollowing(_ context.Context, followerUID, followeeUID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.following[followerUID][followeeUID]
+	return ok, nil
+}
+
+func (r *memFollowRepo) ListFollowers(_ context.Context, uid string, limit int, _ string) ([]*model.Follow, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.Follow
+	for follower, followees := range r.following {
+		if since, ok := followees[uid]; ok {
+			result = append(result, &model.Follow{UID: follower, CreatedAt: since})
+		}
+	}
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (r *memFollowRepo) ListFollowing(_ context.Context, uid string, limit int, _ string) ([]*model.Follow, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.Follow
+	for followee, since := range r.following[uid] {
+		result = append(result, &model.Follow{UID: followee, CreatedAt: since})
+	}
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (r *memFollowRepo) FollowingIDs(_ context.Context, uid string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ids []string
+	for followee := range r.following[uid] {
+		ids = append(ids, followee)
+	}
+	return ids, nil
+}
+
+// --- In-memory CommentRepository ---
+
+type memCommentRepo struct {
+	mu       sync.Mutex
+	gallery  *memGalleryRepo
+	comments map[string]*model.Comment
+	nextID   int
+}
+
+func newMemCommentRepo(gallery *memGalleryRepo) *memCommentRepo {
+	r := &memCommentRepo{
+		gallery:  gallery,
+		comments: make(map[string]*model.Comment),
+	}
+	gallery.subcollections = append(gallery.subcollections, func(itemID string) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		for id, c := range r.comments {
+			if c.ItemID == itemID {
+				delete(r.comments, id)
+			}
+		}
+	})
+	return r
+}
+
+func (r *memCommentRepo) GetByID(_ context.Context, itemID, commentID string) (*model.Comment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.comments[commentID]
+	if !ok || c.ItemID != itemID {
+		return nil, fmt.Errorf("comment %s not found", commentID)
+	}
+	copy := *c
+	return &copy, nil
+}
+
+func (r *memCommentRepo) List(_ context.Context, itemID string, limit int, startAfter string) ([]*model.Comment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.Comment
+	for _, c := range r.comments {
+		if c.ItemID == itemID && c.ID > startAfter && !c.Hidden {
+			copy := *c
+			result = append(result, &copy)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (r *memCommentRepo) Create(_ context.Context, itemID string, comment *model.Comment) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	id := fmt.Sprintf("c_%03d", r.nextID)
+	comment.ID = id
+	comment.ItemID = itemID
+	r.comments[id] = comment
+	r.gallery.mu.Lock()
+	r.gallery.items[itemID].CommentCount++
+	r.gallery.mu.Unlock()
+	return id, nil
+}
+
+func (r *memCommentRepo) UpdateBody(_ context.Context, _, commentID, body string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.comments[commentID]
+	if !ok {
+		return fmt.Errorf("comment %s not found", commentID)
+	}
+	c.Body = body
+	c.Edited = true
+	return nil
+}
+
+func (r *memCommentRepo) Delete(_ context.Context, itemID, commentID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	removed := int64(0)
+	for id, c := range r.comments {
+		if id == commentID || c.ParentID == commentID {
+			delete(r.comments, id)
+			removed++
+		}
+	}
+	r.gallery.mu.Lock()
+	r.gallery.items[itemID].CommentCount -= removed
+	r.gallery.mu.Unlock()
+	return nil
+}
+
+func (r *memCommentRepo) SetHidden(_ context.Context, _, commentID string, hidden bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.comments[commentID]
+	if !ok {
+		return fmt.Errorf("comment %s not found", commentID)
+	}
+	c.Hidden = hidden
+	return nil
+}
+
+// --- In-memory ReactionRepository ---
+
+type memReactionRepo struct {
+	mu        sync.Mutex
+	gallery   *memGalleryRepo
+	reactions map[string]map[string]bool // itemID -> "uid_reaction" -> present
+}
+
+func newMemReactionRepo(gallery *memGalleryRepo) *memReactionRepo {
+	r := &memReactionRepo{
+		gallery:   gallery,
+		reactions: make(map[string]map[string]bool),
+	}
+	gallery.subcollections = append(gallery.subcollections, func(itemID string) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.reactions, itemID)
+	})
+	return r
+}
+
+func (r *memReactionRepo) Add(_ context.Context, itemID, uid, reaction string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.reactions[itemID] == nil {
+		r.reactions[itemID] = make(map[string]bool)
+	}
+	key := uid + "_" + reaction
+	if r.reactions[itemID][key] {
+		return nil
+	}
+	r.reactions[itemID][key] = true
+	r.gallery.mu.Lock()
+	item := r.gallery.items[itemID]
+	if item.ReactionCounts == nil {
+		item.ReactionCounts = make(map[string]int64)
+	}
+	item.ReactionCounts[reaction]++
+	r.gallery.mu.Unlock()
+	return nil
+}
+
+func (r *memReactionRepo) Remove(_ context.Context, itemID, uid, reaction string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := uid + "_" + reaction
+	if !r.reactions[itemID][key] {
+		return nil
+	}
+	delete(r.reactions[itemID], key)
+	r.gallery.mu.Lock()
+	r.gallery.items[itemID].ReactionCounts[reaction]--
+	r.gallery.mu.Unlock()
+	return nil
+}
+
+func (r *memReactionRepo) ListByUser(_ context.Context, itemID, uid string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	mine := []string{}
+	for key := range r.reactions[itemID] {
+		if strings.HasPrefix(key, uid+"_") {
+			mine = append(mine, strings.TrimPrefix(key, uid+"_"))
+		}
+	}
+	sort.Strings(mine)
+	return mine, nil
+}
+
+// --- In-memory ReportRepository ---
+
+type memReportRepo struct {
+	mu      sync.Mutex
+	reports map[string]*model.Report
+}
+
+func newMemReportRepo() *memReportRepo {
+	return &memReportRepo{reports: make(map[string]*model.Report)}
+}
+
+func (r *memReportRepo) GetByID(_ context.Context, reportID string) (*model.Report, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rep, ok := r.reports[reportID]
+	if !ok {
+		return nil, fmt.Errorf("report %s not found", reportID)
+	}
+	copy := *rep
+	return &copy, nil
+}
+
+func (r *memReportRepo) List(_ context.Context, status string, limit int, _ string) ([]*model.Report, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.Report
+	for _, rep := range r.reports {
+		if rep.Status == status {
+			copy := *rep
+			result = append(result, &copy)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (r *memReportRepo) Create(_ context.Context, report *model.Report) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := report.ReporterID + "_" + report.TargetType + "_" + report.TargetID
+	if _, exists := r.reports[id]; exists {
+		return "", fmt.Errorf("report already exists for this %s", report.TargetType)
+	}
+	report.ID = id
+	r.reports[id] = report
+	return id, nil
+}
+
+func (r *memReportRepo) Resolve(_ context.Context, reportID, status, resolvedBy, note string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rep, ok := r.reports[reportID]
+	if !ok {
+		return fmt.Errorf("report %s not found", reportID)
+	}
+	rep.Status = status
+	rep.ResolvedBy = resolvedBy
+	rep.Note = note
+	return nil
+}
+
+// --- In-memory AuditLogger ---
+
+type memAuditLogger struct {
+	mu      sync.Mutex
+	entries []*model.AuditEntry
+}
+
+func newMemAuditLogger() *memAuditLogger {
+	return &memAuditLogger{}
+}
+
+func (r *memAuditLogger) Log(_ context.Context, entry *model.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry.ID = fmt.Sprintf("audit_%03d", len(r.entries)+1)
+	entry.CreatedAt = time.Now()
+	copy := *entry
+	r.entries = append(r.entries, &copy)
+	return nil
+}
+
+func (r *memAuditLogger) List(_ context.Context, actorUID string, limit int, _ string) ([]*model.AuditEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.AuditEntry
+	for i := len(r.entries) - 1; i >= 0 && len(result) < limit; i-- {
+		if actorUID != "" && r.entries[i].ActorUID != actorUID {
+			continue
+		}
+		copy := *r.entries[i]
+		result = append(result, &copy)
+	}
+	return result, nil
+}
+
+// --- In-memory StatsRepository ---
+
+type memStatsRepo struct {
+	stats model.UsageStats
+}
+
+func (r *memStatsRepo) Usage(_ context.Context) (*model.UsageStats, error) {
+	copy := r.stats
+	copy.GeneratedAt = time.Now()
+	return &copy, nil
+}
+
+// RecountUser is not reachable through the API; the in-memory repositories
+// keep their counters exact.
+func (r *memStatsRepo) RecountUser(_ context.Context, uid string, _ bool) (*model.CounterRepair, error) {
+	return nil, fmt.Errorf("recount %s: not supported in memory", uid)
+}
+
+func (r *memStatsRepo) ListUserIDs(context.Context, int, string) ([]string, error) {
+	return nil, nil
+}
+
+// --- In-memory APITokenRepository ---
+
+type memAPITokenRepo struct {
+	mu     sync.Mutex
+	tokens map[string]*model.APIToken
+	nextID int
+}
+
+func newMemAPITokenRepo() *memAPITokenRepo {
+	return &memAPITokenRepo{tokens: make(map[string]*model.APIToken)}
+}
+
+func (r *memAPITokenRepo) GetByID(_ context.Context, tokenID string) (*model.APIToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tok, ok := r.tokens[tokenID]
+	if !ok {
+		return nil, fmt.Errorf("api token %s not found", tokenID)
+	}
+	copy := *tok
+	return &copy, nil
+}
+
+func (r *memAPITokenRepo) GetByHash(_ context.Context, hash string) (*model.APIToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, tok := range r.tokens {
+		if tok.Hash == hash {
+			copy := *tok
+			return &copy, nil
+		}
+	}
+	return nil, fmt.Errorf("api token not found")
+}
+
+func (r *memAPITokenRepo) ListByUser(_ context.Context, uid string) ([]*model.APIToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.APIToken
+	for _, tok := range r.tokens {
+		if tok.UID == uid {
+			copy := *tok
+			result = append(result, &copy)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID > result[j].ID })
+	return result, nil
+}
+
+func (r *memAPITokenRepo) Create(_ context.Context, token *model.APIToken) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	id := fmt.Sprintf("tok%03d", r.nextID)
+	token.ID = id
+	token.CreatedAt = time.Now()
+	copy := *token
+	r.tokens[id] = &copy
+	return id, nil
+}
+
+func (r *memAPITokenRepo) Delete(_ context.Context, tokenID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.tokens, tokenID)
+	return nil
+}
+
+func (r *memAPITokenRepo) TouchLastUsed(_ context.Context, tokenID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if tok, ok := r.tokens[tokenID]; ok {
+		tok.LastUsedAt = &at
+	}
+	return nil
+}
+
+// --- In-memory WebhookRepository ---
+
+type memWebhookRepo struct {
+	mu         sync.Mutex
+	webhooks   map[string]*model.Webhook
+	deliveries map[string][]*model.WebhookDelivery
+	nextID     int
+}
+
+func newMemWebhookRepo() *memWebhookRepo {
+	return &memWebhookRepo{
+		webhooks:   make(map[string]*model.Webhook),
+		deliveries: make(map[string][]*model.WebhookDelivery),
+	}
+}
+
+func (r *memWebhookRepo) GetByID(_ context.Context, webhookID string) (*model.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	webhook, ok := r.webhooks[webhookID]
+	if !ok {
+		return nil, fmt.Errorf("webhook %s not found", webhookID)
+	}
+	copy := *webhook
+	return &copy, nil
+}
+
+func (r *memWebhookRepo) ListByUser(_ context.Context, uid string) ([]*model.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.Webhook
+	for _, webhook := range r.webhooks {
+		if webhook.UID == uid {
+			copy := *webhook
+			result = append(result, &copy)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID > result[j].ID })
+	return result, nil
+}
+
+func (r *memWebhookRepo) Create(_ context.Context, webhook *model.Webhook) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	id := fmt.Sprintf("wh%03d", r.nextID)
+	webhook.ID = id
+	webhook.CreatedAt = time.Now()
+	copy := *webhook
+	r.webhooks[id] = &copy
+	return id, nil
+}
+
+func (r *memWebhookRepo) Delete(_ context.Context, webhookID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.webhooks, webhookID)
+	delete(r.deliveries, webhookID)
+	return nil
+}
+
+func (r *memWebhookRepo) LogDelivery(_ context.Context, webhookID string, delivery *model.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delivery.ID = fmt.Sprintf("d%03d", len(r.deliveries[webhookID])+1)
+	copy := *delivery
+	r.deliveries[webhookID] = append(r.deliveries[webhookID], &copy)
+	return nil
+}
+
+func (r *memWebhookRepo) ListDeliveries(_ context.Context, webhookID string, limit int, _ string) ([]*model.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.WebhookDelivery
+	log := r.deliveries[webhookID]
+	for i := len(log) - 1; i >= 0 && len(result) < limit; i-- {
+		copy := *log[i]
+		result = append(result, &copy)
+	}
+	return result, nil
+}
+
+// --- In-memory UploadSessionRepository ---
+
+type memUploadSessionRepo struct {
+	mu       sync.Mutex
+	sessions map[string]*model.UploadSession
+	nextID   int
+}
+
+func newMemUploadSessionRepo() *memUploadSessionRepo {
+	return &memUploadSessionRepo{sessions: make(map[string]*model.UploadSession)}
+}
+
+func (r *memUploadSessionRepo) Create(_ context.Context, session *model.UploadSession) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	id := fmt.Sprintf("upload_%d", r.nextID)
+	session.ID = id
+	copy := *session
+	r.sessions[id] = &copy
+	return id, nil
+}
+
+func (r *memUploadSessionRepo) GetByID(_ context.Context, uploadID string) (*model.UploadSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sessions[uploadID]
+	if !ok {
+		return nil, fmt.Errorf("upload session %s not found", uploadID)
+	}
+	copy := *s
+	copy.Chunks = append([]model.UploadChunk(nil), s.Chunks...)
+	return &copy, nil
+}
+
+func (r *memUploadSessionRepo) AppendChunk(_ context.Context, uploadID string, chunk model.UploadChunk) (*model.UploadSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sessions[uploadID]
+	if !ok {
+		return nil, fmt.Errorf("upload session %s not found", uploadID)
+	}
+	if s.Status != model.UploadStatusOpen {
+		return nil, fmt.Errorf("invalid upload: session is %s", s.Status)
+	}
+	if chunk.Offset != s.Offset {
+		return nil, fmt.Errorf("invalid range: upload offset is %d", s.Offset)
+	}
+	s.Chunks = append(s.Chunks, chunk)
+	s.Offset += chunk.Size
+	copy := *s
+	copy.Chunks = append([]model.UploadChunk(nil), s.Chunks...)
+	return &copy, nil
+}
+
+func (r *memUploadSessionRepo) MarkFinalizing(_ context.Context, uploadID, jobID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sessions[uploadID]
+	if !ok {
+		return fmt.Errorf("upload session %s not found", uploadID)
+	}
+	if s.Status == model.UploadStatusFinalizing {
+		return nil
+	}
+	if !s.Complete() {
+		return fmt.Errorf("invalid upload: received %d of %d bytes", s.Offset, s.Size)
+	}
+	s.Status = model.UploadStatusFinalizing
+	s.JobID = jobID
+	return nil
+}
+
+func (r *memUploadSessionRepo) Delete(_ context.Context, uploadID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sessions, uploadID)
+	return nil
+}
+
+// --- In-memory StorageClient ---
+
+// memStorageClient keeps object contents in memory. Signed URLs point at a
+// fake bucket.
+type memStorageClient struct {
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func newMemStorageClient() *memStorageClient {
+	return &memStorageClient{data: make(map[string][]byte)}
+}
+
+func (m *memStorageClient) GenerateUploadURL(objectPath string, _ time.Duration) (string, error) {
+	return "https://firebasestorage.googleapis.com/v0/b/test-bucket/o/" + objectPath, nil
+}
+
+func (m *memStorageClient) GenerateDownloadURL(objectPath string, _ time.Duration) (string, error) {
+	return "https://firebasestorage.googleapis.com/v0/b/test-bucket/o/" + objectPath + "?alt=media", nil
+}
+
+func (m *memStorageClient) ObjectExists(_ context.Context, objectPath string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.data[objectPath]
+	return ok, nil
+}
+
+func (m *memStorageClient) ReadObject(_ context.Context, objectPath string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b, ok := m.data[objectPath]
+	if !ok {
+		return nil, fmt.Errorf("object not found: %s", objectPath)
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (m *memStorageClient) ReadObjectFrom(_ context.Context, objectPath string, offset int64) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b, ok := m.data[objectPath]
+	if !ok {
+		return nil, fmt.Errorf("object not found: %s", objectPath)
+	}
+	return io.NopCloser(bytes.NewReader(b[offset:])), nil
+}
+
+func (m *memStorageClient) StatObject(_ context.Context, objectPath string) (*repository.ObjectAttrs, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b, ok := m.data[objectPath]
+	if !ok {
+		return nil, fmt.Errorf("object not found: %s", objectPath)
+	}
+	return &repository.ObjectAttrs{Size: int64(len(b)), Updated: memObjectUpdated}, nil
+}
+
+func (m *memStorageClient) WriteObject(_ context.Context, objectPath string, data io.Reader, _ string) error {
+	b, err := io.ReadAll(data)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[objectPath] = b
+	return nil
+}
+
+func (m *memStorageClient) DeleteObject(_ context.Context, objectPath string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, objectPath)
+	return nil
+}
+
+// --- In-memory identity provider ---
+
+// memIdentity stands in for Firebase Auth. The ID token for an account is
+// "token-" followed by its UID, and the session cookie for a token is
+// "session-" followed by the token.
+type memIdentity struct {
+	mu       sync.Mutex
+	accounts map[string]*model.Account
+}
+
+func newMemIdentity() *memIdentity {
+	return &memIdentity{accounts: make(map[string]*model.Account)}
+}
+
+// add registers an account and returns its ID token.
+func (m *memIdentity) add(account *model.Account) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.accounts[account.UID] = account
+	return "token-" + account.UID
+}
+
+func (m *memIdentity) VerifyIDToken(_ context.Context, idToken string) (*service.UserInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	uid, ok := strings.CutPrefix(idToken, "token-")
+	a, found := m.accounts[uid]
+	if !ok || !found || a.Disabled {
+		return nil, fmt.Errorf("verify id token: invalid token")
+	}
+	return &service.UserInfo{UID: a.UID, Email: a.Email, Roles: slices.Clone(a.Roles)}, nil
+}
+
+func (m *memIdentity) CreateSessionCookie(ctx context.Context, idToken string, _ time.Duration) (string, error) {
+	if _, err := m.VerifyIDToken(ctx, idToken); err != nil {
+		return "", err
+	}
+	return "session-" + idToken, nil
+}
+
+func (m *memIdentity) VerifySessionCookie(ctx context.Context, cookie string) (*service.UserInfo, error) {
+	idToken, ok := strings.CutPrefix(cookie, "session-")
+	if !ok {
+		return nil, fmt.Errorf("verify session cookie: invalid cookie")
+	}
+	return m.VerifyIDToken(ctx, idToken)
+}
+
+func (m *memIdentity) RevokeSessions(_ context.Context, _ string) error {
+	return nil
+}
+
+func (m *memIdentity) GetAccount(_ context.Context, uid string) (*model.Account, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a, ok := m.accounts[uid]
+	if !ok {
+		return nil, fmt.Errorf("account %s not found", uid)
+	}
+	copy := *a
+	return &copy, nil
+}
+
+func (m *memIdentity) GetAccountByEmail(_ context.Context, email string) (*model.Account, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, a := range m.accounts {
+		if strings.EqualFold(a.Email, email) {
+			copy := *a
+			return &copy, nil
+		}
+	}
+	return nil, fmt.Errorf("account for %q not found", email)
+}
+
+func (m *memIdentity) SetAccountDisabled(_ context.Context, uid string, disabled bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a, ok := m.accounts[uid]
+	if !ok {
+		return fmt.Errorf("account %s not found", uid)
+	}
+	a.Disabled = disabled
+	return nil
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/pandasWhoCode/paintbar/internal/config"
+	"github.com/pandasWhoCode/paintbar/internal/metrics"
+	mw "github.com/pandasWhoCode/paintbar/internal/middleware"
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"github.com/pandasWhoCode/paintbar/internal/openapi"
+	"github.com/pandasWhoCode/paintbar/internal/service"
+)
+
+// apiVersionPrefix is where the current API is mounted. The unversioned
+// /api prefix is a deprecated alias for it until apiSunset.
+const apiVersionPrefix = "/api/v1"
+
+var (
+	apiDeprecatedSince = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	apiSunset          = time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC)
+)
+
+// routerServices are the services the router's middleware consults.
+type routerServices struct {
+	auth       identityProvider
+	apiTokens  mw.TokenVerifier
+	moderation mw.SuspensionChecker
+	// spec validates /api/v1 traffic when non-nil
+	spec *openapi.Spec
+	// metrics records requests, rate limiting and uploads
+	metrics *metrics.Metrics
+	// csrfKey keys the CSRF tokens derived from session cookies
+	csrfKey []byte
+}
+
+// newRouter builds the HTTP router: pages, session cookies, health, docs
+// and the API under /api/v1 and its deprecated /api alias.
+func newRouter(cfg *config.Config, logger *slog.Logger, h *handlers, svc *routerServices) http.Handler {
+	// Set up rate limiters (relaxed by default in local env for development)
+	rateLimiter := mw.NewRateLimiter(cfg.RateLimits.Global, cfg.RateLimits.Window)
+	sensitiveLimiter := mw.NewRateLimiter(cfg.RateLimits.Sensitive, cfg.RateLimits.Window)
+	rateLimiter.Instrument("global", svc.metrics)
+	sensitiveLimiter.Instrument("sensitive", svc.metrics)
+
+	r := chi.NewRouter()
+
+	// Global middleware stack (order matters)
+	r.Use(chimiddleware.RequestID)
+	r.Use(chimiddleware.RealIP)
+	r.Use(mw.Tracing())
+	r.Use(mw.Metrics(svc.metrics))
+	r.Use(mw.Recovery(logger))
+	r.Use(mw.SecurityHeaders(cfg.Env))
+	r.Use(mw.RequestLogger(logger))
+	r.Use(mw.Compress(mw.DefaultCompressMinSize))
+	r.Use(mw.AuditContext())
+	r.Use(rateLimiter.Handler())
+
+	// Static files (directory listing disabled)
+	fileServer := http.FileServer(http.Dir("web/static"))
+	r.Handle("/static/*", http.StripPrefix("/static/", noDirListing(fileServer)))
+
+	// Favicon (browsers request /favicon.ico at root)
+	r.Get("/favicon.ico", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "web/static/images/favicon.ico")
+	})
+
+	// Page routes (SSR via Go templates). The session cookie is optional on
+	// public pages and required on the signed-in ones.
+	r.Group(func(r chi.Router) {
+		r.Use(mw.OptionalSession(svc.auth, !cfg.IsLocal()))
+		r.Use(mw.CSRFToken(svc.csrfKey))
+
+		r.Get("/", h.page.Login)
+		r.Get("/login", h.page.Login)
+		r.NotFound(h.page.NotFound)
+
+		r.Group(func(r chi.Router) {
+			r.Use(mw.RequireSession("/login"))
+
+			r.Get("/profile", h.page.Profile)
+			r.Get("/projects", h.page.Projects)
+			r.Get("/canvas", h.page.Canvas)
+		})
+	})
+
+	// Session cookies for the page routes. Only the site's own pages may
+	// sign in or out, which rules out login CSRF.
+	r.Route("/auth", func(r chi.Router) {
+		r.Use(mw.RequireSameOrigin(corsConfig(cfg)))
+		r.With(mw.SensitiveEndpoint(sensitiveLimiter)).Post("/session", h.session.CreateSession)
+		r.Post("/logout", h.session.Logout)
+	})
+
+	// Liveness and readiness probes (no rate limiting, no auth). /health
+	// predates /readyz and answers the same.
+	r.Get("/livez", h.health.Livez)
+	r.Get("/readyz", h.health.Readyz)
+	r.Get("/health", h.health.Readyz)
+
+	// API docs (Swagger UI) — local development only unless enabled
+	if cfg.Features.APIDocs {
+		r.Get("/api/docs", h.docs.ServeUI)
+		r.Get("/api/docs/openapi.yaml", h.docs.ServeSpec)
+		r.Get("/api/docs/init.js", h.docs.ServeInitJS)
+	}
+
+	// API routes with CORS and auth. The spec documents /api/v1, so only
+	// it is validated; the alias serves the same handlers with deprecation
+	// headers pointing clients at their /api/v1 successor.
+	r.Route(apiVersionPrefix, func(r chi.Router) {
+		apiRoutes(r, cfg, h, svc, sensitiveLimiter, svc.spec)
+	})
+	r.Route("/api", func(r chi.Router) {
+		r.Use(mw.Deprecated("/api", apiVersionPrefix, apiDeprecatedSince, apiSunset))
+		apiRoutes(r, cfg, h, svc, sensitiveLimiter, nil)
+	})
+
+	return r
+}
+
+// apiRoutes registers the JSON API on r, which is mounted at /api/v1 and
+// at the deprecated /api alias. Requests are validated against spec, when
+// set, only once they are authenticated, so anonymous callers get a 401
+// rather than the spec's validation details.
+func apiRoutes(r chi.Router, cfg *config.Config, h *handlers, svc *routerServices, sensitiveLimiter *mw.RateLimiter, spec *openapi.Spec) {
+	corsConfig := corsConfig(cfg)
+	r.Use(mw.CORS(corsConfig))
+	r.Use(mw.OptionalSession(svc.auth, !cfg.IsLocal()))
+	r.Use(mw.Auth(mw.ChainVerifiers(svc.apiTokens, svc.auth)))
+	r.Use(mw.CSRF(corsConfig, svc.csrfKey))
+	r.Use(mw.RejectSuspended(svc.moderation))
+	if spec != nil {
+		r.Use(mw.ValidateOpenAPI(spec))
+	}
+
+	r.Get("/ping", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, `{"data":"pong"}`)
+	})
+
+	// Background job status (owner only; any token scope)
+	r.Get("/jobs/{id}", h.job.GetJob)
+
+	// Projects (personal access tokens need the projects scopes)
+	r.Group(func(r chi.Router) {
+		r.Use(mw.RequireScope(model.ScopeProjectsRead, model.ScopeProjectsWrite))
+
+		r.Get("/projects", h.project.ListProjects)
+		r.With(mw.SensitiveEndpoint(sensitiveLimiter)).Post("/projects", h.project.CreateProject)
+		r.Get("/projects/count", h.project.CountProjects)
+		r.Get("/projects/by-title", h.project.GetProjectByTitle)
+		r.Get("/projects/{id}", h.project.GetProject)
+		r.Put("/projects/{id}", h.project.UpdateProject)
+		r.Delete("/projects/{id}", h.project.DeleteProject)
+		r.With(mw.SensitiveEndpoint(sensitiveLimiter)).Post("/projects/{id}/confirm-upload", h.project.ConfirmUpload)
+		r.With(mw.SensitiveEndpoint(sensitiveLimiter), mw.CountUploadBytes(svc.metrics, "blob")).Post("/projects/{id}/upload-blob", h.project.UploadBlob)
+		r.Get("/projects/{id}/blob", h.project.DownloadBlob)
+		r.Get("/projects/{id}/thumbnail", h.project.GetThumbnail)
+
+		// Resumable uploads for large canvases
+		r.With(mw.SensitiveEndpoint(sensitiveLimiter)).Post("/projects/{id}/uploads", h.upload.InitiateUpload)
+		r.Get("/projects/{id}/uploads/{uploadId}", h.upload.GetUpload)
+		r.With(mw.CountUploadBytes(svc.metrics, "chunk")).Put("/projects/{id}/uploads/{uploadId}", h.upload.WriteChunk)
+		r.With(mw.SensitiveEndpoint(sensitiveLimiter)).Post("/projects/{id}/uploads/{uploadId}/finalize", h.upload.FinalizeUpload)
+	})
+
+	// Gallery, comments and reactions (gallery scopes)
+	r.Group(func(r chi.Router) {
+		r.Use(mw.RequireScope(model.ScopeGalleryRead, model.ScopeGalleryWrite))
+
+		r.Get("/gallery", h.gallery.ListItems)
+		r.Post("/gallery", h.gallery.ShareToGallery)
+		r.Get("/gallery/count", h.gallery.CountItems)
+		r.Get("/gallery/{id}", h.gallery.GetItem)
+		r.Get("/gallery/{id}/thumbnail", h.gallery.GetThumbnail)
+		r.Delete("/gallery/{id}", h.gallery.DeleteItem)
+		r.Get("/gallery/{id}/comments", h.comment.ListComments)
+		r.With(mw.SensitiveEndpoint(sensitiveLimiter)).Post("/gallery/{id}/comments", h.comment.CreateComment)
+		r.With(mw.SensitiveEndpoint(sensitiveLimiter)).Put("/gallery/{id}/comments/{commentId}", h.comment.EditComment)
+		r.With(mw.SensitiveEndpoint(sensitiveLimiter)).Delete("/gallery/{id}/comments/{commentId}", h.comment.DeleteComment)
+		r.Get("/gallery/{id}/reactions", h.reaction.GetReactions)
+		r.With(mw.SensitiveEndpoint(sensitiveLimiter)).Post("/gallery/{id}/reactions", h.reaction.AddReaction)
+		r.With(mw.SensitiveEndpoint(sensitiveLimiter)).Delete("/gallery/{id}/reactions/{reaction}", h.reaction.RemoveReaction)
+	})
+
+	// Everything below is off limits to personal access tokens
+	r.Group(func(r chi.Router) {
+		r.Use(mw.DenyAPITokens())
+
+		// Profile
+		r.Get("/profile", h.profile.GetProfile)
+		r.Put("/profile", h.profile.UpdateProfile)
+		r.With(mw.SensitiveEndpoint(sensitiveLimiter)).Post("/claim-username", h.profile.ClaimUsername)
+		r.Get("/account/activity", h.profile.Activity)
+		r.Get("/stats", h.stats.GetStats)
+
+		// Personal access tokens
+		r.Get("/tokens", h.token.ListTokens)
+		r.With(mw.SensitiveEndpoint(sensitiveLimiter)).Post("/tokens", h.token.CreateToken)
+		r.Delete("/tokens/{id}", h.token.RevokeToken)
+
+		// Webhooks
+		r.Get("/webhooks", h.webhook.ListWebhooks)
+		r.With(mw.SensitiveEndpoint(sensitiveLimiter)).Post("/webhooks", h.webhook.CreateWebhook)
+		r.Delete("/webhooks/{id}", h.webhook.DeleteWebhook)
+		r.Get("/webhooks/{id}/deliveries", h.webhook.ListDeliveries)
+		r.With(mw.SensitiveEndpoint(sensitiveLimiter)).Post("/webhooks/{id}/ping", h.webhook.PingWebhook)
+
+		// NFTs
+		r.Get("/nfts", h.nft.ListNFTs)
+		r.Post("/nfts", h.nft.CreateNFT)
+		r.Get("/nfts/count", h.nft.CountNFTs)
+		r.Get("/nfts/{id}", h.nft.GetNFT)
+		r.Get("/nfts/{id}/thumbnail", h.nft.GetThumbnail)
+		r.Delete("/nfts/{id}", h.nft.DeleteNFT)
+
+		// Users & follow graph
+		r.Get("/users/{username}", h.follow.GetPublicProfile)
+		r.With(mw.SensitiveEndpoint(sensitiveLimiter)).Post("/users/{username}/follow", h.follow.Follow)
+		r.With(mw.SensitiveEndpoint(sensitiveLimiter)).Delete("/users/{username}/follow", h.follow.Unfollow)
+		r.Get("/users/{username}/followers", h.follow.ListFollowers)
+		r.Get("/users/{username}/following", h.follow.ListFollowing)
+
+		// Feeds
+		r.Get("/feed/following", h.follow.FollowingFeed)
+
+		// GraphQL (read-only)
+		if cfg.Features.GraphQL {
+			r.Post("/graphql", h.graphql.Query)
+		}
+
+		// Abuse reports
+		r.With(mw.SensitiveEndpoint(sensitiveLimiter)).Post("/reports", h.moderation.CreateReport)
+
+		// Admin API (requires the "admin" role)
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(mw.RequireRole(service.RoleAdmin))
+
+			r.Get("/users", h.admin.LookupUser)
+			r.Put("/users/{uid}/disabled", h.admin.SetUserDisabled)
+			r.Delete("/usernames/{username}", h.admin.ReleaseUsername)
+			r.Get("/stats", h.admin.Stats)
+			r.Get("/audit", h.admin.ListAuditLog)
+
+			r.Get("/reports", h.moderation.ListReports)
+			r.Put("/reports/{id}", h.moderation.ResolveReport)
+			r.Put("/gallery/{id}/hidden", h.moderation.SetGalleryItemHidden)
+			r.Delete("/gallery/{id}", h.moderation.RemoveGalleryItem)
+			r.Put("/gallery/{id}/comments/{commentId}/hidden", h.moderation.SetCommentHidden)
+			r.Delete("/gallery/{id}/comments/{commentId}", h.moderation.RemoveComment)
+			r.Put("/nfts/{id}/hidden", h.moderation.SetNFTHidden)
+			r.Delete("/nfts/{id}", h.moderation.RemoveNFT)
+			r.Put("/users/{uid}/suspended", h.moderation.SetUserSuspended)
+		})
+	})
+}
+
+// corsConfig returns the CORS settings for the environment, with the
+// configured origins replacing the built-in ones when set.
+func corsConfig(cfg *config.Config) mw.CORSConfig {
+	c := mw.DefaultCORSConfig(cfg.Env)
+	if len(cfg.CORS.AllowedOrigins) > 0 {
+		c.AllowedOrigins = cfg.CORS.AllowedOrigins
+	}
+	return c
+}
+
+// noDirListing wraps an http.Handler to return 404 for directory requests,
+// preventing exposure of application file structure.
+func noDirListing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -1,8 +1,19 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/url"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"gopkg.in/yaml.v3"
 )
 
 // Environment constants
@@ -12,52 +23,205 @@ const (
 	EnvProduction = "production"
 )
 
-// Config holds all application configuration loaded from environment variables.
+// Audit log sinks
+const (
+	AuditSinkFirestore = "firestore"
+	AuditSinkJSONL     = "jsonl"
+)
+
+// Trace exporters
+const (
+	TraceExporterNone   = "none"
+	TraceExporterStdout = "stdout"
+	TraceExporterOTLP   = "otlp"
+)
+
+// MinCursorSecretLen is the minimum length of CURSOR_SECRET.
+const MinCursorSecretLen = 32
+
+// Redacted replaces secrets in WriteYAML output.
+const Redacted = "[redacted]"
+
+// Config holds all application configuration. Each setting comes from, in
+// order of precedence: its environment variable, the config file named by
+// CONFIG_FILE, or the default for the environment. A file key is its
+// variable in lower case (PORT is port), and the sections below nest under
+// their own key (RATE_LIMIT_GLOBAL is rate_limits.global).
 type Config struct {
 	// Environment: local, preview, production
-	Env string
+	Env string `yaml:"env" toml:"env"`
 
 	// HTTP server port
-	Port string
+	Port string `yaml:"port" toml:"port"`
+
+	// Port for the Prometheus /metrics endpoint, kept off the public port so
+	// only in-cluster scrapers reach it; empty disables it.
+	MetricsPort string `yaml:"metrics_port" toml:"metrics_port"`
 
 	// Firebase
-	FirebaseProjectID          string
-	FirebaseServiceAccountPath string
+	FirebaseProjectID          string `yaml:"firebase_project_id" toml:"firebase_project_id"`
+	FirebaseServiceAccountPath string `yaml:"firebase_service_account_path" toml:"firebase_service_account_path"`
 
 	// Firestore emulator (local only, set automatically)
-	FirestoreEmulatorHost string
+	FirestoreEmulatorHost string `yaml:"firestore_emulator_host" toml:"firestore_emulator_host"`
 
 	// Firebase Auth emulator (local only, set automatically)
-	FirebaseAuthEmulatorHost string
+	FirebaseAuthEmulatorHost string `yaml:"firebase_auth_emulator_host" toml:"firebase_auth_emulator_host"`
 
 	// Firebase Storage bucket name
-	FirebaseStorageBucket string
+	FirebaseStorageBucket string `yaml:"firebase_storage_bucket" toml:"firebase_storage_bucket"`
 
 	// Firebase Storage emulator host (local only, set automatically)
-	FirebaseStorageEmulatorHost string
+	FirebaseStorageEmulatorHost string `yaml:"firebase_storage_emulator_host" toml:"firebase_storage_emulator_host"`
 
 	// Hiero network configuration
-	HieroNetwork     string // local, testnet, mainnet
-	HieroOperatorID  string
-	HieroOperatorKey string
+	HieroNetwork     string `yaml:"hiero_network" toml:"hiero_network"` // local, testnet, mainnet
+	HieroOperatorID  string `yaml:"hiero_operator_id" toml:"hiero_operator_id"`
+	HieroOperatorKey string `yaml:"hiero_operator_key" toml:"hiero_operator_key"` // secret
+
+	// Audit log sink: firestore (default) or jsonl (local only)
+	AuditLogSink string `yaml:"audit_log_sink" toml:"audit_log_sink"`
+	AuditLogPath string `yaml:"audit_log_path" toml:"audit_log_path"` // JSONL file path when AuditLogSink is jsonl
+
+	// Background job workers on this instance; 0 disables job processing
+	// (jobs can still be enqueued and are picked up by other instances).
+	JobWorkers int `yaml:"job_workers" toml:"job_workers"`
+
+	// How long shutdown keeps serving after /readyz starts failing, so load
+	// balancers stop routing here before the listener closes. Defaults to
+	// 5s, or 0 locally.
+	DrainDelay time.Duration `yaml:"drain_delay" toml:"drain_delay"`
+
+	// Trace exporter: none (default), stdout, or otlp (configured by the
+	// standard OTEL_EXPORTER_OTLP_* variables)
+	TraceExporter string `yaml:"trace_exporter" toml:"trace_exporter"`
+
+	// CursorSecret keys the HMAC on pagination cursors and, through a derived
+	// key, CSRF tokens. Every instance must share it; when empty locally, a
+	// random per-process key is used.
+	CursorSecret string `yaml:"cursor_secret" toml:"cursor_secret"` // secret
+
+	RateLimits RateLimits `yaml:"rate_limits" toml:"rate_limits"`
+	CORS       CORS       `yaml:"cors" toml:"cors"`
+	Quotas     Quotas     `yaml:"quotas" toml:"quotas"`
+	Limits     Limits     `yaml:"limits" toml:"limits"`
+	Features   Features   `yaml:"features" toml:"features"`
 }
 
-// Load reads configuration from environment variables and validates it.
-func Load() (*Config, error) {
+// RateLimits are the per-IP request budgets.
+type RateLimits struct {
+	// Requests per Window to any route (RATE_LIMIT_GLOBAL)
+	Global int `yaml:"global" toml:"global"`
+	// Requests per Window to sign-in and upload routes, on top of the
+	// global limit (RATE_LIMIT_SENSITIVE)
+	Sensitive int `yaml:"sensitive" toml:"sensitive"`
+	// Window the budgets refill over (RATE_LIMIT_WINDOW)
+	Window time.Duration `yaml:"window" toml:"window"`
+}
+
+// CORS configures cross-origin access to the API.
+type CORS struct {
+	// Origins allowed to call the API from a browser, replacing the
+	// built-in list for the environment when set (CORS_ALLOWED_ORIGINS,
+	// comma-separated)
+	AllowedOrigins []string `yaml:"allowed_origins" toml:"allowed_origins"`
+}
+
+// Quotas cap what a single user may create.
+type Quotas struct {
+	APITokensPerUser int `yaml:"api_tokens_per_user" toml:"api_tokens_per_user"` // QUOTA_API_TOKENS_PER_USER
+	WebhooksPerUser  int `yaml:"webhooks_per_user" toml:"webhooks_per_user"`     // QUOTA_WEBHOOKS_PER_USER
+}
+
+// Limits bound the work a single request may ask for.
+type Limits struct {
+	GraphQLMaxDepth      int `yaml:"graphql_max_depth" toml:"graphql_max_depth"`           // LIMIT_GRAPHQL_MAX_DEPTH
+	GraphQLMaxComplexity int `yaml:"graphql_max_complexity" toml:"graphql_max_complexity"` // LIMIT_GRAPHQL_MAX_COMPLEXITY
+}
+
+// Features switch optional endpoints on and off.
+type Features struct {
+	// POST /api/v1/graphql (FEATURE_GRAPHQL)
+	GraphQL bool `yaml:"graphql" toml:"graphql"`
+	// Swagger UI at /api/docs; on locally by default (FEATURE_API_DOCS)
+	APIDocs bool `yaml:"api_docs" toml:"api_docs"`
+}
+
+// Defaults returns the configuration used for env when nothing overrides it.
+func Defaults(env string) *Config {
 	cfg := &Config{
-		Env:                         getEnv("ENV", EnvLocal),
-		Port:                        getEnv("PORT", "8080"),
-		FirebaseProjectID:           getEnv("FIREBASE_PROJECT_ID", "paintbar-7f887"),
-		FirebaseServiceAccountPath:  getEnv("FIREBASE_SERVICE_ACCOUNT_PATH", ""),
-		FirestoreEmulatorHost:       getEnv("FIRESTORE_EMULATOR_HOST", ""),
-		FirebaseAuthEmulatorHost:    getEnv("FIREBASE_AUTH_EMULATOR_HOST", ""),
-		FirebaseStorageBucket:       getEnv("FIREBASE_STORAGE_BUCKET", "paintbar-7f887.firebasestorage.app"),
-		FirebaseStorageEmulatorHost: getEnv("FIREBASE_STORAGE_EMULATOR_HOST", ""),
-		HieroNetwork:                getEnv("HIERO_NETWORK", "local"),
-		HieroOperatorID:             getEnv("HIERO_OPERATOR_ID", ""),
-		HieroOperatorKey:            getEnv("HIERO_OPERATOR_KEY", ""),
+		Env:                   env,
+		Port:                  "8080",
+		MetricsPort:           "9090",
+		FirebaseProjectID:     "paintbar-7f887",
+		FirebaseStorageBucket: "paintbar-7f887.firebasestorage.app",
+		HieroNetwork:          "local",
+		AuditLogSink:          AuditSinkFirestore,
+		AuditLogPath:          "audit.jsonl",
+		JobWorkers:            4,
+		DrainDelay:            5 * time.Second,
+		TraceExporter:         TraceExporterNone,
+		RateLimits: RateLimits{
+			Global:    100,
+			Sensitive: 20,
+			Window:    time.Minute,
+		},
+		Quotas: Quotas{
+			APITokensPerUser: model.MaxAPITokensPerUser,
+			WebhooksPerUser:  model.MaxWebhooksPerUser,
+		},
+		Limits: Limits{
+			GraphQLMaxDepth:      10,
+			GraphQLMaxComplexity: 1000,
+		},
+		Features: Features{
+			GraphQL: true,
+		},
 	}
 
+	// Relaxed for development
+	if env == EnvLocal {
+		cfg.DrainDelay = 0
+		cfg.RateLimits.Sensitive = 60
+		cfg.Features.APIDocs = true
+	}
+	return cfg
+}
+
+// Load reads configuration from the file named by CONFIG_FILE, if any, and
+// the environment, and validates it.
+func Load() (*Config, error) {
+	return LoadFile(os.Getenv("CONFIG_FILE"))
+}
+
+// LoadFile is Load with the config file at path; an empty path reads only
+// the environment. The file is YAML, or TOML when path ends in .toml.
+func LoadFile(path string) (*Config, error) {
+	var data []byte
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("read config file: %w", err)
+		}
+	}
+
+	// The environment picks the defaults, so it is read before the rest
+	env, err := fileEnv(path, data)
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	env = getEnv("ENV", env)
+
+	cfg := Defaults(env)
+	if err := decodeFile(path, data, cfg); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	// Problems are collected rather than returned one at a time, so a
+	// broken deployment is fixed in one pass
+	errs := cfg.applyEnv()
+	errs = append(errs, cfg.readSecretFiles()...)
+
 	// Auto-configure emulator hosts for local environment
 	if cfg.Env == EnvLocal {
 		if cfg.FirestoreEmulatorHost == "" {
@@ -74,35 +238,281 @@ func Load() (*Config, error) {
 		}
 	}
 
-	if err := cfg.validate(); err != nil {
+	if err := errors.Join(append(errs, cfg.validate()...)...); err != nil {
 		return nil, fmt.Errorf("config validation: %w", err)
 	}
 
 	return cfg, nil
 }
 
-// validate checks that required fields are set for the current environment.
-func (c *Config) validate() error {
+// applyEnv overrides c with the environment variables that are set. A
+// variable set to the empty string counts as set.
+func (c *Config) applyEnv() []error {
+	var errs []error
+	str := func(key string, dst *string) {
+		if v, ok := os.LookupEnv(key); ok {
+			*dst = v
+		}
+	}
+	num := func(key string, dst *int) {
+		if v, ok := os.LookupEnv(key); ok {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s: %w", key, err))
+				return
+			}
+			*dst = n
+		}
+	}
+	duration := func(key string, dst *time.Duration) {
+		if v, ok := os.LookupEnv(key); ok {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s: %w", key, err))
+				return
+			}
+			*dst = d
+		}
+	}
+	flag := func(key string, dst *bool) {
+		if v, ok := os.LookupEnv(key); ok {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s: %w", key, err))
+				return
+			}
+			*dst = b
+		}
+	}
+
+	str("ENV", &c.Env)
+	str("PORT", &c.Port)
+	str("METRICS_PORT", &c.MetricsPort)
+	str("FIREBASE_PROJECT_ID", &c.FirebaseProjectID)
+	str("FIREBASE_SERVICE_ACCOUNT_PATH", &c.FirebaseServiceAccountPath)
+	str("FIRESTORE_EMULATOR_HOST", &c.FirestoreEmulatorHost)
+	str("FIREBASE_AUTH_EMULATOR_HOST", &c.FirebaseAuthEmulatorHost)
+	str("FIREBASE_STORAGE_BUCKET", &c.FirebaseStorageBucket)
+	str("FIREBASE_STORAGE_EMULATOR_HOST", &c.FirebaseStorageEmulatorHost)
+	str("HIERO_NETWORK", &c.HieroNetwork)
+	str("HIERO_OPERATOR_ID", &c.HieroOperatorID)
+	str("HIERO_OPERATOR_KEY", &c.HieroOperatorKey)
+	str("AUDIT_LOG_SINK", &c.AuditLogSink)
+	str("AUDIT_LOG_PATH", &c.AuditLogPath)
+	num("JOB_WORKERS", &c.JobWorkers)
+	duration("DRAIN_DELAY", &c.DrainDelay)
+	str("TRACE_EXPORTER", &c.TraceExporter)
+	str("CURSOR_SECRET", &c.CursorSecret)
+
+	num("RATE_LIMIT_GLOBAL", &c.RateLimits.Global)
+	num("RATE_LIMIT_SENSITIVE", &c.RateLimits.Sensitive)
+	duration("RATE_LIMIT_WINDOW", &c.RateLimits.Window)
+	if v, ok := os.LookupEnv("CORS_ALLOWED_ORIGINS"); ok {
+		c.CORS.AllowedOrigins = splitList(v)
+	}
+	num("QUOTA_API_TOKENS_PER_USER", &c.Quotas.APITokensPerUser)
+	num("QUOTA_WEBHOOKS_PER_USER", &c.Quotas.WebhooksPerUser)
+	num("LIMIT_GRAPHQL_MAX_DEPTH", &c.Limits.GraphQLMaxDepth)
+	num("LIMIT_GRAPHQL_MAX_COMPLEXITY", &c.Limits.GraphQLMaxComplexity)
+	flag("FEATURE_GRAPHQL", &c.Features.GraphQL)
+	flag("FEATURE_API_DOCS", &c.Features.APIDocs)
+
+	return errs
+}
+
+// secret is a setting that must not appear in logs or --print-config.
+type secret struct {
+	key   string // environment variable
+	value *string
+}
+
+func (c *Config) secrets() []secret {
+	return []secret{
+		{"HIERO_OPERATOR_KEY", &c.HieroOperatorKey},
+		{"CURSOR_SECRET", &c.CursorSecret},
+	}
+}
+
+// readSecretFiles sets each secret named by a <KEY>_FILE variable from that
+// file, so keys can be mounted rather than passed in the environment. A
+// secret can never be empty, so an empty <KEY> or <KEY>_FILE, as a blank
+// .env line exports, does not count as set here.
+func (c *Config) readSecretFiles() []error {
+	var errs []error
+	for _, s := range c.secrets() {
+		path := os.Getenv(s.key + "_FILE")
+		if path == "" {
+			continue
+		}
+		if os.Getenv(s.key) != "" {
+			errs = append(errs, fmt.Errorf("set %s or %s_FILE, not both", s.key, s.key))
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("read %s_FILE: %w", s.key, err))
+			continue
+		}
+		// Trailing newlines from editors and `echo` are not part of the key
+		value := strings.TrimSpace(string(data))
+		if value == "" {
+			errs = append(errs, fmt.Errorf("%s_FILE %s is empty", s.key, path))
+			continue
+		}
+		*s.value = value
+	}
+	return errs
+}
+
+var (
+	// Firebase project IDs: 6-30 lowercase letters, digits and hyphens,
+	// starting with a letter
+	projectIDPattern = regexp.MustCompile(`^[a-z][a-z0-9-]{4,28}[a-z0-9]$`)
+	// Cloud Storage bucket names: lowercase letters, digits, dots, hyphens
+	// and underscores, starting and ending with a letter or digit
+	bucketPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{1,220}[a-z0-9]$`)
+	// Hiero account IDs: shard.realm.num
+	accountIDPattern = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+	hexPattern       = regexp.MustCompile(`^[0-9a-f]+$`)
+)
+
+// validate checks every setting and returns all the problems found.
+func (c *Config) validate() []error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
 	validEnvs := map[string]bool{EnvLocal: true, EnvPreview: true, EnvProduction: true}
 	if !validEnvs[c.Env] {
-		return fmt.Errorf("invalid ENV %q, must be one of: local, preview, production", c.Env)
+		fail("invalid ENV %q, must be one of: local, preview, production", c.Env)
 	}
 
 	if c.Port == "" {
-		return fmt.Errorf("PORT is required")
+		fail("PORT is required")
+	} else if !validPort(c.Port) {
+		fail("invalid PORT %q, must be a port number", c.Port)
+	}
+
+	if c.MetricsPort != "" {
+		if !validPort(c.MetricsPort) {
+			fail("invalid METRICS_PORT %q, must be a port number", c.MetricsPort)
+		} else if c.MetricsPort == c.Port {
+			fail("METRICS_PORT must differ from PORT")
+		}
 	}
 
 	if c.FirebaseProjectID == "" {
-		return fmt.Errorf("FIREBASE_PROJECT_ID is required")
+		fail("FIREBASE_PROJECT_ID is required")
+	} else if !projectIDPattern.MatchString(c.FirebaseProjectID) {
+		fail("invalid FIREBASE_PROJECT_ID %q", c.FirebaseProjectID)
 	}
 
 	// Service account is optional — Cloud Run uses ADC (Application Default
 	// Credentials) in both preview and production environments.
 
+	if c.FirebaseStorageBucket == "" {
+		fail("FIREBASE_STORAGE_BUCKET is required")
+	} else if !bucketPattern.MatchString(c.FirebaseStorageBucket) || strings.Contains(c.FirebaseStorageBucket, "..") {
+		fail("invalid FIREBASE_STORAGE_BUCKET %q, must be a Cloud Storage bucket name (e.g. my-project.firebasestorage.app)", c.FirebaseStorageBucket)
+	}
+
+	for key, host := range map[string]string{
+		"FIRESTORE_EMULATOR_HOST":        c.FirestoreEmulatorHost,
+		"FIREBASE_AUTH_EMULATOR_HOST":    c.FirebaseAuthEmulatorHost,
+		"FIREBASE_STORAGE_EMULATOR_HOST": c.FirebaseStorageEmulatorHost,
+	} {
+		if host == "" {
+			continue
+		}
+		if h, port, err := net.SplitHostPort(host); err != nil || h == "" || !validPort(port) {
+			fail("invalid %s %q, must be host:port", key, host)
+		}
+	}
+
+	switch c.HieroNetwork {
+	case "local", "testnet", "mainnet":
+	default:
+		fail("invalid HIERO_NETWORK %q, must be one of: local, testnet, mainnet", c.HieroNetwork)
+	}
+	if c.HieroOperatorID != "" && !accountIDPattern.MatchString(c.HieroOperatorID) {
+		fail("invalid HIERO_OPERATOR_ID %q, must be an account ID like 0.0.1234", c.HieroOperatorID)
+	}
+	// The key is never echoed back
+	if c.HieroOperatorKey != "" && !validHieroKey(c.HieroOperatorKey) {
+		fail("invalid HIERO_OPERATOR_KEY, must be a hex-encoded ED25519 or ECDSA (secp256k1) private key, raw or DER")
+	}
+	if (c.HieroOperatorID == "") != (c.HieroOperatorKey == "") {
+		fail("HIERO_OPERATOR_ID and HIERO_OPERATOR_KEY must be set together")
+	}
+
 	// Hiero operator credentials are not yet required — tokenization is not
 	// implemented. This check will be re-enabled when NFT minting goes live.
 
-	return nil
+	switch c.AuditLogSink {
+	case AuditSinkFirestore:
+	case AuditSinkJSONL:
+		// A local file does not survive Cloud Run instance restarts.
+		if c.Env != EnvLocal {
+			fail("AUDIT_LOG_SINK=jsonl is only supported when ENV=local")
+		}
+		if c.AuditLogPath == "" {
+			fail("AUDIT_LOG_PATH is required when AUDIT_LOG_SINK=jsonl")
+		}
+	default:
+		fail("invalid AUDIT_LOG_SINK %q, must be one of: firestore, jsonl", c.AuditLogSink)
+	}
+
+	switch c.TraceExporter {
+	case TraceExporterNone, TraceExporterStdout, TraceExporterOTLP:
+	default:
+		fail("invalid TRACE_EXPORTER %q, must be one of: none, stdout, otlp", c.TraceExporter)
+	}
+
+	if c.JobWorkers < 0 {
+		fail("JOB_WORKERS must be 0 or more")
+	}
+
+	if c.DrainDelay < 0 {
+		fail("DRAIN_DELAY must be 0 or more")
+	}
+
+	// A per-process cursor key would break pagination across instances
+	if (c.Env != EnvLocal || c.CursorSecret != "") && len(c.CursorSecret) < MinCursorSecretLen {
+		fail("CURSOR_SECRET must be at least %d characters (required unless ENV=local)", MinCursorSecretLen)
+	}
+
+	if c.RateLimits.Global <= 0 {
+		fail("RATE_LIMIT_GLOBAL must be at least 1")
+	}
+	if c.RateLimits.Sensitive <= 0 {
+		fail("RATE_LIMIT_SENSITIVE must be at least 1")
+	}
+	if c.RateLimits.Window <= 0 {
+		fail("RATE_LIMIT_WINDOW must be positive")
+	}
+
+	for _, origin := range c.CORS.AllowedOrigins {
+		if err := validateOrigin(origin); err != nil {
+			fail("invalid CORS_ALLOWED_ORIGINS entry %q: %v", origin, err)
+		}
+	}
+
+	if c.Quotas.APITokensPerUser <= 0 {
+		fail("QUOTA_API_TOKENS_PER_USER must be at least 1")
+	}
+	if c.Quotas.WebhooksPerUser <= 0 {
+		fail("QUOTA_WEBHOOKS_PER_USER must be at least 1")
+	}
+
+	if c.Limits.GraphQLMaxDepth <= 0 {
+		fail("LIMIT_GRAPHQL_MAX_DEPTH must be at least 1")
+	}
+	if c.Limits.GraphQLMaxComplexity <= 0 {
+		fail("LIMIT_GRAPHQL_MAX_COMPLEXITY must be at least 1")
+	}
+
+	return errs
 }
 
 // IsLocal returns true if running in local development mode.
@@ -115,9 +525,86 @@ func (c *Config) IsProduction() bool {
 	return c.Env == EnvProduction
 }
 
+// WriteYAML writes c in the config file format with secrets redacted, for
+// --print-config.
+func (c *Config) WriteYAML(w io.Writer) error {
+	redacted := *c
+	for _, s := range redacted.secrets() {
+		if *s.value != "" {
+			*s.value = Redacted
+		}
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&redacted); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
 func getEnv(key, fallback string) string {
 	if val, ok := os.LookupEnv(key); ok {
 		return val
 	}
 	return fallback
 }
+
+// splitList splits a comma-separated value, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func validPort(s string) bool {
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 1 && n <= 65535
+}
+
+// validateOrigin checks that origin is a scheme and host with no path, as
+// browsers send it in the Origin header.
+func validateOrigin(origin string) error {
+	if origin == "*" {
+		return errors.New("wildcards are not allowed, since the API accepts credentials")
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("scheme must be http or https")
+	}
+	if u.Host == "" || u.User != nil || u.Path != "" || u.RawQuery != "" || u.Fragment != "" {
+		return errors.New("must be scheme://host[:port] with no path")
+	}
+	return nil
+}
+
+// hieroKeyDERPrefixes are the DER headers the Hiero SDKs write before a
+// 32-byte private key, as hex.
+var hieroKeyDERPrefixes = []string{
+	"302e020100300506032b657004220420",     // ED25519
+	"3030020100300706052b8104000a04220420", // ECDSA secp256k1
+}
+
+// validHieroKey reports whether key is a hex private key as the Hiero SDKs
+// export it: 32 raw bytes or a DER-encoded ED25519 or ECDSA key.
+func validHieroKey(key string) bool {
+	key = strings.ToLower(strings.TrimPrefix(key, "0x"))
+	if !hexPattern.MatchString(key) {
+		return false
+	}
+	if len(key) == 64 {
+		return true
+	}
+	for _, prefix := range hieroKeyDERPrefixes {
+		if len(key) == len(prefix)+64 && strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,12 +2,17 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// testCursorSecret satisfies the CURSOR_SECRET requirement outside local.
+const testCursorSecret = "0123456789abcdef0123456789abcdef"
+
 func TestLoad_Defaults(t *testing.T) {
 	// Clear env to test defaults
 	os.Unsetenv("ENV")
@@ -46,6 +51,7 @@ func TestLoad_InvalidEnv(t *testing.T) {
 }
 
 func TestLoad_ProductionAllowsADC(t *testing.T) {
+	t.Setenv("CURSOR_SECRET", testCursorSecret)
 	os.Setenv("ENV", "production")
 	os.Setenv("FIREBASE_SERVICE_ACCOUNT_PATH", "")
 	defer func() {
@@ -60,6 +66,7 @@ func TestLoad_ProductionAllowsADC(t *testing.T) {
 }
 
 func TestLoad_ProductionDoesNotRequireHieroCredentials(t *testing.T) {
+	t.Setenv("CURSOR_SECRET", testCursorSecret)
 	os.Setenv("ENV", "production")
 	os.Setenv("HIERO_OPERATOR_ID", "")
 	os.Setenv("HIERO_OPERATOR_KEY", "")
@@ -92,6 +99,22 @@ func TestLoad_EmptyPort(t *testing.T) {
 	assert.Contains(t, err.Error(), "PORT is required")
 }
 
+func TestLoad_MetricsPort(t *testing.T) {
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "9090", cfg.MetricsPort)
+
+	t.Setenv("METRICS_PORT", "")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.MetricsPort, "an empty METRICS_PORT disables metrics")
+
+	t.Setenv("METRICS_PORT", "8080")
+	_, err = Load()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "METRICS_PORT must differ from PORT")
+}
+
 func TestLoad_EmptyFirebaseProjectID(t *testing.T) {
 	os.Setenv("FIREBASE_PROJECT_ID", "")
 	defer os.Unsetenv("FIREBASE_PROJECT_ID")
@@ -102,6 +125,7 @@ func TestLoad_EmptyFirebaseProjectID(t *testing.T) {
 }
 
 func TestLoad_PreviewUsesADC(t *testing.T) {
+	t.Setenv("CURSOR_SECRET", testCursorSecret)
 	os.Setenv("ENV", "preview")
 	os.Setenv("FIREBASE_SERVICE_ACCOUNT_PATH", "")
 	defer func() {
@@ -145,6 +169,7 @@ func TestLoad_LocalWithEmptyHieroNetwork(t *testing.T) {
 }
 
 func TestLoad_ProductionValid(t *testing.T) {
+	t.Setenv("CURSOR_SECRET", testCursorSecret)
 	os.Setenv("ENV", "production")
 	defer os.Unsetenv("ENV")
 
@@ -153,3 +178,250 @@ func TestLoad_ProductionValid(t *testing.T) {
 	assert.True(t, cfg.IsProduction())
 	assert.False(t, cfg.IsLocal())
 }
+
+func TestLoad_AuditLogDefaultsToFirestore(t *testing.T) {
+	os.Unsetenv("AUDIT_LOG_SINK")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, AuditSinkFirestore, cfg.AuditLogSink)
+}
+
+func TestLoad_AuditLogJSONLLocal(t *testing.T) {
+	os.Setenv("AUDIT_LOG_SINK", "jsonl")
+	os.Setenv("AUDIT_LOG_PATH", "/tmp/paintbar-audit.jsonl")
+	defer func() {
+		os.Unsetenv("AUDIT_LOG_SINK")
+		os.Unsetenv("AUDIT_LOG_PATH")
+	}()
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, AuditSinkJSONL, cfg.AuditLogSink)
+	assert.Equal(t, "/tmp/paintbar-audit.jsonl", cfg.AuditLogPath)
+}
+
+func TestLoad_AuditLogJSONLRejectedOutsideLocal(t *testing.T) {
+	os.Setenv("ENV", "production")
+	os.Setenv("AUDIT_LOG_SINK", "jsonl")
+	defer func() {
+		os.Unsetenv("ENV")
+		os.Unsetenv("AUDIT_LOG_SINK")
+	}()
+
+	_, err := Load()
+	assert.ErrorContains(t, err, "only supported when ENV=local")
+}
+
+func TestLoad_InvalidAuditLogSink(t *testing.T) {
+	os.Setenv("AUDIT_LOG_SINK", "bigquery")
+	defer os.Unsetenv("AUDIT_LOG_SINK")
+
+	_, err := Load()
+	assert.ErrorContains(t, err, "invalid AUDIT_LOG_SINK")
+}
+
+func TestLoad_JobWorkers(t *testing.T) {
+	os.Unsetenv("JOB_WORKERS")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 4, cfg.JobWorkers)
+
+	os.Setenv("JOB_WORKERS", "0")
+	defer os.Unsetenv("JOB_WORKERS")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, 0, cfg.JobWorkers)
+
+	os.Setenv("JOB_WORKERS", "-1")
+	_, err = Load()
+	assert.ErrorContains(t, err, "JOB_WORKERS must be")
+
+	os.Setenv("JOB_WORKERS", "many")
+	_, err = Load()
+	assert.ErrorContains(t, err, "invalid JOB_WORKERS")
+}
+
+func TestLoad_DrainDelay(t *testing.T) {
+	os.Unsetenv("DRAIN_DELAY")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Zero(t, cfg.DrainDelay, "no delay locally")
+
+	t.Setenv("ENV", "production")
+	t.Setenv("CURSOR_SECRET", testCursorSecret)
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, cfg.DrainDelay)
+
+	t.Setenv("DRAIN_DELAY", "250ms")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, 250*time.Millisecond, cfg.DrainDelay)
+
+	t.Setenv("DRAIN_DELAY", "-1s")
+	_, err = Load()
+	assert.ErrorContains(t, err, "DRAIN_DELAY must be")
+
+	t.Setenv("DRAIN_DELAY", "soon")
+	_, err = Load()
+	assert.ErrorContains(t, err, "invalid DRAIN_DELAY")
+}
+
+func TestLoad_CursorSecret(t *testing.T) {
+	os.Unsetenv("CURSOR_SECRET")
+	cfg, err := Load()
+	require.NoError(t, err, "optional locally")
+	assert.Empty(t, cfg.CursorSecret)
+
+	t.Setenv("ENV", "production")
+	_, err = Load()
+	assert.ErrorContains(t, err, "CURSOR_SECRET must be at least 32 characters")
+
+	t.Setenv("CURSOR_SECRET", "short")
+	_, err = Load()
+	assert.ErrorContains(t, err, "CURSOR_SECRET")
+
+	t.Setenv("CURSOR_SECRET", testCursorSecret)
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, testCursorSecret, cfg.CursorSecret)
+
+	t.Setenv("ENV", "local")
+	t.Setenv("CURSOR_SECRET", "short")
+	_, err = Load()
+	assert.ErrorContains(t, err, "CURSOR_SECRET", "a weak key is rejected even locally")
+}
+
+// testHieroKey is a well-formed raw 32-byte private key.
+const testHieroKey = "a8d6c1f2e3b4a5968778695a4b3c2d1e0f1e2d3c4b5a69788796a5b4c3d2e1f0"
+
+func TestLoad_SecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cursor_secret")
+	require.NoError(t, os.WriteFile(path, []byte(testCursorSecret+"\n"), 0o600))
+	os.Unsetenv("CURSOR_SECRET")
+	t.Setenv("CURSOR_SECRET_FILE", path)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, testCursorSecret, cfg.CursorSecret, "trailing newline trimmed")
+
+	t.Setenv("CURSOR_SECRET", testCursorSecret)
+	_, err = Load()
+	assert.ErrorContains(t, err, "set CURSOR_SECRET or CURSOR_SECRET_FILE, not both")
+	os.Unsetenv("CURSOR_SECRET")
+
+	empty := filepath.Join(dir, "empty")
+	require.NoError(t, os.WriteFile(empty, []byte("\n"), 0o600))
+	t.Setenv("CURSOR_SECRET_FILE", empty)
+	_, err = Load()
+	assert.ErrorContains(t, err, "is empty")
+
+	t.Setenv("CURSOR_SECRET_FILE", filepath.Join(dir, "missing"))
+	_, err = Load()
+	assert.ErrorContains(t, err, "read CURSOR_SECRET_FILE")
+}
+
+func TestLoad_AggregatesErrors(t *testing.T) {
+	t.Setenv("PORT", "http")
+	t.Setenv("FIREBASE_STORAGE_BUCKET", "Not A Bucket")
+	t.Setenv("RATE_LIMIT_GLOBAL", "lots")
+	t.Setenv("QUOTA_WEBHOOKS_PER_USER", "0")
+
+	_, err := Load()
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "invalid PORT")
+	assert.ErrorContains(t, err, "invalid FIREBASE_STORAGE_BUCKET")
+	assert.ErrorContains(t, err, "invalid RATE_LIMIT_GLOBAL")
+	assert.ErrorContains(t, err, "QUOTA_WEBHOOKS_PER_USER must be")
+}
+
+func TestLoad_StorageBucket(t *testing.T) {
+	for _, bucket := range []string{"paintbar-7f887.appspot.com", "my_bucket-1"} {
+		t.Setenv("FIREBASE_STORAGE_BUCKET", bucket)
+		_, err := Load()
+		assert.NoError(t, err, bucket)
+	}
+	for _, bucket := range []string{"", "gs://paintbar", "a..b", "-bucket", "UPPER"} {
+		t.Setenv("FIREBASE_STORAGE_BUCKET", bucket)
+		_, err := Load()
+		assert.ErrorContains(t, err, "FIREBASE_STORAGE_BUCKET", bucket)
+	}
+}
+
+func TestLoad_HieroOperator(t *testing.T) {
+	tests := []struct {
+		name    string
+		id, key string
+		wantErr string
+	}{
+		{"raw key", "0.0.1234", testHieroKey, ""},
+		{"0x raw key", "0.0.1234", "0x" + testHieroKey, ""},
+		{"DER ED25519", "0.0.1234", "302e020100300506032b657004220420" + testHieroKey, ""},
+		{"DER ECDSA", "0.0.1234", "3030020100300706052b8104000a04220420" + testHieroKey, ""},
+		{"short key", "0.0.1234", testHieroKey[:60], "invalid HIERO_OPERATOR_KEY"},
+		{"not hex", "0.0.1234", "zz" + testHieroKey[2:], "invalid HIERO_OPERATOR_KEY"},
+		{"bad ID", "1234", testHieroKey, "invalid HIERO_OPERATOR_ID"},
+		{"key without ID", "", testHieroKey, "must be set together"},
+		{"ID without key", "0.0.1234", "", "must be set together"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HIERO_OPERATOR_ID", tt.id)
+			t.Setenv("HIERO_OPERATOR_KEY", tt.key)
+			_, err := Load()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+			if tt.key != "" {
+				assert.NotContains(t, err.Error(), tt.key, "the key is never echoed")
+			}
+		})
+	}
+}
+
+func TestLoad_CORSOrigins(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://paintbar.example, http://localhost:5173")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://paintbar.example", "http://localhost:5173"}, cfg.CORS.AllowedOrigins)
+
+	for _, origin := range []string{"*", "paintbar.example", "ftp://paintbar.example", "https://paintbar.example/app"} {
+		t.Setenv("CORS_ALLOWED_ORIGINS", origin)
+		_, err := Load()
+		assert.ErrorContains(t, err, "invalid CORS_ALLOWED_ORIGINS entry", origin)
+	}
+}
+
+func TestLoad_Sections(t *testing.T) {
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 100, cfg.RateLimits.Global)
+	assert.Equal(t, 60, cfg.RateLimits.Sensitive, "relaxed locally")
+	assert.True(t, cfg.Features.GraphQL)
+	assert.True(t, cfg.Features.APIDocs, "on locally")
+
+	t.Setenv("RATE_LIMIT_SENSITIVE", "5")
+	t.Setenv("RATE_LIMIT_WINDOW", "10s")
+	t.Setenv("QUOTA_API_TOKENS_PER_USER", "2")
+	t.Setenv("LIMIT_GRAPHQL_MAX_COMPLEXITY", "200")
+	t.Setenv("FEATURE_GRAPHQL", "false")
+	t.Setenv("FEATURE_API_DOCS", "0")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, 5, cfg.RateLimits.Sensitive)
+	assert.Equal(t, 10*time.Second, cfg.RateLimits.Window)
+	assert.Equal(t, 2, cfg.Quotas.APITokensPerUser)
+	assert.Equal(t, 200, cfg.Limits.GraphQLMaxComplexity)
+	assert.False(t, cfg.Features.GraphQL)
+	assert.False(t, cfg.Features.APIDocs)
+
+	t.Setenv("FEATURE_GRAPHQL", "maybe")
+	t.Setenv("LIMIT_GRAPHQL_MAX_DEPTH", "0")
+	_, err = Load()
+	assert.ErrorContains(t, err, "invalid FEATURE_GRAPHQL")
+	assert.ErrorContains(t, err, "LIMIT_GRAPHQL_MAX_DEPTH must be")
+}
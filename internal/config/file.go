@@ -0,0 +1,71 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// isTOML reports whether the config file at path is TOML rather than YAML.
+func isTOML(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".toml")
+}
+
+// fileEnv returns the env the config file sets, or EnvLocal.
+func fileEnv(path string, data []byte) (string, error) {
+	var head struct {
+		Env string `yaml:"env" toml:"env"`
+	}
+	if len(data) > 0 {
+		var err error
+		if isTOML(path) {
+			_, err = toml.Decode(string(data), &head)
+		} else {
+			err = yaml.Unmarshal(data, &head)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	if head.Env == "" {
+		return EnvLocal, nil
+	}
+	return head.Env, nil
+}
+
+// decodeFile overlays the config file onto cfg. Keys the file leaves out
+// keep their defaults; keys cfg does not know are errors, so a typo does not
+// silently leave a setting at its default.
+func decodeFile(path string, data []byte, cfg *Config) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	if isTOML(path) {
+		md, err := toml.Decode(string(data), cfg)
+		if err != nil {
+			return err
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			keys := make([]string, len(undecoded))
+			for i, k := range undecoded {
+				keys[i] = k.String()
+			}
+			return fmt.Errorf("unknown keys: %s", strings.Join(keys, ", "))
+		}
+		return nil
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
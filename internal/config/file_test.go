@@ -0,0 +1,175 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFile writes a config file named name into a temp dir.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadFile_YAML(t *testing.T) {
+	path := writeFile(t, "paintbar.yaml", `
+port: "3000"
+firebase_storage_bucket: other.firebasestorage.app
+rate_limits:
+  global: 500
+  window: 30s
+cors:
+  allowed_origins: [https://paintbar.example]
+quotas:
+  webhooks_per_user: 3
+limits:
+  graphql_max_depth: 6
+features:
+  graphql: false
+`)
+	cfg, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "3000", cfg.Port)
+	assert.Equal(t, "other.firebasestorage.app", cfg.FirebaseStorageBucket)
+	assert.Equal(t, 500, cfg.RateLimits.Global)
+	assert.Equal(t, 60, cfg.RateLimits.Sensitive, "keys left out keep their defaults")
+	assert.Equal(t, 30*time.Second, cfg.RateLimits.Window)
+	assert.Equal(t, []string{"https://paintbar.example"}, cfg.CORS.AllowedOrigins)
+	assert.Equal(t, 3, cfg.Quotas.WebhooksPerUser)
+	assert.Equal(t, 6, cfg.Limits.GraphQLMaxDepth)
+	assert.False(t, cfg.Features.GraphQL)
+	assert.True(t, cfg.Features.APIDocs)
+}
+
+func TestLoadFile_TOML(t *testing.T) {
+	path := writeFile(t, "paintbar.toml", `
+port = "3000"
+
+[rate_limits]
+global = 500
+window = "30s"
+
+[features]
+api_docs = false
+`)
+	cfg, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "3000", cfg.Port)
+	assert.Equal(t, 500, cfg.RateLimits.Global)
+	assert.Equal(t, 30*time.Second, cfg.RateLimits.Window)
+	assert.False(t, cfg.Features.APIDocs)
+}
+
+func TestLoadFile_EnvOverridesFile(t *testing.T) {
+	path := writeFile(t, "paintbar.yaml", "port: \"3000\"\nrate_limits:\n  global: 500\n")
+	t.Setenv("PORT", "4000")
+	t.Setenv("RATE_LIMIT_GLOBAL", "50")
+
+	cfg, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "4000", cfg.Port)
+	assert.Equal(t, 50, cfg.RateLimits.Global)
+}
+
+func TestLoadFile_EmptyEnvOverridesFile(t *testing.T) {
+	path := writeFile(t, "paintbar.yaml", `
+metrics_port: "9100"
+cors:
+  allowed_origins: [https://paintbar.example]
+`)
+	t.Setenv("METRICS_PORT", "")
+	t.Setenv("CORS_ALLOWED_ORIGINS", "")
+
+	cfg, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.MetricsPort, "a variable set to empty still overrides the file")
+	assert.Empty(t, cfg.CORS.AllowedOrigins)
+
+	// Empty secrets, as a blank .env line exports, do not clash with _FILE
+	secret := writeFile(t, "cursor_secret", testCursorSecret)
+	t.Setenv("CURSOR_SECRET", "")
+	t.Setenv("CURSOR_SECRET_FILE", secret)
+	t.Setenv("HIERO_OPERATOR_KEY", "")
+	t.Setenv("HIERO_OPERATOR_KEY_FILE", "")
+	cfg, err = LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, testCursorSecret, cfg.CursorSecret)
+}
+
+func TestLoadFile_EnvSelectsDefaults(t *testing.T) {
+	path := writeFile(t, "paintbar.yaml", "env: production\ncursor_secret: "+testCursorSecret+"\n")
+	os.Unsetenv("ENV")
+
+	cfg, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.True(t, cfg.IsProduction())
+	assert.Equal(t, 20, cfg.RateLimits.Sensitive, "production defaults")
+	assert.False(t, cfg.Features.APIDocs)
+	assert.Equal(t, 5*time.Second, cfg.DrainDelay)
+}
+
+func TestLoadFile_UnknownKeys(t *testing.T) {
+	_, err := LoadFile(writeFile(t, "paintbar.yaml", "rate_limits:\n  globl: 5\n"))
+	assert.ErrorContains(t, err, "globl")
+
+	_, err = LoadFile(writeFile(t, "paintbar.toml", "[rate_limits]\ngotbal = 5\n"))
+	assert.ErrorContains(t, err, "unknown keys: rate_limits.gotbal")
+}
+
+func TestLoadFile_Errors(t *testing.T) {
+	_, err := LoadFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorContains(t, err, "read config file")
+
+	_, err = LoadFile(writeFile(t, "paintbar.yaml", "port: [\n"))
+	assert.ErrorContains(t, err, "config file")
+
+	cfg, err := LoadFile(writeFile(t, "paintbar.yaml", ""))
+	require.NoError(t, err, "an empty file changes nothing")
+	assert.Equal(t, "8080", cfg.Port)
+}
+
+func TestLoad_ConfigFileVariable(t *testing.T) {
+	t.Setenv("CONFIG_FILE", writeFile(t, "paintbar.yaml", "port: \"3000\"\n"))
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "3000", cfg.Port)
+}
+
+func TestWriteYAML_RedactsSecrets(t *testing.T) {
+	t.Setenv("CURSOR_SECRET", testCursorSecret)
+	t.Setenv("HIERO_OPERATOR_ID", "0.0.1234")
+	t.Setenv("HIERO_OPERATOR_KEY", "302e020100300506032b657004220420"+testHieroKey)
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, cfg.WriteYAML(&buf))
+	out := buf.String()
+	assert.NotContains(t, out, testCursorSecret)
+	assert.NotContains(t, out, testHieroKey)
+	assert.Contains(t, out, "cursor_secret: '"+Redacted+"'")
+	assert.Contains(t, out, "hiero_operator_id: 0.0.1234")
+	assert.Equal(t, testCursorSecret, cfg.CursorSecret, "the config itself is left alone")
+
+	// Without secrets the dump loads back as the same config
+	os.Unsetenv("CURSOR_SECRET")
+	os.Unsetenv("HIERO_OPERATOR_ID")
+	os.Unsetenv("HIERO_OPERATOR_KEY")
+	cfg, err = Load()
+	require.NoError(t, err)
+	buf.Reset()
+	require.NoError(t, cfg.WriteYAML(&buf))
+	reloaded, err := LoadFile(writeFile(t, "dump.yaml", buf.String()))
+	require.NoError(t, err)
+	assert.Empty(t, reloaded.CORS.AllowedOrigins)
+	reloaded.CORS.AllowedOrigins = nil // dumped as []
+	assert.Equal(t, cfg, reloaded)
+}
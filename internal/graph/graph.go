@@ -0,0 +1,148 @@
+// Package graph serves a read-only GraphQL view of users, projects, gallery
+// items and NFTs, so a client can fetch a profile page and everything on it
+// in one request. Resolvers go through the services, so the same ownership
+// and visibility rules apply as on the REST API; related records are loaded
+// in batches per query level, and queries are bounded in depth and
+// complexity before they run.
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/pandasWhoCode/paintbar/internal/service"
+)
+
+// Services are the services the resolvers read through.
+type Services struct {
+	Users    *service.UserService
+	Follows  *service.FollowService
+	Projects *service.ProjectService
+	Gallery  *service.GalleryService
+	NFTs     *service.NFTService
+	// Cursors signs page cursors, as for the REST list endpoints
+	Cursors *service.CursorCodec
+}
+
+// Graph executes GraphQL queries against the services.
+type Graph struct {
+	schema graphql.Schema
+	svc    Services
+	limits Limits
+}
+
+// New builds the schema over svc, running queries within limits.
+func New(svc Services, limits Limits) (*Graph, error) {
+	limits.setDefaults()
+	g := &Graph{svc: svc, limits: limits}
+	schema, err := g.buildSchema()
+	if err != nil {
+		return nil, err
+	}
+	g.schema = schema
+	return g, nil
+}
+
+// Request is a GraphQL request body.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+	// Extensions is accepted for client compatibility and ignored
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// Response is a GraphQL response body. Data is absent when the request was
+// rejected before execution: unparseable, invalid against the schema or
+// over the limits.
+type Response struct {
+	Data   interface{}                `json:"data,omitempty"`
+	Errors []gqlerrors.FormattedError `json:"errors,omitempty"`
+
+	executed bool
+}
+
+// Executed reports whether the query ran, as opposed to being rejected.
+func (r *Response) Executed() bool {
+	return r.executed
+}
+
+// Execute runs req on behalf of viewerUID.
+func (g *Graph) Execute(ctx context.Context, viewerUID string, req *Request) *Response {
+	if strings.TrimSpace(req.Query) == "" {
+		return rejected(errors.New("query is required"))
+	}
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(req.Query),
+		Name: "GraphQL request",
+	})})
+	if err != nil {
+		return rejected(err)
+	}
+	if v := graphql.ValidateDocument(&g.schema, doc, nil); !v.IsValid {
+		return &Response{Errors: v.Errors}
+	}
+	if err := g.limits.check(doc, req.OperationName, req.Variables); err != nil {
+		return rejected(err)
+	}
+
+	res := graphql.Execute(graphql.ExecuteParams{
+		Schema:        g.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       withRequest(ctx, g.newRequest(viewerUID)),
+	})
+	data := res.Data
+	if data == nil {
+		// An error in a non-null root field nulls the whole result, which
+		// is still sent as "data": null
+		data = json.RawMessage("null")
+	}
+	return &Response{Data: data, Errors: res.Errors, executed: true}
+}
+
+func rejected(err error) *Response {
+	return &Response{Errors: []gqlerrors.FormattedError{gqlerrors.FormatError(err)}}
+}
+
+// request is the per-query state resolvers share: who is asking and the
+// loaders batching their reads.
+type request struct {
+	viewer   string
+	users    *loader[*user]
+	projects *loader[*project]
+	gallery  *loader[*galleryItem]
+	nfts     *loader[*nft]
+}
+
+type requestKey struct{}
+
+func withRequest(ctx context.Context, r *request) context.Context {
+	return context.WithValue(ctx, requestKey{}, r)
+}
+
+func requestFrom(ctx context.Context) *request {
+	return ctx.Value(requestKey{}).(*request)
+}
+
+// publicError keeps internal failures out of responses, as the REST
+// handlers do: errors describing the request pass through, anything else is
+// logged and replaced with a generic message.
+func publicError(err error) error {
+	msg := strings.ToLower(err.Error())
+	for _, s := range []string{"unauthorized", "not found", "is required", "invalid", "must be", "validation"} {
+		if strings.Contains(msg, s) {
+			return err
+		}
+	}
+	slog.Error("graphql resolver", "error", err)
+	return errors.New("internal server error")
+}
@@ -0,0 +1,376 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"github.com/pandasWhoCode/paintbar/internal/repository"
+	"github.com/pandasWhoCode/paintbar/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The fakes embed the repository interfaces and implement only the reads
+// the resolvers reach; anything else panics.
+
+type fakeUsers struct {
+	repository.UserRepository
+	users   map[string]*model.User
+	batches int
+	err     error
+}
+
+func (f *fakeUsers) GetByID(_ context.Context, uid string) (*model.User, error) {
+	if u, ok := f.users[uid]; ok {
+		return u, nil
+	}
+	return nil, fmt.Errorf("user %s not found", uid)
+}
+
+func (f *fakeUsers) GetByIDs(_ context.Context, uids []string) ([]*model.User, error) {
+	f.batches++
+	if f.err != nil {
+		return nil, f.err
+	}
+	var out []*model.User
+	for _, uid := range uids {
+		if u, ok := f.users[uid]; ok {
+			out = append(out, u)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeUsers) GetByUsername(_ context.Context, username string) (*model.User, error) {
+	for _, u := range f.users {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return nil, fmt.Errorf("user %q not found", username)
+}
+
+type fakeFollows struct {
+	repository.FollowRepository
+}
+
+func (fakeFollows) IsFollowing(context.Context, string, string) (bool, error) {
+	return false, nil
+}
+
+type fakeProjects struct {
+	repository.ProjectRepository
+	projects []*model.Project
+	batches  int
+}
+
+func (f *fakeProjects) GetByIDs(_ context.Context, ids []string) ([]*model.Project, error) {
+	f.batches++
+	var out []*model.Project
+	for _, p := range f.projects {
+		for _, id := range ids {
+			if p.ID == id {
+				out = append(out, p)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeProjects) matching(uid string, opts *model.ListOptions) []*model.Project {
+	var out []*model.Project
+	for _, p := range f.projects {
+		if p.UserID == uid && (opts.IsPublic == nil || p.IsPublic == *opts.IsPublic) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func (f *fakeProjects) List(_ context.Context, uid string, limit int, opts *model.ListOptions, _ []string) ([]*model.Project, *model.Cursor, error) {
+	all := f.matching(uid, opts)
+	if opts.Cursor != nil {
+		for i, p := range all {
+			if p.ID == opts.Cursor.ID {
+				all = all[i+1:]
+				break
+			}
+		}
+	}
+	if len(all) <= limit {
+		return all, nil, nil
+	}
+	page := all[:limit]
+	last := page[limit-1]
+	next := &model.Cursor{Sort: opts.Sort, Order: opts.Order, Filter: opts.FilterKey(), Value: last.CreatedAt.Format(time.RFC3339Nano), ID: last.ID}
+	return page, next, nil
+}
+
+func (f *fakeProjects) CountList(_ context.Context, uid string, opts *model.ListOptions) (int64, error) {
+	return int64(len(f.matching(uid, opts))), nil
+}
+
+func (f *fakeProjects) Count(_ context.Context, uid string) (int64, error) {
+	return int64(len(f.matching(uid, &model.ListOptions{}))), nil
+}
+
+type fakeGallery struct {
+	repository.GalleryRepository
+	items []*model.GalleryItem
+}
+
+func (f *fakeGallery) owned(uid string) []*model.GalleryItem {
+	var out []*model.GalleryItem
+	for _, item := range f.items {
+		if item.UserID == uid {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func (f *fakeGallery) GetByIDs(_ context.Context, ids []string) ([]*model.GalleryItem, error) {
+	var out []*model.GalleryItem
+	for _, item := range f.items {
+		for _, id := range ids {
+			if item.ID == id {
+				out = append(out, item)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeGallery) List(_ context.Context, uid string, _ int, _ *model.ListOptions, _ []string) ([]*model.GalleryItem, *model.Cursor, error) {
+	return f.owned(uid), nil, nil
+}
+
+func (f *fakeGallery) CountList(_ context.Context, uid string, _ *model.ListOptions) (int64, error) {
+	return int64(len(f.owned(uid))), nil
+}
+
+func (f *fakeGallery) Count(_ context.Context, uid string) (int64, error) {
+	return int64(len(f.owned(uid))), nil
+}
+
+type fakeNFTs struct {
+	repository.NFTRepository
+}
+
+func (fakeNFTs) GetByIDs(context.Context, []string) ([]*model.NFT, error) { return nil, nil }
+
+func (fakeNFTs) List(context.Context, string, int, *model.ListOptions, []string) ([]*model.NFT, *model.Cursor, error) {
+	return nil, nil, nil
+}
+
+func (fakeNFTs) CountList(context.Context, string, *model.ListOptions) (int64, error) { return 0, nil }
+
+func (fakeNFTs) Count(context.Context, string) (int64, error) { return 0, nil }
+
+type fixture struct {
+	graph    *Graph
+	users    *fakeUsers
+	projects *fakeProjects
+}
+
+// newFixture has alice with two public projects and a private one, a
+// gallery item shared from a public project, and bob.
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	users := &fakeUsers{users: map[string]*model.User{
+		"alice": {UID: "alice", Username: "alice", Email: "alice@example.com", CreatedAt: created},
+		"bob":   {UID: "bob", Username: "bob", Email: "bob@example.com", CreatedAt: created},
+	}}
+	projects := &fakeProjects{projects: []*model.Project{
+		{ID: "p1", UserID: "alice", Title: "Sky", IsPublic: true, CreatedAt: created, UpdatedAt: created},
+		{ID: "p2", UserID: "alice", Title: "Sea", IsPublic: true, CreatedAt: created, UpdatedAt: created},
+		{ID: "p3", UserID: "alice", Title: "Secret", CreatedAt: created, UpdatedAt: created},
+	}}
+	gallery := &fakeGallery{items: []*model.GalleryItem{
+		{ID: "g1", UserID: "alice", ProjectID: "p1", Name: "Sky", CreatedAt: created},
+	}}
+
+	g, err := New(Services{
+		Users:    service.NewUserService(users, nil),
+		Follows:  service.NewFollowService(users, fakeFollows{}, gallery),
+		Projects: service.NewProjectService(projects, nil, nil, nil),
+		Gallery:  service.NewGalleryService(gallery, nil, nil),
+		NFTs:     service.NewNFTService(fakeNFTs{}, nil, nil),
+		Cursors:  service.NewCursorCodec([]byte("test")),
+	}, Limits{})
+	require.NoError(t, err)
+	return &fixture{graph: g, users: users, projects: projects}
+}
+
+// run executes query as viewer and decodes the response.
+func (f *fixture) run(t *testing.T, viewer, query string, vars map[string]interface{}) (map[string]interface{}, []string, *Response) {
+	t.Helper()
+	res := f.graph.Execute(context.Background(), viewer, &Request{Query: query, Variables: vars})
+	raw, err := json.Marshal(res)
+	require.NoError(t, err)
+	var body struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &body))
+	var msgs []string
+	for _, e := range body.Errors {
+		msgs = append(msgs, e.Message)
+	}
+	return body.Data, msgs, res
+}
+
+func TestGraph_ProfilePageInOneQuery(t *testing.T) {
+	f := newFixture(t)
+	data, errs, res := f.run(t, "alice", `{
+		me {
+			username email projectCount galleryCount nftCount
+			projects { total items { title owner { username } } }
+			gallery { items { name project { title } } }
+			nfts { total }
+		}
+	}`, nil)
+	require.True(t, res.Executed())
+	require.Empty(t, errs)
+
+	me := data["me"].(map[string]interface{})
+	assert.Equal(t, "alice@example.com", me["email"])
+	assert.EqualValues(t, 3, me["projectCount"])
+	assert.EqualValues(t, 1, me["galleryCount"])
+	assert.EqualValues(t, 0, me["nftCount"])
+
+	projects := me["projects"].(map[string]interface{})
+	assert.EqualValues(t, 3, projects["total"])
+	items := projects["items"].([]interface{})
+	require.Len(t, items, 3)
+	for _, item := range items {
+		assert.Equal(t, "alice", item.(map[string]interface{})["owner"].(map[string]interface{})["username"])
+	}
+
+	gallery := me["gallery"].(map[string]interface{})["items"].([]interface{})
+	require.Len(t, gallery, 1)
+	assert.Equal(t, "Sky", gallery[0].(map[string]interface{})["project"].(map[string]interface{})["title"])
+
+	// Three owners and one gallery project, each read in a single batch
+	assert.Equal(t, 1, f.users.batches)
+	assert.Equal(t, 1, f.projects.batches)
+}
+
+func TestGraph_AppliesVisibility(t *testing.T) {
+	f := newFixture(t)
+	data, errs, _ := f.run(t, "bob", `{
+		user(username: "alice") { email projectCount projects { items { id } } gallery { total } }
+		secret: project(id: "p3") { title }
+		sky: project(id: "p1") { title }
+		galleryItem(id: "g1") { name }
+	}`, nil)
+
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0], "unauthorized")
+
+	alice := data["user"].(map[string]interface{})
+	assert.Nil(t, alice["email"])
+	assert.EqualValues(t, 2, alice["projectCount"])
+	assert.Len(t, alice["projects"].(map[string]interface{})["items"], 2)
+	assert.Nil(t, alice["gallery"])
+
+	assert.Nil(t, data["secret"])
+	assert.Equal(t, "Sky", data["sky"].(map[string]interface{})["title"])
+	assert.Nil(t, data["galleryItem"])
+}
+
+func TestGraph_PagesWithCursors(t *testing.T) {
+	f := newFixture(t)
+	query := `query($after: String) { me { projects(first: 2, after: $after) { items { id } nextCursor hasMore } } }`
+
+	data, errs, _ := f.run(t, "alice", query, nil)
+	require.Empty(t, errs)
+	page := data["me"].(map[string]interface{})["projects"].(map[string]interface{})
+	assert.Len(t, page["items"], 2)
+	assert.Equal(t, true, page["hasMore"])
+	cursor, ok := page["nextCursor"].(string)
+	require.True(t, ok)
+
+	data, errs, _ = f.run(t, "alice", query, map[string]interface{}{"after": cursor})
+	require.Empty(t, errs)
+	page = data["me"].(map[string]interface{})["projects"].(map[string]interface{})
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": "p3"}}, page["items"])
+	assert.Equal(t, false, page["hasMore"])
+
+	_, errs, _ = f.run(t, "alice", query, map[string]interface{}{"after": "forged"})
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0], "invalid cursor")
+}
+
+func TestGraph_Limits(t *testing.T) {
+	f := newFixture(t)
+
+	deep := `{ me { projects { items { owner { projects { items { owner { projects { items { owner { username } } } } } } } } } } }`
+	_, errs, res := f.run(t, "alice", deep, nil)
+	assert.False(t, res.Executed())
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0], "query depth 11 exceeds the limit of 10")
+
+	wide := `{ me { projects(first: 50) { items { owner { projects(first: $n) { items { id title } } } } } } }`
+	_, errs, res = f.run(t, "alice", "query($n: Int) "+wide, map[string]interface{}{"n": float64(50)})
+	assert.False(t, res.Executed())
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0], "query complexity")
+
+	// Fragments count toward the limits
+	spread := `query { me { ...P } } fragment P on User { projects(first: 50) { items { owner { projects(first: 50) { total } } } } }`
+	_, errs, _ = f.run(t, "alice", spread, nil)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0], "query complexity")
+
+	// Introspection is free, so tooling can always read the schema
+	_, errs, res = f.run(t, "alice", `{ __schema { types { name fields { name type { name ofType { name ofType { name } } } } } } }`, nil)
+	assert.True(t, res.Executed())
+	assert.Empty(t, errs)
+}
+
+func TestGraph_ConfiguredLimits(t *testing.T) {
+	f := newFixture(t)
+	g, err := New(f.graph.svc, Limits{MaxDepth: 3, MaxComplexity: 20})
+	require.NoError(t, err)
+
+	res := g.Execute(context.Background(), "alice", &Request{Query: `{ me { projects { items { id } } } }`})
+	require.Len(t, res.Errors, 1)
+	assert.Contains(t, res.Errors[0].Message, "query depth 4 exceeds the limit of 3")
+
+	res = g.Execute(context.Background(), "alice", &Request{Query: `{ me { projects(first: 50) { total } } }`})
+	require.Len(t, res.Errors, 1)
+	assert.Contains(t, res.Errors[0].Message, "exceeds the limit of 20")
+
+	res = g.Execute(context.Background(), "alice", &Request{Query: `{ me { username } }`})
+	assert.True(t, res.Executed())
+	assert.Empty(t, res.Errors)
+}
+
+func TestGraph_RejectsInvalidQueries(t *testing.T) {
+	f := newFixture(t)
+	for _, q := range []string{"", "{ me { ", "{ me { password } }"} {
+		_, errs, res := f.run(t, "alice", q, nil)
+		assert.False(t, res.Executed(), q)
+		assert.NotEmpty(t, errs, q)
+	}
+}
+
+func TestGraph_MasksInternalErrors(t *testing.T) {
+	f := newFixture(t)
+	f.users.err = errors.New("firestore: deadline exceeded on users")
+
+	data, errs, res := f.run(t, "alice", `{ project(id: "p1") { title owner { username } } }`, nil)
+	assert.True(t, res.Executed())
+	require.Len(t, errs, 1)
+	assert.Equal(t, "internal server error", errs[0])
+	assert.Nil(t, data["project"].(map[string]interface{})["owner"])
+}
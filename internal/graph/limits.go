@@ -0,0 +1,149 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/pandasWhoCode/paintbar/internal/service"
+)
+
+const (
+	firstArg    = "first"
+	maxPageSize = service.MaxPageSize
+)
+
+// Limits bound the queries a Graph will run.
+type Limits struct {
+	// MaxDepth is the deepest field nesting a query may select (default 10).
+	MaxDepth int
+	// MaxComplexity is the most a query may cost (default 1000). Every
+	// field costs one, and the fields under a list field count once per
+	// item requested.
+	MaxComplexity int
+}
+
+func (l *Limits) setDefaults() {
+	if l.MaxDepth <= 0 {
+		l.MaxDepth = 10
+	}
+	if l.MaxComplexity <= 0 {
+		l.MaxComplexity = 1000
+	}
+}
+
+// check rejects an operation that nests deeper than MaxDepth or costs more
+// than MaxComplexity. The document has already been validated, so its
+// fragments exist and do not cycle. Introspection fields are free, so
+// tooling can always load the schema.
+func (l Limits) check(doc *ast.Document, operationName string, vars map[string]interface{}) error {
+	var op *ast.OperationDefinition
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, def := range doc.Definitions {
+		switch d := def.(type) {
+		case *ast.OperationDefinition:
+			if operationName == "" || (d.Name != nil && d.Name.Value == operationName) {
+				op = d
+			}
+		case *ast.FragmentDefinition:
+			fragments[d.Name.Value] = d
+		}
+	}
+	if op == nil {
+		// graphql.Execute reports the missing or ambiguous operation
+		return nil
+	}
+
+	c := &costs{fragments: fragments, vars: vars}
+	depth, cost := c.selections(op.SelectionSet)
+	if depth > l.MaxDepth {
+		return fmt.Errorf("query depth %d exceeds the limit of %d", depth, l.MaxDepth)
+	}
+	if cost > l.MaxComplexity {
+		return fmt.Errorf("query complexity %d exceeds the limit of %d", cost, l.MaxComplexity)
+	}
+	return nil
+}
+
+type costs struct {
+	fragments map[string]*ast.FragmentDefinition
+	vars      map[string]interface{}
+}
+
+// selections returns the depth and cost of a selection set.
+func (c *costs) selections(set *ast.SelectionSet) (depth, cost int) {
+	if set == nil {
+		return 0, 0
+	}
+	for _, sel := range set.Selections {
+		var d, n int
+		switch s := sel.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name.Value, "__") {
+				continue
+			}
+			d, n = c.selections(s.SelectionSet)
+			d++
+			n = 1 + n*c.pageSize(s)
+		case *ast.InlineFragment:
+			d, n = c.selections(s.SelectionSet)
+		case *ast.FragmentSpread:
+			if f := c.fragments[s.Name.Value]; f != nil {
+				d, n = c.selections(f.SelectionSet)
+			}
+		}
+		depth = max(depth, d)
+		cost += n
+	}
+	return depth, cost
+}
+
+// pageSize is the number of items a list field asks for, as the services
+// will clamp it: its first argument, the default page size without one, or
+// the maximum when the value is not known before execution.
+func (c *costs) pageSize(f *ast.Field) int {
+	for _, arg := range f.Arguments {
+		if arg.Name.Value != firstArg {
+			continue
+		}
+		var n int
+		switch v := arg.Value.(type) {
+		case *ast.IntValue:
+			i, err := strconv.Atoi(v.Value)
+			if err != nil {
+				return maxPageSize
+			}
+			n = i
+		case *ast.Variable:
+			switch x := c.vars[v.Name.Value].(type) {
+			case float64:
+				n = int(x)
+			case int:
+				n = x
+			default:
+				return maxPageSize
+			}
+		default:
+			return maxPageSize
+		}
+		if n <= 0 {
+			return service.DefaultPageSize
+		}
+		return min(n, maxPageSize)
+	}
+	if isListField(f) {
+		return service.DefaultPageSize
+	}
+	return 1
+}
+
+// isListField reports whether f is one of the paged User fields, which
+// return a page even when first is left out.
+func isListField(f *ast.Field) bool {
+	switch f.Name.Value {
+	case "projects", "gallery", "nfts":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package graph
+
+import (
+	"context"
+)
+
+// thunk is a deferred field value. graphql-go resolves the thunks of one
+// query level after all of that level's resolvers have run, which is what
+// lets a loader see every key of the level before it fetches.
+type thunk = func() (interface{}, error)
+
+// loader batches the keys requested while one query level resolves into a
+// single fetch and caches the results for the rest of the request. Keys the
+// fetch does not return resolve to nil, so missing and inaccessible records
+// look the same. A loader belongs to one request, and graphql-go resolves a
+// request on one goroutine, so it needs no locking.
+type loader[T any] struct {
+	fetch   func(ctx context.Context, keys []string) (map[string]T, error)
+	pending []string
+	fetched map[string]bool // false while a key waits in pending
+	results map[string]T
+	errs    map[string]error
+}
+
+func newLoader[T any](fetch func(ctx context.Context, keys []string) (map[string]T, error)) *loader[T] {
+	return &loader[T]{
+		fetch:   fetch,
+		fetched: make(map[string]bool),
+		results: make(map[string]T),
+		errs:    make(map[string]error),
+	}
+}
+
+// load queues key and returns a thunk for its value. The first thunk called
+// fetches every key queued so far.
+func (l *loader[T]) load(ctx context.Context, key string) thunk {
+	if _, seen := l.fetched[key]; !seen {
+		l.fetched[key] = false
+		l.pending = append(l.pending, key)
+	}
+	return func() (interface{}, error) {
+		if !l.fetched[key] {
+			l.flush(ctx)
+		}
+		if err := l.errs[key]; err != nil {
+			return nil, publicError(err)
+		}
+		if v, ok := l.results[key]; ok {
+			return v, nil
+		}
+		return nil, nil
+	}
+}
+
+// flush fetches the pending keys in one call.
+func (l *loader[T]) flush(ctx context.Context) {
+	keys := l.pending
+	l.pending = nil
+	results, err := l.fetch(ctx, keys)
+	for _, k := range keys {
+		l.fetched[k] = true
+		if err != nil {
+			l.errs[k] = err
+		} else if v, ok := results[k]; ok {
+			l.results[k] = v
+		}
+	}
+}
@@ -0,0 +1,474 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/pandasWhoCode/paintbar/internal/model"
+)
+
+// The object types resolve from the list summaries, as the REST lists
+// return them: inline image data is left to the thumbnail endpoints. Each
+// source also carries its owner for the owner field; Resolve hands the
+// remaining fields to the default resolver, which reads their JSON names.
+
+type user struct {
+	*model.PublicProfile
+	email string // set when loaded as the viewer's own profile
+}
+
+func (u *user) Resolve(p graphql.ResolveParams) (interface{}, error) {
+	p.Source = u.PublicProfile
+	return graphql.DefaultResolveFn(p)
+}
+
+type project struct {
+	*model.ProjectSummary
+	ownerID string
+}
+
+func (s *project) Resolve(p graphql.ResolveParams) (interface{}, error) {
+	p.Source = s.ProjectSummary
+	return graphql.DefaultResolveFn(p)
+}
+
+type galleryItem struct {
+	*model.GallerySummary
+	ownerID string
+}
+
+func (s *galleryItem) Resolve(p graphql.ResolveParams) (interface{}, error) {
+	p.Source = s.GallerySummary
+	return graphql.DefaultResolveFn(p)
+}
+
+type nft struct {
+	*model.NFTSummary
+	ownerID string
+}
+
+func (s *nft) Resolve(p graphql.ResolveParams) (interface{}, error) {
+	p.Source = s.NFTSummary
+	return graphql.DefaultResolveFn(p)
+}
+
+// page is the GraphQL form of model.Page.
+type page struct {
+	Items      interface{} `json:"items"`
+	NextCursor *string     `json:"nextCursor"`
+	HasMore    bool        `json:"hasMore"`
+	Total      int64       `json:"total"`
+}
+
+// newRequest creates the per-query state for viewer. Each loader fetches
+// through a batch service method that applies the single-record rules.
+func (g *Graph) newRequest(viewer string) *request {
+	return &request{
+		viewer: viewer,
+		users: newLoader(func(ctx context.Context, uids []string) (map[string]*user, error) {
+			profiles, err := g.svc.Follows.GetPublicProfiles(ctx, uids)
+			if err != nil {
+				return nil, err
+			}
+			users := make(map[string]*user, len(profiles))
+			for uid, p := range profiles {
+				users[uid] = &user{PublicProfile: p}
+			}
+			return users, nil
+		}),
+		projects: newLoader(func(ctx context.Context, ids []string) (map[string]*project, error) {
+			projects, err := g.svc.Projects.GetProjects(ctx, viewer, ids)
+			if err != nil {
+				return nil, err
+			}
+			sources := make(map[string]*project, len(projects))
+			for id, p := range projects {
+				sources[id] = &project{ProjectSummary: p.Summary(), ownerID: p.UserID}
+			}
+			return sources, nil
+		}),
+		gallery: newLoader(func(ctx context.Context, ids []string) (map[string]*galleryItem, error) {
+			items, err := g.svc.Gallery.GetItems(ctx, viewer, ids)
+			if err != nil {
+				return nil, err
+			}
+			sources := make(map[string]*galleryItem, len(items))
+			for id, item := range items {
+				sources[id] = &galleryItem{GallerySummary: item.Summary(), ownerID: item.UserID}
+			}
+			return sources, nil
+		}),
+		nfts: newLoader(func(ctx context.Context, ids []string) (map[string]*nft, error) {
+			nfts, err := g.svc.NFTs.GetNFTs(ctx, viewer, ids)
+			if err != nil {
+				return nil, err
+			}
+			sources := make(map[string]*nft, len(nfts))
+			for id, n := range nfts {
+				sources[id] = &nft{NFTSummary: n.Summary(), ownerID: n.UserID}
+			}
+			return sources, nil
+		}),
+	}
+}
+
+// buildSchema defines the GraphQL schema. Object types refer to each other
+// through field thunks, since users own projects that point back at them.
+func (g *Graph) buildSchema() (graphql.Schema, error) {
+	var userType, projectType, galleryType, nftType *graphql.Object
+
+	// owner is resolved inside the field thunks, once userType exists
+	owner := func(ownerID func(interface{}) string) *graphql.Field {
+		return &graphql.Field{
+			Type:        userType,
+			Description: "The owner. Null if their account is suspended.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return requestFrom(p.Context).users.load(p.Context, ownerID(p.Source)), nil
+			},
+		}
+	}
+
+	userType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "User",
+		Description: "A PaintBar user. Email and the gallery and NFT fields are only visible on the viewer's own profile.",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"uid":             {Type: graphql.NewNonNull(graphql.ID)},
+				"username":        {Type: graphql.String},
+				"email":           {Type: graphql.String, Resolve: g.resolveEmail},
+				"displayName":     {Type: graphql.String},
+				"bio":             {Type: graphql.String},
+				"location":        {Type: graphql.String},
+				"website":         {Type: graphql.String},
+				"githubUrl":       {Type: graphql.String},
+				"twitterHandle":   {Type: graphql.String},
+				"blueskyHandle":   {Type: graphql.String},
+				"instagramHandle": {Type: graphql.String},
+				"followerCount":   {Type: graphql.NewNonNull(graphql.Int)},
+				"followingCount":  {Type: graphql.NewNonNull(graphql.Int)},
+				"createdAt":       {Type: graphql.NewNonNull(graphql.DateTime)},
+				"projectCount": {
+					Type:        graphql.NewNonNull(graphql.Int),
+					Description: "Projects the viewer can see: all of their own, others' public ones.",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return wrap(g.svc.Projects.CountVisibleProjects(p.Context, requestFrom(p.Context).viewer, p.Source.(*user).UID))
+					},
+				},
+				"galleryCount": {
+					Type: graphql.Int,
+					Resolve: g.ownOnly(func(ctx context.Context, uid string, _ graphql.ResolveParams) (interface{}, error) {
+						return g.svc.Gallery.CountItems(ctx, uid)
+					}),
+				},
+				"nftCount": {
+					Type: graphql.Int,
+					Resolve: g.ownOnly(func(ctx context.Context, uid string, _ graphql.ResolveParams) (interface{}, error) {
+						return g.svc.NFTs.CountNFTs(ctx, uid)
+					}),
+				},
+				"projects": {
+					Type:        graphql.NewNonNull(pageType("ProjectPage", projectType)),
+					Description: "Projects the viewer can see: all of their own, others' public ones.",
+					Args:        listArgs(true, true),
+					Resolve:     g.resolveProjects,
+				},
+				"gallery": {
+					Type:    pageType("GalleryPage", galleryType),
+					Args:    listArgs(true, false),
+					Resolve: g.ownOnly(g.resolveGallery),
+				},
+				"nfts": {
+					Type:    pageType("NFTPage", nftType),
+					Args:    listArgs(false, false),
+					Resolve: g.ownOnly(g.resolveNFTs),
+				},
+			}
+		}),
+	})
+
+	projectType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Project",
+		Description: "A canvas project, visible to its owner or to anyone while public.",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":           {Type: graphql.NewNonNull(graphql.ID)},
+				"title":        {Type: graphql.NewNonNull(graphql.String)},
+				"contentHash":  {Type: graphql.NewNonNull(graphql.String)},
+				"width":        {Type: graphql.Int},
+				"height":       {Type: graphql.Int},
+				"isPublic":     {Type: graphql.NewNonNull(graphql.Boolean)},
+				"tags":         {Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+				"createdAt":    {Type: graphql.NewNonNull(graphql.DateTime)},
+				"updatedAt":    {Type: graphql.NewNonNull(graphql.DateTime)},
+				"thumbnailUrl": {Type: graphql.NewNonNull(graphql.String)},
+				"owner":        owner(func(s interface{}) string { return s.(*project).ownerID }),
+			}
+		}),
+	})
+
+	galleryType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "GalleryItem",
+		Description: "An artwork shared to the gallery, visible to its owner.",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":           {Type: graphql.NewNonNull(graphql.ID)},
+				"name":         {Type: graphql.NewNonNull(graphql.String)},
+				"width":        {Type: graphql.Int},
+				"height":       {Type: graphql.Int},
+				"tags":         {Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+				"commentCount": {Type: graphql.NewNonNull(graphql.Int)},
+				"hidden":       {Type: graphql.NewNonNull(graphql.Boolean)},
+				"createdAt":    {Type: graphql.NewNonNull(graphql.DateTime)},
+				"thumbnailUrl": {Type: graphql.NewNonNull(graphql.String)},
+				"owner":        owner(func(s interface{}) string { return s.(*galleryItem).ownerID }),
+				"project": {
+					Type:        projectType,
+					Description: "The project the item was shared from, if the viewer can see it.",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						id := p.Source.(*galleryItem).ProjectID
+						if id == "" {
+							return nil, nil
+						}
+						return requestFrom(p.Context).projects.load(p.Context, id), nil
+					},
+				},
+			}
+		}),
+	})
+
+	nftType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "NFT",
+		Description: "An NFT record, visible to its owner.",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":           {Type: graphql.NewNonNull(graphql.ID)},
+				"name":         {Type: graphql.NewNonNull(graphql.String)},
+				"imageUrl":     {Type: graphql.String},
+				"price":        {Type: graphql.Float},
+				"isListed":     {Type: graphql.NewNonNull(graphql.Boolean)},
+				"hidden":       {Type: graphql.NewNonNull(graphql.Boolean)},
+				"tokenId":      {Type: graphql.String},
+				"serialNumber": {Type: graphql.Int},
+				"createdAt":    {Type: graphql.NewNonNull(graphql.DateTime)},
+				"updatedAt":    {Type: graphql.NewNonNull(graphql.DateTime)},
+				"thumbnailUrl": {Type: graphql.NewNonNull(graphql.String)},
+				"owner":        owner(func(s interface{}) string { return s.(*nft).ownerID }),
+			}
+		}),
+	})
+
+	byID := func(load func(r *request) func(context.Context, string) thunk) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			return load(requestFrom(p.Context))(p.Context, p.Args["id"].(string)), nil
+		}
+	}
+	idArgs := graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"me": {
+				Type:        graphql.NewNonNull(userType),
+				Description: "The signed-in user.",
+				Resolve:     g.resolveMe,
+			},
+			"user": {
+				Type:        userType,
+				Description: "The user with a username. Suspended users are not found.",
+				Args:        graphql.FieldConfigArgument{"username": {Type: graphql.NewNonNull(graphql.String)}},
+				Resolve:     g.resolveUser,
+			},
+			"project": {
+				Type:        projectType,
+				Description: "A project by ID, or null if it does not exist or the viewer cannot see it.",
+				Args:        idArgs,
+				Resolve:     byID(func(r *request) func(context.Context, string) thunk { return r.projects.load }),
+			},
+			"galleryItem": {
+				Type:        galleryType,
+				Description: "One of the viewer's gallery items by ID, or null.",
+				Args:        idArgs,
+				Resolve:     byID(func(r *request) func(context.Context, string) thunk { return r.gallery.load }),
+			},
+			"nft": {
+				Type:        nftType,
+				Description: "One of the viewer's NFTs by ID, or null.",
+				Args:        idArgs,
+				Resolve:     byID(func(r *request) func(context.Context, string) thunk { return r.nfts.load }),
+			},
+		},
+	})
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: query})
+	if err != nil {
+		return graphql.Schema{}, fmt.Errorf("graphql schema: %w", err)
+	}
+	return schema, nil
+}
+
+// pageType is a page of items, as the REST lists return it.
+func pageType(name string, item *graphql.Object) *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: name,
+		Fields: graphql.Fields{
+			"items":      {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(item)))},
+			"nextCursor": {Type: graphql.String, Description: "Pass as after to get the next page."},
+			"hasMore":    {Type: graphql.NewNonNull(graphql.Boolean)},
+			"total":      {Type: graphql.NewNonNull(graphql.Int), Description: "Items matching the filters across all pages."},
+		},
+	})
+}
+
+// listArgs are the paging, sort and filter arguments of a list field,
+// matching the REST query parameters.
+func listArgs(tag, isPublic bool) graphql.FieldConfigArgument {
+	args := graphql.FieldConfigArgument{
+		firstArg: {Type: graphql.Int, Description: fmt.Sprintf("Page size, at most %d.", maxPageSize)},
+		"after":  {Type: graphql.String, Description: "nextCursor of the previous page."},
+		"sort":   {Type: graphql.String},
+		"order":  {Type: graphql.String, Description: "asc or desc."},
+	}
+	if tag {
+		args["tag"] = &graphql.ArgumentConfig{Type: graphql.String}
+	}
+	if isPublic {
+		args["isPublic"] = &graphql.ArgumentConfig{Type: graphql.Boolean}
+	}
+	return args
+}
+
+func (g *Graph) resolveMe(p graphql.ResolveParams) (interface{}, error) {
+	u, err := g.svc.Users.GetProfile(p.Context, requestFrom(p.Context).viewer)
+	if err != nil {
+		return nil, publicError(err)
+	}
+	return &user{PublicProfile: u.ToPublicProfile(), email: u.Email}, nil
+}
+
+func (g *Graph) resolveUser(p graphql.ResolveParams) (interface{}, error) {
+	profile, err := g.svc.Follows.GetPublicProfile(p.Context, requestFrom(p.Context).viewer, p.Args["username"].(string))
+	if err != nil {
+		return nil, publicError(err)
+	}
+	return &user{PublicProfile: profile}, nil
+}
+
+// resolveEmail shows the viewer their own email, reading it if the profile
+// was loaded as someone's owner.
+func (g *Graph) resolveEmail(p graphql.ResolveParams) (interface{}, error) {
+	u := p.Source.(*user)
+	if u.UID != requestFrom(p.Context).viewer {
+		return nil, nil
+	}
+	if u.email == "" {
+		full, err := g.svc.Users.GetProfile(p.Context, u.UID)
+		if err != nil {
+			return nil, publicError(err)
+		}
+		u.email = full.Email
+	}
+	return u.email, nil
+}
+
+// ownOnly restricts a User field to the viewer's own profile, as the
+// gallery and NFT services only serve owners.
+func (g *Graph) ownOnly(resolve func(ctx context.Context, uid string, p graphql.ResolveParams) (interface{}, error)) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		uid := p.Source.(*user).UID
+		if uid != requestFrom(p.Context).viewer {
+			return nil, fmt.Errorf("unauthorized: %s is only visible to its owner", p.Info.FieldName)
+		}
+		return wrap(resolve(p.Context, uid, p))
+	}
+}
+
+func (g *Graph) resolveProjects(p graphql.ResolveParams) (interface{}, error) {
+	ownerID := p.Source.(*user).UID
+	opts, limit, err := g.listOptions(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	res, err := g.svc.Projects.ListVisibleProjects(p.Context, requestFrom(p.Context).viewer, ownerID, limit, opts, nil)
+	if err != nil {
+		return nil, publicError(err)
+	}
+	summaries := res.Items.([]*model.ProjectSummary)
+	items := make([]*project, len(summaries))
+	for i, s := range summaries {
+		items[i] = &project{ProjectSummary: s, ownerID: ownerID}
+	}
+	return g.page(res, items), nil
+}
+
+func (g *Graph) resolveGallery(ctx context.Context, uid string, p graphql.ResolveParams) (interface{}, error) {
+	opts, limit, err := g.listOptions(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	res, err := g.svc.Gallery.ListItems(ctx, uid, limit, opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	summaries := res.Items.([]*model.GallerySummary)
+	items := make([]*galleryItem, len(summaries))
+	for i, s := range summaries {
+		items[i] = &galleryItem{GallerySummary: s, ownerID: uid}
+	}
+	return g.page(res, items), nil
+}
+
+func (g *Graph) resolveNFTs(ctx context.Context, uid string, p graphql.ResolveParams) (interface{}, error) {
+	opts, limit, err := g.listOptions(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	res, err := g.svc.NFTs.ListNFTs(ctx, uid, limit, opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	summaries := res.Items.([]*model.NFTSummary)
+	items := make([]*nft, len(summaries))
+	for i, s := range summaries {
+		items[i] = &nft{NFTSummary: s, ownerID: uid}
+	}
+	return g.page(res, items), nil
+}
+
+// listOptions reads the listArgs of a field. The services validate the
+// sort and filters against the collection.
+func (g *Graph) listOptions(args map[string]interface{}) (*model.ListOptions, int, error) {
+	opts := &model.ListOptions{}
+	opts.Sort, _ = args["sort"].(string)
+	opts.Order, _ = args["order"].(string)
+	opts.Tag, _ = args["tag"].(string)
+	if v, ok := args["isPublic"].(bool); ok {
+		opts.IsPublic = &v
+	}
+	if after, _ := args["after"].(string); after != "" {
+		c, err := g.svc.Cursors.Decode(after)
+		if err != nil {
+			return nil, 0, err
+		}
+		opts.Cursor = c
+	}
+	limit, _ := args[firstArg].(int)
+	return opts, limit, nil
+}
+
+func (g *Graph) page(res *model.Page, items interface{}) *page {
+	out := &page{Items: items, HasMore: res.HasMore, Total: res.Total}
+	if res.Next != nil {
+		cursor := g.svc.Cursors.Encode(res.Next)
+		out.NextCursor = &cursor
+	}
+	return out
+}
+
+// wrap passes a service result through publicError.
+func wrap[T any](v T, err error) (interface{}, error) {
+	if err != nil {
+		return nil, publicError(err)
+	}
+	return v, nil
+}
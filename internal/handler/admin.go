@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pandasWhoCode/paintbar/internal/service"
+)
+
+// AdminHandler handles the account-level admin API. Routes are gated by
+// middleware.RequireRole(service.RoleAdmin).
+type AdminHandler struct {
+	adminService *service.AdminService
+}
+
+// NewAdminHandler creates a new AdminHandler.
+func NewAdminHandler(adminService *service.AdminService) *AdminHandler {
+	return &AdminHandler{adminService: adminService}
+}
+
+// LookupUser handles GET /api/admin/users?email=|uid=|username=
+func (h *AdminHandler) LookupUser(w http.ResponseWriter, r *http.Request) {
+	admin := requireUser(w, r)
+	if admin == nil {
+		return
+	}
+
+	q := r.URL.Query()
+	user, err := h.adminService.LookupUser(r.Context(), admin.UID, q.Get("email"), q.Get("uid"), q.Get("username"))
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, user)
+}
+
+// Stats handles GET /api/admin/stats
+func (h *AdminHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	if requireUser(w, r) == nil {
+		return
+	}
+
+	stats, err := h.adminService.Stats(r.Context())
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, stats)
+}
+
+// SetUserDisabled handles PUT /api/admin/users/{uid}/disabled
+func (h *AdminHandler) SetUserDisabled(w http.ResponseWriter, r *http.Request) {
+	setFlag(w, r, "disabled", func(r *http.Request, adminUID string, disabled bool) error {
+		return h.adminService.SetUserDisabled(r.Context(), adminUID, chi.URLParam(r, "uid"), disabled)
+	})
+}
+
+// ReleaseUsername handles DELETE /api/admin/usernames/{username}
+func (h *AdminHandler) ReleaseUsername(w http.ResponseWriter, r *http.Request) {
+	admin := requireUser(w, r)
+	if admin == nil {
+		return
+	}
+
+	if err := h.adminService.ReleaseUsername(r.Context(), admin.UID, chi.URLParam(r, "username")); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "released"})
+}
+
+// ListAuditLog handles GET /api/admin/audit?actor=
+func (h *AdminHandler) ListAuditLog(w http.ResponseWriter, r *http.Request) {
+	if requireUser(w, r) == nil {
+		return
+	}
+
+	limit, startAfter := parsePagination(r)
+
+	entries, err := h.adminService.ListAuditLog(r.Context(), r.URL.Query().Get("actor"), limit, startAfter)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, entries)
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pandasWhoCode/paintbar/internal/service"
+)
+
+// Cache-Control values for cacheable responses. Everything under /api is
+// authenticated, so responses are private to the browser cache.
+const (
+	// cacheRevalidate lets the browser keep a copy but check it with the
+	// validators on every use.
+	cacheRevalidate = "private, no-cache"
+	// cacheImmutable is for content-addressed URLs, whose bytes never change.
+	cacheImmutable = "private, max-age=31536000, immutable"
+)
+
+// setCacheControl sets Cache-Control and marks the response as varying by
+// credentials, since the same URL can return different bodies per user.
+func setCacheControl(w http.ResponseWriter, value string) {
+	w.Header().Set("Cache-Control", value)
+	w.Header().Add("Vary", "Authorization, Cookie")
+}
+
+// strongETag quotes a content hash as a strong entity tag.
+func strongETag(hash string) string {
+	return `"` + hash + `"`
+}
+
+// checkNotModified sets the ETag and Last-Modified validators and reports
+// whether the request's conditional headers match them, in which case it has
+// written a 304 response. modTime may be zero to omit Last-Modified. As in
+// RFC 9110, If-Modified-Since is ignored when If-None-Match is present.
+func checkNotModified(w http.ResponseWriter, r *http.Request, etag string, modTime time.Time) bool {
+	if etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+	if !modTime.IsZero() {
+		w.Header().Set("Last-Modified", modTime.UTC().Format(http.TimeFormat))
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	match := false
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		match = etag != "" && etagListMatches(inm, etag)
+	} else if ims := r.Header.Get("If-Modified-Since"); ims != "" && !modTime.IsZero() {
+		if t, err := http.ParseTime(ims); err == nil {
+			match = !modTime.Truncate(time.Second).After(t)
+		}
+	}
+	if !match {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagListMatches applies the weak comparison If-None-Match uses: "*"
+// matches anything and W/ prefixes are ignored.
+func etagListMatches(list, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// respondCacheableJSON writes a 200 JSON response the browser may cache and
+// revalidate, with an ETag derived from the body. It answers 304 when the
+// client's copy is current. Use it for reads of shared resources; respondJSON
+// remains the default for per-user data.
+func respondCacheableJSON(w http.ResponseWriter, r *http.Request, data interface{}, modTime time.Time) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(data); err != nil {
+		respondError(w, err)
+		return
+	}
+	sum := sha256.Sum256(buf.Bytes())
+
+	setCacheControl(w, cacheRevalidate)
+	if checkNotModified(w, r, strongETag(hex.EncodeToString(sum[:16])), modTime) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// serveThumbnail writes a decoded thumbnail with validators derived from its
+// bytes, so conditional and range requests work as for blobs.
+func serveThumbnail(w http.ResponseWriter, r *http.Request, thumb *service.Thumbnail) {
+	sum := sha256.Sum256(thumb.Data)
+	w.Header().Set("Content-Type", thumb.ContentType)
+	setCacheControl(w, cacheRevalidate)
+	w.Header().Set("ETag", strongETag(hex.EncodeToString(sum[:16])))
+	http.ServeContent(w, r, "", thumb.ModTime, bytes.NewReader(thumb.Data))
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"github.com/pandasWhoCode/paintbar/internal/service"
+)
+
+// CommentHandler handles gallery comment API endpoints.
+type CommentHandler struct {
+	commentService *service.CommentService
+}
+
+// NewCommentHandler creates a new CommentHandler.
+func NewCommentHandler(commentService *service.CommentService) *CommentHandler {
+	return &CommentHandler{commentService: commentService}
+}
+
+// ListComments handles GET /api/gallery/{id}/comments
+func (h *CommentHandler) ListComments(w http.ResponseWriter, r *http.Request) {
+	if requireUser(w, r) == nil {
+		return
+	}
+
+	limit, startAfter := parsePagination(r)
+
+	comments, err := h.commentService.ListComments(r.Context(), chi.URLParam(r, "id"), limit, startAfter)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondCacheableJSON(w, r, comments, time.Time{})
+}
+
+// CreateComment handles POST /api/gallery/{id}/comments
+func (h *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	var body struct {
+		Body     string `json:"body"`
+		ParentID string `json:"parentId"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+
+	comment := &model.Comment{Body: body.Body, ParentID: body.ParentID}
+	id, err := h.commentService.CreateComment(r.Context(), user.UID, chi.URLParam(r, "id"), comment)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]string{"id": id})
+}
+
+// EditComment handles PUT /api/gallery/{id}/comments/{commentId}
+func (h *CommentHandler) EditComment(w http.ResponseWriter, r *http.Request) {
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	var body struct {
+		Body string `json:"body"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+
+	err := h.commentService.EditComment(r.Context(), user.UID,
+		chi.URLParam(r, "id"), chi.URLParam(r, "commentId"), body.Body)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "updated"})
+}
+
+// DeleteComment handles DELETE /api/gallery/{id}/comments/{commentId}
+func (h *CommentHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	err := h.commentService.DeleteComment(r.Context(), user.UID,
+		chi.URLParam(r, "id"), chi.URLParam(r, "commentId"))
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"github.com/pandasWhoCode/paintbar/internal/service"
+)
+
+// FollowHandler handles public profile, follow graph, and feed endpoints.
+type FollowHandler struct {
+	followService *service.FollowService
+	cursors       *service.CursorCodec
+}
+
+// NewFollowHandler creates a new FollowHandler. cursors signs feed cursors; it may be
+// nil to use a per-process key.
+func NewFollowHandler(followService *service.FollowService, cursors *service.CursorCodec) *FollowHandler {
+	if cursors == nil {
+		cursors = service.NewCursorCodec(nil)
+	}
+	return &FollowHandler{followService: followService, cursors: cursors}
+}
+
+// GetPublicProfile handles GET /api/users/{username}
+func (h *FollowHandler) GetPublicProfile(w http.ResponseWriter, r *http.Request) {
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	profile, err := h.followService.GetPublicProfile(r.Context(), user.UID, chi.URLParam(r, "username"))
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondCacheableJSON(w, r, profile, time.Time{})
+}
+
+// Follow handles POST /api/users/{username}/follow
+func (h *FollowHandler) Follow(w http.ResponseWriter, r *http.Request) {
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	if err := h.followService.Follow(r.Context(), user.UID, chi.URLParam(r, "username")); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "following"})
+}
+
+// Unfollow handles DELETE /api/users/{username}/follow
+func (h *FollowHandler) Unfollow(w http.ResponseWriter, r *http.Request) {
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	if err := h.followService.Unfollow(r.Context(), user.UID, chi.URLParam(r, "username")); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "unfollowed"})
+}
+
+// ListFollowers handles GET /api/users/{username}/followers
+func (h *FollowHandler) ListFollowers(w http.ResponseWriter, r *http.Request) {
+	if requireUser(w, r) == nil {
+		return
+	}
+
+	limit, startAfter := parsePagination(r)
+
+	follows, err := h.followService.ListFollowers(r.Context(), chi.URLParam(r, "username"), limit, startAfter)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondCacheableJSON(w, r, follows, time.Time{})
+}
+
+// ListFollowing handles GET /api/users/{username}/following
+func (h *FollowHandler) ListFollowing(w http.ResponseWriter, r *http.Request) {
+	if requireUser(w, r) == nil {
+		return
+	}
+
+	limit, startAfter := parsePagination(r)
+
+	follows, err := h.followService.ListFollowing(r.Context(), chi.URLParam(r, "username"), limit, startAfter)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondCacheableJSON(w, r, follows, time.Time{})
+}
+
+// FollowingFeed handles GET /api/feed/following
+func (h *FollowHandler) FollowingFeed(w http.ResponseWriter, r *http.Request) {
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	limit, startAfter := parsePagination(r)
+	var after *model.Cursor
+	if startAfter != "" {
+		c, err := h.cursors.Decode(startAfter)
+		if err != nil {
+			respondError(w, err)
+			return
+		}
+		after = c
+	}
+
+	page, err := h.followService.FollowingFeed(r.Context(), user.UID, limit, after)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondFeedPage(w, r, page, h.cursors)
+}
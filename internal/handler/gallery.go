@@ -11,11 +11,16 @@ import (
 // GalleryHandler handles gallery API endpoints.
 type GalleryHandler struct {
 	galleryService *service.GalleryService
+	cursors        *service.CursorCodec
 }
 
-// NewGalleryHandler creates a new GalleryHandler.
-func NewGalleryHandler(galleryService *service.GalleryService) *GalleryHandler {
-	return &GalleryHandler{galleryService: galleryService}
+// NewGalleryHandler creates a new GalleryHandler. cursors signs list cursors; it may be
+// nil to use a per-process key.
+func NewGalleryHandler(galleryService *service.GalleryService, cursors *service.CursorCodec) *GalleryHandler {
+	if cursors == nil {
+		cursors = service.NewCursorCodec(nil)
+	}
+	return &GalleryHandler{galleryService: galleryService, cursors: cursors}
 }
 
 // ListItems handles GET /api/gallery
@@ -25,15 +30,24 @@ func (h *GalleryHandler) ListItems(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	limit, startAfter := parsePagination(r)
+	limit, _ := parsePagination(r)
+	opts, err := parseListOptions(r, h.cursors)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+	fields, ok := parseFields(w, r, model.GallerySummaryFields)
+	if !ok {
+		return
+	}
 
-	items, err := h.galleryService.ListItems(r.Context(), user.UID, limit, startAfter)
+	page, err := h.galleryService.ListItems(r.Context(), user.UID, limit, opts, fields)
 	if err != nil {
 		respondError(w, err)
 		return
 	}
 
-	respondJSON(w, http.StatusOK, items)
+	respondPage(w, r, page, fields, h.cursors)
 }
 
 // GetItem handles GET /api/gallery/{id}
@@ -107,3 +121,20 @@ func (h *GalleryHandler) CountItems(w http.ResponseWriter, r *http.Request) {
 
 	respondJSON(w, http.StatusOK, map[string]int64{"count": count})
 }
+
+// GetThumbnail handles GET /api/gallery/{id}/thumbnail — the gallery item's thumbnail image,
+// decoded from its stored data URL.
+func (h *GalleryHandler) GetThumbnail(w http.ResponseWriter, r *http.Request) {
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	thumb, err := h.galleryService.GetThumbnail(r.Context(), user.UID, chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	serveThumbnail(w, r, thumb)
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/pandasWhoCode/paintbar/internal/graph"
+)
+
+// GraphQLHandler serves the GraphQL endpoint.
+type GraphQLHandler struct {
+	graph *graph.Graph
+}
+
+// NewGraphQLHandler creates a new GraphQLHandler.
+func NewGraphQLHandler(g *graph.Graph) *GraphQLHandler {
+	return &GraphQLHandler{graph: g}
+}
+
+// Query handles POST /api/graphql. A query that ran is answered with 200
+// even if some fields failed, as the errors are reported per field; one
+// rejected before running is a 400.
+func (h *GraphQLHandler) Query(w http.ResponseWriter, r *http.Request) {
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	var req graph.Request
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	res := h.graph.Execute(r.Context(), user.UID, &req)
+	status := http.StatusOK
+	if !res.Executed() {
+		status = http.StatusBadRequest
+	}
+	respondJSON(w, status, res)
+}
@@ -2,17 +2,22 @@ package handler
 
 import (
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pandasWhoCode/paintbar/internal/middleware"
+	"github.com/pandasWhoCode/paintbar/internal/model"
 	"github.com/pandasWhoCode/paintbar/internal/service"
 )
 
 // respondJSON writes a JSON response with the given status code.
-// Sets Cache-Control: no-store to prevent caching of authenticated data.
+// Sets Cache-Control: no-store to prevent caching of authenticated data;
+// see respondCacheableJSON for reads that may be revalidated.
 func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Cache-Control", "no-store")
@@ -90,6 +95,121 @@ func parsePagination(r *http.Request) (limit int, startAfter string) {
 	return
 }
 
+// parseListOptions reads the sort, filter and cursor parameters of a
+// sortable list endpoint. startAfter carries the signed cursor from a
+// previous page's nextCursor. The service validates the options against
+// the collection.
+func parseListOptions(r *http.Request, cursors *service.CursorCodec) (*model.ListOptions, error) {
+	q := r.URL.Query()
+	opts := &model.ListOptions{
+		Sort:  q.Get("sort"),
+		Order: strings.ToLower(q.Get("order")),
+		Tag:   strings.ToLower(strings.TrimSpace(q.Get("tag"))),
+	}
+
+	if v := q.Get("isPublic"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid isPublic: must be true or false")
+		}
+		opts.IsPublic = &b
+	}
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"createdAfter", &opts.CreatedAfter}, {"createdBefore", &opts.CreatedBefore}} {
+		if v := q.Get(p.name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: must be an RFC 3339 timestamp", p.name)
+			}
+			*p.dst = t
+		}
+	}
+	if v := q.Get("startAfter"); v != "" {
+		c, err := cursors.Decode(v)
+		if err != nil {
+			return nil, err
+		}
+		opts.Cursor = c
+	}
+	return opts, nil
+}
+
+// parseFields parses the ?fields= sparse fieldset against a list projection.
+// Returns false and writes a 400 response if it names an unknown field.
+func parseFields(w http.ResponseWriter, r *http.Request, p model.Projection) (model.FieldSet, bool) {
+	fields, err := p.ParseFields(r.URL.Query().Get("fields"))
+	if err != nil {
+		respondError(w, err)
+		return nil, false
+	}
+	return fields, true
+}
+
+// respondPage writes a 200 list envelope with each item trimmed to the
+// fields in set (nil keeps them whole). When more items follow, the next
+// position is signed into nextCursor and also advertised as an RFC 8288
+// Link header: the request URL with startAfter replaced.
+func respondPage(w http.ResponseWriter, r *http.Request, page *model.Page, set model.FieldSet, cursors *service.CursorCodec) {
+	if set != nil {
+		items, err := sparseItems(page.Items, set)
+		if err != nil {
+			respondError(w, err)
+			return
+		}
+		page.Items = items
+	}
+
+	if page.Next != nil {
+		page.NextCursor = nextLink(w, r, page.Next, cursors)
+	}
+	respondJSON(w, http.StatusOK, page)
+}
+
+// respondFeedPage writes a 200 feed page, signing and advertising the next
+// position as respondPage does.
+func respondFeedPage(w http.ResponseWriter, r *http.Request, page *model.FeedPage, cursors *service.CursorCodec) {
+	if page.Next != nil {
+		page.NextCursor = nextLink(w, r, page.Next, cursors)
+	}
+	respondJSON(w, http.StatusOK, page)
+}
+
+// nextLink signs next into a cursor token and adds the RFC 8288 Link header
+// for the following page: the request URL with startAfter replaced.
+func nextLink(w http.ResponseWriter, r *http.Request, next *model.Cursor, cursors *service.CursorCodec) string {
+	token := cursors.Encode(next)
+	q := r.URL.Query()
+	q.Set("startAfter", token)
+	u := url.URL{Path: r.URL.Path, RawQuery: q.Encode()}
+	w.Header().Add("Link", fmt.Sprintf("<%s>; rel=\"next\"", u.String()))
+	return token
+}
+
+// sparseItems returns a list of JSON objects trimmed to the fields in set.
+func sparseItems(items interface{}, set model.FieldSet) ([]map[string]json.RawMessage, error) {
+	raw, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	var objects []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &objects); err != nil {
+		return nil, err
+	}
+
+	sparse := make([]map[string]json.RawMessage, len(objects))
+	for i, obj := range objects {
+		sparse[i] = make(map[string]json.RawMessage, len(set))
+		for _, name := range set {
+			if v, ok := obj[name]; ok {
+				sparse[i][name] = v
+			}
+		}
+	}
+	return sparse, nil
+}
+
 // maxRequestBodySize is the maximum allowed request body size (1 MB).
 const maxRequestBodySize = 1 << 20
 
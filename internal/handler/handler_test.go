@@ -3,20 +3,27 @@ package handler
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"html/template"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"slices"
 	"strings"
 	"testing"
 	"testing/fstest"
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/pandasWhoCode/paintbar/internal/graph"
+	"github.com/pandasWhoCode/paintbar/internal/health"
+	"github.com/pandasWhoCode/paintbar/internal/jobs"
 	"github.com/pandasWhoCode/paintbar/internal/middleware"
 	"github.com/pandasWhoCode/paintbar/internal/model"
+	"github.com/pandasWhoCode/paintbar/internal/repository"
 	"github.com/pandasWhoCode/paintbar/internal/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -43,6 +50,24 @@ func (m *mockUserRepo) GetByID(_ context.Context, uid string) (*model.User, erro
 	}
 	return u, nil
 }
+func (m *mockUserRepo) GetByIDs(_ context.Context, uids []string) ([]*model.User, error) {
+	var result []*model.User
+	for _, id := range uids {
+		if u, ok := m.users[id]; ok {
+			result = append(result, u)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockUserRepo) GetByUsername(_ context.Context, username string) (*model.User, error) {
+	for _, u := range m.users {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return nil, fmt.Errorf("user not found")
+}
 
 func (m *mockUserRepo) Create(_ context.Context, user *model.User) error {
 	m.users[user.UID] = user
@@ -66,6 +91,27 @@ func (m *mockUserRepo) ClaimUsername(_ context.Context, uid string, username str
 	return nil
 }
 
+func (m *mockUserRepo) SetSuspended(_ context.Context, uid string, suspended bool) error {
+	u, ok := m.users[uid]
+	if !ok {
+		return fmt.Errorf("user not found")
+	}
+	u.Suspended = suspended
+	return nil
+}
+
+func (m *mockUserRepo) ReleaseUsername(_ context.Context, username string) (string, error) {
+	uid, ok := m.usernames[username]
+	if !ok {
+		return "", fmt.Errorf("username not found")
+	}
+	delete(m.usernames, username)
+	if u, ok := m.users[uid]; ok {
+		u.Username = ""
+	}
+	return uid, nil
+}
+
 type mockProjectRepo struct {
 	projects map[string]*model.Project
 	counter  int
@@ -82,6 +128,15 @@ func (m *mockProjectRepo) GetByID(_ context.Context, id string) (*model.Project,
 	}
 	return p, nil
 }
+func (m *mockProjectRepo) GetByIDs(_ context.Context, projectIDs []string) ([]*model.Project, error) {
+	var result []*model.Project
+	for _, id := range projectIDs {
+		if p, ok := m.projects[id]; ok {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
 
 func (m *mockProjectRepo) FindByContentHash(_ context.Context, userID, contentHash string) (*model.Project, error) {
 	for _, p := range m.projects {
@@ -101,14 +156,26 @@ func (m *mockProjectRepo) FindByTitle(_ context.Context, userID, title string) (
 	return nil, nil
 }
 
-func (m *mockProjectRepo) List(_ context.Context, userID string, limit int, startAfter string) ([]*model.Project, error) {
+// List returns the user's projects in ID order, with a cursor after the
+// last one when more than limit match.
+func (m *mockProjectRepo) List(_ context.Context, userID string, limit int, opts *model.ListOptions, _ []string) ([]*model.Project, *model.Cursor, error) {
 	var result []*model.Project
 	for _, p := range m.projects {
 		if p.UserID == userID {
 			result = append(result, p)
 		}
 	}
-	return result, nil
+	slices.SortFunc(result, func(a, b *model.Project) int { return strings.Compare(a.ID, b.ID) })
+	if len(result) <= limit {
+		return result, nil, nil
+	}
+	last := result[limit-1]
+	next := &model.Cursor{Sort: opts.Sort, Order: opts.Order, Filter: opts.FilterKey(), Value: last.CreatedAt.UTC().Format(time.RFC3339Nano), ID: last.ID}
+	return result[:limit], next, nil
+}
+
+func (m *mockProjectRepo) CountList(ctx context.Context, userID string, _ *model.ListOptions) (int64, error) {
+	return m.Count(ctx, userID)
 }
 
 func (m *mockProjectRepo) Count(_ context.Context, userID string) (int64, error) {
@@ -167,17 +234,50 @@ func (m *mockGalleryRepo) GetByID(_ context.Context, id string) (*model.GalleryI
 	}
 	return item, nil
 }
+func (m *mockGalleryRepo) GetByIDs(_ context.Context, itemIDs []string) ([]*model.GalleryItem, error) {
+	var result []*model.GalleryItem
+	for _, id := range itemIDs {
+		if item, ok := m.items[id]; ok {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
 
-func (m *mockGalleryRepo) List(_ context.Context, userID string, limit int, startAfter string) ([]*model.GalleryItem, error) {
+func (m *mockGalleryRepo) List(_ context.Context, userID string, limit int, _ *model.ListOptions, _ []string) ([]*model.GalleryItem, *model.Cursor, error) {
 	var result []*model.GalleryItem
 	for _, item := range m.items {
 		if item.UserID == userID {
 			result = append(result, item)
 		}
 	}
+	return result, nil, nil
+}
+
+func (m *mockGalleryRepo) ListByUsers(_ context.Context, userIDs []string, limit int, after *model.Cursor) ([]*model.GalleryItem, error) {
+	before := time.Now()
+	if after != nil {
+		before, _ = time.Parse(time.RFC3339Nano, after.Value)
+	}
+	var result []*model.GalleryItem
+	for _, item := range m.items {
+		for _, id := range userIDs {
+			if item.UserID == id && item.CreatedAt.Before(before) {
+				result = append(result, item)
+			}
+		}
+	}
+	slices.SortFunc(result, func(a, b *model.GalleryItem) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	if len(result) > limit {
+		result = result[:limit]
+	}
 	return result, nil
 }
 
+func (m *mockGalleryRepo) CountList(ctx context.Context, userID string, _ *model.ListOptions) (int64, error) {
+	return m.Count(ctx, userID)
+}
+
 func (m *mockGalleryRepo) Count(_ context.Context, userID string) (int64, error) {
 	var count int64
 	for _, item := range m.items {
@@ -204,6 +304,15 @@ func (m *mockGalleryRepo) Delete(_ context.Context, id string) error {
 	return nil
 }
 
+func (m *mockGalleryRepo) SetHidden(_ context.Context, id string, hidden bool) error {
+	item, ok := m.items[id]
+	if !ok {
+		return fmt.Errorf("gallery item not found")
+	}
+	item.Hidden = hidden
+	return nil
+}
+
 type mockNFTRepo struct {
 	nfts    map[string]*model.NFT
 	counter int
@@ -220,15 +329,28 @@ func (m *mockNFTRepo) GetByID(_ context.Context, id string) (*model.NFT, error)
 	}
 	return nft, nil
 }
+func (m *mockNFTRepo) GetByIDs(_ context.Context, nftIDs []string) ([]*model.NFT, error) {
+	var result []*model.NFT
+	for _, id := range nftIDs {
+		if nft, ok := m.nfts[id]; ok {
+			result = append(result, nft)
+		}
+	}
+	return result, nil
+}
 
-func (m *mockNFTRepo) List(_ context.Context, userID string, limit int, startAfter string) ([]*model.NFT, error) {
+func (m *mockNFTRepo) List(_ context.Context, userID string, limit int, _ *model.ListOptions, _ []string) ([]*model.NFT, *model.Cursor, error) {
 	var result []*model.NFT
 	for _, nft := range m.nfts {
 		if nft.UserID == userID {
 			result = append(result, nft)
 		}
 	}
-	return result, nil
+	return result, nil, nil
+}
+
+func (m *mockNFTRepo) CountList(ctx context.Context, userID string, _ *model.ListOptions) (int64, error) {
+	return m.Count(ctx, userID)
 }
 
 func (m *mockNFTRepo) Count(_ context.Context, userID string) (int64, error) {
@@ -264,8 +386,371 @@ func (m *mockNFTRepo) Delete(_ context.Context, id string) error {
 	return nil
 }
 
+type mockFollowRepo struct {
+	following map[string]map[string]bool
+}
+
+func newMockFollowRepo() *mockFollowRepo {
+	return &mockFollowRepo{following: make(map[string]map[string]bool)}
+}
+
+func (m *mockFollowRepo) Follow(_ context.Context, follower, followee *model.User) error {
+	if m.following[follower.UID] == nil {
+		m.following[follower.UID] = make(map[string]bool)
+	}
+	m.following[follower.UID][followee.UID] = true
+	return nil
+}
+
+func (m *mockFollowRepo) Unfollow(_ context.Context, followerUID, followeeUID string) error {
+	delete(m.following[followerUID], followeeUID)
+	return nil
+}
+
+func (m *mockFollowRepo) IsFollowing(_ context.Context, followerUID, followeeUID string) (bool, error) {
+	return m.following[followerUID][followeeUID], nil
+}
+
+func (m *mockFollowRepo) ListFollowers(_ context.Context, uid string, limit int, startAfter string) ([]*model.Follow, error) {
+	var result []*model.Follow
+	for follower, followees := range m.following {
+		if followees[uid] {
+			result = append(result, &model.Follow{UID: follower})
+		}
+	}
+	return result, nil
+}
+
+func (m *mockFollowRepo) ListFollowing(_ context.Context, uid string, limit int, startAfter string) ([]*model.Follow, error) {
+	var result []*model.Follow
+	for followee := range m.following[uid] {
+		result = append(result, &model.Follow{UID: followee})
+	}
+	return result, nil
+}
+
+func (m *mockFollowRepo) FollowingIDs(_ context.Context, uid string) ([]string, error) {
+	var ids []string
+	for followee := range m.following[uid] {
+		ids = append(ids, followee)
+	}
+	return ids, nil
+}
+
+// mockRepos wires together the mock repositories the social handlers share,
+// so a follow or a gallery item created through one is visible to the others.
+type mockRepos struct {
+	users     *mockUserRepo
+	follows   *mockFollowRepo
+	gallery   *mockGalleryRepo
+	comments  *mockCommentRepo
+	reactions *mockReactionRepo
+	nfts      *mockNFTRepo
+	reports   *mockReportRepo
+	audit     *mockAuditLogger
+	accounts  *mockAccountManager
+}
+
+// newMockRepos returns mock repositories holding an account and a user who
+// has claimed a username for each uid.
+func newMockRepos(uids ...string) *mockRepos {
+	users := newMockUserRepo()
+	accounts := &mockAccountManager{accounts: make(map[string]*model.Account)}
+	for _, uid := range uids {
+		users.users[uid] = &model.User{UID: uid, Email: uid + "@example.com", Username: uid}
+		users.usernames[uid] = uid
+		accounts.accounts[uid] = &model.Account{UID: uid, Email: uid + "@example.com"}
+	}
+	return &mockRepos{
+		users:     users,
+		follows:   newMockFollowRepo(),
+		gallery:   newMockGalleryRepo(),
+		comments:  newMockCommentRepo(),
+		reactions: newMockReactionRepo(),
+		nfts:      newMockNFTRepo(),
+		reports:   newMockReportRepo(),
+		audit:     &mockAuditLogger{},
+		accounts:  accounts,
+	}
+}
+
+type mockCommentRepo struct {
+	comments map[string]*model.Comment
+	counter  int
+}
+
+func newMockCommentRepo() *mockCommentRepo {
+	return &mockCommentRepo{comments: make(map[string]*model.Comment)}
+}
+
+func (m *mockCommentRepo) GetByID(_ context.Context, itemID, commentID string) (*model.Comment, error) {
+	c, ok := m.comments[commentID]
+	if !ok || c.ItemID != itemID {
+		return nil, fmt.Errorf("comment not found")
+	}
+	return c, nil
+}
+
+func (m *mockCommentRepo) List(_ context.Context, itemID string, limit int, startAfter string) ([]*model.Comment, error) {
+	var result []*model.Comment
+	for _, c := range m.comments {
+		if c.ItemID == itemID && !c.Hidden {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockCommentRepo) Create(_ context.Context, itemID string, comment *model.Comment) (string, error) {
+	m.counter++
+	id := fmt.Sprintf("c-%d", m.counter)
+	comment.ID = id
+	comment.ItemID = itemID
+	m.comments[id] = comment
+	return id, nil
+}
+
+func (m *mockCommentRepo) UpdateBody(_ context.Context, itemID, commentID, body string) error {
+	m.comments[commentID].Body = body
+	return nil
+}
+
+func (m *mockCommentRepo) Delete(_ context.Context, itemID, commentID string) error {
+	delete(m.comments, commentID)
+	return nil
+}
+
+func (m *mockCommentRepo) SetHidden(_ context.Context, itemID, commentID string, hidden bool) error {
+	m.comments[commentID].Hidden = hidden
+	return nil
+}
+
+type mockReactionRepo struct {
+	reactions map[string]bool // "itemID|uid|reaction"
+}
+
+func newMockReactionRepo() *mockReactionRepo {
+	return &mockReactionRepo{reactions: make(map[string]bool)}
+}
+
+func (m *mockReactionRepo) Add(_ context.Context, itemID, uid, reaction string) error {
+	m.reactions[itemID+"|"+uid+"|"+reaction] = true
+	return nil
+}
+
+func (m *mockReactionRepo) Remove(_ context.Context, itemID, uid, reaction string) error {
+	delete(m.reactions, itemID+"|"+uid+"|"+reaction)
+	return nil
+}
+
+func (m *mockReactionRepo) ListByUser(_ context.Context, itemID, uid string) ([]string, error) {
+	mine := []string{}
+	for key := range m.reactions {
+		if strings.HasPrefix(key, itemID+"|"+uid+"|") {
+			mine = append(mine, strings.TrimPrefix(key, itemID+"|"+uid+"|"))
+		}
+	}
+	return mine, nil
+}
+
+type mockReportRepo struct {
+	reports map[string]*model.Report
+}
+
+func newMockReportRepo() *mockReportRepo {
+	return &mockReportRepo{reports: make(map[string]*model.Report)}
+}
+
+func (m *mockReportRepo) GetByID(_ context.Context, id string) (*model.Report, error) {
+	r, ok := m.reports[id]
+	if !ok {
+		return nil, fmt.Errorf("report not found")
+	}
+	return r, nil
+}
+
+func (m *mockReportRepo) List(_ context.Context, status string, limit int, startAfter string) ([]*model.Report, error) {
+	result := []*model.Report{}
+	for _, r := range m.reports {
+		if r.Status == status {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockReportRepo) Create(_ context.Context, report *model.Report) (string, error) {
+	id := report.ReporterID + "_" + report.TargetType + "_" + report.TargetID
+	if _, ok := m.reports[id]; ok {
+		return "", fmt.Errorf("report already exists")
+	}
+	report.ID = id
+	m.reports[id] = report
+	return id, nil
+}
+
+func (m *mockReportRepo) Resolve(_ context.Context, id, status, resolvedBy, note string) error {
+	r, ok := m.reports[id]
+	if !ok {
+		return fmt.Errorf("report not found")
+	}
+	r.Status = status
+	r.ResolvedBy = resolvedBy
+	r.Note = note
+	return nil
+}
+
+type mockAuditLogger struct {
+	entries []*model.AuditEntry
+}
+
+func (m *mockAuditLogger) Log(_ context.Context, entry *model.AuditEntry) error {
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+func (m *mockAuditLogger) List(_ context.Context, actorUID string, limit int, startAfter string) ([]*model.AuditEntry, error) {
+	result := []*model.AuditEntry{}
+	for _, e := range m.entries {
+		if actorUID == "" || e.ActorUID == actorUID {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+type mockStatsRepo struct{}
+
+func (m *mockStatsRepo) Usage(_ context.Context) (*model.UsageStats, error) {
+	return &model.UsageStats{Users: 3, GalleryItems: 7}, nil
+}
+
+func (m *mockStatsRepo) RecountUser(_ context.Context, uid string, _ bool) (*model.CounterRepair, error) {
+	return &model.CounterRepair{UID: uid, Stored: &model.UserStats{}, Actual: &model.UserStats{}}, nil
+}
+
+func (m *mockStatsRepo) ListUserIDs(context.Context, int, string) ([]string, error) {
+	return nil, nil
+}
+
+type mockAccountManager struct {
+	accounts map[string]*model.Account
+}
+
+func (m *mockAccountManager) GetAccount(_ context.Context, uid string) (*model.Account, error) {
+	a, ok := m.accounts[uid]
+	if !ok {
+		return nil, fmt.Errorf("account not found")
+	}
+	return a, nil
+}
+
+func (m *mockAccountManager) GetAccountByEmail(_ context.Context, email string) (*model.Account, error) {
+	for _, a := range m.accounts {
+		if a.Email == email {
+			return a, nil
+		}
+	}
+	return nil, fmt.Errorf("account not found")
+}
+
+func (m *mockAccountManager) SetAccountDisabled(_ context.Context, uid string, disabled bool) error {
+	a, ok := m.accounts[uid]
+	if !ok {
+		return fmt.Errorf("account not found")
+	}
+	a.Disabled = disabled
+	return nil
+}
+
+type mockSessionManager struct {
+	cookies   map[string]string // cookie value -> uid
+	createErr error
+	revoked   []string
+}
+
+func newMockSessionManager() *mockSessionManager {
+	return &mockSessionManager{cookies: make(map[string]string)}
+}
+
+func (m *mockSessionManager) CreateSessionCookie(_ context.Context, idToken string, _ time.Duration) (string, error) {
+	if m.createErr != nil {
+		return "", m.createErr
+	}
+	cookie := "cookie-for-" + idToken
+	m.cookies[cookie] = idToken
+	return cookie, nil
+}
+
+func (m *mockSessionManager) VerifySessionCookie(_ context.Context, cookie string) (*service.UserInfo, error) {
+	uid, ok := m.cookies[cookie]
+	if !ok {
+		return nil, fmt.Errorf("invalid session cookie")
+	}
+	return &service.UserInfo{UID: uid}, nil
+}
+
+func (m *mockSessionManager) RevokeSessions(_ context.Context, uid string) error {
+	m.revoked = append(m.revoked, uid)
+	return nil
+}
+
+type mockAPITokenRepo struct {
+	tokens map[string]*model.APIToken
+}
+
+func newMockAPITokenRepo() *mockAPITokenRepo {
+	return &mockAPITokenRepo{tokens: make(map[string]*model.APIToken)}
+}
+
+func (m *mockAPITokenRepo) GetByID(_ context.Context, id string) (*model.APIToken, error) {
+	t, ok := m.tokens[id]
+	if !ok {
+		return nil, fmt.Errorf("api token not found")
+	}
+	return t, nil
+}
+
+func (m *mockAPITokenRepo) GetByHash(_ context.Context, hash string) (*model.APIToken, error) {
+	for _, t := range m.tokens {
+		if t.Hash == hash {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("api token not found")
+}
+
+func (m *mockAPITokenRepo) ListByUser(_ context.Context, uid string) ([]*model.APIToken, error) {
+	var result []*model.APIToken
+	for _, t := range m.tokens {
+		if t.UID == uid {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockAPITokenRepo) Create(_ context.Context, t *model.APIToken) (string, error) {
+	id := fmt.Sprintf("tok%d", len(m.tokens)+1)
+	t.ID = id
+	m.tokens[id] = t
+	return id, nil
+}
+
+func (m *mockAPITokenRepo) Delete(_ context.Context, id string) error {
+	delete(m.tokens, id)
+	return nil
+}
+
+func (m *mockAPITokenRepo) TouchLastUsed(_ context.Context, _ string, _ time.Time) error {
+	return nil
+}
+
 // --- Mock StorageClient ---
 
+// mockObjectUpdated is the last-modified time mock storage reports.
+var mockObjectUpdated = time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+
 type mockStorageClient struct {
 	objects map[string]bool
 }
@@ -293,6 +778,20 @@ func (m *mockStorageClient) ReadObject(_ context.Context, objectPath string) (io
 	return nil, fmt.Errorf("object not found: %s", objectPath)
 }
 
+func (m *mockStorageClient) ReadObjectFrom(_ context.Context, objectPath string, offset int64) (io.ReadCloser, error) {
+	if m.objects[objectPath] {
+		return io.NopCloser(bytes.NewReader([]byte("fake-png-data")[offset:])), nil
+	}
+	return nil, fmt.Errorf("object not found: %s", objectPath)
+}
+
+func (m *mockStorageClient) StatObject(_ context.Context, objectPath string) (*repository.ObjectAttrs, error) {
+	if m.objects[objectPath] {
+		return &repository.ObjectAttrs{Size: int64(len("fake-png-data")), Updated: mockObjectUpdated}, nil
+	}
+	return nil, fmt.Errorf("object not found: %s", objectPath)
+}
+
 func (m *mockStorageClient) WriteObject(_ context.Context, objectPath string, _ io.Reader, _ string) error {
 	m.objects[objectPath] = true
 	return nil
@@ -455,7 +954,7 @@ func TestDecodeJSON_Valid(t *testing.T) {
 func TestGetProfile_Success(t *testing.T) {
 	repo := newMockUserRepo()
 	repo.users["user1"] = &model.User{UID: "user1", Email: "a@b.com", DisplayName: "Alice"}
-	h := NewProfileHandler(service.NewUserService(repo))
+	h := NewProfileHandler(service.NewUserService(repo, nil))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/profile", nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -467,7 +966,7 @@ func TestGetProfile_Success(t *testing.T) {
 }
 
 func TestGetProfile_NoAuth(t *testing.T) {
-	h := NewProfileHandler(service.NewUserService(newMockUserRepo()))
+	h := NewProfileHandler(service.NewUserService(newMockUserRepo(), nil))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/profile", nil)
 	rr := httptest.NewRecorder()
@@ -477,7 +976,7 @@ func TestGetProfile_NoAuth(t *testing.T) {
 }
 
 func TestGetProfile_NotFound(t *testing.T) {
-	h := NewProfileHandler(service.NewUserService(newMockUserRepo()))
+	h := NewProfileHandler(service.NewUserService(newMockUserRepo(), nil))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/profile", nil)
 	req = withUser(req, "nonexistent", "a@b.com")
@@ -490,7 +989,7 @@ func TestGetProfile_NotFound(t *testing.T) {
 func TestUpdateProfile_Success(t *testing.T) {
 	repo := newMockUserRepo()
 	repo.users["user1"] = &model.User{UID: "user1", Email: "a@b.com"}
-	h := NewProfileHandler(service.NewUserService(repo))
+	h := NewProfileHandler(service.NewUserService(repo, nil))
 
 	body := jsonBody(map[string]string{"displayName": "Bob"})
 	req := httptest.NewRequest(http.MethodPut, "/api/profile", body)
@@ -503,7 +1002,7 @@ func TestUpdateProfile_Success(t *testing.T) {
 }
 
 func TestUpdateProfile_NoAuth(t *testing.T) {
-	h := NewProfileHandler(service.NewUserService(newMockUserRepo()))
+	h := NewProfileHandler(service.NewUserService(newMockUserRepo(), nil))
 
 	req := httptest.NewRequest(http.MethodPut, "/api/profile", strings.NewReader("{}"))
 	rr := httptest.NewRecorder()
@@ -513,7 +1012,7 @@ func TestUpdateProfile_NoAuth(t *testing.T) {
 }
 
 func TestUpdateProfile_BadJSON(t *testing.T) {
-	h := NewProfileHandler(service.NewUserService(newMockUserRepo()))
+	h := NewProfileHandler(service.NewUserService(newMockUserRepo(), nil))
 
 	req := httptest.NewRequest(http.MethodPut, "/api/profile", strings.NewReader("{bad"))
 	req = withUser(req, "user1", "a@b.com")
@@ -526,7 +1025,7 @@ func TestUpdateProfile_BadJSON(t *testing.T) {
 func TestUpdateProfile_ValidationError(t *testing.T) {
 	repo := newMockUserRepo()
 	repo.users["user1"] = &model.User{UID: "user1", Email: "a@b.com"}
-	h := NewProfileHandler(service.NewUserService(repo))
+	h := NewProfileHandler(service.NewUserService(repo, nil))
 
 	body := jsonBody(map[string]string{"website": "ftp://bad"})
 	req := httptest.NewRequest(http.MethodPut, "/api/profile", body)
@@ -540,7 +1039,7 @@ func TestUpdateProfile_ValidationError(t *testing.T) {
 func TestUpdateProfile_UseGravatar(t *testing.T) {
 	repo := newMockUserRepo()
 	repo.users["user1"] = &model.User{UID: "user1", Email: "a@b.com"}
-	h := NewProfileHandler(service.NewUserService(repo))
+	h := NewProfileHandler(service.NewUserService(repo, nil))
 
 	body := jsonBody(map[string]interface{}{"useGravatar": true})
 	req := httptest.NewRequest(http.MethodPut, "/api/profile", body)
@@ -555,7 +1054,7 @@ func TestUpdateProfile_UseGravatar(t *testing.T) {
 func TestClaimUsername_Success(t *testing.T) {
 	repo := newMockUserRepo()
 	repo.users["user1"] = &model.User{UID: "user1", Email: "a@b.com"}
-	h := NewProfileHandler(service.NewUserService(repo))
+	h := NewProfileHandler(service.NewUserService(repo, nil))
 
 	body := jsonBody(map[string]string{"username": "cool_user"})
 	req := httptest.NewRequest(http.MethodPost, "/api/claim-username", body)
@@ -568,7 +1067,7 @@ func TestClaimUsername_Success(t *testing.T) {
 }
 
 func TestClaimUsername_NoAuth(t *testing.T) {
-	h := NewProfileHandler(service.NewUserService(newMockUserRepo()))
+	h := NewProfileHandler(service.NewUserService(newMockUserRepo(), nil))
 
 	req := httptest.NewRequest(http.MethodPost, "/api/claim-username", strings.NewReader("{}"))
 	rr := httptest.NewRecorder()
@@ -578,7 +1077,7 @@ func TestClaimUsername_NoAuth(t *testing.T) {
 }
 
 func TestClaimUsername_BadJSON(t *testing.T) {
-	h := NewProfileHandler(service.NewUserService(newMockUserRepo()))
+	h := NewProfileHandler(service.NewUserService(newMockUserRepo(), nil))
 
 	req := httptest.NewRequest(http.MethodPost, "/api/claim-username", strings.NewReader("{bad"))
 	req = withUser(req, "user1", "a@b.com")
@@ -591,7 +1090,7 @@ func TestClaimUsername_BadJSON(t *testing.T) {
 func TestClaimUsername_InvalidFormat(t *testing.T) {
 	repo := newMockUserRepo()
 	repo.users["user1"] = &model.User{UID: "user1", Email: "a@b.com"}
-	h := NewProfileHandler(service.NewUserService(repo))
+	h := NewProfileHandler(service.NewUserService(repo, nil))
 
 	body := jsonBody(map[string]string{"username": "AB"})
 	req := httptest.NewRequest(http.MethodPost, "/api/claim-username", body)
@@ -606,9 +1105,9 @@ func TestClaimUsername_InvalidFormat(t *testing.T) {
 
 func TestListProjects_Success(t *testing.T) {
 	repo := newMockProjectRepo()
-	svc := service.NewProjectService(repo, nil)
+	svc := service.NewProjectService(repo, nil, nil, nil)
 	svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art"})
-	h := NewProjectHandler(svc)
+	h := NewProjectHandler(svc, testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/projects", nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -619,47 +1118,151 @@ func TestListProjects_Success(t *testing.T) {
 	assert.Contains(t, rr.Body.String(), "Art")
 }
 
-func TestListProjects_NoAuth(t *testing.T) {
-	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil))
+func TestListProjects_ReturnsSummaries(t *testing.T) {
+	repo := newMockProjectRepo()
+	svc := service.NewProjectService(repo, nil, nil, nil)
+	id, _ := repo.Create(context.Background(), &model.Project{
+		UserID:        "user1",
+		Title:         "Art",
+		ThumbnailData: "data:image/png;base64,AAAA",
+	})
+	h := NewProjectHandler(svc, testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/projects", nil)
+	req = withUser(req, "user1", "a@b.com")
 	rr := httptest.NewRecorder()
 	h.ListProjects(rr, req)
 
-	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "thumbnailData")
+	assert.Contains(t, rr.Body.String(), `"thumbnailUrl":"/api/v1/projects/`+id+`/thumbnail"`)
 }
 
-func TestGetProject_Success(t *testing.T) {
+func TestListProjects_SparseFields(t *testing.T) {
 	repo := newMockProjectRepo()
-	svc := service.NewProjectService(repo, nil)
-	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art"})
-	id := result.ProjectID
-	h := NewProjectHandler(svc)
+	svc := service.NewProjectService(repo, nil, nil, nil)
+	id, _ := repo.Create(context.Background(), &model.Project{UserID: "user1", Title: "Art", Tags: []string{"x"}})
+	h := NewProjectHandler(svc, testCursors)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/projects/"+id, nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/projects?fields=id,title,thumbnailUrl", nil)
 	req = withUser(req, "user1", "a@b.com")
-	req = chiContext(req, map[string]string{"id": id})
 	rr := httptest.NewRecorder()
-	h.GetProject(rr, req)
-
-	assert.Equal(t, http.StatusOK, rr.Code)
-	assert.Contains(t, rr.Body.String(), "Art")
-}
+	h.ListProjects(rr, req)
 
-func TestGetProject_NotFound(t *testing.T) {
-	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"items":[{"id":"`+id+`","title":"Art","thumbnailUrl":"/api/v1/projects/`+id+`/thumbnail"}],"hasMore":false,"total":1}`, rr.Body.String())
 
-	req := httptest.NewRequest(http.MethodGet, "/api/projects/nope", nil)
+	req = httptest.NewRequest(http.MethodGet, "/api/projects?fields=id,thumbnailData", nil)
 	req = withUser(req, "user1", "a@b.com")
-	req = chiContext(req, map[string]string{"id": "nope"})
-	rr := httptest.NewRecorder()
-	h.GetProject(rr, req)
+	rr = httptest.NewRecorder()
+	h.ListProjects(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "unknown field")
+}
+
+func TestListProjects_Envelope(t *testing.T) {
+	repo := newMockProjectRepo()
+	svc := service.NewProjectService(repo, nil, nil, nil)
+	for _, title := range []string{"A", "B", "C"} {
+		repo.Create(context.Background(), &model.Project{UserID: "user1", Title: title, Tags: []string{"pixel"}})
+	}
+	h := NewProjectHandler(svc, testCursors)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/projects?limit=2&tag=pixel", nil)
+	req = withUser(req, "user1", "a@b.com")
+	rr := httptest.NewRecorder()
+	h.ListProjects(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var page struct {
+		Items      []map[string]interface{} `json:"items"`
+		NextCursor string                   `json:"nextCursor"`
+		HasMore    bool                     `json:"hasMore"`
+		Total      int64                    `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &page))
+	assert.Len(t, page.Items, 2)
+	assert.True(t, page.HasMore)
+	assert.Equal(t, int64(3), page.Total)
+	require.NotEmpty(t, page.NextCursor)
+
+	// The Link header repeats the request with the cursor swapped in
+	next := url.Values{"limit": {"2"}, "tag": {"pixel"}, "startAfter": {page.NextCursor}}
+	assert.Equal(t, `</api/projects?`+next.Encode()+`>; rel="next"`, rr.Header().Get("Link"))
+
+	c, err := testCursors.Decode(page.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, "createdAt", c.Sort)
+	assert.NotEmpty(t, c.Filter, "cursor is bound to the tag filter")
+
+	// The last page has no cursor and no Link
+	req = httptest.NewRequest(http.MethodGet, "/api/projects?limit=5", nil)
+	req = withUser(req, "user1", "a@b.com")
+	rr = httptest.NewRecorder()
+	h.ListProjects(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "nextCursor")
+	assert.Contains(t, rr.Body.String(), `"hasMore":false`)
+	assert.Empty(t, rr.Header().Get("Link"))
+}
+
+func TestListProjects_RejectsForgedCursor(t *testing.T) {
+	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil, nil, nil), testCursors)
+	other := service.NewCursorCodec([]byte("another-deployment-secret-0123456789"))
+	forged := other.Encode(&model.Cursor{Sort: "createdAt", Order: "desc", Value: "2025-01-01T00:00:00Z", ID: "abc"})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/projects?startAfter="+forged, nil)
+	req = withUser(req, "user1", "a@b.com")
+	rr := httptest.NewRecorder()
+	h.ListProjects(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "invalid cursor")
+}
+
+func TestListProjects_NoAuth(t *testing.T) {
+	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil, nil, nil), testCursors)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/projects", nil)
+	rr := httptest.NewRecorder()
+	h.ListProjects(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestGetProject_Success(t *testing.T) {
+	repo := newMockProjectRepo()
+	svc := service.NewProjectService(repo, nil, nil, nil)
+	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art"})
+	id := result.ProjectID
+	h := NewProjectHandler(svc, testCursors)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/projects/"+id, nil)
+	req = withUser(req, "user1", "a@b.com")
+	req = chiContext(req, map[string]string{"id": id})
+	rr := httptest.NewRecorder()
+	h.GetProject(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Art")
+}
+
+func TestGetProject_NotFound(t *testing.T) {
+	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil, nil, nil), testCursors)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/projects/nope", nil)
+	req = withUser(req, "user1", "a@b.com")
+	req = chiContext(req, map[string]string{"id": "nope"})
+	rr := httptest.NewRecorder()
+	h.GetProject(rr, req)
 
 	assert.Equal(t, http.StatusNotFound, rr.Code)
 }
 
 func TestCreateProject_Success(t *testing.T) {
-	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil))
+	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil, nil, nil), testCursors)
 
 	body := jsonBody(map[string]string{"title": "New Art"})
 	req := httptest.NewRequest(http.MethodPost, "/api/projects", body)
@@ -672,7 +1275,7 @@ func TestCreateProject_Success(t *testing.T) {
 }
 
 func TestCreateProject_NoAuth(t *testing.T) {
-	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil))
+	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil, nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/projects", strings.NewReader("{}"))
 	rr := httptest.NewRecorder()
@@ -682,7 +1285,7 @@ func TestCreateProject_NoAuth(t *testing.T) {
 }
 
 func TestCreateProject_BadJSON(t *testing.T) {
-	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil))
+	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil, nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/projects", strings.NewReader("{bad"))
 	req = withUser(req, "user1", "a@b.com")
@@ -693,7 +1296,7 @@ func TestCreateProject_BadJSON(t *testing.T) {
 }
 
 func TestCreateProject_ValidationFails(t *testing.T) {
-	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil))
+	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil, nil, nil), testCursors)
 
 	body := jsonBody(map[string]string{"title": ""})
 	req := httptest.NewRequest(http.MethodPost, "/api/projects", body)
@@ -706,10 +1309,10 @@ func TestCreateProject_ValidationFails(t *testing.T) {
 
 func TestUpdateProject_Success(t *testing.T) {
 	repo := newMockProjectRepo()
-	svc := service.NewProjectService(repo, nil)
+	svc := service.NewProjectService(repo, nil, nil, nil)
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art"})
 	id := result.ProjectID
-	h := NewProjectHandler(svc)
+	h := NewProjectHandler(svc, testCursors)
 
 	body := jsonBody(map[string]string{"title": "Updated"})
 	req := httptest.NewRequest(http.MethodPut, "/api/projects/"+id, body)
@@ -722,7 +1325,7 @@ func TestUpdateProject_Success(t *testing.T) {
 }
 
 func TestUpdateProject_NoAuth(t *testing.T) {
-	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil))
+	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil, nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodPut, "/api/projects/x", strings.NewReader("{}"))
 	rr := httptest.NewRecorder()
@@ -732,7 +1335,7 @@ func TestUpdateProject_NoAuth(t *testing.T) {
 }
 
 func TestUpdateProject_BadJSON(t *testing.T) {
-	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil))
+	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil, nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodPut, "/api/projects/x", strings.NewReader("{bad"))
 	req = withUser(req, "user1", "a@b.com")
@@ -745,10 +1348,10 @@ func TestUpdateProject_BadJSON(t *testing.T) {
 
 func TestDeleteProject_Success(t *testing.T) {
 	repo := newMockProjectRepo()
-	svc := service.NewProjectService(repo, nil)
+	svc := service.NewProjectService(repo, nil, nil, nil)
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art"})
 	id := result.ProjectID
-	h := NewProjectHandler(svc)
+	h := NewProjectHandler(svc, testCursors)
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/projects/"+id, nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -761,7 +1364,7 @@ func TestDeleteProject_Success(t *testing.T) {
 }
 
 func TestDeleteProject_NoAuth(t *testing.T) {
-	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil))
+	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil, nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/projects/x", nil)
 	rr := httptest.NewRecorder()
@@ -771,7 +1374,7 @@ func TestDeleteProject_NoAuth(t *testing.T) {
 }
 
 func TestDeleteProject_NotFound(t *testing.T) {
-	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil))
+	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil, nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/projects/nope", nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -784,10 +1387,10 @@ func TestDeleteProject_NotFound(t *testing.T) {
 
 func TestCountProjects_Success(t *testing.T) {
 	repo := newMockProjectRepo()
-	svc := service.NewProjectService(repo, nil)
+	svc := service.NewProjectService(repo, nil, nil, nil)
 	svc.CreateProject(context.Background(), "user1", &model.Project{Title: "A"})
 	svc.CreateProject(context.Background(), "user1", &model.Project{Title: "B"})
-	h := NewProjectHandler(svc)
+	h := NewProjectHandler(svc, testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/projects/count", nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -799,7 +1402,7 @@ func TestCountProjects_Success(t *testing.T) {
 }
 
 func TestCountProjects_NoAuth(t *testing.T) {
-	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil))
+	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil, nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/projects/count", nil)
 	rr := httptest.NewRecorder()
@@ -812,9 +1415,9 @@ func TestCountProjects_NoAuth(t *testing.T) {
 
 func TestListGallery_Success(t *testing.T) {
 	repo := newMockGalleryRepo()
-	svc := service.NewGalleryService(repo)
+	svc := service.NewGalleryService(repo, nil, nil)
 	svc.ShareToGallery(context.Background(), "user1", &model.GalleryItem{Name: "Sunset"})
-	h := NewGalleryHandler(svc)
+	h := NewGalleryHandler(svc, testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/gallery", nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -826,7 +1429,7 @@ func TestListGallery_Success(t *testing.T) {
 }
 
 func TestListGallery_NoAuth(t *testing.T) {
-	h := NewGalleryHandler(service.NewGalleryService(newMockGalleryRepo()))
+	h := NewGalleryHandler(service.NewGalleryService(newMockGalleryRepo(), nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/gallery", nil)
 	rr := httptest.NewRecorder()
@@ -837,9 +1440,9 @@ func TestListGallery_NoAuth(t *testing.T) {
 
 func TestGetGalleryItem_Success(t *testing.T) {
 	repo := newMockGalleryRepo()
-	svc := service.NewGalleryService(repo)
+	svc := service.NewGalleryService(repo, nil, nil)
 	id, _ := svc.ShareToGallery(context.Background(), "user1", &model.GalleryItem{Name: "Art"})
-	h := NewGalleryHandler(svc)
+	h := NewGalleryHandler(svc, testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/gallery/"+id, nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -851,7 +1454,7 @@ func TestGetGalleryItem_Success(t *testing.T) {
 }
 
 func TestGetGalleryItem_NotFound(t *testing.T) {
-	h := NewGalleryHandler(service.NewGalleryService(newMockGalleryRepo()))
+	h := NewGalleryHandler(service.NewGalleryService(newMockGalleryRepo(), nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/gallery/nope", nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -863,7 +1466,7 @@ func TestGetGalleryItem_NotFound(t *testing.T) {
 }
 
 func TestShareToGallery_Success(t *testing.T) {
-	h := NewGalleryHandler(service.NewGalleryService(newMockGalleryRepo()))
+	h := NewGalleryHandler(service.NewGalleryService(newMockGalleryRepo(), nil, nil), testCursors)
 
 	body := jsonBody(map[string]string{"name": "Sunset"})
 	req := httptest.NewRequest(http.MethodPost, "/api/gallery", body)
@@ -876,7 +1479,7 @@ func TestShareToGallery_Success(t *testing.T) {
 }
 
 func TestShareToGallery_NoAuth(t *testing.T) {
-	h := NewGalleryHandler(service.NewGalleryService(newMockGalleryRepo()))
+	h := NewGalleryHandler(service.NewGalleryService(newMockGalleryRepo(), nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/gallery", strings.NewReader("{}"))
 	rr := httptest.NewRecorder()
@@ -886,7 +1489,7 @@ func TestShareToGallery_NoAuth(t *testing.T) {
 }
 
 func TestShareToGallery_BadJSON(t *testing.T) {
-	h := NewGalleryHandler(service.NewGalleryService(newMockGalleryRepo()))
+	h := NewGalleryHandler(service.NewGalleryService(newMockGalleryRepo(), nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/gallery", strings.NewReader("{bad"))
 	req = withUser(req, "user1", "a@b.com")
@@ -897,7 +1500,7 @@ func TestShareToGallery_BadJSON(t *testing.T) {
 }
 
 func TestShareToGallery_ValidationFails(t *testing.T) {
-	h := NewGalleryHandler(service.NewGalleryService(newMockGalleryRepo()))
+	h := NewGalleryHandler(service.NewGalleryService(newMockGalleryRepo(), nil, nil), testCursors)
 
 	body := jsonBody(map[string]string{"name": ""})
 	req := httptest.NewRequest(http.MethodPost, "/api/gallery", body)
@@ -910,9 +1513,9 @@ func TestShareToGallery_ValidationFails(t *testing.T) {
 
 func TestDeleteGalleryItem_Success(t *testing.T) {
 	repo := newMockGalleryRepo()
-	svc := service.NewGalleryService(repo)
+	svc := service.NewGalleryService(repo, nil, nil)
 	id, _ := svc.ShareToGallery(context.Background(), "user1", &model.GalleryItem{Name: "Art"})
-	h := NewGalleryHandler(svc)
+	h := NewGalleryHandler(svc, testCursors)
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/gallery/"+id, nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -924,7 +1527,7 @@ func TestDeleteGalleryItem_Success(t *testing.T) {
 }
 
 func TestDeleteGalleryItem_NoAuth(t *testing.T) {
-	h := NewGalleryHandler(service.NewGalleryService(newMockGalleryRepo()))
+	h := NewGalleryHandler(service.NewGalleryService(newMockGalleryRepo(), nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/gallery/x", nil)
 	rr := httptest.NewRecorder()
@@ -935,9 +1538,9 @@ func TestDeleteGalleryItem_NoAuth(t *testing.T) {
 
 func TestCountGallery_Success(t *testing.T) {
 	repo := newMockGalleryRepo()
-	svc := service.NewGalleryService(repo)
+	svc := service.NewGalleryService(repo, nil, nil)
 	svc.ShareToGallery(context.Background(), "user1", &model.GalleryItem{Name: "A"})
-	h := NewGalleryHandler(svc)
+	h := NewGalleryHandler(svc, testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/gallery/count", nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -949,7 +1552,7 @@ func TestCountGallery_Success(t *testing.T) {
 }
 
 func TestCountGallery_NoAuth(t *testing.T) {
-	h := NewGalleryHandler(service.NewGalleryService(newMockGalleryRepo()))
+	h := NewGalleryHandler(service.NewGalleryService(newMockGalleryRepo(), nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/gallery/count", nil)
 	rr := httptest.NewRecorder()
@@ -962,9 +1565,9 @@ func TestCountGallery_NoAuth(t *testing.T) {
 
 func TestListNFTs_Success(t *testing.T) {
 	repo := newMockNFTRepo()
-	svc := service.NewNFTService(repo)
+	svc := service.NewNFTService(repo, nil, nil)
 	svc.CreateNFT(context.Background(), "user1", &model.NFT{Name: "CoolNFT"})
-	h := NewNFTHandler(svc)
+	h := NewNFTHandler(svc, testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/nfts", nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -976,7 +1579,7 @@ func TestListNFTs_Success(t *testing.T) {
 }
 
 func TestListNFTs_NoAuth(t *testing.T) {
-	h := NewNFTHandler(service.NewNFTService(newMockNFTRepo()))
+	h := NewNFTHandler(service.NewNFTService(newMockNFTRepo(), nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/nfts", nil)
 	rr := httptest.NewRecorder()
@@ -987,9 +1590,9 @@ func TestListNFTs_NoAuth(t *testing.T) {
 
 func TestGetNFT_Success(t *testing.T) {
 	repo := newMockNFTRepo()
-	svc := service.NewNFTService(repo)
+	svc := service.NewNFTService(repo, nil, nil)
 	id, _ := svc.CreateNFT(context.Background(), "user1", &model.NFT{Name: "NFT"})
-	h := NewNFTHandler(svc)
+	h := NewNFTHandler(svc, testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/nfts/"+id, nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -1001,7 +1604,7 @@ func TestGetNFT_Success(t *testing.T) {
 }
 
 func TestGetNFT_NotFound(t *testing.T) {
-	h := NewNFTHandler(service.NewNFTService(newMockNFTRepo()))
+	h := NewNFTHandler(service.NewNFTService(newMockNFTRepo(), nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/nfts/nope", nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -1013,7 +1616,7 @@ func TestGetNFT_NotFound(t *testing.T) {
 }
 
 func TestCreateNFT_Success(t *testing.T) {
-	h := NewNFTHandler(service.NewNFTService(newMockNFTRepo()))
+	h := NewNFTHandler(service.NewNFTService(newMockNFTRepo(), nil, nil), testCursors)
 
 	body := jsonBody(map[string]interface{}{"name": "NewNFT", "price": 5.0})
 	req := httptest.NewRequest(http.MethodPost, "/api/nfts", body)
@@ -1026,7 +1629,7 @@ func TestCreateNFT_Success(t *testing.T) {
 }
 
 func TestCreateNFT_NoAuth(t *testing.T) {
-	h := NewNFTHandler(service.NewNFTService(newMockNFTRepo()))
+	h := NewNFTHandler(service.NewNFTService(newMockNFTRepo(), nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/nfts", strings.NewReader("{}"))
 	rr := httptest.NewRecorder()
@@ -1036,7 +1639,7 @@ func TestCreateNFT_NoAuth(t *testing.T) {
 }
 
 func TestCreateNFT_BadJSON(t *testing.T) {
-	h := NewNFTHandler(service.NewNFTService(newMockNFTRepo()))
+	h := NewNFTHandler(service.NewNFTService(newMockNFTRepo(), nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/nfts", strings.NewReader("{bad"))
 	req = withUser(req, "user1", "a@b.com")
@@ -1047,7 +1650,7 @@ func TestCreateNFT_BadJSON(t *testing.T) {
 }
 
 func TestCreateNFT_ValidationFails(t *testing.T) {
-	h := NewNFTHandler(service.NewNFTService(newMockNFTRepo()))
+	h := NewNFTHandler(service.NewNFTService(newMockNFTRepo(), nil, nil), testCursors)
 
 	body := jsonBody(map[string]interface{}{"name": "", "price": -1})
 	req := httptest.NewRequest(http.MethodPost, "/api/nfts", body)
@@ -1060,9 +1663,9 @@ func TestCreateNFT_ValidationFails(t *testing.T) {
 
 func TestDeleteNFT_Success(t *testing.T) {
 	repo := newMockNFTRepo()
-	svc := service.NewNFTService(repo)
+	svc := service.NewNFTService(repo, nil, nil)
 	id, _ := svc.CreateNFT(context.Background(), "user1", &model.NFT{Name: "NFT"})
-	h := NewNFTHandler(svc)
+	h := NewNFTHandler(svc, testCursors)
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/nfts/"+id, nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -1074,7 +1677,7 @@ func TestDeleteNFT_Success(t *testing.T) {
 }
 
 func TestDeleteNFT_NoAuth(t *testing.T) {
-	h := NewNFTHandler(service.NewNFTService(newMockNFTRepo()))
+	h := NewNFTHandler(service.NewNFTService(newMockNFTRepo(), nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/nfts/x", nil)
 	rr := httptest.NewRecorder()
@@ -1085,10 +1688,10 @@ func TestDeleteNFT_NoAuth(t *testing.T) {
 
 func TestCountNFTs_Success(t *testing.T) {
 	repo := newMockNFTRepo()
-	svc := service.NewNFTService(repo)
+	svc := service.NewNFTService(repo, nil, nil)
 	svc.CreateNFT(context.Background(), "user1", &model.NFT{Name: "A"})
 	svc.CreateNFT(context.Background(), "user1", &model.NFT{Name: "B"})
-	h := NewNFTHandler(svc)
+	h := NewNFTHandler(svc, testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/nfts/count", nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -1100,7 +1703,7 @@ func TestCountNFTs_Success(t *testing.T) {
 }
 
 func TestCountNFTs_NoAuth(t *testing.T) {
-	h := NewNFTHandler(service.NewNFTService(newMockNFTRepo()))
+	h := NewNFTHandler(service.NewNFTService(newMockNFTRepo(), nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/nfts/count", nil)
 	rr := httptest.NewRecorder()
@@ -1111,13 +1714,22 @@ func TestCountNFTs_NoAuth(t *testing.T) {
 
 // --- Additional error path tests ---
 
+// testCursors signs list cursors in handler tests.
+var testCursors = service.NewCursorCodec([]byte("handler-test-cursor-secret-0123456789"))
+
+// testCursor signs a cursor for the default sort positioned after id.
+func testCursor(id string) string {
+	c := &model.Cursor{Sort: "createdAt", Order: "desc", Value: "2025-01-01T00:00:00Z", ID: id}
+	return testCursors.Encode(c)
+}
+
 func TestListProjects_WithPagination(t *testing.T) {
 	repo := newMockProjectRepo()
-	svc := service.NewProjectService(repo, nil)
+	svc := service.NewProjectService(repo, nil, nil, nil)
 	svc.CreateProject(context.Background(), "user1", &model.Project{Title: "A"})
-	h := NewProjectHandler(svc)
+	h := NewProjectHandler(svc, testCursors)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/projects?limit=5&startAfter=abc", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/projects?limit=5&startAfter="+testCursor("abc"), nil)
 	req = withUser(req, "user1", "a@b.com")
 	rr := httptest.NewRecorder()
 	h.ListProjects(rr, req)
@@ -1126,7 +1738,7 @@ func TestListProjects_WithPagination(t *testing.T) {
 }
 
 func TestGetProject_NoAuth(t *testing.T) {
-	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil))
+	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil, nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/projects/x", nil)
 	rr := httptest.NewRecorder()
@@ -1136,7 +1748,7 @@ func TestGetProject_NoAuth(t *testing.T) {
 }
 
 func TestGetGalleryItem_NoAuth(t *testing.T) {
-	h := NewGalleryHandler(service.NewGalleryService(newMockGalleryRepo()))
+	h := NewGalleryHandler(service.NewGalleryService(newMockGalleryRepo(), nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/gallery/x", nil)
 	rr := httptest.NewRecorder()
@@ -1146,7 +1758,7 @@ func TestGetGalleryItem_NoAuth(t *testing.T) {
 }
 
 func TestGetNFT_NoAuth(t *testing.T) {
-	h := NewNFTHandler(service.NewNFTService(newMockNFTRepo()))
+	h := NewNFTHandler(service.NewNFTService(newMockNFTRepo(), nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/nfts/x", nil)
 	rr := httptest.NewRecorder()
@@ -1156,7 +1768,7 @@ func TestGetNFT_NoAuth(t *testing.T) {
 }
 
 func TestDeleteGalleryItem_NotFound(t *testing.T) {
-	h := NewGalleryHandler(service.NewGalleryService(newMockGalleryRepo()))
+	h := NewGalleryHandler(service.NewGalleryService(newMockGalleryRepo(), nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/gallery/nope", nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -1168,7 +1780,7 @@ func TestDeleteGalleryItem_NotFound(t *testing.T) {
 }
 
 func TestDeleteNFT_NotFound(t *testing.T) {
-	h := NewNFTHandler(service.NewNFTService(newMockNFTRepo()))
+	h := NewNFTHandler(service.NewNFTService(newMockNFTRepo(), nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/nfts/nope", nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -1184,8 +1796,8 @@ func TestDeleteNFT_NotFound(t *testing.T) {
 func TestCreateProject_StorageURL_Stripped(t *testing.T) {
 	// Verify that a client-supplied storageURL is zeroed out (Fix #8)
 	repo := newMockProjectRepo()
-	svc := service.NewProjectService(repo, nil)
-	h := NewProjectHandler(svc)
+	svc := service.NewProjectService(repo, nil, nil, nil)
+	h := NewProjectHandler(svc, testCursors)
 
 	body := jsonBody(map[string]interface{}{
 		"title":      "Injected",
@@ -1208,10 +1820,10 @@ func TestCreateProject_StorageURL_Stripped(t *testing.T) {
 
 func TestUpdateProject_ValidationRejectsLongTitle(t *testing.T) {
 	repo := newMockProjectRepo()
-	svc := service.NewProjectService(repo, nil)
+	svc := service.NewProjectService(repo, nil, nil, nil)
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art"})
 	id := result.ProjectID
-	h := NewProjectHandler(svc)
+	h := NewProjectHandler(svc, testCursors)
 
 	longTitle := string(make([]byte, 201))
 	body := jsonBody(map[string]string{"title": longTitle})
@@ -1227,10 +1839,10 @@ func TestUpdateProject_ValidationRejectsLongTitle(t *testing.T) {
 
 func TestUpdateProject_ValidationRejectsTooManyTags(t *testing.T) {
 	repo := newMockProjectRepo()
-	svc := service.NewProjectService(repo, nil)
+	svc := service.NewProjectService(repo, nil, nil, nil)
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art"})
 	id := result.ProjectID
-	h := NewProjectHandler(svc)
+	h := NewProjectHandler(svc, testCursors)
 
 	tags := make([]string, 21)
 	body := jsonBody(map[string]interface{}{"tags": tags})
@@ -1247,7 +1859,7 @@ func TestUpdateProject_ValidationRejectsTooManyTags(t *testing.T) {
 func TestConfirmUpload_Success(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := newMockStorageClient()
-	svc := service.NewProjectService(repo, storage)
+	svc := service.NewProjectService(repo, storage, nil, nil)
 
 	hash := "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{
@@ -1257,7 +1869,7 @@ func TestConfirmUpload_Success(t *testing.T) {
 	// Simulate blob upload
 	storage.objects["projects/user1/"+hash+".png"] = true
 
-	h := NewProjectHandler(svc)
+	h := NewProjectHandler(svc, testCursors)
 	req := httptest.NewRequest(http.MethodPost, "/api/projects/"+result.ProjectID+"/confirm-upload", nil)
 	req = withUser(req, "user1", "a@b.com")
 	req = chiContext(req, map[string]string{"id": result.ProjectID})
@@ -1269,7 +1881,7 @@ func TestConfirmUpload_Success(t *testing.T) {
 }
 
 func TestConfirmUpload_NoAuth(t *testing.T) {
-	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), newMockStorageClient()))
+	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), newMockStorageClient(), nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/projects/x/confirm-upload", nil)
 	rr := httptest.NewRecorder()
@@ -1279,7 +1891,7 @@ func TestConfirmUpload_NoAuth(t *testing.T) {
 }
 
 func TestConfirmUpload_NotFound(t *testing.T) {
-	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), newMockStorageClient()))
+	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), newMockStorageClient(), nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/projects/nope/confirm-upload", nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -1293,12 +1905,12 @@ func TestConfirmUpload_NotFound(t *testing.T) {
 func TestConfirmUpload_Unauthorized(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := newMockStorageClient()
-	svc := service.NewProjectService(repo, storage)
+	svc := service.NewProjectService(repo, storage, nil, nil)
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{
 		Title:       "Art",
 		ContentHash: "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2",
 	})
-	h := NewProjectHandler(svc)
+	h := NewProjectHandler(svc, testCursors)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/projects/"+result.ProjectID+"/confirm-upload", nil)
 	req = withUser(req, "attacker", "evil@b.com")
@@ -1312,12 +1924,12 @@ func TestConfirmUpload_Unauthorized(t *testing.T) {
 func TestConfirmUpload_NotUploaded(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := newMockStorageClient()
-	svc := service.NewProjectService(repo, storage)
+	svc := service.NewProjectService(repo, storage, nil, nil)
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{
 		Title:       "Art",
 		ContentHash: "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2",
 	})
-	h := NewProjectHandler(svc)
+	h := NewProjectHandler(svc, testCursors)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/projects/"+result.ProjectID+"/confirm-upload", nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -1330,10 +1942,10 @@ func TestConfirmUpload_NotUploaded(t *testing.T) {
 
 func TestUpdateProject_ValidationRejectsEmptyTitle(t *testing.T) {
 	repo := newMockProjectRepo()
-	svc := service.NewProjectService(repo, nil)
+	svc := service.NewProjectService(repo, nil, nil, nil)
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art"})
 	id := result.ProjectID
-	h := NewProjectHandler(svc)
+	h := NewProjectHandler(svc, testCursors)
 
 	body := jsonBody(map[string]string{"title": ""})
 	req := httptest.NewRequest(http.MethodPut, "/api/projects/"+id, body)
@@ -1347,8 +1959,8 @@ func TestUpdateProject_ValidationRejectsEmptyTitle(t *testing.T) {
 }
 
 func TestCreateProject_BadThumbnail_Rejected(t *testing.T) {
-	svc := service.NewProjectService(newMockProjectRepo(), nil)
-	h := NewProjectHandler(svc)
+	svc := service.NewProjectService(newMockProjectRepo(), nil, nil, nil)
+	h := NewProjectHandler(svc, testCursors)
 
 	body := jsonBody(map[string]string{
 		"title":         "Art",
@@ -1364,7 +1976,7 @@ func TestCreateProject_BadThumbnail_Rejected(t *testing.T) {
 }
 
 func TestUpdateProject_NotFound(t *testing.T) {
-	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil))
+	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil, nil, nil), testCursors)
 
 	body := jsonBody(map[string]string{"title": "Updated"})
 	req := httptest.NewRequest(http.MethodPut, "/api/projects/nope", body)
@@ -1377,9 +1989,9 @@ func TestUpdateProject_NotFound(t *testing.T) {
 }
 
 func TestListGallery_WithPagination(t *testing.T) {
-	h := NewGalleryHandler(service.NewGalleryService(newMockGalleryRepo()))
+	h := NewGalleryHandler(service.NewGalleryService(newMockGalleryRepo(), nil, nil), testCursors)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/gallery?limit=20&startAfter=xyz", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/gallery?limit=20&startAfter="+testCursor("xyz"), nil)
 	req = withUser(req, "user1", "a@b.com")
 	rr := httptest.NewRecorder()
 	h.ListItems(rr, req)
@@ -1388,9 +2000,9 @@ func TestListGallery_WithPagination(t *testing.T) {
 }
 
 func TestListNFTs_WithPagination(t *testing.T) {
-	h := NewNFTHandler(service.NewNFTService(newMockNFTRepo()))
+	h := NewNFTHandler(service.NewNFTService(newMockNFTRepo(), nil, nil), testCursors)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/nfts?limit=20&startAfter=xyz", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/nfts?limit=20&startAfter="+testCursor("xyz"), nil)
 	req = withUser(req, "user1", "a@b.com")
 	rr := httptest.NewRecorder()
 	h.ListNFTs(rr, req)
@@ -1398,13 +2010,53 @@ func TestListNFTs_WithPagination(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rr.Code)
 }
 
+func TestListProjects_SortAndFilterOptions(t *testing.T) {
+	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil, nil, nil), testCursors)
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{"defaults", "", http.StatusOK},
+		{"sort by title", "?sort=title&order=asc", http.StatusOK},
+		{"filters", "?tag=pixel&isPublic=true&createdAfter=2025-01-01T00:00:00Z", http.StatusOK},
+		{"unknown sort", "?sort=contentHash", http.StatusBadRequest},
+		{"bad order", "?order=sideways", http.StatusBadRequest},
+		{"bad isPublic", "?isPublic=maybe", http.StatusBadRequest},
+		{"bad timestamp", "?createdBefore=yesterday", http.StatusBadRequest},
+		{"range needs createdAt sort", "?sort=title&createdAfter=2025-01-01T00:00:00Z", http.StatusBadRequest},
+		{"raw document ID cursor", "?startAfter=abc", http.StatusBadRequest},
+		{"cursor from another sort", "?sort=title&startAfter=" + testCursor("abc"), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := withUser(httptest.NewRequest(http.MethodGet, "/api/projects"+tt.query, nil), "user1", "a@b.com")
+			rr := httptest.NewRecorder()
+			h.ListProjects(rr, req)
+			assert.Equal(t, tt.want, rr.Code, rr.Body.String())
+		})
+	}
+}
+
+func TestListGallery_RejectsUnsupportedFilter(t *testing.T) {
+	h := NewGalleryHandler(service.NewGalleryService(newMockGalleryRepo(), nil, nil), testCursors)
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/api/gallery?isPublic=true", nil), "user1", "a@b.com")
+	rr := httptest.NewRecorder()
+	h.ListItems(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "cannot be filtered by isPublic")
+}
+
 // --- GetProjectByTitle handler tests ---
 
 func TestGetProjectByTitle_Success(t *testing.T) {
 	repo := newMockProjectRepo()
-	svc := service.NewProjectService(repo, nil)
+	svc := service.NewProjectService(repo, nil, nil, nil)
 	svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Sunset"})
-	h := NewProjectHandler(svc)
+	h := NewProjectHandler(svc, testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/projects/by-title?title=Sunset", nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -1416,7 +2068,7 @@ func TestGetProjectByTitle_Success(t *testing.T) {
 }
 
 func TestGetProjectByTitle_MissingTitle(t *testing.T) {
-	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil))
+	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil, nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/projects/by-title", nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -1427,7 +2079,7 @@ func TestGetProjectByTitle_MissingTitle(t *testing.T) {
 }
 
 func TestGetProjectByTitle_NotFound(t *testing.T) {
-	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil))
+	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil, nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/projects/by-title?title=Nope", nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -1438,7 +2090,7 @@ func TestGetProjectByTitle_NotFound(t *testing.T) {
 }
 
 func TestGetProjectByTitle_NoAuth(t *testing.T) {
-	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil))
+	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), nil, nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/projects/by-title?title=Art", nil)
 	rr := httptest.NewRecorder()
@@ -1452,7 +2104,7 @@ func TestGetProjectByTitle_NoAuth(t *testing.T) {
 func TestDownloadBlob_Success(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := newMockStorageClient()
-	svc := service.NewProjectService(repo, storage)
+	svc := service.NewProjectService(repo, storage, nil, nil)
 
 	hash := "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{
@@ -1460,7 +2112,7 @@ func TestDownloadBlob_Success(t *testing.T) {
 	})
 	storage.objects["projects/user1/"+hash+".png"] = true
 
-	h := NewProjectHandler(svc)
+	h := NewProjectHandler(svc, testCursors)
 	req := httptest.NewRequest(http.MethodGet, "/api/projects/"+result.ProjectID+"/blob", nil)
 	req = withUser(req, "user1", "a@b.com")
 	req = chiContext(req, map[string]string{"id": result.ProjectID})
@@ -1469,12 +2121,102 @@ func TestDownloadBlob_Success(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 	assert.Equal(t, "image/png", rr.Header().Get("Content-Type"))
-	assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+	assert.Equal(t, "private, no-cache", rr.Header().Get("Cache-Control"))
+	assert.Equal(t, `"`+hash+`"`, rr.Header().Get("ETag"))
+	assert.Equal(t, "Sat, 01 Feb 2025 12:00:00 GMT", rr.Header().Get("Last-Modified"))
+	assert.Equal(t, "bytes", rr.Header().Get("Accept-Ranges"))
+	assert.Equal(t, "fake-png-data", rr.Body.String())
+}
+
+// blobTestRequest creates a project with a stored blob and returns a handler
+// and a function building authenticated blob requests.
+func blobTestRequest(t *testing.T) (*ProjectHandler, string, func(target string, headers map[string]string) *http.Request) {
+	t.Helper()
+	repo := newMockProjectRepo()
+	storage := newMockStorageClient()
+	svc := service.NewProjectService(repo, storage, nil, nil)
+
+	hash := "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
+	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art", ContentHash: hash})
+	storage.objects["projects/user1/"+hash+".png"] = true
+
+	build := func(target string, headers map[string]string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		req = withUser(req, "user1", "a@b.com")
+		return chiContext(req, map[string]string{"id": result.ProjectID})
+	}
+	return NewProjectHandler(svc, testCursors), hash, build
+}
+
+func TestDownloadBlob_Conditional(t *testing.T) {
+	h, hash, build := blobTestRequest(t)
+
+	rr := httptest.NewRecorder()
+	h.DownloadBlob(rr, build("/api/projects/p/blob", map[string]string{"If-None-Match": `"` + hash + `"`}))
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Empty(t, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	h.DownloadBlob(rr, build("/api/projects/p/blob", map[string]string{"If-None-Match": `"stale"`}))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	h.DownloadBlob(rr, build("/api/projects/p/blob", map[string]string{"If-Modified-Since": "Sat, 01 Feb 2025 12:00:00 GMT"}))
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+
+	rr = httptest.NewRecorder()
+	h.DownloadBlob(rr, build("/api/projects/p/blob", map[string]string{"If-Modified-Since": "Fri, 31 Jan 2025 12:00:00 GMT"}))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestDownloadBlob_Range(t *testing.T) {
+	h, hash, build := blobTestRequest(t)
+
+	rr := httptest.NewRecorder()
+	h.DownloadBlob(rr, build("/api/projects/p/blob", map[string]string{"Range": "bytes=5-7"}))
+	assert.Equal(t, http.StatusPartialContent, rr.Code)
+	assert.Equal(t, "bytes 5-7/13", rr.Header().Get("Content-Range"))
+	assert.Equal(t, "png", rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	h.DownloadBlob(rr, build("/api/projects/p/blob", map[string]string{"Range": "bytes=-4"}))
+	assert.Equal(t, http.StatusPartialContent, rr.Code)
+	assert.Equal(t, "data", rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	h.DownloadBlob(rr, build("/api/projects/p/blob", map[string]string{"Range": "bytes=50-"}))
+	assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, rr.Code)
+
+	// If-Range with a stale ETag returns the whole blob
+	rr = httptest.NewRecorder()
+	h.DownloadBlob(rr, build("/api/projects/p/blob", map[string]string{"Range": "bytes=5-7", "If-Range": `"old"`}))
+	assert.Equal(t, http.StatusOK, rr.Code)
 	assert.Equal(t, "fake-png-data", rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	h.DownloadBlob(rr, build("/api/projects/p/blob", map[string]string{"Range": "bytes=5-7", "If-Range": `"` + hash + `"`}))
+	assert.Equal(t, http.StatusPartialContent, rr.Code)
+}
+
+func TestDownloadBlob_ContentAddressedURL(t *testing.T) {
+	h, hash, build := blobTestRequest(t)
+
+	rr := httptest.NewRecorder()
+	h.DownloadBlob(rr, build("/api/projects/p/blob?v="+hash, nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "private, max-age=31536000, immutable", rr.Header().Get("Cache-Control"))
+
+	// A stale version must not be cached as immutable
+	rr = httptest.NewRecorder()
+	h.DownloadBlob(rr, build("/api/projects/p/blob?v=old", nil))
+	assert.Equal(t, "private, no-cache", rr.Header().Get("Cache-Control"))
 }
 
 func TestDownloadBlob_NoAuth(t *testing.T) {
-	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), newMockStorageClient()))
+	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), newMockStorageClient(), nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/projects/x/blob", nil)
 	rr := httptest.NewRecorder()
@@ -1484,7 +2226,7 @@ func TestDownloadBlob_NoAuth(t *testing.T) {
 }
 
 func TestDownloadBlob_NotFound(t *testing.T) {
-	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), newMockStorageClient()))
+	h := NewProjectHandler(service.NewProjectService(newMockProjectRepo(), newMockStorageClient(), nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/projects/nope/blob", nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -1498,10 +2240,10 @@ func TestDownloadBlob_NotFound(t *testing.T) {
 func TestDownloadBlob_Unauthorized(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := newMockStorageClient()
-	svc := service.NewProjectService(repo, storage)
+	svc := service.NewProjectService(repo, storage, nil, nil)
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art"})
 
-	h := NewProjectHandler(svc)
+	h := NewProjectHandler(svc, testCursors)
 	req := httptest.NewRequest(http.MethodGet, "/api/projects/"+result.ProjectID+"/blob", nil)
 	req = withUser(req, "attacker", "evil@b.com")
 	req = chiContext(req, map[string]string{"id": result.ProjectID})
@@ -1515,8 +2257,11 @@ func TestDownloadBlob_Unauthorized(t *testing.T) {
 
 type failingProjectRepo struct{ mockProjectRepo }
 
-func (m *failingProjectRepo) List(_ context.Context, _ string, _ int, _ string) ([]*model.Project, error) {
-	return nil, fmt.Errorf("firestore unavailable")
+func (m *failingProjectRepo) List(_ context.Context, _ string, _ int, _ *model.ListOptions, _ []string) ([]*model.Project, *model.Cursor, error) {
+	return nil, nil, fmt.Errorf("firestore unavailable")
+}
+func (m *failingProjectRepo) CountList(_ context.Context, _ string, _ *model.ListOptions) (int64, error) {
+	return 0, fmt.Errorf("firestore unavailable")
 }
 func (m *failingProjectRepo) Count(_ context.Context, _ string) (int64, error) {
 	return 0, fmt.Errorf("firestore unavailable")
@@ -1524,8 +2269,11 @@ func (m *failingProjectRepo) Count(_ context.Context, _ string) (int64, error) {
 
 type failingGalleryRepo struct{ mockGalleryRepo }
 
-func (m *failingGalleryRepo) List(_ context.Context, _ string, _ int, _ string) ([]*model.GalleryItem, error) {
-	return nil, fmt.Errorf("firestore unavailable")
+func (m *failingGalleryRepo) List(_ context.Context, _ string, _ int, _ *model.ListOptions, _ []string) ([]*model.GalleryItem, *model.Cursor, error) {
+	return nil, nil, fmt.Errorf("firestore unavailable")
+}
+func (m *failingGalleryRepo) CountList(_ context.Context, _ string, _ *model.ListOptions) (int64, error) {
+	return 0, fmt.Errorf("firestore unavailable")
 }
 func (m *failingGalleryRepo) Count(_ context.Context, _ string) (int64, error) {
 	return 0, fmt.Errorf("firestore unavailable")
@@ -1533,15 +2281,18 @@ func (m *failingGalleryRepo) Count(_ context.Context, _ string) (int64, error) {
 
 type failingNFTRepo struct{ mockNFTRepo }
 
-func (m *failingNFTRepo) List(_ context.Context, _ string, _ int, _ string) ([]*model.NFT, error) {
-	return nil, fmt.Errorf("firestore unavailable")
+func (m *failingNFTRepo) List(_ context.Context, _ string, _ int, _ *model.ListOptions, _ []string) ([]*model.NFT, *model.Cursor, error) {
+	return nil, nil, fmt.Errorf("firestore unavailable")
+}
+func (m *failingNFTRepo) CountList(_ context.Context, _ string, _ *model.ListOptions) (int64, error) {
+	return 0, fmt.Errorf("firestore unavailable")
 }
 func (m *failingNFTRepo) Count(_ context.Context, _ string) (int64, error) {
 	return 0, fmt.Errorf("firestore unavailable")
 }
 
 func TestListProjects_ServiceError(t *testing.T) {
-	h := NewProjectHandler(service.NewProjectService(&failingProjectRepo{}, nil))
+	h := NewProjectHandler(service.NewProjectService(&failingProjectRepo{}, nil, nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/projects", nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -1552,7 +2303,7 @@ func TestListProjects_ServiceError(t *testing.T) {
 }
 
 func TestCountProjects_ServiceError(t *testing.T) {
-	h := NewProjectHandler(service.NewProjectService(&failingProjectRepo{}, nil))
+	h := NewProjectHandler(service.NewProjectService(&failingProjectRepo{}, nil, nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/projects/count", nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -1563,7 +2314,7 @@ func TestCountProjects_ServiceError(t *testing.T) {
 }
 
 func TestListGallery_ServiceError(t *testing.T) {
-	h := NewGalleryHandler(service.NewGalleryService(&failingGalleryRepo{}))
+	h := NewGalleryHandler(service.NewGalleryService(&failingGalleryRepo{}, nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/gallery", nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -1574,7 +2325,7 @@ func TestListGallery_ServiceError(t *testing.T) {
 }
 
 func TestCountGallery_ServiceError(t *testing.T) {
-	h := NewGalleryHandler(service.NewGalleryService(&failingGalleryRepo{}))
+	h := NewGalleryHandler(service.NewGalleryService(&failingGalleryRepo{}, nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/gallery/count", nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -1585,7 +2336,7 @@ func TestCountGallery_ServiceError(t *testing.T) {
 }
 
 func TestListNFTs_ServiceError(t *testing.T) {
-	h := NewNFTHandler(service.NewNFTService(&failingNFTRepo{}))
+	h := NewNFTHandler(service.NewNFTService(&failingNFTRepo{}, nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/nfts", nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -1596,7 +2347,7 @@ func TestListNFTs_ServiceError(t *testing.T) {
 }
 
 func TestCountNFTs_ServiceError(t *testing.T) {
-	h := NewNFTHandler(service.NewNFTService(&failingNFTRepo{}))
+	h := NewNFTHandler(service.NewNFTService(&failingNFTRepo{}, nil, nil), testCursors)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/nfts/count", nil)
 	req = withUser(req, "user1", "a@b.com")
@@ -1740,7 +2491,7 @@ func TestTemplateRenderer_Render_NotFound(t *testing.T) {
 
 func TestPageHandler_Login(t *testing.T) {
 	renderer, _ := NewTemplateRenderer(testTemplatesFS())
-	h := NewPageHandler(renderer, "local")
+	h := NewPageHandler(renderer, "local", nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
 	rr := httptest.NewRecorder()
@@ -1752,7 +2503,7 @@ func TestPageHandler_Login(t *testing.T) {
 
 func TestPageHandler_Profile(t *testing.T) {
 	renderer, _ := NewTemplateRenderer(testTemplatesFS())
-	h := NewPageHandler(renderer, "local")
+	h := NewPageHandler(renderer, "local", nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/profile", nil)
 	rr := httptest.NewRecorder()
@@ -1764,7 +2515,7 @@ func TestPageHandler_Profile(t *testing.T) {
 
 func TestPageHandler_Projects(t *testing.T) {
 	renderer, _ := NewTemplateRenderer(testTemplatesFS())
-	h := NewPageHandler(renderer, "local")
+	h := NewPageHandler(renderer, "local", nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/projects", nil)
 	rr := httptest.NewRecorder()
@@ -1776,7 +2527,7 @@ func TestPageHandler_Projects(t *testing.T) {
 
 func TestPageHandler_Canvas(t *testing.T) {
 	renderer, _ := NewTemplateRenderer(testTemplatesFS())
-	h := NewPageHandler(renderer, "local")
+	h := NewPageHandler(renderer, "local", nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/canvas", nil)
 	rr := httptest.NewRecorder()
@@ -1788,7 +2539,7 @@ func TestPageHandler_Canvas(t *testing.T) {
 
 func TestPageHandler_NotFound(t *testing.T) {
 	renderer, _ := NewTemplateRenderer(testTemplatesFS())
-	h := NewPageHandler(renderer, "local")
+	h := NewPageHandler(renderer, "local", nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/nope", nil)
 	rr := httptest.NewRecorder()
@@ -1798,6 +2549,68 @@ func TestPageHandler_NotFound(t *testing.T) {
 	assert.Contains(t, rr.Body.String(), "404")
 }
 
+func TestPageHandler_Profile_WithSession(t *testing.T) {
+	renderer, _ := NewTemplateRenderer(testTemplatesFS())
+	repo := newMockUserRepo()
+	repo.users["user1"] = &model.User{UID: "user1", Email: "a@b.com", Username: "artist", DisplayName: "Ada <3"}
+	h := NewPageHandler(renderer, "local", service.NewUserService(repo, nil))
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/profile", nil), "user1", "a@b.com")
+	rr := httptest.NewRecorder()
+	h.Profile(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Hello Ada &lt;3")
+}
+
+func TestPageHandler_Profile_WithSessionNoProfile(t *testing.T) {
+	renderer, _ := NewTemplateRenderer(testTemplatesFS())
+	h := NewPageHandler(renderer, "local", service.NewUserService(newMockUserRepo(), nil))
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/profile", nil), "new-user", "new@b.com")
+	rr := httptest.NewRecorder()
+	h.Profile(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Hello new@b.com")
+}
+
+func TestPageHandler_Profile_Anonymous(t *testing.T) {
+	renderer, _ := NewTemplateRenderer(testTemplatesFS())
+	h := NewPageHandler(renderer, "local", service.NewUserService(newMockUserRepo(), nil))
+
+	req := httptest.NewRequest(http.MethodGet, "/profile", nil)
+	rr := httptest.NewRecorder()
+	h.Profile(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "Hello")
+}
+
+func TestPageHandler_PageDataIncludesCSRFToken(t *testing.T) {
+	renderer, _ := NewTemplateRenderer(testTemplatesFS())
+	h := NewPageHandler(renderer, "local", nil)
+
+	var data PageData
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data = h.pageData(r, "Profile")
+	})
+	sessions := newMockSessionManager()
+	sessions.cookies["good"] = "user1"
+	req := httptest.NewRequest(http.MethodGet, "/profile", nil)
+	req.AddCookie(&http.Cookie{Name: middleware.SessionCookieName, Value: "good"})
+	chain := middleware.OptionalSession(sessions, false)(middleware.CSRFToken(middleware.NewCSRFKey(nil))(next))
+	chain.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Len(t, data.CSRFToken, 64)
+}
+
+func TestPageUser_Name(t *testing.T) {
+	assert.Equal(t, "Ada", (&PageUser{Email: "a@b.com", Username: "ada", DisplayName: "Ada"}).Name())
+	assert.Equal(t, "ada", (&PageUser{Email: "a@b.com", Username: "ada"}).Name())
+	assert.Equal(t, "a@b.com", (&PageUser{Email: "a@b.com"}).Name())
+}
+
 func TestTemplateRenderer_Render_ExecuteError(t *testing.T) {
 	// Create a template that will error during execution by calling a missing function
 	badTmpl := template.Must(template.New("base.html").Parse(`{{call .BadFunc}}`))
@@ -1825,7 +2638,7 @@ func TestCurrentYear(t *testing.T) {
 func testTemplatesFS() fstest.MapFS {
 	base := `<!DOCTYPE html><html><head><title>{{block "title" .}}PaintBar{{end}}</title>{{block "head" .}}{{end}}</head><body>{{block "body" .}}{{end}}{{block "scripts" .}}{{end}}</body></html>`
 	login := `{{define "title"}}Login{{end}}{{define "head"}}{{end}}{{define "body"}}<h1>Login</h1>{{end}}{{define "scripts"}}{{end}}`
-	profile := `{{define "title"}}Profile{{end}}{{define "head"}}{{end}}{{define "body"}}<h1>Profile</h1>{{end}}{{define "scripts"}}{{end}}`
+	profile := `{{define "title"}}Profile{{end}}{{define "head"}}{{end}}{{define "body"}}<h1>Profile</h1>{{if .User}}<p>Hello {{.User.Name}}</p>{{end}}{{end}}{{define "scripts"}}{{end}}`
 	projects := `{{define "title"}}Projects{{end}}{{define "head"}}{{end}}{{define "body"}}<h1>Projects</h1>{{end}}{{define "scripts"}}{{end}}`
 	canvas := `{{define "title"}}Canvas{{end}}{{define "head"}}{{end}}{{define "body"}}<h1>Canvas</h1>{{end}}{{define "scripts"}}{{end}}`
 	notFound := `{{define "title"}}404{{end}}{{define "head"}}{{end}}{{define "body"}}<h1>404</h1>{{end}}{{define "scripts"}}{{end}}`
@@ -1839,3 +2652,1239 @@ func testTemplatesFS() fstest.MapFS {
 		"templates/pages/404.html":      &fstest.MapFile{Data: []byte(notFound)},
 	}
 }
+
+// --- Follow handler tests ---
+
+func TestGetPublicProfile_Success(t *testing.T) {
+	repos := newMockRepos("alice", "bob")
+	h := NewFollowHandler(service.NewFollowService(repos.users, repos.follows, repos.gallery), testCursors)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users/bob", nil)
+	req = withUser(req, "alice", "a@b.com")
+	req = chiContext(req, map[string]string{"username": "bob"})
+	rr := httptest.NewRecorder()
+	h.GetPublicProfile(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"followerCount"`)
+	assert.NotContains(t, rr.Body.String(), "bob@example.com")
+}
+
+func TestGetPublicProfile_NotFound(t *testing.T) {
+	repos := newMockRepos("alice", "bob")
+	h := NewFollowHandler(service.NewFollowService(repos.users, repos.follows, repos.gallery), testCursors)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users/nobody", nil)
+	req = withUser(req, "alice", "a@b.com")
+	req = chiContext(req, map[string]string{"username": "nobody"})
+	rr := httptest.NewRecorder()
+	h.GetPublicProfile(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestFollow_Success(t *testing.T) {
+	repos := newMockRepos("alice", "bob")
+	h := NewFollowHandler(service.NewFollowService(repos.users, repos.follows, repos.gallery), testCursors)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/users/bob/follow", nil)
+	req = withUser(req, "alice", "a@b.com")
+	req = chiContext(req, map[string]string{"username": "bob"})
+	rr := httptest.NewRecorder()
+	h.Follow(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "following")
+}
+
+func TestFollow_Self(t *testing.T) {
+	repos := newMockRepos("alice", "bob")
+	h := NewFollowHandler(service.NewFollowService(repos.users, repos.follows, repos.gallery), testCursors)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/users/alice/follow", nil)
+	req = withUser(req, "alice", "a@b.com")
+	req = chiContext(req, map[string]string{"username": "alice"})
+	rr := httptest.NewRecorder()
+	h.Follow(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestFollow_NoAuth(t *testing.T) {
+	repos := newMockRepos("alice", "bob")
+	h := NewFollowHandler(service.NewFollowService(repos.users, repos.follows, repos.gallery), testCursors)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/users/bob/follow", nil)
+	rr := httptest.NewRecorder()
+	h.Follow(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestUnfollow_Success(t *testing.T) {
+	repos := newMockRepos("alice", "bob")
+	h := NewFollowHandler(service.NewFollowService(repos.users, repos.follows, repos.gallery), testCursors)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/users/bob/follow", nil)
+	req = withUser(req, "alice", "a@b.com")
+	req = chiContext(req, map[string]string{"username": "bob"})
+	rr := httptest.NewRecorder()
+	h.Unfollow(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "unfollowed")
+}
+
+func TestListFollowers_Success(t *testing.T) {
+	repos := newMockRepos("alice", "bob")
+	h := NewFollowHandler(service.NewFollowService(repos.users, repos.follows, repos.gallery), testCursors)
+
+	follow := httptest.NewRequest(http.MethodPost, "/api/users/bob/follow", nil)
+	follow = withUser(follow, "alice", "a@b.com")
+	follow = chiContext(follow, map[string]string{"username": "bob"})
+	h.Follow(httptest.NewRecorder(), follow)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users/bob/followers", nil)
+	req = withUser(req, "alice", "a@b.com")
+	req = chiContext(req, map[string]string{"username": "bob"})
+	rr := httptest.NewRecorder()
+	h.ListFollowers(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "alice")
+}
+
+func TestListFollowing_NoAuth(t *testing.T) {
+	repos := newMockRepos("alice", "bob")
+	h := NewFollowHandler(service.NewFollowService(repos.users, repos.follows, repos.gallery), testCursors)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users/bob/following", nil)
+	rr := httptest.NewRecorder()
+	h.ListFollowing(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestFollowingFeed_Success(t *testing.T) {
+	repos := newMockRepos("alice", "bob")
+	h := NewFollowHandler(service.NewFollowService(repos.users, repos.follows, repos.gallery), testCursors)
+	repos.gallery.items["g1"] = &model.GalleryItem{ID: "g1", UserID: "bob", Name: "Sunset", CreatedAt: time.Now().Add(-time.Hour)}
+
+	follow := httptest.NewRequest(http.MethodPost, "/api/users/bob/follow", nil)
+	follow = withUser(follow, "alice", "a@b.com")
+	follow = chiContext(follow, map[string]string{"username": "bob"})
+	h.Follow(httptest.NewRecorder(), follow)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/feed/following", nil)
+	req = withUser(req, "alice", "a@b.com")
+	rr := httptest.NewRecorder()
+	h.FollowingFeed(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Sunset")
+	assert.Contains(t, rr.Body.String(), `"hasMore":false`)
+}
+
+func TestFollowingFeed_NoAuth(t *testing.T) {
+	repos := newMockRepos("alice", "bob")
+	h := NewFollowHandler(service.NewFollowService(repos.users, repos.follows, repos.gallery), testCursors)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/feed/following", nil)
+	rr := httptest.NewRecorder()
+	h.FollowingFeed(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestFollowingFeed_InvalidCursor(t *testing.T) {
+	repos := newMockRepos("alice", "bob")
+	h := NewFollowHandler(service.NewFollowService(repos.users, repos.follows, repos.gallery), testCursors)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/feed/following?startAfter=gal-1", nil)
+	req = withUser(req, "alice", "a@b.com")
+	rr := httptest.NewRecorder()
+	h.FollowingFeed(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+// --- Comment handler tests ---
+
+func TestCreateComment_Success(t *testing.T) {
+	repos := newMockRepos("owner", "fan")
+	repos.gallery.items["gal-1"] = &model.GalleryItem{ID: "gal-1", UserID: "owner", Name: "Art"}
+	h := NewCommentHandler(service.NewCommentService(repos.gallery, repos.comments, repos.users))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/gallery/gal-1/comments", jsonBody(map[string]string{"body": "Great!"}))
+	req = withUser(req, "fan", "f@b.com")
+	req = chiContext(req, map[string]string{"id": "gal-1"})
+	rr := httptest.NewRecorder()
+	h.CreateComment(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"id"`)
+}
+
+func TestCreateComment_EmptyBody(t *testing.T) {
+	repos := newMockRepos("owner", "fan")
+	repos.gallery.items["gal-1"] = &model.GalleryItem{ID: "gal-1", UserID: "owner", Name: "Art"}
+	h := NewCommentHandler(service.NewCommentService(repos.gallery, repos.comments, repos.users))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/gallery/gal-1/comments", jsonBody(map[string]string{"body": "  "}))
+	req = withUser(req, "fan", "f@b.com")
+	req = chiContext(req, map[string]string{"id": "gal-1"})
+	rr := httptest.NewRecorder()
+	h.CreateComment(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestCreateComment_NoAuth(t *testing.T) {
+	repos := newMockRepos("owner", "fan")
+	repos.gallery.items["gal-1"] = &model.GalleryItem{ID: "gal-1", UserID: "owner", Name: "Art"}
+	h := NewCommentHandler(service.NewCommentService(repos.gallery, repos.comments, repos.users))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/gallery/gal-1/comments", jsonBody(map[string]string{"body": "hi"}))
+	rr := httptest.NewRecorder()
+	h.CreateComment(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestListComments_Success(t *testing.T) {
+	repos := newMockRepos("owner", "fan")
+	repos.gallery.items["gal-1"] = &model.GalleryItem{ID: "gal-1", UserID: "owner", Name: "Art"}
+	h := NewCommentHandler(service.NewCommentService(repos.gallery, repos.comments, repos.users))
+	repos.comments.Create(context.Background(), "gal-1", &model.Comment{UserID: "fan", Body: "Lovely"})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/gallery/gal-1/comments", nil)
+	req = withUser(req, "owner", "o@b.com")
+	req = chiContext(req, map[string]string{"id": "gal-1"})
+	rr := httptest.NewRecorder()
+	h.ListComments(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Lovely")
+}
+
+func TestEditComment_NotAuthor(t *testing.T) {
+	repos := newMockRepos("owner", "fan")
+	repos.gallery.items["gal-1"] = &model.GalleryItem{ID: "gal-1", UserID: "owner", Name: "Art"}
+	h := NewCommentHandler(service.NewCommentService(repos.gallery, repos.comments, repos.users))
+	id, _ := repos.comments.Create(context.Background(), "gal-1", &model.Comment{UserID: "fan", Body: "Lovely"})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/gallery/gal-1/comments/"+id, jsonBody(map[string]string{"body": "edited"}))
+	req = withUser(req, "owner", "o@b.com")
+	req = chiContext(req, map[string]string{"id": "gal-1", "commentId": id})
+	rr := httptest.NewRecorder()
+	h.EditComment(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestDeleteComment_ByItemOwner(t *testing.T) {
+	repos := newMockRepos("owner", "fan")
+	repos.gallery.items["gal-1"] = &model.GalleryItem{ID: "gal-1", UserID: "owner", Name: "Art"}
+	h := NewCommentHandler(service.NewCommentService(repos.gallery, repos.comments, repos.users))
+	id, _ := repos.comments.Create(context.Background(), "gal-1", &model.Comment{UserID: "fan", Body: "Lovely"})
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/gallery/gal-1/comments/"+id, nil)
+	req = withUser(req, "owner", "o@b.com")
+	req = chiContext(req, map[string]string{"id": "gal-1", "commentId": id})
+	rr := httptest.NewRecorder()
+	h.DeleteComment(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, repos.comments.comments)
+}
+
+// --- Reaction handler tests ---
+
+func TestAddReaction_Success(t *testing.T) {
+	repos := newMockRepos()
+	repos.gallery.items["gal-1"] = &model.GalleryItem{ID: "gal-1", UserID: "owner", Name: "Art", ReactionCounts: map[string]int64{"heart": 3}}
+	h := NewReactionHandler(service.NewReactionService(repos.gallery, repos.reactions))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/gallery/gal-1/reactions", jsonBody(map[string]string{"reaction": "heart"}))
+	req = withUser(req, "fan", "f@b.com")
+	req = chiContext(req, map[string]string{"id": "gal-1"})
+	rr := httptest.NewRecorder()
+	h.AddReaction(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestAddReaction_Invalid(t *testing.T) {
+	repos := newMockRepos()
+	repos.gallery.items["gal-1"] = &model.GalleryItem{ID: "gal-1", UserID: "owner", Name: "Art", ReactionCounts: map[string]int64{"heart": 3}}
+	h := NewReactionHandler(service.NewReactionService(repos.gallery, repos.reactions))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/gallery/gal-1/reactions", jsonBody(map[string]string{"reaction": "<script>"}))
+	req = withUser(req, "fan", "f@b.com")
+	req = chiContext(req, map[string]string{"id": "gal-1"})
+	rr := httptest.NewRecorder()
+	h.AddReaction(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestGetReactions_Success(t *testing.T) {
+	repos := newMockRepos()
+	repos.gallery.items["gal-1"] = &model.GalleryItem{ID: "gal-1", UserID: "owner", Name: "Art", ReactionCounts: map[string]int64{"heart": 3}}
+	h := NewReactionHandler(service.NewReactionService(repos.gallery, repos.reactions))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/gallery/gal-1/reactions", nil)
+	req = withUser(req, "fan", "f@b.com")
+	req = chiContext(req, map[string]string{"id": "gal-1"})
+	rr := httptest.NewRecorder()
+	h.GetReactions(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"heart":3`)
+}
+
+func TestRemoveReaction_NoAuth(t *testing.T) {
+	repos := newMockRepos()
+	repos.gallery.items["gal-1"] = &model.GalleryItem{ID: "gal-1", UserID: "owner", Name: "Art", ReactionCounts: map[string]int64{"heart": 3}}
+	h := NewReactionHandler(service.NewReactionService(repos.gallery, repos.reactions))
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/gallery/gal-1/reactions/heart", nil)
+	rr := httptest.NewRecorder()
+	h.RemoveReaction(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+// --- Moderation handler tests ---
+
+func TestCreateReport_Success(t *testing.T) {
+	repos := newMockRepos("admin", "troll")
+	repos.gallery.items["gal-1"] = &model.GalleryItem{ID: "gal-1", UserID: "troll", Name: "Bad"}
+	h := NewModerationHandler(service.NewModerationService(repos.reports, repos.users, repos.gallery, repos.comments, repos.nfts, repos.audit))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/reports",
+		jsonBody(map[string]string{"targetType": "gallery", "targetId": "gal-1", "reason": "spam"}))
+	req = withUser(req, "fan", "f@b.com")
+	rr := httptest.NewRecorder()
+	h.CreateReport(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Len(t, repos.reports.reports, 1)
+}
+
+func TestCreateReport_Duplicate(t *testing.T) {
+	repos := newMockRepos("admin", "troll")
+	h := NewModerationHandler(service.NewModerationService(repos.reports, repos.users, repos.gallery, repos.comments, repos.nfts, repos.audit))
+	body := map[string]string{"targetType": "user", "targetId": "troll", "reason": "harassment"}
+
+	for _, want := range []int{http.StatusCreated, http.StatusConflict} {
+		req := httptest.NewRequest(http.MethodPost, "/api/reports", jsonBody(body))
+		req = withUser(req, "fan", "f@b.com")
+		rr := httptest.NewRecorder()
+		h.CreateReport(rr, req)
+		assert.Equal(t, want, rr.Code)
+	}
+}
+
+func TestCreateReport_InvalidReason(t *testing.T) {
+	repos := newMockRepos("admin", "troll")
+	repos.gallery.items["gal-1"] = &model.GalleryItem{ID: "gal-1", UserID: "troll", Name: "Bad"}
+	h := NewModerationHandler(service.NewModerationService(repos.reports, repos.users, repos.gallery, repos.comments, repos.nfts, repos.audit))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/reports",
+		jsonBody(map[string]string{"targetType": "gallery", "targetId": "gal-1", "reason": "meh"}))
+	req = withUser(req, "fan", "f@b.com")
+	rr := httptest.NewRecorder()
+	h.CreateReport(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestCreateReport_NoAuth(t *testing.T) {
+	repos := newMockRepos("admin", "troll")
+	h := NewModerationHandler(service.NewModerationService(repos.reports, repos.users, repos.gallery, repos.comments, repos.nfts, repos.audit))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/reports", jsonBody(map[string]string{}))
+	rr := httptest.NewRecorder()
+	h.CreateReport(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestListReports_Success(t *testing.T) {
+	repos := newMockRepos("admin", "troll")
+	repos.gallery.items["gal-1"] = &model.GalleryItem{ID: "gal-1", UserID: "troll", Name: "Bad"}
+	h := NewModerationHandler(service.NewModerationService(repos.reports, repos.users, repos.gallery, repos.comments, repos.nfts, repos.audit))
+	repos.reports.reports["r1"] = &model.Report{ID: "r1", Status: model.ReportStatusOpen, TargetType: "gallery", TargetID: "gal-1"}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/reports?status=open", nil)
+	req = withUser(req, "admin", "a@b.com")
+	rr := httptest.NewRecorder()
+	h.ListReports(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"r1"`)
+}
+
+func TestResolveReport_InvalidStatus(t *testing.T) {
+	repos := newMockRepos("admin", "troll")
+	h := NewModerationHandler(service.NewModerationService(repos.reports, repos.users, repos.gallery, repos.comments, repos.nfts, repos.audit))
+	repos.reports.reports["r1"] = &model.Report{ID: "r1", Status: model.ReportStatusOpen}
+
+	req := httptest.NewRequest(http.MethodPut, "/api/admin/reports/r1", jsonBody(map[string]string{"status": "closed"}))
+	req = withUser(req, "admin", "a@b.com")
+	req = chiContext(req, map[string]string{"id": "r1"})
+	rr := httptest.NewRecorder()
+	h.ResolveReport(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestSetGalleryItemHidden_Success(t *testing.T) {
+	repos := newMockRepos("admin", "troll")
+	repos.gallery.items["gal-1"] = &model.GalleryItem{ID: "gal-1", UserID: "troll", Name: "Bad"}
+	h := NewModerationHandler(service.NewModerationService(repos.reports, repos.users, repos.gallery, repos.comments, repos.nfts, repos.audit))
+
+	req := httptest.NewRequest(http.MethodPut, "/api/admin/gallery/gal-1/hidden", jsonBody(map[string]bool{"hidden": true}))
+	req = withUser(req, "admin", "a@b.com")
+	req = chiContext(req, map[string]string{"id": "gal-1"})
+	rr := httptest.NewRecorder()
+	h.SetGalleryItemHidden(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, repos.gallery.items["gal-1"].Hidden)
+}
+
+func TestSetGalleryItemHidden_MissingField(t *testing.T) {
+	repos := newMockRepos("admin", "troll")
+	repos.gallery.items["gal-1"] = &model.GalleryItem{ID: "gal-1", UserID: "troll", Name: "Bad"}
+	h := NewModerationHandler(service.NewModerationService(repos.reports, repos.users, repos.gallery, repos.comments, repos.nfts, repos.audit))
+
+	req := httptest.NewRequest(http.MethodPut, "/api/admin/gallery/gal-1/hidden", jsonBody(map[string]bool{}))
+	req = withUser(req, "admin", "a@b.com")
+	req = chiContext(req, map[string]string{"id": "gal-1"})
+	rr := httptest.NewRecorder()
+	h.SetGalleryItemHidden(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestRemoveGalleryItem_NotFound(t *testing.T) {
+	repos := newMockRepos("admin", "troll")
+	h := NewModerationHandler(service.NewModerationService(repos.reports, repos.users, repos.gallery, repos.comments, repos.nfts, repos.audit))
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/admin/gallery/missing", nil)
+	req = withUser(req, "admin", "a@b.com")
+	req = chiContext(req, map[string]string{"id": "missing"})
+	rr := httptest.NewRecorder()
+	h.RemoveGalleryItem(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestSetUserSuspended_Success(t *testing.T) {
+	repos := newMockRepos("admin", "troll")
+	h := NewModerationHandler(service.NewModerationService(repos.reports, repos.users, repos.gallery, repos.comments, repos.nfts, repos.audit))
+
+	req := httptest.NewRequest(http.MethodPut, "/api/admin/users/troll/suspended", jsonBody(map[string]bool{"suspended": true}))
+	req = withUser(req, "admin", "a@b.com")
+	req = chiContext(req, map[string]string{"uid": "troll"})
+	rr := httptest.NewRecorder()
+	h.SetUserSuspended(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, repos.users.users["troll"].Suspended)
+}
+
+// --- AdminHandler tests ---
+
+func TestLookupUser_ByUsername(t *testing.T) {
+	repos := newMockRepos("admin", "troll")
+	h := NewAdminHandler(service.NewAdminService(repos.accounts, repos.users, &mockStatsRepo{}, repos.audit))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/users?username=troll", nil)
+	req = withUser(req, "admin", "a@b.com")
+	rr := httptest.NewRecorder()
+	h.LookupUser(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var got model.AdminUser
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+	assert.Equal(t, "troll@example.com", got.Account.Email)
+	assert.Equal(t, "troll", got.Profile.Username)
+	assert.Len(t, repos.audit.entries, 1)
+}
+
+func TestLookupUser_MissingQuery(t *testing.T) {
+	repos := newMockRepos("admin", "troll")
+	h := NewAdminHandler(service.NewAdminService(repos.accounts, repos.users, &mockStatsRepo{}, repos.audit))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/users", nil)
+	req = withUser(req, "admin", "a@b.com")
+	rr := httptest.NewRecorder()
+	h.LookupUser(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestLookupUser_NotFound(t *testing.T) {
+	repos := newMockRepos("admin", "troll")
+	h := NewAdminHandler(service.NewAdminService(repos.accounts, repos.users, &mockStatsRepo{}, repos.audit))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/users?email=nobody@b.com", nil)
+	req = withUser(req, "admin", "a@b.com")
+	rr := httptest.NewRecorder()
+	h.LookupUser(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestAdminStats_Success(t *testing.T) {
+	repos := newMockRepos("admin", "troll")
+	h := NewAdminHandler(service.NewAdminService(repos.accounts, repos.users, &mockStatsRepo{}, repos.audit))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/stats", nil)
+	req = withUser(req, "admin", "a@b.com")
+	rr := httptest.NewRecorder()
+	h.Stats(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"galleryItems":7`)
+}
+
+func TestSetUserDisabled_Success(t *testing.T) {
+	repos := newMockRepos("admin", "troll")
+	h := NewAdminHandler(service.NewAdminService(repos.accounts, repos.users, &mockStatsRepo{}, repos.audit))
+
+	req := httptest.NewRequest(http.MethodPut, "/api/admin/users/troll/disabled", jsonBody(map[string]bool{"disabled": true}))
+	req = withUser(req, "admin", "a@b.com")
+	req = chiContext(req, map[string]string{"uid": "troll"})
+	rr := httptest.NewRecorder()
+	h.SetUserDisabled(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, repos.accounts.accounts["troll"].Disabled)
+	require.Len(t, repos.audit.entries, 1)
+	assert.Equal(t, model.AuditActionUserDisable, repos.audit.entries[0].Action)
+}
+
+func TestSetUserDisabled_Self(t *testing.T) {
+	repos := newMockRepos("admin", "troll")
+	h := NewAdminHandler(service.NewAdminService(repos.accounts, repos.users, &mockStatsRepo{}, repos.audit))
+
+	req := httptest.NewRequest(http.MethodPut, "/api/admin/users/admin/disabled", jsonBody(map[string]bool{"disabled": true}))
+	req = withUser(req, "admin", "a@b.com")
+	req = chiContext(req, map[string]string{"uid": "admin"})
+	rr := httptest.NewRecorder()
+	h.SetUserDisabled(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestReleaseUsername_Success(t *testing.T) {
+	repos := newMockRepos("admin", "troll")
+	h := NewAdminHandler(service.NewAdminService(repos.accounts, repos.users, &mockStatsRepo{}, repos.audit))
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/admin/usernames/troll", nil)
+	req = withUser(req, "admin", "a@b.com")
+	req = chiContext(req, map[string]string{"username": "troll"})
+	rr := httptest.NewRecorder()
+	h.ReleaseUsername(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, repos.users.users["troll"].Username)
+	assert.NotContains(t, repos.users.usernames, "troll")
+}
+
+func TestReleaseUsername_NotFound(t *testing.T) {
+	repos := newMockRepos("admin", "troll")
+	h := NewAdminHandler(service.NewAdminService(repos.accounts, repos.users, &mockStatsRepo{}, repos.audit))
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/admin/usernames/nobody", nil)
+	req = withUser(req, "admin", "a@b.com")
+	req = chiContext(req, map[string]string{"username": "nobody"})
+	rr := httptest.NewRecorder()
+	h.ReleaseUsername(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestListAuditLog_Success(t *testing.T) {
+	repos := newMockRepos("admin", "troll")
+	h := NewAdminHandler(service.NewAdminService(repos.accounts, repos.users, &mockStatsRepo{}, repos.audit))
+	repos.audit.entries = []*model.AuditEntry{{ID: "e1", ActorUID: "admin", Action: model.AuditActionUserDisable}}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/audit", nil)
+	req = withUser(req, "admin", "a@b.com")
+	rr := httptest.NewRecorder()
+	h.ListAuditLog(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"user.disable"`)
+}
+
+func TestAdminHandler_NoAuth(t *testing.T) {
+	repos := newMockRepos("admin", "troll")
+	h := NewAdminHandler(service.NewAdminService(repos.accounts, repos.users, &mockStatsRepo{}, repos.audit))
+
+	rr := httptest.NewRecorder()
+	h.Stats(rr, httptest.NewRequest(http.MethodGet, "/api/admin/stats", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestListAuditLog_FilterByActor(t *testing.T) {
+	repos := newMockRepos("admin", "troll")
+	h := NewAdminHandler(service.NewAdminService(repos.accounts, repos.users, &mockStatsRepo{}, repos.audit))
+	repos.audit.entries = []*model.AuditEntry{
+		{ID: "e1", ActorUID: "admin", Action: model.AuditActionUserDisable},
+		{ID: "e2", ActorUID: "troll", Action: model.AuditActionProfileUpdate},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/audit?actor=troll", nil)
+	req = withUser(req, "admin", "a@b.com")
+	rr := httptest.NewRecorder()
+	h.ListAuditLog(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"e2"`)
+	assert.NotContains(t, rr.Body.String(), `"e1"`)
+}
+
+// --- Account activity tests ---
+
+func TestAccountActivity_OwnEntriesOnly(t *testing.T) {
+	audit := &mockAuditLogger{entries: []*model.AuditEntry{
+		{ID: "mine", ActorUID: "user1", Action: model.AuditActionUsernameClaim},
+		{ID: "theirs", ActorUID: "user2", Action: model.AuditActionNFTCreate},
+	}}
+	h := NewProfileHandler(service.NewUserService(newMockUserRepo(), audit))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/account/activity", nil)
+	req = withUser(req, "user1", "a@b.com")
+	rr := httptest.NewRecorder()
+	h.Activity(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"mine"`)
+	assert.NotContains(t, rr.Body.String(), `"theirs"`)
+}
+
+func TestAccountActivity_NoAuth(t *testing.T) {
+	h := NewProfileHandler(service.NewUserService(newMockUserRepo(), &mockAuditLogger{}))
+
+	rr := httptest.NewRecorder()
+	h.Activity(rr, httptest.NewRequest(http.MethodGet, "/api/account/activity", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestUpdateProfile_WritesAuditEntry(t *testing.T) {
+	repo := newMockUserRepo()
+	repo.users["user1"] = &model.User{UID: "user1", Email: "a@b.com", Bio: "old"}
+	audit := &mockAuditLogger{}
+	h := NewProfileHandler(service.NewUserService(repo, audit))
+
+	req := httptest.NewRequest(http.MethodPut, "/api/profile", jsonBody(map[string]string{"bio": "new"}))
+	req = withUser(req, "user1", "a@b.com")
+	rr := httptest.NewRecorder()
+	h.UpdateProfile(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	require.Len(t, audit.entries, 1)
+	assert.Equal(t, model.AuditChange{Before: "old", After: "new"}, audit.entries[0].Changes["bio"])
+}
+
+// --- Session handler tests ---
+
+func TestSessionHandler_CreateSession(t *testing.T) {
+	h := NewSessionHandler(newMockSessionManager(), time.Hour, true)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/session", jsonBody(map[string]string{"idToken": "user1"}))
+	rr := httptest.NewRecorder()
+	h.CreateSession(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	cookies := rr.Result().Cookies()
+	require.Len(t, cookies, 1)
+	c := cookies[0]
+	assert.Equal(t, middleware.SessionCookieName, c.Name)
+	assert.Equal(t, "cookie-for-user1", c.Value)
+	assert.Equal(t, 3600, c.MaxAge)
+	assert.True(t, c.HttpOnly)
+	assert.True(t, c.Secure)
+	assert.Equal(t, http.SameSiteLaxMode, c.SameSite)
+}
+
+func TestSessionHandler_CreateSession_MissingToken(t *testing.T) {
+	h := NewSessionHandler(newMockSessionManager(), time.Hour, true)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/session", jsonBody(map[string]string{"idToken": " "}))
+	rr := httptest.NewRecorder()
+	h.CreateSession(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Empty(t, rr.Result().Cookies())
+}
+
+func TestSessionHandler_CreateSession_StaleSignIn(t *testing.T) {
+	sessions := newMockSessionManager()
+	h := NewSessionHandler(sessions, time.Hour, true)
+	sessions.createErr = fmt.Errorf("recent sign-in required")
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/session", jsonBody(map[string]string{"idToken": "user1"}))
+	rr := httptest.NewRecorder()
+	h.CreateSession(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, rr.Body.String(), "recent sign-in required")
+	assert.Empty(t, rr.Result().Cookies())
+}
+
+func TestSessionHandler_CreateSession_InvalidToken(t *testing.T) {
+	sessions := newMockSessionManager()
+	h := NewSessionHandler(sessions, time.Hour, true)
+	sessions.createErr = fmt.Errorf("verify id token: token expired")
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/session", jsonBody(map[string]string{"idToken": "bad"}))
+	rr := httptest.NewRecorder()
+	h.CreateSession(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, rr.Body.String(), "invalid or expired token")
+}
+
+func TestSessionHandler_Logout(t *testing.T) {
+	sessions := newMockSessionManager()
+	h := NewSessionHandler(sessions, time.Hour, true)
+	sessions.cookies["valid"] = "user1"
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/logout", nil)
+	req.AddCookie(&http.Cookie{Name: middleware.SessionCookieName, Value: "valid"})
+	rr := httptest.NewRecorder()
+	h.Logout(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, []string{"user1"}, sessions.revoked)
+	cookies := rr.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, -1, cookies[0].MaxAge)
+}
+
+func TestSessionHandler_Logout_NoCookie(t *testing.T) {
+	sessions := newMockSessionManager()
+	h := NewSessionHandler(sessions, time.Hour, true)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/logout", nil)
+	rr := httptest.NewRecorder()
+	h.Logout(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, sessions.revoked)
+	require.Len(t, rr.Result().Cookies(), 1)
+}
+
+// --- Token handler tests ---
+
+func TestTokenHandler_CreateListRevoke(t *testing.T) {
+	repo := newMockAPITokenRepo()
+	h := NewTokenHandler(service.NewAPITokenService(repo, nil))
+
+	req := withUser(httptest.NewRequest(http.MethodPost, "/api/tokens",
+		jsonBody(map[string]interface{}{"name": "CI", "scopes": []string{"projects:read"}})), "user1", "a@b.com")
+	rr := httptest.NewRecorder()
+	h.CreateToken(rr, req)
+
+	require.Equal(t, http.StatusCreated, rr.Code)
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	assert.True(t, strings.HasPrefix(created["token"].(string), model.APITokenPrefix))
+	assert.NotContains(t, created, "hash")
+	id := created["id"].(string)
+
+	req = withUser(httptest.NewRequest(http.MethodGet, "/api/tokens", nil), "user1", "a@b.com")
+	rr = httptest.NewRecorder()
+	h.ListTokens(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), created["token"].(string))
+	assert.Contains(t, rr.Body.String(), `"name":"CI"`)
+
+	req = withUser(httptest.NewRequest(http.MethodDelete, "/api/tokens/"+id, nil), "user2", "b@b.com")
+	req = chiContext(req, map[string]string{"id": id})
+	rr = httptest.NewRecorder()
+	h.RevokeToken(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	req = withUser(httptest.NewRequest(http.MethodDelete, "/api/tokens/"+id, nil), "user1", "a@b.com")
+	req = chiContext(req, map[string]string{"id": id})
+	rr = httptest.NewRecorder()
+	h.RevokeToken(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, repo.tokens)
+}
+
+func TestTokenHandler_CreateToken_InvalidScope(t *testing.T) {
+	h := NewTokenHandler(service.NewAPITokenService(newMockAPITokenRepo(), nil))
+
+	req := withUser(httptest.NewRequest(http.MethodPost, "/api/tokens",
+		jsonBody(map[string]interface{}{"name": "CI", "scopes": []string{"admin"}})), "user1", "a@b.com")
+	rr := httptest.NewRecorder()
+	h.CreateToken(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestTokenHandler_RequiresUser(t *testing.T) {
+	h := NewTokenHandler(service.NewAPITokenService(newMockAPITokenRepo(), nil))
+
+	rr := httptest.NewRecorder()
+	h.ListTokens(rr, httptest.NewRequest(http.MethodGet, "/api/tokens", nil))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+// --- Webhook handler tests ---
+
+type mockWebhookRepo struct {
+	webhooks   map[string]*model.Webhook
+	deliveries map[string][]*model.WebhookDelivery
+}
+
+func newMockWebhookRepo() *mockWebhookRepo {
+	return &mockWebhookRepo{
+		webhooks:   make(map[string]*model.Webhook),
+		deliveries: make(map[string][]*model.WebhookDelivery),
+	}
+}
+
+func (m *mockWebhookRepo) GetByID(_ context.Context, id string) (*model.Webhook, error) {
+	w, ok := m.webhooks[id]
+	if !ok {
+		return nil, fmt.Errorf("webhook not found")
+	}
+	return w, nil
+}
+
+func (m *mockWebhookRepo) ListByUser(_ context.Context, uid string) ([]*model.Webhook, error) {
+	var result []*model.Webhook
+	for _, w := range m.webhooks {
+		if w.UID == uid {
+			result = append(result, w)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockWebhookRepo) Create(_ context.Context, w *model.Webhook) (string, error) {
+	id := fmt.Sprintf("wh%d", len(m.webhooks)+1)
+	w.ID = id
+	m.webhooks[id] = w
+	return id, nil
+}
+
+func (m *mockWebhookRepo) Delete(_ context.Context, id string) error {
+	delete(m.webhooks, id)
+	return nil
+}
+
+func (m *mockWebhookRepo) LogDelivery(_ context.Context, id string, d *model.WebhookDelivery) error {
+	m.deliveries[id] = append(m.deliveries[id], d)
+	return nil
+}
+
+func (m *mockWebhookRepo) ListDeliveries(_ context.Context, id string, _ int, _ string) ([]*model.WebhookDelivery, error) {
+	return m.deliveries[id], nil
+}
+
+func TestWebhookHandler_CreateListPingDelete(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	repo := newMockWebhookRepo()
+	h := NewWebhookHandler(service.NewWebhookService(repo, nil, true))
+
+	req := withUser(httptest.NewRequest(http.MethodPost, "/api/webhooks",
+		jsonBody(map[string]interface{}{"url": receiver.URL, "events": []string{"project.created"}})), "user1", "a@b.com")
+	rr := httptest.NewRecorder()
+	h.CreateWebhook(rr, req)
+
+	require.Equal(t, http.StatusCreated, rr.Code)
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	secret := created["secret"].(string)
+	assert.True(t, strings.HasPrefix(secret, model.WebhookSecretPrefix))
+	id := created["id"].(string)
+
+	req = withUser(httptest.NewRequest(http.MethodGet, "/api/webhooks", nil), "user1", "a@b.com")
+	rr = httptest.NewRecorder()
+	h.ListWebhooks(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), secret)
+	assert.Contains(t, rr.Body.String(), `"project.created"`)
+
+	req = withUser(httptest.NewRequest(http.MethodPost, "/api/webhooks/"+id+"/ping", nil), "user1", "a@b.com")
+	req = chiContext(req, map[string]string{"id": id})
+	rr = httptest.NewRecorder()
+	h.PingWebhook(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"statusCode":204`)
+	assert.Contains(t, rr.Body.String(), `"success":true`)
+
+	req = withUser(httptest.NewRequest(http.MethodGet, "/api/webhooks/"+id+"/deliveries", nil), "user2", "b@b.com")
+	req = chiContext(req, map[string]string{"id": id})
+	rr = httptest.NewRecorder()
+	h.ListDeliveries(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	req = withUser(httptest.NewRequest(http.MethodGet, "/api/webhooks/"+id+"/deliveries", nil), "user1", "a@b.com")
+	req = chiContext(req, map[string]string{"id": id})
+	rr = httptest.NewRecorder()
+	h.ListDeliveries(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"event":"ping"`)
+
+	req = withUser(httptest.NewRequest(http.MethodDelete, "/api/webhooks/"+id, nil), "user1", "a@b.com")
+	req = chiContext(req, map[string]string{"id": id})
+	rr = httptest.NewRecorder()
+	h.DeleteWebhook(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, repo.webhooks)
+}
+
+func TestWebhookHandler_CreateWebhook_Invalid(t *testing.T) {
+	h := NewWebhookHandler(service.NewWebhookService(newMockWebhookRepo(), nil, false))
+
+	req := withUser(httptest.NewRequest(http.MethodPost, "/api/webhooks",
+		jsonBody(map[string]interface{}{"url": "http://example.com/hook", "events": []string{"project.created"}})), "user1", "a@b.com")
+	rr := httptest.NewRecorder()
+	h.CreateWebhook(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestWebhookHandler_RequiresUser(t *testing.T) {
+	h := NewWebhookHandler(service.NewWebhookService(newMockWebhookRepo(), nil, false))
+
+	rr := httptest.NewRecorder()
+	h.ListWebhooks(rr, httptest.NewRequest(http.MethodGet, "/api/webhooks", nil))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+// --- Job handler tests ---
+
+func TestJobHandler_GetJob(t *testing.T) {
+	runner := jobs.NewRunner(jobs.NewMemoryStore(), jobs.Config{})
+	runner.Register("export", func(context.Context, *jobs.Job) (map[string]interface{}, error) { return nil, nil })
+	h := NewJobHandler(service.NewJobService(runner))
+
+	job, err := runner.Enqueue(context.Background(), jobs.EnqueueRequest{
+		Type:    "export",
+		UID:     "user1",
+		Payload: map[string]interface{}{"secret": "internal"},
+	})
+	require.NoError(t, err)
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/api/jobs/"+job.ID, nil), "user1", "a@b.com")
+	req = chiContext(req, map[string]string{"id": job.ID})
+	rr := httptest.NewRecorder()
+	h.GetJob(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"status":"queued"`)
+	assert.Contains(t, rr.Body.String(), `"type":"export"`)
+	assert.NotContains(t, rr.Body.String(), "internal")
+
+	req = withUser(httptest.NewRequest(http.MethodGet, "/api/jobs/"+job.ID, nil), "user2", "b@b.com")
+	req = chiContext(req, map[string]string{"id": job.ID})
+	rr = httptest.NewRecorder()
+	h.GetJob(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	req = withUser(httptest.NewRequest(http.MethodGet, "/api/jobs/missing", nil), "user1", "a@b.com")
+	req = chiContext(req, map[string]string{"id": "missing"})
+	rr = httptest.NewRecorder()
+	h.GetJob(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestParseContentRange(t *testing.T) {
+	start, size, total, err := parseContentRange("bytes 262144-524287/600000")
+	require.NoError(t, err)
+	assert.Equal(t, int64(262144), start)
+	assert.Equal(t, int64(262144), size)
+	assert.Equal(t, int64(600000), total)
+
+	for _, header := range []string{
+		"",
+		"items 0-9/10",
+		"bytes 0-9",
+		"bytes 0/10",
+		"bytes 9-0/10",
+		"bytes 0-10/10",
+		"bytes -1-5/10",
+		"bytes 0-9/*",
+	} {
+		_, _, _, err := parseContentRange(header)
+		assert.Error(t, err, header)
+		assert.Equal(t, http.StatusBadRequest, errorStatus(err.Error()), header)
+	}
+}
+
+func TestUploadHandler_WriteChunk_RequiresContentRange(t *testing.T) {
+	h := NewUploadHandler(nil)
+
+	req := withUser(httptest.NewRequest(http.MethodPut, "/api/projects/p1/uploads/u1", strings.NewReader("data")), "user1", "a@b.com")
+	req = chiContext(req, map[string]string{"id": "p1", "uploadId": "u1"})
+	rr := httptest.NewRecorder()
+	h.WriteChunk(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Content-Range")
+}
+
+func TestProjectHandler_GetThumbnail(t *testing.T) {
+	repo := newMockProjectRepo()
+	svc := service.NewProjectService(repo, nil, nil, nil)
+	png := []byte("\x89PNG\r\n\x1a\nthumb")
+	id, _ := repo.Create(context.Background(), &model.Project{
+		UserID:        "user1",
+		Title:         "Art",
+		ThumbnailData: "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+		UpdatedAt:     time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+	})
+	h := NewProjectHandler(svc, testCursors)
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/api/projects/"+id+"/thumbnail", nil), "user1", "a@b.com")
+	req = chiContext(req, map[string]string{"id": id})
+	rr := httptest.NewRecorder()
+	h.GetThumbnail(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "image/png", rr.Header().Get("Content-Type"))
+	assert.Equal(t, png, rr.Body.Bytes())
+	assert.Equal(t, "Sat, 01 Mar 2025 00:00:00 GMT", rr.Header().Get("Last-Modified"))
+	etag := rr.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	req = withUser(httptest.NewRequest(http.MethodGet, "/api/projects/"+id+"/thumbnail", nil), "user1", "a@b.com")
+	req.Header.Set("If-None-Match", etag)
+	req = chiContext(req, map[string]string{"id": id})
+	rr = httptest.NewRecorder()
+	h.GetThumbnail(rr, req)
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+
+	req = withUser(httptest.NewRequest(http.MethodGet, "/api/projects/"+id+"/thumbnail", nil), "user2", "b@b.com")
+	req = chiContext(req, map[string]string{"id": id})
+	rr = httptest.NewRecorder()
+	h.GetThumbnail(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestNFTHandler_GetThumbnail(t *testing.T) {
+	repo := newMockNFTRepo()
+	svc := service.NewNFTService(repo, nil, nil)
+	png := []byte("\x89PNG\r\n\x1a\nimage")
+	id, _ := repo.Create(context.Background(), &model.NFT{
+		UserID:    "user1",
+		Name:      "Token",
+		ImageData: "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+	})
+	h := NewNFTHandler(svc, testCursors)
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/api/nfts/"+id+"/thumbnail", nil), "user1", "a@b.com")
+	req = chiContext(req, map[string]string{"id": id})
+	rr := httptest.NewRecorder()
+	h.GetThumbnail(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "image/png", rr.Header().Get("Content-Type"))
+	assert.Equal(t, png, rr.Body.Bytes())
+	assert.NotEmpty(t, rr.Header().Get("ETag"))
+}
+
+func TestRespondCacheableJSON(t *testing.T) {
+	modTime := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	data := map[string]string{"hello": "world"}
+
+	rr := httptest.NewRecorder()
+	respondCacheableJSON(rr, httptest.NewRequest(http.MethodGet, "/", nil), data, modTime)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "private, no-cache", rr.Header().Get("Cache-Control"))
+	assert.JSONEq(t, `{"hello":"world"}`, rr.Body.String())
+	etag := rr.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    int
+	}{
+		{"matching etag", map[string]string{"If-None-Match": etag}, http.StatusNotModified},
+		{"weak etag in list", map[string]string{"If-None-Match": `"other", W/` + etag}, http.StatusNotModified},
+		{"wildcard", map[string]string{"If-None-Match": "*"}, http.StatusNotModified},
+		{"other etag", map[string]string{"If-None-Match": `"other"`}, http.StatusOK},
+		{"not modified since", map[string]string{"If-Modified-Since": "Sat, 01 Mar 2025 00:00:00 GMT"}, http.StatusNotModified},
+		{"modified since", map[string]string{"If-Modified-Since": "Fri, 28 Feb 2025 00:00:00 GMT"}, http.StatusOK},
+		{"etag wins over date", map[string]string{"If-None-Match": `"other"`, "If-Modified-Since": "Sat, 01 Mar 2025 00:00:00 GMT"}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rr := httptest.NewRecorder()
+			respondCacheableJSON(rr, req, data, modTime)
+			assert.Equal(t, tt.want, rr.Code)
+			assert.Equal(t, etag, rr.Header().Get("ETag"))
+		})
+	}
+}
+
+// --- GraphQL handler tests ---
+
+func newTestGraphQLHandler(t *testing.T) *GraphQLHandler {
+	t.Helper()
+	users := newMockUserRepo()
+	users.users["user1"] = &model.User{UID: "user1", Username: "user1", Email: "a@b.com"}
+	projects := newMockProjectRepo()
+	gallery := newMockGalleryRepo()
+	nfts := newMockNFTRepo()
+	g, err := graph.New(graph.Services{
+		Users:    service.NewUserService(users, nil),
+		Follows:  service.NewFollowService(users, newMockFollowRepo(), gallery),
+		Projects: service.NewProjectService(projects, nil, nil, nil),
+		Gallery:  service.NewGalleryService(gallery, nil, nil),
+		NFTs:     service.NewNFTService(nfts, nil, nil),
+		Cursors:  testCursors,
+	}, graph.Limits{})
+	require.NoError(t, err)
+	return NewGraphQLHandler(g)
+}
+
+func TestGraphQLHandler_Query(t *testing.T) {
+	h := newTestGraphQLHandler(t)
+
+	tests := []struct {
+		name  string
+		body  string
+		want  int
+		check string
+	}{
+		{"runs a query", `{"query":"{ me { username email projectCount } }"}`, http.StatusOK, `"email":"a@b.com"`},
+		{"field errors are still a 200", `{"query":"{ me { username } missing: project(id: \"nope\") { title } }"}`, http.StatusOK, `"missing":null`},
+		{"invalid query", `{"query":"{ me { password } }"}`, http.StatusBadRequest, `"errors"`},
+		{"missing query", `{}`, http.StatusBadRequest, "query is required"},
+		{"bad json", `{"query":`, http.StatusBadRequest, `"error"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/graphql", strings.NewReader(tt.body))
+			req = withUser(req, "user1", "a@b.com")
+			rr := httptest.NewRecorder()
+			h.Query(rr, req)
+
+			assert.Equal(t, tt.want, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.check)
+		})
+	}
+}
+
+func TestGraphQLHandler_NoAuth(t *testing.T) {
+	h := newTestGraphQLHandler(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/graphql", strings.NewReader(`{"query":"{ me { uid } }"}`))
+	rr := httptest.NewRecorder()
+	h.Query(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+// --- Stats handler tests ---
+
+func TestStatsHandler_GetStats(t *testing.T) {
+	users := newMockUserRepo()
+	users.users["user1"] = &model.User{UID: "user1", ProjectCount: 4, NFTCount: 2, FollowerCount: 1}
+	h := NewStatsHandler(service.NewStatsService(users, &mockStatsRepo{}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/stats", nil)
+	req = withUser(req, "user1", "a@b.com")
+	rr := httptest.NewRecorder()
+	h.GetStats(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"projects":4,"galleryItems":0,"nfts":2,"followers":1,"following":0}`, rr.Body.String())
+}
+
+func TestStatsHandler_NoAuth(t *testing.T) {
+	h := NewStatsHandler(service.NewStatsService(newMockUserRepo(), &mockStatsRepo{}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/stats", nil)
+	rr := httptest.NewRecorder()
+	h.GetStats(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+// --- Health handler tests ---
+
+func TestHealthHandler_Livez(t *testing.T) {
+	down := health.Check{Name: "firestore", Critical: true, Run: func(context.Context) error { return fmt.Errorf("unreachable") }}
+	h := NewHealthHandler(health.NewChecker([]health.Check{down}, health.Config{}))
+
+	rr := httptest.NewRecorder()
+	h.Livez(rr, httptest.NewRequest(http.MethodGet, "/livez", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code, "liveness ignores dependencies")
+	assert.JSONEq(t, `{"status":"ok"}`, rr.Body.String())
+}
+
+func TestHealthHandler_Readyz(t *testing.T) {
+	var storageErr error
+	checker := health.NewChecker([]health.Check{
+		{Name: "firestore", Critical: true, Run: func(context.Context) error { return nil }},
+		{Name: "storage", Run: func(context.Context) error { return storageErr }},
+	}, health.Config{CacheTTL: time.Nanosecond})
+	h := NewHealthHandler(checker)
+
+	ready := func() (int, *health.Report) {
+		rr := httptest.NewRecorder()
+		h.Readyz(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		var report health.Report
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &report))
+		return rr.Code, &report
+	}
+
+	code, report := ready()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, health.StatusOK, report.Status)
+	assert.Equal(t, health.StatusOK, report.Checks["storage"].Status)
+
+	// A non-critical failure is reported without failing readiness
+	storageErr = fmt.Errorf("bucket unreachable")
+	code, report = ready()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, health.StatusDegraded, report.Status)
+	assert.Equal(t, health.StatusError, report.Checks["storage"].Status)
+
+	checker.Drain()
+	code, report = ready()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, health.StatusDraining, report.Status)
+}
+
+func TestHealthHandler_ReadyzCriticalDown(t *testing.T) {
+	h := NewHealthHandler(health.NewChecker([]health.Check{
+		{Name: "firestore", Critical: true, Run: func(context.Context) error { return fmt.Errorf("unreachable") }},
+	}, health.Config{}))
+
+	rr := httptest.NewRecorder()
+	h.Readyz(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "unreachable", "errors are logged, not returned")
+}
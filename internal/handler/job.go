@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pandasWhoCode/paintbar/internal/service"
+)
+
+// JobHandler handles background job status endpoints.
+type JobHandler struct {
+	jobService *service.JobService
+}
+
+// NewJobHandler creates a new JobHandler.
+func NewJobHandler(jobService *service.JobService) *JobHandler {
+	return &JobHandler{jobService: jobService}
+}
+
+// GetJob handles GET /api/jobs/{id}
+func (h *JobHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	job, err := h.jobService.GetJob(r.Context(), user.UID, chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, job)
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"github.com/pandasWhoCode/paintbar/internal/service"
+)
+
+// ModerationHandler handles abuse reports and admin moderation endpoints.
+// Admin routes are gated by middleware.RequireRole(service.RoleAdmin).
+type ModerationHandler struct {
+	moderationService *service.ModerationService
+}
+
+// NewModerationHandler creates a new ModerationHandler.
+func NewModerationHandler(moderationService *service.ModerationService) *ModerationHandler {
+	return &ModerationHandler{moderationService: moderationService}
+}
+
+// CreateReport handles POST /api/reports
+func (h *ModerationHandler) CreateReport(w http.ResponseWriter, r *http.Request) {
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	var body struct {
+		TargetType string `json:"targetType"`
+		TargetID   string `json:"targetId"`
+		ItemID     string `json:"itemId"`
+		Reason     string `json:"reason"`
+		Details    string `json:"details"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+
+	report := &model.Report{
+		TargetType: body.TargetType,
+		TargetID:   body.TargetID,
+		ItemID:     body.ItemID,
+		Reason:     body.Reason,
+		Details:    body.Details,
+	}
+	id, err := h.moderationService.CreateReport(r.Context(), user.UID, report)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]string{"id": id})
+}
+
+// ListReports handles GET /api/admin/reports
+func (h *ModerationHandler) ListReports(w http.ResponseWriter, r *http.Request) {
+	if requireUser(w, r) == nil {
+		return
+	}
+
+	limit, startAfter := parsePagination(r)
+
+	reports, err := h.moderationService.ListReports(r.Context(), r.URL.Query().Get("status"), limit, startAfter)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, reports)
+}
+
+// ResolveReport handles PUT /api/admin/reports/{id}
+func (h *ModerationHandler) ResolveReport(w http.ResponseWriter, r *http.Request) {
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	var body struct {
+		Status string `json:"status"`
+		Note   string `json:"note"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+
+	err := h.moderationService.ResolveReport(r.Context(), user.UID, chi.URLParam(r, "id"), body.Status, body.Note)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "updated"})
+}
+
+// SetGalleryItemHidden handles PUT /api/admin/gallery/{id}/hidden
+func (h *ModerationHandler) SetGalleryItemHidden(w http.ResponseWriter, r *http.Request) {
+	setFlag(w, r, "hidden", func(r *http.Request, adminUID string, hidden bool) error {
+		return h.moderationService.SetGalleryItemHidden(r.Context(), adminUID, chi.URLParam(r, "id"), hidden)
+	})
+}
+
+// RemoveGalleryItem handles DELETE /api/admin/gallery/{id}
+func (h *ModerationHandler) RemoveGalleryItem(w http.ResponseWriter, r *http.Request) {
+	h.remove(w, r, func(r *http.Request, adminUID string) error {
+		return h.moderationService.RemoveGalleryItem(r.Context(), adminUID, chi.URLParam(r, "id"))
+	})
+}
+
+// SetCommentHidden handles PUT /api/admin/gallery/{id}/comments/{commentId}/hidden
+func (h *ModerationHandler) SetCommentHidden(w http.ResponseWriter, r *http.Request) {
+	setFlag(w, r, "hidden", func(r *http.Request, adminUID string, hidden bool) error {
+		return h.moderationService.SetCommentHidden(r.Context(), adminUID,
+			chi.URLParam(r, "id"), chi.URLParam(r, "commentId"), hidden)
+	})
+}
+
+// RemoveComment handles DELETE /api/admin/gallery/{id}/comments/{commentId}
+func (h *ModerationHandler) RemoveComment(w http.ResponseWriter, r *http.Request) {
+	h.remove(w, r, func(r *http.Request, adminUID string) error {
+		return h.moderationService.RemoveComment(r.Context(), adminUID,
+			chi.URLParam(r, "id"), chi.URLParam(r, "commentId"))
+	})
+}
+
+// SetNFTHidden handles PUT /api/admin/nfts/{id}/hidden
+func (h *ModerationHandler) SetNFTHidden(w http.ResponseWriter, r *http.Request) {
+	setFlag(w, r, "hidden", func(r *http.Request, adminUID string, hidden bool) error {
+		return h.moderationService.SetNFTHidden(r.Context(), adminUID, chi.URLParam(r, "id"), hidden)
+	})
+}
+
+// RemoveNFT handles DELETE /api/admin/nfts/{id}
+func (h *ModerationHandler) RemoveNFT(w http.ResponseWriter, r *http.Request) {
+	h.remove(w, r, func(r *http.Request, adminUID string) error {
+		return h.moderationService.RemoveNFT(r.Context(), adminUID, chi.URLParam(r, "id"))
+	})
+}
+
+// SetUserSuspended handles PUT /api/admin/users/{uid}/suspended
+func (h *ModerationHandler) SetUserSuspended(w http.ResponseWriter, r *http.Request) {
+	setFlag(w, r, "suspended", func(r *http.Request, adminUID string, suspended bool) error {
+		return h.moderationService.SetUserSuspended(r.Context(), adminUID, chi.URLParam(r, "uid"), suspended)
+	})
+}
+
+// setFlag decodes a {"<field>": bool} body and applies it with fn on behalf
+// of the authenticated admin.
+func setFlag(w http.ResponseWriter, r *http.Request, field string, fn func(*http.Request, string, bool) error) {
+	admin := requireUser(w, r)
+	if admin == nil {
+		return
+	}
+
+	var body map[string]bool
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	value, ok := body[field]
+	if !ok || len(body) != 1 {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": field + " is required"})
+		return
+	}
+
+	if err := fn(r, admin.UID, value); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "updated"})
+}
+
+// remove applies a permanent removal with fn on behalf of the
+// authenticated admin.
+func (h *ModerationHandler) remove(w http.ResponseWriter, r *http.Request, fn func(*http.Request, string) error) {
+	admin := requireUser(w, r)
+	if admin == nil {
+		return
+	}
+
+	if err := fn(r, admin.UID); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
@@ -11,11 +11,16 @@ import (
 // NFTHandler handles NFT API endpoints.
 type NFTHandler struct {
 	nftService *service.NFTService
+	cursors    *service.CursorCodec
 }
 
-// NewNFTHandler creates a new NFTHandler.
-func NewNFTHandler(nftService *service.NFTService) *NFTHandler {
-	return &NFTHandler{nftService: nftService}
+// NewNFTHandler creates a new NFTHandler. cursors signs list cursors; it may be
+// nil to use a per-process key.
+func NewNFTHandler(nftService *service.NFTService, cursors *service.CursorCodec) *NFTHandler {
+	if cursors == nil {
+		cursors = service.NewCursorCodec(nil)
+	}
+	return &NFTHandler{nftService: nftService, cursors: cursors}
 }
 
 // ListNFTs handles GET /api/nfts
@@ -25,15 +30,24 @@ func (h *NFTHandler) ListNFTs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	limit, startAfter := parsePagination(r)
+	limit, _ := parsePagination(r)
+	opts, err := parseListOptions(r, h.cursors)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+	fields, ok := parseFields(w, r, model.NFTSummaryFields)
+	if !ok {
+		return
+	}
 
-	nfts, err := h.nftService.ListNFTs(r.Context(), user.UID, limit, startAfter)
+	page, err := h.nftService.ListNFTs(r.Context(), user.UID, limit, opts, fields)
 	if err != nil {
 		respondError(w, err)
 		return
 	}
 
-	respondJSON(w, http.StatusOK, nfts)
+	respondPage(w, r, page, fields, h.cursors)
 }
 
 // GetNFT handles GET /api/nfts/{id}
@@ -107,3 +121,20 @@ func (h *NFTHandler) CountNFTs(w http.ResponseWriter, r *http.Request) {
 
 	respondJSON(w, http.StatusOK, map[string]int64{"count": count})
 }
+
+// GetThumbnail handles GET /api/nfts/{id}/thumbnail — the NFT's thumbnail image,
+// decoded from its stored data URL.
+func (h *NFTHandler) GetThumbnail(w http.ResponseWriter, r *http.Request) {
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	thumb, err := h.nftService.GetThumbnail(r.Context(), user.UID, chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	serveThumbnail(w, r, thumb)
+}
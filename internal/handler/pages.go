@@ -2,56 +2,81 @@ package handler
 
 import (
 	"net/http"
+
+	"github.com/pandasWhoCode/paintbar/internal/middleware"
+	"github.com/pandasWhoCode/paintbar/internal/service"
 )
 
 // PageHandler serves SSR pages using Go templates.
 type PageHandler struct {
-	renderer *TemplateRenderer
-	env      string
+	renderer    *TemplateRenderer
+	env         string
+	userService *service.UserService
 }
 
-// NewPageHandler creates a new PageHandler.
-func NewPageHandler(renderer *TemplateRenderer, env string) *PageHandler {
-	return &PageHandler{renderer: renderer, env: env}
+// NewPageHandler creates a new PageHandler. userService is used to
+// personalize pages for visitors with a session cookie and may be nil.
+func NewPageHandler(renderer *TemplateRenderer, env string, userService *service.UserService) *PageHandler {
+	return &PageHandler{renderer: renderer, env: env, userService: userService}
 }
 
 // Login serves the login page (GET /).
 func (h *PageHandler) Login(w http.ResponseWriter, r *http.Request) {
-	h.renderer.Render(w, "login", PageData{
-		Title: "Login - PaintBar",
-		Env:   h.env,
-	})
+	h.renderer.Render(w, "login", h.pageData(r, "Login - PaintBar"))
 }
 
 // Profile serves the profile page (GET /profile).
 func (h *PageHandler) Profile(w http.ResponseWriter, r *http.Request) {
-	h.renderer.Render(w, "profile", PageData{
-		Title: "User Profile - Paintbar",
-		Env:   h.env,
-	})
+	h.renderer.Render(w, "profile", h.pageData(r, "User Profile - Paintbar"))
 }
 
 // Projects serves the projects page (GET /projects).
 func (h *PageHandler) Projects(w http.ResponseWriter, r *http.Request) {
-	h.renderer.Render(w, "projects", PageData{
-		Title: "My Projects - PaintBar",
-		Env:   h.env,
-	})
+	h.renderer.Render(w, "projects", h.pageData(r, "My Projects - PaintBar"))
 }
 
 // Canvas serves the canvas page (GET /canvas).
 func (h *PageHandler) Canvas(w http.ResponseWriter, r *http.Request) {
-	h.renderer.Render(w, "canvas", PageData{
-		Title: "PaintBar",
-		Env:   h.env,
-	})
+	h.renderer.Render(w, "canvas", h.pageData(r, "PaintBar"))
 }
 
 // NotFound serves the 404 page.
 func (h *PageHandler) NotFound(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNotFound)
-	h.renderer.Render(w, "404", PageData{
-		Title: "Page Not Found - PaintBar",
-		Env:   h.env,
-	})
+	h.renderer.Render(w, "404", h.pageData(r, "Page Not Found - PaintBar"))
+}
+
+// pageData builds the template data for a page, including the signed-in
+// user when the request carries a valid session cookie and the CSRF token
+// for cookie-authenticated API calls.
+func (h *PageHandler) pageData(r *http.Request, title string) PageData {
+	return PageData{
+		Title:     title,
+		Env:       h.env,
+		User:      h.pageUser(r),
+		CSRFToken: middleware.CSRFTokenFromContext(r.Context()),
+	}
+}
+
+// pageUser resolves the session user's profile. A failed profile lookup
+// (e.g. a brand-new account with no profile yet) still yields a PageUser
+// with the identity from the session.
+func (h *PageHandler) pageUser(r *http.Request) *PageUser {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		return nil
+	}
+
+	pu := &PageUser{UID: user.UID, Email: user.Email}
+	if h.userService == nil {
+		return pu
+	}
+
+	profile, err := h.userService.GetProfile(r.Context(), user.UID)
+	if err != nil {
+		return pu
+	}
+	pu.Username = profile.Username
+	pu.DisplayName = profile.DisplayName
+	return pu
 }
@@ -74,3 +74,21 @@ func (h *ProfileHandler) ClaimUsername(w http.ResponseWriter, r *http.Request) {
 
 	respondJSON(w, http.StatusOK, map[string]string{"status": "claimed", "username": body.Username})
 }
+
+// Activity handles GET /api/account/activity
+func (h *ProfileHandler) Activity(w http.ResponseWriter, r *http.Request) {
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	limit, startAfter := parsePagination(r)
+
+	entries, err := h.userService.ListActivity(r.Context(), user.UID, limit, startAfter)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, entries)
+}
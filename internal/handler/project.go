@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"io"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -12,11 +11,16 @@ import (
 // ProjectHandler handles project API endpoints.
 type ProjectHandler struct {
 	projectService *service.ProjectService
+	cursors        *service.CursorCodec
 }
 
-// NewProjectHandler creates a new ProjectHandler.
-func NewProjectHandler(projectService *service.ProjectService) *ProjectHandler {
-	return &ProjectHandler{projectService: projectService}
+// NewProjectHandler creates a new ProjectHandler. cursors signs list cursors; it may be
+// nil to use a per-process key.
+func NewProjectHandler(projectService *service.ProjectService, cursors *service.CursorCodec) *ProjectHandler {
+	if cursors == nil {
+		cursors = service.NewCursorCodec(nil)
+	}
+	return &ProjectHandler{projectService: projectService, cursors: cursors}
 }
 
 // ListProjects handles GET /api/projects
@@ -26,15 +30,24 @@ func (h *ProjectHandler) ListProjects(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	limit, startAfter := parsePagination(r)
+	limit, _ := parsePagination(r)
+	opts, err := parseListOptions(r, h.cursors)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+	fields, ok := parseFields(w, r, model.ProjectSummaryFields)
+	if !ok {
+		return
+	}
 
-	projects, err := h.projectService.ListProjects(r.Context(), user.UID, limit, startAfter)
+	page, err := h.projectService.ListProjects(r.Context(), user.UID, limit, opts, fields)
 	if err != nil {
 		respondError(w, err)
 		return
 	}
 
-	respondJSON(w, http.StatusOK, projects)
+	respondPage(w, r, page, fields, h.cursors)
 }
 
 // GetProject handles GET /api/projects/{id}
@@ -52,7 +65,7 @@ func (h *ProjectHandler) GetProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondJSON(w, http.StatusOK, project)
+	respondCacheableJSON(w, r, project, project.UpdatedAt)
 }
 
 // GetProjectByTitle handles GET /api/projects/by-title?title=...
@@ -177,7 +190,9 @@ func (h *ProjectHandler) UploadBlob(w http.ResponseWriter, r *http.Request) {
 
 // DownloadBlob handles GET /api/projects/{id}/blob — streams the project PNG
 // from Storage through the API so the browser never hits the storage emulator
-// directly (avoids CORS and auth issues).
+// directly (avoids CORS and auth issues). The ETag is the content hash, and
+// conditional and Range requests are honoured. With ?v={contentHash} the URL
+// is content-addressed and cached as immutable.
 func (h *ProjectHandler) DownloadBlob(w http.ResponseWriter, r *http.Request) {
 	user := requireUser(w, r)
 	if user == nil {
@@ -186,18 +201,40 @@ func (h *ProjectHandler) DownloadBlob(w http.ResponseWriter, r *http.Request) {
 
 	projectID := chi.URLParam(r, "id")
 
-	reader, err := h.projectService.DownloadBlob(r.Context(), user.UID, projectID)
+	blob, err := h.projectService.DownloadBlob(r.Context(), user.UID, projectID)
 	if err != nil {
 		respondError(w, err)
 		return
 	}
-	defer reader.Close()
+	defer blob.Content.Close()
+
+	cacheControl := cacheRevalidate
+	if r.URL.Query().Get("v") == blob.ContentHash {
+		cacheControl = cacheImmutable
+	}
 
 	w.Header().Set("Content-Type", "image/png")
 	w.Header().Set("Content-Disposition", "inline; filename=\"canvas.png\"")
-	w.Header().Set("Cache-Control", "no-store")
-	w.WriteHeader(http.StatusOK)
-	io.Copy(w, io.LimitReader(reader, 10<<20)) // cap at 10 MB
+	setCacheControl(w, cacheControl)
+	w.Header().Set("ETag", strongETag(blob.ContentHash))
+	http.ServeContent(w, r, "", blob.ModTime, blob.Content)
+}
+
+// GetThumbnail handles GET /api/projects/{id}/thumbnail — the project's
+// thumbnail as an image, for owners and, on public projects, anyone.
+func (h *ProjectHandler) GetThumbnail(w http.ResponseWriter, r *http.Request) {
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	thumb, err := h.projectService.GetThumbnail(r.Context(), user.UID, chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	serveThumbnail(w, r, thumb)
 }
 
 // CountProjects handles GET /api/projects/count
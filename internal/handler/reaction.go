@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pandasWhoCode/paintbar/internal/service"
+)
+
+// ReactionHandler handles gallery reaction API endpoints.
+type ReactionHandler struct {
+	reactionService *service.ReactionService
+}
+
+// NewReactionHandler creates a new ReactionHandler.
+func NewReactionHandler(reactionService *service.ReactionService) *ReactionHandler {
+	return &ReactionHandler{reactionService: reactionService}
+}
+
+// GetReactions handles GET /api/gallery/{id}/reactions
+func (h *ReactionHandler) GetReactions(w http.ResponseWriter, r *http.Request) {
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	summary, err := h.reactionService.GetReactions(r.Context(), user.UID, chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, summary)
+}
+
+// AddReaction handles POST /api/gallery/{id}/reactions
+func (h *ReactionHandler) AddReaction(w http.ResponseWriter, r *http.Request) {
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	var body struct {
+		Reaction string `json:"reaction"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+
+	if err := h.reactionService.AddReaction(r.Context(), user.UID, chi.URLParam(r, "id"), body.Reaction); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "added"})
+}
+
+// RemoveReaction handles DELETE /api/gallery/{id}/reactions/{reaction}
+func (h *ReactionHandler) RemoveReaction(w http.ResponseWriter, r *http.Request) {
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	err := h.reactionService.RemoveReaction(r.Context(), user.UID,
+		chi.URLParam(r, "id"), chi.URLParam(r, "reaction"))
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "removed"})
+}
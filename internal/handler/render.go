@@ -55,6 +55,28 @@ type PageData struct {
 	Title          string
 	Env            string
 	FirebaseConfig template.JS
+	User           *PageUser // nil for anonymous visitors
+	CSRFToken      string    // session cookie HMAC, echoed in X-CSRF-Token by cookie-authenticated writes
+}
+
+// PageUser is the signed-in user as seen by page templates.
+type PageUser struct {
+	UID         string
+	Email       string
+	Username    string
+	DisplayName string
+}
+
+// Name returns the best available name to greet the user with.
+func (u *PageUser) Name() string {
+	switch {
+	case u.DisplayName != "":
+		return u.DisplayName
+	case u.Username != "":
+		return u.Username
+	default:
+		return u.Email
+	}
 }
 
 // Render renders a named template with the given data.
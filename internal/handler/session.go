@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pandasWhoCode/paintbar/internal/middleware"
+	"github.com/pandasWhoCode/paintbar/internal/service"
+)
+
+// DefaultSessionTTL is how long a session cookie stays valid. Firebase
+// accepts anything between five minutes and two weeks.
+const DefaultSessionTTL = 5 * 24 * time.Hour
+
+// SessionManager creates, verifies and revokes Firebase session cookies.
+// Implemented by service.AuthService.
+type SessionManager interface {
+	CreateSessionCookie(ctx context.Context, idToken string, expiresIn time.Duration) (string, error)
+	VerifySessionCookie(ctx context.Context, cookie string) (*service.UserInfo, error)
+	RevokeSessions(ctx context.Context, uid string) error
+}
+
+// SessionHandler exchanges ID tokens for session cookies used by the SSR
+// page routes.
+type SessionHandler struct {
+	sessions SessionManager
+	ttl      time.Duration
+	secure   bool
+}
+
+// NewSessionHandler creates a new SessionHandler. secure controls the
+// cookie's Secure attribute and should only be false for plain-HTTP local
+// development.
+func NewSessionHandler(sessions SessionManager, ttl time.Duration, secure bool) *SessionHandler {
+	return &SessionHandler{sessions: sessions, ttl: ttl, secure: secure}
+}
+
+// sessionRequest is the request body for POST /auth/session.
+type sessionRequest struct {
+	IDToken string `json:"idToken"`
+}
+
+// CreateSession handles POST /auth/session
+func (h *SessionHandler) CreateSession(w http.ResponseWriter, r *http.Request) {
+	var req sessionRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.IDToken) == "" {
+		respondJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "idToken is required",
+		})
+		return
+	}
+
+	cookie, err := h.sessions.CreateSessionCookie(r.Context(), req.IDToken, h.ttl)
+	if err != nil {
+		slog.Warn("session cookie creation failed",
+			"error", err,
+			"ip", r.RemoteAddr,
+		)
+		msg := "invalid or expired token"
+		if strings.Contains(err.Error(), "recent sign-in required") {
+			msg = "recent sign-in required"
+		}
+		respondJSON(w, http.StatusUnauthorized, map[string]string{"error": msg})
+		return
+	}
+
+	middleware.SetSessionCookie(w, cookie, h.ttl, h.secure)
+	respondJSON(w, http.StatusOK, map[string]string{"status": "created"})
+}
+
+// Logout handles POST /auth/logout. It always clears the cookie; when the
+// cookie is still valid, the user's sessions are also revoked so a copied
+// cookie stops working.
+func (h *SessionHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(middleware.SessionCookieName); err == nil && cookie.Value != "" {
+		if user, err := h.sessions.VerifySessionCookie(r.Context(), cookie.Value); err == nil {
+			if err := h.sessions.RevokeSessions(r.Context(), user.UID); err != nil {
+				slog.Error("session revocation failed",
+					"error", err,
+					"uid", user.UID,
+				)
+			}
+		}
+	}
+
+	middleware.ClearSessionCookie(w, h.secure)
+	respondJSON(w, http.StatusOK, map[string]string{"status": "logged_out"})
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"github.com/pandasWhoCode/paintbar/internal/service"
+)
+
+// TokenHandler handles personal access token management endpoints. The
+// routes are gated by middleware.DenyAPITokens so a token cannot mint or
+// revoke tokens.
+type TokenHandler struct {
+	tokenService *service.APITokenService
+}
+
+// NewTokenHandler creates a new TokenHandler.
+func NewTokenHandler(tokenService *service.APITokenService) *TokenHandler {
+	return &TokenHandler{tokenService: tokenService}
+}
+
+// CreateToken handles POST /api/tokens
+func (h *TokenHandler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	var req model.APITokenCreate
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	created, err := h.tokenService.CreateToken(r.Context(), user.UID, &req)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, created)
+}
+
+// ListTokens handles GET /api/tokens
+func (h *TokenHandler) ListTokens(w http.ResponseWriter, r *http.Request) {
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	tokens, err := h.tokenService.ListTokens(r.Context(), user.UID)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tokens)
+}
+
+// RevokeToken handles DELETE /api/tokens/{id}
+func (h *TokenHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	if err := h.tokenService.RevokeToken(r.Context(), user.UID, chi.URLParam(r, "id")); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "revoked"})
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"github.com/pandasWhoCode/paintbar/internal/service"
+)
+
+// UploadHandler handles resumable project upload endpoints.
+type UploadHandler struct {
+	uploadService *service.UploadService
+}
+
+// NewUploadHandler creates a new UploadHandler.
+func NewUploadHandler(uploadService *service.UploadService) *UploadHandler {
+	return &UploadHandler{uploadService: uploadService}
+}
+
+// InitiateUpload handles POST /api/projects/{id}/uploads
+func (h *UploadHandler) InitiateUpload(w http.ResponseWriter, r *http.Request) {
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	var req model.UploadInit
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	session, err := h.uploadService.InitiateUpload(r.Context(), user.UID, chi.URLParam(r, "id"), &req)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, session)
+}
+
+// GetUpload handles GET /api/projects/{id}/uploads/{uploadId}
+func (h *UploadHandler) GetUpload(w http.ResponseWriter, r *http.Request) {
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	session, err := h.uploadService.GetUpload(r.Context(), user.UID, chi.URLParam(r, "id"), chi.URLParam(r, "uploadId"))
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, session)
+}
+
+// WriteChunk handles PUT /api/projects/{id}/uploads/{uploadId} — the body is
+// the byte range named by the Content-Range header.
+func (h *UploadHandler) WriteChunk(w http.ResponseWriter, r *http.Request) {
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	start, size, total, err := parseContentRange(r.Header.Get("Content-Range"))
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, model.MaxUploadChunkSize)
+
+	session, err := h.uploadService.WriteChunk(r.Context(), user.UID, chi.URLParam(r, "id"), chi.URLParam(r, "uploadId"), start, size, total, r.Body)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, session)
+}
+
+// FinalizeUpload handles POST /api/projects/{id}/uploads/{uploadId}/finalize
+func (h *UploadHandler) FinalizeUpload(w http.ResponseWriter, r *http.Request) {
+	user := requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	session, err := h.uploadService.FinalizeUpload(r.Context(), user.UID, chi.URLParam(r, "id"), chi.URLParam(r, "uploadId"))
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, session)
+}
+
+// parseContentRange parses "bytes start-end/total" into the chunk's start,
+// its length, and the total size.
+func parseContentRange(header string) (start, size, total int64, err error) {
+	if header == "" {
+		return 0, 0, 0, fmt.Errorf("Content-Range header is required")
+	}
+	spec, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("invalid Content-Range: unit must be bytes")
+	}
+	rng, totalStr, ok := strings.Cut(spec, "/")
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("invalid Content-Range: total size is required")
+	}
+	startStr, endStr, ok := strings.Cut(rng, "-")
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("invalid Content-Range: range must be start-end")
+	}
+
+	start, err1 := strconv.ParseInt(startStr, 10, 64)
+	end, err2 := strconv.ParseInt(endStr, 10, 64)
+	total, err3 := strconv.ParseInt(totalStr, 10, 64)
+	if err1 != nil || err2 != nil || err3 != nil || start < 0 || end < start || end >= total {
+		return 0, 0, 0, fmt.Errorf("invalid Content-Range %q", header)
+	}
+	return start, end - start + 1, total, nil
+}
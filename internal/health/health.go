@@ -0,0 +1,162 @@
+// Package health reports whether the server can take traffic. Liveness only
+// says the process is up; readiness runs a check per dependency, each with
+// its own timeout, and caches the results so probes from every load
+// balancer do not each reach Firestore, Storage and Auth.
+package health
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Report statuses.
+const (
+	StatusOK          = "ok"          // every check passed
+	StatusDegraded    = "degraded"    // only non-critical checks failed
+	StatusUnavailable = "unavailable" // a critical check failed
+	StatusDraining    = "draining"    // shutting down
+	StatusError       = "error"       // a single check failed
+)
+
+// Check is one dependency readiness depends on.
+type Check struct {
+	Name string
+	// Critical checks make the server not ready when they fail; failures
+	// of the others are reported but the server keeps taking traffic.
+	Critical bool
+	Run      func(ctx context.Context) error
+}
+
+// Config tunes a Checker.
+type Config struct {
+	Timeout  time.Duration // limit for a single check (default 2s)
+	CacheTTL time.Duration // how long a check's result is reused (default 5s)
+}
+
+func (c *Config) setDefaults() {
+	if c.Timeout <= 0 {
+		c.Timeout = 2 * time.Second
+	}
+	if c.CacheTTL <= 0 {
+		c.CacheTTL = 5 * time.Second
+	}
+}
+
+// Result is the outcome of one check. Errors are logged rather than
+// returned, since the endpoints are public.
+type Result struct {
+	Status    string    `json:"status"`
+	Critical  bool      `json:"critical"`
+	LatencyMs int64     `json:"latencyMs"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// Report is the readiness of the server and each of its dependencies.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Ready reports whether the server should receive traffic.
+func (r *Report) Ready() bool {
+	return r.Status == StatusOK || r.Status == StatusDegraded
+}
+
+// Checker runs the readiness checks.
+type Checker struct {
+	cfg      Config
+	probes   []*probe
+	draining atomic.Bool
+	now      func() time.Time
+}
+
+// probe is a check and its last result. mu is held while the check runs,
+// so concurrent requests wait for one run instead of starting their own.
+type probe struct {
+	Check
+	mu   sync.Mutex
+	last *Result
+}
+
+// NewChecker creates a Checker for checks.
+func NewChecker(checks []Check, cfg Config) *Checker {
+	cfg.setDefaults()
+	probes := make([]*probe, len(checks))
+	for i, c := range checks {
+		probes[i] = &probe{Check: c}
+	}
+	return &Checker{cfg: cfg, probes: probes, now: time.Now}
+}
+
+// Drain marks the server as shutting down. Readiness fails from then on, so
+// load balancers stop sending traffic before the listener closes.
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// Check runs the checks whose cached results have expired, in parallel,
+// and reports on all of them. While draining no checks are run.
+func (c *Checker) Check(ctx context.Context) *Report {
+	report := &Report{Status: StatusOK, Checks: make(map[string]Result, len(c.probes))}
+	if c.draining.Load() {
+		report.Status = StatusDraining
+		return report
+	}
+
+	results := make([]Result, len(c.probes))
+	var wg sync.WaitGroup
+	for i, p := range c.probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx, p)
+		}()
+	}
+	wg.Wait()
+
+	for i, p := range c.probes {
+		res := results[i]
+		report.Checks[p.Name] = res
+		if res.Status == StatusOK {
+			continue
+		}
+		if p.Critical {
+			report.Status = StatusUnavailable
+		} else if report.Status == StatusOK {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+// run returns p's cached result, or runs it when the cache has expired.
+func (c *Checker) run(ctx context.Context, p *probe) Result {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.last != nil && c.now().Sub(p.last.CheckedAt) < c.cfg.CacheTTL {
+		return *p.last
+	}
+
+	// A probe that gives up early must not shorten the check for the
+	// requests waiting on the cached result
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.cfg.Timeout)
+	defer cancel()
+	start := c.now()
+	err := p.Run(ctx)
+
+	res := Result{
+		Status:    StatusOK,
+		Critical:  p.Critical,
+		LatencyMs: c.now().Sub(start).Milliseconds(),
+		CheckedAt: start,
+	}
+	if err != nil {
+		slog.Warn("readiness check failed", "check", p.Name, "critical", p.Critical, "error", err)
+		res.Status = StatusError
+	}
+	p.last = &res
+	return res
+}
@@ -0,0 +1,116 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// counted returns a check function that fails with err and counts its runs.
+func counted(runs *atomic.Int32, err error) func(context.Context) error {
+	return func(context.Context) error {
+		runs.Add(1)
+		return err
+	}
+}
+
+func TestChecker_AllPass(t *testing.T) {
+	var runs atomic.Int32
+	c := NewChecker([]Check{
+		{Name: "firestore", Critical: true, Run: counted(&runs, nil)},
+		{Name: "storage", Run: counted(&runs, nil)},
+	}, Config{})
+
+	report := c.Check(context.Background())
+	assert.Equal(t, StatusOK, report.Status)
+	assert.True(t, report.Ready())
+	require.Len(t, report.Checks, 2)
+	assert.Equal(t, StatusOK, report.Checks["firestore"].Status)
+	assert.True(t, report.Checks["firestore"].Critical)
+	assert.False(t, report.Checks["storage"].Critical)
+	assert.EqualValues(t, 2, runs.Load())
+}
+
+func TestChecker_Status(t *testing.T) {
+	down := errors.New("connection refused")
+	tests := []struct {
+		name              string
+		critical, another error
+		want              string
+		ready             bool
+	}{
+		{"non-critical down", nil, down, StatusDegraded, true},
+		{"critical down", down, nil, StatusUnavailable, false},
+		{"both down", down, down, StatusUnavailable, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var runs atomic.Int32
+			c := NewChecker([]Check{
+				{Name: "firestore", Critical: true, Run: counted(&runs, tt.critical)},
+				{Name: "storage", Run: counted(&runs, tt.another)},
+			}, Config{})
+
+			report := c.Check(context.Background())
+			assert.Equal(t, tt.want, report.Status)
+			assert.Equal(t, tt.ready, report.Ready())
+		})
+	}
+}
+
+func TestChecker_CachesResults(t *testing.T) {
+	var runs atomic.Int32
+	c := NewChecker([]Check{{Name: "firestore", Critical: true, Run: counted(&runs, errors.New("down"))}}, Config{CacheTTL: time.Minute})
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	first := c.Check(context.Background())
+	second := c.Check(context.Background())
+	assert.EqualValues(t, 1, runs.Load(), "failures are cached too")
+	assert.Equal(t, first.Checks["firestore"].CheckedAt, second.Checks["firestore"].CheckedAt)
+
+	now = now.Add(time.Minute)
+	c.Check(context.Background())
+	assert.EqualValues(t, 2, runs.Load(), "expired results are checked again")
+}
+
+func TestChecker_TimesOutSlowChecks(t *testing.T) {
+	c := NewChecker([]Check{{Name: "auth", Critical: true, Run: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}}, Config{Timeout: 10 * time.Millisecond})
+
+	start := time.Now()
+	report := c.Check(context.Background())
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, StatusUnavailable, report.Status)
+	assert.Equal(t, StatusError, report.Checks["auth"].Status)
+}
+
+func TestChecker_ReportsLatency(t *testing.T) {
+	c := NewChecker([]Check{{Name: "storage", Run: func(context.Context) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}}}, Config{})
+
+	report := c.Check(context.Background())
+	assert.GreaterOrEqual(t, report.Checks["storage"].LatencyMs, int64(20))
+}
+
+func TestChecker_Drain(t *testing.T) {
+	var runs atomic.Int32
+	c := NewChecker([]Check{{Name: "firestore", Critical: true, Run: counted(&runs, nil)}}, Config{})
+	require.True(t, c.Check(context.Background()).Ready())
+
+	c.Drain()
+	report := c.Check(context.Background())
+	assert.Equal(t, StatusDraining, report.Status)
+	assert.False(t, report.Ready())
+	assert.Empty(t, report.Checks)
+	assert.EqualValues(t, 1, runs.Load(), "no checks run while draining")
+}
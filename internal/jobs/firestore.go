@@ -0,0 +1,230 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// claimBatch is how many candidate jobs Claim reads per query. Candidates
+// another worker claims first are skipped.
+const claimBatch = 10
+
+// firestoreStore implements Store using the top-level jobs collection.
+// Lease changes run in transactions so two workers cannot claim the same job.
+type firestoreStore struct {
+	client *firestore.Client
+}
+
+// NewFirestoreStore creates a new Firestore-backed Store.
+func NewFirestoreStore(client *firestore.Client) Store {
+	return &firestoreStore{client: client}
+}
+
+func (s *firestoreStore) jobs() *firestore.CollectionRef {
+	return s.client.Collection("jobs")
+}
+
+// Enqueue implements Store. Jobs with an idempotency key use a document ID
+// derived from the key, so a duplicate fails Create and the existing job is
+// returned instead.
+func (s *firestoreStore) Enqueue(ctx context.Context, job *Job) (*Job, bool, error) {
+	if job.IdempotencyKey == "" {
+		ref, _, err := s.jobs().Add(ctx, job)
+		if err != nil {
+			return nil, false, fmt.Errorf("create job: %w", err)
+		}
+		job.ID = ref.ID
+		return job, true, nil
+	}
+
+	id := idempotencyID(job.Type, job.UID, job.IdempotencyKey)
+	_, err := s.jobs().Doc(id).Create(ctx, job)
+	if status.Code(err) == codes.AlreadyExists {
+		existing, err := s.Get(ctx, id)
+		if err != nil {
+			return nil, false, err
+		}
+		return existing, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("create job: %w", err)
+	}
+	job.ID = id
+	return job, true, nil
+}
+
+// Get implements Store.
+func (s *firestoreStore) Get(ctx context.Context, jobID string) (*Job, error) {
+	doc, err := s.jobs().Doc(jobID).Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get job %s: %w", jobID, err)
+	}
+	return decodeJob(doc)
+}
+
+// Claim implements Store. Due queued jobs are tried first, then running jobs
+// whose lease has expired (their worker crashed or lost connectivity).
+func (s *firestoreStore) Claim(ctx context.Context, owner string, now, leaseUntil time.Time) (*Job, error) {
+	queries := []firestore.Query{
+		s.jobs().
+			Where("status", "==", string(StatusQueued)).
+			Where("runAt", "<=", now).
+			OrderBy("runAt", firestore.Asc).
+			Limit(claimBatch),
+		s.jobs().
+			Where("status", "==", string(StatusRunning)).
+			Where("leaseExpiresAt", "<", now).
+			OrderBy("leaseExpiresAt", firestore.Asc).
+			Limit(claimBatch),
+	}
+
+	for _, q := range queries {
+		refs, err := candidateRefs(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range refs {
+			job, err := s.tryClaim(ctx, ref, owner, now, leaseUntil)
+			if err != nil {
+				return nil, err
+			}
+			if job != nil {
+				return job, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// candidateRefs runs a claim query and returns the matching document refs.
+func candidateRefs(ctx context.Context, q firestore.Query) ([]*firestore.DocumentRef, error) {
+	iter := q.Documents(ctx)
+	defer iter.Stop()
+
+	var refs []*firestore.DocumentRef
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("query claimable jobs: %w", err)
+		}
+		refs = append(refs, doc.Ref)
+	}
+	return refs, nil
+}
+
+// tryClaim leases one candidate in a transaction. It returns nil, nil if the
+// job stopped being claimable since the query ran.
+func (s *firestoreStore) tryClaim(ctx context.Context, ref *firestore.DocumentRef, owner string, now, leaseUntil time.Time) (*Job, error) {
+	var claimed *Job
+	err := s.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		claimed = nil
+		doc, err := tx.Get(ref)
+		if err != nil {
+			return err
+		}
+		job, err := decodeJob(doc)
+		if err != nil {
+			return err
+		}
+		if !job.claimable(now) {
+			return nil
+		}
+
+		job.Status = StatusRunning
+		job.Attempts++
+		job.LeaseOwner = owner
+		job.LeaseExpiresAt = &leaseUntil
+		job.UpdatedAt = now
+		claimed = job
+		return tx.Update(ref, []firestore.Update{
+			{Path: "status", Value: string(StatusRunning)},
+			{Path: "attempts", Value: job.Attempts},
+			{Path: "leaseOwner", Value: owner},
+			{Path: "leaseExpiresAt", Value: leaseUntil},
+			{Path: "updatedAt", Value: now},
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("claim job %s: %w", ref.ID, err)
+	}
+	return claimed, nil
+}
+
+// Heartbeat implements Store.
+func (s *firestoreStore) Heartbeat(ctx context.Context, jobID, owner string, leaseUntil time.Time) error {
+	return s.update(ctx, jobID, owner, []firestore.Update{
+		{Path: "leaseExpiresAt", Value: leaseUntil},
+	})
+}
+
+// Complete implements Store.
+func (s *firestoreStore) Complete(ctx context.Context, jobID, owner string, result map[string]interface{}) error {
+	return s.update(ctx, jobID, owner, []firestore.Update{
+		{Path: "status", Value: string(StatusSucceeded)},
+		{Path: "result", Value: result},
+		{Path: "lastError", Value: firestore.Delete},
+		{Path: "completedAt", Value: time.Now()},
+		{Path: "leaseOwner", Value: firestore.Delete},
+		{Path: "leaseExpiresAt", Value: firestore.Delete},
+	})
+}
+
+// Retry implements Store.
+func (s *firestoreStore) Retry(ctx context.Context, jobID, owner string, runAt time.Time, lastError string) error {
+	return s.update(ctx, jobID, owner, []firestore.Update{
+		{Path: "status", Value: string(StatusQueued)},
+		{Path: "runAt", Value: runAt},
+		{Path: "lastError", Value: lastError},
+		{Path: "leaseOwner", Value: firestore.Delete},
+		{Path: "leaseExpiresAt", Value: firestore.Delete},
+	})
+}
+
+// Fail implements Store.
+func (s *firestoreStore) Fail(ctx context.Context, jobID, owner string, lastError string) error {
+	return s.update(ctx, jobID, owner, []firestore.Update{
+		{Path: "status", Value: string(StatusFailed)},
+		{Path: "lastError", Value: lastError},
+		{Path: "completedAt", Value: time.Now()},
+		{Path: "leaseOwner", Value: firestore.Delete},
+		{Path: "leaseExpiresAt", Value: firestore.Delete},
+	})
+}
+
+// update applies updates to a running job leased to owner.
+func (s *firestoreStore) update(ctx context.Context, jobID, owner string, updates []firestore.Update) error {
+	ref := s.jobs().Doc(jobID)
+	return s.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if err != nil {
+			return fmt.Errorf("get job %s: %w", jobID, err)
+		}
+		job, err := decodeJob(doc)
+		if err != nil {
+			return err
+		}
+		if job.Status != StatusRunning || job.LeaseOwner != owner {
+			return ErrLeaseLost
+		}
+		return tx.Update(ref, append(updates, firestore.Update{Path: "updatedAt", Value: time.Now()}))
+	})
+}
+
+// decodeJob converts a snapshot into a Job.
+func decodeJob(doc *firestore.DocumentSnapshot) (*Job, error) {
+	var job Job
+	if err := doc.DataTo(&job); err != nil {
+		return nil, fmt.Errorf("decode job %s: %w", doc.Ref.ID, err)
+	}
+	job.ID = doc.Ref.ID
+	return &job, nil
+}
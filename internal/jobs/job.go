@@ -0,0 +1,136 @@
+// Package jobs runs work that cannot finish inside an HTTP request, such as
+// thumbnail generation, exports and cleanup. Jobs are persisted in a Store,
+// claimed by a Runner under a time-limited lease that the worker renews with
+// heartbeats, and retried with exponential backoff until they succeed or run
+// out of attempts.
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Status is the lifecycle state of a job.
+type Status string
+
+// Job states. A job moves from queued to running when a worker claims it and
+// back to queued when an attempt fails and may be retried.
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+// DefaultMaxAttempts is used when a job is enqueued without MaxAttempts.
+const DefaultMaxAttempts = 5
+
+// MaxIdempotencyKeyLen bounds client-supplied idempotency keys.
+const MaxIdempotencyKeyLen = 200
+
+// ErrLeaseLost is returned by Store methods when the caller no longer holds
+// the job's lease, e.g. because it expired and another worker claimed it.
+var ErrLeaseLost = errors.New("job lease lost")
+
+// Job is one unit of background work, stored in the jobs collection.
+type Job struct {
+	ID             string                 `firestore:"-" json:"id"`
+	Type           string                 `firestore:"type" json:"type"`
+	UID            string                 `firestore:"uid,omitempty" json:"-"`
+	Payload        map[string]interface{} `firestore:"payload,omitempty" json:"-"`
+	IdempotencyKey string                 `firestore:"idempotencyKey,omitempty" json:"-"`
+	Status         Status                 `firestore:"status" json:"status"`
+	Attempts       int                    `firestore:"attempts" json:"attempts"`
+	MaxAttempts    int                    `firestore:"maxAttempts" json:"maxAttempts"`
+	RunAt          time.Time              `firestore:"runAt" json:"runAt"`
+	LeaseOwner     string                 `firestore:"leaseOwner,omitempty" json:"-"`
+	LeaseExpiresAt *time.Time             `firestore:"leaseExpiresAt,omitempty" json:"-"`
+	LastError      string                 `firestore:"lastError,omitempty" json:"lastError,omitempty"`
+	Result         map[string]interface{} `firestore:"result,omitempty" json:"result,omitempty"`
+	CreatedAt      time.Time              `firestore:"createdAt" json:"createdAt"`
+	UpdatedAt      time.Time              `firestore:"updatedAt" json:"updatedAt"`
+	CompletedAt    *time.Time             `firestore:"completedAt,omitempty" json:"completedAt,omitempty"`
+}
+
+// Done reports whether the job has reached a final state.
+func (j *Job) Done() bool {
+	return j.Status == StatusSucceeded || j.Status == StatusFailed
+}
+
+// DecodePayload unmarshals the job payload into v, which should be a pointer
+// to a struct with json tags.
+func (j *Job) DecodePayload(v interface{}) error {
+	b, err := json.Marshal(j.Payload)
+	if err != nil {
+		return fmt.Errorf("encode job payload: %w", err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("decode job payload: %w", err)
+	}
+	return nil
+}
+
+// claimable reports whether a worker may claim the job at now: it is queued
+// and due, or it is running under a lease that has expired.
+func (j *Job) claimable(now time.Time) bool {
+	switch j.Status {
+	case StatusQueued:
+		return !j.RunAt.After(now)
+	case StatusRunning:
+		return j.LeaseExpiresAt != nil && j.LeaseExpiresAt.Before(now)
+	default:
+		return false
+	}
+}
+
+// EnqueueRequest describes a job to add to the queue.
+type EnqueueRequest struct {
+	Type    string
+	UID     string // owner allowed to read the job's status; empty for system jobs
+	Payload map[string]interface{}
+	// IdempotencyKey makes Enqueue return the existing job, in any state,
+	// instead of creating a duplicate. Keys are scoped to Type and UID.
+	IdempotencyKey string
+	MaxAttempts    int
+	RunAt          time.Time // zero means now
+}
+
+// validate checks the request fields.
+func (r *EnqueueRequest) validate() error {
+	if strings.TrimSpace(r.Type) == "" {
+		return fmt.Errorf("job type is required")
+	}
+	if len(r.IdempotencyKey) > MaxIdempotencyKeyLen {
+		return fmt.Errorf("idempotency key must be %d characters or less", MaxIdempotencyKeyLen)
+	}
+	if r.MaxAttempts < 0 {
+		return fmt.Errorf("max attempts must be positive")
+	}
+	return nil
+}
+
+// permanentError marks an error that should not be retried.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so the runner fails the job immediately instead of
+// retrying it, e.g. for a malformed payload.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was wrapped with Permanent.
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
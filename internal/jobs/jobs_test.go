@@ -0,0 +1,327 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig() Config {
+	return Config{
+		Workers:           2,
+		PollInterval:      5 * time.Millisecond,
+		LeaseDuration:     time.Second,
+		HeartbeatInterval: 20 * time.Millisecond,
+		BaseBackoff:       time.Millisecond,
+		MaxBackoff:        4 * time.Millisecond,
+	}
+}
+
+// waitForJob polls until the job reaches a final state.
+func waitForJob(t *testing.T, r *Runner, id string) *Job {
+	t.Helper()
+	var job *Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = r.Get(context.Background(), id)
+		require.NoError(t, err)
+		return job.Done()
+	}, 5*time.Second, 5*time.Millisecond)
+	return job
+}
+
+func shutdown(t *testing.T, r *Runner) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, r.Shutdown(ctx))
+}
+
+func TestRunner_RunsJob(t *testing.T) {
+	r := NewRunner(NewMemoryStore(), testConfig())
+	r.Register("thumbnail", func(_ context.Context, job *Job) (map[string]interface{}, error) {
+		var p struct {
+			ProjectID string `json:"projectId"`
+		}
+		if err := job.DecodePayload(&p); err != nil {
+			return nil, Permanent(err)
+		}
+		return map[string]interface{}{"thumbnail": p.ProjectID + ".png"}, nil
+	})
+	r.Start()
+	defer shutdown(t, r)
+
+	job, err := r.Enqueue(context.Background(), EnqueueRequest{
+		Type:    "thumbnail",
+		UID:     "u1",
+		Payload: map[string]interface{}{"projectId": "p1"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, StatusQueued, job.Status)
+	assert.Equal(t, DefaultMaxAttempts, job.MaxAttempts)
+
+	done := waitForJob(t, r, job.ID)
+	assert.Equal(t, StatusSucceeded, done.Status)
+	assert.Equal(t, 1, done.Attempts)
+	assert.Equal(t, "p1.png", done.Result["thumbnail"])
+	assert.NotNil(t, done.CompletedAt)
+	assert.Empty(t, done.LeaseOwner)
+}
+
+func TestRunner_RetriesThenSucceeds(t *testing.T) {
+	var calls atomic.Int32
+	r := NewRunner(NewMemoryStore(), testConfig())
+	r.Register("flaky", func(context.Context, *Job) (map[string]interface{}, error) {
+		if calls.Add(1) < 3 {
+			return nil, errors.New("storage unavailable")
+		}
+		return nil, nil
+	})
+	r.Start()
+	defer shutdown(t, r)
+
+	job, err := r.Enqueue(context.Background(), EnqueueRequest{Type: "flaky"})
+	require.NoError(t, err)
+
+	done := waitForJob(t, r, job.ID)
+	assert.Equal(t, StatusSucceeded, done.Status)
+	assert.Equal(t, 3, done.Attempts)
+	assert.Empty(t, done.LastError)
+}
+
+func TestRunner_GivesUpAfterMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	r := NewRunner(NewMemoryStore(), testConfig())
+	r.Register("broken", func(context.Context, *Job) (map[string]interface{}, error) {
+		calls.Add(1)
+		return nil, errors.New("boom")
+	})
+	r.Start()
+	defer shutdown(t, r)
+
+	job, err := r.Enqueue(context.Background(), EnqueueRequest{Type: "broken", MaxAttempts: 3})
+	require.NoError(t, err)
+
+	done := waitForJob(t, r, job.ID)
+	assert.Equal(t, StatusFailed, done.Status)
+	assert.Equal(t, 3, done.Attempts)
+	assert.Equal(t, "boom", done.LastError)
+	assert.EqualValues(t, 3, calls.Load())
+}
+
+func TestRunner_PermanentErrorAndPanicAreNotRetried(t *testing.T) {
+	r := NewRunner(NewMemoryStore(), testConfig())
+	r.Register("bad-payload", func(context.Context, *Job) (map[string]interface{}, error) {
+		return nil, Permanent(errors.New("missing projectId"))
+	})
+	r.Register("panics", func(context.Context, *Job) (map[string]interface{}, error) {
+		panic("nil map")
+	})
+	r.Start()
+	defer shutdown(t, r)
+
+	bad, err := r.Enqueue(context.Background(), EnqueueRequest{Type: "bad-payload"})
+	require.NoError(t, err)
+	panicky, err := r.Enqueue(context.Background(), EnqueueRequest{Type: "panics"})
+	require.NoError(t, err)
+
+	done := waitForJob(t, r, bad.ID)
+	assert.Equal(t, StatusFailed, done.Status)
+	assert.Equal(t, 1, done.Attempts)
+
+	done = waitForJob(t, r, panicky.ID)
+	assert.Equal(t, StatusFailed, done.Status)
+	assert.Equal(t, 1, done.Attempts)
+	assert.Contains(t, done.LastError, "panic")
+}
+
+func TestRunner_IdempotencyKey(t *testing.T) {
+	r := NewRunner(NewMemoryStore(), testConfig())
+	r.Register("export", func(context.Context, *Job) (map[string]interface{}, error) { return nil, nil })
+	ctx := context.Background()
+
+	first, err := r.Enqueue(ctx, EnqueueRequest{Type: "export", UID: "u1", IdempotencyKey: "k1"})
+	require.NoError(t, err)
+	second, err := r.Enqueue(ctx, EnqueueRequest{Type: "export", UID: "u1", IdempotencyKey: "k1"})
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, second.ID)
+
+	other, err := r.Enqueue(ctx, EnqueueRequest{Type: "export", UID: "u2", IdempotencyKey: "k1"})
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, other.ID, "keys are scoped per user")
+}
+
+func TestRunner_Enqueue_Invalid(t *testing.T) {
+	r := NewRunner(NewMemoryStore(), testConfig())
+	r.Register("export", func(context.Context, *Job) (map[string]interface{}, error) { return nil, nil })
+	ctx := context.Background()
+
+	_, err := r.Enqueue(ctx, EnqueueRequest{})
+	assert.ErrorContains(t, err, "job type is required")
+
+	_, err = r.Enqueue(ctx, EnqueueRequest{Type: "unknown"})
+	assert.ErrorContains(t, err, "invalid job type")
+
+	_, err = r.Enqueue(ctx, EnqueueRequest{Type: "export", MaxAttempts: -1})
+	assert.ErrorContains(t, err, "validation")
+}
+
+func TestRunner_DelayedJob(t *testing.T) {
+	r := NewRunner(NewMemoryStore(), testConfig())
+	r.Register("later", func(context.Context, *Job) (map[string]interface{}, error) { return nil, nil })
+	r.Start()
+	defer shutdown(t, r)
+
+	runAt := time.Now().Add(100 * time.Millisecond)
+	job, err := r.Enqueue(context.Background(), EnqueueRequest{Type: "later", RunAt: runAt})
+	require.NoError(t, err)
+
+	done := waitForJob(t, r, job.ID)
+	assert.False(t, done.CompletedAt.Before(runAt))
+}
+
+func TestRunner_HeartbeatKeepsLease(t *testing.T) {
+	cfg := testConfig()
+	cfg.LeaseDuration = 50 * time.Millisecond
+	cfg.HeartbeatInterval = 10 * time.Millisecond
+
+	var calls atomic.Int32
+	r := NewRunner(NewMemoryStore(), cfg)
+	r.Register("slow", func(ctx context.Context, _ *Job) (map[string]interface{}, error) {
+		calls.Add(1)
+		select {
+		case <-time.After(200 * time.Millisecond):
+			return nil, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	})
+	r.Start()
+	defer shutdown(t, r)
+
+	job, err := r.Enqueue(context.Background(), EnqueueRequest{Type: "slow"})
+	require.NoError(t, err)
+
+	done := waitForJob(t, r, job.ID)
+	assert.Equal(t, StatusSucceeded, done.Status)
+	assert.EqualValues(t, 1, calls.Load(), "the other worker must not steal a renewed lease")
+}
+
+func TestRunner_JobTimeout(t *testing.T) {
+	cfg := testConfig()
+	cfg.JobTimeout = 20 * time.Millisecond
+
+	r := NewRunner(NewMemoryStore(), cfg)
+	r.Register("hangs", func(ctx context.Context, _ *Job) (map[string]interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	r.Start()
+	defer shutdown(t, r)
+
+	job, err := r.Enqueue(context.Background(), EnqueueRequest{Type: "hangs", MaxAttempts: 1})
+	require.NoError(t, err)
+
+	done := waitForJob(t, r, job.ID)
+	assert.Equal(t, StatusFailed, done.Status)
+	assert.Contains(t, done.LastError, "deadline exceeded")
+}
+
+func TestRunner_ShutdownDrains(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	r := NewRunner(NewMemoryStore(), testConfig())
+	r.Register("drain", func(context.Context, *Job) (map[string]interface{}, error) {
+		close(started)
+		<-release
+		return map[string]interface{}{"ok": true}, nil
+	})
+	r.Start()
+
+	job, err := r.Enqueue(context.Background(), EnqueueRequest{Type: "drain"})
+	require.NoError(t, err)
+	<-started
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	var shutdownErr error
+	go func() {
+		defer wg.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		shutdownErr = r.Shutdown(ctx)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	require.NoError(t, shutdownErr)
+
+	done, err := r.Get(context.Background(), job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusSucceeded, done.Status)
+}
+
+func TestRunner_ShutdownTimeoutCancelsJobs(t *testing.T) {
+	r := NewRunner(NewMemoryStore(), testConfig())
+	started := make(chan struct{})
+	r.Register("stuck", func(ctx context.Context, _ *Job) (map[string]interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	r.Start()
+
+	_, err := r.Enqueue(context.Background(), EnqueueRequest{Type: "stuck"})
+	require.NoError(t, err)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, r.Shutdown(ctx), context.DeadlineExceeded)
+}
+
+func TestMemoryStore_ReclaimsExpiredLease(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	now := time.Now()
+
+	job, created, err := s.Enqueue(ctx, &Job{Type: "t", Status: StatusQueued, MaxAttempts: 3, RunAt: now})
+	require.NoError(t, err)
+	require.True(t, created)
+
+	claimed, err := s.Claim(ctx, "w1", now, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	assert.Equal(t, 1, claimed.Attempts)
+
+	none, err := s.Claim(ctx, "w2", now.Add(time.Second), now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Nil(t, none, "lease is still held")
+
+	later := now.Add(2 * time.Minute)
+	reclaimed, err := s.Claim(ctx, "w2", later, later.Add(time.Minute))
+	require.NoError(t, err)
+	require.NotNil(t, reclaimed)
+	assert.Equal(t, job.ID, reclaimed.ID)
+	assert.Equal(t, 2, reclaimed.Attempts)
+
+	assert.ErrorIs(t, s.Heartbeat(ctx, job.ID, "w1", later), ErrLeaseLost)
+	assert.ErrorIs(t, s.Complete(ctx, job.ID, "w1", nil), ErrLeaseLost)
+	assert.NoError(t, s.Complete(ctx, job.ID, "w2", nil))
+}
+
+func TestRunner_Backoff(t *testing.T) {
+	r := NewRunner(NewMemoryStore(), Config{BaseBackoff: time.Second, MaxBackoff: 10 * time.Second})
+	assert.Equal(t, time.Second, r.backoff(1))
+	assert.Equal(t, 2*time.Second, r.backoff(2))
+	assert.Equal(t, 8*time.Second, r.backoff(4))
+	assert.Equal(t, 10*time.Second, r.backoff(5))
+	assert.Equal(t, 10*time.Second, r.backoff(30))
+}
@@ -0,0 +1,344 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/pandasWhoCode/paintbar/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Handler runs one attempt of a job. The context is cancelled when the job
+// times out, its lease is lost, or the runner is shut down without time to
+// drain; handlers must stop promptly and be safe to run again. The returned
+// result is stored on the job when it succeeds. Wrap an error with Permanent
+// to fail the job without retrying.
+type Handler func(ctx context.Context, job *Job) (map[string]interface{}, error)
+
+// Config tunes a Runner. Zero values take the defaults below.
+type Config struct {
+	Workers           int           // concurrent jobs (default 4)
+	PollInterval      time.Duration // idle wait between claim attempts (default 2s)
+	LeaseDuration     time.Duration // lease granted per claim and heartbeat (default 1m)
+	HeartbeatInterval time.Duration // lease renewal period (default LeaseDuration/3)
+	JobTimeout        time.Duration // limit for a single attempt (default 10m)
+	BaseBackoff       time.Duration // delay before the first retry (default 10s)
+	MaxBackoff        time.Duration // cap on the retry delay (default 30m)
+}
+
+func (c *Config) setDefaults() {
+	if c.Workers <= 0 {
+		c.Workers = 4
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = 2 * time.Second
+	}
+	if c.LeaseDuration <= 0 {
+		c.LeaseDuration = time.Minute
+	}
+	if c.HeartbeatInterval <= 0 {
+		c.HeartbeatInterval = c.LeaseDuration / 3
+	}
+	if c.JobTimeout <= 0 {
+		c.JobTimeout = 10 * time.Minute
+	}
+	if c.BaseBackoff <= 0 {
+		c.BaseBackoff = 10 * time.Second
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = 30 * time.Minute
+	}
+}
+
+// Runner enqueues jobs and executes them with a pool of workers. Register
+// handlers before calling Start.
+type Runner struct {
+	store    Store
+	cfg      Config
+	owner    string
+	handlers map[string]Handler
+
+	wake    chan struct{}
+	stop    chan struct{}
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+}
+
+// NewRunner creates a Runner backed by store.
+func NewRunner(store Store, cfg Config) *Runner {
+	cfg.setDefaults()
+	return &Runner{
+		store:    store,
+		cfg:      cfg,
+		owner:    newOwnerID(),
+		handlers: make(map[string]Handler),
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+	}
+}
+
+// Register sets the handler for a job type. It panics if called after Start
+// or twice for the same type, both of which are programming errors.
+func (r *Runner) Register(jobType string, h Handler) {
+	if r.started {
+		panic("jobs: Register called after Start")
+	}
+	if _, ok := r.handlers[jobType]; ok {
+		panic("jobs: duplicate handler for " + jobType)
+	}
+	r.handlers[jobType] = h
+}
+
+// Enqueue validates and stores a job. With an idempotency key, a repeated
+// request returns the job created by the first one.
+func (r *Runner) Enqueue(ctx context.Context, req EnqueueRequest) (*Job, error) {
+	if err := req.validate(); err != nil {
+		return nil, fmt.Errorf("validation: %w", err)
+	}
+	if _, ok := r.handlers[req.Type]; !ok {
+		return nil, fmt.Errorf("invalid job type %q", req.Type)
+	}
+
+	now := time.Now()
+	job := &Job{
+		Type:           req.Type,
+		UID:            req.UID,
+		Payload:        req.Payload,
+		IdempotencyKey: req.IdempotencyKey,
+		Status:         StatusQueued,
+		MaxAttempts:    req.MaxAttempts,
+		RunAt:          req.RunAt,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if job.MaxAttempts == 0 {
+		job.MaxAttempts = DefaultMaxAttempts
+	}
+	if job.RunAt.IsZero() {
+		job.RunAt = now
+	}
+
+	stored, created, err := r.store.Enqueue(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		slog.Info("job enqueued", "jobId", stored.ID, "type", stored.Type, "uid", stored.UID)
+		select {
+		case r.wake <- struct{}{}:
+		default:
+		}
+	}
+	return stored, nil
+}
+
+// Get returns a job by ID.
+func (r *Runner) Get(ctx context.Context, jobID string) (*Job, error) {
+	return r.store.Get(ctx, jobID)
+}
+
+// Start launches the worker pool. It returns immediately.
+func (r *Runner) Start() {
+	r.started = true
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	for i := 0; i < r.cfg.Workers; i++ {
+		r.wg.Add(1)
+		go r.work(ctx)
+	}
+	slog.Info("job runner started", "workers", r.cfg.Workers, "owner", r.owner)
+}
+
+// Shutdown stops claiming new jobs and waits for running ones to finish. If
+// ctx ends first, running jobs are cancelled; their leases expire and
+// another instance retries them.
+func (r *Runner) Shutdown(ctx context.Context) error {
+	if !r.started {
+		return nil
+	}
+	close(r.stop)
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		r.cancel()
+		return nil
+	case <-ctx.Done():
+		r.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// work is one worker's claim loop.
+func (r *Runner) work(ctx context.Context) {
+	defer r.wg.Done()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		default:
+		}
+
+		now := time.Now()
+		job, err := r.store.Claim(ctx, r.owner, now, now.Add(r.cfg.LeaseDuration))
+		if err != nil {
+			slog.Error("job claim failed", "error", err)
+		}
+		if job != nil {
+			r.run(ctx, job)
+			continue
+		}
+
+		select {
+		case <-r.stop:
+			return
+		case <-r.wake:
+		case <-time.After(r.cfg.PollInterval):
+		}
+	}
+}
+
+// run executes one claimed job and records the outcome.
+func (r *Runner) run(ctx context.Context, job *Job) {
+	log := slog.With("jobId", job.ID, "type", job.Type, "attempt", job.Attempts)
+
+	// Stores update lease state without the job context so an outcome can
+	// be recorded even when the attempt was cancelled.
+	storeCtx := context.WithoutCancel(ctx)
+
+	if job.Attempts > job.MaxAttempts {
+		// The previous worker lost its lease on the final attempt.
+		r.finish(storeCtx, log, job, nil, Permanent(errors.New("attempts exhausted")))
+		return
+	}
+	h, ok := r.handlers[job.Type]
+	if !ok {
+		r.finish(storeCtx, log, job, nil, Permanent(fmt.Errorf("no handler registered for job type %q", job.Type)))
+		return
+	}
+
+	jobCtx, cancel := context.WithTimeout(ctx, r.cfg.JobTimeout)
+	defer cancel()
+
+	heartbeatDone := make(chan struct{})
+	go func() {
+		defer close(heartbeatDone)
+		r.heartbeat(jobCtx, storeCtx, log, job, cancel)
+	}()
+
+	// Each attempt is its own trace, under which the handler's repository
+	// and Storage calls appear
+	traceCtx, span := tracing.Tracer().Start(jobCtx, "job "+job.Type, trace.WithAttributes(
+		attribute.String("paintbar.job.id", job.ID),
+		attribute.Int("paintbar.job.attempt", job.Attempts),
+	))
+	start := time.Now()
+	result, err := r.invoke(traceCtx, h, job)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+	cancel()
+	<-heartbeatDone
+
+	log.Info("job attempt finished", "duration", time.Since(start), "error", err)
+	r.finish(storeCtx, log, job, result, err)
+}
+
+// invoke calls the handler, converting a panic into a permanent error.
+func (r *Runner) invoke(ctx context.Context, h Handler, job *Job) (result map[string]interface{}, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			slog.Error("job handler panic", "jobId", job.ID, "panic", rec, "stack", string(debug.Stack()))
+			err = Permanent(fmt.Errorf("panic: %v", rec))
+		}
+	}()
+	return h(ctx, job)
+}
+
+// heartbeat renews the lease until jobCtx ends, cancelling the job if the
+// lease is lost.
+func (r *Runner) heartbeat(jobCtx, storeCtx context.Context, log *slog.Logger, job *Job, cancel context.CancelFunc) {
+	ticker := time.NewTicker(r.cfg.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-jobCtx.Done():
+			return
+		case <-ticker.C:
+			err := r.store.Heartbeat(storeCtx, job.ID, r.owner, time.Now().Add(r.cfg.LeaseDuration))
+			if errors.Is(err, ErrLeaseLost) {
+				log.Warn("job lease lost; cancelling attempt")
+				cancel()
+				return
+			}
+			if err != nil {
+				log.Warn("job heartbeat failed", "error", err)
+			}
+		}
+	}
+}
+
+// finish records success, a retry, or a permanent failure.
+func (r *Runner) finish(ctx context.Context, log *slog.Logger, job *Job, result map[string]interface{}, err error) {
+	var storeErr error
+	switch {
+	case err == nil:
+		storeErr = r.store.Complete(ctx, job.ID, r.owner, result)
+	case IsPermanent(err) || job.Attempts >= job.MaxAttempts:
+		log.Error("job failed", "error", err)
+		storeErr = r.store.Fail(ctx, job.ID, r.owner, err.Error())
+	default:
+		runAt := time.Now().Add(r.backoff(job.Attempts))
+		log.Warn("job attempt failed; retrying", "error", err, "runAt", runAt)
+		storeErr = r.store.Retry(ctx, job.ID, r.owner, runAt, err.Error())
+	}
+
+	if errors.Is(storeErr, ErrLeaseLost) {
+		log.Warn("job lease lost before its outcome was recorded")
+	} else if storeErr != nil {
+		log.Error("job outcome update failed", "error", storeErr)
+	}
+}
+
+// backoff returns the delay before retrying after the given attempt:
+// BaseBackoff doubled per previous attempt, capped at MaxBackoff.
+func (r *Runner) backoff(attempt int) time.Duration {
+	d := r.cfg.BaseBackoff
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= r.cfg.MaxBackoff {
+			return r.cfg.MaxBackoff
+		}
+	}
+	return d
+}
+
+// newOwnerID identifies this process in job leases.
+func newOwnerID() string {
+	host, _ := os.Hostname()
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return host + "-" + hex.EncodeToString(b)
+}
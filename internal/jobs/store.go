@@ -0,0 +1,188 @@
+package jobs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Store persists jobs and arbitrates leases between workers. Firestore is
+// the default store (see NewFirestoreStore); MemoryStore is for tests and
+// single-process development.
+//
+// Methods that take an owner fail with ErrLeaseLost when the job is no
+// longer leased to that owner.
+type Store interface {
+	// Enqueue stores a new job, setting its ID. If the job has an
+	// idempotency key and a job with the same type, UID and key already
+	// exists, the existing job is returned and created is false.
+	Enqueue(ctx context.Context, job *Job) (stored *Job, created bool, err error)
+	// Get returns a job by ID.
+	Get(ctx context.Context, jobID string) (*Job, error)
+	// Claim leases the next due job to owner until leaseUntil and increments
+	// its attempt count. It returns nil, nil when no job is due.
+	Claim(ctx context.Context, owner string, now, leaseUntil time.Time) (*Job, error)
+	// Heartbeat extends the owner's lease.
+	Heartbeat(ctx context.Context, jobID, owner string, leaseUntil time.Time) error
+	// Complete marks the job succeeded and stores its result.
+	Complete(ctx context.Context, jobID, owner string, result map[string]interface{}) error
+	// Retry releases the lease and queues the job to run again at runAt.
+	Retry(ctx context.Context, jobID, owner string, runAt time.Time, lastError string) error
+	// Fail marks the job permanently failed.
+	Fail(ctx context.Context, jobID, owner string, lastError string) error
+}
+
+// idempotencyID derives a stable job ID from an idempotency key so stores can
+// detect duplicates with a single document lookup.
+func idempotencyID(jobType, uid, key string) string {
+	sum := sha256.Sum256([]byte(jobType + "\x00" + uid + "\x00" + key))
+	return "idem-" + hex.EncodeToString(sum[:16])
+}
+
+// MemoryStore is an in-process Store. Jobs are lost when the process exits.
+type MemoryStore struct {
+	mu     sync.Mutex
+	jobs   map[string]*Job
+	nextID int
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{jobs: make(map[string]*Job)}
+}
+
+// Enqueue implements Store.
+func (s *MemoryStore) Enqueue(_ context.Context, job *Job) (*Job, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := ""
+	if job.IdempotencyKey != "" {
+		id = idempotencyID(job.Type, job.UID, job.IdempotencyKey)
+		if existing, ok := s.jobs[id]; ok {
+			copy := *existing
+			return &copy, false, nil
+		}
+	} else {
+		s.nextID++
+		id = fmt.Sprintf("job-%06d", s.nextID)
+	}
+
+	job.ID = id
+	copy := *job
+	s.jobs[id] = &copy
+	return job, true, nil
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(_ context.Context, jobID string) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[jobID]
+	if !ok {
+		return nil, fmt.Errorf("job %s not found", jobID)
+	}
+	copy := *job
+	return &copy, nil
+}
+
+// Claim implements Store. The job that has been due the longest wins.
+func (s *MemoryStore) Claim(_ context.Context, owner string, now, leaseUntil time.Time) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []*Job
+	for _, job := range s.jobs {
+		if job.claimable(now) {
+			due = append(due, job)
+		}
+	}
+	if len(due) == 0 {
+		return nil, nil
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return dueAt(due[i]).Before(dueAt(due[j]))
+	})
+
+	job := due[0]
+	job.Status = StatusRunning
+	job.Attempts++
+	job.LeaseOwner = owner
+	job.LeaseExpiresAt = &leaseUntil
+	job.UpdatedAt = now
+	copy := *job
+	return &copy, nil
+}
+
+// dueAt is when a claimable job became claimable.
+func dueAt(job *Job) time.Time {
+	if job.Status == StatusRunning && job.LeaseExpiresAt != nil {
+		return *job.LeaseExpiresAt
+	}
+	return job.RunAt
+}
+
+// Heartbeat implements Store.
+func (s *MemoryStore) Heartbeat(_ context.Context, jobID, owner string, leaseUntil time.Time) error {
+	return s.update(jobID, owner, func(job *Job) {
+		job.LeaseExpiresAt = &leaseUntil
+	})
+}
+
+// Complete implements Store.
+func (s *MemoryStore) Complete(_ context.Context, jobID, owner string, result map[string]interface{}) error {
+	return s.update(jobID, owner, func(job *Job) {
+		now := time.Now()
+		job.Status = StatusSucceeded
+		job.Result = result
+		job.LastError = ""
+		job.CompletedAt = &now
+		job.LeaseOwner = ""
+		job.LeaseExpiresAt = nil
+	})
+}
+
+// Retry implements Store.
+func (s *MemoryStore) Retry(_ context.Context, jobID, owner string, runAt time.Time, lastError string) error {
+	return s.update(jobID, owner, func(job *Job) {
+		job.Status = StatusQueued
+		job.RunAt = runAt
+		job.LastError = lastError
+		job.LeaseOwner = ""
+		job.LeaseExpiresAt = nil
+	})
+}
+
+// Fail implements Store.
+func (s *MemoryStore) Fail(_ context.Context, jobID, owner string, lastError string) error {
+	return s.update(jobID, owner, func(job *Job) {
+		now := time.Now()
+		job.Status = StatusFailed
+		job.LastError = lastError
+		job.CompletedAt = &now
+		job.LeaseOwner = ""
+		job.LeaseExpiresAt = nil
+	})
+}
+
+// update applies fn to a running job leased to owner.
+func (s *MemoryStore) update(jobID, owner string, fn func(*Job)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[jobID]
+	if !ok {
+		return fmt.Errorf("job %s not found", jobID)
+	}
+	if job.Status != StatusRunning || job.LeaseOwner != owner {
+		return ErrLeaseLost
+	}
+	fn(job)
+	job.UpdatedAt = time.Now()
+	return nil
+}
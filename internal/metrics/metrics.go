@@ -0,0 +1,144 @@
+// Package metrics collects the server's Prometheus metrics: request rate,
+// errors and duration per route, latency and errors per repository method,
+// rate-limit rejections and upload volume. Each Metrics has its own
+// registry, so servers built side by side in tests do not collide. A nil
+// *Metrics records nothing.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "paintbar"
+
+// UnmatchedRoute labels requests that matched no route, so probes of random
+// paths do not each get their own series.
+const UnmatchedRoute = "unmatched"
+
+// Metrics holds the collectors and the registry that serves them.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	callDuration    *prometheus.HistogramVec
+	callErrors      *prometheus.CounterVec
+	rateLimited     *prometheus.CounterVec
+	uploadBytes     *prometheus.CounterVec
+}
+
+// New creates the collectors and registers them, along with the Go runtime
+// and process collectors, on a new registry.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route pattern and status code.",
+		}, []string{"method", "route", "code"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method and route pattern.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		callDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "repository_call_duration_seconds",
+			Help:      "Firestore and Storage call latency by repository and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"repository", "method"}),
+		callErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "repository_call_errors_total",
+			Help:      "Failed Firestore and Storage calls by repository and method.",
+		}, []string{"repository", "method"}),
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rate_limit_rejections_total",
+			Help:      "Requests rejected with 429 by rate limiter.",
+		}, []string{"limiter"}),
+		uploadBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "upload_bytes_total",
+			Help:      "Request body bytes received by upload endpoint.",
+		}, []string{"upload"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.callDuration,
+		m.callErrors,
+		m.rateLimited,
+		m.uploadBytes,
+	)
+	return m
+}
+
+// Handler serves the registry in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// ObserveRequest records a served request under its route pattern.
+func (m *Metrics) ObserveRequest(method, route string, status int, d time.Duration) {
+	if m == nil {
+		return
+	}
+	if route == "" {
+		route = UnmatchedRoute
+	}
+	m.requests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.requestDuration.WithLabelValues(method, route).Observe(d.Seconds())
+}
+
+// ObserveCall records a repository call's latency, and an error if failed.
+func (m *Metrics) ObserveCall(repository, method string, d time.Duration, failed bool) {
+	if m == nil {
+		return
+	}
+	m.callDuration.WithLabelValues(repository, method).Observe(d.Seconds())
+	if failed {
+		m.callErrors.WithLabelValues(repository, method).Inc()
+	}
+}
+
+// RateLimited records a request rejected by the named limiter.
+func (m *Metrics) RateLimited(limiter string) {
+	if m == nil {
+		return
+	}
+	m.rateLimited.WithLabelValues(limiter).Inc()
+}
+
+// TrackVisitors exports the number of clients the named limiter is
+// tracking, read from size at scrape time.
+func (m *Metrics) TrackVisitors(limiter string, size func() int) {
+	if m == nil {
+		return
+	}
+	m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   namespace,
+		Name:        "rate_limit_visitors",
+		Help:        "Clients currently tracked by rate limiter.",
+		ConstLabels: prometheus.Labels{"limiter": limiter},
+	}, func() float64 { return float64(size()) }))
+}
+
+// AddUploadBytes records n bytes received by the named upload endpoint.
+func (m *Metrics) AddUploadBytes(upload string, n int64) {
+	if m == nil {
+		return
+	}
+	m.uploadBytes.WithLabelValues(upload).Add(float64(n))
+}
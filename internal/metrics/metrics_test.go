@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scrape returns m's exposition in the Prometheus text format.
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	return rec.Body.String()
+}
+
+func TestMetrics_Exposition(t *testing.T) {
+	m := New()
+	m.ObserveRequest("GET", "/api/v1/projects/{id}", 200, 20*time.Millisecond)
+	m.ObserveRequest("GET", "/api/v1/projects/{id}", 404, time.Millisecond)
+	m.ObserveRequest("GET", "", 404, time.Millisecond)
+	m.ObserveCall("projects", "GetByID", 5*time.Millisecond, false)
+	m.ObserveCall("projects", "GetByID", 5*time.Millisecond, true)
+	m.RateLimited("global")
+	m.AddUploadBytes("blob", 1024)
+	visitors := 3
+	m.TrackVisitors("global", func() int { return visitors })
+	visitors = 7
+
+	out := scrape(t, m)
+	assert.Contains(t, out, `paintbar_http_requests_total{code="200",method="GET",route="/api/v1/projects/{id}"} 1`)
+	assert.Contains(t, out, `paintbar_http_requests_total{code="404",method="GET",route="/api/v1/projects/{id}"} 1`)
+	assert.Contains(t, out, `paintbar_http_requests_total{code="404",method="GET",route="unmatched"} 1`)
+	assert.Contains(t, out, `paintbar_http_request_duration_seconds_count{method="GET",route="/api/v1/projects/{id}"} 2`)
+	assert.Contains(t, out, `paintbar_repository_call_duration_seconds_count{method="GetByID",repository="projects"} 2`)
+	assert.Contains(t, out, `paintbar_repository_call_errors_total{method="GetByID",repository="projects"} 1`)
+	assert.Contains(t, out, `paintbar_rate_limit_rejections_total{limiter="global"} 1`)
+	assert.Contains(t, out, `paintbar_rate_limit_visitors{limiter="global"} 7`)
+	assert.Contains(t, out, `paintbar_upload_bytes_total{upload="blob"} 1024`)
+	assert.Contains(t, out, "go_goroutines")
+}
+
+func TestMetrics_Separate(t *testing.T) {
+	// Each Metrics has its own registry, so building two servers in one
+	// process does not panic on duplicate registration
+	a, b := New(), New()
+	a.TrackVisitors("global", func() int { return 1 })
+	b.TrackVisitors("global", func() int { return 2 })
+	a.RateLimited("global")
+
+	assert.Contains(t, scrape(t, a), `paintbar_rate_limit_rejections_total{limiter="global"} 1`)
+	assert.NotContains(t, scrape(t, b), "paintbar_rate_limit_rejections_total{")
+}
+
+func TestMetrics_NilRecordsNothing(t *testing.T) {
+	var m *Metrics
+	assert.NotPanics(t, func() {
+		m.ObserveRequest("GET", "/", 200, time.Millisecond)
+		m.ObserveCall("users", "GetByID", time.Millisecond, true)
+		m.RateLimited("global")
+		m.TrackVisitors("global", func() int { return 0 })
+		m.AddUploadBytes("chunk", 1)
+	})
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+)
+
+// RequireRole returns middleware that only admits users whose ID token
+// grants the given role (see service.UserInfo.Roles). It must run after Auth.
+// Roles are granted out of band with the Firebase Admin SDK
+// (SetCustomUserClaims with a "roles" list).
+func RequireRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := UserFromContext(r.Context())
+			if user == nil {
+				unauthorizedJSON(w, "authentication required")
+				return
+			}
+			if !user.HasRole(role) {
+				slog.Warn("role check failed",
+					"uid", user.UID,
+					"role", role,
+					"path", r.URL.Path,
+				)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusForbidden)
+				json.NewEncoder(w).Encode(map[string]string{
+					"error": role + " role required",
+				})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/pandasWhoCode/paintbar/internal/service"
+)
+
+// AuditContext returns middleware that attaches the client IP and request ID
+// to the request context so the service layer can stamp them onto audit
+// entries. It must run after chimiddleware.RequestID and RealIP.
+func AuditContext() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := r.RemoteAddr
+			if host, _, err := net.SplitHostPort(ip); err == nil {
+				ip = host
+			}
+			ctx := service.WithRequestMeta(r.Context(), service.RequestMeta{
+				IP:        ip,
+				RequestID: chimiddleware.GetReqID(r.Context()),
+			})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -24,12 +24,14 @@ const (
 )
 
 // Auth returns middleware that verifies Firebase ID tokens from the
-// Authorization header. Requests to paths in the skip list are passed through
-// without authentication.
+// Authorization header. Requests to paths in the skip list, and requests
+// already authenticated by OptionalSession, are passed through.
 func Auth(authService TokenVerifier) func(http.Handler) http.Handler {
 	// Paths that skip authentication entirely
 	skipPaths := map[string]bool{
 		"/":            true,
+		"/livez":       true,
+		"/readyz":      true,
 		"/health":      true,
 		"/favicon.ico": true,
 	}
@@ -55,6 +57,12 @@ func Auth(authService TokenVerifier) func(http.Handler) http.Handler {
 				}
 			}
 
+			// Already authenticated by the session cookie
+			if IsSessionAuth(r.Context()) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
 			// Extract Bearer token
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
@@ -7,8 +7,10 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/pandasWhoCode/paintbar/internal/service"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // mockTokenVerifier implements TokenVerifier for testing.
@@ -236,3 +238,458 @@ func TestAuth_SingleWordAuth(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, rr.Code)
 	assert.Contains(t, rr.Body.String(), "invalid authorization header format")
 }
+
+// --- RequireRole tests ---
+
+func withUserInfo(req *http.Request, user *service.UserInfo) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), UserContextKey, user))
+}
+
+func TestRequireRole_AllowsRole(t *testing.T) {
+	req := withUserInfo(httptest.NewRequest(http.MethodGet, "/api/admin/reports", nil),
+		&service.UserInfo{UID: "admin", Roles: []string{"support", "admin"}})
+	rr := httptest.NewRecorder()
+	RequireRole("admin")(okHandler()).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestRequireRole_RejectsMissingRole(t *testing.T) {
+	req := withUserInfo(httptest.NewRequest(http.MethodGet, "/api/admin/reports", nil),
+		&service.UserInfo{UID: "user", Roles: []string{"support"}})
+	rr := httptest.NewRecorder()
+	RequireRole("admin")(okHandler()).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), "admin role required")
+}
+
+func TestRequireRole_RejectsAnonymous(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/reports", nil)
+	rr := httptest.NewRecorder()
+	RequireRole("admin")(okHandler()).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+// --- RejectSuspended tests ---
+
+type mockSuspensionChecker struct {
+	suspended map[string]bool
+	err       error
+}
+
+func (m *mockSuspensionChecker) IsSuspended(_ context.Context, uid string) (bool, error) {
+	return m.suspended[uid], m.err
+}
+
+func TestRejectSuspended_BlocksWrites(t *testing.T) {
+	checker := &mockSuspensionChecker{suspended: map[string]bool{"troll": true}}
+	req := withUserInfo(httptest.NewRequest(http.MethodPost, "/api/gallery", nil),
+		&service.UserInfo{UID: "troll"})
+	rr := httptest.NewRecorder()
+	RejectSuspended(checker)(okHandler()).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), "account suspended")
+}
+
+func TestRejectSuspended_AllowsReads(t *testing.T) {
+	checker := &mockSuspensionChecker{suspended: map[string]bool{"troll": true}}
+	req := withUserInfo(httptest.NewRequest(http.MethodGet, "/api/profile", nil),
+		&service.UserInfo{UID: "troll"})
+	rr := httptest.NewRecorder()
+	RejectSuspended(checker)(okHandler()).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestRejectSuspended_AllowsActiveUsers(t *testing.T) {
+	checker := &mockSuspensionChecker{suspended: map[string]bool{}}
+	req := withUserInfo(httptest.NewRequest(http.MethodPost, "/api/gallery", nil),
+		&service.UserInfo{UID: "fan"})
+	rr := httptest.NewRecorder()
+	RejectSuspended(checker)(okHandler()).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestRejectSuspended_CheckerError(t *testing.T) {
+	checker := &mockSuspensionChecker{err: fmt.Errorf("firestore unavailable")}
+	req := withUserInfo(httptest.NewRequest(http.MethodDelete, "/api/projects/p1", nil),
+		&service.UserInfo{UID: "fan"})
+	rr := httptest.NewRecorder()
+	RejectSuspended(checker)(okHandler()).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "firestore")
+}
+
+// --- AuditContext tests ---
+
+func TestAuditContext_AttachesRequestMeta(t *testing.T) {
+	var got service.RequestMeta
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = service.RequestMetaFromContext(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/profile", nil)
+	req.RemoteAddr = "203.0.113.7:51234"
+	chimiddleware.RequestID(AuditContext()(next)).ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "203.0.113.7", got.IP)
+	assert.NotEmpty(t, got.RequestID)
+}
+
+// --- Session cookie tests ---
+
+type mockSessionVerifier struct {
+	cookies map[string]string // cookie value -> uid
+}
+
+func (m *mockSessionVerifier) VerifySessionCookie(_ context.Context, cookie string) (*service.UserInfo, error) {
+	uid, ok := m.cookies[cookie]
+	if !ok {
+		return nil, fmt.Errorf("invalid session cookie")
+	}
+	return &service.UserInfo{UID: uid}, nil
+}
+
+func TestOptionalSession_ValidCookie(t *testing.T) {
+	verifier := &mockSessionVerifier{cookies: map[string]string{"good": "user1"}}
+
+	var gotUser *service.UserInfo
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser = UserFromContext(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/profile", nil)
+	req.AddCookie(&http.Cookie{Name: SessionCookieName, Value: "good"})
+	rr := httptest.NewRecorder()
+	OptionalSession(verifier, true)(next).ServeHTTP(rr, req)
+
+	require.NotNil(t, gotUser)
+	assert.Equal(t, "user1", gotUser.UID)
+	assert.Empty(t, rr.Result().Cookies())
+}
+
+func TestOptionalSession_NoCookie(t *testing.T) {
+	verifier := &mockSessionVerifier{cookies: map[string]string{}}
+
+	var gotUser *service.UserInfo
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser = UserFromContext(r.Context())
+	})
+
+	rr := httptest.NewRecorder()
+	OptionalSession(verifier, true)(next).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Nil(t, gotUser)
+}
+
+func TestOptionalSession_InvalidCookieIsCleared(t *testing.T) {
+	verifier := &mockSessionVerifier{cookies: map[string]string{}}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: SessionCookieName, Value: "stale"})
+	rr := httptest.NewRecorder()
+	OptionalSession(verifier, true)(okHandler()).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	cookies := rr.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, SessionCookieName, cookies[0].Name)
+	assert.Equal(t, -1, cookies[0].MaxAge)
+}
+
+func TestRequireSession_RedirectsAnonymous(t *testing.T) {
+	rr := httptest.NewRecorder()
+	RequireSession("/login")(okHandler()).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/profile", nil))
+
+	assert.Equal(t, http.StatusSeeOther, rr.Code)
+	assert.Equal(t, "/login", rr.Header().Get("Location"))
+}
+
+func TestRequireSession_AllowsUser(t *testing.T) {
+	req := withUserInfo(httptest.NewRequest(http.MethodGet, "/profile", nil), &service.UserInfo{UID: "user1"})
+	rr := httptest.NewRecorder()
+	RequireSession("/login")(okHandler()).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestOptionalSession_BearerTakesPrecedence(t *testing.T) {
+	verifier := &mockSessionVerifier{cookies: map[string]string{"good": "user1"}}
+
+	var sessionAuth bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionAuth = IsSessionAuth(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/projects", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	req.AddCookie(&http.Cookie{Name: SessionCookieName, Value: "good"})
+	OptionalSession(verifier, true)(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.False(t, sessionAuth)
+}
+
+func TestAuth_SkipsSessionAuthenticatedRequests(t *testing.T) {
+	verifier := &mockSessionVerifier{cookies: map[string]string{"good": "user1"}}
+	handler := OptionalSession(verifier, true)(Auth(&mockTokenVerifier{err: fmt.Errorf("unused")})(okHandler()))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/profile", nil)
+	req.AddCookie(&http.Cookie{Name: SessionCookieName, Value: "good"})
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+// --- CSRF tests ---
+
+// testCSRFKey keys the CSRF tokens in these tests.
+var testCSRFKey = NewCSRFKey([]byte("0123456789abcdef0123456789abcdef"))
+
+// csrfRequest builds a cookie-authenticated request through OptionalSession
+// and CSRF. The request carries only the session cookie and the token
+// header, as requests forwarded by Firebase Hosting do.
+func csrfRequest(t *testing.T, method string, mutate func(*http.Request)) *httptest.ResponseRecorder {
+	t.Helper()
+	verifier := &mockSessionVerifier{cookies: map[string]string{"good": "user1"}}
+	handler := OptionalSession(verifier, true)(CSRF(DefaultCORSConfig("production"), testCSRFKey)(okHandler()))
+
+	req := httptest.NewRequest(method, "https://paintbar.app/api/projects", nil)
+	req.AddCookie(&http.Cookie{Name: SessionCookieName, Value: "good"})
+	req.Header.Set("Origin", "https://paintbar.app")
+	req.Header.Set(CSRFHeaderName, csrfToken(testCSRFKey, "good"))
+	if mutate != nil {
+		mutate(req)
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestCSRF_AllowsValidRequest(t *testing.T) {
+	rr := csrfRequest(t, http.MethodPost, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestCSRF_AllowsSafeMethods(t *testing.T) {
+	rr := csrfRequest(t, http.MethodGet, func(r *http.Request) {
+		r.Header.Del(CSRFHeaderName)
+		r.Header.Set("Origin", "https://evil.example")
+	})
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestCSRF_RejectsMissingToken(t *testing.T) {
+	rr := csrfRequest(t, http.MethodPost, func(r *http.Request) {
+		r.Header.Del(CSRFHeaderName)
+	})
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), "invalid CSRF token")
+}
+
+func TestCSRF_RejectsMismatchedToken(t *testing.T) {
+	rr := csrfRequest(t, http.MethodDelete, func(r *http.Request) {
+		r.Header.Set(CSRFHeaderName, "forged")
+	})
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestCSRF_RejectsTokenForAnotherSession(t *testing.T) {
+	rr := csrfRequest(t, http.MethodPost, func(r *http.Request) {
+		r.Header.Set(CSRFHeaderName, csrfToken(testCSRFKey, "other-session"))
+	})
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestCSRF_RejectsTokenUnderAnotherKey(t *testing.T) {
+	rr := csrfRequest(t, http.MethodPost, func(r *http.Request) {
+		r.Header.Set(CSRFHeaderName, csrfToken(NewCSRFKey(nil), "good"))
+	})
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestCSRF_RejectsForeignOrigin(t *testing.T) {
+	rr := csrfRequest(t, http.MethodPut, func(r *http.Request) {
+		r.Header.Set("Origin", "https://evil.example")
+	})
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), "cross-origin request rejected")
+}
+
+func TestCSRF_FallsBackToReferer(t *testing.T) {
+	rr := csrfRequest(t, http.MethodPost, func(r *http.Request) {
+		r.Header.Del("Origin")
+		r.Header.Set("Referer", "https://paintbar.art/profile")
+	})
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestCSRF_RejectsMissingOriginAndReferer(t *testing.T) {
+	rr := csrfRequest(t, http.MethodPost, func(r *http.Request) {
+		r.Header.Del("Origin")
+	})
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestCSRF_AllowsSameHost(t *testing.T) {
+	rr := csrfRequest(t, http.MethodPost, func(r *http.Request) {
+		r.Host = "paintbar-123.us-central1.run.app"
+		r.Header.Set("Origin", "https://paintbar-123.us-central1.run.app")
+	})
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestCSRF_IgnoresBearerRequests(t *testing.T) {
+	handler := CSRF(DefaultCORSConfig("production"), testCSRFKey)(okHandler())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/projects", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("Origin", "https://evil.example")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestRequireSameOrigin(t *testing.T) {
+	handler := RequireSameOrigin(DefaultCORSConfig("production"))(okHandler())
+	tests := []struct {
+		name            string
+		origin, referer string
+		want            int
+	}{
+		{"allowed origin", "https://paintbar.app", "", http.StatusOK},
+		{"same host", "https://paintbar-123.us-central1.run.app", "", http.StatusOK},
+		{"referer fallback", "", "https://paintbar.art/login", http.StatusOK},
+		{"foreign origin", "https://evil.example", "", http.StatusForbidden},
+		{"foreign referer", "", "https://evil.example/login", http.StatusForbidden},
+		{"neither", "", "", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "https://paintbar-123.us-central1.run.app/auth/session", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.referer != "" {
+				req.Header.Set("Referer", tt.referer)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, tt.want, rr.Code)
+		})
+	}
+}
+
+func TestCSRFToken_DerivesFromSession(t *testing.T) {
+	var token string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = CSRFTokenFromContext(r.Context())
+	})
+	verifier := &mockSessionVerifier{cookies: map[string]string{"good": "user1"}}
+
+	req := httptest.NewRequest(http.MethodGet, "/profile", nil)
+	req.AddCookie(&http.Cookie{Name: SessionCookieName, Value: "good"})
+	rr := httptest.NewRecorder()
+	OptionalSession(verifier, true)(CSRFToken(testCSRFKey)(next)).ServeHTTP(rr, req)
+
+	assert.Equal(t, csrfToken(testCSRFKey, "good"), token)
+	assert.Len(t, token, 64)
+	assert.Empty(t, rr.Result().Cookies(), "no cookie besides __session is set")
+}
+
+func TestCSRFToken_NoSession(t *testing.T) {
+	token := "unset"
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = CSRFTokenFromContext(r.Context())
+	})
+
+	rr := httptest.NewRecorder()
+	CSRFToken(testCSRFKey)(next).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/login", nil))
+
+	assert.Empty(t, token)
+	assert.Empty(t, rr.Result().Cookies())
+}
+
+func TestNewCSRFKey(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	assert.Equal(t, NewCSRFKey(secret), NewCSRFKey(secret), "stable across instances")
+	assert.NotEqual(t, secret, NewCSRFKey(secret))
+	assert.NotEqual(t, NewCSRFKey(nil), NewCSRFKey(nil), "random without a secret")
+}
+
+// --- Token verifier chain and scope tests ---
+
+func TestChainVerifiers_FirstSuccessWins(t *testing.T) {
+	chain := ChainVerifiers(
+		&mockTokenVerifier{err: fmt.Errorf("not an API token")},
+		&mockTokenVerifier{user: &service.UserInfo{UID: "firebase-user"}},
+	)
+
+	user, err := chain.VerifyIDToken(context.Background(), "token")
+	require.NoError(t, err)
+	assert.Equal(t, "firebase-user", user.UID)
+}
+
+func TestChainVerifiers_AllFail(t *testing.T) {
+	chain := ChainVerifiers(
+		&mockTokenVerifier{err: fmt.Errorf("not an API token")},
+		&mockTokenVerifier{err: fmt.Errorf("verify id token: malformed")},
+	)
+
+	_, err := chain.VerifyIDToken(context.Background(), "token")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not an API token")
+	assert.Contains(t, err.Error(), "malformed")
+
+	_, err = ChainVerifiers().VerifyIDToken(context.Background(), "token")
+	assert.Error(t, err)
+}
+
+func TestRequireScope(t *testing.T) {
+	tokenUser := &service.UserInfo{UID: "u1", TokenID: "t1", Scopes: []string{"projects:read"}}
+	firebaseUser := &service.UserInfo{UID: "u1"}
+	mw := RequireScope("projects:read", "projects:write")
+
+	tests := []struct {
+		name   string
+		method string
+		user   *service.UserInfo
+		want   int
+	}{
+		{"token read allowed", http.MethodGet, tokenUser, http.StatusOK},
+		{"token write denied", http.MethodPost, tokenUser, http.StatusForbidden},
+		{"firebase write allowed", http.MethodDelete, firebaseUser, http.StatusOK},
+		{"anonymous", http.MethodGet, nil, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/projects", nil)
+			if tt.user != nil {
+				req = withUserInfo(req, tt.user)
+			}
+			rr := httptest.NewRecorder()
+			mw(okHandler()).ServeHTTP(rr, req)
+			assert.Equal(t, tt.want, rr.Code)
+		})
+	}
+}
+
+func TestDenyAPITokens(t *testing.T) {
+	rr := httptest.NewRecorder()
+	req := withUserInfo(httptest.NewRequest(http.MethodGet, "/api/profile", nil),
+		&service.UserInfo{UID: "u1", TokenID: "t1", Scopes: []string{"projects:read"}})
+	DenyAPITokens()(okHandler()).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), "API tokens cannot access this endpoint")
+
+	rr = httptest.NewRecorder()
+	req = withUserInfo(httptest.NewRequest(http.MethodGet, "/api/profile", nil), &service.UserInfo{UID: "u1"})
+	DenyAPITokens()(okHandler()).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
@@ -0,0 +1,312 @@
+package middleware
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+)
+
+// DefaultCompressMinSize is the smallest response body worth compressing;
+// below it the encoding overhead outweighs the savings.
+const DefaultCompressMinSize = 1024
+
+// Supported content codings, in server preference order for equal q-values.
+const (
+	encodingZstd = "zstd"
+	encodingGzip = "gzip"
+)
+
+var supportedEncodings = []string{encodingZstd, encodingGzip}
+
+// compressibleTypes are the media types worth compressing. Images, archives
+// and other already-compressed formats are passed through untouched.
+var compressibleTypes = map[string]bool{
+	"application/json":         true,
+	"application/problem+json": true,
+	"application/javascript":   true,
+	"application/xml":          true,
+	"application/yaml":         true,
+	"application/x-ndjson":     true,
+	"image/svg+xml":            true,
+}
+
+var (
+	gzipPool = sync.Pool{New: func() interface{} {
+		w, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression)
+		return w
+	}}
+	zstdPool = sync.Pool{New: func() interface{} {
+		// Browsers reject zstd frames with windows above 8 MB
+		w, _ := zstd.NewWriter(nil,
+			zstd.WithEncoderLevel(zstd.SpeedDefault),
+			zstd.WithEncoderConcurrency(1),
+			zstd.WithWindowSize(8<<20),
+		)
+		return w
+	}}
+)
+
+// Compress returns middleware that compresses responses with zstd or gzip,
+// negotiated from Accept-Encoding. Only compressible media types at least
+// minSize bytes long are encoded; partial content, 304s, responses that
+// already carry a Content-Encoding and those marked no-transform pass
+// through. Install it inside RequestLogger so logged byte counts are the
+// bytes sent on the wire.
+func Compress(minSize int) func(http.Handler) http.Handler {
+	if minSize <= 0 {
+		minSize = DefaultCompressMinSize
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodHead || r.Header.Get("Upgrade") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{
+				ResponseWriter: w,
+				encoding:       negotiateEncoding(r.Header.Get("Accept-Encoding")),
+				minSize:        minSize,
+			}
+			defer cw.close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding picks the supported coding with the highest q-value in
+// an Accept-Encoding header, or "" for identity.
+func negotiateEncoding(header string) string {
+	if header == "" {
+		return ""
+	}
+
+	q := make(map[string]float64)
+	wildcard := -1.0
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		weight := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				weight = f
+			}
+		}
+		if name == "*" {
+			wildcard = weight
+		} else {
+			q[name] = weight
+		}
+	}
+
+	best, bestQ := "", 0.0
+	for _, enc := range supportedEncodings {
+		weight, ok := q[enc]
+		if !ok {
+			weight = max(wildcard, 0)
+		}
+		if weight > bestQ {
+			best, bestQ = enc, weight
+		}
+	}
+	return best
+}
+
+// compressWriter buffers the start of an eligible response until it reaches
+// minSize, then switches to streaming through an encoder. Smaller responses
+// are written uncompressed when the handler returns.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+
+	status      int
+	wroteHeader bool // handler has called WriteHeader (or Write)
+	passthrough bool // response goes out unmodified
+	buf         []byte
+	enc         io.WriteCloser
+}
+
+// WriteHeader records the status and decides whether the response can be
+// compressed. Eligible responses defer the real WriteHeader until enough of
+// the body is known.
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.wroteHeader {
+		return
+	}
+	if status < 200 {
+		cw.ResponseWriter.WriteHeader(status) // informational; not final
+		return
+	}
+	cw.wroteHeader = true
+	cw.status = status
+
+	h := cw.Header()
+	if !compressible(status, h) {
+		cw.passthrough = true
+		cw.ResponseWriter.WriteHeader(status)
+		return
+	}
+
+	// The representation depends on Accept-Encoding whether or not this
+	// particular response ends up compressed.
+	addVary(h, "Accept-Encoding")
+
+	if cw.encoding == "" {
+		cw.passthrough = true
+		cw.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if n, err := strconv.Atoi(h.Get("Content-Length")); err == nil && n < cw.minSize {
+		cw.passthrough = true
+		cw.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if !cw.wroteHeader {
+		if cw.Header().Get("Content-Type") == "" {
+			cw.Header().Set("Content-Type", http.DetectContentType(p))
+		}
+		cw.WriteHeader(http.StatusOK)
+	}
+
+	switch {
+	case cw.passthrough:
+		return cw.ResponseWriter.Write(p)
+	case cw.enc != nil:
+		return cw.enc.Write(p)
+	}
+
+	cw.buf = append(cw.buf, p...)
+	if len(cw.buf) >= cw.minSize {
+		if err := cw.startEncoding(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// startEncoding sends the headers for a compressed response and flushes the
+// buffered prefix through the encoder.
+func (cw *compressWriter) startEncoding() error {
+	h := cw.Header()
+	h.Set("Content-Encoding", cw.encoding)
+	h.Del("Content-Length")
+	// The encoded bytes differ from the identity representation, so a
+	// strong validator must not be reused for them.
+	if etag := h.Get("ETag"); strings.HasPrefix(etag, `"`) {
+		h.Set("ETag", "W/"+etag)
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	switch cw.encoding {
+	case encodingZstd:
+		z := zstdPool.Get().(*zstd.Encoder)
+		z.Reset(cw.ResponseWriter)
+		cw.enc = z
+	default:
+		g := gzipPool.Get().(*gzip.Writer)
+		g.Reset(cw.ResponseWriter)
+		cw.enc = g
+	}
+
+	buf := cw.buf
+	cw.buf = nil
+	_, err := cw.enc.Write(buf)
+	return err
+}
+
+// close finishes the response: it ends the encoder, or writes a buffered
+// body that never reached minSize as-is.
+func (cw *compressWriter) close() {
+	switch {
+	case cw.enc != nil:
+		cw.enc.Close()
+		switch enc := cw.enc.(type) {
+		case *zstd.Encoder:
+			enc.Reset(nil)
+			zstdPool.Put(enc)
+		case *gzip.Writer:
+			enc.Reset(nil)
+			gzipPool.Put(enc)
+		}
+		cw.enc = nil
+	case cw.wroteHeader && !cw.passthrough:
+		cw.ResponseWriter.WriteHeader(cw.status)
+		if len(cw.buf) > 0 {
+			cw.ResponseWriter.Write(cw.buf)
+		}
+	}
+}
+
+// Flush sends buffered data now. A streaming handler is assumed to want
+// compression, so a pending buffer starts the encoder regardless of size.
+func (cw *compressWriter) Flush() {
+	if cw.wroteHeader && !cw.passthrough && cw.enc == nil {
+		if err := cw.startEncoding(); err != nil {
+			return
+		}
+	}
+	if f, ok := cw.enc.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+	http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Hijack lets connection upgrades bypass compression.
+func (cw *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(cw.ResponseWriter).Hijack()
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// compressible reports whether a response with this status and these
+// headers may be compressed.
+func compressible(status int, h http.Header) bool {
+	switch status {
+	case http.StatusNoContent, http.StatusNotModified, http.StatusPartialContent:
+		return false
+	}
+	if h.Get("Content-Encoding") != "" || h.Get("Content-Range") != "" {
+		return false
+	}
+	if strings.Contains(strings.ToLower(h.Get("Cache-Control")), "no-transform") {
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(mediaType, "text/") || compressibleTypes[mediaType]
+}
+
+// addVary adds tokens to the Vary header, keeping any already present and
+// skipping duplicates, so middleware that each vary on something compose.
+func addVary(h http.Header, tokens ...string) {
+	existing := make(map[string]bool)
+	for _, line := range h.Values("Vary") {
+		for _, t := range strings.Split(line, ",") {
+			existing[strings.ToLower(strings.TrimSpace(t))] = true
+		}
+	}
+	for _, t := range tokens {
+		if existing["*"] || existing[strings.ToLower(t)] {
+			continue
+		}
+		h.Add("Vary", t)
+		existing[strings.ToLower(t)] = true
+	}
+}
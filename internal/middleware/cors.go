@@ -10,7 +10,8 @@ type CORSConfig struct {
 	AllowedOrigins []string
 	AllowedMethods []string
 	AllowedHeaders []string
-	MaxAge         string // preflight cache duration in seconds
+	ExposedHeaders []string // response headers scripts may read
+	MaxAge         string   // preflight cache duration in seconds
 }
 
 // DefaultCORSConfig returns a sensible default CORS config for the PaintBar API.
@@ -23,7 +24,8 @@ func DefaultCORSConfig(env string) CORSConfig {
 	return CORSConfig{
 		AllowedOrigins: origins,
 		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-Request-ID"},
+		AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-Request-ID", CSRFHeaderName},
+		ExposedHeaders: []string{"Link", "Deprecation", "Sunset"},
 		MaxAge:         "86400",
 	}
 }
@@ -37,17 +39,24 @@ func CORS(cfg CORSConfig) func(http.Handler) http.Handler {
 
 	methods := strings.Join(cfg.AllowedMethods, ", ")
 	headers := strings.Join(cfg.AllowedHeaders, ", ")
+	exposed := strings.Join(cfg.ExposedHeaders, ", ")
 
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			origin := r.Header.Get("Origin")
 
+			// Responses differ by Origin even when it is not allowed, so
+			// caches must not reuse them across origins. addVary keeps any
+			// Vary tokens set by other middleware (e.g. Compress).
+			addVary(w.Header(), "Origin")
 			if origin != "" && originsSet[origin] {
 				w.Header().Set("Access-Control-Allow-Origin", origin)
 				w.Header().Set("Access-Control-Allow-Methods", methods)
 				w.Header().Set("Access-Control-Allow-Headers", headers)
 				w.Header().Set("Access-Control-Max-Age", cfg.MaxAge)
-				w.Header().Set("Vary", "Origin")
+				if exposed != "" {
+					w.Header().Set("Access-Control-Expose-Headers", exposed)
+				}
 			}
 
 			// Handle preflight
@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+)
+
+// CSRFHeaderName is the header that carries the CSRF token. The token is an
+// HMAC of the session cookie, so no second cookie is needed: Firebase
+// Hosting forwards only __session to Cloud Run.
+const CSRFHeaderName = "X-CSRF-Token"
+
+// csrfTokenKey is the context key for the page's CSRF token.
+const csrfTokenKey contextKey = "csrfToken"
+
+// NewCSRFKey derives the key for CSRF tokens from secret, labelled so it
+// differs from any other key taken from the same secret. An empty secret
+// uses a random key, so tokens only verify within this process.
+func NewCSRFKey(secret []byte) []byte {
+	if len(secret) == 0 {
+		key := make([]byte, 32)
+		rand.Read(key)
+		return key
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("paintbar csrf"))
+	return mac.Sum(nil)
+}
+
+// csrfToken returns the CSRF token for a session cookie value.
+func csrfToken(key []byte, session string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(session))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// CSRFToken returns middleware for page routes that exposes the CSRF token
+// for the visitor's session to templates via CSRFTokenFromContext. Visitors
+// without a session get no token. It must run after OptionalSession.
+func CSRFToken(key []byte) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cookie, err := r.Cookie(SessionCookieName)
+			if err != nil || !IsSessionAuth(r.Context()) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ctx := context.WithValue(r.Context(), csrfTokenKey, csrfToken(key, cookie.Value))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// CSRFTokenFromContext returns the CSRF token set by CSRFToken, or "".
+func CSRFTokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(csrfTokenKey).(string)
+	return token
+}
+
+// CSRF returns middleware that protects state-changing requests
+// authenticated by the session cookie. The request's Origin (or Referer,
+// when Origin is absent) must be the request's own host or one of
+// cfg.AllowedOrigins, and the X-CSRF-Token header must be the HMAC of the
+// session cookie under key. Safe methods and Bearer-token requests pass
+// through untouched, since a cross-site page cannot attach an Authorization
+// header. It must run after OptionalSession.
+func CSRF(cfg CORSConfig, key []byte) func(http.Handler) http.Handler {
+	originsSet := make(map[string]bool, len(cfg.AllowedOrigins))
+	for _, o := range cfg.AllowedOrigins {
+		originsSet[o] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !IsSessionAuth(r.Context()) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !sameOrAllowedOrigin(r, originsSet) {
+				slog.Warn("csrf origin rejected",
+					"origin", r.Header.Get("Origin"),
+					"referer", r.Header.Get("Referer"),
+					"path", r.URL.Path,
+				)
+				forbiddenJSON(w, "cross-origin request rejected")
+				return
+			}
+
+			cookie, err := r.Cookie(SessionCookieName)
+			header := r.Header.Get(CSRFHeaderName)
+			if err != nil || header == "" ||
+				!hmac.Equal([]byte(csrfToken(key, cookie.Value)), []byte(header)) {
+				slog.Warn("csrf token mismatch", "path", r.URL.Path)
+				forbiddenJSON(w, "invalid CSRF token")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireSameOrigin returns middleware for routes that cookie-less browsers
+// post to, such as swapping an ID token for a session cookie. CSRF cannot
+// protect them, since the caller has no session yet; instead the Origin (or
+// Referer) must be the request's own host or one of cfg.AllowedOrigins, so
+// a third-party page cannot sign the visitor in to another account.
+func RequireSameOrigin(cfg CORSConfig) func(http.Handler) http.Handler {
+	originsSet := make(map[string]bool, len(cfg.AllowedOrigins))
+	for _, o := range cfg.AllowedOrigins {
+		originsSet[o] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !sameOrAllowedOrigin(r, originsSet) {
+				slog.Warn("cross-origin request rejected",
+					"origin", r.Header.Get("Origin"),
+					"referer", r.Header.Get("Referer"),
+					"path", r.URL.Path,
+				)
+				forbiddenJSON(w, "cross-origin request rejected")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// sameOrAllowedOrigin checks the Origin header, falling back to Referer.
+// Requests carrying neither are rejected.
+func sameOrAllowedOrigin(r *http.Request, allowed map[string]bool) bool {
+	source := r.Header.Get("Origin")
+	if source == "" || source == "null" {
+		source = r.Header.Get("Referer")
+	}
+	if source == "" {
+		return false
+	}
+
+	u, err := url.Parse(source)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return false
+	}
+	if u.Host == r.Host {
+		return true
+	}
+	return allowed[u.Scheme+"://"+u.Host]
+}
+
+// forbiddenJSON writes a 403 JSON error response.
+func forbiddenJSON(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	json.NewEncoder(w).Encode(map[string]string{
+		"error": message,
+	})
+}
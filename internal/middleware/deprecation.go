@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Deprecated returns middleware for routes mounted at a deprecated prefix.
+// Responses carry a Deprecation header (RFC 9745) dated since, a Sunset
+// header (RFC 8594) with the date the routes go away, and a
+// rel="successor-version" Link to the same path under successor.
+func Deprecated(prefix, successor string, since, sunset time.Time) func(http.Handler) http.Handler {
+	deprecation := fmt.Sprintf("@%d", since.Unix())
+	sunsetDate := sunset.UTC().Format(http.TimeFormat)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", deprecation)
+			w.Header().Set("Sunset", sunsetDate)
+			if rest, ok := strings.CutPrefix(r.URL.Path, prefix); ok {
+				w.Header().Add("Link", fmt.Sprintf("<%s%s>; rel=\"successor-version\"", successor, rest))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/pandasWhoCode/paintbar/internal/metrics"
+)
+
+// Metrics returns middleware that records each request's status and
+// duration under its chi route pattern (e.g. /api/v1/projects/{id}) rather
+// than the raw path, which would give every ID its own series. It must run
+// outside Recovery so panics are counted as the 500s they become.
+func Metrics(m *metrics.Metrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r)
+
+			// The pattern is complete only once routing has finished
+			route := ""
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				route = rctx.RoutePattern()
+			}
+			m.ObserveRequest(r.Method, route, ww.Status(), time.Since(start))
+		})
+	}
+}
+
+// CountUploadBytes returns middleware that adds the request body bytes the
+// handler reads to the named upload counter.
+func CountUploadBytes(m *metrics.Metrics, upload string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := &countingReader{ReadCloser: r.Body}
+			r.Body = body
+			next.ServeHTTP(w, r)
+			m.AddUploadBytes(upload, body.n)
+		})
+	}
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.n += int64(n)
+	return n, err
+}
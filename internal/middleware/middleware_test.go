@@ -1,18 +1,30 @@
 package middleware
 
 import (
+	"bytes"
 	"encoding/json"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pandasWhoCode/paintbar/internal/metrics"
+	"github.com/pandasWhoCode/paintbar/internal/openapi"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
 )
 
 // helper: create a simple OK handler
@@ -85,6 +97,95 @@ func TestRequestLogger_5xxLogsError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, rr.Code)
 }
 
+// --- Tracing tests ---
+
+// recordSpans installs a global tracer provider that records ended spans,
+// and the W3C propagator, for the rest of the test.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	tp, prop := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	rec := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(tp)
+		otel.SetTextMapPropagator(prop)
+	})
+	return rec
+}
+
+func TestTracing_NamesSpanByRoutePattern(t *testing.T) {
+	rec := recordSpans(t)
+	r := chi.NewRouter()
+	r.Use(Tracing())
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Get("/projects/{id}", okHandler())
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/projects/abc", nil))
+
+	spans := rec.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /api/v1/projects/{id}", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), semconv.HTTPRoute("/api/v1/projects/{id}"))
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+}
+
+func TestTracing_ContinuesCallerTrace(t *testing.T) {
+	rec := recordSpans(t)
+	handler := Tracing()(okHandler())
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := rec.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+}
+
+func TestTracing_MarksServerErrors(t *testing.T) {
+	rec := recordSpans(t)
+	r := chi.NewRouter()
+	r.Use(Tracing())
+	r.Use(Recovery(slog.New(slog.NewJSONHandler(io.Discard, nil))))
+	r.Get("/boom", panicHandler())
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/boom", nil))
+
+	spans := rec.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
+
+func TestRequestLogger_LogsTraceID(t *testing.T) {
+	rec := recordSpans(t)
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	r := chi.NewRouter()
+	r.Use(Tracing())
+	r.Use(RequestLogger(logger))
+	r.Get("/test", okHandler())
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	require.Len(t, rec.Ended(), 1)
+	assert.Equal(t, rec.Ended()[0].SpanContext().TraceID().String(), entry["trace_id"])
+}
+
+func TestRequestLogger_OmitsTraceIDWhenUntraced(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	handler := RequestLogger(logger)(okHandler())
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	assert.NotContains(t, buf.String(), "trace_id")
+}
+
 // --- SecurityHeaders tests ---
 
 func TestSecurityHeaders_SetsAllHeaders(t *testing.T) {
@@ -173,6 +274,23 @@ func TestRateLimiter_BlocksOverLimit(t *testing.T) {
 	assert.Contains(t, body["error"], "rate limit exceeded")
 }
 
+func TestRateLimiter_RetryAfterFollowsWindow(t *testing.T) {
+	rl := NewRateLimiter(1, 1500*time.Millisecond)
+	defer rl.Close()
+	handler := rl.Handler()(okHandler())
+
+	var rr *httptest.ResponseRecorder
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/api/test", nil)
+		req.RemoteAddr = "10.0.0.1:12345"
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+	}
+
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "2", rr.Header().Get("Retry-After"), "rounded up to whole seconds")
+}
+
 func TestRateLimiter_SkipsStaticAssets(t *testing.T) {
 	rl := NewRateLimiter(1, time.Minute)
 	defer rl.Close()
@@ -207,13 +325,15 @@ func TestRateLimiter_SkipsHealthEndpoint(t *testing.T) {
 	handler.ServeHTTP(rr, req)
 	assert.Equal(t, http.StatusOK, rr.Code)
 
-	// /health should still pass even though limit is exhausted
-	for i := 0; i < 5; i++ {
-		req := httptest.NewRequest(http.MethodGet, "/health", nil)
-		req.RemoteAddr = "10.0.0.50:12345"
-		rr := httptest.NewRecorder()
-		handler.ServeHTTP(rr, req)
-		assert.Equal(t, http.StatusOK, rr.Code, "/health request %d should not be rate limited", i+1)
+	// Health probes should still pass even though limit is exhausted
+	for _, path := range []string{"/health", "/livez", "/readyz"} {
+		for i := 0; i < 5; i++ {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.RemoteAddr = "10.0.0.50:12345"
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, http.StatusOK, rr.Code, "%s request %d should not be rate limited", path, i+1)
+		}
 	}
 }
 
@@ -441,6 +561,76 @@ func TestRateLimiter_PurgeExpired_NoneExpired(t *testing.T) {
 	assert.Len(t, rl.visitors, 2)
 }
 
+// --- Metrics tests ---
+
+// scrapeMetrics returns m's exposition in the Prometheus text format.
+func scrapeMetrics(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	return rr.Body.String()
+}
+
+func TestMetrics_LabelsByRoutePattern(t *testing.T) {
+	m := metrics.New()
+	r := chi.NewRouter()
+	r.Use(Metrics(m))
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Get("/projects/{id}", okHandler())
+	})
+
+	for _, path := range []string{"/api/v1/projects/a", "/api/v1/projects/b", "/nowhere"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	out := scrapeMetrics(t, m)
+	assert.Contains(t, out, `paintbar_http_requests_total{code="200",method="GET",route="/api/v1/projects/{id}"} 2`)
+	assert.Contains(t, out, `paintbar_http_requests_total{code="404",method="GET",route="unmatched"} 1`)
+	assert.NotContains(t, out, "/api/v1/projects/a")
+}
+
+func TestMetrics_CountsRecoveredPanics(t *testing.T) {
+	m := metrics.New()
+	r := chi.NewRouter()
+	r.Use(Metrics(m))
+	r.Use(Recovery(slog.New(slog.NewJSONHandler(io.Discard, nil))))
+	r.Get("/boom", panicHandler())
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/boom", nil))
+
+	assert.Contains(t, scrapeMetrics(t, m), `paintbar_http_requests_total{code="500",method="GET",route="/boom"} 1`)
+}
+
+func TestCountUploadBytes(t *testing.T) {
+	m := metrics.New()
+	handler := CountUploadBytes(m, "chunk")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPut, "/upload", strings.NewReader("0123456789")))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPut, "/upload", strings.NewReader("01234")))
+
+	assert.Contains(t, scrapeMetrics(t, m), `paintbar_upload_bytes_total{upload="chunk"} 15`)
+}
+
+func TestRateLimiter_Instrument(t *testing.T) {
+	m := metrics.New()
+	rl := NewRateLimiter(1, time.Minute)
+	defer rl.Close()
+	rl.Instrument("sensitive", m)
+	handler := SensitiveEndpoint(rl)(okHandler())
+
+	for _, ip := range []string{"10.0.0.1", "10.0.0.1", "10.0.0.2"} {
+		req := httptest.NewRequest(http.MethodPost, "/api/claim-username", nil)
+		req.RemoteAddr = ip + ":12345"
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	out := scrapeMetrics(t, m)
+	assert.Contains(t, out, `paintbar_rate_limit_rejections_total{limiter="sensitive"} 1`)
+	assert.Contains(t, out, `paintbar_rate_limit_visitors{limiter="sensitive"} 2`)
+}
+
 // --- Recovery tests ---
 
 func TestRecovery_CatchesPanic(t *testing.T) {
@@ -470,3 +660,325 @@ func TestRecovery_PassesThroughNormally(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rr.Code)
 	assert.Equal(t, "ok", rr.Body.String())
 }
+
+// --- Compress tests ---
+
+// jsonHandler writes n bytes of compressible JSON-ish content.
+func jsonHandler(n int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"abc"`)
+		w.Write([]byte(`"` + strings.Repeat("a", n-2) + `"`))
+	}
+}
+
+func compressRequest(h http.Handler, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/projects", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr
+}
+
+func decodeBody(t *testing.T, rr *httptest.ResponseRecorder) string {
+	t.Helper()
+	var r io.Reader = rr.Body
+	switch rr.Header().Get("Content-Encoding") {
+	case "gzip":
+		gz, err := gzip.NewReader(rr.Body)
+		require.NoError(t, err)
+		r = gz
+	case "zstd":
+		zr, err := zstd.NewReader(rr.Body)
+		require.NoError(t, err)
+		defer zr.Close()
+		r = zr
+	}
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", "gzip"},
+		{"gzip, deflate, br, zstd", "zstd"},
+		{"zstd;q=0.5, gzip", "gzip"},
+		{"GZIP", "gzip"},
+		{"gzip;q=0, zstd;q=0", ""},
+		{"*", "zstd"},
+		{"*;q=0.1, zstd;q=0", "gzip"},
+		{"br", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, negotiateEncoding(tt.header), tt.header)
+	}
+}
+
+func TestCompress_Gzip(t *testing.T) {
+	h := Compress(DefaultCompressMinSize)(jsonHandler(5000))
+	rr := compressRequest(h, "gzip")
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", rr.Header().Get("Vary"))
+	assert.Equal(t, `W/"abc"`, rr.Header().Get("ETag"))
+	assert.Less(t, rr.Body.Len(), 5000)
+	assert.Len(t, decodeBody(t, rr), 5000)
+}
+
+func TestCompress_Zstd(t *testing.T) {
+	h := Compress(DefaultCompressMinSize)(jsonHandler(5000))
+	rr := compressRequest(h, "gzip, zstd")
+
+	assert.Equal(t, "zstd", rr.Header().Get("Content-Encoding"))
+	assert.Len(t, decodeBody(t, rr), 5000)
+}
+
+func TestCompress_PassThrough(t *testing.T) {
+	png := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(make([]byte, 5000))
+	}
+	partial := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Content-Range", "bytes 0-4999/10000")
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(make([]byte, 5000))
+	}
+	noTransform := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-transform")
+		w.Write(make([]byte, 5000))
+	}
+	preEncoded := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "br")
+		w.Write(make([]byte, 5000))
+	}
+
+	tests := []struct {
+		name     string
+		handler  http.HandlerFunc
+		accept   string
+		wantVary string
+	}{
+		{"small body", jsonHandler(100), "gzip", "Accept-Encoding"},
+		{"no accept-encoding", jsonHandler(5000), "", "Accept-Encoding"},
+		{"png", png, "gzip", ""},
+		{"partial content", partial, "gzip", ""},
+		{"no-transform", noTransform, "gzip", ""},
+		{"already encoded", preEncoded, "gzip", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := compressRequest(Compress(DefaultCompressMinSize)(tt.handler), tt.accept)
+			assert.NotContains(t, []string{"gzip", "zstd"}, rr.Header().Get("Content-Encoding"))
+			assert.Equal(t, tt.wantVary, rr.Header().Get("Vary"))
+			assert.NotEmpty(t, rr.Body.Bytes())
+		})
+	}
+}
+
+func TestCompress_SmallBodyKeepsContentLength(t *testing.T) {
+	h := Compress(DefaultCompressMinSize)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Content-Length", "5")
+		w.Write([]byte("hello"))
+	}))
+	rr := compressRequest(h, "gzip")
+
+	assert.Empty(t, rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, "5", rr.Header().Get("Content-Length"))
+	assert.Equal(t, "hello", rr.Body.String())
+}
+
+func TestCompress_NotModified(t *testing.T) {
+	h := Compress(DefaultCompressMinSize)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	rr := compressRequest(h, "gzip")
+
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Empty(t, rr.Header().Get("Content-Encoding"))
+	assert.Empty(t, rr.Body.Bytes())
+}
+
+func TestCompress_SniffsContentType(t *testing.T) {
+	h := Compress(DefaultCompressMinSize)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html>" + strings.Repeat("x", 4000) + "</html>"))
+	}))
+	rr := compressRequest(h, "gzip")
+
+	assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+	assert.Contains(t, rr.Header().Get("Content-Type"), "text/html")
+}
+
+func TestCompress_FlushStreams(t *testing.T) {
+	h := Compress(DefaultCompressMinSize)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: 1\n\n"))
+		w.(http.Flusher).Flush()
+		w.Write([]byte("data: 2\n\n"))
+	}))
+	rr := compressRequest(h, "gzip")
+
+	assert.True(t, rr.Flushed)
+	assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, "data: 1\n\ndata: 2\n\n", decodeBody(t, rr))
+}
+
+func TestCompress_WithRequestLoggerAndCORS(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := RequestLogger(logger)(Compress(DefaultCompressMinSize)(CORS(DefaultCORSConfig("production"))(jsonHandler(5000))))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/projects", nil)
+	req.Header.Set("Origin", "https://paintbar.art")
+	req.Header.Set("Accept-Encoding", "gzip")
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+	assert.ElementsMatch(t, []string{"Origin", "Accept-Encoding"}, rr.Header().Values("Vary"))
+	assert.Equal(t, "https://paintbar.art", rr.Header().Get("Access-Control-Allow-Origin"))
+	assert.Len(t, decodeBody(t, rr), 5000)
+}
+
+func TestAddVary(t *testing.T) {
+	h := http.Header{}
+	h.Set("Vary", "Origin, accept-encoding")
+	addVary(h, "Accept-Encoding", "Origin", "Cookie")
+	assert.Equal(t, []string{"Origin, accept-encoding", "Cookie"}, h.Values("Vary"))
+
+	h = http.Header{}
+	h.Set("Vary", "*")
+	addVary(h, "Origin")
+	assert.Equal(t, []string{"*"}, h.Values("Vary"))
+}
+
+const validateTestSpec = `
+openapi: 3.1.0
+paths:
+  /api/v1/items:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema: {type: integer, minimum: 1}
+      responses:
+        "200":
+          content:
+            application/json:
+              schema: {type: array}
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name: {type: string}
+      responses:
+        "201":
+          content:
+            application/json:
+              schema: {type: object}
+`
+
+func TestValidateOpenAPI_RejectsInvalidRequest(t *testing.T) {
+	spec, err := openapi.Load([]byte(validateTestSpec))
+	require.NoError(t, err)
+	called := false
+	h := ValidateOpenAPI(spec)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true }))
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/items?limit=0", nil))
+
+	assert.False(t, called)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Contains(t, body["error"], `invalid query parameter "limit"`)
+}
+
+func TestValidateOpenAPI_BodyStillReadable(t *testing.T) {
+	spec, err := openapi.Load([]byte(validateTestSpec))
+	require.NoError(t, err)
+	var got string
+	h := ValidateOpenAPI(spec)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		got = string(b)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/items", strings.NewReader(`{"name":"sky"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, `{"name":"sky"}`, got)
+	assert.Equal(t, `{"id":"1"}`, rr.Body.String())
+}
+
+func TestValidateOpenAPI_LogsInvalidResponse(t *testing.T) {
+	spec, err := openapi.Load([]byte(validateTestSpec))
+	require.NoError(t, err)
+	var logs strings.Builder
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	defer slog.SetDefault(prev)
+
+	h := ValidateOpenAPI(spec)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items":[]}`))
+	}))
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/items", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code, "the response is sent unchanged")
+	assert.Equal(t, `{"items":[]}`, rr.Body.String())
+	assert.Contains(t, logs.String(), "response does not match openapi spec")
+	assert.Contains(t, logs.String(), "GET /api/v1/items")
+}
+
+func TestValidateOpenAPI_PassesUndocumentedPaths(t *testing.T) {
+	spec, err := openapi.Load([]byte(validateTestSpec))
+	require.NoError(t, err)
+	h := ValidateOpenAPI(spec)(okHandler())
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/api/v1/other?limit=0", nil),
+		httptest.NewRequest(http.MethodDelete, "/api/v1/items", nil),
+	} {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code, req.Method+" "+req.URL.Path)
+	}
+}
+
+func TestDeprecated(t *testing.T) {
+	since := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC)
+	h := Deprecated("/api", "/api/v1", since, sunset)(okHandler())
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/projects/abc?x=1", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "@1792368000", rr.Header().Get("Deprecation"))
+	assert.Equal(t, "Fri, 30 Apr 2027 00:00:00 GMT", rr.Header().Get("Sunset"))
+	assert.Equal(t, `</api/v1/projects/abc>; rel="successor-version"`, rr.Header().Get("Link"))
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/pandasWhoCode/paintbar/internal/openapi"
+)
+
+// maxValidatedBodySize caps the request and response bodies buffered for
+// validation. Larger JSON bodies are passed through unchecked.
+const maxValidatedBodySize = 1 << 20
+
+// ValidateOpenAPI returns middleware that checks API traffic against the
+// OpenAPI spec. Requests that violate it are rejected with 400 before they
+// reach a handler; responses that violate it have already been sent, so
+// they are logged as errors. Undocumented paths and methods pass through to
+// the router. Meant for local and preview, so drift between the handlers
+// and api/openapi.yaml surfaces before production.
+func ValidateOpenAPI(spec *openapi.Spec) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			op, params, ok := spec.Find(r.Method, r.URL.Path)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Only JSON bodies are inspected; uploads are streamed untouched
+			var body []byte
+			if isJSONType(r.Header.Get("Content-Type")) && r.Body != nil {
+				buf, err := io.ReadAll(io.LimitReader(r.Body, maxValidatedBodySize+1))
+				if err != nil {
+					writeValidationError(w, "invalid request: unreadable body")
+					return
+				}
+				r.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.MultiReader(bytes.NewReader(buf), r.Body), r.Body}
+				if len(buf) <= maxValidatedBodySize {
+					body = buf
+				}
+			}
+			if err := op.ValidateRequest(r, params, body); err != nil {
+				writeValidationError(w, err.Error())
+				return
+			}
+
+			vw := &validatingWriter{ResponseWriter: w}
+			next.ServeHTTP(vw, r)
+
+			status := vw.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			var respBody []byte
+			if vw.capture {
+				respBody = vw.body.Bytes()
+			}
+			if vw.truncated {
+				return
+			}
+			if err := op.ValidateResponse(status, vw.Header(), respBody); err != nil {
+				slog.Error("response does not match openapi spec",
+					"operation", op.String(),
+					"status", status,
+					"error", err,
+				)
+			}
+		})
+	}
+}
+
+func writeValidationError(w http.ResponseWriter, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
+
+func isJSONType(header string) bool {
+	mediaType, _, err := mime.ParseMediaType(header)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+// validatingWriter records the status of a response and keeps a copy of
+// its body when it is JSON, while writing through to the client.
+type validatingWriter struct {
+	http.ResponseWriter
+	status    int
+	capture   bool // body is JSON and being copied
+	truncated bool // body outgrew maxValidatedBodySize
+	body      bytes.Buffer
+}
+
+func (vw *validatingWriter) WriteHeader(status int) {
+	if vw.status == 0 {
+		vw.status = status
+		vw.capture = isJSONType(vw.Header().Get("Content-Type"))
+	}
+	vw.ResponseWriter.WriteHeader(status)
+}
+
+func (vw *validatingWriter) Write(p []byte) (int, error) {
+	if vw.status == 0 {
+		vw.WriteHeader(http.StatusOK)
+	}
+	if vw.capture && !vw.truncated {
+		if vw.body.Len()+len(p) > maxValidatedBodySize {
+			vw.truncated = true
+			vw.body.Reset()
+		} else {
+			vw.body.Write(p)
+		}
+	}
+	return vw.ResponseWriter.Write(p)
+}
+
+// Flush passes through to the underlying writer when it supports flushing.
+func (vw *validatingWriter) Flush() {
+	if f, ok := vw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (vw *validatingWriter) Unwrap() http.ResponseWriter {
+	return vw.ResponseWriter
+}
@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/pandasWhoCode/paintbar/internal/metrics"
 	"github.com/pandasWhoCode/paintbar/internal/service"
 )
 
@@ -19,6 +21,9 @@ type RateLimiter struct {
 	window   time.Duration // window duration
 	cleanup  time.Duration // how often to purge expired entries
 	done     chan struct{} // signals cleanupLoop to stop
+
+	name    string           // label for the limiter's metrics
+	metrics *metrics.Metrics // nil until Instrument
 }
 
 type visitor struct {
@@ -47,25 +52,42 @@ func (rl *RateLimiter) Close() {
 	close(rl.done)
 }
 
+// Instrument reports the limiter's rejections and the number of clients it
+// is tracking to m under the given name.
+func (rl *RateLimiter) Instrument(name string, m *metrics.Metrics) {
+	rl.name = name
+	rl.metrics = m
+	m.TrackVisitors(name, rl.size)
+}
+
+// healthPaths are the probe endpoints, which load balancers poll from a
+// handful of addresses.
+var healthPaths = map[string]bool{
+	"/livez":  true,
+	"/readyz": true,
+	"/health": true,
+}
+
 // Handler returns middleware that enforces the rate limit.
 // Skips static asset requests.
 func (rl *RateLimiter) Handler() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Skip rate limiting for static assets and health checks
-			if strings.HasPrefix(r.URL.Path, "/static/") || r.URL.Path == "/health" {
+			// Skip rate limiting for static assets and health probes
+			if strings.HasPrefix(r.URL.Path, "/static/") || healthPaths[r.URL.Path] {
 				next.ServeHTTP(w, r)
 				return
 			}
 
 			key := extractIP(r)
 			if !rl.allow(key) {
+				rl.metrics.RateLimited(rl.name)
 				slog.Warn("rate limit exceeded",
 					"ip", key,
 					"path", r.URL.Path,
 				)
 				w.Header().Set("Content-Type", "application/json")
-				w.Header().Set("Retry-After", "60")
+				w.Header().Set("Retry-After", rl.retryAfter())
 				w.WriteHeader(http.StatusTooManyRequests)
 				json.NewEncoder(w).Encode(map[string]string{
 					"error": "rate limit exceeded, please try again later",
@@ -99,6 +121,13 @@ func (rl *RateLimiter) allow(key string) bool {
 	return true
 }
 
+// size returns the number of clients being tracked.
+func (rl *RateLimiter) size() int {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return len(rl.visitors)
+}
+
 // cleanupLoop periodically removes expired visitor entries.
 // Coverage: defer ticker.Stop() is unreachable — this goroutine runs for the
 // lifetime of the process. The actual cleanup logic is tested via purgeExpired.
@@ -128,6 +157,13 @@ func (rl *RateLimiter) purgeExpired() {
 	rl.mu.Unlock()
 }
 
+// retryAfter is the Retry-After value for a limited request: the whole
+// window in seconds, rounded up.
+func (rl *RateLimiter) retryAfter() string {
+	secs := int64((rl.window + time.Second - 1) / time.Second)
+	return strconv.FormatInt(max(secs, 1), 10)
+}
+
 // SensitiveEndpoint returns middleware that applies a stricter rate limit
 // to sensitive endpoints like username claiming. It uses a separate limiter
 // instance so it doesn't share the global budget.
@@ -144,12 +180,13 @@ func SensitiveEndpoint(rl *RateLimiter) func(http.Handler) http.Handler {
 				key = "uid:" + userInfo.UID + "|" + ip
 			}
 			if !rl.allow(key) {
+				rl.metrics.RateLimited(rl.name)
 				slog.Warn("sensitive endpoint rate limit exceeded",
 					"key", key,
 					"path", r.URL.Path,
 				)
 				w.Header().Set("Content-Type", "application/json")
-				w.Header().Set("Retry-After", "60")
+				w.Header().Set("Retry-After", rl.retryAfter())
 				w.WriteHeader(http.StatusTooManyRequests)
 				json.NewEncoder(w).Encode(map[string]string{
 					"error": "too many attempts, please try again later",
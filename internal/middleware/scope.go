@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/pandasWhoCode/paintbar/internal/service"
+)
+
+// verifierChain tries each TokenVerifier in order and returns the first
+// identity that verifies.
+type verifierChain []TokenVerifier
+
+// ChainVerifiers combines verifiers so Auth can accept several kinds of
+// bearer credential (personal access tokens and Firebase ID tokens).
+func ChainVerifiers(verifiers ...TokenVerifier) TokenVerifier {
+	return verifierChain(verifiers)
+}
+
+// VerifyIDToken implements TokenVerifier.
+func (c verifierChain) VerifyIDToken(ctx context.Context, token string) (*service.UserInfo, error) {
+	var errs []error
+	for _, v := range c {
+		user, err := v.VerifyIDToken(ctx, token)
+		if err == nil {
+			return user, nil
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		return nil, errors.New("no token verifiers configured")
+	}
+	return nil, errors.Join(errs...)
+}
+
+// RequireScope returns middleware that limits personal access tokens to
+// routes matching their scopes: safe methods (GET, HEAD, OPTIONS) need
+// readScope and everything else needs writeScope. Firebase ID tokens pass.
+// It must run after Auth.
+func RequireScope(readScope, writeScope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := UserFromContext(r.Context())
+			if user == nil {
+				unauthorizedJSON(w, "authentication required")
+				return
+			}
+
+			scope := writeScope
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				scope = readScope
+			}
+			if !user.HasScope(scope) {
+				slog.Warn("scope check failed",
+					"uid", user.UID,
+					"tokenId", user.TokenID,
+					"scope", scope,
+					"path", r.URL.Path,
+				)
+				forbiddenJSON(w, "token missing required scope "+scope)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// DenyAPITokens returns middleware that rejects personal access tokens on
+// routes no scope covers, such as account settings, token management and the
+// admin API. It must run after Auth.
+func DenyAPITokens() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if user := UserFromContext(r.Context()); user != nil && user.TokenID != "" {
+				forbiddenJSON(w, "API tokens cannot access this endpoint")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
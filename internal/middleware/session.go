@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/pandasWhoCode/paintbar/internal/service"
+)
+
+// SessionCookieName is the name of the Firebase session cookie. Firebase
+// Hosting strips every cookie except __session before forwarding to Cloud
+// Run, so the name is not arbitrary.
+const SessionCookieName = "__session"
+
+// SessionVerifier verifies a session cookie and returns user info.
+type SessionVerifier interface {
+	VerifySessionCookie(ctx context.Context, cookie string) (*service.UserInfo, error)
+}
+
+// sessionAuthKey marks a request authenticated by the session cookie rather
+// than a Bearer token.
+const sessionAuthKey contextKey = "sessionAuth"
+
+// OptionalSession returns middleware that reads the session cookie and, when
+// it verifies, injects the user into the request context exactly as Auth
+// does for Bearer tokens. Requests without a valid cookie continue
+// anonymously; an invalid or expired cookie is cleared so the browser stops
+// sending it. Requests carrying an Authorization header are left to Auth.
+func OptionalSession(verifier SessionVerifier, secure bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cookie, err := r.Cookie(SessionCookieName)
+			if err != nil || cookie.Value == "" || verifier == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			userInfo, err := verifier.VerifySessionCookie(r.Context(), cookie.Value)
+			if err != nil {
+				slog.Debug("session cookie rejected",
+					"error", err,
+					"path", r.URL.Path,
+				)
+				ClearSessionCookie(w, secure)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), UserContextKey, userInfo)
+			ctx = context.WithValue(ctx, sessionAuthKey, true)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// IsSessionAuth reports whether the request was authenticated by the
+// session cookie. Such requests are subject to CSRF checks.
+func IsSessionAuth(ctx context.Context) bool {
+	ok, _ := ctx.Value(sessionAuthKey).(bool)
+	return ok
+}
+
+// RequireSession returns middleware that redirects visitors without a
+// session to loginPath. It must run after OptionalSession.
+func RequireSession(loginPath string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if UserFromContext(r.Context()) == nil {
+				http.Redirect(w, r, loginPath, http.StatusSeeOther)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// SetSessionCookie writes the session cookie. It is HttpOnly so scripts
+// cannot read it, and SameSite=Lax so it is sent on top-level navigations
+// (following a link to /profile) but not on cross-site subrequests.
+func SetSessionCookie(w http.ResponseWriter, value string, ttl time.Duration, secure bool) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookieName,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   int(ttl.Seconds()),
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// ClearSessionCookie expires the session cookie in the browser.
+func ClearSessionCookie(w http.ResponseWriter, secure bool) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
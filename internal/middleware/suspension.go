@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+)
+
+// SuspensionChecker reports whether a user has been suspended by a moderator.
+type SuspensionChecker interface {
+	IsSuspended(ctx context.Context, uid string) (bool, error)
+}
+
+// RejectSuspended returns middleware that blocks state-changing requests
+// (anything other than GET, HEAD and OPTIONS) from suspended users with a
+// 403. Suspended users keep read access to their own data. It must run
+// after Auth.
+func RejectSuspended(checker SuspensionChecker) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			user := UserFromContext(r.Context())
+			if user == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			suspended, err := checker.IsSuspended(r.Context(), user.UID)
+			if err != nil {
+				slog.Error("suspension check failed",
+					"error", err,
+					"uid", user.UID,
+				)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(map[string]string{
+					"error": "internal server error",
+				})
+				return
+			}
+			if suspended {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusForbidden)
+				json.NewEncoder(w).Encode(map[string]string{
+					"error": "account suspended",
+				})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
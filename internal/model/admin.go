@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// Account is the Firebase Auth side of a user, as shown to admins.
+type Account struct {
+	UID           string    `json:"uid"`
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"emailVerified"`
+	Disabled      bool      `json:"disabled"`
+	Roles         []string  `json:"roles,omitempty"`
+	CreatedAt     time.Time `json:"createdAt,omitempty"`
+	LastSignInAt  time.Time `json:"lastSignInAt,omitempty"`
+}
+
+// AdminUser combines a user's auth account with their Firestore profile.
+// Profile is nil for accounts that have never saved a profile.
+type AdminUser struct {
+	Account *Account `json:"account"`
+	Profile *User    `json:"profile"`
+}
+
+// UsageStats holds site-wide document counts for the admin dashboard.
+type UsageStats struct {
+	Users        int64     `json:"users"`
+	Projects     int64     `json:"projects"`
+	GalleryItems int64     `json:"galleryItems"`
+	Comments     int64     `json:"comments"`
+	NFTs         int64     `json:"nfts"`
+	OpenReports  int64     `json:"openReports"`
+	GeneratedAt  time.Time `json:"generatedAt"`
+}
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"reflect"
+	"time"
+)
+
+// Audit actions. User actions are recorded for the acting user; admin
+// actions are recorded for the admin who performed them.
+const (
+	AuditActionUsernameClaim     = "username.claim"
+	AuditActionProfileUpdate     = "profile.update"
+	AuditActionProjectDelete     = "project.delete"
+	AuditActionProjectVisibility = "project.visibility"
+	AuditActionGalleryShare      = "gallery.share"
+	AuditActionNFTCreate         = "nft.create"
+	AuditActionTokenCreate       = "token.create"
+	AuditActionTokenRevoke       = "token.revoke"
+	AuditActionWebhookCreate     = "webhook.create"
+	AuditActionWebhookDelete     = "webhook.delete"
+
+	AuditActionUserLookup      = "user.lookup"
+	AuditActionUserDisable     = "user.disable"
+	AuditActionUserEnable      = "user.enable"
+	AuditActionUserSuspend     = "user.suspend"
+	AuditActionUserUnsuspend   = "user.unsuspend"
+	AuditActionUsernameRelease = "username.release"
+	AuditActionReportResolve   = "report.resolve"
+	AuditActionGalleryHide     = "gallery.hide"
+	AuditActionGalleryUnhide   = "gallery.unhide"
+	AuditActionGalleryRemove   = "gallery.remove"
+	AuditActionCommentHide     = "comment.hide"
+	AuditActionCommentUnhide   = "comment.unhide"
+	AuditActionCommentRemove   = "comment.remove"
+	AuditActionNFTHide         = "nft.hide"
+	AuditActionNFTUnhide       = "nft.unhide"
+	AuditActionNFTRemove       = "nft.remove"
+)
+
+// Reserved audit actions for flows that do not exist yet. There is no
+// share-link feature, and NFTs are off-chain records (nft.create) until Hiero
+// minting and transfers land, so nothing records these today.
+const (
+	AuditActionShareLinkCreate = "sharelink.create"
+	AuditActionNFTMint         = "nft.mint"
+	AuditActionNFTTransfer     = "nft.transfer"
+)
+
+// Audit resource types.
+const (
+	AuditResourceUser     = "user"
+	AuditResourceUsername = "username"
+	AuditResourceProject  = "project"
+	AuditResourceGallery  = "gallery"
+	AuditResourceComment  = "comment"
+	AuditResourceNFT      = "nft"
+	AuditResourceReport   = "report"
+	AuditResourceToken    = "token"
+	AuditResourceWebhook  = "webhook"
+)
+
+// AuditEntry is one record in the append-only audit log. Entries are
+// written by the server only and are never updated or deleted.
+type AuditEntry struct {
+	ID           string                 `firestore:"-" json:"id"`
+	ActorUID     string                 `firestore:"actorUid" json:"actorUid"`
+	IP           string                 `firestore:"ip,omitempty" json:"ip,omitempty"`
+	RequestID    string                 `firestore:"requestId,omitempty" json:"requestId,omitempty"`
+	Action       string                 `firestore:"action" json:"action"`
+	ResourceType string                 `firestore:"resourceType" json:"resourceType"`
+	ResourceID   string                 `firestore:"resourceId" json:"resourceId"`
+	Changes      map[string]AuditChange `firestore:"changes,omitempty" json:"changes,omitempty"`
+	Details      map[string]interface{} `firestore:"details,omitempty" json:"details,omitempty"`
+	CreatedAt    time.Time              `firestore:"createdAt" json:"createdAt"`
+}
+
+// AuditChange is the before and after value of one changed field.
+type AuditChange struct {
+	Before interface{} `firestore:"before" json:"before"`
+	After  interface{} `firestore:"after" json:"after"`
+}
+
+// AuditDiff returns the fields of after whose value differs from before.
+// Fields only present in before are ignored, which suits partial updates:
+// pass the full previous state as before and the update map as after.
+// updatedAt is always skipped. Returns nil if nothing changed.
+func AuditDiff(before, after map[string]interface{}) map[string]AuditChange {
+	var changes map[string]AuditChange
+	for field, newValue := range after {
+		if field == "updatedAt" {
+			continue
+		}
+		oldValue := before[field]
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		if changes == nil {
+			changes = make(map[string]AuditChange)
+		}
+		changes[field] = AuditChange{Before: oldValue, After: newValue}
+	}
+	return changes
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxCommentLen is the maximum length of a comment body in characters.
+const MaxCommentLen = 1000
+
+// Comment represents a comment on a gallery item, stored in the
+// gallery/{itemId}/comments subcollection. Threads are one level deep:
+// ParentID is empty for top-level comments and set to the top-level
+// comment's ID for replies.
+type Comment struct {
+	ID        string    `firestore:"-" json:"id"`
+	ItemID    string    `firestore:"-" json:"itemId"`
+	UserID    string    `firestore:"userId" json:"userId"`
+	Username  string    `firestore:"username" json:"username"`
+	ParentID  string    `firestore:"parentId,omitempty" json:"parentId,omitempty"`
+	Body      string    `firestore:"body" json:"body"`
+	Edited    bool      `firestore:"edited" json:"edited"`
+	Hidden    bool      `firestore:"hidden" json:"-"`
+	CreatedAt time.Time `firestore:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time `firestore:"updatedAt" json:"updatedAt"`
+}
+
+// Sanitize cleans comment input.
+func (c *Comment) Sanitize() {
+	c.Body = SanitizeCommentBody(c.Body)
+	c.ParentID = strings.TrimSpace(c.ParentID)
+}
+
+// Validate checks that the Comment has required fields and valid values.
+func (c *Comment) Validate() error {
+	if c.UserID == "" {
+		return fmt.Errorf("userId is required")
+	}
+	return ValidateCommentBody(c.Body)
+}
+
+// SanitizeCommentBody trims whitespace and strips control characters.
+func SanitizeCommentBody(body string) string {
+	return StripControlChars(strings.TrimSpace(body))
+}
+
+// ValidateCommentBody checks that a sanitized comment body is non-empty and
+// within MaxCommentLen characters.
+func ValidateCommentBody(body string) error {
+	if body == "" {
+		return fmt.Errorf("comment body is required")
+	}
+	if utf8.RuneCountInString(body) > MaxCommentLen {
+		return fmt.Errorf("comment body must be %d characters or less", MaxCommentLen)
+	}
+	return nil
+}
+
+// Reactions maps the allowed reaction keys to the emoji they render as.
+// Keys (not glyphs) are stored in Firestore so they are safe field names.
+var Reactions = map[string]string{
+	"heart": "❤️",
+	"fire":  "🔥",
+	"laugh": "😂",
+	"wow":   "😮",
+	"clap":  "👏",
+	"sad":   "😢",
+}
+
+// ValidateReaction checks that a reaction key is in the allow-list.
+func ValidateReaction(reaction string) error {
+	if _, ok := Reactions[reaction]; !ok {
+		return fmt.Errorf("invalid reaction %q", reaction)
+	}
+	return nil
+}
+
+// ReactionSummary is the aggregate reaction state of a gallery item as seen
+// by the requesting user.
+type ReactionSummary struct {
+	Counts map[string]int64 `json:"counts"`
+	Mine   []string         `json:"mine"`
+}
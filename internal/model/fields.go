@@ -0,0 +1,70 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FieldSet is a sparse fieldset: the JSON field names a client asked for
+// with ?fields=, in request order. A nil FieldSet means every field of the
+// projection.
+type FieldSet []string
+
+// Projection maps each JSON field of a list projection to the Firestore
+// field paths needed to fill it. Fields computed from the document ID, such
+// as id and thumbnailUrl, map to none.
+type Projection map[string][]string
+
+// ParseFields parses a comma-separated ?fields= value against p. Unknown
+// names are rejected and duplicates dropped. An empty value yields nil.
+func (p Projection) ParseFields(raw string) (FieldSet, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	var set FieldSet
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := p[name]; !ok {
+			return nil, fmt.Errorf("invalid fields: unknown field %q", name)
+		}
+		if !seen[name] {
+			seen[name] = true
+			set = append(set, name)
+		}
+	}
+	if len(set) == 0 {
+		return nil, fmt.Errorf("invalid fields: at least one field name is required")
+	}
+	return set, nil
+}
+
+// Paths returns the sorted Firestore field paths needed to fill set, or
+// those of every field when set is nil. An empty result means only
+// document IDs need to be read.
+func (p Projection) Paths(set FieldSet) []string {
+	if set == nil {
+		set = make(FieldSet, 0, len(p))
+		for name := range p {
+			set = append(set, name)
+		}
+	}
+
+	seen := make(map[string]bool)
+	paths := []string{}
+	for _, name := range set {
+		for _, path := range p[name] {
+			if !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
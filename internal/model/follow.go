@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+// Follow represents one edge in the follow graph. It is stored twice: under
+// users/{followee}/followers/{follower} and users/{follower}/following/{followee}.
+// The document ID is the UID of the other party; Username is denormalized
+// because usernames are immutable once claimed.
+type Follow struct {
+	UID       string    `firestore:"-" json:"uid"`
+	Username  string    `firestore:"username" json:"username"`
+	CreatedAt time.Time `firestore:"createdAt" json:"createdAt"`
+}
+
+// PublicProfile is the subset of a User that is visible to other users.
+// Email and wallet address are deliberately omitted.
+type PublicProfile struct {
+	UID             string    `json:"uid"`
+	Username        string    `json:"username"`
+	DisplayName     string    `json:"displayName,omitempty"`
+	Bio             string    `json:"bio,omitempty"`
+	Location        string    `json:"location,omitempty"`
+	Website         string    `json:"website,omitempty"`
+	GithubURL       string    `json:"githubUrl,omitempty"`
+	TwitterHandle   string    `json:"twitterHandle,omitempty"`
+	BlueskyHandle   string    `json:"blueskyHandle,omitempty"`
+	InstagramHandle string    `json:"instagramHandle,omitempty"`
+	FollowerCount   int64     `json:"followerCount"`
+	FollowingCount  int64     `json:"followingCount"`
+	IsFollowing     bool      `json:"isFollowing"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// ToPublicProfile returns the publicly visible view of the user.
+func (u *User) ToPublicProfile() *PublicProfile {
+	return &PublicProfile{
+		UID:             u.UID,
+		Username:        u.Username,
+		DisplayName:     u.DisplayName,
+		Bio:             u.Bio,
+		Location:        u.Location,
+		Website:         u.Website,
+		GithubURL:       u.GithubURL,
+		TwitterHandle:   u.TwitterHandle,
+		BlueskyHandle:   u.BlueskyHandle,
+		InstagramHandle: u.InstagramHandle,
+		FollowerCount:   u.FollowerCount,
+		FollowingCount:  u.FollowingCount,
+		CreatedAt:       u.CreatedAt,
+	}
+}
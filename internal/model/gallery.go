@@ -8,17 +8,69 @@ import (
 
 // GalleryItem represents an artwork shared to the public gallery in Firestore.
 type GalleryItem struct {
-	ID            string    `firestore:"-" json:"id"`
-	UserID        string    `firestore:"userId" json:"userId"`
-	ProjectID     string    `firestore:"projectId,omitempty" json:"projectId,omitempty"`
-	Name          string    `firestore:"name" json:"name"`
-	Description   string    `firestore:"description,omitempty" json:"description,omitempty"`
-	ImageData     string    `firestore:"imageData,omitempty" json:"imageData,omitempty"`
-	ThumbnailData string    `firestore:"thumbnailData,omitempty" json:"thumbnailData,omitempty"`
-	Width         int       `firestore:"width,omitempty" json:"width,omitempty"`
-	Height        int       `firestore:"height,omitempty" json:"height,omitempty"`
-	Tags          []string  `firestore:"tags,omitempty" json:"tags,omitempty"`
-	CreatedAt     time.Time `firestore:"createdAt" json:"createdAt"`
+	ID             string           `firestore:"-" json:"id"`
+	UserID         string           `firestore:"userId" json:"userId"`
+	ProjectID      string           `firestore:"projectId,omitempty" json:"projectId,omitempty"`
+	Name           string           `firestore:"name" json:"name"`
+	Description    string           `firestore:"description,omitempty" json:"description,omitempty"`
+	ImageData      string           `firestore:"imageData,omitempty" json:"imageData,omitempty"`
+	ThumbnailData  string           `firestore:"thumbnailData,omitempty" json:"thumbnailData,omitempty"`
+	Width          int              `firestore:"width,omitempty" json:"width,omitempty"`
+	Height         int              `firestore:"height,omitempty" json:"height,omitempty"`
+	Tags           []string         `firestore:"tags,omitempty" json:"tags,omitempty"`
+	CommentCount   int64            `firestore:"commentCount" json:"commentCount"`
+	ReactionCounts map[string]int64 `firestore:"reactionCounts,omitempty" json:"reactionCounts,omitempty"`
+	Hidden         bool             `firestore:"hidden" json:"hidden"`
+	CreatedAt      time.Time        `firestore:"createdAt" json:"createdAt"`
+}
+
+// GallerySummary is the compact projection of a GalleryItem returned by
+// list endpoints. Inline image data is replaced by a link to the thumbnail
+// endpoint; the full document is only returned by GET /api/gallery/{id}.
+type GallerySummary struct {
+	ID             string           `json:"id"`
+	ProjectID      string           `json:"projectId,omitempty"`
+	Name           string           `json:"name"`
+	Width          int              `json:"width,omitempty"`
+	Height         int              `json:"height,omitempty"`
+	Tags           []string         `json:"tags,omitempty"`
+	CommentCount   int64            `json:"commentCount"`
+	ReactionCounts map[string]int64 `json:"reactionCounts,omitempty"`
+	Hidden         bool             `json:"hidden"`
+	CreatedAt      time.Time        `json:"createdAt"`
+	ThumbnailURL   string           `json:"thumbnailUrl"`
+}
+
+// GallerySummaryFields is the GallerySummary projection for sparse fieldsets.
+var GallerySummaryFields = Projection{
+	"id":             nil,
+	"projectId":      {"projectId"},
+	"name":           {"name"},
+	"width":          {"width"},
+	"height":         {"height"},
+	"tags":           {"tags"},
+	"commentCount":   {"commentCount"},
+	"reactionCounts": {"reactionCounts"},
+	"hidden":         {"hidden"},
+	"createdAt":      {"createdAt"},
+	"thumbnailUrl":   nil,
+}
+
+// Summary returns the list projection of the gallery item.
+func (g *GalleryItem) Summary() *GallerySummary {
+	return &GallerySummary{
+		ID:             g.ID,
+		ProjectID:      g.ProjectID,
+		Name:           g.Name,
+		Width:          g.Width,
+		Height:         g.Height,
+		Tags:           g.Tags,
+		CommentCount:   g.CommentCount,
+		ReactionCounts: g.ReactionCounts,
+		Hidden:         g.Hidden,
+		CreatedAt:      g.CreatedAt,
+		ThumbnailURL:   "/api/v1/gallery/" + g.ID + "/thumbnail",
+	}
 }
 
 // Validate checks that the GalleryItem has required fields.
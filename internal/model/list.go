@@ -0,0 +1,216 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sort orders accepted by list endpoints.
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// DefaultSort is the sort key used when a list request names none.
+const DefaultSort = "createdAt"
+
+// ListOptions are the sort, filter and cursor options of a list request,
+// in API terms. ListSchema.Normalize validates them for a collection.
+type ListOptions struct {
+	Sort          string
+	Order         string
+	Tag           string
+	IsPublic      *bool
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// Cursor is the position the page starts after; nil for the first page.
+	Cursor *Cursor
+}
+
+// SortField is a sortable Firestore field.
+type SortField struct {
+	Field string
+	Time  bool // values are timestamps rather than strings
+}
+
+// ListSchema describes the sort keys and filters a collection's list
+// endpoint supports, in terms of its Firestore fields.
+type ListSchema struct {
+	// Collection is the Firestore collection the list reads.
+	Collection string
+	// Noun names the listed items in error messages.
+	Noun string
+	// Sorts maps API sort keys to Firestore fields.
+	Sorts map[string]SortField
+	// TagField is the array field ?tag= filters on, or "" if unsupported.
+	TagField string
+	// PublicField is the boolean field ?isPublic= filters on, or "" if
+	// unsupported.
+	PublicField string
+}
+
+// ProjectListSchema is the list schema of GET /api/projects.
+var ProjectListSchema = ListSchema{
+	Collection: "projects",
+	Noun:       "projects",
+	Sorts: map[string]SortField{
+		"createdAt": {Field: "createdAt", Time: true},
+		"updatedAt": {Field: "updatedAt", Time: true},
+		"title":     {Field: "title"},
+	},
+	TagField:    "tags",
+	PublicField: "isPublic",
+}
+
+// GalleryListSchema is the list schema of GET /api/gallery. Gallery items
+// are public by definition and have no update time.
+var GalleryListSchema = ListSchema{
+	Collection: "gallery",
+	Noun:       "gallery items",
+	Sorts: map[string]SortField{
+		"createdAt": {Field: "createdAt", Time: true},
+		"title":     {Field: "name"},
+	},
+	TagField: "tags",
+}
+
+// NFTListSchema is the list schema of GET /api/nfts. An NFT is public
+// while it is listed.
+var NFTListSchema = ListSchema{
+	Collection: "nfts",
+	Noun:       "NFTs",
+	Sorts: map[string]SortField{
+		"createdAt": {Field: "createdAt", Time: true},
+		"updatedAt": {Field: "updatedAt", Time: true},
+		"title":     {Field: "name"},
+	},
+	PublicField: "isListed",
+}
+
+// SortKeys returns the schema's API sort keys in sorted order.
+func (s ListSchema) SortKeys() []string {
+	keys := make([]string, 0, len(s.Sorts))
+	for k := range s.Sorts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Normalize fills in default sort options and validates opts against the
+// schema. Creation-time ranges need sort=createdAt, since Firestore orders
+// a range query by the range field first; a cursor must come from a page
+// with the same sort and filters.
+func (s ListSchema) Normalize(opts *ListOptions) error {
+	if opts.Sort == "" {
+		opts.Sort = DefaultSort
+	}
+	if opts.Order == "" {
+		opts.Order = SortDesc
+	}
+
+	if _, ok := s.Sorts[opts.Sort]; !ok {
+		return fmt.Errorf("invalid sort %q: %s can be sorted by %v", opts.Sort, s.Noun, s.SortKeys())
+	}
+	if opts.Order != SortAsc && opts.Order != SortDesc {
+		return fmt.Errorf("invalid order %q: must be asc or desc", opts.Order)
+	}
+	if opts.Tag != "" && s.TagField == "" {
+		return fmt.Errorf("invalid filter: %s cannot be filtered by tag", s.Noun)
+	}
+	if opts.IsPublic != nil && s.PublicField == "" {
+		return fmt.Errorf("invalid filter: %s cannot be filtered by isPublic", s.Noun)
+	}
+	if (!opts.CreatedAfter.IsZero() || !opts.CreatedBefore.IsZero()) && opts.Sort != "createdAt" {
+		return fmt.Errorf("invalid filter: createdAfter and createdBefore require sort=createdAt")
+	}
+	if !opts.CreatedAfter.IsZero() && !opts.CreatedBefore.IsZero() && !opts.CreatedAfter.Before(opts.CreatedBefore) {
+		return fmt.Errorf("invalid filter: createdAfter must be before createdBefore")
+	}
+
+	if c := opts.Cursor; c != nil {
+		if c.Sort != opts.Sort || c.Order != opts.Order {
+			return fmt.Errorf("invalid cursor: it belongs to a list with a different sort")
+		}
+		if c.Filter != opts.FilterKey() {
+			return fmt.Errorf("invalid cursor: it belongs to a list with different filters")
+		}
+		if s.Sorts[opts.Sort].Time {
+			if _, err := time.Parse(time.RFC3339Nano, c.Value); err != nil {
+				return fmt.Errorf("invalid cursor: malformed position")
+			}
+		}
+	}
+	return nil
+}
+
+// FilterKey returns a short fingerprint of the filters in opts, or "" when
+// there are none. Cursors carry it so a page cannot be continued under
+// different filters.
+func (opts *ListOptions) FilterKey() string {
+	if opts.Tag == "" && opts.IsPublic == nil && opts.CreatedAfter.IsZero() && opts.CreatedBefore.IsZero() {
+		return ""
+	}
+	public := ""
+	if opts.IsPublic != nil {
+		public = strconv.FormatBool(*opts.IsPublic)
+	}
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		opts.Tag,
+		public,
+		opts.CreatedAfter.UTC().Format(time.RFC3339Nano),
+		opts.CreatedBefore.UTC().Format(time.RFC3339Nano),
+	}, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Cursor is a position in a sorted list: the sort and filters it was issued
+// for, and the sort value and document ID of the last item on the previous
+// page. The ID breaks ties between items with equal sort values. Cursors
+// are serialized and signed by service.CursorCodec.
+type Cursor struct {
+	Sort   string `json:"s"`
+	Order  string `json:"o"`
+	Filter string `json:"f,omitempty"` // ListOptions.FilterKey
+	Value  string `json:"v"`           // strings as-is, timestamps as RFC 3339
+	ID     string `json:"id"`
+}
+
+// SortValue returns the cursor's sort value as Firestore compares it.
+func (c *Cursor) SortValue(f SortField) interface{} {
+	if f.Time {
+		t, _ := time.Parse(time.RFC3339Nano, c.Value)
+		return t
+	}
+	return c.Value
+}
+
+// Page is one page of a list endpoint's results.
+type Page struct {
+	Items      interface{} `json:"items"`
+	NextCursor string      `json:"nextCursor,omitempty"`
+	HasMore    bool        `json:"hasMore"`
+	// Total counts every item matching the filters, across all pages.
+	Total int64 `json:"total"`
+
+	// Next is the position of the following page, or nil on the last one.
+	// Handlers sign it into NextCursor.
+	Next *Cursor `json:"-"`
+}
+
+// FeedPage is one page of a feed. Feeds merge items from many users as they
+// are read, so unlike Page they carry no total.
+type FeedPage struct {
+	Items      []*GalleryItem `json:"items"`
+	NextCursor string         `json:"nextCursor,omitempty"`
+	HasMore    bool           `json:"hasMore"`
+
+	// Next is the position of the following page, or nil on the last one.
+	// Handlers sign it into NextCursor.
+	Next *Cursor `json:"-"`
+}
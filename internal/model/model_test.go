@@ -1,10 +1,14 @@
 package model
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // --- User tests ---
@@ -598,3 +602,384 @@ func TestNFT_Sanitize_StripsControlChars(t *testing.T) {
 	assert.Equal(t, "NFTName", n.Name)
 	assert.Equal(t, "NFTDesc", n.Description)
 }
+
+// --- PublicProfile tests ---
+
+func TestUser_ToPublicProfile_OmitsPrivateFields(t *testing.T) {
+	u := &User{
+		UID:            "uid1",
+		Email:          "secret@example.com",
+		Username:       "artist",
+		DisplayName:    "Artist",
+		HbarAddress:    "0.0.123",
+		FollowerCount:  4,
+		FollowingCount: 2,
+	}
+	p := u.ToPublicProfile()
+	assert.Equal(t, "uid1", p.UID)
+	assert.Equal(t, "artist", p.Username)
+	assert.Equal(t, int64(4), p.FollowerCount)
+	assert.Equal(t, int64(2), p.FollowingCount)
+	assert.False(t, p.IsFollowing)
+
+	b, err := json.Marshal(p)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "secret@example.com")
+	assert.NotContains(t, string(b), "0.0.123")
+}
+
+// --- Comment tests ---
+
+func TestComment_Validate_Valid(t *testing.T) {
+	c := &Comment{UserID: "uid1", Body: "Nice colours"}
+	assert.NoError(t, c.Validate())
+}
+
+func TestComment_Validate_EmptyBody(t *testing.T) {
+	c := &Comment{UserID: "uid1", Body: ""}
+	assert.ErrorContains(t, c.Validate(), "comment body is required")
+}
+
+func TestComment_Validate_BodyTooLong(t *testing.T) {
+	c := &Comment{UserID: "uid1", Body: strings.Repeat("é", MaxCommentLen+1)}
+	assert.ErrorContains(t, c.Validate(), "must be")
+}
+
+func TestComment_Validate_BodyAtLimitRunes(t *testing.T) {
+	c := &Comment{UserID: "uid1", Body: strings.Repeat("é", MaxCommentLen)}
+	assert.NoError(t, c.Validate())
+}
+
+func TestComment_Sanitize_TrimsAndStrips(t *testing.T) {
+	c := &Comment{Body: "  Love\x00 it  "}
+	c.Sanitize()
+	assert.Equal(t, "Love it", c.Body)
+}
+
+// --- Reaction tests ---
+
+func TestValidateReaction(t *testing.T) {
+	for key := range Reactions {
+		assert.NoError(t, ValidateReaction(key))
+	}
+	assert.ErrorContains(t, ValidateReaction("thumbs"), "invalid reaction")
+	assert.ErrorContains(t, ValidateReaction(""), "invalid reaction")
+}
+
+// --- Report tests ---
+
+func TestReport_Validate_Valid(t *testing.T) {
+	r := &Report{ReporterID: "uid1", TargetType: ReportTargetGallery, TargetID: "item1", Reason: "spam"}
+	assert.NoError(t, r.Validate())
+}
+
+func TestReport_Validate_CommentRequiresItemID(t *testing.T) {
+	r := &Report{ReporterID: "uid1", TargetType: ReportTargetComment, TargetID: "c1", Reason: "spam"}
+	assert.ErrorContains(t, r.Validate(), "itemId is required")
+	r.ItemID = "item1"
+	assert.NoError(t, r.Validate())
+}
+
+func TestReport_Validate_BadTargetType(t *testing.T) {
+	r := &Report{ReporterID: "uid1", TargetType: "project", TargetID: "p1", Reason: "spam"}
+	assert.ErrorContains(t, r.Validate(), "invalid targetType")
+}
+
+func TestReport_Validate_SlashInTargetID(t *testing.T) {
+	r := &Report{ReporterID: "uid1", TargetType: ReportTargetNFT, TargetID: "a/b", Reason: "spam"}
+	assert.ErrorContains(t, r.Validate(), "invalid target ID")
+}
+
+func TestReport_Validate_DetailsTooLong(t *testing.T) {
+	r := &Report{ReporterID: "uid1", TargetType: ReportTargetUser, TargetID: "u2", Reason: "other",
+		Details: strings.Repeat("x", MaxReportDetailsLen+1)}
+	assert.ErrorContains(t, r.Validate(), "must be")
+}
+
+func TestReport_Sanitize(t *testing.T) {
+	r := &Report{TargetType: " Gallery ", Reason: "SPAM", Details: " look\x00 "}
+	r.Sanitize()
+	assert.Equal(t, "gallery", r.TargetType)
+	assert.Equal(t, "spam", r.Reason)
+	assert.Equal(t, "look", r.Details)
+}
+
+func TestValidateReportResolution(t *testing.T) {
+	assert.NoError(t, ValidateReportResolution(ReportStatusActioned))
+	assert.NoError(t, ValidateReportResolution(ReportStatusDismissed))
+	assert.ErrorContains(t, ValidateReportResolution(ReportStatusOpen), "invalid status")
+}
+
+// --- AuditDiff tests ---
+
+func TestAuditDiff(t *testing.T) {
+	before := map[string]interface{}{"bio": "old", "location": "Paris", "useGravatar": false}
+	after := map[string]interface{}{"bio": "new", "location": "Paris", "useGravatar": true, "updatedAt": time.Now()}
+
+	changes := AuditDiff(before, after)
+	assert.Equal(t, map[string]AuditChange{
+		"bio":         {Before: "old", After: "new"},
+		"useGravatar": {Before: false, After: true},
+	}, changes)
+}
+
+func TestAuditDiff_NoChange(t *testing.T) {
+	assert.Nil(t, AuditDiff(map[string]interface{}{"bio": "same"}, map[string]interface{}{"bio": "same"}))
+}
+
+func TestAuditDiff_NewField(t *testing.T) {
+	changes := AuditDiff(map[string]interface{}{}, map[string]interface{}{"website": "https://x.dev"})
+	assert.Equal(t, AuditChange{Before: nil, After: "https://x.dev"}, changes["website"])
+}
+
+func TestUser_ToProfileMap_MatchesUpdateKeys(t *testing.T) {
+	s := "x"
+	b := true
+	update := &UserUpdate{
+		DisplayName: &s, Bio: &s, Location: &s, Website: &s, GithubURL: &s,
+		TwitterHandle: &s, BlueskyHandle: &s, InstagramHandle: &s, HbarAddress: &s, UseGravatar: &b,
+	}
+	profile := (&User{}).ToProfileMap()
+	for key := range update.ToUpdateMap() {
+		if key == "updatedAt" {
+			continue
+		}
+		assert.Contains(t, profile, key)
+	}
+}
+
+// --- APIToken tests ---
+
+func TestAPITokenCreate_SanitizeAndValidate(t *testing.T) {
+	c := &APITokenCreate{Name: "  nightly export\x00 ", Scopes: []string{" Gallery:Read", "gallery:read", ""}}
+	c.Sanitize()
+	assert.Equal(t, "nightly export", c.Name)
+	assert.Equal(t, []string{"gallery:read"}, c.Scopes)
+	assert.NoError(t, c.Validate())
+}
+
+func TestAPITokenCreate_Validate_Errors(t *testing.T) {
+	days := MaxAPITokenExpiryDays + 1
+	tests := []struct {
+		name string
+		c    APITokenCreate
+		want string
+	}{
+		{"missing name", APITokenCreate{Scopes: []string{ScopeGalleryRead}}, "name is required"},
+		{"long name", APITokenCreate{Name: strings.Repeat("a", MaxAPITokenNameLen+1), Scopes: []string{ScopeGalleryRead}}, "name must be"},
+		{"no scopes", APITokenCreate{Name: "t"}, "at least one scope"},
+		{"unknown scope", APITokenCreate{Name: "t", Scopes: []string{"users:write"}}, "invalid scope"},
+		{"expiry too long", APITokenCreate{Name: "t", Scopes: []string{ScopeGalleryRead}, ExpiresInDays: &days}, "expiresInDays"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorContains(t, tt.c.Validate(), tt.want)
+		})
+	}
+}
+
+func TestAPIToken_Expired(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Second)
+	future := now.Add(time.Hour)
+
+	assert.False(t, (&APIToken{}).Expired(now))
+	assert.True(t, (&APIToken{ExpiresAt: &past}).Expired(now))
+	assert.False(t, (&APIToken{ExpiresAt: &future}).Expired(now))
+}
+
+// --- Webhook tests ---
+
+func TestWebhookCreate_SanitizeAndValidate(t *testing.T) {
+	c := &WebhookCreate{URL: " https://example.com/hook ", Events: []string{" Project.Created", "project.created", ""}}
+	c.Sanitize()
+	assert.Equal(t, "https://example.com/hook", c.URL)
+	assert.Equal(t, []string{"project.created"}, c.Events)
+	assert.NoError(t, c.Validate())
+}
+
+func TestWebhookCreate_Validate_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		c    WebhookCreate
+		want string
+	}{
+		{"missing url", WebhookCreate{Events: []string{WebhookEventNFTCreated}}, "url is required"},
+		{"long url", WebhookCreate{URL: "https://example.com/" + strings.Repeat("a", MaxWebhookURLLen), Events: []string{WebhookEventNFTCreated}}, "url must be"},
+		{"bad scheme", WebhookCreate{URL: "ftp://example.com", Events: []string{WebhookEventNFTCreated}}, "invalid url"},
+		{"no events", WebhookCreate{URL: "https://example.com"}, "at least one event"},
+		{"unknown event", WebhookCreate{URL: "https://example.com", Events: []string{"user.deleted"}}, "invalid event"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorContains(t, tt.c.Validate(), tt.want)
+		})
+	}
+}
+
+func TestWebhook_Subscribed(t *testing.T) {
+	w := &Webhook{Events: []string{WebhookEventProjectCreated, WebhookEventGalleryShared}}
+	assert.True(t, w.Subscribed(WebhookEventGalleryShared))
+	assert.False(t, w.Subscribed(WebhookEventNFTCreated))
+	assert.False(t, w.Subscribed(WebhookEventPing))
+}
+
+func TestUploadInit_Validate(t *testing.T) {
+	hash := strings.Repeat("a", 64)
+	assert.NoError(t, (&UploadInit{Size: 1024, ContentHash: hash}).Validate())
+
+	tests := []struct {
+		name string
+		u    UploadInit
+		want string
+	}{
+		{"missing size", UploadInit{ContentHash: hash}, "size is required"},
+		{"too large", UploadInit{Size: MaxResumableUploadSize + 1, ContentHash: hash}, "size must be"},
+		{"missing hash", UploadInit{Size: 1024}, "contentHash is required"},
+		{"bad hash", UploadInit{Size: 1024, ContentHash: "ABC"}, "contentHash must be"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorContains(t, tt.u.Validate(), tt.want)
+		})
+	}
+}
+
+func TestUploadSession_Complete(t *testing.T) {
+	s := &UploadSession{Size: 10, Offset: 4}
+	assert.False(t, s.Complete())
+	s.Offset = 10
+	assert.True(t, s.Complete())
+}
+
+func TestDecodeThumbnailData(t *testing.T) {
+	contentType, data, err := DecodeThumbnailData("data:image/jpeg;base64," + base64.StdEncoding.EncodeToString([]byte("jpeg")))
+	require.NoError(t, err)
+	assert.Equal(t, "image/jpeg", contentType)
+	assert.Equal(t, []byte("jpeg"), data)
+
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"not a data uri", "https://example.com/a.png", "must be a data:image/ URI"},
+		{"not base64", "data:image/png,raw", "must be a base64 data URI"},
+		{"svg", "data:image/svg+xml;base64,PHN2Zz4=", "unsupported image type"},
+		{"bad base64", "data:image/png;base64,!!!", "not valid base64"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := DecodeThumbnailData(tt.data)
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestProjection_ParseFields(t *testing.T) {
+	set, err := ProjectSummaryFields.ParseFields("id, title,updatedAt,title,thumbnailUrl")
+	require.NoError(t, err)
+	assert.Equal(t, FieldSet{"id", "title", "updatedAt", "thumbnailUrl"}, set)
+
+	set, err = ProjectSummaryFields.ParseFields("")
+	require.NoError(t, err)
+	assert.Nil(t, set)
+
+	_, err = ProjectSummaryFields.ParseFields("id,thumbnailData")
+	assert.ErrorContains(t, err, `invalid fields: unknown field "thumbnailData"`)
+
+	_, err = ProjectSummaryFields.ParseFields(" , ")
+	assert.ErrorContains(t, err, "invalid fields")
+}
+
+func TestProjection_Paths(t *testing.T) {
+	assert.Equal(t, []string{"title", "updatedAt"}, ProjectSummaryFields.Paths(FieldSet{"id", "updatedAt", "title", "thumbnailUrl"}))
+	assert.Empty(t, ProjectSummaryFields.Paths(FieldSet{"id"}))
+
+	all := ProjectSummaryFields.Paths(nil)
+	assert.Contains(t, all, "title")
+	assert.NotContains(t, all, "thumbnailData")
+	assert.NotContains(t, GallerySummaryFields.Paths(nil), "imageData")
+	assert.NotContains(t, NFTSummaryFields.Paths(nil), "imageData")
+}
+
+func TestProject_Summary(t *testing.T) {
+	p := &Project{ID: "p1", Title: "Art", ThumbnailData: "data:image/png;base64,AAAA", IsPublic: true}
+	s := p.Summary()
+	assert.Equal(t, "p1", s.ID)
+	assert.Equal(t, "Art", s.Title)
+	assert.True(t, s.IsPublic)
+	assert.Equal(t, "/api/v1/projects/p1/thumbnail", s.ThumbnailURL)
+
+	assert.Equal(t, "/api/v1/gallery/g1/thumbnail", (&GalleryItem{ID: "g1"}).Summary().ThumbnailURL)
+	assert.Equal(t, "/api/v1/nfts/n1/thumbnail", (&NFT{ID: "n1"}).Summary().ThumbnailURL)
+}
+
+func TestListSchema_Normalize(t *testing.T) {
+	opts := &ListOptions{}
+	require.NoError(t, ProjectListSchema.Normalize(opts))
+	assert.Equal(t, "createdAt", opts.Sort)
+	assert.Equal(t, SortDesc, opts.Order)
+
+	yes := true
+	jan := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		schema ListSchema
+		opts   ListOptions
+		want   string
+	}{
+		{"unknown sort", ProjectListSchema, ListOptions{Sort: "width"}, "invalid sort"},
+		{"bad order", ProjectListSchema, ListOptions{Order: "up"}, "invalid order"},
+		{"gallery has no updatedAt", GalleryListSchema, ListOptions{Sort: "updatedAt"}, "invalid sort"},
+		{"gallery has no isPublic", GalleryListSchema, ListOptions{IsPublic: &yes}, "cannot be filtered by isPublic"},
+		{"NFTs have no tags", NFTListSchema, ListOptions{Tag: "x"}, "cannot be filtered by tag"},
+		{"range with other sort", ProjectListSchema, ListOptions{Sort: "title", CreatedAfter: jan}, "require sort=createdAt"},
+		{"empty range", ProjectListSchema, ListOptions{CreatedAfter: jan, CreatedBefore: jan}, "must be before"},
+		{"cursor for other order", ProjectListSchema, ListOptions{Order: SortAsc, Cursor: &Cursor{Sort: "createdAt", Order: SortDesc, ID: "a"}}, "invalid cursor"},
+		{"cursor with bad time", ProjectListSchema, ListOptions{Cursor: &Cursor{Sort: "createdAt", Order: SortDesc, Value: "soon", ID: "a"}}, "invalid cursor"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			assert.ErrorContains(t, tt.schema.Normalize(&opts), tt.want)
+		})
+	}
+}
+
+func TestCursor_SortValue(t *testing.T) {
+	c := &Cursor{Sort: "createdAt", Order: SortDesc, Value: "2025-01-01T00:00:00.5Z", ID: "doc1"}
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 5e8, time.UTC), c.SortValue(ProjectListSchema.Sorts["createdAt"]))
+	assert.Equal(t, "2025-01-01T00:00:00.5Z", c.SortValue(ProjectListSchema.Sorts["title"]))
+}
+
+func TestListOptions_FilterKey(t *testing.T) {
+	public, private := true, false
+	assert.Empty(t, (&ListOptions{Sort: "title", Order: SortAsc}).FilterKey(), "sort alone is not a filter")
+
+	keys := map[string]bool{}
+	for _, opts := range []*ListOptions{
+		{Tag: "pixel"},
+		{Tag: "retro"},
+		{IsPublic: &public},
+		{IsPublic: &private},
+		{Tag: "pixel", IsPublic: &public},
+		{CreatedAfter: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{CreatedBefore: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		k := opts.FilterKey()
+		assert.Len(t, k, 16)
+		assert.False(t, keys[k], "filter keys are distinct")
+		keys[k] = true
+	}
+
+	// Equal instants in different zones are the same filter
+	a := &ListOptions{CreatedAfter: time.Date(2025, 1, 1, 1, 0, 0, 0, time.FixedZone("CET", 3600))}
+	b := &ListOptions{CreatedAfter: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	assert.Equal(t, a.FilterKey(), b.FilterKey())
+
+	// A cursor is rejected under filters other than its own
+	c := &Cursor{Sort: DefaultSort, Order: SortDesc, Filter: (&ListOptions{Tag: "pixel"}).FilterKey(), Value: "2025-01-01T00:00:00Z", ID: "doc1"}
+	assert.NoError(t, ProjectListSchema.Normalize(&ListOptions{Tag: "pixel", Cursor: c}))
+	assert.ErrorContains(t, ProjectListSchema.Normalize(&ListOptions{Tag: "retro", Cursor: c}), "different filters")
+}
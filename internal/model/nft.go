@@ -18,6 +18,7 @@ type NFT struct {
 	Metadata      string  `firestore:"metadata,omitempty" json:"metadata,omitempty"`
 	Price         float64 `firestore:"price,omitempty" json:"price,omitempty"`
 	IsListed      bool    `firestore:"isListed" json:"isListed"`
+	Hidden        bool    `firestore:"hidden" json:"hidden"`
 	// Hiero network fields
 	TokenID       string `firestore:"tokenId,omitempty" json:"tokenId,omitempty"`
 	SerialNumber  int64  `firestore:"serialNumber,omitempty" json:"serialNumber,omitempty"`
@@ -27,6 +28,55 @@ type NFT struct {
 	UpdatedAt time.Time `firestore:"updatedAt" json:"updatedAt"`
 }
 
+// NFTSummary is the compact projection of an NFT returned by list
+// endpoints. Inline image data is replaced by a link to the thumbnail
+// endpoint; the full document is only returned by GET /api/nfts/{id}.
+type NFTSummary struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	ImageURL     string    `json:"imageUrl,omitempty"`
+	Price        float64   `json:"price,omitempty"`
+	IsListed     bool      `json:"isListed"`
+	Hidden       bool      `json:"hidden"`
+	TokenID      string    `json:"tokenId,omitempty"`
+	SerialNumber int64     `json:"serialNumber,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+	ThumbnailURL string    `json:"thumbnailUrl"`
+}
+
+// NFTSummaryFields is the NFTSummary projection for sparse fieldsets.
+var NFTSummaryFields = Projection{
+	"id":           nil,
+	"name":         {"name"},
+	"imageUrl":     {"imageUrl"},
+	"price":        {"price"},
+	"isListed":     {"isListed"},
+	"hidden":       {"hidden"},
+	"tokenId":      {"tokenId"},
+	"serialNumber": {"serialNumber"},
+	"createdAt":    {"createdAt"},
+	"updatedAt":    {"updatedAt"},
+	"thumbnailUrl": nil,
+}
+
+// Summary returns the list projection of the NFT.
+func (n *NFT) Summary() *NFTSummary {
+	return &NFTSummary{
+		ID:           n.ID,
+		Name:         n.Name,
+		ImageURL:     n.ImageURL,
+		Price:        n.Price,
+		IsListed:     n.IsListed,
+		Hidden:       n.Hidden,
+		TokenID:      n.TokenID,
+		SerialNumber: n.SerialNumber,
+		CreatedAt:    n.CreatedAt,
+		UpdatedAt:    n.UpdatedAt,
+		ThumbnailURL: "/api/v1/nfts/" + n.ID + "/thumbnail",
+	}
+}
+
 // Validate checks that the NFT has required fields.
 func (n *NFT) Validate() error {
 	if n.UserID == "" {
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/base64"
 	"fmt"
 	"regexp"
 	"strings"
@@ -39,6 +40,52 @@ type ProjectUpdate struct {
 	Tags     []string `firestore:"tags,omitempty" json:"tags,omitempty"`
 }
 
+// ProjectSummary is the compact projection of a Project returned by list
+// endpoints. The inline thumbnail is replaced by a link to the thumbnail
+// endpoint; the full document is only returned by GET /api/projects/{id}.
+type ProjectSummary struct {
+	ID           string    `json:"id"`
+	Title        string    `json:"title"`
+	ContentHash  string    `json:"contentHash"`
+	Width        int       `json:"width,omitempty"`
+	Height       int       `json:"height,omitempty"`
+	IsPublic     bool      `json:"isPublic"`
+	Tags         []string  `json:"tags,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+	ThumbnailURL string    `json:"thumbnailUrl"`
+}
+
+// ProjectSummaryFields is the ProjectSummary projection for sparse fieldsets.
+var ProjectSummaryFields = Projection{
+	"id":           nil,
+	"title":        {"title"},
+	"contentHash":  {"contentHash"},
+	"width":        {"width"},
+	"height":       {"height"},
+	"isPublic":     {"isPublic"},
+	"tags":         {"tags"},
+	"createdAt":    {"createdAt"},
+	"updatedAt":    {"updatedAt"},
+	"thumbnailUrl": nil,
+}
+
+// Summary returns the list projection of the project.
+func (p *Project) Summary() *ProjectSummary {
+	return &ProjectSummary{
+		ID:           p.ID,
+		Title:        p.Title,
+		ContentHash:  p.ContentHash,
+		Width:        p.Width,
+		Height:       p.Height,
+		IsPublic:     p.IsPublic,
+		Tags:         p.Tags,
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
+		ThumbnailURL: "/api/v1/projects/" + p.ID + "/thumbnail",
+	}
+}
+
 // Validate checks that the Project has required fields and valid values.
 func (p *Project) Validate() error {
 	if p.UserID == "" {
@@ -113,6 +160,36 @@ func ValidateThumbnailData(data string) error {
 	return nil
 }
 
+// thumbnailContentTypes are the image types a thumbnail may be served as.
+// SVG is excluded because it can carry script.
+var thumbnailContentTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/webp": true,
+	"image/gif":  true,
+}
+
+// DecodeThumbnailData returns the content type and bytes of a base64
+// thumbnail data URL.
+func DecodeThumbnailData(data string) (string, []byte, error) {
+	if err := ValidateThumbnailData(data); err != nil {
+		return "", nil, err
+	}
+	header, payload, ok := strings.Cut(strings.TrimPrefix(data, "data:"), ",")
+	contentType, isBase64 := strings.CutSuffix(header, ";base64")
+	if !ok || !isBase64 {
+		return "", nil, fmt.Errorf("thumbnailData must be a base64 data URI")
+	}
+	if !thumbnailContentTypes[contentType] {
+		return "", nil, fmt.Errorf("thumbnailData has an unsupported image type %q", contentType)
+	}
+	b, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return "", nil, fmt.Errorf("thumbnailData is not valid base64")
+	}
+	return contentType, b, nil
+}
+
 // ToUpdateMap converts a ProjectUpdate to a map for Firestore partial updates.
 func (p *ProjectUpdate) ToUpdateMap() map[string]interface{} {
 	m := make(map[string]interface{})
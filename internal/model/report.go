@@ -0,0 +1,100 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Report target types.
+const (
+	ReportTargetGallery = "gallery"
+	ReportTargetComment = "comment"
+	ReportTargetUser    = "user"
+	ReportTargetNFT     = "nft"
+)
+
+// Report statuses. Reports start open and are closed by an admin as either
+// actioned (content was hidden, removed or the user suspended) or dismissed.
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusActioned  = "actioned"
+	ReportStatusDismissed = "dismissed"
+)
+
+// MaxReportDetailsLen is the maximum length of a report's free-text details.
+const MaxReportDetailsLen = 1000
+
+// ReportReasons is the allow-list of reasons a user may give when reporting.
+var ReportReasons = map[string]bool{
+	"spam":       true,
+	"harassment": true,
+	"nudity":     true,
+	"violence":   true,
+	"copyright":  true,
+	"other":      true,
+}
+
+// Report represents an abuse report in the moderation queue, stored in the
+// top-level reports collection.
+type Report struct {
+	ID         string    `firestore:"-" json:"id"`
+	ReporterID string    `firestore:"reporterId" json:"reporterId"`
+	TargetType string    `firestore:"targetType" json:"targetType"`
+	TargetID   string    `firestore:"targetId" json:"targetId"`
+	ItemID     string    `firestore:"itemId,omitempty" json:"itemId,omitempty"` // gallery item a reported comment belongs to
+	Reason     string    `firestore:"reason" json:"reason"`
+	Details    string    `firestore:"details,omitempty" json:"details,omitempty"`
+	Status     string    `firestore:"status" json:"status"`
+	ResolvedBy string    `firestore:"resolvedBy,omitempty" json:"resolvedBy,omitempty"`
+	Note       string    `firestore:"note,omitempty" json:"note,omitempty"`
+	CreatedAt  time.Time `firestore:"createdAt" json:"createdAt"`
+	UpdatedAt  time.Time `firestore:"updatedAt" json:"updatedAt"`
+}
+
+// Sanitize cleans report input.
+func (r *Report) Sanitize() {
+	r.TargetType = strings.TrimSpace(strings.ToLower(r.TargetType))
+	r.TargetID = strings.TrimSpace(r.TargetID)
+	r.ItemID = strings.TrimSpace(r.ItemID)
+	r.Reason = strings.TrimSpace(strings.ToLower(r.Reason))
+	r.Details = StripControlChars(strings.TrimSpace(r.Details))
+}
+
+// Validate checks that the Report has required fields and valid values.
+func (r *Report) Validate() error {
+	if r.ReporterID == "" {
+		return fmt.Errorf("reporterId is required")
+	}
+	switch r.TargetType {
+	case ReportTargetGallery, ReportTargetUser, ReportTargetNFT:
+	case ReportTargetComment:
+		if r.ItemID == "" {
+			return fmt.Errorf("itemId is required when reporting a comment")
+		}
+	default:
+		return fmt.Errorf("invalid targetType %q", r.TargetType)
+	}
+	if r.TargetID == "" {
+		return fmt.Errorf("targetId is required")
+	}
+	if strings.ContainsRune(r.TargetID, '/') || strings.ContainsRune(r.ItemID, '/') {
+		return fmt.Errorf("invalid target ID")
+	}
+	if !ReportReasons[r.Reason] {
+		return fmt.Errorf("invalid reason %q", r.Reason)
+	}
+	if utf8.RuneCountInString(r.Details) > MaxReportDetailsLen {
+		return fmt.Errorf("details must be %d characters or less", MaxReportDetailsLen)
+	}
+	return nil
+}
+
+// ValidateReportResolution checks that status is a valid closing status.
+func ValidateReportResolution(status string) error {
+	if status != ReportStatusActioned && status != ReportStatusDismissed {
+		return fmt.Errorf("invalid status %q: must be %q or %q", status, ReportStatusActioned, ReportStatusDismissed)
+	}
+	return nil
+}
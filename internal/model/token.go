@@ -0,0 +1,109 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// API token scopes. A personal access token can only reach routes that
+// require one of its scopes.
+const (
+	ScopeProjectsRead  = "projects:read"
+	ScopeProjectsWrite = "projects:write"
+	ScopeGalleryRead   = "gallery:read"
+	ScopeGalleryWrite  = "gallery:write"
+)
+
+// APITokenScopes is the allow-list of scopes a token may be granted.
+var APITokenScopes = map[string]bool{
+	ScopeProjectsRead:  true,
+	ScopeProjectsWrite: true,
+	ScopeGalleryRead:   true,
+	ScopeGalleryWrite:  true,
+}
+
+// APITokenPrefix marks a bearer credential as a PaintBar personal access
+// token rather than a Firebase ID token.
+const APITokenPrefix = "pbt_"
+
+// API token limits.
+const (
+	MaxAPITokenNameLen    = 100
+	MaxAPITokenExpiryDays = 365
+	MaxAPITokensPerUser   = 25
+)
+
+// APIToken is a personal access token stored in the top-level apiTokens
+// collection. Only the SHA-256 hash of the secret is stored; Prefix keeps the
+// first characters so users can tell their tokens apart.
+type APIToken struct {
+	ID         string     `firestore:"-" json:"id"`
+	UID        string     `firestore:"uid" json:"-"`
+	Name       string     `firestore:"name" json:"name"`
+	Prefix     string     `firestore:"prefix" json:"prefix"`
+	Hash       string     `firestore:"hash" json:"-"`
+	Scopes     []string   `firestore:"scopes" json:"scopes"`
+	ExpiresAt  *time.Time `firestore:"expiresAt,omitempty" json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `firestore:"lastUsedAt,omitempty" json:"lastUsedAt,omitempty"`
+	CreatedAt  time.Time  `firestore:"createdAt" json:"createdAt"`
+}
+
+// Expired reports whether the token has an expiry that has passed.
+func (t *APIToken) Expired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// APITokenCreate is the request body for creating a personal access token.
+// ExpiresInDays is optional; nil means the token never expires.
+type APITokenCreate struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays *int     `json:"expiresInDays,omitempty"`
+}
+
+// Sanitize cleans token creation input.
+func (c *APITokenCreate) Sanitize() {
+	c.Name = StripControlChars(strings.TrimSpace(c.Name))
+	seen := make(map[string]bool, len(c.Scopes))
+	scopes := c.Scopes[:0]
+	for _, s := range c.Scopes {
+		s = strings.TrimSpace(strings.ToLower(s))
+		if s != "" && !seen[s] {
+			seen[s] = true
+			scopes = append(scopes, s)
+		}
+	}
+	c.Scopes = scopes
+}
+
+// Validate checks that the request has a name, known scopes and a sane
+// expiry.
+func (c *APITokenCreate) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if utf8.RuneCountInString(c.Name) > MaxAPITokenNameLen {
+		return fmt.Errorf("name must be %d characters or less", MaxAPITokenNameLen)
+	}
+	if len(c.Scopes) == 0 {
+		return fmt.Errorf("at least one scope is required")
+	}
+	for _, s := range c.Scopes {
+		if !APITokenScopes[s] {
+			return fmt.Errorf("invalid scope %q", s)
+		}
+	}
+	if c.ExpiresInDays != nil && (*c.ExpiresInDays < 1 || *c.ExpiresInDays > MaxAPITokenExpiryDays) {
+		return fmt.Errorf("expiresInDays must be between 1 and %d", MaxAPITokenExpiryDays)
+	}
+	return nil
+}
+
+// APITokenCreated is returned once, when a token is created. Token is the
+// only time the plaintext secret is ever shown.
+type APITokenCreated struct {
+	*APIToken
+	Token string `json:"token"`
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Resumable upload limits. Chunks other than the last must be at least
+// MinUploadChunkSize so a session cannot be split into millions of objects.
+const (
+	MaxResumableUploadSize = 50 << 20 // 50 MB
+	MinUploadChunkSize     = 256 << 10
+	MaxUploadChunkSize     = 8 << 20
+	UploadSessionTTL       = 24 * time.Hour
+)
+
+// Upload session states.
+const (
+	UploadStatusOpen       = "open"
+	UploadStatusFinalizing = "finalizing"
+)
+
+// UploadSession tracks a resumable project blob upload in the top-level
+// uploadSessions collection. Received chunks are stored as separate Storage
+// objects until the session is finalized.
+type UploadSession struct {
+	ID          string        `firestore:"-" json:"uploadId"`
+	UID         string        `firestore:"uid" json:"-"`
+	ProjectID   string        `firestore:"projectId" json:"projectId"`
+	Size        int64         `firestore:"size" json:"size"`
+	ContentHash string        `firestore:"contentHash" json:"contentHash"`
+	Offset      int64         `firestore:"offset" json:"offset"`
+	Chunks      []UploadChunk `firestore:"chunks" json:"-"`
+	Status      string        `firestore:"status" json:"status"`
+	JobID       string        `firestore:"jobId,omitempty" json:"jobId,omitempty"`
+	ExpiresAt   time.Time     `firestore:"expiresAt" json:"expiresAt"`
+	CreatedAt   time.Time     `firestore:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time     `firestore:"updatedAt" json:"updatedAt"`
+}
+
+// Complete reports whether every byte has been received.
+func (s *UploadSession) Complete() bool {
+	return s.Offset == s.Size
+}
+
+// UploadChunk is one received byte range and the Storage object holding it.
+type UploadChunk struct {
+	Offset int64  `firestore:"offset"`
+	Size   int64  `firestore:"size"`
+	Object string `firestore:"object"`
+}
+
+// UploadInit is the request body for starting a resumable upload.
+type UploadInit struct {
+	Size        int64  `json:"size"`
+	ContentHash string `json:"contentHash"`
+}
+
+// Validate checks the declared size and hash.
+func (u *UploadInit) Validate() error {
+	if u.Size <= 0 {
+		return fmt.Errorf("size is required")
+	}
+	if u.Size > MaxResumableUploadSize {
+		return fmt.Errorf("size must be %d bytes or less", MaxResumableUploadSize)
+	}
+	if u.ContentHash == "" {
+		return fmt.Errorf("contentHash is required")
+	}
+	if !contentHashRegex.MatchString(u.ContentHash) {
+		return fmt.Errorf("contentHash must be a 64-character lowercase hex string")
+	}
+	return nil
+}
@@ -11,7 +11,9 @@ import (
 // UsernameRegex validates username format: 3-30 chars, lowercase alphanumeric, underscores, hyphens.
 var UsernameRegex = regexp.MustCompile(`^[a-z0-9_-]{3,30}$`)
 
-// User represents a user profile stored in Firestore.
+// User represents a user profile stored in Firestore. The follow and
+// document counters are maintained by the repositories that write the
+// counted documents.
 type User struct {
 	UID             string    `firestore:"uid" json:"uid"`
 	Email           string    `firestore:"email" json:"email"`
@@ -26,10 +28,51 @@ type User struct {
 	InstagramHandle string    `firestore:"instagramHandle,omitempty" json:"instagramHandle,omitempty"`
 	HbarAddress     string    `firestore:"hbarAddress,omitempty" json:"hbarAddress,omitempty"`
 	UseGravatar     bool      `firestore:"useGravatar" json:"useGravatar"`
+	FollowerCount   int64     `firestore:"followerCount" json:"followerCount"`
+	FollowingCount  int64     `firestore:"followingCount" json:"followingCount"`
+	ProjectCount    int64     `firestore:"projectCount" json:"projectCount"`
+	GalleryCount    int64     `firestore:"galleryCount" json:"galleryCount"`
+	NFTCount        int64     `firestore:"nftCount" json:"nftCount"`
+	Suspended       bool      `firestore:"suspended" json:"suspended"`
 	CreatedAt       time.Time `firestore:"createdAt" json:"createdAt"`
 	UpdatedAt       time.Time `firestore:"updatedAt" json:"updatedAt"`
 }
 
+// UserStats are a user's counts, read from the counters on their user
+// document.
+type UserStats struct {
+	Projects     int64 `json:"projects"`
+	GalleryItems int64 `json:"galleryItems"`
+	NFTs         int64 `json:"nfts"`
+	Followers    int64 `json:"followers"`
+	Following    int64 `json:"following"`
+}
+
+// Stats returns the user's counters.
+func (u *User) Stats() *UserStats {
+	return &UserStats{
+		Projects:     u.ProjectCount,
+		GalleryItems: u.GalleryCount,
+		NFTs:         u.NFTCount,
+		Followers:    u.FollowerCount,
+		Following:    u.FollowingCount,
+	}
+}
+
+// CounterRepair is the outcome of recounting one user's counters: the
+// stored values and the values recomputed from the counted documents.
+type CounterRepair struct {
+	UID    string     `json:"uid"`
+	Stored *UserStats `json:"stored"`
+	Actual *UserStats `json:"actual"`
+}
+
+// Drifted reports whether the stored counters differed from the actual
+// counts.
+func (c *CounterRepair) Drifted() bool {
+	return *c.Stored != *c.Actual
+}
+
 // UserUpdate represents a partial update to a user profile.
 // Pointer fields allow distinguishing between "not provided" (nil) and "set to empty" ("").
 type UserUpdate struct {
@@ -129,6 +172,23 @@ func (u *UserUpdate) ToUpdateMap() map[string]interface{} {
 	return m
 }
 
+// ToProfileMap returns the user-editable profile fields keyed like
+// UserUpdate.ToUpdateMap, for diffing an update against the current state.
+func (u *User) ToProfileMap() map[string]interface{} {
+	return map[string]interface{}{
+		"displayName":     u.DisplayName,
+		"bio":             u.Bio,
+		"location":        u.Location,
+		"website":         u.Website,
+		"githubUrl":       u.GithubURL,
+		"twitterHandle":   u.TwitterHandle,
+		"blueskyHandle":   u.BlueskyHandle,
+		"instagramHandle": u.InstagramHandle,
+		"hbarAddress":     u.HbarAddress,
+		"useGravatar":     u.UseGravatar,
+	}
+}
+
 // normalizeHandle strips leading @ from social media handles.
 func normalizeHandle(handle string) string {
 	handle = strings.TrimSpace(handle)
@@ -0,0 +1,140 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Webhook event types. Events are published from the service layer after the
+// change has been persisted.
+const (
+	WebhookEventProjectCreated = "project.created"
+	WebhookEventProjectUpdated = "project.updated"
+	WebhookEventProjectDeleted = "project.deleted"
+	WebhookEventGalleryShared  = "gallery.shared"
+	WebhookEventNFTCreated     = "nft.created"
+
+	// WebhookEventNFTMinted and WebhookEventNFTSold are reserved for Hiero
+	// tokenization. They may be subscribed to but are not emitted yet: NFTs
+	// are off-chain records with no mint or sale flow.
+	WebhookEventNFTMinted = "nft.minted"
+	WebhookEventNFTSold   = "nft.sold"
+
+	// WebhookEventPing is sent by the test-ping endpoint only; it cannot be
+	// subscribed to.
+	WebhookEventPing = "ping"
+)
+
+// WebhookEvents is the allow-list of events a webhook may subscribe to.
+var WebhookEvents = map[string]bool{
+	WebhookEventProjectCreated: true,
+	WebhookEventProjectUpdated: true,
+	WebhookEventProjectDeleted: true,
+	WebhookEventGalleryShared:  true,
+	WebhookEventNFTCreated:     true,
+	WebhookEventNFTMinted:      true,
+	WebhookEventNFTSold:        true,
+}
+
+// Webhook limits.
+const (
+	MaxWebhookURLLen    = 2048
+	MaxWebhooksPerUser  = 10
+	WebhookSecretPrefix = "whsec_"
+)
+
+// Webhook is a user-registered endpoint that receives signed event
+// deliveries, stored in the top-level webhooks collection. The secret is
+// kept in plaintext because the server needs it to sign every delivery; it is
+// only returned to the user when the webhook is created.
+type Webhook struct {
+	ID        string    `firestore:"-" json:"id"`
+	UID       string    `firestore:"uid" json:"-"`
+	URL       string    `firestore:"url" json:"url"`
+	Events    []string  `firestore:"events" json:"events"`
+	Secret    string    `firestore:"secret" json:"-"`
+	CreatedAt time.Time `firestore:"createdAt" json:"createdAt"`
+}
+
+// Subscribed reports whether the webhook wants the given event.
+func (w *Webhook) Subscribed(event string) bool {
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookCreate is the request body for registering a webhook.
+type WebhookCreate struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
+// Sanitize cleans webhook input.
+func (c *WebhookCreate) Sanitize() {
+	c.URL = strings.TrimSpace(c.URL)
+	seen := make(map[string]bool, len(c.Events))
+	events := c.Events[:0]
+	for _, e := range c.Events {
+		e = strings.TrimSpace(strings.ToLower(e))
+		if e != "" && !seen[e] {
+			seen[e] = true
+			events = append(events, e)
+		}
+	}
+	c.Events = events
+}
+
+// Validate checks the URL and event filter.
+func (c *WebhookCreate) Validate() error {
+	if c.URL == "" {
+		return fmt.Errorf("url is required")
+	}
+	if len(c.URL) > MaxWebhookURLLen {
+		return fmt.Errorf("url must be %d characters or less", MaxWebhookURLLen)
+	}
+	if err := validateURL(c.URL); err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	if len(c.Events) == 0 {
+		return fmt.Errorf("at least one event is required")
+	}
+	for _, e := range c.Events {
+		if !WebhookEvents[e] {
+			return fmt.Errorf("invalid event %q", e)
+		}
+	}
+	return nil
+}
+
+// WebhookCreated is returned once, when a webhook is registered, and is the
+// only response that includes the signing secret.
+type WebhookCreated struct {
+	*Webhook
+	Secret string `json:"secret"`
+}
+
+// WebhookPayload is the JSON body POSTed to a webhook endpoint.
+type WebhookPayload struct {
+	ID        string      `json:"id"` // delivery ID, stable across retries
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"createdAt"`
+	Data      interface{} `json:"data"`
+}
+
+// WebhookDelivery records one delivery attempt in the
+// webhooks/{webhookId}/deliveries subcollection.
+type WebhookDelivery struct {
+	ID         string    `firestore:"-" json:"id"`
+	DeliveryID string    `firestore:"deliveryId" json:"deliveryId"`
+	Event      string    `firestore:"event" json:"event"`
+	Attempt    int       `firestore:"attempt" json:"attempt"`
+	StatusCode int       `firestore:"statusCode,omitempty" json:"statusCode,omitempty"`
+	Error      string    `firestore:"error,omitempty" json:"error,omitempty"`
+	Success    bool      `firestore:"success" json:"success"`
+	DurationMs int64     `firestore:"durationMs" json:"durationMs"`
+	CreatedAt  time.Time `firestore:"createdAt" json:"createdAt"`
+}
@@ -0,0 +1,296 @@
+// Package openapi checks HTTP traffic against the OpenAPI document in
+// api/openapi.yaml. It understands the subset of OpenAPI 3.1 the document
+// uses: path templates, path and query parameters, JSON request and
+// response bodies, and component references.
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Spec is a parsed OpenAPI document.
+type Spec struct {
+	ops        []*Operation
+	components components
+}
+
+// Operation is one documented method on one path.
+type Operation struct {
+	Method string // upper case, e.g. GET
+	Path   string // template, e.g. /api/v1/projects/{id}
+	ID     string // operationId
+
+	segments    []string
+	params      []*Parameter
+	requestBody *RequestBody
+	responses   map[string]*Response
+	spec        *Spec
+}
+
+// Parameter is a path, query or header parameter.
+type Parameter struct {
+	Ref      string  `yaml:"$ref"`
+	Name     string  `yaml:"name"`
+	In       string  `yaml:"in"`
+	Required bool    `yaml:"required"`
+	Schema   *Schema `yaml:"schema"`
+}
+
+// RequestBody is an operation's documented request body.
+type RequestBody struct {
+	Ref      string                `yaml:"$ref"`
+	Required bool                  `yaml:"required"`
+	Content  map[string]*MediaType `yaml:"content"`
+}
+
+// Response is a documented response for one status code.
+type Response struct {
+	Ref     string                `yaml:"$ref"`
+	Content map[string]*MediaType `yaml:"content"`
+}
+
+// MediaType is the schema of a body in one content type.
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+type components struct {
+	Schemas       map[string]*Schema      `yaml:"schemas"`
+	Parameters    map[string]*Parameter   `yaml:"parameters"`
+	RequestBodies map[string]*RequestBody `yaml:"requestBodies"`
+	Responses     map[string]*Response    `yaml:"responses"`
+}
+
+type operationDoc struct {
+	OperationID string               `yaml:"operationId"`
+	Parameters  []*Parameter         `yaml:"parameters"`
+	RequestBody *RequestBody         `yaml:"requestBody"`
+	Responses   map[string]*Response `yaml:"responses"`
+}
+
+type pathItem struct {
+	Parameters []*Parameter  `yaml:"parameters"`
+	Get        *operationDoc `yaml:"get"`
+	Put        *operationDoc `yaml:"put"`
+	Post       *operationDoc `yaml:"post"`
+	Delete     *operationDoc `yaml:"delete"`
+	Patch      *operationDoc `yaml:"patch"`
+	Head       *operationDoc `yaml:"head"`
+}
+
+// Load parses an OpenAPI document and resolves its component references.
+// It fails on references to missing components, so a broken document is
+// caught at startup rather than on the first request.
+func Load(data []byte) (*Spec, error) {
+	var doc struct {
+		Paths      map[string]*pathItem `yaml:"paths"`
+		Components components           `yaml:"components"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse openapi: %w", err)
+	}
+
+	s := &Spec{components: doc.Components}
+	for path, item := range doc.Paths {
+		for _, m := range []struct {
+			method string
+			doc    *operationDoc
+		}{
+			{http.MethodGet, item.Get},
+			{http.MethodPut, item.Put},
+			{http.MethodPost, item.Post},
+			{http.MethodDelete, item.Delete},
+			{http.MethodPatch, item.Patch},
+			{http.MethodHead, item.Head},
+		} {
+			if m.doc == nil {
+				continue
+			}
+			op, err := s.newOperation(m.method, path, item.Parameters, m.doc)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", m.method, path, err)
+			}
+			s.ops = append(s.ops, op)
+		}
+	}
+	for name, schema := range s.components.Schemas {
+		if err := s.checkRefs(schema); err != nil {
+			return nil, fmt.Errorf("schema %s: %w", name, err)
+		}
+	}
+
+	sort.Slice(s.ops, func(i, j int) bool {
+		if s.ops[i].Path != s.ops[j].Path {
+			return s.ops[i].Path < s.ops[j].Path
+		}
+		return s.ops[i].Method < s.ops[j].Method
+	})
+	return s, nil
+}
+
+func (s *Spec) newOperation(method, path string, shared []*Parameter, doc *operationDoc) (*Operation, error) {
+	op := &Operation{
+		Method:    method,
+		Path:      path,
+		ID:        doc.OperationID,
+		segments:  strings.Split(strings.Trim(path, "/"), "/"),
+		responses: make(map[string]*Response, len(doc.Responses)),
+		spec:      s,
+	}
+
+	// Operation parameters override path-level ones with the same name
+	byKey := make(map[string]*Parameter)
+	var order []string
+	for _, p := range append(append([]*Parameter{}, shared...), doc.Parameters...) {
+		p, err := s.parameter(p)
+		if err != nil {
+			return nil, err
+		}
+		key := p.In + ":" + p.Name
+		if _, ok := byKey[key]; !ok {
+			order = append(order, key)
+		}
+		byKey[key] = p
+		if err := s.checkRefs(p.Schema); err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", p.Name, err)
+		}
+	}
+	for _, key := range order {
+		op.params = append(op.params, byKey[key])
+	}
+
+	if doc.RequestBody != nil {
+		body := doc.RequestBody
+		if body.Ref != "" {
+			var ok bool
+			if body, ok = s.components.RequestBodies[refName(body.Ref, "requestBodies")]; !ok {
+				return nil, fmt.Errorf("unresolved reference %s", doc.RequestBody.Ref)
+			}
+		}
+		for _, mt := range body.Content {
+			if err := s.checkRefs(mt.Schema); err != nil {
+				return nil, fmt.Errorf("request body: %w", err)
+			}
+		}
+		op.requestBody = body
+	}
+
+	for status, resp := range doc.Responses {
+		if resp.Ref != "" {
+			ref := resp.Ref
+			var ok bool
+			if resp, ok = s.components.Responses[refName(ref, "responses")]; !ok {
+				return nil, fmt.Errorf("unresolved reference %s", ref)
+			}
+		}
+		for _, mt := range resp.Content {
+			if err := s.checkRefs(mt.Schema); err != nil {
+				return nil, fmt.Errorf("response %s: %w", status, err)
+			}
+		}
+		op.responses[strings.ToUpper(status)] = resp
+	}
+	return op, nil
+}
+
+func (s *Spec) parameter(p *Parameter) (*Parameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+	resolved, ok := s.components.Parameters[refName(p.Ref, "parameters")]
+	if !ok {
+		return nil, fmt.Errorf("unresolved reference %s", p.Ref)
+	}
+	return resolved, nil
+}
+
+// refName returns the component name of a local reference such as
+// #/components/schemas/Project, or "" if ref points elsewhere.
+func refName(ref, kind string) string {
+	name, ok := strings.CutPrefix(ref, "#/components/"+kind+"/")
+	if !ok {
+		return ""
+	}
+	return name
+}
+
+// Operations returns every documented operation, ordered by path and method.
+func (s *Spec) Operations() []*Operation {
+	return s.ops
+}
+
+// Find returns the operation documented for a request method and path, and
+// the path parameters it extracts. When several templates match, the one
+// with the most literal segments wins, so /projects/count is preferred over
+// /projects/{id}.
+func (s *Spec) Find(method, path string) (*Operation, map[string]string, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	var best *Operation
+	var bestParams map[string]string
+	bestLiterals := -1
+	for _, op := range s.ops {
+		if op.Method != method || len(op.segments) != len(segments) {
+			continue
+		}
+		params, literals, ok := op.match(segments)
+		if ok && literals > bestLiterals {
+			best, bestParams, bestLiterals = op, params, literals
+		}
+	}
+	return best, bestParams, best != nil
+}
+
+// HasPath reports whether any operation is documented on path, whatever
+// its method.
+func (s *Spec) HasPath(path string) bool {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, op := range s.ops {
+		if len(op.segments) == len(segments) {
+			if _, _, ok := op.match(segments); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (op *Operation) match(segments []string) (map[string]string, int, bool) {
+	params := make(map[string]string)
+	literals := 0
+	for i, seg := range op.segments {
+		if name, ok := strings.CutPrefix(seg, "{"); ok {
+			if segments[i] == "" {
+				return nil, 0, false
+			}
+			params[strings.TrimSuffix(name, "}")] = segments[i]
+			continue
+		}
+		if seg != segments[i] {
+			return nil, 0, false
+		}
+		literals++
+	}
+	return params, literals, true
+}
+
+// Responses returns the documented status codes of the operation, e.g.
+// "200", "4XX" or "default", in sorted order.
+func (op *Operation) Responses() []string {
+	codes := make([]string, 0, len(op.responses))
+	for code := range op.responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// String returns the operation as "METHOD /path".
+func (op *Operation) String() string {
+	return op.Method + " " + op.Path
+}
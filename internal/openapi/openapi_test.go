@@ -0,0 +1,233 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pandasWhoCode/paintbar/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `
+openapi: 3.1.0
+paths:
+  /items:
+    get:
+      operationId: listItems
+      parameters:
+        - name: limit
+          in: query
+          schema: {type: integer, minimum: 1, maximum: 50}
+        - name: order
+          in: query
+          schema: {type: string, enum: [asc, desc]}
+      responses:
+        "200":
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: '#/components/schemas/Item'}
+        4XX: {$ref: '#/components/responses/Error'}
+    post:
+      operationId: createItem
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Item'}
+      responses:
+        "201": {$ref: '#/components/responses/Error'}
+  /items/{id}:
+    parameters:
+      - {$ref: '#/components/parameters/ID'}
+    get:
+      operationId: getItem
+      responses:
+        "200":
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Item'}
+    delete:
+      operationId: deleteItem
+      responses:
+        "204": {description: Deleted}
+  /items/count:
+    get:
+      operationId: countItems
+      responses:
+        "200":
+          content:
+            text/plain: {}
+  /items/{id}/image:
+    put:
+      operationId: putImage
+      requestBody:
+        content:
+          image/*: {}
+      responses:
+        "200": {description: Stored}
+components:
+  parameters:
+    ID:
+      name: id
+      in: path
+      required: true
+      schema: {type: string, pattern: '^[a-z0-9]+$'}
+  responses:
+    Error:
+      description: Error
+      content:
+        application/json:
+          schema:
+            type: object
+            required: [error]
+            properties:
+              error: {type: string}
+  schemas:
+    Item:
+      type: object
+      required: [id, name]
+      additionalProperties: false
+      properties:
+        id: {type: string, readOnly: true}
+        name: {type: string, maxLength: 5}
+        tags:
+          type: [array, "null"]
+          items: {type: string}
+        createdAt: {type: string, format: date-time}
+`
+
+func loadTestSpec(t *testing.T) *Spec {
+	t.Helper()
+	spec, err := Load([]byte(testSpec))
+	require.NoError(t, err)
+	return spec
+}
+
+func TestLoad_RealSpec(t *testing.T) {
+	spec, err := Load(api.OpenAPISpec)
+	require.NoError(t, err)
+	assert.NotEmpty(t, spec.Operations())
+
+	op, params, ok := spec.Find(http.MethodGet, "/api/v1/projects/abc")
+	require.True(t, ok)
+	assert.Equal(t, "getProject", op.ID)
+	assert.Equal(t, map[string]string{"id": "abc"}, params)
+}
+
+func TestLoad_RejectsUnresolvedRef(t *testing.T) {
+	_, err := Load([]byte(strings.Replace(testSpec, "'#/components/schemas/Item'}\n      responses:\n        \"201\"", "'#/components/schemas/Missing'}\n      responses:\n        \"201\"", 1)))
+	assert.ErrorContains(t, err, "Missing")
+}
+
+func TestFind(t *testing.T) {
+	spec := loadTestSpec(t)
+
+	op, params, ok := spec.Find(http.MethodGet, "/items/count")
+	require.True(t, ok)
+	assert.Equal(t, "countItems", op.ID, "literal segments win over templates")
+	assert.Empty(t, params)
+
+	op, params, ok = spec.Find(http.MethodDelete, "/items/abc")
+	require.True(t, ok)
+	assert.Equal(t, "deleteItem", op.ID)
+	assert.Equal(t, "abc", params["id"])
+	assert.Equal(t, "DELETE /items/{id}", op.String())
+
+	_, _, ok = spec.Find(http.MethodPatch, "/items/abc")
+	assert.False(t, ok)
+	_, _, ok = spec.Find(http.MethodGet, "/other")
+	assert.False(t, ok)
+	assert.True(t, spec.HasPath("/items/abc/image"))
+}
+
+func TestValidateRequest_Params(t *testing.T) {
+	spec := loadTestSpec(t)
+	list, _, _ := spec.Find(http.MethodGet, "/items")
+
+	for query, wantErr := range map[string]string{
+		"":                      "",
+		"limit=10&order=asc":    "",
+		"limit=0":               `query parameter "limit"`,
+		"limit=ten":             "must be an integer",
+		"order=sideways":        "must be one of",
+		"limit=1&limit=2":       "must be given once",
+		"limit=&order=":         "",
+		"unknownParam=whatever": "",
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/items?"+query, nil)
+		err := list.ValidateRequest(r, nil, nil)
+		if wantErr == "" {
+			assert.NoError(t, err, query)
+			continue
+		}
+		assert.ErrorContains(t, err, wantErr, query)
+		assert.True(t, strings.HasPrefix(err.Error(), "invalid"), query)
+	}
+
+	get, params, _ := spec.Find(http.MethodGet, "/items/ABC")
+	assert.ErrorContains(t, get.ValidateRequest(httptest.NewRequest(http.MethodGet, "/items/ABC", nil), params, nil), `path parameter "id"`)
+}
+
+func TestValidateRequest_Body(t *testing.T) {
+	spec := loadTestSpec(t)
+	create, _, _ := spec.Find(http.MethodPost, "/items")
+
+	validate := func(contentType, body string) error {
+		r := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(body))
+		r.Header.Set("Content-Type", contentType)
+		return create.ValidateRequest(r, nil, []byte(body))
+	}
+
+	assert.NoError(t, validate("application/json", `{"name":"sky","tags":null}`), "readOnly id is not required in requests")
+	assert.NoError(t, validate("application/json; charset=utf-8", `{"name":"sky","createdAt":"2026-01-02T03:04:05Z"}`))
+	assert.ErrorContains(t, validate("application/json", ``), "body is required")
+	assert.ErrorContains(t, validate("application/json", `{"name":`), "malformed JSON")
+	assert.ErrorContains(t, validate("application/json", `{}`), "name")
+	assert.ErrorContains(t, validate("application/json", `{"name":"toolong"}`), "name")
+	assert.ErrorContains(t, validate("application/json", `{"name":"sky","color":"red"}`), "color")
+	assert.ErrorContains(t, validate("application/json", `{"name":"sky","tags":[1]}`), "tags[0]")
+	assert.ErrorContains(t, validate("application/json", `{"name":"sky","createdAt":"yesterday"}`), "createdAt")
+	assert.ErrorContains(t, validate("text/plain", `sky`), "content type")
+}
+
+func TestValidateRequest_UnreadBody(t *testing.T) {
+	spec := loadTestSpec(t)
+	put, params, _ := spec.Find(http.MethodPut, "/items/abc/image")
+
+	r := httptest.NewRequest(http.MethodPut, "/items/abc/image", strings.NewReader("png bytes"))
+	r.Header.Set("Content-Type", "image/png")
+	assert.NoError(t, put.ValidateRequest(r, params, nil), "image/* range matches and the body is not read")
+
+	r = httptest.NewRequest(http.MethodPut, "/items/abc/image", strings.NewReader("{}"))
+	r.Header.Set("Content-Type", "application/json")
+	assert.ErrorContains(t, put.ValidateRequest(r, params, nil), "not accepted")
+}
+
+func TestValidateResponse(t *testing.T) {
+	spec := loadTestSpec(t)
+	list, _, _ := spec.Find(http.MethodGet, "/items")
+	get, _, _ := spec.Find(http.MethodGet, "/items/abc")
+	del, _, _ := spec.Find(http.MethodDelete, "/items/abc")
+	count, _, _ := spec.Find(http.MethodGet, "/items/count")
+
+	jsonHeader := http.Header{"Content-Type": []string{"application/json"}}
+
+	assert.NoError(t, list.ValidateResponse(200, jsonHeader, []byte(`[{"id":"a","name":"sky"}]`)))
+	assert.ErrorContains(t, list.ValidateResponse(200, jsonHeader, []byte(`null`)), "array")
+	assert.NoError(t, list.ValidateResponse(404, jsonHeader, []byte(`{"error":"not found"}`)), "4XX matches the class")
+	assert.ErrorContains(t, list.ValidateResponse(500, jsonHeader, []byte(`{"error":"boom"}`)), "status 500 is not documented")
+
+	assert.ErrorContains(t, get.ValidateResponse(200, jsonHeader, []byte(`{"name":"sky"}`)), "id", "readOnly fields are required in responses")
+	assert.ErrorContains(t, get.ValidateResponse(200, http.Header{"Content-Type": []string{"text/html"}}, []byte(`<p>`)), "content type")
+	assert.ErrorContains(t, get.ValidateResponse(200, http.Header{}, nil), "got none")
+
+	assert.NoError(t, del.ValidateResponse(204, http.Header{}, nil))
+	assert.ErrorContains(t, del.ValidateResponse(204, jsonHeader, []byte(`{}`)), "without a body")
+
+	assert.NoError(t, count.ValidateResponse(200, http.Header{"Content-Type": []string{"text/plain"}}, []byte("3")))
+}
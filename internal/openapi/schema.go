@@ -0,0 +1,341 @@
+package openapi
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Schema is a JSON Schema, limited to the keywords the document uses.
+type Schema struct {
+	Ref                  string             `yaml:"$ref"`
+	Type                 schemaType         `yaml:"type"`
+	Format               string             `yaml:"format"`
+	Enum                 []interface{}      `yaml:"enum"`
+	Properties           map[string]*Schema `yaml:"properties"`
+	Required             []string           `yaml:"required"`
+	AdditionalProperties *additional        `yaml:"additionalProperties"`
+	Items                *Schema            `yaml:"items"`
+	AllOf                []*Schema          `yaml:"allOf"`
+	OneOf                []*Schema          `yaml:"oneOf"`
+	AnyOf                []*Schema          `yaml:"anyOf"`
+	Pattern              string             `yaml:"pattern"`
+	MinLength            *int               `yaml:"minLength"`
+	MaxLength            *int               `yaml:"maxLength"`
+	Minimum              *float64           `yaml:"minimum"`
+	Maximum              *float64           `yaml:"maximum"`
+	MinItems             *int               `yaml:"minItems"`
+	MaxItems             *int               `yaml:"maxItems"`
+	ReadOnly             bool               `yaml:"readOnly"`
+	WriteOnly            bool               `yaml:"writeOnly"`
+}
+
+// schemaType is the type keyword: a single type name or, in OpenAPI 3.1, a
+// list such as [string, "null"].
+type schemaType []string
+
+func (t *schemaType) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*t = schemaType{node.Value}
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
+}
+
+// additional is additionalProperties: false, true or a schema.
+type additional struct {
+	Allowed bool
+	Schema  *Schema
+}
+
+func (a *additional) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&a.Allowed)
+	}
+	a.Allowed = true
+	return node.Decode(&a.Schema)
+}
+
+// direction is whether a value is sent by the client or the server, which
+// decides how readOnly and writeOnly properties are treated.
+type direction int
+
+const (
+	inRequest direction = iota
+	inResponse
+)
+
+// checkRefs reports a reference in schema, or any schema it contains, that
+// does not name a component schema.
+func (s *Spec) checkRefs(schema *Schema) error {
+	seen := make(map[*Schema]bool)
+	var walk func(*Schema) error
+	walk = func(sc *Schema) error {
+		if sc == nil || seen[sc] {
+			return nil
+		}
+		seen[sc] = true
+		if sc.Ref != "" {
+			if _, ok := s.components.Schemas[refName(sc.Ref, "schemas")]; !ok {
+				return fmt.Errorf("unresolved reference %s", sc.Ref)
+			}
+		}
+		children := append(append(append([]*Schema{sc.Items}, sc.AllOf...), sc.OneOf...), sc.AnyOf...)
+		for _, p := range sc.Properties {
+			children = append(children, p)
+		}
+		if sc.AdditionalProperties != nil {
+			children = append(children, sc.AdditionalProperties.Schema)
+		}
+		for _, c := range children {
+			if err := walk(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(schema)
+}
+
+func (s *Spec) resolve(sc *Schema) *Schema {
+	for sc != nil && sc.Ref != "" {
+		sc = s.components.Schemas[refName(sc.Ref, "schemas")]
+	}
+	return sc
+}
+
+// validate checks a decoded JSON value against schema. at is the JSON path
+// of v, used in error messages.
+func (s *Spec) validate(sc *Schema, v interface{}, at string, dir direction) error {
+	sc = s.resolve(sc)
+	if sc == nil {
+		return nil
+	}
+
+	for _, sub := range sc.AllOf {
+		if err := s.validate(sub, v, at, dir); err != nil {
+			return err
+		}
+	}
+	if len(sc.AnyOf) > 0 {
+		var first error
+		for _, sub := range sc.AnyOf {
+			err := s.validate(sub, v, at, dir)
+			if err == nil {
+				first = nil
+				break
+			}
+			if first == nil {
+				first = err
+			}
+		}
+		if first != nil {
+			return fieldError(at, "does not match any allowed schema (%v)", first)
+		}
+	}
+	if len(sc.OneOf) > 0 {
+		matched := 0
+		var first error
+		for _, sub := range sc.OneOf {
+			if err := s.validate(sub, v, at, dir); err == nil {
+				matched++
+			} else if first == nil {
+				first = err
+			}
+		}
+		if matched != 1 {
+			if matched == 0 {
+				return fieldError(at, "does not match any allowed schema (%v)", first)
+			}
+			return fieldError(at, "matches %d schemas, expected exactly one", matched)
+		}
+	}
+
+	if len(sc.Type) > 0 && !sc.Type.allows(v) {
+		return fieldError(at, "must be %s", strings.Join(sc.Type, " or "))
+	}
+	if len(sc.Enum) > 0 && !inEnum(sc.Enum, v) {
+		return fieldError(at, "must be one of %v", sc.Enum)
+	}
+
+	switch val := v.(type) {
+	case string:
+		return s.validateString(sc, val, at)
+	case float64:
+		if sc.Minimum != nil && val < *sc.Minimum {
+			return fieldError(at, "must be at least %v", *sc.Minimum)
+		}
+		if sc.Maximum != nil && val > *sc.Maximum {
+			return fieldError(at, "must be at most %v", *sc.Maximum)
+		}
+	case []interface{}:
+		if sc.MinItems != nil && len(val) < *sc.MinItems {
+			return fieldError(at, "must have at least %d items", *sc.MinItems)
+		}
+		if sc.MaxItems != nil && len(val) > *sc.MaxItems {
+			return fieldError(at, "must have at most %d items", *sc.MaxItems)
+		}
+		for i, item := range val {
+			if err := s.validate(sc.Items, item, fmt.Sprintf("%s[%d]", at, i), dir); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		return s.validateObject(sc, val, at, dir)
+	}
+	return nil
+}
+
+func (s *Spec) validateString(sc *Schema, v, at string) error {
+	n := utf8.RuneCountInString(v)
+	if sc.MinLength != nil && n < *sc.MinLength {
+		return fieldError(at, "must be at least %d characters", *sc.MinLength)
+	}
+	if sc.MaxLength != nil && n > *sc.MaxLength {
+		return fieldError(at, "must be at most %d characters", *sc.MaxLength)
+	}
+	if sc.Pattern != "" {
+		re, err := compilePattern(sc.Pattern)
+		if err == nil && !re.MatchString(v) {
+			return fieldError(at, "must match %s", sc.Pattern)
+		}
+	}
+	switch sc.Format {
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339Nano, v); err != nil {
+			return fieldError(at, "must be an RFC 3339 date-time")
+		}
+	case "date":
+		if _, err := time.Parse(time.DateOnly, v); err != nil {
+			return fieldError(at, "must be a date (YYYY-MM-DD)")
+		}
+	}
+	return nil
+}
+
+func (s *Spec) validateObject(sc *Schema, v map[string]interface{}, at string, dir direction) error {
+	for _, name := range sc.Required {
+		if _, ok := v[name]; ok {
+			continue
+		}
+		// Read-only properties are never sent by clients, and write-only
+		// ones never returned, so they are only required one way
+		if p := s.resolve(sc.Properties[name]); p != nil &&
+			((dir == inRequest && p.ReadOnly) || (dir == inResponse && p.WriteOnly)) {
+			continue
+		}
+		return fieldError(join(at, name), "is required")
+	}
+
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if p, ok := sc.Properties[name]; ok {
+			if err := s.validate(p, v[name], join(at, name), dir); err != nil {
+				return err
+			}
+			continue
+		}
+		if ap := sc.AdditionalProperties; ap != nil {
+			if !ap.Allowed {
+				return fieldError(join(at, name), "is not a documented property")
+			}
+			if err := s.validate(ap.Schema, v[name], join(at, name), dir); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// allows reports whether a decoded JSON value has one of the types.
+func (t schemaType) allows(v interface{}) bool {
+	for _, name := range t {
+		switch val := v.(type) {
+		case nil:
+			if name == "null" {
+				return true
+			}
+		case bool:
+			if name == "boolean" {
+				return true
+			}
+		case float64:
+			if name == "number" || (name == "integer" && val == math.Trunc(val)) {
+				return true
+			}
+		case string:
+			if name == "string" {
+				return true
+			}
+		case []interface{}:
+			if name == "array" {
+				return true
+			}
+		case map[string]interface{}:
+			if name == "object" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func inEnum(enum []interface{}, v interface{}) bool {
+	for _, e := range enum {
+		// YAML integers decode as int; JSON numbers as float64
+		if i, ok := e.(int); ok {
+			e = float64(i)
+		}
+		if reflect.DeepEqual(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+var patterns sync.Map // pattern -> *regexp.Regexp
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, re)
+	return re, nil
+}
+
+func join(at, name string) string {
+	if at == "" {
+		return name
+	}
+	return at + "." + name
+}
+
+// fieldError reports a schema violation at a JSON path.
+func fieldError(at, format string, args ...interface{}) error {
+	msg := fmt.Sprintf(format, args...)
+	if at == "" {
+		return fmt.Errorf("%s", msg)
+	}
+	return fmt.Errorf("%s %s", at, msg)
+}
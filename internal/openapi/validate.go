@@ -0,0 +1,224 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ValidateRequest checks a request's parameters and body against the
+// operation. pathParams are the values Find extracted and body is the
+// request body, already read; nil means it was not read (an upload, say),
+// so only its content type is checked. Errors start with "invalid" so
+// handlers map them to 400.
+func (op *Operation) ValidateRequest(r *http.Request, pathParams map[string]string, body []byte) error {
+	query := r.URL.Query()
+	for _, p := range op.params {
+		var values []string
+		switch p.In {
+		case "path":
+			if v, ok := pathParams[p.Name]; ok {
+				values = []string{v}
+			}
+		case "query":
+			values = query[p.Name]
+		case "header":
+			values = r.Header.Values(p.Name)
+		default:
+			continue
+		}
+
+		if len(values) == 0 || (len(values) == 1 && values[0] == "" && p.In == "query") {
+			if p.Required {
+				return fmt.Errorf("invalid request: %s parameter %q is required", p.In, p.Name)
+			}
+			continue
+		}
+		if err := op.spec.validateParam(p, values); err != nil {
+			return fmt.Errorf("invalid %s parameter %q: %w", p.In, p.Name, err)
+		}
+	}
+
+	if op.requestBody == nil {
+		return nil
+	}
+	unread := body == nil && r.ContentLength != 0
+	if !unread && len(bytes.TrimSpace(body)) == 0 {
+		if op.requestBody.Required {
+			return fmt.Errorf("invalid request: body is required")
+		}
+		return nil
+	}
+
+	mediaType, mt := matchContent(op.requestBody.Content, r.Header.Get("Content-Type"))
+	if mt == nil {
+		return fmt.Errorf("invalid request: content type %q is not accepted (expected %s)",
+			r.Header.Get("Content-Type"), strings.Join(contentTypes(op.requestBody.Content), ", "))
+	}
+	if unread || !isJSON(mediaType) || mt.Schema == nil {
+		return nil
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return fmt.Errorf("invalid request body: malformed JSON")
+	}
+	if err := op.spec.validate(mt.Schema, v, "", inRequest); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+// ValidateResponse checks a response's status, content type and body
+// against the operation. body may be nil for non-JSON responses, whose
+// bodies are not inspected.
+func (op *Operation) ValidateResponse(status int, header http.Header, body []byte) error {
+	resp := op.Response(status)
+	if resp == nil {
+		return fmt.Errorf("status %d is not documented (documented: %s)", status, strings.Join(op.Responses(), ", "))
+	}
+
+	contentType := header.Get("Content-Type")
+	if len(resp.Content) == 0 {
+		if len(bytes.TrimSpace(body)) > 0 {
+			return fmt.Errorf("status %d is documented without a body", status)
+		}
+		return nil
+	}
+	if contentType == "" && len(body) == 0 {
+		return fmt.Errorf("status %d is documented with a body (%s), got none", status, strings.Join(contentTypes(resp.Content), ", "))
+	}
+
+	mediaType, mt := matchContent(resp.Content, contentType)
+	if mt == nil {
+		return fmt.Errorf("content type %q is not documented for status %d (documented: %s)",
+			contentType, status, strings.Join(contentTypes(resp.Content), ", "))
+	}
+	if !isJSON(mediaType) || mt.Schema == nil {
+		return nil
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return fmt.Errorf("response body: malformed JSON: %w", err)
+	}
+	if err := op.spec.validate(mt.Schema, v, "", inResponse); err != nil {
+		return fmt.Errorf("response body: %w", err)
+	}
+	return nil
+}
+
+// Response returns the response documented for status: its exact code,
+// then its class (e.g. 4XX), then default. It returns nil if none is.
+func (op *Operation) Response(status int) *Response {
+	code := strconv.Itoa(status)
+	for _, key := range []string{code, code[:1] + "XX", "DEFAULT"} {
+		if resp, ok := op.responses[key]; ok {
+			return resp
+		}
+	}
+	return nil
+}
+
+// validateParam checks the string values of a parameter against its schema,
+// converting them to the schema's type first.
+func (s *Spec) validateParam(p *Parameter, values []string) error {
+	schema := s.resolve(p.Schema)
+	if schema == nil {
+		return nil
+	}
+	if schema.Type.has("array") {
+		items := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			conv, err := s.convertParam(schema.Items, v)
+			if err != nil {
+				return err
+			}
+			items = append(items, conv)
+		}
+		return s.validate(schema, items, "", inRequest)
+	}
+	if len(values) > 1 {
+		return fmt.Errorf("must be given once")
+	}
+	v, err := s.convertParam(schema, values[0])
+	if err != nil {
+		return err
+	}
+	return s.validate(schema, v, "", inRequest)
+}
+
+func (s *Spec) convertParam(schema *Schema, raw string) (interface{}, error) {
+	schema = s.resolve(schema)
+	if schema == nil {
+		return raw, nil
+	}
+	switch {
+	case schema.Type.has("integer"):
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be an integer")
+		}
+		return float64(n), nil
+	case schema.Type.has("number"):
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be a number")
+		}
+		return f, nil
+	case schema.Type.has("boolean"):
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("must be true or false")
+		}
+		return b, nil
+	}
+	return raw, nil
+}
+
+func (t schemaType) has(name string) bool {
+	for _, n := range t {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// matchContent finds the documented media type for a Content-Type header:
+// an exact match, then a type/* range, then */*.
+func matchContent(content map[string]*MediaType, header string) (string, *MediaType) {
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return "", nil
+	}
+	if mt, ok := content[mediaType]; ok {
+		return mediaType, mt
+	}
+	major, _, _ := strings.Cut(mediaType, "/")
+	if mt, ok := content[major+"/*"]; ok {
+		return mediaType, mt
+	}
+	if mt, ok := content["*/*"]; ok {
+		return mediaType, mt
+	}
+	return "", nil
+}
+
+func contentTypes(content map[string]*MediaType) []string {
+	types := make([]string, 0, len(content))
+	for t := range content {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
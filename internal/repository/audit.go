@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"google.golang.org/api/iterator"
+)
+
+// AuditLogger is an append-only sink for audit entries that can also be
+// queried. Firestore is the default sink; a JSONL file sink is available
+// for local development (see NewJSONLAuditLogger).
+type AuditLogger interface {
+	// Log appends an entry, setting its ID and CreatedAt.
+	Log(ctx context.Context, entry *model.AuditEntry) error
+	// List returns entries newest first. An empty actorUID lists every actor.
+	List(ctx context.Context, actorUID string, limit int, startAfter string) ([]*model.AuditEntry, error)
+}
+
+// firestoreAuditLogger implements AuditLogger using the top-level auditLog
+// collection.
+type firestoreAuditLogger struct {
+	client *firestore.Client
+}
+
+// NewFirestoreAuditLogger creates a new Firestore-backed AuditLogger.
+func NewFirestoreAuditLogger(client *firestore.Client) AuditLogger {
+	return &firestoreAuditLogger{client: client}
+}
+
+// Log appends an entry to the auditLog collection.
+func (r *firestoreAuditLogger) Log(ctx context.Context, entry *model.AuditEntry) error {
+	entry.CreatedAt = time.Now()
+
+	ref, _, err := r.client.Collection("auditLog").Add(ctx, entry)
+	if err != nil {
+		return fmt.Errorf("create audit entry: %w", err)
+	}
+	entry.ID = ref.ID
+	return nil
+}
+
+// List retrieves audit entries, newest first, with cursor pagination.
+func (r *firestoreAuditLogger) List(ctx context.Context, actorUID string, pageLimit int, startAfter string) ([]*model.AuditEntry, error) {
+	q := r.client.Collection("auditLog").Query
+	if actorUID != "" {
+		q = q.Where("actorUid", "==", actorUID)
+	}
+	q = q.OrderBy("createdAt", firestore.Desc).Limit(pageLimit)
+
+	if startAfter != "" {
+		cursorDoc, err := r.client.Collection("auditLog").Doc(startAfter).Get(ctx)
+		if err != nil {
+			return []*model.AuditEntry{}, nil
+		}
+		q = q.StartAfter(cursorDoc)
+	}
+
+	iter := q.Documents(ctx)
+	defer iter.Stop()
+
+	var entries []*model.AuditEntry
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("iterate audit log: %w", err)
+		}
+
+		var entry model.AuditEntry
+		if err := doc.DataTo(&entry); err != nil {
+			return nil, fmt.Errorf("decode audit entry: %w", err)
+		}
+		entry.ID = doc.Ref.ID
+		entries = append(entries, &entry)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pandasWhoCode/paintbar/internal/model"
+)
+
+// maxAuditLineSize bounds a single JSONL line when reading the log back.
+const maxAuditLineSize = 1 << 20 // 1 MB
+
+// jsonlAuditLogger implements AuditLogger by appending one JSON object per
+// line to a local file. List scans the whole file, so it is meant for local
+// development, not production volumes.
+type jsonlAuditLogger struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewJSONLAuditLogger creates an AuditLogger that appends to the file at
+// path, creating it if needed.
+func NewJSONLAuditLogger(path string) (AuditLogger, error) {
+	if path == "" {
+		return nil, fmt.Errorf("audit log path is required")
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("close audit log %s: %w", path, err)
+	}
+	return &jsonlAuditLogger{path: path}, nil
+}
+
+// Log appends an entry as a single JSON line.
+func (l *jsonlAuditLogger) Log(_ context.Context, entry *model.AuditEntry) error {
+	id := make([]byte, 10)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Errorf("generate audit entry ID: %w", err)
+	}
+	entry.ID = hex.EncodeToString(id)
+	entry.CreatedAt = time.Now()
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encode audit entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return fmt.Errorf("write audit entry: %w", err)
+	}
+	return f.Close()
+}
+
+// List reads the file and returns matching entries, newest first.
+func (l *jsonlAuditLogger) List(_ context.Context, actorUID string, limit int, startAfter string) ([]*model.AuditEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.Open(l.path)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+	defer f.Close()
+
+	var all []*model.AuditEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxAuditLineSize)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry model.AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("decode audit entry: %w", err)
+		}
+		if actorUID != "" && entry.ActorUID != actorUID {
+			continue
+		}
+		all = append(all, &entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read audit log: %w", err)
+	}
+
+	entries := []*model.AuditEntry{}
+	skipping := startAfter != ""
+	for i := len(all) - 1; i >= 0 && len(entries) < limit; i-- {
+		if skipping {
+			if all[i].ID == startAfter {
+				skipping = false
+			}
+			continue
+		}
+		entries = append(entries, all[i])
+	}
+	return entries, nil
+}
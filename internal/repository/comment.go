@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"google.golang.org/api/iterator"
+)
+
+// CommentRepository defines the interface for gallery comment persistence.
+type CommentRepository interface {
+	GetByID(ctx context.Context, itemID, commentID string) (*model.Comment, error)
+	List(ctx context.Context, itemID string, limit int, startAfter string) ([]*model.Comment, error)
+	Create(ctx context.Context, itemID string, comment *model.Comment) (string, error)
+	UpdateBody(ctx context.Context, itemID, commentID, body string) error
+	Delete(ctx context.Context, itemID, commentID string) error
+	SetHidden(ctx context.Context, itemID, commentID string, hidden bool) error
+}
+
+// firestoreCommentRepo implements CommentRepository using the
+// gallery/{itemId}/comments subcollection.
+type firestoreCommentRepo struct {
+	client *firestore.Client
+}
+
+// NewCommentRepository creates a new Firestore-backed CommentRepository.
+func NewCommentRepository(client *firestore.Client) CommentRepository {
+	return &firestoreCommentRepo{client: client}
+}
+
+// comments returns the comments subcollection of a gallery item.
+func (r *firestoreCommentRepo) comments(itemID string) *firestore.CollectionRef {
+	return r.client.Collection("gallery").Doc(itemID).Collection("comments")
+}
+
+// GetByID retrieves a single comment.
+func (r *firestoreCommentRepo) GetByID(ctx context.Context, itemID, commentID string) (*model.Comment, error) {
+	doc, err := r.comments(itemID).Doc(commentID).Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get comment %s: %w", commentID, err)
+	}
+
+	var c model.Comment
+	if err := doc.DataTo(&c); err != nil {
+		return nil, fmt.Errorf("decode comment %s: %w", commentID, err)
+	}
+	c.ID = doc.Ref.ID
+	c.ItemID = itemID
+	return &c, nil
+}
+
+// List retrieves the visible comments on a gallery item, oldest first, with
+// cursor pagination. Hidden comments are filtered in the query so pages stay
+// full. Replies are returned inline; clients group them by parentId.
+func (r *firestoreCommentRepo) List(ctx context.Context, itemID string, pageLimit int, startAfter string) ([]*model.Comment, error) {
+	q := r.comments(itemID).
+		Where("hidden", "==", false).
+		OrderBy("createdAt", firestore.Asc).
+		Limit(pageLimit)
+
+	if startAfter != "" {
+		cursorDoc, err := r.comments(itemID).Doc(startAfter).Get(ctx)
+		if err != nil {
+			return []*model.Comment{}, nil
+		}
+		q = q.StartAfter(cursorDoc)
+	}
+
+	iter := q.Documents(ctx)
+	defer iter.Stop()
+
+	var comments []*model.Comment
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("iterate comments: %w", err)
+		}
+
+		var c model.Comment
+		if err := doc.DataTo(&c); err != nil {
+			return nil, fmt.Errorf("decode comment: %w", err)
+		}
+		c.ID = doc.Ref.ID
+		c.ItemID = itemID
+		comments = append(comments, &c)
+	}
+
+	return comments, nil
+}
+
+// Create adds a comment and increments the item's commentCount in one
+// transaction.
+func (r *firestoreCommentRepo) Create(ctx context.Context, itemID string, comment *model.Comment) (string, error) {
+	now := time.Now()
+	comment.CreatedAt = now
+	comment.UpdatedAt = now
+
+	ref := r.comments(itemID).NewDoc()
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if err := tx.Create(ref, comment); err != nil {
+			return fmt.Errorf("create comment: %w", err)
+		}
+		if err := tx.Update(r.client.Collection("gallery").Doc(itemID), []firestore.Update{
+			{Path: "commentCount", Value: firestore.Increment(1)},
+		}); err != nil {
+			return fmt.Errorf("increment comment count: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	comment.ID = ref.ID
+	comment.ItemID = itemID
+	return ref.ID, nil
+}
+
+// UpdateBody replaces a comment's body and marks it as edited.
+func (r *firestoreCommentRepo) UpdateBody(ctx context.Context, itemID, commentID, body string) error {
+	_, err := r.comments(itemID).Doc(commentID).Update(ctx, []firestore.Update{
+		{Path: "body", Value: body},
+		{Path: "edited", Value: true},
+		{Path: "updatedAt", Value: time.Now()},
+	})
+	if err != nil {
+		return fmt.Errorf("update comment %s: %w", commentID, err)
+	}
+	return nil
+}
+
+// SetHidden sets the moderator-managed hidden flag on a comment.
+func (r *firestoreCommentRepo) SetHidden(ctx context.Context, itemID, commentID string, hidden bool) error {
+	_, err := r.comments(itemID).Doc(commentID).Update(ctx, []firestore.Update{
+		{Path: "hidden", Value: hidden},
+	})
+	if err != nil {
+		return fmt.Errorf("set hidden on comment %s: %w", commentID, err)
+	}
+	return nil
+}
+
+// Delete removes a comment together with its replies and decrements the
+// item's commentCount by the number of documents removed, in one transaction.
+func (r *firestoreCommentRepo) Delete(ctx context.Context, itemID, commentID string) error {
+	ref := r.comments(itemID).Doc(commentID)
+	replies := r.comments(itemID).Where("parentId", "==", commentID)
+
+	return r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		replyDocs, err := tx.Documents(replies).GetAll()
+		if err != nil {
+			return fmt.Errorf("list replies of comment %s: %w", commentID, err)
+		}
+
+		for _, doc := range replyDocs {
+			if err := tx.Delete(doc.Ref); err != nil {
+				return fmt.Errorf("delete reply %s: %w", doc.Ref.ID, err)
+			}
+		}
+		if err := tx.Delete(ref); err != nil {
+			return fmt.Errorf("delete comment %s: %w", commentID, err)
+		}
+		if err := tx.Update(r.client.Collection("gallery").Doc(itemID), []firestore.Update{
+			{Path: "commentCount", Value: firestore.Increment(-(len(replyDocs) + 1))},
+		}); err != nil {
+			return fmt.Errorf("decrement comment count: %w", err)
+		}
+		return nil
+	})
+}
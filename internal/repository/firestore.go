@@ -9,6 +9,7 @@ import (
 	"cloud.google.com/go/firestore"
 	firebase "firebase.google.com/go/v4"
 	"firebase.google.com/go/v4/auth"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
 
@@ -102,6 +103,111 @@ func FirestoreHealthCheck(ctx context.Context, client *firestore.Client) error {
 	return nil
 }
 
+// getDocs reads the documents with the given IDs from a collection in one
+// batched call, skipping IDs with no document.
+func getDocs(ctx context.Context, client *firestore.Client, collection string, ids []string) ([]*firestore.DocumentSnapshot, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	refs := make([]*firestore.DocumentRef, len(ids))
+	for i, id := range ids {
+		refs[i] = client.Collection(collection).Doc(id)
+	}
+	docs, err := client.GetAll(ctx, refs)
+	if err != nil {
+		return nil, fmt.Errorf("get %s: %w", collection, err)
+	}
+	found := docs[:0]
+	for _, doc := range docs {
+		if doc.Exists() {
+			found = append(found, doc)
+		}
+	}
+	return found, nil
+}
+
+// createCounted adds data to collection as a new document and increments
+// counter on the owner's user document in the same transaction, so the
+// counter moves only with the documents it counts.
+func createCounted(ctx context.Context, client *firestore.Client, collection, counter, ownerUID string, data interface{}) (string, error) {
+	ref := client.Collection(collection).NewDoc()
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if err := tx.Create(ref, data); err != nil {
+			return err
+		}
+		return tx.Set(client.Collection("users").Doc(ownerUID), map[string]interface{}{
+			counter: firestore.Increment(1),
+		}, firestore.MergeAll)
+	})
+	if err != nil {
+		return "", err
+	}
+	return ref.ID, nil
+}
+
+// deleteCounted deletes a document from collection and decrements counter
+// on its owner's user document in the same transaction. As with a plain
+// delete, deleting a missing document is a no-op.
+func deleteCounted(ctx context.Context, client *firestore.Client, collection, counter, id string) error {
+	ref := client.Collection(collection).Doc(id)
+	return client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if err != nil {
+			if isNotFoundError(err) {
+				return nil
+			}
+			return err
+		}
+		owner, _ := doc.DataAt("userId")
+		if err := tx.Delete(ref); err != nil {
+			return err
+		}
+		if uid, _ := owner.(string); uid != "" {
+			return tx.Set(client.Collection("users").Doc(uid), map[string]interface{}{
+				counter: firestore.Increment(-1),
+			}, firestore.MergeAll)
+		}
+		return nil
+	})
+}
+
+// deleteSubcollections removes every document in the named subcollections of
+// parent with a BulkWriter. It returns the first failed delete, if any.
+func deleteSubcollections(ctx context.Context, client *firestore.Client, parent *firestore.DocumentRef, names ...string) error {
+	bw := client.BulkWriter(ctx)
+	var jobs []*firestore.BulkWriterJob
+	for _, name := range names {
+		iter := parent.Collection(name).Documents(ctx)
+		for {
+			doc, err := iter.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				iter.Stop()
+				bw.End()
+				return fmt.Errorf("iterate %s: %w", name, err)
+			}
+			job, err := bw.Delete(doc.Ref)
+			if err != nil {
+				iter.Stop()
+				bw.End()
+				return fmt.Errorf("delete %s %s: %w", name, doc.Ref.ID, err)
+			}
+			jobs = append(jobs, job)
+		}
+		iter.Stop()
+	}
+	bw.End()
+
+	for _, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // isNotFoundError checks if the error is a Firestore "not found" error.
 func isNotFoundError(err error) bool {
 	if err == nil {
@@ -111,6 +217,12 @@ func isNotFoundError(err error) bool {
 	return contains(err.Error(), "NotFound") || contains(err.Error(), "not found")
 }
 
+// IsNotFoundError reports whether err is a Firestore "not found" error.
+// Exported for services that treat a missing document as a valid state.
+func IsNotFoundError(err error) bool {
+	return isNotFoundError(err)
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && searchString(s, substr)
 }
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"google.golang.org/api/iterator"
+)
+
+// MaxInQueryValues is the maximum number of values Firestore accepts in a
+// single "in" filter.
+const MaxInQueryValues = 30
+
+// MaxFollowing caps how many accounts a single user may follow. It bounds the
+// fan-out of the following feed query.
+const MaxFollowing = 1000
+
+// FollowRepository defines the interface for follow graph persistence.
+type FollowRepository interface {
+	Follow(ctx context.Context, follower, followee *model.User) error
+	Unfollow(ctx context.Context, followerUID, followeeUID string) error
+	IsFollowing(ctx context.Context, followerUID, followeeUID string) (bool, error)
+	ListFollowers(ctx context.Context, uid string, limit int, startAfter string) ([]*model.Follow, error)
+	ListFollowing(ctx context.Context, uid string, limit int, startAfter string) ([]*model.Follow, error)
+	FollowingIDs(ctx context.Context, uid string) ([]string, error)
+}
+
+// firestoreFollowRepo implements FollowRepository using Firestore
+// subcollections under each user document.
+type firestoreFollowRepo struct {
+	client *firestore.Client
+}
+
+// NewFollowRepository creates a new Firestore-backed FollowRepository.
+func NewFollowRepository(client *firestore.Client) FollowRepository {
+	return &firestoreFollowRepo{client: client}
+}
+
+// Follow records that follower follows followee. The edge documents and both
+// users' counters are written in one transaction, and the MaxFollowing cap is
+// checked against the follower's stored count inside it, so concurrent
+// follows can neither drift the counts nor exceed the cap. Following an
+// already-followed user is a no-op.
+func (r *firestoreFollowRepo) Follow(ctx context.Context, follower, followee *model.User) error {
+	users := r.client.Collection("users")
+	followingRef := users.Doc(follower.UID).Collection("following").Doc(followee.UID)
+	followerRef := users.Doc(followee.UID).Collection("followers").Doc(follower.UID)
+
+	return r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(followingRef)
+		if err != nil && !isNotFoundError(err) {
+			return fmt.Errorf("check follow %s -> %s: %w", follower.UID, followee.UID, err)
+		}
+		if err == nil && doc.Exists() {
+			return nil
+		}
+
+		userDoc, err := tx.Get(users.Doc(follower.UID))
+		if err != nil {
+			return fmt.Errorf("get follower %s: %w", follower.UID, err)
+		}
+		var current model.User
+		if err := userDoc.DataTo(&current); err != nil {
+			return fmt.Errorf("decode follower %s: %w", follower.UID, err)
+		}
+		if current.FollowingCount >= MaxFollowing {
+			return fmt.Errorf("validation: cannot follow more than %d users", MaxFollowing)
+		}
+
+		now := time.Now()
+		if err := tx.Set(followingRef, &model.Follow{Username: followee.Username, CreatedAt: now}); err != nil {
+			return fmt.Errorf("set following doc: %w", err)
+		}
+		if err := tx.Set(followerRef, &model.Follow{Username: follower.Username, CreatedAt: now}); err != nil {
+			return fmt.Errorf("set follower doc: %w", err)
+		}
+		if err := tx.Set(users.Doc(follower.UID), map[string]interface{}{
+			"followingCount": firestore.Increment(1),
+		}, firestore.MergeAll); err != nil {
+			return fmt.Errorf("increment following count: %w", err)
+		}
+		if err := tx.Set(users.Doc(followee.UID), map[string]interface{}{
+			"followerCount": firestore.Increment(1),
+		}, firestore.MergeAll); err != nil {
+			return fmt.Errorf("increment follower count: %w", err)
+		}
+		return nil
+	})
+}
+
+// Unfollow removes the follow edge and decrements both counters in one
+// transaction. Unfollowing a user that is not followed is a no-op.
+func (r *firestoreFollowRepo) Unfollow(ctx context.Context, followerUID, followeeUID string) error {
+	users := r.client.Collection("users")
+	followingRef := users.Doc(followerUID).Collection("following").Doc(followeeUID)
+	followerRef := users.Doc(followeeUID).Collection("followers").Doc(followerUID)
+
+	return r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(followingRef)
+		if err != nil {
+			if isNotFoundError(err) {
+				return nil
+			}
+			return fmt.Errorf("check follow %s -> %s: %w", followerUID, followeeUID, err)
+		}
+		if !doc.Exists() {
+			return nil
+		}
+
+		if err := tx.Delete(followingRef); err != nil {
+			return fmt.Errorf("delete following doc: %w", err)
+		}
+		if err := tx.Delete(followerRef); err != nil {
+			return fmt.Errorf("delete follower doc: %w", err)
+		}
+		if err := tx.Set(users.Doc(followerUID), map[string]interface{}{
+			"followingCount": firestore.Increment(-1),
+		}, firestore.MergeAll); err != nil {
+			return fmt.Errorf("decrement following count: %w", err)
+		}
+		if err := tx.Set(users.Doc(followeeUID), map[string]interface{}{
+			"followerCount": firestore.Increment(-1),
+		}, firestore.MergeAll); err != nil {
+			return fmt.Errorf("decrement follower count: %w", err)
+		}
+		return nil
+	})
+}
+
+// IsFollowing reports whether followerUID currently follows followeeUID.
+func (r *firestoreFollowRepo) IsFollowing(ctx context.Context, followerUID, followeeUID string) (bool, error) {
+	doc, err := r.client.Collection("users").Doc(followerUID).
+		Collection("following").Doc(followeeUID).Get(ctx)
+	if err != nil {
+		if isNotFoundError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("check follow %s -> %s: %w", followerUID, followeeUID, err)
+	}
+	return doc.Exists(), nil
+}
+
+// ListFollowers returns the users following uid, newest first.
+func (r *firestoreFollowRepo) ListFollowers(ctx context.Context, uid string, pageLimit int, startAfter string) ([]*model.Follow, error) {
+	return r.list(ctx, uid, "followers", pageLimit, startAfter)
+}
+
+// ListFollowing returns the users uid follows, newest first.
+func (r *firestoreFollowRepo) ListFollowing(ctx context.Context, uid string, pageLimit int, startAfter string) ([]*model.Follow, error) {
+	return r.list(ctx, uid, "following", pageLimit, startAfter)
+}
+
+// list pages through one of the follow subcollections of a user.
+func (r *firestoreFollowRepo) list(ctx context.Context, uid, sub string, pageLimit int, startAfter string) ([]*model.Follow, error) {
+	col := r.client.Collection("users").Doc(uid).Collection(sub)
+	q := col.OrderBy("createdAt", firestore.Desc).Limit(pageLimit)
+
+	if startAfter != "" {
+		cursorDoc, err := col.Doc(startAfter).Get(ctx)
+		if err != nil {
+			return []*model.Follow{}, nil
+		}
+		q = q.StartAfter(cursorDoc)
+	}
+
+	iter := q.Documents(ctx)
+	defer iter.Stop()
+
+	var follows []*model.Follow
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("iterate %s: %w", sub, err)
+		}
+
+		var f model.Follow
+		if err := doc.DataTo(&f); err != nil {
+			return nil, fmt.Errorf("decode %s doc: %w", sub, err)
+		}
+		f.UID = doc.Ref.ID
+		follows = append(follows, &f)
+	}
+
+	return follows, nil
+}
+
+// FollowingIDs returns the UIDs of up to MaxFollowing accounts uid follows.
+func (r *firestoreFollowRepo) FollowingIDs(ctx context.Context, uid string) ([]string, error) {
+	iter := r.client.Collection("users").Doc(uid).Collection("following").
+		Limit(MaxFollowing).
+		Documents(ctx)
+	defer iter.Stop()
+
+	var ids []string
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("iterate following ids: %w", err)
+		}
+		ids = append(ids, doc.Ref.ID)
+	}
+	return ids, nil
+}
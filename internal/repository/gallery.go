@@ -13,10 +13,14 @@ import (
 // GalleryRepository defines the interface for gallery persistence operations.
 type GalleryRepository interface {
 	GetByID(ctx context.Context, itemID string) (*model.GalleryItem, error)
-	List(ctx context.Context, userID string, limit int, startAfter string) ([]*model.GalleryItem, error)
+	GetByIDs(ctx context.Context, itemIDs []string) ([]*model.GalleryItem, error)
+	List(ctx context.Context, userID string, limit int, opts *model.ListOptions, fields []string) ([]*model.GalleryItem, *model.Cursor, error)
+	CountList(ctx context.Context, userID string, opts *model.ListOptions) (int64, error)
+	ListByUsers(ctx context.Context, userIDs []string, limit int, after *model.Cursor) ([]*model.GalleryItem, error)
 	Count(ctx context.Context, userID string) (int64, error)
 	Create(ctx context.Context, item *model.GalleryItem) (string, error)
 	Delete(ctx context.Context, itemID string) error
+	SetHidden(ctx context.Context, itemID string, hidden bool) error
 }
 
 // firestoreGalleryRepo implements GalleryRepository using Firestore.
@@ -44,22 +48,74 @@ func (r *firestoreGalleryRepo) GetByID(ctx context.Context, itemID string) (*mod
 	return &item, nil
 }
 
-// List retrieves gallery items for a user with cursor pagination.
-func (r *firestoreGalleryRepo) List(ctx context.Context, userID string, pageLimit int, startAfter string) ([]*model.GalleryItem, error) {
-	q := r.client.Collection("gallery").
-		Where("userId", "==", userID).
-		OrderBy("createdAt", firestore.Desc).
-		Limit(pageLimit)
+// GetByIDs retrieves the gallery items with the given IDs in one batched read.
+// IDs with no gallery item are skipped.
+func (r *firestoreGalleryRepo) GetByIDs(ctx context.Context, itemIDs []string) ([]*model.GalleryItem, error) {
+	docs, err := getDocs(ctx, r.client, "gallery", itemIDs)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*model.GalleryItem, 0, len(docs))
+	for _, doc := range docs {
+		var item model.GalleryItem
+		if err := doc.DataTo(&item); err != nil {
+			return nil, fmt.Errorf("decode gallery item %s: %w", doc.Ref.ID, err)
+		}
+		item.ID = doc.Ref.ID
+		items = append(items, &item)
+	}
+	return items, nil
+}
 
-	if startAfter != "" {
-		cursorDoc, err := r.client.Collection("gallery").Doc(startAfter).Get(ctx)
-		if err != nil {
-			return []*model.GalleryItem{}, nil
+// List retrieves a page of a user's gallery items per opts (normalized against
+// model.GalleryListSchema) and the cursor for the next page. Only the given
+// field paths are read.
+func (r *firestoreGalleryRepo) List(ctx context.Context, userID string, pageLimit int, opts *model.ListOptions, fields []string) ([]*model.GalleryItem, *model.Cursor, error) {
+	q := listQuery(r.client, model.GalleryListSchema, userID, pageLimit, opts, fields)
+	docs, next, err := listPage(ctx, q, model.GalleryListSchema, pageLimit, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items := make([]*model.GalleryItem, 0, len(docs))
+	for _, doc := range docs {
+		var item model.GalleryItem
+		if err := doc.DataTo(&item); err != nil {
+			return nil, nil, fmt.Errorf("decode gallery item: %w", err)
 		}
-		q = q.StartAfter(cursorDoc)
+		item.ID = doc.Ref.ID
+		items = append(items, &item)
+	}
+
+	return items, next, nil
+}
+
+// CountList counts the gallery items List would return across all pages.
+func (r *firestoreGalleryRepo) CountList(ctx context.Context, userID string, opts *model.ListOptions) (int64, error) {
+	return listCount(ctx, r.client, model.GalleryListSchema, userID, opts)
+}
+
+// ListByUsers retrieves the most recent gallery items created by any of the
+// given users, newest first with a document ID tiebreak, starting after the
+// given position (nil for the first page). At most MaxInQueryValues user IDs
+// may be passed per call (Firestore "in" limit).
+func (r *firestoreGalleryRepo) ListByUsers(ctx context.Context, userIDs []string, pageLimit int, after *model.Cursor) ([]*model.GalleryItem, error) {
+	if len(userIDs) == 0 {
+		return []*model.GalleryItem{}, nil
+	}
+	if len(userIDs) > MaxInQueryValues {
+		return nil, fmt.Errorf("list gallery by users: at most %d user IDs per query", MaxInQueryValues)
 	}
 
-	iter := q.Documents(ctx)
+	q := r.client.Collection("gallery").
+		Where("userId", "in", userIDs).
+		OrderBy("createdAt", firestore.Desc).
+		OrderBy(firestore.DocumentID, firestore.Desc)
+	if after != nil {
+		q = q.StartAfter(after.SortValue(model.GalleryListSchema.Sorts["createdAt"]), after.ID)
+	}
+
+	iter := q.Limit(pageLimit).Documents(ctx)
 	defer iter.Stop()
 
 	var items []*model.GalleryItem
@@ -69,7 +125,7 @@ func (r *firestoreGalleryRepo) List(ctx context.Context, userID string, pageLimi
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("iterate gallery: %w", err)
+			return nil, fmt.Errorf("iterate gallery by users: %w", err)
 		}
 
 		var item model.GalleryItem
@@ -106,24 +162,41 @@ func (r *firestoreGalleryRepo) Count(ctx context.Context, userID string) (int64,
 	}
 }
 
-// Create adds a new gallery item to Firestore and returns the generated document ID.
+// Create adds a new gallery item to Firestore, increments the owner's counter and
+// returns the generated document ID.
 func (r *firestoreGalleryRepo) Create(ctx context.Context, item *model.GalleryItem) (string, error) {
 	item.CreatedAt = time.Now()
 
-	ref, _, err := r.client.Collection("gallery").Add(ctx, item)
+	id, err := createCounted(ctx, r.client, "gallery", "galleryCount", item.UserID, item)
 	if err != nil {
 		return "", fmt.Errorf("create gallery item: %w", err)
 	}
 
-	item.ID = ref.ID
-	return ref.ID, nil
+	item.ID = id
+	return id, nil
 }
 
-// Delete removes a gallery item from Firestore.
+// Delete removes a gallery item from Firestore together with its comments
+// and reactions, and decrements the owner's counter.
 func (r *firestoreGalleryRepo) Delete(ctx context.Context, itemID string) error {
-	_, err := r.client.Collection("gallery").Doc(itemID).Delete(ctx)
+	ref := r.client.Collection("gallery").Doc(itemID)
+	if err := deleteSubcollections(ctx, r.client, ref, "comments", "reactions"); err != nil {
+		return fmt.Errorf("delete gallery item %s: %w", itemID, err)
+	}
+	err := deleteCounted(ctx, r.client, "gallery", "galleryCount", itemID)
 	if err != nil {
 		return fmt.Errorf("delete gallery item %s: %w", itemID, err)
 	}
 	return nil
 }
+
+// SetHidden sets the moderator-managed hidden flag on a gallery item.
+func (r *firestoreGalleryRepo) SetHidden(ctx context.Context, itemID string, hidden bool) error {
+	_, err := r.client.Collection("gallery").Doc(itemID).Update(ctx, []firestore.Update{
+		{Path: "hidden", Value: hidden},
+	})
+	if err != nil {
+		return fmt.Errorf("set hidden on gallery item %s: %w", itemID, err)
+	}
+	return nil
+}
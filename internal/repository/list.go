@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"google.golang.org/api/iterator"
+)
+
+// ListSchemas are the schemas of the sortable, filterable list endpoints.
+// Their composite indexes are generated into firestore.indexes.json.
+var ListSchemas = []model.ListSchema{
+	model.ProjectListSchema,
+	model.GalleryListSchema,
+	model.NFTListSchema,
+}
+
+// listFilter returns the query for all of a user's items matching the
+// filters in opts, before sorting and paging.
+func listFilter(client *firestore.Client, schema model.ListSchema, userID string, opts *model.ListOptions) firestore.Query {
+	q := client.Collection(schema.Collection).Where("userId", "==", userID)
+	if opts.Tag != "" {
+		q = q.Where(schema.TagField, "array-contains", opts.Tag)
+	}
+	if opts.IsPublic != nil {
+		q = q.Where(schema.PublicField, "==", *opts.IsPublic)
+	}
+	if !opts.CreatedAfter.IsZero() {
+		q = q.Where("createdAt", ">", opts.CreatedAfter)
+	}
+	if !opts.CreatedBefore.IsZero() {
+		q = q.Where("createdAt", "<", opts.CreatedBefore)
+	}
+	return q
+}
+
+// listCount counts a user's items matching the filters in opts with a
+// Firestore aggregation, without reading the documents.
+func listCount(ctx context.Context, client *firestore.Client, schema model.ListSchema, userID string, opts *model.ListOptions) (int64, error) {
+	q := listFilter(client, schema, userID, opts)
+	results, err := q.NewAggregationQuery().WithCount("count").Get(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("count %s: %w", schema.Collection, err)
+	}
+
+	switch v := results["count"].(type) {
+	case nil:
+		return 0, nil
+	case int64:
+		return v, nil
+	case float64:
+		return int64(v), nil
+	default:
+		return 0, fmt.Errorf("count %s: unexpected count type: %T", schema.Collection, v)
+	}
+}
+
+// listQuery builds the query for a page of a user's items: the filters in
+// opts, the sort with a document ID tiebreak so cursors are stable, and
+// the cursor position. opts must have been normalized against schema. One
+// extra document is requested so listPage can tell whether more follow.
+func listQuery(client *firestore.Client, schema model.ListSchema, userID string, pageLimit int, opts *model.ListOptions, fields []string) firestore.Query {
+	sort := schema.Sorts[opts.Sort]
+	dir := firestore.Desc
+	if opts.Order == model.SortAsc {
+		dir = firestore.Asc
+	}
+
+	q := listFilter(client, schema, userID, opts).OrderBy(sort.Field, dir).OrderBy(firestore.DocumentID, dir)
+	if c := opts.Cursor; c != nil {
+		q = q.StartAfter(c.SortValue(sort), c.ID)
+	}
+
+	// The sort field is always read so the next cursor can be built
+	return q.Limit(pageLimit + 1).Select(appendMissing(fields, sort.Field)...)
+}
+
+// listPage runs a query built by listQuery and returns at most pageLimit
+// documents, plus the cursor for the following page or nil on the last one.
+func listPage(ctx context.Context, q firestore.Query, schema model.ListSchema, pageLimit int, opts *model.ListOptions) ([]*firestore.DocumentSnapshot, *model.Cursor, error) {
+	iter := q.Documents(ctx)
+	defer iter.Stop()
+
+	var docs []*firestore.DocumentSnapshot
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("iterate %s: %w", schema.Collection, err)
+		}
+		docs = append(docs, doc)
+	}
+	if len(docs) <= pageLimit {
+		return docs, nil, nil
+	}
+
+	docs = docs[:pageLimit]
+	last := docs[pageLimit-1]
+	value, err := last.DataAt(schema.Sorts[opts.Sort].Field)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read %s cursor value: %w", schema.Collection, err)
+	}
+
+	next := &model.Cursor{Sort: opts.Sort, Order: opts.Order, Filter: opts.FilterKey(), ID: last.Ref.ID}
+	switch v := value.(type) {
+	case time.Time:
+		next.Value = v.UTC().Format(time.RFC3339Nano)
+	case string:
+		next.Value = v
+	default:
+		return nil, nil, fmt.Errorf("read %s cursor value: unexpected type %T", schema.Collection, value)
+	}
+	return docs, next, nil
+}
+
+// appendMissing returns paths with extra appended if it is not already
+// present, without modifying paths.
+func appendMissing(paths []string, extra string) []string {
+	for _, p := range paths {
+		if p == extra {
+			return paths
+		}
+	}
+	return append(append([]string{}, paths...), extra)
+}
+
+// Index is a Firestore composite index definition, as it appears in
+// firestore.indexes.json.
+type Index struct {
+	CollectionGroup string       `json:"collectionGroup"`
+	QueryScope      string       `json:"queryScope"`
+	Fields          []IndexField `json:"fields"`
+}
+
+// IndexField is one field of a composite index. Exactly one of Order and
+// ArrayConfig is set.
+type IndexField struct {
+	FieldPath   string `json:"fieldPath"`
+	Order       string `json:"order,omitempty"`
+	ArrayConfig string `json:"arrayConfig,omitempty"`
+}
+
+// ListIndexes returns the composite indexes every combination of filters,
+// sort key and order in ListSchemas needs. The createdAt range filters add
+// none, since they are only allowed when sorting by createdAt.
+func ListIndexes() []Index {
+	var indexes []Index
+	for _, schema := range ListSchemas {
+		// Each subset of the optional equality filters
+		var filterSets [][]IndexField
+		filterSets = append(filterSets, nil)
+		if schema.PublicField != "" {
+			for _, set := range filterSets {
+				filterSets = append(filterSets, append(append([]IndexField{}, set...), IndexField{FieldPath: schema.PublicField, Order: "ASCENDING"}))
+			}
+		}
+		if schema.TagField != "" {
+			for _, set := range filterSets {
+				filterSets = append(filterSets, append(append([]IndexField{}, set...), IndexField{FieldPath: schema.TagField, ArrayConfig: "CONTAINS"}))
+			}
+		}
+
+		for _, key := range schema.SortKeys() {
+			for _, order := range []string{"ASCENDING", "DESCENDING"} {
+				for _, filters := range filterSets {
+					fields := []IndexField{{FieldPath: "userId", Order: "ASCENDING"}}
+					fields = append(fields, filters...)
+					fields = append(fields, IndexField{FieldPath: schema.Sorts[key].Field, Order: order})
+					indexes = append(indexes, Index{
+						CollectionGroup: schema.Collection,
+						QueryScope:      "COLLECTION",
+						Fields:          fields,
+					})
+				}
+			}
+		}
+	}
+	return indexes
+}
@@ -7,13 +7,14 @@ import (
 
 	"cloud.google.com/go/firestore"
 	"github.com/pandasWhoCode/paintbar/internal/model"
-	"google.golang.org/api/iterator"
 )
 
 // NFTRepository defines the interface for NFT persistence operations.
 type NFTRepository interface {
 	GetByID(ctx context.Context, nftID string) (*model.NFT, error)
-	List(ctx context.Context, userID string, limit int, startAfter string) ([]*model.NFT, error)
+	GetByIDs(ctx context.Context, nftIDs []string) ([]*model.NFT, error)
+	List(ctx context.Context, userID string, limit int, opts *model.ListOptions, fields []string) ([]*model.NFT, *model.Cursor, error)
+	CountList(ctx context.Context, userID string, opts *model.ListOptions) (int64, error)
 	Count(ctx context.Context, userID string) (int64, error)
 	Create(ctx context.Context, nft *model.NFT) (string, error)
 	Update(ctx context.Context, nftID string, updates map[string]interface{}) error
@@ -45,43 +46,51 @@ func (r *firestoreNFTRepo) GetByID(ctx context.Context, nftID string) (*model.NF
 	return &nft, nil
 }
 
-// List retrieves NFTs for a user with cursor pagination.
-func (r *firestoreNFTRepo) List(ctx context.Context, userID string, pageLimit int, startAfter string) ([]*model.NFT, error) {
-	q := r.client.Collection("nfts").
-		Where("userId", "==", userID).
-		OrderBy("createdAt", firestore.Desc).
-		Limit(pageLimit)
-
-	if startAfter != "" {
-		cursorDoc, err := r.client.Collection("nfts").Doc(startAfter).Get(ctx)
-		if err != nil {
-			return []*model.NFT{}, nil
+// GetByIDs retrieves the NFTs with the given IDs in one batched read.
+// IDs with no nft are skipped.
+func (r *firestoreNFTRepo) GetByIDs(ctx context.Context, nftIDs []string) ([]*model.NFT, error) {
+	docs, err := getDocs(ctx, r.client, "nfts", nftIDs)
+	if err != nil {
+		return nil, err
+	}
+	nfts := make([]*model.NFT, 0, len(docs))
+	for _, doc := range docs {
+		var nft model.NFT
+		if err := doc.DataTo(&nft); err != nil {
+			return nil, fmt.Errorf("decode nft %s: %w", doc.Ref.ID, err)
 		}
-		q = q.StartAfter(cursorDoc)
+		nft.ID = doc.Ref.ID
+		nfts = append(nfts, &nft)
 	}
+	return nfts, nil
+}
 
-	iter := q.Documents(ctx)
-	defer iter.Stop()
-
-	var nfts []*model.NFT
-	for {
-		doc, err := iter.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			return nil, fmt.Errorf("iterate nfts: %w", err)
-		}
+// List retrieves a page of a user's NFTs per opts (normalized against
+// model.NFTListSchema) and the cursor for the next page. Only the given
+// field paths are read.
+func (r *firestoreNFTRepo) List(ctx context.Context, userID string, pageLimit int, opts *model.ListOptions, fields []string) ([]*model.NFT, *model.Cursor, error) {
+	q := listQuery(r.client, model.NFTListSchema, userID, pageLimit, opts, fields)
+	docs, next, err := listPage(ctx, q, model.NFTListSchema, pageLimit, opts)
+	if err != nil {
+		return nil, nil, err
+	}
 
+	nfts := make([]*model.NFT, 0, len(docs))
+	for _, doc := range docs {
 		var nft model.NFT
 		if err := doc.DataTo(&nft); err != nil {
-			return nil, fmt.Errorf("decode nft: %w", err)
+			return nil, nil, fmt.Errorf("decode nft: %w", err)
 		}
 		nft.ID = doc.Ref.ID
 		nfts = append(nfts, &nft)
 	}
 
-	return nfts, nil
+	return nfts, next, nil
+}
+
+// CountList counts the NFTs List would return across all pages.
+func (r *firestoreNFTRepo) CountList(ctx context.Context, userID string, opts *model.ListOptions) (int64, error) {
+	return listCount(ctx, r.client, model.NFTListSchema, userID, opts)
 }
 
 // Count returns the total number of NFTs for a user.
@@ -107,19 +116,20 @@ func (r *firestoreNFTRepo) Count(ctx context.Context, userID string) (int64, err
 	}
 }
 
-// Create adds a new NFT to Firestore and returns the generated document ID.
+// Create adds a new NFT to Firestore, increments the owner's counter and
+// returns the generated document ID.
 func (r *firestoreNFTRepo) Create(ctx context.Context, nft *model.NFT) (string, error) {
 	now := time.Now()
 	nft.CreatedAt = now
 	nft.UpdatedAt = now
 
-	ref, _, err := r.client.Collection("nfts").Add(ctx, nft)
+	id, err := createCounted(ctx, r.client, "nfts", "nftCount", nft.UserID, nft)
 	if err != nil {
 		return "", fmt.Errorf("create nft: %w", err)
 	}
 
-	nft.ID = ref.ID
-	return ref.ID, nil
+	nft.ID = id
+	return id, nil
 }
 
 // Update applies a partial update to an NFT document.
@@ -132,9 +142,10 @@ func (r *firestoreNFTRepo) Update(ctx context.Context, nftID string, updates map
 	return nil
 }
 
-// Delete removes an NFT document from Firestore.
+// Delete removes an NFT document from Firestore and decrements the owner's
+// counter.
 func (r *firestoreNFTRepo) Delete(ctx context.Context, nftID string) error {
-	_, err := r.client.Collection("nfts").Doc(nftID).Delete(ctx)
+	err := deleteCounted(ctx, r.client, "nfts", "nftCount", nftID)
 	if err != nil {
 		return fmt.Errorf("delete nft %s: %w", nftID, err)
 	}
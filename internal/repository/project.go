@@ -13,9 +13,11 @@ import (
 // ProjectRepository defines the interface for project persistence operations.
 type ProjectRepository interface {
 	GetByID(ctx context.Context, projectID string) (*model.Project, error)
+	GetByIDs(ctx context.Context, projectIDs []string) ([]*model.Project, error)
 	FindByContentHash(ctx context.Context, userID, contentHash string) (*model.Project, error)
 	FindByTitle(ctx context.Context, userID, title string) (*model.Project, error)
-	List(ctx context.Context, userID string, limit int, startAfter string) ([]*model.Project, error)
+	List(ctx context.Context, userID string, limit int, opts *model.ListOptions, fields []string) ([]*model.Project, *model.Cursor, error)
+	CountList(ctx context.Context, userID string, opts *model.ListOptions) (int64, error)
 	Count(ctx context.Context, userID string) (int64, error)
 	Create(ctx context.Context, project *model.Project) (string, error)
 	Update(ctx context.Context, projectID string, update *model.ProjectUpdate) error
@@ -48,6 +50,25 @@ func (r *firestoreProjectRepo) GetByID(ctx context.Context, projectID string) (*
 	return &project, nil
 }
 
+// GetByIDs retrieves the projects with the given IDs in one batched read.
+// IDs with no project are skipped.
+func (r *firestoreProjectRepo) GetByIDs(ctx context.Context, projectIDs []string) ([]*model.Project, error) {
+	docs, err := getDocs(ctx, r.client, "projects", projectIDs)
+	if err != nil {
+		return nil, err
+	}
+	projects := make([]*model.Project, 0, len(docs))
+	for _, doc := range docs {
+		var project model.Project
+		if err := doc.DataTo(&project); err != nil {
+			return nil, fmt.Errorf("decode project %s: %w", doc.Ref.ID, err)
+		}
+		project.ID = doc.Ref.ID
+		projects = append(projects, &project)
+	}
+	return projects, nil
+}
+
 // FindByContentHash looks up a project by user ID and content hash for deduplication.
 // Returns nil, nil if no matching project is found.
 func (r *firestoreProjectRepo) FindByContentHash(ctx context.Context, userID, contentHash string) (*model.Project, error) {
@@ -100,46 +121,33 @@ func (r *firestoreProjectRepo) FindByTitle(ctx context.Context, userID, title st
 	return &project, nil
 }
 
-// List retrieves projects for a user, ordered by createdAt descending, with cursor pagination.
-func (r *firestoreProjectRepo) List(ctx context.Context, userID string, pageLimit int, startAfter string) ([]*model.Project, error) {
-	q := r.client.Collection("projects").
-		Where("userId", "==", userID).
-		OrderBy("createdAt", firestore.Desc).
-		Limit(pageLimit)
-
-	// Cursor-based pagination: start after a specific document
-	if startAfter != "" {
-		cursorDoc, err := r.client.Collection("projects").Doc(startAfter).Get(ctx)
-		if err != nil {
-			// If cursor doc doesn't exist, return empty results rather than
-			// exposing whether a document ID exists (information disclosure).
-			return []*model.Project{}, nil
-		}
-		q = q.StartAfter(cursorDoc)
+// List retrieves a page of a user's projects, sorted, filtered and positioned
+// by opts, and returns the cursor for the next page (nil on the last). opts
+// must be normalized against model.ProjectListSchema. Only the given field
+// paths are read; an empty list reads document IDs only.
+func (r *firestoreProjectRepo) List(ctx context.Context, userID string, pageLimit int, opts *model.ListOptions, fields []string) ([]*model.Project, *model.Cursor, error) {
+	q := listQuery(r.client, model.ProjectListSchema, userID, pageLimit, opts, fields)
+	docs, next, err := listPage(ctx, q, model.ProjectListSchema, pageLimit, opts)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	iter := q.Documents(ctx)
-	defer iter.Stop()
-
-	var projects []*model.Project
-	for {
-		doc, err := iter.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			return nil, fmt.Errorf("iterate projects: %w", err)
-		}
-
+	projects := make([]*model.Project, 0, len(docs))
+	for _, doc := range docs {
 		var p model.Project
 		if err := doc.DataTo(&p); err != nil {
-			return nil, fmt.Errorf("decode project: %w", err)
+			return nil, nil, fmt.Errorf("decode project: %w", err)
 		}
 		p.ID = doc.Ref.ID
 		projects = append(projects, &p)
 	}
 
-	return projects, nil
+	return projects, next, nil
+}
+
+// CountList counts the projects List would return across all pages.
+func (r *firestoreProjectRepo) CountList(ctx context.Context, userID string, opts *model.ListOptions) (int64, error) {
+	return listCount(ctx, r.client, model.ProjectListSchema, userID, opts)
 }
 
 // Count returns the total number of projects for a user using Firestore aggregation.
@@ -167,19 +175,20 @@ func (r *firestoreProjectRepo) Count(ctx context.Context, userID string) (int64,
 	}
 }
 
-// Create adds a new project to Firestore and returns the generated document ID.
+// Create adds a new project to Firestore, increments the owner's counter and
+// returns the generated document ID.
 func (r *firestoreProjectRepo) Create(ctx context.Context, project *model.Project) (string, error) {
 	now := time.Now()
 	project.CreatedAt = now
 	project.UpdatedAt = now
 
-	ref, _, err := r.client.Collection("projects").Add(ctx, project)
+	id, err := createCounted(ctx, r.client, "projects", "projectCount", project.UserID, project)
 	if err != nil {
 		return "", fmt.Errorf("create project: %w", err)
 	}
 
-	project.ID = ref.ID
-	return ref.ID, nil
+	project.ID = id
+	return id, nil
 }
 
 // Update applies a partial update to a project document.
@@ -202,9 +211,10 @@ func (r *firestoreProjectRepo) UpdateRaw(ctx context.Context, projectID string,
 	return nil
 }
 
-// Delete removes a project document from Firestore.
+// Delete removes a project document from Firestore and decrements the owner's
+// counter.
 func (r *firestoreProjectRepo) Delete(ctx context.Context, projectID string) error {
-	_, err := r.client.Collection("projects").Doc(projectID).Delete(ctx)
+	err := deleteCounted(ctx, r.client, "projects", "projectCount", projectID)
 	if err != nil {
 		return fmt.Errorf("delete project %s: %w", projectID, err)
 	}
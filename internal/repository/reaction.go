@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+)
+
+// ReactionRepository defines the interface for gallery reaction persistence.
+// Aggregate counts live on the gallery item (reactionCounts) and are read
+// through GalleryRepository.
+type ReactionRepository interface {
+	Add(ctx context.Context, itemID, uid, reaction string) error
+	Remove(ctx context.Context, itemID, uid, reaction string) error
+	ListByUser(ctx context.Context, itemID, uid string) ([]string, error)
+}
+
+// firestoreReactionRepo implements ReactionRepository using the
+// gallery/{itemId}/reactions subcollection, one document per
+// (user, reaction) pair.
+type firestoreReactionRepo struct {
+	client *firestore.Client
+}
+
+// NewReactionRepository creates a new Firestore-backed ReactionRepository.
+func NewReactionRepository(client *firestore.Client) ReactionRepository {
+	return &firestoreReactionRepo{client: client}
+}
+
+// reactionDocID returns the deterministic document ID for a user's reaction.
+func reactionDocID(uid, reaction string) string {
+	return uid + "_" + reaction
+}
+
+// Add records a reaction and increments the aggregate count in one
+// transaction. Adding a reaction the user already has is a no-op.
+func (r *firestoreReactionRepo) Add(ctx context.Context, itemID, uid, reaction string) error {
+	itemRef := r.client.Collection("gallery").Doc(itemID)
+	ref := itemRef.Collection("reactions").Doc(reactionDocID(uid, reaction))
+
+	return r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if err != nil && !isNotFoundError(err) {
+			return fmt.Errorf("check reaction: %w", err)
+		}
+		if err == nil && doc.Exists() {
+			return nil
+		}
+
+		if err := tx.Set(ref, map[string]interface{}{
+			"userId":    uid,
+			"reaction":  reaction,
+			"createdAt": time.Now(),
+		}); err != nil {
+			return fmt.Errorf("set reaction: %w", err)
+		}
+		if err := tx.Update(itemRef, []firestore.Update{
+			{FieldPath: firestore.FieldPath{"reactionCounts", reaction}, Value: firestore.Increment(1)},
+		}); err != nil {
+			return fmt.Errorf("increment reaction count: %w", err)
+		}
+		return nil
+	})
+}
+
+// Remove deletes a reaction and decrements the aggregate count in one
+// transaction. Removing a reaction the user does not have is a no-op.
+func (r *firestoreReactionRepo) Remove(ctx context.Context, itemID, uid, reaction string) error {
+	itemRef := r.client.Collection("gallery").Doc(itemID)
+	ref := itemRef.Collection("reactions").Doc(reactionDocID(uid, reaction))
+
+	return r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if err != nil {
+			if isNotFoundError(err) {
+				return nil
+			}
+			return fmt.Errorf("check reaction: %w", err)
+		}
+		if !doc.Exists() {
+			return nil
+		}
+
+		if err := tx.Delete(ref); err != nil {
+			return fmt.Errorf("delete reaction: %w", err)
+		}
+		if err := tx.Update(itemRef, []firestore.Update{
+			{FieldPath: firestore.FieldPath{"reactionCounts", reaction}, Value: firestore.Increment(-1)},
+		}); err != nil {
+			return fmt.Errorf("decrement reaction count: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListByUser returns the reaction keys the user has left on an item.
+func (r *firestoreReactionRepo) ListByUser(ctx context.Context, itemID, uid string) ([]string, error) {
+	iter := r.client.Collection("gallery").Doc(itemID).Collection("reactions").
+		Where("userId", "==", uid).
+		Documents(ctx)
+	defer iter.Stop()
+
+	reactions := []string{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("iterate reactions: %w", err)
+		}
+		if key, ok := doc.Data()["reaction"].(string); ok {
+			reactions = append(reactions, key)
+		}
+	}
+	return reactions, nil
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"google.golang.org/api/iterator"
+)
+
+// ReportRepository defines the interface for the moderation queue.
+type ReportRepository interface {
+	GetByID(ctx context.Context, reportID string) (*model.Report, error)
+	List(ctx context.Context, status string, limit int, startAfter string) ([]*model.Report, error)
+	Create(ctx context.Context, report *model.Report) (string, error)
+	Resolve(ctx context.Context, reportID, status, resolvedBy, note string) error
+}
+
+// firestoreReportRepo implements ReportRepository using the top-level
+// reports collection.
+type firestoreReportRepo struct {
+	client *firestore.Client
+}
+
+// NewReportRepository creates a new Firestore-backed ReportRepository.
+func NewReportRepository(client *firestore.Client) ReportRepository {
+	return &firestoreReportRepo{client: client}
+}
+
+// reportDocID returns the deterministic document ID for a report, so a
+// user can file at most one report per target.
+func reportDocID(reporterID, targetType, targetID string) string {
+	return reporterID + "_" + targetType + "_" + targetID
+}
+
+// GetByID retrieves a report by its document ID.
+func (r *firestoreReportRepo) GetByID(ctx context.Context, reportID string) (*model.Report, error) {
+	doc, err := r.client.Collection("reports").Doc(reportID).Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get report %s: %w", reportID, err)
+	}
+
+	var report model.Report
+	if err := doc.DataTo(&report); err != nil {
+		return nil, fmt.Errorf("decode report %s: %w", reportID, err)
+	}
+	report.ID = doc.Ref.ID
+	return &report, nil
+}
+
+// List retrieves reports with the given status, oldest first, with cursor
+// pagination.
+func (r *firestoreReportRepo) List(ctx context.Context, status string, pageLimit int, startAfter string) ([]*model.Report, error) {
+	q := r.client.Collection("reports").
+		Where("status", "==", status).
+		OrderBy("createdAt", firestore.Asc).
+		Limit(pageLimit)
+
+	if startAfter != "" {
+		cursorDoc, err := r.client.Collection("reports").Doc(startAfter).Get(ctx)
+		if err != nil {
+			return []*model.Report{}, nil
+		}
+		q = q.StartAfter(cursorDoc)
+	}
+
+	iter := q.Documents(ctx)
+	defer iter.Stop()
+
+	var reports []*model.Report
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("iterate reports: %w", err)
+		}
+
+		var report model.Report
+		if err := doc.DataTo(&report); err != nil {
+			return nil, fmt.Errorf("decode report: %w", err)
+		}
+		report.ID = doc.Ref.ID
+		reports = append(reports, &report)
+	}
+
+	return reports, nil
+}
+
+// Create adds a report to the queue and returns its document ID. Reporting
+// the same target twice returns an "already exists" error.
+func (r *firestoreReportRepo) Create(ctx context.Context, report *model.Report) (string, error) {
+	now := time.Now()
+	report.CreatedAt = now
+	report.UpdatedAt = now
+
+	id := reportDocID(report.ReporterID, report.TargetType, report.TargetID)
+	_, err := r.client.Collection("reports").Doc(id).Create(ctx, report)
+	if err != nil {
+		if strings.Contains(err.Error(), "AlreadyExists") {
+			return "", fmt.Errorf("report already exists for this %s", report.TargetType)
+		}
+		return "", fmt.Errorf("create report: %w", err)
+	}
+
+	report.ID = id
+	return id, nil
+}
+
+// Resolve closes a report with the given status.
+func (r *firestoreReportRepo) Resolve(ctx context.Context, reportID, status, resolvedBy, note string) error {
+	_, err := r.client.Collection("reports").Doc(reportID).Update(ctx, []firestore.Update{
+		{Path: "status", Value: status},
+		{Path: "resolvedBy", Value: resolvedBy},
+		{Path: "note", Value: note},
+		{Path: "updatedAt", Value: time.Now()},
+	})
+	if err != nil {
+		return fmt.Errorf("resolve report %s: %w", reportID, err)
+	}
+	return nil
+}
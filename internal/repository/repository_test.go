@@ -1,11 +1,18 @@
 package repository
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"cloud.google.com/go/firestore"
+	"github.com/pandasWhoCode/paintbar/internal/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // --- Helper function unit tests (no external deps) ---
@@ -119,3 +126,122 @@ func TestValidatePathSegment(t *testing.T) {
 	assert.Error(t, validatePathSegment(".."))
 	assert.Error(t, validatePathSegment("a..b"))
 }
+
+// --- JSONL audit logger tests ---
+
+func TestJSONLAuditLogger_LogAndList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger, err := NewJSONLAuditLogger(path)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	for _, e := range []*model.AuditEntry{
+		{ActorUID: "u1", Action: model.AuditActionUsernameClaim, ResourceType: "username", ResourceID: "painter"},
+		{ActorUID: "u2", Action: model.AuditActionNFTCreate, ResourceType: "nft", ResourceID: "n1"},
+		{ActorUID: "u1", Action: model.AuditActionProfileUpdate, ResourceType: "user", ResourceID: "u1",
+			Changes: map[string]model.AuditChange{"bio": {Before: "old", After: "new"}}},
+	} {
+		require.NoError(t, logger.Log(ctx, e))
+		assert.NotEmpty(t, e.ID)
+		assert.False(t, e.CreatedAt.IsZero())
+	}
+
+	all, err := logger.List(ctx, "", 10, "")
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, model.AuditActionProfileUpdate, all[0].Action, "newest first")
+	assert.Equal(t, "new", all[0].Changes["bio"].After)
+
+	mine, err := logger.List(ctx, "u1", 1, "")
+	require.NoError(t, err)
+	require.Len(t, mine, 1)
+	next, err := logger.List(ctx, "u1", 1, mine[0].ID)
+	require.NoError(t, err)
+	require.Len(t, next, 1)
+	assert.Equal(t, model.AuditActionUsernameClaim, next[0].Action)
+}
+
+func TestJSONLAuditLogger_RequiresPath(t *testing.T) {
+	_, err := NewJSONLAuditLogger("")
+	assert.Error(t, err)
+}
+
+func TestJSONLAuditLogger_FilePermissions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	_, err := NewJSONLAuditLogger(path)
+	require.NoError(t, err)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestListIndexes(t *testing.T) {
+	indexes := ListIndexes()
+	// projects: 3 sorts × 2 orders × 4 filter subsets; gallery: 2 × 2 × 2;
+	// NFTs: 3 × 2 × 2
+	assert.Len(t, indexes, 24+8+12)
+	for _, idx := range indexes {
+		assert.Equal(t, "userId", idx.Fields[0].FieldPath)
+		assert.NotEmpty(t, idx.Fields[len(idx.Fields)-1].Order, "sort field comes last")
+	}
+}
+
+func TestFirestoreIndexes_CoverListSchemas(t *testing.T) {
+	raw, err := os.ReadFile(filepath.Join("..", "..", "firestore.indexes.json"))
+	require.NoError(t, err)
+	var file struct {
+		Indexes []Index `json:"indexes"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &file))
+
+	for _, want := range ListIndexes() {
+		assert.Contains(t, file.Indexes, want, "firestore.indexes.json is stale; run go run ./cmd/indexgen")
+	}
+}
+
+func TestAppendMissing(t *testing.T) {
+	paths := []string{"title"}
+	assert.Equal(t, []string{"title"}, appendMissing(paths, "title"))
+	assert.Equal(t, []string{"title", "createdAt"}, appendMissing(paths, "createdAt"))
+	assert.Equal(t, []string{"createdAt"}, appendMissing(nil, "createdAt"))
+	assert.Equal(t, []string{"title"}, paths)
+}
+
+// --- Emulator integration tests ---
+
+// emulatorClient returns a Firestore client connected to the emulator, or
+// skips the test when FIRESTORE_EMULATOR_HOST is unset or -short is given.
+func emulatorClient(t *testing.T) *firestore.Client {
+	t.Helper()
+	if testing.Short() || os.Getenv("FIRESTORE_EMULATOR_HOST") == "" {
+		t.Skip("requires the Firestore emulator (FIRESTORE_EMULATOR_HOST)")
+	}
+	client, err := firestore.NewClient(context.Background(), "demo-paintbar")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestGalleryRepository_Delete_RemovesCommentsAndReactions(t *testing.T) {
+	client := emulatorClient(t)
+	ctx := context.Background()
+	gallery := NewGalleryRepository(client)
+	comments := NewCommentRepository(client)
+	reactions := NewReactionRepository(client)
+
+	itemID, err := gallery.Create(ctx, &model.GalleryItem{UserID: "owner", Name: "Art"})
+	require.NoError(t, err)
+	_, err = comments.Create(ctx, itemID, &model.Comment{UserID: "fan", Body: "nice"})
+	require.NoError(t, err)
+	require.NoError(t, reactions.Add(ctx, itemID, "fan", "heart"))
+
+	require.NoError(t, gallery.Delete(ctx, itemID))
+
+	item := client.Collection("gallery").Doc(itemID)
+	for _, name := range []string{"comments", "reactions"} {
+		docs, err := item.Collection(name).Documents(ctx).GetAll()
+		require.NoError(t, err)
+		assert.Empty(t, docs, "%s left behind", name)
+	}
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"google.golang.org/api/iterator"
+)
+
+// StatsRepository defines the interface for site-wide usage statistics and
+// the upkeep of per-user counters.
+type StatsRepository interface {
+	Usage(ctx context.Context) (*model.UsageStats, error)
+	// RecountUser recomputes uid's counters from the documents they count,
+	// storing the results if apply is set and they differ.
+	RecountUser(ctx context.Context, uid string, apply bool) (*model.CounterRepair, error)
+	// ListUserIDs returns up to limit user document IDs after startAfter,
+	// in ID order.
+	ListUserIDs(ctx context.Context, limit int, startAfter string) ([]string, error)
+}
+
+// firestoreStatsRepo implements StatsRepository with Firestore count
+// aggregations, which are billed per batch of index entries rather than
+// per document read.
+type firestoreStatsRepo struct {
+	client *firestore.Client
+}
+
+// NewStatsRepository creates a new Firestore-backed StatsRepository.
+func NewStatsRepository(client *firestore.Client) StatsRepository {
+	return &firestoreStatsRepo{client: client}
+}
+
+// Usage counts users, projects, gallery items, comments, NFTs and open reports.
+func (r *firestoreStatsRepo) Usage(ctx context.Context) (*model.UsageStats, error) {
+	stats := &model.UsageStats{GeneratedAt: time.Now()}
+
+	counts := []struct {
+		what  string
+		query firestore.Query
+		dst   *int64
+	}{
+		{"users", r.client.Collection("users").Query, &stats.Users},
+		{"projects", r.client.Collection("projects").Query, &stats.Projects},
+		{"gallery items", r.client.Collection("gallery").Query, &stats.GalleryItems},
+		{"comments", r.client.CollectionGroup("comments").Query, &stats.Comments},
+		{"nfts", r.client.Collection("nfts").Query, &stats.NFTs},
+		{"open reports", r.client.Collection("reports").Where("status", "==", model.ReportStatusOpen), &stats.OpenReports},
+	}
+	for _, c := range counts {
+		n, err := countQuery(ctx, c.query, c.what)
+		if err != nil {
+			return nil, err
+		}
+		*c.dst = n
+	}
+
+	return stats, nil
+}
+
+// RecountUser counts uid's projects, gallery items, NFTs and follow edges
+// with aggregation queries and compares them with the counters on the user
+// document. Reads and the write run in one transaction, so a document
+// created or deleted meanwhile cannot be lost from the repaired counters.
+func (r *firestoreStatsRepo) RecountUser(ctx context.Context, uid string, apply bool) (*model.CounterRepair, error) {
+	userRef := r.client.Collection("users").Doc(uid)
+
+	var repair *model.CounterRepair
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		stored := &model.UserStats{}
+		doc, err := tx.Get(userRef)
+		if err != nil && !isNotFoundError(err) {
+			return fmt.Errorf("get user %s: %w", uid, err)
+		}
+		if err == nil {
+			var user model.User
+			if err := doc.DataTo(&user); err != nil {
+				return fmt.Errorf("decode user %s: %w", uid, err)
+			}
+			stored = user.Stats()
+		}
+
+		actual := &model.UserStats{}
+		counts := []struct {
+			what  string
+			query firestore.Query
+			dst   *int64
+		}{
+			{"projects", r.client.Collection("projects").Where("userId", "==", uid), &actual.Projects},
+			{"gallery items", r.client.Collection("gallery").Where("userId", "==", uid), &actual.GalleryItems},
+			{"nfts", r.client.Collection("nfts").Where("userId", "==", uid), &actual.NFTs},
+			{"followers", userRef.Collection("followers").Query, &actual.Followers},
+			{"following", userRef.Collection("following").Query, &actual.Following},
+		}
+		for _, c := range counts {
+			n, err := countAggregation(ctx, c.query.NewAggregationQuery().Transaction(tx), c.what)
+			if err != nil {
+				return err
+			}
+			*c.dst = n
+		}
+
+		repair = &model.CounterRepair{UID: uid, Stored: stored, Actual: actual}
+		if !apply || !repair.Drifted() {
+			return nil
+		}
+		return tx.Set(userRef, map[string]interface{}{
+			"projectCount":   actual.Projects,
+			"galleryCount":   actual.GalleryItems,
+			"nftCount":       actual.NFTs,
+			"followerCount":  actual.Followers,
+			"followingCount": actual.Following,
+		}, firestore.MergeAll)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("recount user %s: %w", uid, err)
+	}
+	return repair, nil
+}
+
+// ListUserIDs pages through the user documents by ID, reading no fields.
+func (r *firestoreStatsRepo) ListUserIDs(ctx context.Context, limit int, startAfter string) ([]string, error) {
+	q := r.client.Collection("users").Select().OrderBy(firestore.DocumentID, firestore.Asc).Limit(limit)
+	if startAfter != "" {
+		q = q.StartAfter(startAfter)
+	}
+	iter := q.Documents(ctx)
+	defer iter.Stop()
+
+	var ids []string
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("list users: %w", err)
+		}
+		ids = append(ids, doc.Ref.ID)
+	}
+	return ids, nil
+}
+
+// countQuery runs a count aggregation over q.
+func countQuery(ctx context.Context, q firestore.Query, what string) (int64, error) {
+	return countAggregation(ctx, q.NewAggregationQuery(), what)
+}
+
+// countAggregation runs a count aggregation query.
+func countAggregation(ctx context.Context, q *firestore.AggregationQuery, what string) (int64, error) {
+	results, err := q.WithCount("count").Get(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("count %s: %w", what, err)
+	}
+
+	count, ok := results["count"]
+	if !ok {
+		return 0, nil
+	}
+
+	switch v := count.(type) {
+	case *firestorepb.Value:
+		return v.GetIntegerValue(), nil
+	case int64:
+		return v, nil
+	default:
+		return 0, fmt.Errorf("count %s: unexpected count type: %T", what, count)
+	}
+}
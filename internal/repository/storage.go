@@ -2,13 +2,16 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"golang.org/x/oauth2/google"
 )
 
@@ -29,7 +32,12 @@ func NewStorageService(bucketName, emulatorHost string) *StorageService {
 	return &StorageService{
 		bucketName:   bucketName,
 		emulatorHost: emulatorHost,
-		httpClient:   &http.Client{Timeout: 60 * time.Second},
+		// Each call is a client span, and carries the trace to Storage
+		// in the traceparent header
+		httpClient: &http.Client{
+			Timeout:   60 * time.Second,
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+		},
 	}
 }
 
@@ -139,6 +147,101 @@ func (s *StorageService) ReadObject(ctx context.Context, objectPath string) (io.
 	return resp.Body, nil
 }
 
+// ReadObjectFrom downloads an object starting at offset using an HTTP Range
+// request. The caller must close the returned ReadCloser when done.
+func (s *StorageService) ReadObjectFrom(ctx context.Context, objectPath string, offset int64) (io.ReadCloser, error) {
+	encoded := url.PathEscape(objectPath)
+	downloadURL := fmt.Sprintf("%s/v0/b/%s/o/%s?alt=media",
+		s.baseURL(), s.bucketName, encoded)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create download request: %w", err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	if err := s.addAuth(ctx, req); err != nil {
+		return nil, fmt.Errorf("auth for download: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("execute download request: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, fmt.Errorf("object not found: %s", objectPath)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("download failed (HTTP %d): %s", resp.StatusCode, string(body))
+	}
+
+	// A server that ignores Range returns the whole object with 200
+	if offset > 0 && resp.StatusCode == http.StatusOK {
+		if _, err := io.CopyN(io.Discard, resp.Body, offset); err != nil {
+			resp.Body.Close()
+			return nil, fmt.Errorf("skip to offset %d: %w", offset, err)
+		}
+	}
+	return resp.Body, nil
+}
+
+// ObjectAttrs is the subset of Storage object metadata the API uses.
+type ObjectAttrs struct {
+	Size    int64
+	Updated time.Time
+}
+
+// StatObject fetches an object's size and last-modified time via the REST
+// API.
+func (s *StorageService) StatObject(ctx context.Context, objectPath string) (*ObjectAttrs, error) {
+	encoded := url.PathEscape(objectPath)
+	metaURL := fmt.Sprintf("%s/v0/b/%s/o/%s",
+		s.baseURL(), s.bucketName, encoded)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metaURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create metadata request: %w", err)
+	}
+
+	if err := s.addAuth(ctx, req); err != nil {
+		return nil, fmt.Errorf("auth for metadata: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("execute metadata request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("object not found: %s", objectPath)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("metadata request failed (HTTP %d): %s", resp.StatusCode, string(body))
+	}
+
+	// Firebase Storage reports size as a decimal string
+	var meta struct {
+		Size    string    `json:"size"`
+		Updated time.Time `json:"updated"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&meta); err != nil {
+		return nil, fmt.Errorf("decode metadata: %w", err)
+	}
+	size, err := strconv.ParseInt(meta.Size, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected object size %q", meta.Size)
+	}
+	return &ObjectAttrs{Size: size, Updated: meta.Updated}, nil
+}
+
 // ObjectExists checks whether an object exists at the given path via the REST API.
 func (s *StorageService) ObjectExists(ctx context.Context, objectPath string) (bool, error) {
 	encoded := url.PathEscape(objectPath)
@@ -170,6 +273,33 @@ func (s *StorageService) ObjectExists(ctx context.Context, objectPath string) (b
 	return true, nil
 }
 
+// HealthCheck verifies the bucket is reachable with the server's
+// credentials by listing at most one object; a missing bucket fails with 404.
+func (s *StorageService) HealthCheck(ctx context.Context) error {
+	listURL := fmt.Sprintf("%s/v0/b/%s/o?maxResults=1", s.baseURL(), s.bucketName)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, listURL, nil)
+	if err != nil {
+		return fmt.Errorf("create list request: %w", err)
+	}
+
+	if err := s.addAuth(ctx, req); err != nil {
+		return fmt.Errorf("auth for list: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("storage health check: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("storage health check failed (HTTP %d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
 // DeleteObject removes the object at the given path. Returns nil if the object
 // does not exist (idempotent).
 func (s *StorageService) DeleteObject(ctx context.Context, objectPath string) error {
@@ -215,6 +345,23 @@ func ProjectObjectPath(userID, contentHash string) (string, error) {
 	return fmt.Sprintf("projects/%s/%s.png", userID, contentHash), nil
 }
 
+// UploadChunkObjectPath returns the storage path for one chunk of a resumable
+// upload. Format: uploads/{userID}/{uploadID}/{offset}-{suffix}, with the
+// offset zero-padded so chunks list in order. The random suffix keeps two
+// concurrent writes of the same range from overwriting each other.
+func UploadChunkObjectPath(userID, uploadID string, offset int64, suffix string) (string, error) {
+	if err := validatePathSegment(userID); err != nil {
+		return "", fmt.Errorf("invalid userID: %w", err)
+	}
+	if err := validatePathSegment(uploadID); err != nil {
+		return "", fmt.Errorf("invalid uploadID: %w", err)
+	}
+	if err := validatePathSegment(suffix); err != nil {
+		return "", fmt.Errorf("invalid suffix: %w", err)
+	}
+	return fmt.Sprintf("uploads/%s/%s/%012d-%s", userID, uploadID, offset, suffix), nil
+}
+
 // validatePathSegment rejects values that could escape the intended storage prefix.
 func validatePathSegment(s string) error {
 	if s == "" {
@@ -11,6 +11,10 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 // newTestStorageService creates a StorageService pointing at the given test server.
@@ -180,6 +184,36 @@ func TestObjectExists_ServerError(t *testing.T) {
 	assert.Contains(t, err.Error(), "metadata check failed (HTTP 500)")
 }
 
+// --- HealthCheck ---
+
+func TestHealthCheck_BucketReachable(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/v0/b/test-bucket.firebasestorage.app/o", r.URL.Path)
+		assert.Equal(t, "1", r.URL.Query().Get("maxResults"))
+		assert.Equal(t, "Bearer owner", r.Header.Get("Authorization"))
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"prefixes":[],"items":[]}`))
+	}))
+	defer ts.Close()
+
+	svc := newTestStorageService(ts)
+	assert.NoError(t, svc.HealthCheck(context.Background()))
+}
+
+func TestHealthCheck_BucketMissing(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	svc := newTestStorageService(ts)
+	err := svc.HealthCheck(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "storage health check failed (HTTP 404)")
+}
+
 // --- DeleteObject ---
 
 func TestDeleteObject_Success(t *testing.T) {
@@ -220,6 +254,45 @@ func TestDeleteObject_ServerError(t *testing.T) {
 	assert.Contains(t, err.Error(), "delete failed (HTTP 500)")
 }
 
+// --- Tracing ---
+
+func TestStorage_PropagatesTraceContext(t *testing.T) {
+	tp, prop := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	rec := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer func() {
+		otel.SetTracerProvider(tp)
+		otel.SetTextMapPropagator(prop)
+	}()
+
+	var traceparent string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.Write([]byte("png"))
+	}))
+	defer ts.Close()
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "request")
+	rc, err := newTestStorageService(ts).ReadObject(ctx, "projects/uid1/hash1.png")
+	require.NoError(t, err)
+	rc.Close()
+	parent.End()
+
+	traceID := parent.SpanContext().TraceID().String()
+	assert.Contains(t, traceparent, traceID, "the request carries the caller's trace")
+
+	// The HTTP call is a client span under the caller's span
+	var client sdktrace.ReadOnlySpan
+	for _, s := range rec.Ended() {
+		if s.Parent().SpanID() == parent.SpanContext().SpanID() {
+			client = s
+		}
+	}
+	require.NotNil(t, client)
+	assert.Contains(t, traceparent, client.SpanContext().SpanID().String())
+}
+
 // --- baseURL ---
 
 func TestBaseURL_Emulator(t *testing.T) {
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"google.golang.org/api/iterator"
+)
+
+// APITokenRepository defines the interface for personal access token storage.
+type APITokenRepository interface {
+	GetByID(ctx context.Context, tokenID string) (*model.APIToken, error)
+	GetByHash(ctx context.Context, hash string) (*model.APIToken, error)
+	ListByUser(ctx context.Context, uid string) ([]*model.APIToken, error)
+	Create(ctx context.Context, token *model.APIToken) (string, error)
+	Delete(ctx context.Context, tokenID string) error
+	TouchLastUsed(ctx context.Context, tokenID string, at time.Time) error
+}
+
+// firestoreAPITokenRepo implements APITokenRepository using the top-level
+// apiTokens collection.
+type firestoreAPITokenRepo struct {
+	client *firestore.Client
+}
+
+// NewAPITokenRepository creates a new Firestore-backed APITokenRepository.
+func NewAPITokenRepository(client *firestore.Client) APITokenRepository {
+	return &firestoreAPITokenRepo{client: client}
+}
+
+// GetByID retrieves a token by its document ID.
+func (r *firestoreAPITokenRepo) GetByID(ctx context.Context, tokenID string) (*model.APIToken, error) {
+	doc, err := r.client.Collection("apiTokens").Doc(tokenID).Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get api token %s: %w", tokenID, err)
+	}
+
+	var token model.APIToken
+	if err := doc.DataTo(&token); err != nil {
+		return nil, fmt.Errorf("decode api token %s: %w", tokenID, err)
+	}
+	token.ID = doc.Ref.ID
+	return &token, nil
+}
+
+// GetByHash retrieves the token whose secret hashes to hash.
+func (r *firestoreAPITokenRepo) GetByHash(ctx context.Context, hash string) (*model.APIToken, error) {
+	iter := r.client.Collection("apiTokens").
+		Where("hash", "==", hash).
+		Limit(1).
+		Documents(ctx)
+	defer iter.Stop()
+
+	doc, err := iter.Next()
+	if err == iterator.Done {
+		return nil, fmt.Errorf("api token not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query api token: %w", err)
+	}
+
+	var token model.APIToken
+	if err := doc.DataTo(&token); err != nil {
+		return nil, fmt.Errorf("decode api token: %w", err)
+	}
+	token.ID = doc.Ref.ID
+	return &token, nil
+}
+
+// ListByUser retrieves all of a user's tokens, newest first.
+func (r *firestoreAPITokenRepo) ListByUser(ctx context.Context, uid string) ([]*model.APIToken, error) {
+	iter := r.client.Collection("apiTokens").
+		Where("uid", "==", uid).
+		OrderBy("createdAt", firestore.Desc).
+		Documents(ctx)
+	defer iter.Stop()
+
+	var tokens []*model.APIToken
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("iterate api tokens: %w", err)
+		}
+
+		var token model.APIToken
+		if err := doc.DataTo(&token); err != nil {
+			return nil, fmt.Errorf("decode api token: %w", err)
+		}
+		token.ID = doc.Ref.ID
+		tokens = append(tokens, &token)
+	}
+
+	return tokens, nil
+}
+
+// Create stores a new token and returns its document ID.
+func (r *firestoreAPITokenRepo) Create(ctx context.Context, token *model.APIToken) (string, error) {
+	token.CreatedAt = time.Now()
+
+	ref, _, err := r.client.Collection("apiTokens").Add(ctx, token)
+	if err != nil {
+		return "", fmt.Errorf("create api token: %w", err)
+	}
+	token.ID = ref.ID
+	return ref.ID, nil
+}
+
+// Delete removes a token, revoking it immediately.
+func (r *firestoreAPITokenRepo) Delete(ctx context.Context, tokenID string) error {
+	_, err := r.client.Collection("apiTokens").Doc(tokenID).Delete(ctx)
+	if err != nil {
+		return fmt.Errorf("delete api token %s: %w", tokenID, err)
+	}
+	return nil
+}
+
+// TouchLastUsed records when a token was last used.
+func (r *firestoreAPITokenRepo) TouchLastUsed(ctx context.Context, tokenID string, at time.Time) error {
+	_, err := r.client.Collection("apiTokens").Doc(tokenID).Update(ctx, []firestore.Update{
+		{Path: "lastUsedAt", Value: at},
+	})
+	if err != nil {
+		return fmt.Errorf("touch api token %s: %w", tokenID, err)
+	}
+	return nil
+}
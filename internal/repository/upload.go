@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/pandasWhoCode/paintbar/internal/model"
+)
+
+// UploadSessionRepository defines the interface for resumable upload
+// sessions.
+type UploadSessionRepository interface {
+	Create(ctx context.Context, session *model.UploadSession) (string, error)
+	GetByID(ctx context.Context, uploadID string) (*model.UploadSession, error)
+	// AppendChunk records a received chunk if it starts at the session's
+	// current offset and the session is still open, and returns the updated
+	// session.
+	AppendChunk(ctx context.Context, uploadID string, chunk model.UploadChunk) (*model.UploadSession, error)
+	// MarkFinalizing moves a complete, open session to finalizing with the
+	// given job ID. It is a no-op for a session already finalizing.
+	MarkFinalizing(ctx context.Context, uploadID, jobID string) error
+	Delete(ctx context.Context, uploadID string) error
+}
+
+// firestoreUploadSessionRepo implements UploadSessionRepository using the
+// top-level uploadSessions collection.
+type firestoreUploadSessionRepo struct {
+	client *firestore.Client
+}
+
+// NewUploadSessionRepository creates a new Firestore-backed
+// UploadSessionRepository.
+func NewUploadSessionRepository(client *firestore.Client) UploadSessionRepository {
+	return &firestoreUploadSessionRepo{client: client}
+}
+
+// Create stores a new session and returns its document ID.
+func (r *firestoreUploadSessionRepo) Create(ctx context.Context, session *model.UploadSession) (string, error) {
+	now := time.Now()
+	session.CreatedAt = now
+	session.UpdatedAt = now
+
+	ref, _, err := r.client.Collection("uploadSessions").Add(ctx, session)
+	if err != nil {
+		return "", fmt.Errorf("create upload session: %w", err)
+	}
+	session.ID = ref.ID
+	return ref.ID, nil
+}
+
+// GetByID retrieves a session by its document ID.
+func (r *firestoreUploadSessionRepo) GetByID(ctx context.Context, uploadID string) (*model.UploadSession, error) {
+	doc, err := r.client.Collection("uploadSessions").Doc(uploadID).Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get upload session %s: %w", uploadID, err)
+	}
+	return decodeUploadSession(doc)
+}
+
+// AppendChunk records a chunk in a transaction so concurrent writes for the
+// same offset cannot both succeed.
+func (r *firestoreUploadSessionRepo) AppendChunk(ctx context.Context, uploadID string, chunk model.UploadChunk) (*model.UploadSession, error) {
+	ref := r.client.Collection("uploadSessions").Doc(uploadID)
+
+	var updated *model.UploadSession
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if err != nil {
+			return fmt.Errorf("get upload session %s: %w", uploadID, err)
+		}
+		session, err := decodeUploadSession(doc)
+		if err != nil {
+			return err
+		}
+		if session.Status != model.UploadStatusOpen {
+			return fmt.Errorf("invalid upload: session is %s", session.Status)
+		}
+		if chunk.Offset != session.Offset {
+			return fmt.Errorf("invalid range: upload offset is %d", session.Offset)
+		}
+
+		session.Chunks = append(session.Chunks, chunk)
+		session.Offset += chunk.Size
+		session.UpdatedAt = time.Now()
+		updated = session
+		return tx.Update(ref, []firestore.Update{
+			{Path: "chunks", Value: session.Chunks},
+			{Path: "offset", Value: session.Offset},
+			{Path: "updatedAt", Value: session.UpdatedAt},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// MarkFinalizing moves a complete session to finalizing.
+func (r *firestoreUploadSessionRepo) MarkFinalizing(ctx context.Context, uploadID, jobID string) error {
+	ref := r.client.Collection("uploadSessions").Doc(uploadID)
+	return r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if err != nil {
+			return fmt.Errorf("get upload session %s: %w", uploadID, err)
+		}
+		session, err := decodeUploadSession(doc)
+		if err != nil {
+			return err
+		}
+		if session.Status == model.UploadStatusFinalizing {
+			return nil
+		}
+		if !session.Complete() {
+			return fmt.Errorf("invalid upload: received %d of %d bytes", session.Offset, session.Size)
+		}
+		return tx.Update(ref, []firestore.Update{
+			{Path: "status", Value: model.UploadStatusFinalizing},
+			{Path: "jobId", Value: jobID},
+			{Path: "updatedAt", Value: time.Now()},
+		})
+	})
+}
+
+// Delete removes a session document. Chunk objects are deleted by the caller.
+func (r *firestoreUploadSessionRepo) Delete(ctx context.Context, uploadID string) error {
+	_, err := r.client.Collection("uploadSessions").Doc(uploadID).Delete(ctx)
+	if err != nil {
+		return fmt.Errorf("delete upload session %s: %w", uploadID, err)
+	}
+	return nil
+}
+
+// decodeUploadSession converts a snapshot into an UploadSession.
+func decodeUploadSession(doc *firestore.DocumentSnapshot) (*model.UploadSession, error) {
+	var session model.UploadSession
+	if err := doc.DataTo(&session); err != nil {
+		return nil, fmt.Errorf("decode upload session %s: %w", doc.Ref.ID, err)
+	}
+	session.ID = doc.Ref.ID
+	return &session, nil
+}
@@ -12,9 +12,13 @@ import (
 // UserRepository defines the interface for user persistence operations.
 type UserRepository interface {
 	GetByID(ctx context.Context, uid string) (*model.User, error)
+	GetByIDs(ctx context.Context, uids []string) ([]*model.User, error)
+	GetByUsername(ctx context.Context, username string) (*model.User, error)
 	Create(ctx context.Context, user *model.User) error
 	Update(ctx context.Context, uid string, update *model.UserUpdate) error
 	ClaimUsername(ctx context.Context, uid string, username string) error
+	SetSuspended(ctx context.Context, uid string, suspended bool) error
+	ReleaseUsername(ctx context.Context, username string) (string, error)
 }
 
 // firestoreUserRepo implements UserRepository using Firestore.
@@ -42,6 +46,43 @@ func (r *firestoreUserRepo) GetByID(ctx context.Context, uid string) (*model.Use
 	return &user, nil
 }
 
+// GetByIDs retrieves the users with the given UIDs in one batched read.
+// UIDs with no user are skipped.
+func (r *firestoreUserRepo) GetByIDs(ctx context.Context, uids []string) ([]*model.User, error) {
+	docs, err := getDocs(ctx, r.client, "users", uids)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]*model.User, 0, len(docs))
+	for _, doc := range docs {
+		var user model.User
+		if err := doc.DataTo(&user); err != nil {
+			return nil, fmt.Errorf("decode user %s: %w", doc.Ref.ID, err)
+		}
+		user.UID = doc.Ref.ID
+		users = append(users, &user)
+	}
+	return users, nil
+}
+
+// GetByUsername resolves a username via the `usernames` collection and
+// returns the owning user.
+func (r *firestoreUserRepo) GetByUsername(ctx context.Context, username string) (*model.User, error) {
+	doc, err := r.client.Collection("usernames").Doc(username).Get(ctx)
+	if err != nil {
+		if isNotFoundError(err) {
+			return nil, fmt.Errorf("user %q not found", username)
+		}
+		return nil, fmt.Errorf("get username %q: %w", username, err)
+	}
+
+	uid, _ := doc.Data()["uid"].(string)
+	if uid == "" {
+		return nil, fmt.Errorf("user %q not found", username)
+	}
+	return r.GetByID(ctx, uid)
+}
+
 // Create creates a new user document in Firestore.
 func (r *firestoreUserRepo) Create(ctx context.Context, user *model.User) error {
 	now := time.Now()
@@ -65,6 +106,18 @@ func (r *firestoreUserRepo) Update(ctx context.Context, uid string, update *mode
 	return nil
 }
 
+// SetSuspended sets the moderator-managed suspended flag on a user.
+func (r *firestoreUserRepo) SetSuspended(ctx context.Context, uid string, suspended bool) error {
+	_, err := r.client.Collection("users").Doc(uid).Update(ctx, []firestore.Update{
+		{Path: "suspended", Value: suspended},
+		{Path: "updatedAt", Value: time.Now()},
+	})
+	if err != nil {
+		return fmt.Errorf("set suspended on user %s: %w", uid, err)
+	}
+	return nil
+}
+
 // ClaimUsername atomically claims a username for a user.
 // It uses a Firestore transaction to check the `usernames` collection and
 // set both the username doc and the user's username field atomically.
@@ -99,3 +152,51 @@ func (r *firestoreUserRepo) ClaimUsername(ctx context.Context, uid string, usern
 		return nil
 	})
 }
+
+// ReleaseUsername atomically deletes a username claim and clears the
+// username field on the owning user, returning the owner's UID. The name
+// becomes available to be claimed again.
+func (r *firestoreUserRepo) ReleaseUsername(ctx context.Context, username string) (string, error) {
+	var uid string
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		usernameRef := r.client.Collection("usernames").Doc(username)
+		usernameDoc, err := tx.Get(usernameRef)
+		if err != nil {
+			if isNotFoundError(err) {
+				return fmt.Errorf("username %q not found", username)
+			}
+			return fmt.Errorf("get username %q: %w", username, err)
+		}
+		uid, _ = usernameDoc.Data()["uid"].(string)
+
+		var userDoc *firestore.DocumentSnapshot
+		if uid != "" {
+			userDoc, err = tx.Get(r.client.Collection("users").Doc(uid))
+			if err != nil && !isNotFoundError(err) {
+				return fmt.Errorf("get user %s: %w", uid, err)
+			}
+		}
+
+		if err := tx.Delete(usernameRef); err != nil {
+			return fmt.Errorf("delete username doc: %w", err)
+		}
+
+		// Only clear the user's field if it still points at this username.
+		if userDoc != nil && userDoc.Exists() {
+			if current, _ := userDoc.Data()["username"].(string); current == username {
+				if err := tx.Update(userDoc.Ref, []firestore.Update{
+					{Path: "username", Value: firestore.Delete},
+					{Path: "updatedAt", Value: time.Now()},
+				}); err != nil {
+					return fmt.Errorf("clear user username: %w", err)
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return uid, nil
+}
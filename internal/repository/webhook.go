@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"google.golang.org/api/iterator"
+)
+
+// WebhookRepository defines the interface for webhook registrations and
+// their delivery log.
+type WebhookRepository interface {
+	GetByID(ctx context.Context, webhookID string) (*model.Webhook, error)
+	ListByUser(ctx context.Context, uid string) ([]*model.Webhook, error)
+	Create(ctx context.Context, webhook *model.Webhook) (string, error)
+	Delete(ctx context.Context, webhookID string) error
+	LogDelivery(ctx context.Context, webhookID string, delivery *model.WebhookDelivery) error
+	ListDeliveries(ctx context.Context, webhookID string, limit int, startAfter string) ([]*model.WebhookDelivery, error)
+}
+
+// firestoreWebhookRepo implements WebhookRepository using the top-level
+// webhooks collection with a deliveries subcollection per webhook.
+type firestoreWebhookRepo struct {
+	client *firestore.Client
+}
+
+// NewWebhookRepository creates a new Firestore-backed WebhookRepository.
+func NewWebhookRepository(client *firestore.Client) WebhookRepository {
+	return &firestoreWebhookRepo{client: client}
+}
+
+// deliveries returns the delivery log subcollection for a webhook.
+func (r *firestoreWebhookRepo) deliveries(webhookID string) *firestore.CollectionRef {
+	return r.client.Collection("webhooks").Doc(webhookID).Collection("deliveries")
+}
+
+// GetByID retrieves a webhook by its document ID.
+func (r *firestoreWebhookRepo) GetByID(ctx context.Context, webhookID string) (*model.Webhook, error) {
+	doc, err := r.client.Collection("webhooks").Doc(webhookID).Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get webhook %s: %w", webhookID, err)
+	}
+
+	var webhook model.Webhook
+	if err := doc.DataTo(&webhook); err != nil {
+		return nil, fmt.Errorf("decode webhook %s: %w", webhookID, err)
+	}
+	webhook.ID = doc.Ref.ID
+	return &webhook, nil
+}
+
+// ListByUser retrieves all of a user's webhooks, newest first.
+func (r *firestoreWebhookRepo) ListByUser(ctx context.Context, uid string) ([]*model.Webhook, error) {
+	iter := r.client.Collection("webhooks").
+		Where("uid", "==", uid).
+		OrderBy("createdAt", firestore.Desc).
+		Documents(ctx)
+	defer iter.Stop()
+
+	var webhooks []*model.Webhook
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("iterate webhooks: %w", err)
+		}
+
+		var webhook model.Webhook
+		if err := doc.DataTo(&webhook); err != nil {
+			return nil, fmt.Errorf("decode webhook: %w", err)
+		}
+		webhook.ID = doc.Ref.ID
+		webhooks = append(webhooks, &webhook)
+	}
+
+	return webhooks, nil
+}
+
+// Create stores a new webhook and returns its document ID.
+func (r *firestoreWebhookRepo) Create(ctx context.Context, webhook *model.Webhook) (string, error) {
+	webhook.CreatedAt = time.Now()
+
+	ref, _, err := r.client.Collection("webhooks").Add(ctx, webhook)
+	if err != nil {
+		return "", fmt.Errorf("create webhook: %w", err)
+	}
+	webhook.ID = ref.ID
+	return ref.ID, nil
+}
+
+// Delete removes a webhook and its delivery log.
+func (r *firestoreWebhookRepo) Delete(ctx context.Context, webhookID string) error {
+	ref := r.client.Collection("webhooks").Doc(webhookID)
+	if err := deleteSubcollections(ctx, r.client, ref, "deliveries"); err != nil {
+		return fmt.Errorf("delete webhook %s deliveries: %w", webhookID, err)
+	}
+	if _, err := ref.Delete(ctx); err != nil {
+		return fmt.Errorf("delete webhook %s: %w", webhookID, err)
+	}
+	return nil
+}
+
+// LogDelivery appends a delivery attempt to the webhook's delivery log.
+func (r *firestoreWebhookRepo) LogDelivery(ctx context.Context, webhookID string, delivery *model.WebhookDelivery) error {
+	ref, _, err := r.deliveries(webhookID).Add(ctx, delivery)
+	if err != nil {
+		return fmt.Errorf("log webhook delivery: %w", err)
+	}
+	delivery.ID = ref.ID
+	return nil
+}
+
+// ListDeliveries retrieves a webhook's delivery attempts, newest first,
+// with cursor pagination.
+func (r *firestoreWebhookRepo) ListDeliveries(ctx context.Context, webhookID string, pageLimit int, startAfter string) ([]*model.WebhookDelivery, error) {
+	q := r.deliveries(webhookID).
+		OrderBy("createdAt", firestore.Desc).
+		Limit(pageLimit)
+
+	if startAfter != "" {
+		cursorDoc, err := r.deliveries(webhookID).Doc(startAfter).Get(ctx)
+		if err != nil {
+			return []*model.WebhookDelivery{}, nil
+		}
+		q = q.StartAfter(cursorDoc)
+	}
+
+	iter := q.Documents(ctx)
+	defer iter.Stop()
+
+	var deliveries []*model.WebhookDelivery
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("iterate webhook deliveries: %w", err)
+		}
+
+		var delivery model.WebhookDelivery
+		if err := doc.DataTo(&delivery); err != nil {
+			return nil, fmt.Errorf("decode webhook delivery: %w", err)
+		}
+		delivery.ID = doc.Ref.ID
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return deliveries, nil
+}
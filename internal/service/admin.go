@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"github.com/pandasWhoCode/paintbar/internal/repository"
+)
+
+// AccountManager reads and disables Firebase Auth accounts. Implemented by
+// AuthService.
+type AccountManager interface {
+	GetAccount(ctx context.Context, uid string) (*model.Account, error)
+	GetAccountByEmail(ctx context.Context, email string) (*model.Account, error)
+	SetAccountDisabled(ctx context.Context, uid string, disabled bool) error
+}
+
+// AdminService handles the account-level admin API: user lookup, usage
+// stats, disabling accounts and releasing usernames. Every action is written
+// to the audit log. Callers must already have been authorized (see
+// middleware.RequireRole).
+type AdminService struct {
+	accounts AccountManager
+	users    repository.UserRepository
+	stats    repository.StatsRepository
+	audit    repository.AuditLogger
+}
+
+// NewAdminService creates a new AdminService.
+func NewAdminService(
+	accounts AccountManager,
+	users repository.UserRepository,
+	stats repository.StatsRepository,
+	audit repository.AuditLogger,
+) *AdminService {
+	return &AdminService{
+		accounts: accounts,
+		users:    users,
+		stats:    stats,
+		audit:    audit,
+	}
+}
+
+// LookupUser finds a user by exactly one of email, UID or username and
+// returns their auth account together with their profile, if any.
+func (s *AdminService) LookupUser(ctx context.Context, adminUID, email, uid, username string) (*model.AdminUser, error) {
+	email = strings.TrimSpace(email)
+	uid = strings.TrimSpace(uid)
+	username = strings.ToLower(strings.TrimSpace(username))
+
+	given := 0
+	for _, v := range []string{email, uid, username} {
+		if v != "" {
+			given++
+		}
+	}
+	if given != 1 {
+		return nil, fmt.Errorf("exactly one of email, uid or username is required")
+	}
+
+	var (
+		account *model.Account
+		profile *model.User
+		err     error
+	)
+	switch {
+	case email != "":
+		account, err = s.accounts.GetAccountByEmail(ctx, email)
+		if err != nil {
+			return nil, fmt.Errorf("lookup user: %w", err)
+		}
+		profile, err = s.optionalProfile(ctx, account.UID)
+	case uid != "":
+		account, err = s.accounts.GetAccount(ctx, uid)
+		if err != nil {
+			return nil, fmt.Errorf("lookup user: %w", err)
+		}
+		profile, err = s.optionalProfile(ctx, uid)
+	default:
+		profile, err = s.users.GetByUsername(ctx, username)
+		if err != nil {
+			return nil, fmt.Errorf("lookup user: %w", err)
+		}
+		account, err = s.accounts.GetAccount(ctx, profile.UID)
+		if err != nil {
+			return nil, fmt.Errorf("lookup user: %w", err)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.audit, &model.AuditEntry{
+		ActorUID:     adminUID,
+		Action:       model.AuditActionUserLookup,
+		ResourceType: model.AuditResourceUser,
+		ResourceID:   account.UID,
+	})
+	return &model.AdminUser{Account: account, Profile: profile}, nil
+}
+
+// Stats returns site-wide usage counts.
+func (s *AdminService) Stats(ctx context.Context) (*model.UsageStats, error) {
+	stats, err := s.stats.Usage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get usage stats: %w", err)
+	}
+	return stats, nil
+}
+
+// SetUserDisabled disables or re-enables a user's Firebase Auth account.
+// A disabled account cannot sign in or refresh its ID token.
+func (s *AdminService) SetUserDisabled(ctx context.Context, adminUID, uid string, disabled bool) error {
+	if uid == "" {
+		return fmt.Errorf("uid is required")
+	}
+	if uid == adminUID {
+		return fmt.Errorf("invalid request: you cannot disable yourself")
+	}
+	account, err := s.accounts.GetAccount(ctx, uid)
+	if err != nil {
+		return fmt.Errorf("get account: %w", err)
+	}
+	if err := s.accounts.SetAccountDisabled(ctx, uid, disabled); err != nil {
+		return fmt.Errorf("set account disabled: %w", err)
+	}
+
+	recordAudit(ctx, s.audit, &model.AuditEntry{
+		ActorUID:     adminUID,
+		Action:       pickAction(disabled, model.AuditActionUserDisable, model.AuditActionUserEnable),
+		ResourceType: model.AuditResourceUser,
+		ResourceID:   uid,
+		Changes:      flagChange("disabled", account.Disabled, disabled),
+	})
+	return nil
+}
+
+// ReleaseUsername force-releases a claimed username so it can be claimed
+// again, and clears it from the owning user's profile.
+func (s *AdminService) ReleaseUsername(ctx context.Context, adminUID, username string) error {
+	username = strings.ToLower(strings.TrimSpace(username))
+	if username == "" {
+		return fmt.Errorf("username is required")
+	}
+
+	uid, err := s.users.ReleaseUsername(ctx, username)
+	if err != nil {
+		return fmt.Errorf("release username: %w", err)
+	}
+
+	recordAudit(ctx, s.audit, &model.AuditEntry{
+		ActorUID:     adminUID,
+		Action:       model.AuditActionUsernameRelease,
+		ResourceType: model.AuditResourceUsername,
+		ResourceID:   username,
+		Changes: model.AuditDiff(
+			map[string]interface{}{"uid": uid},
+			map[string]interface{}{"uid": ""},
+		),
+	})
+	return nil
+}
+
+// ListAuditLog returns a page of audit entries, newest first, optionally
+// restricted to a single actor.
+func (s *AdminService) ListAuditLog(ctx context.Context, actorUID string, limit int, startAfter string) ([]*model.AuditEntry, error) {
+	return s.audit.List(ctx, strings.TrimSpace(actorUID), clampPageSize(limit), startAfter)
+}
+
+// optionalProfile loads a user's profile, returning nil if they have never
+// saved one.
+func (s *AdminService) optionalProfile(ctx context.Context, uid string) (*model.User, error) {
+	profile, err := s.users.GetByID(ctx, uid)
+	if err != nil {
+		if repository.IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get profile: %w", err)
+	}
+	return profile, nil
+}
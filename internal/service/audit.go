@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"github.com/pandasWhoCode/paintbar/internal/repository"
+)
+
+// RequestMeta identifies the HTTP request an action came from. It is attached
+// to the context by middleware.AuditContext and copied onto audit entries.
+type RequestMeta struct {
+	IP        string
+	RequestID string
+}
+
+type requestMetaKey struct{}
+
+// WithRequestMeta returns a copy of ctx carrying meta.
+func WithRequestMeta(ctx context.Context, meta RequestMeta) context.Context {
+	return context.WithValue(ctx, requestMetaKey{}, meta)
+}
+
+// RequestMetaFromContext returns the RequestMeta attached to ctx, if any.
+func RequestMetaFromContext(ctx context.Context) RequestMeta {
+	meta, _ := ctx.Value(requestMetaKey{}).(RequestMeta)
+	return meta
+}
+
+// recordAudit appends an entry to the audit log, filling in the request's IP
+// and ID from ctx. It is called after an action has succeeded, so a failed
+// write is logged rather than returned: the action cannot be rolled back and
+// the caller should still see success. A nil logger disables auditing.
+func recordAudit(ctx context.Context, audit repository.AuditLogger, entry *model.AuditEntry) {
+	if audit == nil {
+		return
+	}
+
+	meta := RequestMetaFromContext(ctx)
+	entry.IP = meta.IP
+	entry.RequestID = meta.RequestID
+
+	if err := audit.Log(ctx, entry); err != nil {
+		slog.Error("audit log write failed",
+			"error", err,
+			"actor", entry.ActorUID,
+			"action", entry.Action,
+			"resource", entry.ResourceType+"/"+entry.ResourceID,
+			"request_id", entry.RequestID,
+		)
+	}
+}
@@ -3,19 +3,86 @@ package service
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/http"
+	"time"
 
 	"firebase.google.com/go/v4/auth"
+	"github.com/pandasWhoCode/paintbar/internal/model"
 )
 
-// UserInfo holds the verified identity extracted from a Firebase ID token.
+// RoleAdmin is the role required for the /api/admin routes.
+const RoleAdmin = "admin"
+
+// sessionSignInWindow is how recently the user must have signed in for their
+// ID token to be exchanged for a session cookie. A stolen ID token is only
+// useful for minting a long-lived cookie within this window.
+const sessionSignInWindow = 5 * time.Minute
+
+// UserInfo holds the verified identity extracted from a Firebase ID token
+// or a personal access token.
 type UserInfo struct {
 	UID   string
 	Email string
+	Roles []string // from the "roles" custom claim
+
+	// Set only for personal access tokens, which are limited to Scopes.
+	TokenID string
+	Scopes  []string
 }
 
-// AuthService handles Firebase token verification.
+// HasScope reports whether the credential may use routes requiring scope.
+// Firebase ID tokens carry the user's full access and have every scope.
+func (u *UserInfo) HasScope(scope string) bool {
+	if u.TokenID == "" {
+		return true
+	}
+	for _, s := range u.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRole reports whether the user has been granted the given role.
+func (u *UserInfo) HasRole(role string) bool {
+	for _, r := range u.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// IDTokenCertsURL is where Google publishes the keys Firebase ID tokens are
+// signed with. The Admin SDK fetches them on demand to verify tokens.
+const IDTokenCertsURL = "https://www.googleapis.com/robot/v1/metadata/x509/securetoken@system.gserviceaccount.com"
+
+// AuthKeysHealthCheck fetches the token signing keys from certsURL. While
+// they cannot be fetched, no ID token verifies once the SDK's copy expires.
+func AuthKeysHealthCheck(ctx context.Context, client *http.Client, certsURL string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, certsURL, nil)
+	if err != nil {
+		return fmt.Errorf("create auth keys request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("auth keys health check: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("auth keys health check failed (HTTP %d)", resp.StatusCode)
+	}
+	return nil
+}
+
+// AuthService handles Firebase token verification and the account
+// operations (lookup, disable) available to admins.
 // Coverage: thin wrapper around Firebase Admin SDK — unit-tested indirectly via
-// the TokenVerifier interface mock in middleware tests. Direct coverage requires
+// the TokenVerifier and AccountManager interface mocks. Direct coverage requires
 // the Firebase Auth emulator (integration tests).
 type AuthService struct {
 	authClient *auth.Client
@@ -38,5 +105,146 @@ func (s *AuthService) VerifyIDToken(ctx context.Context, idToken string) (*UserI
 	return &UserInfo{
 		UID:   token.UID,
 		Email: email,
+		Roles: rolesFromClaims(token.Claims),
 	}, nil
 }
+
+// CreateSessionCookie exchanges a freshly issued ID token for a Firebase
+// session cookie valid for expiresIn. The token must come from a sign-in
+// within the last five minutes.
+func (s *AuthService) CreateSessionCookie(ctx context.Context, idToken string, expiresIn time.Duration) (string, error) {
+	token, err := s.authClient.VerifyIDToken(ctx, idToken)
+	if err != nil {
+		return "", fmt.Errorf("verify id token: %w", err)
+	}
+
+	if time.Since(time.Unix(token.AuthTime, 0)) > sessionSignInWindow {
+		return "", fmt.Errorf("recent sign-in required")
+	}
+
+	cookie, err := s.authClient.SessionCookie(ctx, idToken, expiresIn)
+	if err != nil {
+		return "", fmt.Errorf("create session cookie: %w", err)
+	}
+	return cookie, nil
+}
+
+// VerifySessionCookie verifies a session cookie and returns the user's
+// identity. Cookies belonging to revoked or disabled accounts are rejected.
+func (s *AuthService) VerifySessionCookie(ctx context.Context, cookie string) (*UserInfo, error) {
+	token, err := s.authClient.VerifySessionCookieAndCheckRevoked(ctx, cookie)
+	if err != nil {
+		return nil, fmt.Errorf("verify session cookie: %w", err)
+	}
+
+	email, _ := token.Claims["email"].(string)
+
+	return &UserInfo{
+		UID:   token.UID,
+		Email: email,
+		Roles: rolesFromClaims(token.Claims),
+	}, nil
+}
+
+// RevokeSessions revokes every refresh token and session cookie issued to
+// the user, signing them out on all devices.
+func (s *AuthService) RevokeSessions(ctx context.Context, uid string) error {
+	if err := s.authClient.RevokeRefreshTokens(ctx, uid); err != nil {
+		return fmt.Errorf("revoke sessions for %s: %w", uid, err)
+	}
+	return nil
+}
+
+// GetAccount retrieves a Firebase Auth account by UID.
+func (s *AuthService) GetAccount(ctx context.Context, uid string) (*model.Account, error) {
+	record, err := s.authClient.GetUser(ctx, uid)
+	if err != nil {
+		if auth.IsUserNotFound(err) {
+			return nil, fmt.Errorf("account %s not found", uid)
+		}
+		return nil, fmt.Errorf("get account %s: %w", uid, err)
+	}
+	return accountFromRecord(record), nil
+}
+
+// GetAccountByEmail retrieves a Firebase Auth account by email address.
+func (s *AuthService) GetAccountByEmail(ctx context.Context, email string) (*model.Account, error) {
+	record, err := s.authClient.GetUserByEmail(ctx, email)
+	if err != nil {
+		if auth.IsUserNotFound(err) {
+			return nil, fmt.Errorf("account for %q not found", email)
+		}
+		return nil, fmt.Errorf("get account by email: %w", err)
+	}
+	return accountFromRecord(record), nil
+}
+
+// SetAccountDisabled disables or re-enables a Firebase Auth account.
+// Disabling also revokes the account's refresh tokens so existing sessions
+// end once their current ID token expires.
+func (s *AuthService) SetAccountDisabled(ctx context.Context, uid string, disabled bool) error {
+	params := (&auth.UserToUpdate{}).Disabled(disabled)
+	if _, err := s.authClient.UpdateUser(ctx, uid, params); err != nil {
+		if auth.IsUserNotFound(err) {
+			return fmt.Errorf("account %s not found", uid)
+		}
+		return fmt.Errorf("update account %s: %w", uid, err)
+	}
+	if disabled {
+		if err := s.authClient.RevokeRefreshTokens(ctx, uid); err != nil {
+			return fmt.Errorf("revoke refresh tokens for %s: %w", uid, err)
+		}
+	}
+	return nil
+}
+
+// rolesFromClaims extracts roles from the "roles" custom claim (a list of
+// strings). The boolean "admin" claim is also honored so accounts granted
+// admin before roles existed keep their access.
+func rolesFromClaims(claims map[string]interface{}) []string {
+	var roles []string
+	seen := make(map[string]bool)
+	add := func(role string) {
+		if role != "" && !seen[role] {
+			seen[role] = true
+			roles = append(roles, role)
+		}
+	}
+
+	switch v := claims["roles"].(type) {
+	case []interface{}:
+		for _, r := range v {
+			if s, ok := r.(string); ok {
+				add(s)
+			}
+		}
+	case []string:
+		for _, r := range v {
+			add(r)
+		}
+	}
+	if admin, _ := claims["admin"].(bool); admin {
+		add(RoleAdmin)
+	}
+	return roles
+}
+
+// accountFromRecord converts a Firebase Auth user record to a model.Account.
+func accountFromRecord(record *auth.UserRecord) *model.Account {
+	account := &model.Account{
+		UID:           record.UID,
+		Email:         record.Email,
+		EmailVerified: record.EmailVerified,
+		Disabled:      record.Disabled,
+		Roles:         rolesFromClaims(record.CustomClaims),
+	}
+	if record.UserMetadata != nil {
+		if ts := record.UserMetadata.CreationTimestamp; ts > 0 {
+			account.CreatedAt = time.UnixMilli(ts)
+		}
+		if ts := record.UserMetadata.LastLogInTimestamp; ts > 0 {
+			account.LastSignInAt = time.UnixMilli(ts)
+		}
+	}
+	return account
+}
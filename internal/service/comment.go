@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"github.com/pandasWhoCode/paintbar/internal/repository"
+)
+
+// CommentService handles comments on gallery items. Gallery items are public
+// by design, so any authenticated user with a username may comment.
+type CommentService struct {
+	gallery  repository.GalleryRepository
+	comments repository.CommentRepository
+	users    repository.UserRepository
+}
+
+// NewCommentService creates a new CommentService.
+func NewCommentService(gallery repository.GalleryRepository, comments repository.CommentRepository, users repository.UserRepository) *CommentService {
+	return &CommentService{gallery: gallery, comments: comments, users: users}
+}
+
+// ListComments returns a page of comments on a gallery item, oldest first.
+// Hidden comments are left out.
+func (s *CommentService) ListComments(ctx context.Context, itemID string, limit int, startAfter string) ([]*model.Comment, error) {
+	if _, err := s.getItem(ctx, itemID); err != nil {
+		return nil, err
+	}
+	return s.comments.List(ctx, itemID, clampPageSize(limit), startAfter)
+}
+
+// CreateComment validates and adds a comment (or a reply when ParentID is
+// set) to a gallery item. Replies may only target top-level comments.
+func (s *CommentService) CreateComment(ctx context.Context, uid, itemID string, comment *model.Comment) (string, error) {
+	if uid == "" {
+		return "", fmt.Errorf("uid is required")
+	}
+	if _, err := s.getItem(ctx, itemID); err != nil {
+		return "", err
+	}
+
+	author, err := s.users.GetByID(ctx, uid)
+	if err != nil {
+		return "", fmt.Errorf("get comment author: %w", err)
+	}
+	if author.Username == "" {
+		return "", fmt.Errorf("username is required before commenting")
+	}
+
+	comment.UserID = uid
+	comment.Username = author.Username
+	comment.Edited = false
+	comment.Hidden = false
+	comment.Sanitize()
+
+	if err := comment.Validate(); err != nil {
+		return "", fmt.Errorf("validation: %w", err)
+	}
+
+	if comment.ParentID != "" {
+		parent, err := s.comments.GetByID(ctx, itemID, comment.ParentID)
+		if err != nil {
+			return "", fmt.Errorf("get parent comment: %w", err)
+		}
+		if parent.ParentID != "" {
+			return "", fmt.Errorf("invalid parentId: replies cannot be nested")
+		}
+	}
+
+	return s.comments.Create(ctx, itemID, comment)
+}
+
+// EditComment replaces the body of a comment. Only the author may edit, and
+// hidden comments or comments on hidden items read as not found.
+func (s *CommentService) EditComment(ctx context.Context, requestorUID, itemID, commentID, body string) error {
+	if commentID == "" {
+		return fmt.Errorf("comment ID is required")
+	}
+
+	if _, err := s.getItem(ctx, itemID); err != nil {
+		return err
+	}
+	comment, err := s.comments.GetByID(ctx, itemID, commentID)
+	if err != nil {
+		return fmt.Errorf("get comment for edit: %w", err)
+	}
+	if comment.Hidden {
+		return fmt.Errorf("comment %s not found", commentID)
+	}
+	if comment.UserID != requestorUID {
+		return fmt.Errorf("unauthorized: cannot edit another user's comment")
+	}
+
+	body = model.SanitizeCommentBody(body)
+	if err := model.ValidateCommentBody(body); err != nil {
+		return fmt.Errorf("validation: %w", err)
+	}
+
+	return s.comments.UpdateBody(ctx, itemID, commentID, body)
+}
+
+// DeleteComment removes a comment and its replies. The comment's author and
+// the gallery item's owner may delete.
+func (s *CommentService) DeleteComment(ctx context.Context, requestorUID, itemID, commentID string) error {
+	if commentID == "" {
+		return fmt.Errorf("comment ID is required")
+	}
+
+	item, err := s.getItem(ctx, itemID)
+	if err != nil {
+		return err
+	}
+	comment, err := s.comments.GetByID(ctx, itemID, commentID)
+	if err != nil {
+		return fmt.Errorf("get comment for delete: %w", err)
+	}
+	if comment.UserID != requestorUID && item.UserID != requestorUID {
+		return fmt.Errorf("unauthorized: cannot delete this comment")
+	}
+
+	return s.comments.Delete(ctx, itemID, commentID)
+}
+
+// getItem loads the gallery item a comment belongs to.
+func (s *CommentService) getItem(ctx context.Context, itemID string) (*model.GalleryItem, error) {
+	return visibleGalleryItem(ctx, s.gallery, itemID)
+}
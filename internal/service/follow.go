@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"github.com/pandasWhoCode/paintbar/internal/repository"
+)
+
+// FollowService handles the follow graph and the following feed.
+type FollowService struct {
+	users   repository.UserRepository
+	follows repository.FollowRepository
+	gallery repository.GalleryRepository
+}
+
+// NewFollowService creates a new FollowService.
+func NewFollowService(users repository.UserRepository, follows repository.FollowRepository, gallery repository.GalleryRepository) *FollowService {
+	return &FollowService{users: users, follows: follows, gallery: gallery}
+}
+
+// GetPublicProfile returns the public view of the user with the given
+// username, including follow counts and whether the requestor follows them.
+func (s *FollowService) GetPublicProfile(ctx context.Context, requestorUID, username string) (*model.PublicProfile, error) {
+	user, err := s.lookupUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := user.ToPublicProfile()
+	if requestorUID != "" && requestorUID != user.UID {
+		following, err := s.follows.IsFollowing(ctx, requestorUID, user.UID)
+		if err != nil {
+			return nil, fmt.Errorf("check follow: %w", err)
+		}
+		profile.IsFollowing = following
+	}
+	return profile, nil
+}
+
+// GetPublicProfiles returns the public views of the users with the given
+// UIDs in one batched read, keyed by UID. Missing and suspended users are
+// left out, as GetPublicProfile hides them. IsFollowing is not filled in.
+func (s *FollowService) GetPublicProfiles(ctx context.Context, uids []string) (map[string]*model.PublicProfile, error) {
+	users, err := s.users.GetByIDs(ctx, uids)
+	if err != nil {
+		return nil, fmt.Errorf("get users: %w", err)
+	}
+	profiles := make(map[string]*model.PublicProfile, len(users))
+	for _, u := range users {
+		if !u.Suspended {
+			profiles[u.UID] = u.ToPublicProfile()
+		}
+	}
+	return profiles, nil
+}
+
+// Follow makes the requestor follow the user with the given username.
+// The requestor must have claimed a username so that follower lists can
+// display them. The repository enforces repository.MaxFollowing.
+func (s *FollowService) Follow(ctx context.Context, requestorUID, username string) error {
+	if requestorUID == "" {
+		return fmt.Errorf("uid is required")
+	}
+
+	target, err := s.lookupUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+	if target.UID == requestorUID {
+		return fmt.Errorf("invalid follow: you cannot follow yourself")
+	}
+
+	follower, err := s.users.GetByID(ctx, requestorUID)
+	if err != nil {
+		return fmt.Errorf("get follower: %w", err)
+	}
+	if follower.Username == "" {
+		return fmt.Errorf("username is required before following other users")
+	}
+	return s.follows.Follow(ctx, follower, target)
+}
+
+// Unfollow removes the requestor's follow of the user with the given
+// username. Suspended users can still be unfollowed.
+func (s *FollowService) Unfollow(ctx context.Context, requestorUID, username string) error {
+	if requestorUID == "" {
+		return fmt.Errorf("uid is required")
+	}
+
+	target, err := s.resolveUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	return s.follows.Unfollow(ctx, requestorUID, target.UID)
+}
+
+// ListFollowers returns a page of users following the given username.
+func (s *FollowService) ListFollowers(ctx context.Context, username string, limit int, startAfter string) ([]*model.Follow, error) {
+	user, err := s.lookupUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return s.follows.ListFollowers(ctx, user.UID, clampPageSize(limit), startAfter)
+}
+
+// ListFollowing returns a page of users the given username follows.
+func (s *FollowService) ListFollowing(ctx context.Context, username string, limit int, startAfter string) ([]*model.Follow, error) {
+	user, err := s.lookupUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return s.follows.ListFollowing(ctx, user.UID, clampPageSize(limit), startAfter)
+}
+
+// FollowingFeed merges the most recent gallery items from every user the
+// requestor follows, newest first. after is the position from the previous
+// page's Next, or nil for the first page. Suspended users and hidden items
+// are left out.
+//
+// Firestore limits "in" filters to MaxInQueryValues values, so followed
+// users are queried in chunks and the per-chunk results are merged here.
+// Each chunk contributes a full page of visible items (or all it has),
+// which guarantees the merged page is the true global top-N.
+func (s *FollowService) FollowingFeed(ctx context.Context, uid string, limit int, after *model.Cursor) (*model.FeedPage, error) {
+	if uid == "" {
+		return nil, fmt.Errorf("uid is required")
+	}
+	limit = clampPageSize(limit)
+	if after != nil {
+		if after.Sort != model.DefaultSort || after.Order != model.SortDesc || after.Filter != feedCursorFilter {
+			return nil, fmt.Errorf("invalid cursor: it belongs to a different list")
+		}
+		if _, err := time.Parse(time.RFC3339Nano, after.Value); err != nil {
+			return nil, fmt.Errorf("invalid cursor: malformed position")
+		}
+	}
+
+	ids, err := s.visibleFollowingIDs(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	// One extra item per chunk shows whether another page follows
+	items := []*model.GalleryItem{}
+	for start := 0; start < len(ids); start += repository.MaxInQueryValues {
+		end := start + repository.MaxInQueryValues
+		if end > len(ids) {
+			end = len(ids)
+		}
+		chunk, err := s.visibleItemsByUsers(ctx, ids[start:end], limit+1, after)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, chunk...)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if !items[i].CreatedAt.Equal(items[j].CreatedAt) {
+			return items[i].CreatedAt.After(items[j].CreatedAt)
+		}
+		return items[i].ID > items[j].ID
+	})
+	page := &model.FeedPage{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		page.HasMore = true
+		page.Next = feedCursor(items[limit-1])
+	}
+	return page, nil
+}
+
+// feedCursorFilter is the filter key of feed cursors, so the cursor of a
+// gallery list page cannot be replayed against the feed.
+const feedCursorFilter = "feed"
+
+// feedCursor returns the feed position just after item.
+func feedCursor(item *model.GalleryItem) *model.Cursor {
+	return &model.Cursor{
+		Sort:   model.DefaultSort,
+		Order:  model.SortDesc,
+		Filter: feedCursorFilter,
+		Value:  item.CreatedAt.UTC().Format(time.RFC3339Nano),
+		ID:     item.ID,
+	}
+}
+
+// visibleFollowingIDs returns the UIDs uid follows, without suspended users.
+func (s *FollowService) visibleFollowingIDs(ctx context.Context, uid string) ([]string, error) {
+	ids, err := s.follows.FollowingIDs(ctx, uid)
+	if err != nil {
+		return nil, fmt.Errorf("list following: %w", err)
+	}
+	users, err := s.users.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("get followed users: %w", err)
+	}
+	visible := make([]string, 0, len(users))
+	for _, u := range users {
+		if !u.Suspended {
+			visible = append(visible, u.UID)
+		}
+	}
+	return visible, nil
+}
+
+// visibleItemsByUsers returns up to limit of the newest items by userIDs
+// after the given position, skipping hidden items. It keeps reading until
+// the page is full or the users have no older items, so hidden items never
+// make a page come back short.
+func (s *FollowService) visibleItemsByUsers(ctx context.Context, userIDs []string, limit int, after *model.Cursor) ([]*model.GalleryItem, error) {
+	var items []*model.GalleryItem
+	for {
+		page, err := s.gallery.ListByUsers(ctx, userIDs, limit, after)
+		if err != nil {
+			return nil, fmt.Errorf("list feed items: %w", err)
+		}
+		for _, item := range page {
+			if !item.Hidden && len(items) < limit {
+				items = append(items, item)
+			}
+		}
+		if len(items) == limit || len(page) < limit {
+			return items, nil
+		}
+		after = feedCursor(page[len(page)-1])
+	}
+}
+
+// lookupUsername resolves a username to a user, treating suspended users
+// as not found so their profile and follow lists are hidden.
+func (s *FollowService) lookupUsername(ctx context.Context, username string) (*model.User, error) {
+	user, err := s.resolveUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if user.Suspended {
+		return nil, fmt.Errorf("user %q not found", username)
+	}
+	return user, nil
+}
+
+// resolveUsername normalizes and validates a username path segment and
+// resolves it to a user.
+func (s *FollowService) resolveUsername(ctx context.Context, username string) (*model.User, error) {
+	username = strings.TrimSpace(strings.ToLower(username))
+	if !model.UsernameRegex.MatchString(username) {
+		return nil, fmt.Errorf("invalid username")
+	}
+
+	user, err := s.users.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("get user by username: %w", err)
+	}
+	return user, nil
+}
+
+// clampPageSize applies the default and maximum page sizes.
+func clampPageSize(limit int) int {
+	if limit <= 0 {
+		return DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		return MaxPageSize
+	}
+	return limit
+}
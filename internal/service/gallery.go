@@ -10,19 +10,30 @@ import (
 
 // GalleryService handles gallery business logic.
 type GalleryService struct {
-	repo repository.GalleryRepository
+	repo   repository.GalleryRepository
+	audit  repository.AuditLogger
+	events EventPublisher
 }
 
-// NewGalleryService creates a new GalleryService.
-func NewGalleryService(repo repository.GalleryRepository) *GalleryService {
-	return &GalleryService{repo: repo}
+// NewGalleryService creates a new GalleryService. audit may be nil to
+// disable audit logging; events may be nil to disable webhook events.
+func NewGalleryService(repo repository.GalleryRepository, audit repository.AuditLogger, events EventPublisher) *GalleryService {
+	return &GalleryService{repo: repo, audit: audit, events: events}
 }
 
-// ListItems returns paginated gallery items for a user.
-func (s *GalleryService) ListItems(ctx context.Context, uid string, limit int, startAfter string) ([]*model.GalleryItem, error) {
+// ListItems returns a page of gallery item summaries for a user with the
+// position of the next page and the total number matching opts, limited to
+// fields when it is non-nil.
+func (s *GalleryService) ListItems(ctx context.Context, uid string, limit int, opts *model.ListOptions, fields model.FieldSet) (*model.Page, error) {
 	if uid == "" {
 		return nil, fmt.Errorf("uid is required")
 	}
+	if opts == nil {
+		opts = &model.ListOptions{}
+	}
+	if err := model.GalleryListSchema.Normalize(opts); err != nil {
+		return nil, err
+	}
 
 	if limit <= 0 {
 		limit = DefaultPageSize
@@ -31,7 +42,20 @@ func (s *GalleryService) ListItems(ctx context.Context, uid string, limit int, s
 		limit = MaxPageSize
 	}
 
-	return s.repo.List(ctx, uid, limit, startAfter)
+	items, next, err := s.repo.List(ctx, uid, limit, opts, model.GallerySummaryFields.Paths(fields))
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]*model.GallerySummary, len(items))
+	for i, v := range items {
+		summaries[i] = v.Summary()
+	}
+	total, err := s.repo.CountList(ctx, uid, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Page{Items: summaries, HasMore: next != nil, Total: total, Next: next}, nil
 }
 
 // GetItem retrieves a gallery item by ID, enforcing ownership.
@@ -52,19 +76,67 @@ func (s *GalleryService) GetItem(ctx context.Context, requestorUID string, itemI
 	return item, nil
 }
 
+// GetItems retrieves the gallery items with the given IDs in one batched
+// read, keyed by ID. Items that do not exist or belong to another user are
+// left out, as GetItem would refuse them.
+func (s *GalleryService) GetItems(ctx context.Context, requestorUID string, itemIDs []string) (map[string]*model.GalleryItem, error) {
+	items, err := s.repo.GetByIDs(ctx, itemIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get gallery items: %w", err)
+	}
+	owned := make(map[string]*model.GalleryItem, len(items))
+	for _, item := range items {
+		if item.UserID == requestorUID {
+			owned[item.ID] = item
+		}
+	}
+	return owned, nil
+}
+
+// GetThumbnail decodes a gallery item's thumbnail, falling back to its full
+// image. The same access rule as GetItem applies.
+func (s *GalleryService) GetThumbnail(ctx context.Context, requestorUID string, itemID string) (*Thumbnail, error) {
+	item, err := s.GetItem(ctx, requestorUID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	return decodeThumbnail(item.CreatedAt, item.ThumbnailData, item.ImageData)
+}
+
 // ShareToGallery validates and creates a new gallery item.
 func (s *GalleryService) ShareToGallery(ctx context.Context, uid string, item *model.GalleryItem) (string, error) {
 	item.UserID = uid
+	item.CommentCount = 0 // Counters are server-managed
+	item.ReactionCounts = nil
+	item.Hidden = false // Visibility is moderator-managed
 	item.Sanitize()
 
 	if err := item.Validate(); err != nil {
 		return "", fmt.Errorf("validation: %w", err)
 	}
 
-	return s.repo.Create(ctx, item)
+	id, err := s.repo.Create(ctx, item)
+	if err != nil {
+		return "", err
+	}
+
+	recordAudit(ctx, s.audit, &model.AuditEntry{
+		ActorUID:     uid,
+		Action:       model.AuditActionGalleryShare,
+		ResourceType: model.AuditResourceGallery,
+		ResourceID:   id,
+		Details:      map[string]interface{}{"projectId": item.ProjectID, "name": item.Name},
+	})
+	publishEvent(ctx, s.events, uid, model.WebhookEventGalleryShared, map[string]interface{}{
+		"itemId":    id,
+		"projectId": item.ProjectID,
+		"name":      item.Name,
+	})
+	return id, nil
 }
 
-// DeleteItem verifies ownership and deletes a gallery item.
+// DeleteItem verifies ownership and deletes a gallery item with its
+// comments and reactions.
 func (s *GalleryService) DeleteItem(ctx context.Context, requestorUID string, itemID string) error {
 	if itemID == "" {
 		return fmt.Errorf("item ID is required")
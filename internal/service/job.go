@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pandasWhoCode/paintbar/internal/jobs"
+)
+
+// JobService exposes background job status to the users who started them.
+type JobService struct {
+	runner *jobs.Runner
+}
+
+// NewJobService creates a new JobService.
+func NewJobService(runner *jobs.Runner) *JobService {
+	return &JobService{runner: runner}
+}
+
+// GetJob returns a job's status, enforcing ownership. System jobs (no owner)
+// are not visible to users.
+func (s *JobService) GetJob(ctx context.Context, uid, jobID string) (*jobs.Job, error) {
+	if jobID == "" {
+		return nil, fmt.Errorf("job ID is required")
+	}
+
+	job, err := s.runner.Get(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("get job: %w", err)
+	}
+	if job.UID == "" || job.UID != uid {
+		return nil, fmt.Errorf("unauthorized: you do not have access to this job")
+	}
+	return job, nil
+}
@@ -1,13 +1,18 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/pandasWhoCode/paintbar/internal/model"
+	"github.com/pandasWhoCode/paintbar/internal/repository"
 )
 
 // --- Mock UserRepository ---
@@ -35,6 +40,30 @@ func (r *mockUserRepo) GetByID(_ context.Context, uid string) (*model.User, erro
 	copy := *u
 	return &copy, nil
 }
+func (r *mockUserRepo) GetByIDs(_ context.Context, uids []string) ([]*model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.User
+	for _, id := range uids {
+		if u, ok := r.users[id]; ok {
+			copy := *u
+			result = append(result, &copy)
+		}
+	}
+	return result, nil
+}
+
+func (r *mockUserRepo) GetByUsername(_ context.Context, username string) (*model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.users {
+		if u.Username == username {
+			copy := *u
+			return &copy, nil
+		}
+	}
+	return nil, fmt.Errorf("user %q not found", username)
+}
 
 func (r *mockUserRepo) Create(_ context.Context, user *model.User) error {
 	r.mu.Lock()
@@ -82,12 +111,38 @@ func (r *mockUserRepo) ClaimUsername(_ context.Context, uid string, username str
 	return nil
 }
 
+func (r *mockUserRepo) SetSuspended(_ context.Context, uid string, suspended bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[uid]
+	if !ok {
+		return fmt.Errorf("user %s not found", uid)
+	}
+	u.Suspended = suspended
+	return nil
+}
+
+func (r *mockUserRepo) ReleaseUsername(_ context.Context, username string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	uid, ok := r.usernames[username]
+	if !ok {
+		return "", fmt.Errorf("username %q not found", username)
+	}
+	delete(r.usernames, username)
+	if u, ok := r.users[uid]; ok && u.Username == username {
+		u.Username = ""
+	}
+	return uid, nil
+}
+
 // --- Mock ProjectRepository ---
 
 type mockProjectRepo struct {
-	mu       sync.Mutex
-	projects map[string]*model.Project
-	nextID   int
+	mu         sync.Mutex
+	projects   map[string]*model.Project
+	nextID     int
+	listFields []string // field paths passed to the last List call
 }
 
 func newMockProjectRepo() *mockProjectRepo {
@@ -106,6 +161,18 @@ func (r *mockProjectRepo) GetByID(_ context.Context, projectID string) (*model.P
 	copy := *p
 	return &copy, nil
 }
+func (r *mockProjectRepo) GetByIDs(_ context.Context, projectIDs []string) ([]*model.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.Project
+	for _, id := range projectIDs {
+		if p, ok := r.projects[id]; ok {
+			copy := *p
+			result = append(result, &copy)
+		}
+	}
+	return result, nil
+}
 
 func (r *mockProjectRepo) FindByContentHash(_ context.Context, userID, contentHash string) (*model.Project, error) {
 	r.mu.Lock()
@@ -131,20 +198,69 @@ func (r *mockProjectRepo) FindByTitle(_ context.Context, userID, title string) (
 	return nil, nil
 }
 
-func (r *mockProjectRepo) List(_ context.Context, userID string, limit int, _ string) ([]*model.Project, error) {
+// List applies the tag and isPublic filters and sorts by title or
+// createdAt, with the ID as tiebreak, so cursors can be exercised.
+func (r *mockProjectRepo) List(_ context.Context, userID string, limit int, opts *model.ListOptions, fields []string) ([]*model.Project, *model.Cursor, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	r.listFields = fields
 	var result []*model.Project
 	for _, p := range r.projects {
-		if p.UserID == userID {
+		if listMatches(p, userID, opts) {
 			copy := *p
 			result = append(result, &copy)
-			if len(result) >= limit {
+		}
+	}
+
+	key := func(p *model.Project) string {
+		if opts.Sort == "title" {
+			return p.Title
+		}
+		return p.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		ki, kj := key(result[i])+"\x00"+result[i].ID, key(result[j])+"\x00"+result[j].ID
+		if opts.Order == model.SortAsc {
+			return ki < kj
+		}
+		return ki > kj
+	})
+	if c := opts.Cursor; c != nil {
+		after := c.Value + "\x00" + c.ID
+		for len(result) > 0 {
+			k := key(result[0]) + "\x00" + result[0].ID
+			if (opts.Order == model.SortAsc && k > after) || (opts.Order != model.SortAsc && k < after) {
 				break
 			}
+			result = result[1:]
 		}
 	}
-	return result, nil
+
+	if len(result) <= limit {
+		return result, nil, nil
+	}
+	result = result[:limit]
+	last := result[limit-1]
+	return result, &model.Cursor{Sort: opts.Sort, Order: opts.Order, Filter: opts.FilterKey(), Value: key(last), ID: last.ID}, nil
+}
+
+func (r *mockProjectRepo) CountList(_ context.Context, userID string, opts *model.ListOptions) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var count int64
+	for _, p := range r.projects {
+		if listMatches(p, userID, opts) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// listMatches reports whether p passes the tag and isPublic filters.
+func listMatches(p *model.Project, userID string, opts *model.ListOptions) bool {
+	return p.UserID == userID &&
+		(opts.Tag == "" || slices.Contains(p.Tags, opts.Tag)) &&
+		(opts.IsPublic == nil || p.IsPublic == *opts.IsPublic)
 }
 
 func (r *mockProjectRepo) Count(_ context.Context, userID string) (int64, error) {
@@ -222,9 +338,11 @@ func (r *mockProjectRepo) Delete(_ context.Context, projectID string) error {
 // --- Mock GalleryRepository ---
 
 type mockGalleryRepo struct {
-	mu     sync.Mutex
-	items  map[string]*model.GalleryItem
-	nextID int
+	mu sync.Mutex
+	// subcollections clear an item's comments and reactions on Delete.
+	subcollections []func(itemID string)
+	items          map[string]*model.GalleryItem
+	nextID         int
 }
 
 func newMockGalleryRepo() *mockGalleryRepo {
@@ -243,8 +361,20 @@ func (r *mockGalleryRepo) GetByID(_ context.Context, itemID string) (*model.Gall
 	copy := *item
 	return &copy, nil
 }
+func (r *mockGalleryRepo) GetByIDs(_ context.Context, itemIDs []string) ([]*model.GalleryItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.GalleryItem
+	for _, id := range itemIDs {
+		if item, ok := r.items[id]; ok {
+			copy := *item
+			result = append(result, &copy)
+		}
+	}
+	return result, nil
+}
 
-func (r *mockGalleryRepo) List(_ context.Context, userID string, limit int, _ string) ([]*model.GalleryItem, error) {
+func (r *mockGalleryRepo) List(_ context.Context, userID string, limit int, _ *model.ListOptions, _ []string) ([]*model.GalleryItem, *model.Cursor, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	var result []*model.GalleryItem
@@ -257,9 +387,52 @@ func (r *mockGalleryRepo) List(_ context.Context, userID string, limit int, _ st
 			}
 		}
 	}
+	return result, nil, nil
+}
+
+func (r *mockGalleryRepo) ListByUsers(_ context.Context, userIDs []string, limit int, after *model.Cursor) ([]*model.GalleryItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	wanted := make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		wanted[id] = true
+	}
+	var result []*model.GalleryItem
+	for _, item := range r.items {
+		if wanted[item.UserID] && feedAfter(item, after) {
+			copy := *item
+			result = append(result, &copy)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return feedAfter(result[j], feedPosition(result[i])) })
+	if len(result) > limit {
+		result = result[:limit]
+	}
 	return result, nil
 }
 
+// feedAfter reports whether item comes after position c in a feed ordered
+// newest first, then by descending ID. A nil position is the feed's start.
+func feedAfter(item *model.GalleryItem, c *model.Cursor) bool {
+	if c == nil {
+		return true
+	}
+	t, _ := time.Parse(time.RFC3339Nano, c.Value)
+	if !item.CreatedAt.Equal(t) {
+		return item.CreatedAt.Before(t)
+	}
+	return item.ID < c.ID
+}
+
+// feedPosition returns item's position in a feed.
+func feedPosition(item *model.GalleryItem) *model.Cursor {
+	return &model.Cursor{Value: item.CreatedAt.Format(time.RFC3339Nano), ID: item.ID}
+}
+
+func (r *mockGalleryRepo) CountList(ctx context.Context, userID string, _ *model.ListOptions) (int64, error) {
+	return r.Count(ctx, userID)
+}
+
 func (r *mockGalleryRepo) Count(_ context.Context, userID string) (int64, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -284,11 +457,26 @@ func (r *mockGalleryRepo) Create(_ context.Context, item *model.GalleryItem) (st
 
 func (r *mockGalleryRepo) Delete(_ context.Context, itemID string) error {
 	r.mu.Lock()
-	defer r.mu.Unlock()
 	if _, ok := r.items[itemID]; !ok {
+		r.mu.Unlock()
 		return fmt.Errorf("gallery item %s not found", itemID)
 	}
 	delete(r.items, itemID)
+	r.mu.Unlock()
+	for _, clear := range r.subcollections {
+		clear(itemID)
+	}
+	return nil
+}
+
+func (r *mockGalleryRepo) SetHidden(_ context.Context, itemID string, hidden bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.items[itemID]
+	if !ok {
+		return fmt.Errorf("gallery item %s not found", itemID)
+	}
+	item.Hidden = hidden
 	return nil
 }
 
@@ -316,8 +504,20 @@ func (r *mockNFTRepo) GetByID(_ context.Context, nftID string) (*model.NFT, erro
 	copy := *nft
 	return &copy, nil
 }
+func (r *mockNFTRepo) GetByIDs(_ context.Context, nftIDs []string) ([]*model.NFT, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.NFT
+	for _, id := range nftIDs {
+		if nft, ok := r.nfts[id]; ok {
+			copy := *nft
+			result = append(result, &copy)
+		}
+	}
+	return result, nil
+}
 
-func (r *mockNFTRepo) List(_ context.Context, userID string, limit int, _ string) ([]*model.NFT, error) {
+func (r *mockNFTRepo) List(_ context.Context, userID string, limit int, _ *model.ListOptions, _ []string) ([]*model.NFT, *model.Cursor, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	var result []*model.NFT
@@ -330,7 +530,11 @@ func (r *mockNFTRepo) List(_ context.Context, userID string, limit int, _ string
 			}
 		}
 	}
-	return result, nil
+	return result, nil, nil
+}
+
+func (r *mockNFTRepo) CountList(ctx context.Context, userID string, _ *model.ListOptions) (int64, error) {
+	return r.Count(ctx, userID)
 }
 
 func (r *mockNFTRepo) Count(_ context.Context, userID string) (int64, error) {
@@ -355,12 +559,16 @@ func (r *mockNFTRepo) Create(_ context.Context, nft *model.NFT) (string, error)
 	return id, nil
 }
 
-func (r *mockNFTRepo) Update(_ context.Context, nftID string, _ map[string]interface{}) error {
+func (r *mockNFTRepo) Update(_ context.Context, nftID string, updates map[string]interface{}) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if _, ok := r.nfts[nftID]; !ok {
+	n, ok := r.nfts[nftID]
+	if !ok {
 		return fmt.Errorf("nft %s not found", nftID)
 	}
+	if v, ok := updates["hidden"]; ok {
+		n.Hidden = v.(bool)
+	}
 	return nil
 }
 
@@ -374,6 +582,314 @@ func (r *mockNFTRepo) Delete(_ context.Context, nftID string) error {
 	return nil
 }
 
+// --- Mock FollowRepository ---
+
+type mockFollowRepo struct {
+	mu        sync.Mutex
+	users     *mockUserRepo
+	following map[string]map[string]time.Time // follower -> followee -> since
+}
+
+func newMockFollowRepo(users *mockUserRepo) *mockFollowRepo {
+	return &mockFollowRepo{
+		users:     users,
+		following: make(map[string]map[string]time.Time),
+	}
+}
+
+func (r *mockFollowRepo) Follow(_ context.Context, follower, followee *model.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.following[follower.UID] == nil {
+		r.following[follower.UID] = make(map[string]time.Time)
+	}
+	if _, ok := r.following[follower.UID][followee.UID]; ok {
+		return nil
+	}
+	r.users.mu.Lock()
+	defer r.users.mu.Unlock()
+	if r.users.users[follower.UID].FollowingCount >= repository.MaxFollowing {
+		return fmt.Errorf("validation: cannot follow more than %d users", repository.MaxFollowing)
+	}
+	r.following[follower.UID][followee.UID] = time.Now()
+	r.users.users[follower.UID].FollowingCount++
+	r.users.users[followee.UID].FollowerCount++
+	return nil
+}
+
+func (r *mockFollowRepo) Unfollow(_ context.Context, followerUID, followeeUID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.following[followerUID][followeeUID]; !ok {
+		return nil
+	}
+	delete(r.following[followerUID], followeeUID)
+	r.users.mu.Lock()
+	r.users.users[followerUID].FollowingCount--
+	r.users.users[followeeUID].FollowerCount--
+	r.users.mu.Unlock()
+	return nil
+}
+
+func (r *mockFollowRepo) IsFollowing(_ context.Context, followerUID, followeeUID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.following[followerUID][followeeUID]
+	return ok, nil
+}
+
+func (r *mockFollowRepo) ListFollowers(_ context.Context, uid string, limit int, _ string) ([]*model.Follow, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.Follow
+	for follower, followees := range r.following {
+		if since, ok := followees[uid]; ok {
+			result = append(result, &model.Follow{UID: follower, CreatedAt: since})
+		}
+	}
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (r *mockFollowRepo) ListFollowing(_ context.Context, uid string, limit int, _ string) ([]*model.Follow, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.Follow
+	for followee, since := range r.following[uid] {
+		result = append(result, &model.Follow{UID: followee, CreatedAt: since})
+	}
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (r *mockFollowRepo) FollowingIDs(_ context.Context, uid string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ids []string
+	for followee := range r.following[uid] {
+		ids = append(ids, followee)
+	}
+	return ids, nil
+}
+
+// --- Shared mock repositories ---
+
+// mockRepos wires together the mock repositories the social services share,
+// so a follow or a gallery item created through one is visible to the others.
+type mockRepos struct {
+	users    *mockUserRepo
+	follows  *mockFollowRepo
+	gallery  *mockGalleryRepo
+	comments *mockCommentRepo
+	nfts     *mockNFTRepo
+	reports  *mockReportRepo
+	audit    *mockAuditLogger
+	accounts *mockAccountManager
+}
+
+// newMockRepos returns mock repositories holding an account and a user who
+// has claimed a username for each uid.
+func newMockRepos(uids ...string) *mockRepos {
+	users := newMockUserRepo()
+	accounts := newMockAccountManager()
+	for _, uid := range uids {
+		users.users[uid] = &model.User{UID: uid, Email: uid + "@example.com", Username: uid}
+		users.usernames[uid] = uid
+		accounts.accounts[uid] = &model.Account{UID: uid, Email: uid + "@example.com"}
+	}
+	gallery := newMockGalleryRepo()
+	return &mockRepos{
+		users:    users,
+		follows:  newMockFollowRepo(users),
+		gallery:  gallery,
+		comments: newMockCommentRepo(gallery),
+		nfts:     newMockNFTRepo(),
+		reports:  newMockReportRepo(),
+		audit:    newMockAuditLogger(),
+		accounts: accounts,
+	}
+}
+
+// --- Mock CommentRepository ---
+
+type mockCommentRepo struct {
+	mu       sync.Mutex
+	gallery  *mockGalleryRepo
+	comments map[string]*model.Comment
+	nextID   int
+}
+
+func newMockCommentRepo(gallery *mockGalleryRepo) *mockCommentRepo {
+	r := &mockCommentRepo{
+		gallery:  gallery,
+		comments: make(map[string]*model.Comment),
+	}
+	gallery.subcollections = append(gallery.subcollections, func(itemID string) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		for id, c := range r.comments {
+			if c.ItemID == itemID {
+				delete(r.comments, id)
+			}
+		}
+	})
+	return r
+}
+
+func (r *mockCommentRepo) GetByID(_ context.Context, itemID, commentID string) (*model.Comment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.comments[commentID]
+	if !ok || c.ItemID != itemID {
+		return nil, fmt.Errorf("comment %s not found", commentID)
+	}
+	copy := *c
+	return &copy, nil
+}
+
+func (r *mockCommentRepo) List(_ context.Context, itemID string, limit int, _ string) ([]*model.Comment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.Comment
+	for _, c := range r.comments {
+		if c.ItemID == itemID && !c.Hidden {
+			copy := *c
+			result = append(result, &copy)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (r *mockCommentRepo) Create(_ context.Context, itemID string, comment *model.Comment) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	id := fmt.Sprintf("c_%03d", r.nextID)
+	comment.ID = id
+	comment.ItemID = itemID
+	r.comments[id] = comment
+	r.gallery.mu.Lock()
+	r.gallery.items[itemID].CommentCount++
+	r.gallery.mu.Unlock()
+	return id, nil
+}
+
+func (r *mockCommentRepo) UpdateBody(_ context.Context, _, commentID, body string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.comments[commentID]
+	if !ok {
+		return fmt.Errorf("comment %s not found", commentID)
+	}
+	c.Body = body
+	c.Edited = true
+	return nil
+}
+
+func (r *mockCommentRepo) Delete(_ context.Context, itemID, commentID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	removed := int64(0)
+	for id, c := range r.comments {
+		if id == commentID || c.ParentID == commentID {
+			delete(r.comments, id)
+			removed++
+		}
+	}
+	r.gallery.mu.Lock()
+	r.gallery.items[itemID].CommentCount -= removed
+	r.gallery.mu.Unlock()
+	return nil
+}
+
+func (r *mockCommentRepo) SetHidden(_ context.Context, _, commentID string, hidden bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.comments[commentID]
+	if !ok {
+		return fmt.Errorf("comment %s not found", commentID)
+	}
+	c.Hidden = hidden
+	return nil
+}
+
+// --- Mock ReactionRepository ---
+
+type mockReactionRepo struct {
+	mu        sync.Mutex
+	gallery   *mockGalleryRepo
+	reactions map[string]map[string]bool // itemID -> "uid_reaction" -> present
+}
+
+func newMockReactionRepo(gallery *mockGalleryRepo) *mockReactionRepo {
+	r := &mockReactionRepo{
+		gallery:   gallery,
+		reactions: make(map[string]map[string]bool),
+	}
+	gallery.subcollections = append(gallery.subcollections, func(itemID string) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.reactions, itemID)
+	})
+	return r
+}
+
+func (r *mockReactionRepo) Add(_ context.Context, itemID, uid, reaction string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.reactions[itemID] == nil {
+		r.reactions[itemID] = make(map[string]bool)
+	}
+	key := uid + "_" + reaction
+	if r.reactions[itemID][key] {
+		return nil
+	}
+	r.reactions[itemID][key] = true
+	r.gallery.mu.Lock()
+	item := r.gallery.items[itemID]
+	if item.ReactionCounts == nil {
+		item.ReactionCounts = make(map[string]int64)
+	}
+	item.ReactionCounts[reaction]++
+	r.gallery.mu.Unlock()
+	return nil
+}
+
+func (r *mockReactionRepo) Remove(_ context.Context, itemID, uid, reaction string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := uid + "_" + reaction
+	if !r.reactions[itemID][key] {
+		return nil
+	}
+	delete(r.reactions[itemID], key)
+	r.gallery.mu.Lock()
+	r.gallery.items[itemID].ReactionCounts[reaction]--
+	r.gallery.mu.Unlock()
+	return nil
+}
+
+func (r *mockReactionRepo) ListByUser(_ context.Context, itemID, uid string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	mine := []string{}
+	for key := range r.reactions[itemID] {
+		if strings.HasPrefix(key, uid+"_") {
+			mine = append(mine, strings.TrimPrefix(key, uid+"_"))
+		}
+	}
+	sort.Strings(mine)
+	return mine, nil
+}
+
 // --- Failing mock variants for error-path coverage ---
 
 // failingFindByContentHashRepo fails on FindByContentHash.
@@ -404,6 +920,10 @@ func (c *failingReadObjectStorageClient) ReadObject(_ context.Context, _ string)
 	return nil, fmt.Errorf("storage read failed")
 }
 
+func (c *failingReadObjectStorageClient) ReadObjectFrom(_ context.Context, _ string, _ int64) (io.ReadCloser, error) {
+	return nil, fmt.Errorf("storage read failed")
+}
+
 // failingObjectExistsStorageClient fails on ObjectExists.
 type failingObjectExistsStorageClient struct{ mockStorageClient }
 
@@ -412,8 +932,562 @@ func (c *failingObjectExistsStorageClient) ObjectExists(_ context.Context, _ str
 }
 
 // failingDownloadURLStorageClient fails on GenerateDownloadURL.
-type failingDownloadURLStorageClient struct{ mockStorageClient }
+// --- Mock ReportRepository ---
+
+type mockReportRepo struct {
+	mu      sync.Mutex
+	reports map[string]*model.Report
+}
+
+func newMockReportRepo() *mockReportRepo {
+	return &mockReportRepo{reports: make(map[string]*model.Report)}
+}
+
+func (r *mockReportRepo) GetByID(_ context.Context, reportID string) (*model.Report, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rep, ok := r.reports[reportID]
+	if !ok {
+		return nil, fmt.Errorf("report %s not found", reportID)
+	}
+	copy := *rep
+	return &copy, nil
+}
+
+func (r *mockReportRepo) List(_ context.Context, status string, limit int, _ string) ([]*model.Report, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.Report
+	for _, rep := range r.reports {
+		if rep.Status == status {
+			copy := *rep
+			result = append(result, &copy)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (r *mockReportRepo) Create(_ context.Context, report *model.Report) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := report.ReporterID + "_" + report.TargetType + "_" + report.TargetID
+	if _, exists := r.reports[id]; exists {
+		return "", fmt.Errorf("report already exists for this %s", report.TargetType)
+	}
+	report.ID = id
+	r.reports[id] = report
+	return id, nil
+}
+
+func (r *mockReportRepo) Resolve(_ context.Context, reportID, status, resolvedBy, note string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rep, ok := r.reports[reportID]
+	if !ok {
+		return fmt.Errorf("report %s not found", reportID)
+	}
+	rep.Status = status
+	rep.ResolvedBy = resolvedBy
+	rep.Note = note
+	return nil
+}
+
+// --- Mock AuditLogger ---
+
+type mockAuditLogger struct {
+	mu      sync.Mutex
+	entries []*model.AuditEntry
+	err     error
+}
+
+func newMockAuditLogger() *mockAuditLogger {
+	return &mockAuditLogger{}
+}
+
+func (r *mockAuditLogger) Log(_ context.Context, entry *model.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	entry.ID = fmt.Sprintf("audit_%03d", len(r.entries)+1)
+	entry.CreatedAt = time.Now()
+	copy := *entry
+	r.entries = append(r.entries, &copy)
+	return nil
+}
+
+func (r *mockAuditLogger) List(_ context.Context, actorUID string, limit int, _ string) ([]*model.AuditEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.AuditEntry
+	for i := len(r.entries) - 1; i >= 0 && len(result) < limit; i-- {
+		if actorUID != "" && r.entries[i].ActorUID != actorUID {
+			continue
+		}
+		copy := *r.entries[i]
+		result = append(result, &copy)
+	}
+	return result, nil
+}
+
+// actions returns the recorded audit actions in order.
+func (r *mockAuditLogger) actions() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	actions := make([]string, len(r.entries))
+	for i, e := range r.entries {
+		actions[i] = e.Action
+	}
+	return actions
+}
+
+// --- Mock StatsRepository ---
+
+type mockStatsRepo struct {
+	stats model.UsageStats
+	// stored and actual are each user's counters and true counts
+	stored  map[string]model.UserStats
+	actual  map[string]model.UserStats
+	applied []string
+}
+
+func (r *mockStatsRepo) Usage(_ context.Context) (*model.UsageStats, error) {
+	copy := r.stats
+	copy.GeneratedAt = time.Now()
+	return &copy, nil
+}
+
+func (r *mockStatsRepo) RecountUser(_ context.Context, uid string, apply bool) (*model.CounterRepair, error) {
+	stored, actual := r.stored[uid], r.actual[uid]
+	repair := &model.CounterRepair{UID: uid, Stored: &stored, Actual: &actual}
+	if apply && repair.Drifted() {
+		r.stored[uid] = actual
+		r.applied = append(r.applied, uid)
+	}
+	return repair, nil
+}
+
+func (r *mockStatsRepo) ListUserIDs(_ context.Context, limit int, startAfter string) ([]string, error) {
+	var uids []string
+	for uid := range r.stored {
+		if uid > startAfter {
+			uids = append(uids, uid)
+		}
+	}
+	sort.Strings(uids)
+	if len(uids) > limit {
+		uids = uids[:limit]
+	}
+	return uids, nil
+}
+
+// --- Mock AccountManager ---
+
+type mockAccountManager struct {
+	mu       sync.Mutex
+	accounts map[string]*model.Account
+}
+
+func newMockAccountManager() *mockAccountManager {
+	return &mockAccountManager{accounts: make(map[string]*model.Account)}
+}
+
+func (m *mockAccountManager) GetAccount(_ context.Context, uid string) (*model.Account, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a, ok := m.accounts[uid]
+	if !ok {
+		return nil, fmt.Errorf("account %s not found", uid)
+	}
+	copy := *a
+	return &copy, nil
+}
+
+func (m *mockAccountManager) GetAccountByEmail(_ context.Context, email string) (*model.Account, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, a := range m.accounts {
+		if strings.EqualFold(a.Email, email) {
+			copy := *a
+			return &copy, nil
+		}
+	}
+	return nil, fmt.Errorf("account for %q not found", email)
+}
+
+func (m *mockAccountManager) SetAccountDisabled(_ context.Context, uid string, disabled bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a, ok := m.accounts[uid]
+	if !ok {
+		return fmt.Errorf("account %s not found", uid)
+	}
+	a.Disabled = disabled
+	return nil
+}
+
+type failingDownloadURLStorageClient struct{ mockStorageClient }
 
 func (c *failingDownloadURLStorageClient) GenerateDownloadURL(_ string, _ time.Duration) (string, error) {
 	return "", fmt.Errorf("download url failed")
 }
+
+// --- Mock APITokenRepository ---
+
+type mockAPITokenRepo struct {
+	mu      sync.Mutex
+	tokens  map[string]*model.APIToken
+	nextID  int
+	touched int
+}
+
+func newMockAPITokenRepo() *mockAPITokenRepo {
+	return &mockAPITokenRepo{tokens: make(map[string]*model.APIToken)}
+}
+
+func (r *mockAPITokenRepo) GetByID(_ context.Context, tokenID string) (*model.APIToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tok, ok := r.tokens[tokenID]
+	if !ok {
+		return nil, fmt.Errorf("api token %s not found", tokenID)
+	}
+	copy := *tok
+	return &copy, nil
+}
+
+func (r *mockAPITokenRepo) GetByHash(_ context.Context, hash string) (*model.APIToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, tok := range r.tokens {
+		if tok.Hash == hash {
+			copy := *tok
+			return &copy, nil
+		}
+	}
+	return nil, fmt.Errorf("api token not found")
+}
+
+func (r *mockAPITokenRepo) ListByUser(_ context.Context, uid string) ([]*model.APIToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.APIToken
+	for _, tok := range r.tokens {
+		if tok.UID == uid {
+			copy := *tok
+			result = append(result, &copy)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID > result[j].ID })
+	return result, nil
+}
+
+func (r *mockAPITokenRepo) Create(_ context.Context, token *model.APIToken) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	id := fmt.Sprintf("tok%03d", r.nextID)
+	token.ID = id
+	token.CreatedAt = time.Now()
+	copy := *token
+	r.tokens[id] = &copy
+	return id, nil
+}
+
+func (r *mockAPITokenRepo) Delete(_ context.Context, tokenID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.tokens, tokenID)
+	return nil
+}
+
+func (r *mockAPITokenRepo) TouchLastUsed(_ context.Context, tokenID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if tok, ok := r.tokens[tokenID]; ok {
+		tok.LastUsedAt = &at
+		r.touched++
+	}
+	return nil
+}
+
+// --- Mock WebhookRepository ---
+
+type mockWebhookRepo struct {
+	mu         sync.Mutex
+	webhooks   map[string]*model.Webhook
+	deliveries map[string][]*model.WebhookDelivery
+	nextID     int
+}
+
+func newMockWebhookRepo() *mockWebhookRepo {
+	return &mockWebhookRepo{
+		webhooks:   make(map[string]*model.Webhook),
+		deliveries: make(map[string][]*model.WebhookDelivery),
+	}
+}
+
+func (r *mockWebhookRepo) GetByID(_ context.Context, webhookID string) (*model.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	webhook, ok := r.webhooks[webhookID]
+	if !ok {
+		return nil, fmt.Errorf("webhook %s not found", webhookID)
+	}
+	copy := *webhook
+	return &copy, nil
+}
+
+func (r *mockWebhookRepo) ListByUser(_ context.Context, uid string) ([]*model.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.Webhook
+	for _, webhook := range r.webhooks {
+		if webhook.UID == uid {
+			copy := *webhook
+			result = append(result, &copy)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID > result[j].ID })
+	return result, nil
+}
+
+func (r *mockWebhookRepo) Create(_ context.Context, webhook *model.Webhook) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	id := fmt.Sprintf("wh%03d", r.nextID)
+	webhook.ID = id
+	webhook.CreatedAt = time.Now()
+	copy := *webhook
+	r.webhooks[id] = &copy
+	return id, nil
+}
+
+func (r *mockWebhookRepo) Delete(_ context.Context, webhookID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.webhooks, webhookID)
+	delete(r.deliveries, webhookID)
+	return nil
+}
+
+func (r *mockWebhookRepo) LogDelivery(_ context.Context, webhookID string, delivery *model.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delivery.ID = fmt.Sprintf("d%03d", len(r.deliveries[webhookID])+1)
+	copy := *delivery
+	r.deliveries[webhookID] = append(r.deliveries[webhookID], &copy)
+	return nil
+}
+
+func (r *mockWebhookRepo) ListDeliveries(_ context.Context, webhookID string, limit int, _ string) ([]*model.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.WebhookDelivery
+	log := r.deliveries[webhookID]
+	for i := len(log) - 1; i >= 0 && len(result) < limit; i-- {
+		copy := *log[i]
+		result = append(result, &copy)
+	}
+	return result, nil
+}
+
+// --- Mock EventPublisher ---
+
+type publishedEvent struct {
+	uid   string
+	event string
+	data  map[string]interface{}
+}
+
+type mockEventPublisher struct {
+	mu     sync.Mutex
+	events []publishedEvent
+}
+
+func (m *mockEventPublisher) Publish(_ context.Context, uid, event string, data map[string]interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, publishedEvent{uid: uid, event: event, data: data})
+}
+
+func (m *mockEventPublisher) names() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := make([]string, len(m.events))
+	for i, e := range m.events {
+		names[i] = e.event
+	}
+	return names
+}
+
+// --- Mock UploadSessionRepository ---
+
+type mockUploadSessionRepo struct {
+	mu       sync.Mutex
+	sessions map[string]*model.UploadSession
+	nextID   int
+}
+
+func newMockUploadSessionRepo() *mockUploadSessionRepo {
+	return &mockUploadSessionRepo{sessions: make(map[string]*model.UploadSession)}
+}
+
+func (r *mockUploadSessionRepo) Create(_ context.Context, session *model.UploadSession) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	id := fmt.Sprintf("upload_%d", r.nextID)
+	session.ID = id
+	copy := *session
+	r.sessions[id] = &copy
+	return id, nil
+}
+
+func (r *mockUploadSessionRepo) GetByID(_ context.Context, uploadID string) (*model.UploadSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sessions[uploadID]
+	if !ok {
+		return nil, fmt.Errorf("upload session %s not found", uploadID)
+	}
+	copy := *s
+	copy.Chunks = append([]model.UploadChunk(nil), s.Chunks...)
+	return &copy, nil
+}
+
+func (r *mockUploadSessionRepo) AppendChunk(_ context.Context, uploadID string, chunk model.UploadChunk) (*model.UploadSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sessions[uploadID]
+	if !ok {
+		return nil, fmt.Errorf("upload session %s not found", uploadID)
+	}
+	if s.Status != model.UploadStatusOpen {
+		return nil, fmt.Errorf("invalid upload: session is %s", s.Status)
+	}
+	if chunk.Offset != s.Offset {
+		return nil, fmt.Errorf("invalid range: upload offset is %d", s.Offset)
+	}
+	s.Chunks = append(s.Chunks, chunk)
+	s.Offset += chunk.Size
+	copy := *s
+	copy.Chunks = append([]model.UploadChunk(nil), s.Chunks...)
+	return &copy, nil
+}
+
+func (r *mockUploadSessionRepo) MarkFinalizing(_ context.Context, uploadID, jobID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sessions[uploadID]
+	if !ok {
+		return fmt.Errorf("upload session %s not found", uploadID)
+	}
+	if s.Status == model.UploadStatusFinalizing {
+		return nil
+	}
+	if !s.Complete() {
+		return fmt.Errorf("invalid upload: received %d of %d bytes", s.Offset, s.Size)
+	}
+	s.Status = model.UploadStatusFinalizing
+	s.JobID = jobID
+	return nil
+}
+
+func (r *mockUploadSessionRepo) Delete(_ context.Context, uploadID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sessions, uploadID)
+	return nil
+}
+
+func (r *mockUploadSessionRepo) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.sessions)
+}
+
+// memoryStorageClient keeps object contents so chunked uploads can be
+// reassembled and checked.
+type memoryStorageClient struct {
+	mockStorageClient
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func newMemoryStorageClient() *memoryStorageClient {
+	return &memoryStorageClient{data: make(map[string][]byte)}
+}
+
+func (m *memoryStorageClient) ObjectExists(_ context.Context, objectPath string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.data[objectPath]
+	return ok, nil
+}
+
+func (m *memoryStorageClient) ReadObject(_ context.Context, objectPath string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b, ok := m.data[objectPath]
+	if !ok {
+		return nil, fmt.Errorf("object not found: %s", objectPath)
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (m *memoryStorageClient) ReadObjectFrom(_ context.Context, objectPath string, offset int64) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b, ok := m.data[objectPath]
+	if !ok {
+		return nil, fmt.Errorf("object not found: %s", objectPath)
+	}
+	return io.NopCloser(bytes.NewReader(b[offset:])), nil
+}
+
+func (m *memoryStorageClient) StatObject(_ context.Context, objectPath string) (*repository.ObjectAttrs, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b, ok := m.data[objectPath]
+	if !ok {
+		return nil, fmt.Errorf("object not found: %s", objectPath)
+	}
+	return &repository.ObjectAttrs{Size: int64(len(b)), Updated: mockObjectUpdated}, nil
+}
+
+func (m *memoryStorageClient) WriteObject(_ context.Context, objectPath string, data io.Reader, _ string) error {
+	b, err := io.ReadAll(data)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[objectPath] = b
+	return nil
+}
+
+func (m *memoryStorageClient) DeleteObject(_ context.Context, objectPath string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, objectPath)
+	return nil
+}
+
+// paths returns the stored object paths, sorted.
+func (m *memoryStorageClient) paths() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	paths := make([]string, 0, len(m.data))
+	for p := range m.data {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,338 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"github.com/pandasWhoCode/paintbar/internal/repository"
+)
+
+// MaxModerationNoteLen is the maximum length of an admin's resolution note.
+const MaxModerationNoteLen = 1000
+
+// ModerationService handles abuse reports and the admin actions taken on
+// them: hiding or removing content and suspending users. Callers of the
+// admin methods must already have been authorized (see middleware.RequireRole);
+// each admin action is written to the audit log.
+type ModerationService struct {
+	reports  repository.ReportRepository
+	users    repository.UserRepository
+	gallery  repository.GalleryRepository
+	comments repository.CommentRepository
+	nfts     repository.NFTRepository
+	audit    repository.AuditLogger
+}
+
+// NewModerationService creates a new ModerationService.
+func NewModerationService(
+	reports repository.ReportRepository,
+	users repository.UserRepository,
+	gallery repository.GalleryRepository,
+	comments repository.CommentRepository,
+	nfts repository.NFTRepository,
+	audit repository.AuditLogger,
+) *ModerationService {
+	return &ModerationService{
+		reports:  reports,
+		users:    users,
+		gallery:  gallery,
+		comments: comments,
+		nfts:     nfts,
+		audit:    audit,
+	}
+}
+
+// CreateReport validates a report, checks that its target exists and adds it
+// to the moderation queue. A user may report each target once.
+func (s *ModerationService) CreateReport(ctx context.Context, reporterUID string, report *model.Report) (string, error) {
+	report.ReporterID = reporterUID
+	report.Status = model.ReportStatusOpen
+	report.ResolvedBy = ""
+	report.Note = ""
+	report.Sanitize()
+
+	if err := report.Validate(); err != nil {
+		return "", fmt.Errorf("validation: %w", err)
+	}
+	if report.TargetType == model.ReportTargetUser && report.TargetID == reporterUID {
+		return "", fmt.Errorf("invalid report: you cannot report yourself")
+	}
+
+	var err error
+	switch report.TargetType {
+	case model.ReportTargetGallery:
+		_, err = visibleGalleryItem(ctx, s.gallery, report.TargetID)
+	case model.ReportTargetComment:
+		_, err = s.comments.GetByID(ctx, report.ItemID, report.TargetID)
+	case model.ReportTargetUser:
+		_, err = s.users.GetByID(ctx, report.TargetID)
+	case model.ReportTargetNFT:
+		_, err = s.nfts.GetByID(ctx, report.TargetID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("get reported %s: %w", report.TargetType, err)
+	}
+
+	return s.reports.Create(ctx, report)
+}
+
+// ListReports returns a page of reports with the given status (default
+// open), oldest first.
+func (s *ModerationService) ListReports(ctx context.Context, status string, limit int, startAfter string) ([]*model.Report, error) {
+	if status == "" {
+		status = model.ReportStatusOpen
+	}
+	if status != model.ReportStatusOpen {
+		if err := model.ValidateReportResolution(status); err != nil {
+			return nil, err
+		}
+	}
+	return s.reports.List(ctx, status, clampPageSize(limit), startAfter)
+}
+
+// ResolveReport closes a report as actioned or dismissed. Resolving an
+// already-closed report overwrites its previous resolution.
+func (s *ModerationService) ResolveReport(ctx context.Context, adminUID, reportID, status, note string) error {
+	if reportID == "" {
+		return fmt.Errorf("report ID is required")
+	}
+	if err := model.ValidateReportResolution(status); err != nil {
+		return err
+	}
+	note = model.StripControlChars(strings.TrimSpace(note))
+	if utf8.RuneCountInString(note) > MaxModerationNoteLen {
+		return fmt.Errorf("note must be %d characters or less", MaxModerationNoteLen)
+	}
+
+	report, err := s.reports.GetByID(ctx, reportID)
+	if err != nil {
+		return fmt.Errorf("get report: %w", err)
+	}
+	if err := s.reports.Resolve(ctx, reportID, status, adminUID, note); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.audit, &model.AuditEntry{
+		ActorUID:     adminUID,
+		Action:       model.AuditActionReportResolve,
+		ResourceType: model.AuditResourceReport,
+		ResourceID:   reportID,
+		Changes: model.AuditDiff(
+			map[string]interface{}{"status": report.Status, "note": report.Note},
+			map[string]interface{}{"status": status, "note": note},
+		),
+	})
+	return nil
+}
+
+// SetGalleryItemHidden hides or unhides a gallery item.
+func (s *ModerationService) SetGalleryItemHidden(ctx context.Context, adminUID, itemID string, hidden bool) error {
+	if itemID == "" {
+		return fmt.Errorf("item ID is required")
+	}
+	item, err := s.gallery.GetByID(ctx, itemID)
+	if err != nil {
+		return fmt.Errorf("get gallery item: %w", err)
+	}
+	if err := s.gallery.SetHidden(ctx, itemID, hidden); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.audit, &model.AuditEntry{
+		ActorUID:     adminUID,
+		Action:       pickAction(hidden, model.AuditActionGalleryHide, model.AuditActionGalleryUnhide),
+		ResourceType: model.AuditResourceGallery,
+		ResourceID:   itemID,
+		Changes:      flagChange("hidden", item.Hidden, hidden),
+	})
+	return nil
+}
+
+// RemoveGalleryItem permanently deletes a gallery item.
+func (s *ModerationService) RemoveGalleryItem(ctx context.Context, adminUID, itemID string) error {
+	if itemID == "" {
+		return fmt.Errorf("item ID is required")
+	}
+	item, err := s.gallery.GetByID(ctx, itemID)
+	if err != nil {
+		return fmt.Errorf("get gallery item: %w", err)
+	}
+	if err := s.gallery.Delete(ctx, itemID); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.audit, &model.AuditEntry{
+		ActorUID:     adminUID,
+		Action:       model.AuditActionGalleryRemove,
+		ResourceType: model.AuditResourceGallery,
+		ResourceID:   itemID,
+		Details:      map[string]interface{}{"ownerUid": item.UserID, "name": item.Name},
+	})
+	return nil
+}
+
+// SetCommentHidden hides or unhides a comment.
+func (s *ModerationService) SetCommentHidden(ctx context.Context, adminUID, itemID, commentID string, hidden bool) error {
+	if itemID == "" || commentID == "" {
+		return fmt.Errorf("item ID and comment ID are required")
+	}
+	comment, err := s.comments.GetByID(ctx, itemID, commentID)
+	if err != nil {
+		return fmt.Errorf("get comment: %w", err)
+	}
+	if err := s.comments.SetHidden(ctx, itemID, commentID, hidden); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.audit, &model.AuditEntry{
+		ActorUID:     adminUID,
+		Action:       pickAction(hidden, model.AuditActionCommentHide, model.AuditActionCommentUnhide),
+		ResourceType: model.AuditResourceComment,
+		ResourceID:   commentID,
+		Changes:      flagChange("hidden", comment.Hidden, hidden),
+		Details:      map[string]interface{}{"itemId": itemID},
+	})
+	return nil
+}
+
+// RemoveComment permanently deletes a comment and its replies.
+func (s *ModerationService) RemoveComment(ctx context.Context, adminUID, itemID, commentID string) error {
+	if itemID == "" || commentID == "" {
+		return fmt.Errorf("item ID and comment ID are required")
+	}
+	comment, err := s.comments.GetByID(ctx, itemID, commentID)
+	if err != nil {
+		return fmt.Errorf("get comment: %w", err)
+	}
+	if err := s.comments.Delete(ctx, itemID, commentID); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.audit, &model.AuditEntry{
+		ActorUID:     adminUID,
+		Action:       model.AuditActionCommentRemove,
+		ResourceType: model.AuditResourceComment,
+		ResourceID:   commentID,
+		Details:      map[string]interface{}{"itemId": itemID, "authorUid": comment.UserID},
+	})
+	return nil
+}
+
+// SetNFTHidden hides or unhides an NFT.
+func (s *ModerationService) SetNFTHidden(ctx context.Context, adminUID, nftID string, hidden bool) error {
+	if nftID == "" {
+		return fmt.Errorf("NFT ID is required")
+	}
+	nft, err := s.nfts.GetByID(ctx, nftID)
+	if err != nil {
+		return fmt.Errorf("get NFT: %w", err)
+	}
+	if err := s.nfts.Update(ctx, nftID, map[string]interface{}{"hidden": hidden}); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.audit, &model.AuditEntry{
+		ActorUID:     adminUID,
+		Action:       pickAction(hidden, model.AuditActionNFTHide, model.AuditActionNFTUnhide),
+		ResourceType: model.AuditResourceNFT,
+		ResourceID:   nftID,
+		Changes:      flagChange("hidden", nft.Hidden, hidden),
+	})
+	return nil
+}
+
+// RemoveNFT permanently deletes an NFT record.
+func (s *ModerationService) RemoveNFT(ctx context.Context, adminUID, nftID string) error {
+	if nftID == "" {
+		return fmt.Errorf("NFT ID is required")
+	}
+	nft, err := s.nfts.GetByID(ctx, nftID)
+	if err != nil {
+		return fmt.Errorf("get NFT: %w", err)
+	}
+	if err := s.nfts.Delete(ctx, nftID); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.audit, &model.AuditEntry{
+		ActorUID:     adminUID,
+		Action:       model.AuditActionNFTRemove,
+		ResourceType: model.AuditResourceNFT,
+		ResourceID:   nftID,
+		Details:      map[string]interface{}{"ownerUid": nft.UserID, "name": nft.Name},
+	})
+	return nil
+}
+
+// SetUserSuspended suspends or reinstates a user. Suspended users cannot
+// make state-changing requests and their public profile is hidden.
+func (s *ModerationService) SetUserSuspended(ctx context.Context, adminUID, uid string, suspended bool) error {
+	if uid == "" {
+		return fmt.Errorf("uid is required")
+	}
+	if uid == adminUID {
+		return fmt.Errorf("invalid request: you cannot suspend yourself")
+	}
+	user, err := s.users.GetByID(ctx, uid)
+	if err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+	if err := s.users.SetSuspended(ctx, uid, suspended); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.audit, &model.AuditEntry{
+		ActorUID:     adminUID,
+		Action:       pickAction(suspended, model.AuditActionUserSuspend, model.AuditActionUserUnsuspend),
+		ResourceType: model.AuditResourceUser,
+		ResourceID:   uid,
+		Changes:      flagChange("suspended", user.Suspended, suspended),
+	})
+	return nil
+}
+
+// IsSuspended reports whether the user has been suspended. Users without a
+// profile document yet are not suspended.
+func (s *ModerationService) IsSuspended(ctx context.Context, uid string) (bool, error) {
+	user, err := s.users.GetByID(ctx, uid)
+	if err != nil {
+		if repository.IsNotFoundError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("get user: %w", err)
+	}
+	return user.Suspended, nil
+}
+
+// pickAction returns on if flag is set, otherwise off.
+func pickAction(flag bool, on, off string) string {
+	if flag {
+		return on
+	}
+	return off
+}
+
+// flagChange returns the audit diff for a boolean field, or nil if the value
+// did not change.
+func flagChange(field string, before, after bool) map[string]model.AuditChange {
+	return model.AuditDiff(map[string]interface{}{field: before}, map[string]interface{}{field: after})
+}
+
+// visibleGalleryItem loads a gallery item for public interaction (comments,
+// reactions, reports). Hidden items are reported as not found.
+func visibleGalleryItem(ctx context.Context, gallery repository.GalleryRepository, itemID string) (*model.GalleryItem, error) {
+	if itemID == "" {
+		return nil, fmt.Errorf("item ID is required")
+	}
+	item, err := gallery.GetByID(ctx, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("get gallery item: %w", err)
+	}
+	if item.Hidden {
+		return nil, fmt.Errorf("gallery item %s not found", itemID)
+	}
+	return item, nil
+}
@@ -12,19 +12,30 @@ import (
 // Hiero SDK integration (minting, transfers) will be added after thorough
 // study of hiero-go-sdk and Solo docs.
 type NFTService struct {
-	repo repository.NFTRepository
+	repo   repository.NFTRepository
+	audit  repository.AuditLogger
+	events EventPublisher
 }
 
-// NewNFTService creates a new NFTService.
-func NewNFTService(repo repository.NFTRepository) *NFTService {
-	return &NFTService{repo: repo}
+// NewNFTService creates a new NFTService. audit may be nil to disable audit
+// logging; events may be nil to disable webhook events.
+func NewNFTService(repo repository.NFTRepository, audit repository.AuditLogger, events EventPublisher) *NFTService {
+	return &NFTService{repo: repo, audit: audit, events: events}
 }
 
-// ListNFTs returns paginated NFTs for a user.
-func (s *NFTService) ListNFTs(ctx context.Context, uid string, limit int, startAfter string) ([]*model.NFT, error) {
+// ListNFTs returns a page of NFT summaries for a user with the position of
+// the next page and the total number matching opts, limited to fields when
+// it is non-nil.
+func (s *NFTService) ListNFTs(ctx context.Context, uid string, limit int, opts *model.ListOptions, fields model.FieldSet) (*model.Page, error) {
 	if uid == "" {
 		return nil, fmt.Errorf("uid is required")
 	}
+	if opts == nil {
+		opts = &model.ListOptions{}
+	}
+	if err := model.NFTListSchema.Normalize(opts); err != nil {
+		return nil, err
+	}
 
 	if limit <= 0 {
 		limit = DefaultPageSize
@@ -33,7 +44,20 @@ func (s *NFTService) ListNFTs(ctx context.Context, uid string, limit int, startA
 		limit = MaxPageSize
 	}
 
-	return s.repo.List(ctx, uid, limit, startAfter)
+	nfts, next, err := s.repo.List(ctx, uid, limit, opts, model.NFTSummaryFields.Paths(fields))
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]*model.NFTSummary, len(nfts))
+	for i, v := range nfts {
+		summaries[i] = v.Summary()
+	}
+	total, err := s.repo.CountList(ctx, uid, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Page{Items: summaries, HasMore: next != nil, Total: total, Next: next}, nil
 }
 
 // GetNFT retrieves an NFT by ID, enforcing ownership.
@@ -54,6 +78,33 @@ func (s *NFTService) GetNFT(ctx context.Context, requestorUID string, nftID stri
 	return nft, nil
 }
 
+// GetNFTs retrieves the NFTs with the given IDs in one batched read, keyed
+// by ID. NFTs that do not exist or belong to another user are left out, as
+// GetNFT would refuse them.
+func (s *NFTService) GetNFTs(ctx context.Context, requestorUID string, nftIDs []string) (map[string]*model.NFT, error) {
+	nfts, err := s.repo.GetByIDs(ctx, nftIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get NFTs: %w", err)
+	}
+	owned := make(map[string]*model.NFT, len(nfts))
+	for _, nft := range nfts {
+		if nft.UserID == requestorUID {
+			owned[nft.ID] = nft
+		}
+	}
+	return owned, nil
+}
+
+// GetThumbnail decodes an NFT's thumbnail, falling back to its inline
+// image. The same access rule as GetNFT applies.
+func (s *NFTService) GetThumbnail(ctx context.Context, requestorUID string, nftID string) (*Thumbnail, error) {
+	nft, err := s.GetNFT(ctx, requestorUID, nftID)
+	if err != nil {
+		return nil, err
+	}
+	return decodeThumbnail(nft.UpdatedAt, nft.ThumbnailData, nft.ImageData)
+}
+
 // CreateNFT validates and creates a new NFT record in Firestore.
 // Note: This does NOT mint on-chain. Hiero minting will be added later.
 func (s *NFTService) CreateNFT(ctx context.Context, uid string, nft *model.NFT) (string, error) {
@@ -61,13 +112,30 @@ func (s *NFTService) CreateNFT(ctx context.Context, uid string, nft *model.NFT)
 	nft.TokenID = ""
 	nft.SerialNumber = 0
 	nft.TransactionID = ""
+	nft.Hidden = false
 	nft.Sanitize()
 
 	if err := nft.Validate(); err != nil {
 		return "", fmt.Errorf("validation: %w", err)
 	}
 
-	return s.repo.Create(ctx, nft)
+	id, err := s.repo.Create(ctx, nft)
+	if err != nil {
+		return "", err
+	}
+
+	recordAudit(ctx, s.audit, &model.AuditEntry{
+		ActorUID:     uid,
+		Action:       model.AuditActionNFTCreate,
+		ResourceType: model.AuditResourceNFT,
+		ResourceID:   id,
+		Details:      map[string]interface{}{"name": nft.Name},
+	})
+	publishEvent(ctx, s.events, uid, model.WebhookEventNFTCreated, map[string]interface{}{
+		"nftId": id,
+		"name":  nft.Name,
+	})
+	return id, nil
 }
 
 // DeleteNFT verifies ownership and deletes an NFT record.
@@ -26,6 +26,8 @@ type StorageClient interface {
 	GenerateDownloadURL(objectPath string, expiry time.Duration) (string, error)
 	ObjectExists(ctx context.Context, objectPath string) (bool, error)
 	ReadObject(ctx context.Context, objectPath string) (io.ReadCloser, error)
+	ReadObjectFrom(ctx context.Context, objectPath string, offset int64) (io.ReadCloser, error)
+	StatObject(ctx context.Context, objectPath string) (*repository.ObjectAttrs, error)
 	WriteObject(ctx context.Context, objectPath string, data io.Reader, contentType string) error
 	DeleteObject(ctx context.Context, objectPath string) error
 }
@@ -40,19 +42,32 @@ type CreateProjectResult struct {
 type ProjectService struct {
 	repo    repository.ProjectRepository
 	storage StorageClient
+	audit   repository.AuditLogger
+	events  EventPublisher
 }
 
 // NewProjectService creates a new ProjectService.
 // storage may be nil if Storage is not yet configured (existing CRUD still works).
-func NewProjectService(repo repository.ProjectRepository, storage StorageClient) *ProjectService {
-	return &ProjectService{repo: repo, storage: storage}
+// audit may be nil to disable audit logging; events may be nil to disable
+// webhook events.
+func NewProjectService(repo repository.ProjectRepository, storage StorageClient, audit repository.AuditLogger, events EventPublisher) *ProjectService {
+	return &ProjectService{repo: repo, storage: storage, audit: audit, events: events}
 }
 
-// ListProjects returns paginated projects for a user.
-func (s *ProjectService) ListProjects(ctx context.Context, uid string, limit int, startAfter string) ([]*model.Project, error) {
+// ListProjects returns a page of project summaries for a user, sorted and
+// filtered by opts, with the position of the next page and the total number
+// of matching projects. fields is the requested sparse fieldset, or nil for
+// the whole summary.
+func (s *ProjectService) ListProjects(ctx context.Context, uid string, limit int, opts *model.ListOptions, fields model.FieldSet) (*model.Page, error) {
 	if uid == "" {
 		return nil, fmt.Errorf("uid is required")
 	}
+	if opts == nil {
+		opts = &model.ListOptions{}
+	}
+	if err := model.ProjectListSchema.Normalize(opts); err != nil {
+		return nil, err
+	}
 
 	if limit <= 0 {
 		limit = DefaultPageSize
@@ -61,7 +76,22 @@ func (s *ProjectService) ListProjects(ctx context.Context, uid string, limit int
 		limit = MaxPageSize
 	}
 
-	return s.repo.List(ctx, uid, limit, startAfter)
+	// Only the fields the projection needs are read, so inline image data
+	// never leaves Firestore on list requests.
+	projects, next, err := s.repo.List(ctx, uid, limit, opts, model.ProjectSummaryFields.Paths(fields))
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]*model.ProjectSummary, len(projects))
+	for i, v := range projects {
+		summaries[i] = v.Summary()
+	}
+	total, err := s.repo.CountList(ctx, uid, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Page{Items: summaries, HasMore: next != nil, Total: total, Next: next}, nil
 }
 
 // GetProject retrieves a project by ID, enforcing ownership or public visibility.
@@ -75,14 +105,85 @@ func (s *ProjectService) GetProject(ctx context.Context, requestorUID string, pr
 		return nil, fmt.Errorf("get project: %w", err)
 	}
 
-	// Allow access if owner or if project is public
-	if project.UserID != requestorUID && !project.IsPublic {
+	if !canViewProject(requestorUID, project) {
 		return nil, fmt.Errorf("unauthorized: you do not have access to this project")
 	}
 
 	return project, nil
 }
 
+// GetProjects retrieves the projects with the given IDs in one batched
+// read, keyed by ID. Projects that do not exist or that the requestor may
+// not see under GetProject's rule are left out.
+func (s *ProjectService) GetProjects(ctx context.Context, requestorUID string, projectIDs []string) (map[string]*model.Project, error) {
+	projects, err := s.repo.GetByIDs(ctx, projectIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get projects: %w", err)
+	}
+	visible := make(map[string]*model.Project, len(projects))
+	for _, p := range projects {
+		if canViewProject(requestorUID, p) {
+			visible[p.ID] = p
+		}
+	}
+	return visible, nil
+}
+
+// canViewProject allows access to the owner, or to anyone if the project
+// is public.
+func canViewProject(requestorUID string, project *model.Project) bool {
+	return project.UserID == requestorUID || project.IsPublic
+}
+
+// ListVisibleProjects returns a page of ownerUID's projects as the
+// requestor may see them: all of them for the owner, only public ones for
+// anyone else. Arguments are as for ListProjects.
+func (s *ProjectService) ListVisibleProjects(ctx context.Context, requestorUID, ownerUID string, limit int, opts *model.ListOptions, fields model.FieldSet) (*model.Page, error) {
+	if requestorUID != ownerUID {
+		public := true
+		visible := model.ListOptions{}
+		if opts != nil {
+			visible = *opts
+		}
+		visible.IsPublic = &public
+		opts = &visible
+	}
+	return s.ListProjects(ctx, ownerUID, limit, opts, fields)
+}
+
+// Thumbnail is a decoded thumbnail image.
+type Thumbnail struct {
+	ContentType string
+	Data        []byte
+	ModTime     time.Time
+}
+
+// GetThumbnail decodes a project's thumbnail. The same access rule as
+// GetProject applies: owner, or any user if the project is public.
+func (s *ProjectService) GetThumbnail(ctx context.Context, requestorUID string, projectID string) (*Thumbnail, error) {
+	project, err := s.GetProject(ctx, requestorUID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return decodeThumbnail(project.UpdatedAt, project.ThumbnailData)
+}
+
+// decodeThumbnail decodes the first non-empty data URL among candidates, so
+// documents with only a full image can fall back to it.
+func decodeThumbnail(modTime time.Time, candidates ...string) (*Thumbnail, error) {
+	for _, dataURL := range candidates {
+		if dataURL == "" {
+			continue
+		}
+		contentType, data, err := model.DecodeThumbnailData(dataURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid stored thumbnail: %w", err)
+		}
+		return &Thumbnail{ContentType: contentType, Data: data, ModTime: modTime}, nil
+	}
+	return nil, fmt.Errorf("thumbnail not found")
+}
+
 // CreateProject validates, dedup-checks, creates or upserts a Firestore record,
 // and returns a signed upload URL so the client can PUT the PNG blob directly.
 //
@@ -133,6 +234,10 @@ func (s *ProjectService) CreateProject(ctx context.Context, uid string, project
 		if err != nil {
 			return nil, fmt.Errorf("upsert project: %w", err)
 		}
+		publishEvent(ctx, s.events, uid, model.WebhookEventProjectUpdated, map[string]interface{}{
+			"projectId": existing.ID,
+			"title":     existing.Title,
+		})
 		return &CreateProjectResult{
 			ProjectID: existing.ID,
 		}, nil
@@ -144,6 +249,10 @@ func (s *ProjectService) CreateProject(ctx context.Context, uid string, project
 		return nil, fmt.Errorf("create project: %w", err)
 	}
 
+	publishEvent(ctx, s.events, uid, model.WebhookEventProjectCreated, map[string]interface{}{
+		"projectId": id,
+		"title":     project.Title,
+	})
 	return &CreateProjectResult{
 		ProjectID: id,
 	}, nil
@@ -308,9 +417,18 @@ func (s *ProjectService) UploadBlob(ctx context.Context, requestorUID, projectID
 	return nil
 }
 
-// DownloadBlob returns a streaming reader for the project's PNG blob from Storage
-// after verifying ownership. The caller must close the returned ReadCloser.
-func (s *ProjectService) DownloadBlob(ctx context.Context, requestorUID, projectID string) (io.ReadCloser, error) {
+// Blob is a project's PNG blob opened for download. Content reads lazily
+// from Storage and supports seeking, so callers can serve byte ranges.
+type Blob struct {
+	ContentHash string
+	Size        int64
+	ModTime     time.Time
+	Content     io.ReadSeekCloser
+}
+
+// DownloadBlob opens the project's PNG blob in Storage after verifying
+// ownership. The caller must close blob.Content.
+func (s *ProjectService) DownloadBlob(ctx context.Context, requestorUID, projectID string) (*Blob, error) {
 	if projectID == "" {
 		return nil, fmt.Errorf("project ID is required")
 	}
@@ -334,11 +452,75 @@ func (s *ProjectService) DownloadBlob(ctx context.Context, requestorUID, project
 		return nil, fmt.Errorf("build object path: %w", err)
 	}
 
-	reader, err := s.storage.ReadObject(ctx, objectPath)
+	attrs, err := s.storage.StatObject(ctx, objectPath)
 	if err != nil {
 		return nil, fmt.Errorf("read blob: %w", err)
 	}
-	return reader, nil
+	return &Blob{
+		ContentHash: project.ContentHash,
+		Size:        attrs.Size,
+		ModTime:     attrs.Updated,
+		Content:     &objectReader{ctx: ctx, storage: s.storage, path: objectPath, size: attrs.Size},
+	}, nil
+}
+
+// objectReader is an io.ReadSeekCloser over a Storage object. Each read
+// after a seek opens a new download at the current offset, so serving a
+// byte range never fetches the bytes before it.
+type objectReader struct {
+	ctx     context.Context
+	storage StorageClient
+	path    string
+	size    int64
+	off     int64
+	rc      io.ReadCloser
+}
+
+func (o *objectReader) Read(p []byte) (int, error) {
+	if o.off >= o.size {
+		return 0, io.EOF
+	}
+	if o.rc == nil {
+		rc, err := o.storage.ReadObjectFrom(o.ctx, o.path, o.off)
+		if err != nil {
+			return 0, fmt.Errorf("read blob: %w", err)
+		}
+		o.rc = rc
+	}
+	n, err := o.rc.Read(p)
+	o.off += int64(n)
+	return n, err
+}
+
+func (o *objectReader) Seek(offset int64, whence int) (int64, error) {
+	var next int64
+	switch whence {
+	case io.SeekStart:
+		next = offset
+	case io.SeekCurrent:
+		next = o.off + offset
+	case io.SeekEnd:
+		next = o.size + offset
+	default:
+		return 0, fmt.Errorf("seek: invalid whence %d", whence)
+	}
+	if next < 0 {
+		return 0, fmt.Errorf("seek: negative position")
+	}
+	if next != o.off {
+		o.Close()
+		o.off = next
+	}
+	return next, nil
+}
+
+func (o *objectReader) Close() error {
+	if o.rc == nil {
+		return nil
+	}
+	err := o.rc.Close()
+	o.rc = nil
+	return err
 }
 
 // UpdateProject validates ownership and applies a partial update.
@@ -360,7 +542,24 @@ func (s *ProjectService) UpdateProject(ctx context.Context, requestorUID string,
 		return fmt.Errorf("unauthorized: cannot update another user's project")
 	}
 
-	return s.repo.Update(ctx, projectID, update)
+	if err := s.repo.Update(ctx, projectID, update); err != nil {
+		return err
+	}
+
+	if update.IsPublic != nil && *update.IsPublic != project.IsPublic {
+		recordAudit(ctx, s.audit, &model.AuditEntry{
+			ActorUID:     requestorUID,
+			Action:       model.AuditActionProjectVisibility,
+			ResourceType: model.AuditResourceProject,
+			ResourceID:   projectID,
+			Changes:      flagChange("isPublic", project.IsPublic, *update.IsPublic),
+		})
+	}
+	publishEvent(ctx, s.events, requestorUID, model.WebhookEventProjectUpdated, map[string]interface{}{
+		"projectId": projectID,
+		"title":     project.Title,
+	})
+	return nil
 }
 
 // DeleteProject verifies ownership, deletes the Storage blob, and removes
@@ -387,7 +586,22 @@ func (s *ProjectService) DeleteProject(ctx context.Context, requestorUID string,
 		}
 	}
 
-	return s.repo.Delete(ctx, projectID)
+	if err := s.repo.Delete(ctx, projectID); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.audit, &model.AuditEntry{
+		ActorUID:     requestorUID,
+		Action:       model.AuditActionProjectDelete,
+		ResourceType: model.AuditResourceProject,
+		ResourceID:   projectID,
+		Details:      map[string]interface{}{"title": project.Title, "contentHash": project.ContentHash},
+	})
+	publishEvent(ctx, s.events, requestorUID, model.WebhookEventProjectDeleted, map[string]interface{}{
+		"projectId": projectID,
+		"title":     project.Title,
+	})
+	return nil
 }
 
 // CountProjects returns the total project count for a user.
@@ -397,3 +611,20 @@ func (s *ProjectService) CountProjects(ctx context.Context, uid string) (int64,
 	}
 	return s.repo.Count(ctx, uid)
 }
+
+// CountVisibleProjects counts ownerUID's projects that the requestor may
+// see, under the same rule as ListVisibleProjects.
+func (s *ProjectService) CountVisibleProjects(ctx context.Context, requestorUID, ownerUID string) (int64, error) {
+	if requestorUID == ownerUID {
+		return s.CountProjects(ctx, ownerUID)
+	}
+	if ownerUID == "" {
+		return 0, fmt.Errorf("uid is required")
+	}
+	public := true
+	opts := &model.ListOptions{IsPublic: &public}
+	if err := model.ProjectListSchema.Normalize(opts); err != nil {
+		return 0, err
+	}
+	return s.repo.CountList(ctx, ownerUID, opts)
+}
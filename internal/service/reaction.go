@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"github.com/pandasWhoCode/paintbar/internal/repository"
+)
+
+// ReactionService handles emoji reactions on gallery items.
+type ReactionService struct {
+	gallery   repository.GalleryRepository
+	reactions repository.ReactionRepository
+}
+
+// NewReactionService creates a new ReactionService.
+func NewReactionService(gallery repository.GalleryRepository, reactions repository.ReactionRepository) *ReactionService {
+	return &ReactionService{gallery: gallery, reactions: reactions}
+}
+
+// GetReactions returns the aggregate counts for an item and the reactions
+// the requestor has left on it.
+func (s *ReactionService) GetReactions(ctx context.Context, requestorUID, itemID string) (*model.ReactionSummary, error) {
+	item, err := visibleGalleryItem(ctx, s.gallery, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	mine, err := s.reactions.ListByUser(ctx, itemID, requestorUID)
+	if err != nil {
+		return nil, fmt.Errorf("list reactions: %w", err)
+	}
+
+	counts := make(map[string]int64, len(item.ReactionCounts))
+	for k, v := range item.ReactionCounts {
+		if v > 0 {
+			counts[k] = v
+		}
+	}
+
+	return &model.ReactionSummary{Counts: counts, Mine: mine}, nil
+}
+
+// AddReaction records the requestor's reaction on an item. Idempotent.
+func (s *ReactionService) AddReaction(ctx context.Context, requestorUID, itemID, reaction string) error {
+	if err := s.check(ctx, requestorUID, itemID, reaction); err != nil {
+		return err
+	}
+	return s.reactions.Add(ctx, itemID, requestorUID, reaction)
+}
+
+// RemoveReaction removes the requestor's reaction from an item. Idempotent.
+func (s *ReactionService) RemoveReaction(ctx context.Context, requestorUID, itemID, reaction string) error {
+	if err := s.check(ctx, requestorUID, itemID, reaction); err != nil {
+		return err
+	}
+	return s.reactions.Remove(ctx, itemID, requestorUID, reaction)
+}
+
+// check validates the common inputs of reaction mutations.
+func (s *ReactionService) check(ctx context.Context, requestorUID, itemID, reaction string) error {
+	if requestorUID == "" {
+		return fmt.Errorf("uid is required")
+	}
+	if err := model.ValidateReaction(reaction); err != nil {
+		return err
+	}
+	_, err := visibleGalleryItem(ctx, s.gallery, itemID)
+	return err
+}
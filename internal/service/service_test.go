@@ -3,13 +3,23 @@ package service
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/pandasWhoCode/paintbar/internal/jobs"
 	"github.com/pandasWhoCode/paintbar/internal/model"
+	"github.com/pandasWhoCode/paintbar/internal/repository"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -19,7 +29,7 @@ import (
 func TestUserService_GetProfile(t *testing.T) {
 	repo := newMockUserRepo()
 	repo.users["user1"] = &model.User{UID: "user1", Email: "a@b.com", DisplayName: "Alice"}
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, nil)
 
 	user, err := svc.GetProfile(context.Background(), "user1")
 	require.NoError(t, err)
@@ -27,13 +37,13 @@ func TestUserService_GetProfile(t *testing.T) {
 }
 
 func TestUserService_GetProfile_NotFound(t *testing.T) {
-	svc := NewUserService(newMockUserRepo())
+	svc := NewUserService(newMockUserRepo(), nil)
 	_, err := svc.GetProfile(context.Background(), "nonexistent")
 	assert.Error(t, err)
 }
 
 func TestUserService_GetProfile_EmptyUID(t *testing.T) {
-	svc := NewUserService(newMockUserRepo())
+	svc := NewUserService(newMockUserRepo(), nil)
 	_, err := svc.GetProfile(context.Background(), "")
 	assert.ErrorContains(t, err, "uid is required")
 }
@@ -41,7 +51,7 @@ func TestUserService_GetProfile_EmptyUID(t *testing.T) {
 func TestUserService_UpdateProfile(t *testing.T) {
 	repo := newMockUserRepo()
 	repo.users["user1"] = &model.User{UID: "user1", Email: "a@b.com"}
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, nil)
 
 	name := "  Alice  "
 	update := &model.UserUpdate{DisplayName: &name}
@@ -53,7 +63,7 @@ func TestUserService_UpdateProfile(t *testing.T) {
 func TestUserService_UpdateProfile_Unauthorized(t *testing.T) {
 	repo := newMockUserRepo()
 	repo.users["user1"] = &model.User{UID: "user1", Email: "a@b.com"}
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, nil)
 
 	name := "Hacker"
 	update := &model.UserUpdate{DisplayName: &name}
@@ -64,7 +74,7 @@ func TestUserService_UpdateProfile_Unauthorized(t *testing.T) {
 func TestUserService_UpdateProfile_BadURL(t *testing.T) {
 	repo := newMockUserRepo()
 	repo.users["user1"] = &model.User{UID: "user1", Email: "a@b.com"}
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, nil)
 
 	badURL := "ftp://bad.com"
 	update := &model.UserUpdate{Website: &badURL}
@@ -75,7 +85,7 @@ func TestUserService_UpdateProfile_BadURL(t *testing.T) {
 func TestUserService_UpdateProfile_LongDisplayName(t *testing.T) {
 	repo := newMockUserRepo()
 	repo.users["user1"] = &model.User{UID: "user1", Email: "a@b.com"}
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, nil)
 
 	long := strings.Repeat("a", 101)
 	update := &model.UserUpdate{DisplayName: &long}
@@ -86,7 +96,7 @@ func TestUserService_UpdateProfile_LongDisplayName(t *testing.T) {
 func TestUserService_UpdateProfile_NormalizesHandles(t *testing.T) {
 	repo := newMockUserRepo()
 	repo.users["user1"] = &model.User{UID: "user1", Email: "a@b.com"}
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, nil)
 
 	handle := "@alice"
 	update := &model.UserUpdate{TwitterHandle: &handle}
@@ -98,7 +108,7 @@ func TestUserService_UpdateProfile_NormalizesHandles(t *testing.T) {
 func TestUserService_ClaimUsername(t *testing.T) {
 	repo := newMockUserRepo()
 	repo.users["user1"] = &model.User{UID: "user1", Email: "a@b.com", Username: ""}
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, nil)
 
 	err := svc.ClaimUsername(context.Background(), "user1", "cool_user")
 	require.NoError(t, err)
@@ -107,7 +117,7 @@ func TestUserService_ClaimUsername(t *testing.T) {
 func TestUserService_ClaimUsername_InvalidFormat(t *testing.T) {
 	repo := newMockUserRepo()
 	repo.users["user1"] = &model.User{UID: "user1", Email: "a@b.com"}
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, nil)
 
 	err := svc.ClaimUsername(context.Background(), "user1", "AB")
 	assert.ErrorContains(t, err, "username must be")
@@ -116,20 +126,20 @@ func TestUserService_ClaimUsername_InvalidFormat(t *testing.T) {
 func TestUserService_ClaimUsername_AlreadySet(t *testing.T) {
 	repo := newMockUserRepo()
 	repo.users["user1"] = &model.User{UID: "user1", Email: "a@b.com", Username: "existing"}
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, nil)
 
 	err := svc.ClaimUsername(context.Background(), "user1", "new_name")
 	assert.ErrorContains(t, err, "username already set")
 }
 
 func TestUserService_ClaimUsername_EmptyUID(t *testing.T) {
-	svc := NewUserService(newMockUserRepo())
+	svc := NewUserService(newMockUserRepo(), nil)
 	err := svc.ClaimUsername(context.Background(), "", "cool_user")
 	assert.ErrorContains(t, err, "uid is required")
 }
 
 func TestUserService_ClaimUsername_UserNotFound(t *testing.T) {
-	svc := NewUserService(newMockUserRepo())
+	svc := NewUserService(newMockUserRepo(), nil)
 	err := svc.ClaimUsername(context.Background(), "nonexistent", "cool_user")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "get user for username claim")
@@ -138,7 +148,7 @@ func TestUserService_ClaimUsername_UserNotFound(t *testing.T) {
 func TestUserService_UpdateProfile_BadGithubURL(t *testing.T) {
 	repo := newMockUserRepo()
 	repo.users["user1"] = &model.User{UID: "user1", Email: "a@b.com"}
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, nil)
 
 	badURL := "ftp://github.com"
 	update := &model.UserUpdate{GithubURL: &badURL}
@@ -150,7 +160,7 @@ func TestUserService_UpdateProfile_BadGithubURL(t *testing.T) {
 func TestUserService_UpdateProfile_LongBio(t *testing.T) {
 	repo := newMockUserRepo()
 	repo.users["user1"] = &model.User{UID: "user1", Email: "a@b.com"}
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, nil)
 
 	long := strings.Repeat("a", 501)
 	update := &model.UserUpdate{Bio: &long}
@@ -161,7 +171,7 @@ func TestUserService_UpdateProfile_LongBio(t *testing.T) {
 func TestUserService_UpdateProfile_LongLocation(t *testing.T) {
 	repo := newMockUserRepo()
 	repo.users["user1"] = &model.User{UID: "user1", Email: "a@b.com"}
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, nil)
 
 	long := strings.Repeat("a", 101)
 	update := &model.UserUpdate{Location: &long}
@@ -172,7 +182,7 @@ func TestUserService_UpdateProfile_LongLocation(t *testing.T) {
 func TestUserService_UpdateProfile_EmptyWebsite_OK(t *testing.T) {
 	repo := newMockUserRepo()
 	repo.users["user1"] = &model.User{UID: "user1", Email: "a@b.com"}
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, nil)
 
 	empty := ""
 	update := &model.UserUpdate{Website: &empty}
@@ -183,7 +193,7 @@ func TestUserService_UpdateProfile_EmptyWebsite_OK(t *testing.T) {
 func TestUserService_UpdateProfile_EmptyGithubURL_OK(t *testing.T) {
 	repo := newMockUserRepo()
 	repo.users["user1"] = &model.User{UID: "user1", Email: "a@b.com"}
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, nil)
 
 	empty := ""
 	update := &model.UserUpdate{GithubURL: &empty}
@@ -194,7 +204,7 @@ func TestUserService_UpdateProfile_EmptyGithubURL_OK(t *testing.T) {
 func TestUserService_UpdateProfile_AllHandlesNormalized(t *testing.T) {
 	repo := newMockUserRepo()
 	repo.users["user1"] = &model.User{UID: "user1", Email: "a@b.com"}
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, nil)
 
 	bs := "  @alice.bsky  "
 	ig := "  @alice_ig  "
@@ -216,7 +226,7 @@ func TestUserService_ClaimUsername_Taken(t *testing.T) {
 	repo.users["user1"] = &model.User{UID: "user1", Email: "a@b.com"}
 	repo.users["user2"] = &model.User{UID: "user2", Email: "b@b.com"}
 	repo.usernames["cool_user"] = "user2"
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, nil)
 
 	err := svc.ClaimUsername(context.Background(), "user1", "cool_user")
 	assert.ErrorContains(t, err, "already taken")
@@ -226,7 +236,7 @@ func TestUserService_ClaimUsername_Taken(t *testing.T) {
 
 func TestProjectService_CreateAndGet(t *testing.T) {
 	repo := newMockProjectRepo()
-	svc := NewProjectService(repo, nil)
+	svc := NewProjectService(repo, nil, nil, nil)
 
 	project := &model.Project{Title: "My Art", IsPublic: false}
 	result, err := svc.CreateProject(context.Background(), "user1", project)
@@ -241,7 +251,7 @@ func TestProjectService_CreateAndGet(t *testing.T) {
 
 func TestProjectService_GetProject_Unauthorized(t *testing.T) {
 	repo := newMockProjectRepo()
-	svc := NewProjectService(repo, nil)
+	svc := NewProjectService(repo, nil, nil, nil)
 
 	project := &model.Project{Title: "Private Art", IsPublic: false}
 	result, _ := svc.CreateProject(context.Background(), "user1", project)
@@ -252,7 +262,7 @@ func TestProjectService_GetProject_Unauthorized(t *testing.T) {
 
 func TestProjectService_GetProject_PublicAllowed(t *testing.T) {
 	repo := newMockProjectRepo()
-	svc := NewProjectService(repo, nil)
+	svc := NewProjectService(repo, nil, nil, nil)
 
 	project := &model.Project{Title: "Public Art", IsPublic: true}
 	result, _ := svc.CreateProject(context.Background(), "user1", project)
@@ -262,9 +272,54 @@ func TestProjectService_GetProject_PublicAllowed(t *testing.T) {
 	assert.Equal(t, "Public Art", got.Title)
 }
 
+func TestProjectService_GetProjects_AppliesVisibility(t *testing.T) {
+	repo := newMockProjectRepo()
+	svc := NewProjectService(repo, nil, nil, nil)
+	ctx := context.Background()
+
+	private, _ := svc.CreateProject(ctx, "user1", &model.Project{Title: "Private"})
+	public, _ := svc.CreateProject(ctx, "user1", &model.Project{Title: "Public", IsPublic: true})
+	ids := []string{private.ProjectID, public.ProjectID, "missing"}
+
+	got, err := svc.GetProjects(ctx, "user1", ids)
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+
+	got, err = svc.GetProjects(ctx, "other_user", ids)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "Public", got[public.ProjectID].Title)
+}
+
+func TestProjectService_ListVisibleProjects_PublicForOthers(t *testing.T) {
+	repo := newMockProjectRepo()
+	svc := NewProjectService(repo, nil, nil, nil)
+	ctx := context.Background()
+
+	svc.CreateProject(ctx, "user1", &model.Project{Title: "Private"})
+	svc.CreateProject(ctx, "user1", &model.Project{Title: "Public", IsPublic: true})
+
+	page, err := svc.ListVisibleProjects(ctx, "user1", "user1", 10, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), page.Total)
+
+	private := false
+	page, err = svc.ListVisibleProjects(ctx, "other_user", "user1", 10, &model.ListOptions{IsPublic: &private}, nil)
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1, "an isPublic=false filter cannot reveal private projects")
+	assert.Equal(t, "Public", page.Items.([]*model.ProjectSummary)[0].Title)
+
+	count, err := svc.CountVisibleProjects(ctx, "other_user", "user1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	count, err = svc.CountVisibleProjects(ctx, "user1", "user1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
 func TestProjectService_UpdateProject_Unauthorized(t *testing.T) {
 	repo := newMockProjectRepo()
-	svc := NewProjectService(repo, nil)
+	svc := NewProjectService(repo, nil, nil, nil)
 
 	project := &model.Project{Title: "Art"}
 	result, _ := svc.CreateProject(context.Background(), "user1", project)
@@ -276,7 +331,7 @@ func TestProjectService_UpdateProject_Unauthorized(t *testing.T) {
 
 func TestProjectService_DeleteProject_Unauthorized(t *testing.T) {
 	repo := newMockProjectRepo()
-	svc := NewProjectService(repo, nil)
+	svc := NewProjectService(repo, nil, nil, nil)
 
 	project := &model.Project{Title: "Art"}
 	result, _ := svc.CreateProject(context.Background(), "user1", project)
@@ -287,7 +342,7 @@ func TestProjectService_DeleteProject_Unauthorized(t *testing.T) {
 
 func TestProjectService_DeleteProject_Success(t *testing.T) {
 	repo := newMockProjectRepo()
-	svc := NewProjectService(repo, nil)
+	svc := NewProjectService(repo, nil, nil, nil)
 
 	project := &model.Project{Title: "Art"}
 	result, _ := svc.CreateProject(context.Background(), "user1", project)
@@ -301,30 +356,105 @@ func TestProjectService_DeleteProject_Success(t *testing.T) {
 
 func TestProjectService_ListProjects(t *testing.T) {
 	repo := newMockProjectRepo()
-	svc := NewProjectService(repo, nil)
+	svc := NewProjectService(repo, nil, nil, nil)
 
 	for i := 0; i < 3; i++ {
 		svc.CreateProject(context.Background(), "user1", &model.Project{Title: fmt.Sprintf("Art %d", i)})
 	}
 	svc.CreateProject(context.Background(), "user2", &model.Project{Title: "Other"})
 
-	projects, err := svc.ListProjects(context.Background(), "user1", 10, "")
+	page, err := svc.ListProjects(context.Background(), "user1", 10, nil, nil)
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 3)
+	assert.Equal(t, int64(3), page.Total)
+	assert.False(t, page.HasMore)
+	assert.Nil(t, page.Next)
+}
+
+func TestProjectService_ListProjects_Summaries(t *testing.T) {
+	repo := newMockProjectRepo()
+	svc := NewProjectService(repo, nil, nil, nil)
+	ctx := context.Background()
+
+	thumb := "data:image/png;base64," + base64.StdEncoding.EncodeToString(validPNG())
+	id, _ := repo.Create(ctx, &model.Project{UserID: "user1", Title: "Art", ThumbnailData: thumb})
+
+	page, err := svc.ListProjects(ctx, "user1", 10, nil, nil)
+	require.NoError(t, err)
+	projects := page.Items.([]*model.ProjectSummary)
+	require.Len(t, projects, 1)
+	assert.Equal(t, "Art", projects[0].Title)
+	assert.Equal(t, "/api/v1/projects/"+id+"/thumbnail", projects[0].ThumbnailURL)
+	assert.NotContains(t, repo.listFields, "thumbnailData")
+	assert.Contains(t, repo.listFields, "title")
+
+	// A sparse fieldset narrows the Firestore read to what it needs
+	_, err = svc.ListProjects(ctx, "user1", 10, nil, model.FieldSet{"id", "title", "thumbnailUrl"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"title"}, repo.listFields)
+}
+
+func TestProjectService_ListProjects_SortFilterAndCursor(t *testing.T) {
+	repo := newMockProjectRepo()
+	svc := NewProjectService(repo, nil, nil, nil)
+	ctx := context.Background()
+
+	for _, title := range []string{"delta", "alpha", "charlie", "bravo"} {
+		repo.Create(ctx, &model.Project{UserID: "user1", Title: title, Tags: []string{"pixel"}, IsPublic: title != "bravo"})
+	}
+	repo.Create(ctx, &model.Project{UserID: "user1", Title: "echo"})
+
+	titles := func(p *model.Page) []string {
+		var out []string
+		for _, s := range p.Items.([]*model.ProjectSummary) {
+			out = append(out, s.Title)
+		}
+		return out
+	}
+
+	opts := &model.ListOptions{Sort: "title", Order: model.SortAsc, Tag: "pixel"}
+	page, err := svc.ListProjects(ctx, "user1", 2, opts, nil)
+	require.NoError(t, err)
+	require.NotNil(t, page.Next)
+	assert.True(t, page.HasMore)
+	assert.Equal(t, int64(4), page.Total, "total counts every filtered item, not just the page")
+	assert.Equal(t, []string{"alpha", "bravo"}, titles(page))
+
+	page, err = svc.ListProjects(ctx, "user1", 2, &model.ListOptions{Sort: "title", Order: model.SortAsc, Tag: "pixel", Cursor: page.Next}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, page.Next, "last page has no next cursor")
+	assert.False(t, page.HasMore)
+	assert.Equal(t, []string{"charlie", "delta"}, titles(page))
+
+	public := true
+	page, err = svc.ListProjects(ctx, "user1", 10, &model.ListOptions{Tag: "pixel", IsPublic: &public}, nil)
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 3)
+	assert.Equal(t, int64(3), page.Total)
+
+	// A cursor cannot be replayed under different filters
+	first, err := svc.ListProjects(ctx, "user1", 2, &model.ListOptions{Sort: "title", Order: model.SortAsc, Tag: "pixel"}, nil)
 	require.NoError(t, err)
-	assert.Len(t, projects, 3)
+	_, err = svc.ListProjects(ctx, "user1", 2, &model.ListOptions{Sort: "title", Order: model.SortAsc, Cursor: first.Next}, nil)
+	assert.ErrorContains(t, err, "different filters")
+
+	// The sort field comes from the request, not the cursor
+	_, err = svc.ListProjects(ctx, "user1", 2, &model.ListOptions{Sort: "createdAt", Cursor: &model.Cursor{Sort: "title", Order: "asc", ID: "x"}}, nil)
+	assert.ErrorContains(t, err, "invalid cursor")
 }
 
 func TestProjectService_ListProjects_CapsPageSize(t *testing.T) {
 	repo := newMockProjectRepo()
-	svc := NewProjectService(repo, nil)
+	svc := NewProjectService(repo, nil, nil, nil)
 
 	// Request 100 but max is 50 — service should cap it without error
-	_, err := svc.ListProjects(context.Background(), "user1", 100, "")
+	_, err := svc.ListProjects(context.Background(), "user1", 100, nil, nil)
 	require.NoError(t, err)
 }
 
 func TestProjectService_CountProjects(t *testing.T) {
 	repo := newMockProjectRepo()
-	svc := NewProjectService(repo, nil)
+	svc := NewProjectService(repo, nil, nil, nil)
 
 	svc.CreateProject(context.Background(), "user1", &model.Project{Title: "A"})
 	svc.CreateProject(context.Background(), "user1", &model.Project{Title: "B"})
@@ -335,52 +465,52 @@ func TestProjectService_CountProjects(t *testing.T) {
 }
 
 func TestProjectService_CreateProject_ValidationFails(t *testing.T) {
-	svc := NewProjectService(newMockProjectRepo(), nil)
+	svc := NewProjectService(newMockProjectRepo(), nil, nil, nil)
 	_, err := svc.CreateProject(context.Background(), "user1", &model.Project{Title: ""})
 	assert.ErrorContains(t, err, "title is required")
 }
 
 func TestProjectService_ListProjects_EmptyUID(t *testing.T) {
-	svc := NewProjectService(newMockProjectRepo(), nil)
-	_, err := svc.ListProjects(context.Background(), "", 10, "")
+	svc := NewProjectService(newMockProjectRepo(), nil, nil, nil)
+	_, err := svc.ListProjects(context.Background(), "", 10, nil, nil)
 	assert.ErrorContains(t, err, "uid is required")
 }
 
 func TestProjectService_ListProjects_DefaultPageSize(t *testing.T) {
 	repo := newMockProjectRepo()
-	svc := NewProjectService(repo, nil)
+	svc := NewProjectService(repo, nil, nil, nil)
 	// limit 0 should default to DefaultPageSize
-	_, err := svc.ListProjects(context.Background(), "user1", 0, "")
+	_, err := svc.ListProjects(context.Background(), "user1", 0, nil, nil)
 	require.NoError(t, err)
 }
 
 func TestProjectService_ListProjects_NegativePageSize(t *testing.T) {
-	svc := NewProjectService(newMockProjectRepo(), nil)
-	_, err := svc.ListProjects(context.Background(), "user1", -5, "")
+	svc := NewProjectService(newMockProjectRepo(), nil, nil, nil)
+	_, err := svc.ListProjects(context.Background(), "user1", -5, nil, nil)
 	require.NoError(t, err)
 }
 
 func TestProjectService_GetProject_EmptyID(t *testing.T) {
-	svc := NewProjectService(newMockProjectRepo(), nil)
+	svc := NewProjectService(newMockProjectRepo(), nil, nil, nil)
 	_, err := svc.GetProject(context.Background(), "user1", "")
 	assert.ErrorContains(t, err, "project ID is required")
 }
 
 func TestProjectService_GetProject_NotFound(t *testing.T) {
-	svc := NewProjectService(newMockProjectRepo(), nil)
+	svc := NewProjectService(newMockProjectRepo(), nil, nil, nil)
 	_, err := svc.GetProject(context.Background(), "user1", "nonexistent")
 	assert.Error(t, err)
 }
 
 func TestProjectService_UpdateProject_EmptyID(t *testing.T) {
-	svc := NewProjectService(newMockProjectRepo(), nil)
+	svc := NewProjectService(newMockProjectRepo(), nil, nil, nil)
 	title := "test"
 	err := svc.UpdateProject(context.Background(), "user1", "", &model.ProjectUpdate{Title: &title})
 	assert.ErrorContains(t, err, "project ID is required")
 }
 
 func TestProjectService_UpdateProject_NotFound(t *testing.T) {
-	svc := NewProjectService(newMockProjectRepo(), nil)
+	svc := NewProjectService(newMockProjectRepo(), nil, nil, nil)
 	title := "test"
 	err := svc.UpdateProject(context.Background(), "user1", "nonexistent", &model.ProjectUpdate{Title: &title})
 	assert.Error(t, err)
@@ -388,7 +518,7 @@ func TestProjectService_UpdateProject_NotFound(t *testing.T) {
 
 func TestProjectService_UpdateProject_Success(t *testing.T) {
 	repo := newMockProjectRepo()
-	svc := NewProjectService(repo, nil)
+	svc := NewProjectService(repo, nil, nil, nil)
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art"})
 	title := "Updated"
 	err := svc.UpdateProject(context.Background(), "user1", result.ProjectID, &model.ProjectUpdate{Title: &title})
@@ -396,25 +526,28 @@ func TestProjectService_UpdateProject_Success(t *testing.T) {
 }
 
 func TestProjectService_DeleteProject_EmptyID(t *testing.T) {
-	svc := NewProjectService(newMockProjectRepo(), nil)
+	svc := NewProjectService(newMockProjectRepo(), nil, nil, nil)
 	err := svc.DeleteProject(context.Background(), "user1", "")
 	assert.ErrorContains(t, err, "project ID is required")
 }
 
 func TestProjectService_DeleteProject_NotFound(t *testing.T) {
-	svc := NewProjectService(newMockProjectRepo(), nil)
+	svc := NewProjectService(newMockProjectRepo(), nil, nil, nil)
 	err := svc.DeleteProject(context.Background(), "user1", "nonexistent")
 	assert.Error(t, err)
 }
 
 func TestProjectService_CountProjects_EmptyUID(t *testing.T) {
-	svc := NewProjectService(newMockProjectRepo(), nil)
+	svc := NewProjectService(newMockProjectRepo(), nil, nil, nil)
 	_, err := svc.CountProjects(context.Background(), "")
 	assert.ErrorContains(t, err, "uid is required")
 }
 
 // --- Mock StorageClient for testing ---
 
+// mockObjectUpdated is the last-modified time mock storage reports.
+var mockObjectUpdated = time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+
 type mockStorageClient struct {
 	objects map[string]bool // tracks which object paths "exist"
 }
@@ -442,6 +575,20 @@ func (m *mockStorageClient) ReadObject(_ context.Context, objectPath string) (io
 	return nil, fmt.Errorf("object not found: %s", objectPath)
 }
 
+func (m *mockStorageClient) ReadObjectFrom(_ context.Context, objectPath string, offset int64) (io.ReadCloser, error) {
+	if m.objects[objectPath] {
+		return io.NopCloser(bytes.NewReader([]byte("fake-png-data")[offset:])), nil
+	}
+	return nil, fmt.Errorf("object not found: %s", objectPath)
+}
+
+func (m *mockStorageClient) StatObject(_ context.Context, objectPath string) (*repository.ObjectAttrs, error) {
+	if m.objects[objectPath] {
+		return &repository.ObjectAttrs{Size: int64(len("fake-png-data")), Updated: mockObjectUpdated}, nil
+	}
+	return nil, fmt.Errorf("object not found: %s", objectPath)
+}
+
 func (m *mockStorageClient) WriteObject(_ context.Context, objectPath string, _ io.Reader, _ string) error {
 	m.objects[objectPath] = true
 	return nil
@@ -457,7 +604,7 @@ func (m *mockStorageClient) DeleteObject(_ context.Context, objectPath string) e
 func TestProjectService_CreateProject_WithStorage(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := newMockStorageClient()
-	svc := NewProjectService(repo, storage)
+	svc := NewProjectService(repo, storage, nil, nil)
 
 	project := &model.Project{
 		Title:       "Art",
@@ -472,7 +619,7 @@ func TestProjectService_CreateProject_WithStorage(t *testing.T) {
 func TestProjectService_CreateProject_Dedup(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := newMockStorageClient()
-	svc := NewProjectService(repo, storage)
+	svc := NewProjectService(repo, storage, nil, nil)
 
 	hash := "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
 	project := &model.Project{Title: "Art", ContentHash: hash}
@@ -490,7 +637,7 @@ func TestProjectService_CreateProject_Dedup(t *testing.T) {
 func TestProjectService_ConfirmUpload_Success(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := newMockStorageClient()
-	svc := NewProjectService(repo, storage)
+	svc := NewProjectService(repo, storage, nil, nil)
 
 	hash := "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
 	project := &model.Project{Title: "Art", ContentHash: hash}
@@ -510,7 +657,7 @@ func TestProjectService_ConfirmUpload_Success(t *testing.T) {
 func TestProjectService_ConfirmUpload_NotUploaded(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := newMockStorageClient()
-	svc := NewProjectService(repo, storage)
+	svc := NewProjectService(repo, storage, nil, nil)
 
 	hash := "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
 	project := &model.Project{Title: "Art", ContentHash: hash}
@@ -524,7 +671,7 @@ func TestProjectService_ConfirmUpload_NotUploaded(t *testing.T) {
 func TestProjectService_ConfirmUpload_Unauthorized(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := newMockStorageClient()
-	svc := NewProjectService(repo, storage)
+	svc := NewProjectService(repo, storage, nil, nil)
 
 	hash := "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
 	project := &model.Project{Title: "Art", ContentHash: hash}
@@ -535,13 +682,13 @@ func TestProjectService_ConfirmUpload_Unauthorized(t *testing.T) {
 }
 
 func TestProjectService_ConfirmUpload_EmptyID(t *testing.T) {
-	svc := NewProjectService(newMockProjectRepo(), newMockStorageClient())
+	svc := NewProjectService(newMockProjectRepo(), newMockStorageClient(), nil, nil)
 	err := svc.ConfirmUpload(context.Background(), "user1", "")
 	assert.ErrorContains(t, err, "project ID is required")
 }
 
 func TestProjectService_ConfirmUpload_NoStorage(t *testing.T) {
-	svc := NewProjectService(newMockProjectRepo(), nil)
+	svc := NewProjectService(newMockProjectRepo(), nil, nil, nil)
 	err := svc.ConfirmUpload(context.Background(), "user1", "proj_1")
 	assert.ErrorContains(t, err, "storage is not configured")
 }
@@ -549,7 +696,7 @@ func TestProjectService_ConfirmUpload_NoStorage(t *testing.T) {
 func TestProjectService_ConfirmUpload_NoContentHash(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := newMockStorageClient()
-	svc := NewProjectService(repo, storage)
+	svc := NewProjectService(repo, storage, nil, nil)
 
 	// Create project without content hash
 	project := &model.Project{Title: "Art"}
@@ -562,7 +709,7 @@ func TestProjectService_ConfirmUpload_NoContentHash(t *testing.T) {
 func TestProjectService_DeleteProject_WithStorage(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := newMockStorageClient()
-	svc := NewProjectService(repo, storage)
+	svc := NewProjectService(repo, storage, nil, nil)
 
 	hash := "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
 	project := &model.Project{Title: "Art", ContentHash: hash}
@@ -583,7 +730,7 @@ func TestProjectService_DeleteProject_WithStorage(t *testing.T) {
 
 func TestProjectService_GetProjectByTitle_Success(t *testing.T) {
 	repo := newMockProjectRepo()
-	svc := NewProjectService(repo, nil)
+	svc := NewProjectService(repo, nil, nil, nil)
 
 	svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Sunset"})
 
@@ -593,14 +740,14 @@ func TestProjectService_GetProjectByTitle_Success(t *testing.T) {
 }
 
 func TestProjectService_GetProjectByTitle_NotFound(t *testing.T) {
-	svc := NewProjectService(newMockProjectRepo(), nil)
+	svc := NewProjectService(newMockProjectRepo(), nil, nil, nil)
 
 	_, err := svc.GetProjectByTitle(context.Background(), "user1", "Nonexistent")
 	assert.ErrorContains(t, err, "project not found")
 }
 
 func TestProjectService_GetProjectByTitle_EmptyTitle(t *testing.T) {
-	svc := NewProjectService(newMockProjectRepo(), nil)
+	svc := NewProjectService(newMockProjectRepo(), nil, nil, nil)
 
 	_, err := svc.GetProjectByTitle(context.Background(), "user1", "")
 	assert.ErrorContains(t, err, "title is required")
@@ -611,7 +758,7 @@ func TestProjectService_GetProjectByTitle_EmptyTitle(t *testing.T) {
 func TestProjectService_DownloadBlob_Success(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := newMockStorageClient()
-	svc := NewProjectService(repo, storage)
+	svc := NewProjectService(repo, storage, nil, nil)
 
 	hash := "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art", ContentHash: hash})
@@ -619,22 +766,59 @@ func TestProjectService_DownloadBlob_Success(t *testing.T) {
 	objPath := "projects/user1/" + hash + ".png"
 	storage.objects[objPath] = true
 
-	reader, err := svc.DownloadBlob(context.Background(), "user1", result.ProjectID)
+	blob, err := svc.DownloadBlob(context.Background(), "user1", result.ProjectID)
 	require.NoError(t, err)
-	defer reader.Close()
-	data, _ := io.ReadAll(reader)
+	defer blob.Content.Close()
+	assert.Equal(t, hash, blob.ContentHash)
+	assert.Equal(t, int64(len("fake-png-data")), blob.Size)
+	assert.Equal(t, mockObjectUpdated, blob.ModTime)
+	data, _ := io.ReadAll(blob.Content)
 	assert.Equal(t, []byte("fake-png-data"), data)
 }
 
+func TestProjectService_DownloadBlob_Seek(t *testing.T) {
+	repo := newMockProjectRepo()
+	storage := newMockStorageClient()
+	svc := NewProjectService(repo, storage, nil, nil)
+
+	hash := "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
+	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art", ContentHash: hash})
+	storage.objects["projects/user1/"+hash+".png"] = true
+
+	blob, err := svc.DownloadBlob(context.Background(), "user1", result.ProjectID)
+	require.NoError(t, err)
+	defer blob.Content.Close()
+
+	size, err := blob.Content.Seek(0, io.SeekEnd)
+	require.NoError(t, err)
+	assert.Equal(t, blob.Size, size)
+
+	// Read part of the object, then jump elsewhere
+	_, err = blob.Content.Seek(5, io.SeekStart)
+	require.NoError(t, err)
+	buf := make([]byte, 3)
+	_, err = io.ReadFull(blob.Content, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "png", string(buf))
+
+	_, err = blob.Content.Seek(-4, io.SeekEnd)
+	require.NoError(t, err)
+	rest, _ := io.ReadAll(blob.Content)
+	assert.Equal(t, "data", string(rest))
+
+	_, err = blob.Content.Seek(-1, io.SeekStart)
+	assert.Error(t, err)
+}
+
 func TestProjectService_DownloadBlob_EmptyID(t *testing.T) {
-	svc := NewProjectService(newMockProjectRepo(), newMockStorageClient())
+	svc := NewProjectService(newMockProjectRepo(), newMockStorageClient(), nil, nil)
 	_, err := svc.DownloadBlob(context.Background(), "user1", "")
 	assert.ErrorContains(t, err, "project ID is required")
 }
 
 func TestProjectService_DownloadBlob_NoStorage(t *testing.T) {
 	repo := newMockProjectRepo()
-	svc := NewProjectService(repo, nil)
+	svc := NewProjectService(repo, nil, nil, nil)
 
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art"})
 
@@ -645,7 +829,7 @@ func TestProjectService_DownloadBlob_NoStorage(t *testing.T) {
 func TestProjectService_DownloadBlob_Unauthorized(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := newMockStorageClient()
-	svc := NewProjectService(repo, storage)
+	svc := NewProjectService(repo, storage, nil, nil)
 
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art"})
 
@@ -656,7 +840,7 @@ func TestProjectService_DownloadBlob_Unauthorized(t *testing.T) {
 func TestProjectService_DownloadBlob_NoContentHash(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := newMockStorageClient()
-	svc := NewProjectService(repo, storage)
+	svc := NewProjectService(repo, storage, nil, nil)
 
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art"})
 
@@ -669,7 +853,7 @@ func TestProjectService_DownloadBlob_NoContentHash(t *testing.T) {
 func TestProjectService_CreateProject_UpsertByTitle(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := newMockStorageClient()
-	svc := NewProjectService(repo, storage)
+	svc := NewProjectService(repo, storage, nil, nil)
 
 	hash1 := "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
 	hash2 := "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
@@ -700,7 +884,7 @@ func TestProjectService_CreateProject_UpsertByTitle(t *testing.T) {
 func TestProjectService_CreateProject_UpsertClearsStorageURL(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := newMockStorageClient()
-	svc := NewProjectService(repo, storage)
+	svc := NewProjectService(repo, storage, nil, nil)
 
 	hash1 := "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
 	hash2 := "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
@@ -732,7 +916,7 @@ func TestProjectService_CreateProject_UpsertClearsStorageURL(t *testing.T) {
 
 func TestProjectService_CreateProject_DedupCheckFails(t *testing.T) {
 	repo := &failingFindByContentHashRepo{mockProjectRepo: *newMockProjectRepo()}
-	svc := NewProjectService(repo, nil)
+	svc := NewProjectService(repo, nil, nil, nil)
 
 	hash := "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
 	_, err := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art", ContentHash: hash})
@@ -741,7 +925,7 @@ func TestProjectService_CreateProject_DedupCheckFails(t *testing.T) {
 
 func TestProjectService_CreateProject_TitleLookupFails(t *testing.T) {
 	repo := &failingFindByTitleRepo{mockProjectRepo: *newMockProjectRepo()}
-	svc := NewProjectService(repo, nil)
+	svc := NewProjectService(repo, nil, nil, nil)
 
 	_, err := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art"})
 	assert.ErrorContains(t, err, "title lookup")
@@ -749,7 +933,7 @@ func TestProjectService_CreateProject_TitleLookupFails(t *testing.T) {
 
 func TestProjectService_GetProjectByTitle_RepoError(t *testing.T) {
 	repo := &failingFindByTitleRepo{mockProjectRepo: *newMockProjectRepo()}
-	svc := NewProjectService(repo, nil)
+	svc := NewProjectService(repo, nil, nil, nil)
 
 	_, err := svc.GetProjectByTitle(context.Background(), "user1", "Art")
 	assert.ErrorContains(t, err, "find project by title")
@@ -758,7 +942,7 @@ func TestProjectService_GetProjectByTitle_RepoError(t *testing.T) {
 func TestProjectService_ConfirmUpload_ObjectExistsFails(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := &failingObjectExistsStorageClient{mockStorageClient: *newMockStorageClient()}
-	svc := NewProjectService(repo, storage)
+	svc := NewProjectService(repo, storage, nil, nil)
 
 	hash := "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art", ContentHash: hash})
@@ -770,7 +954,7 @@ func TestProjectService_ConfirmUpload_ObjectExistsFails(t *testing.T) {
 func TestProjectService_ConfirmUpload_DownloadURLFails(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := &failingDownloadURLStorageClient{mockStorageClient: *newMockStorageClient()}
-	svc := NewProjectService(repo, storage)
+	svc := NewProjectService(repo, storage, nil, nil)
 
 	hash := "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art", ContentHash: hash})
@@ -786,19 +970,75 @@ func TestProjectService_ConfirmUpload_DownloadURLFails(t *testing.T) {
 func TestProjectService_DownloadBlob_ReadObjectFails(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := &failingReadObjectStorageClient{mockStorageClient: *newMockStorageClient()}
-	svc := NewProjectService(repo, storage)
+	svc := NewProjectService(repo, storage, nil, nil)
 
 	hash := "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art", ContentHash: hash})
 
+	// Missing object fails at open
 	_, err := svc.DownloadBlob(context.Background(), "user1", result.ProjectID)
 	assert.ErrorContains(t, err, "read blob")
+
+	// Read errors surface from the content reader
+	storage.objects["projects/user1/"+hash+".png"] = true
+	blob, err := svc.DownloadBlob(context.Background(), "user1", result.ProjectID)
+	require.NoError(t, err)
+	_, err = io.ReadAll(blob.Content)
+	assert.ErrorContains(t, err, "storage read failed")
+}
+
+func TestProjectService_GetThumbnail(t *testing.T) {
+	repo := newMockProjectRepo()
+	svc := NewProjectService(repo, nil, nil, nil)
+	ctx := context.Background()
+
+	thumb := "data:image/png;base64," + base64.StdEncoding.EncodeToString(validPNG())
+	updated := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	private, _ := repo.Create(ctx, &model.Project{UserID: "user1", Title: "A", ThumbnailData: thumb, UpdatedAt: updated})
+	public, _ := repo.Create(ctx, &model.Project{UserID: "user1", Title: "B", ThumbnailData: thumb, IsPublic: true})
+	empty, _ := repo.Create(ctx, &model.Project{UserID: "user1", Title: "C"})
+
+	got, err := svc.GetThumbnail(ctx, "user1", private)
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", got.ContentType)
+	assert.Equal(t, validPNG(), got.Data)
+	assert.Equal(t, updated, got.ModTime)
+
+	_, err = svc.GetThumbnail(ctx, "user2", private)
+	assert.ErrorContains(t, err, "unauthorized")
+
+	_, err = svc.GetThumbnail(ctx, "user2", public)
+	assert.NoError(t, err)
+
+	_, err = svc.GetThumbnail(ctx, "user1", empty)
+	assert.ErrorContains(t, err, "not found")
 }
 
 // --- GalleryService tests ---
 
+func TestGalleryService_GetThumbnail_FallsBackToImage(t *testing.T) {
+	repo := newMockGalleryRepo()
+	svc := NewGalleryService(repo, nil, nil)
+	ctx := context.Background()
+
+	image := "data:image/png;base64," + base64.StdEncoding.EncodeToString(validPNG())
+	withImage, _ := repo.Create(ctx, &model.GalleryItem{UserID: "user1", Name: "A", ImageData: image})
+	bare, _ := repo.Create(ctx, &model.GalleryItem{UserID: "user1", Name: "B"})
+
+	got, err := svc.GetThumbnail(ctx, "user1", withImage)
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", got.ContentType)
+	assert.Equal(t, validPNG(), got.Data)
+
+	_, err = svc.GetThumbnail(ctx, "user2", withImage)
+	assert.ErrorContains(t, err, "unauthorized")
+
+	_, err = svc.GetThumbnail(ctx, "user1", bare)
+	assert.ErrorContains(t, err, "thumbnail not found")
+}
+
 func TestGalleryService_ShareAndGet(t *testing.T) {
-	svc := NewGalleryService(newMockGalleryRepo())
+	svc := NewGalleryService(newMockGalleryRepo(), nil, nil)
 
 	item := &model.GalleryItem{Name: "Sunset", CreatedAt: time.Now()}
 	id, err := svc.ShareToGallery(context.Background(), "user1", item)
@@ -810,7 +1050,7 @@ func TestGalleryService_ShareAndGet(t *testing.T) {
 }
 
 func TestGalleryService_GetItem_Unauthorized(t *testing.T) {
-	svc := NewGalleryService(newMockGalleryRepo())
+	svc := NewGalleryService(newMockGalleryRepo(), nil, nil)
 
 	item := &model.GalleryItem{Name: "Art"}
 	id, _ := svc.ShareToGallery(context.Background(), "user1", item)
@@ -819,8 +1059,20 @@ func TestGalleryService_GetItem_Unauthorized(t *testing.T) {
 	assert.ErrorContains(t, err, "unauthorized")
 }
 
+func TestGalleryService_GetItems_OwnerOnly(t *testing.T) {
+	svc := NewGalleryService(newMockGalleryRepo(), nil, nil)
+	ctx := context.Background()
+	mine, _ := svc.ShareToGallery(ctx, "user1", &model.GalleryItem{Name: "Mine"})
+	theirs, _ := svc.ShareToGallery(ctx, "user2", &model.GalleryItem{Name: "Theirs"})
+
+	got, err := svc.GetItems(ctx, "user1", []string{mine, theirs, "missing"})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "Mine", got[mine].Name)
+}
+
 func TestGalleryService_DeleteItem_Unauthorized(t *testing.T) {
-	svc := NewGalleryService(newMockGalleryRepo())
+	svc := NewGalleryService(newMockGalleryRepo(), nil, nil)
 
 	item := &model.GalleryItem{Name: "Art"}
 	id, _ := svc.ShareToGallery(context.Background(), "user1", item)
@@ -830,31 +1082,31 @@ func TestGalleryService_DeleteItem_Unauthorized(t *testing.T) {
 }
 
 func TestGalleryService_GetItem_EmptyID(t *testing.T) {
-	svc := NewGalleryService(newMockGalleryRepo())
+	svc := NewGalleryService(newMockGalleryRepo(), nil, nil)
 	_, err := svc.GetItem(context.Background(), "user1", "")
 	assert.ErrorContains(t, err, "item ID is required")
 }
 
 func TestGalleryService_GetItem_NotFound(t *testing.T) {
-	svc := NewGalleryService(newMockGalleryRepo())
+	svc := NewGalleryService(newMockGalleryRepo(), nil, nil)
 	_, err := svc.GetItem(context.Background(), "user1", "nonexistent")
 	assert.Error(t, err)
 }
 
 func TestGalleryService_DeleteItem_EmptyID(t *testing.T) {
-	svc := NewGalleryService(newMockGalleryRepo())
+	svc := NewGalleryService(newMockGalleryRepo(), nil, nil)
 	err := svc.DeleteItem(context.Background(), "user1", "")
 	assert.ErrorContains(t, err, "item ID is required")
 }
 
 func TestGalleryService_DeleteItem_NotFound(t *testing.T) {
-	svc := NewGalleryService(newMockGalleryRepo())
+	svc := NewGalleryService(newMockGalleryRepo(), nil, nil)
 	err := svc.DeleteItem(context.Background(), "user1", "nonexistent")
 	assert.Error(t, err)
 }
 
 func TestGalleryService_DeleteItem_Success(t *testing.T) {
-	svc := NewGalleryService(newMockGalleryRepo())
+	svc := NewGalleryService(newMockGalleryRepo(), nil, nil)
 	id, _ := svc.ShareToGallery(context.Background(), "user1", &model.GalleryItem{Name: "Art"})
 	err := svc.DeleteItem(context.Background(), "user1", id)
 	require.NoError(t, err)
@@ -862,38 +1114,55 @@ func TestGalleryService_DeleteItem_Success(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestGalleryService_DeleteItem_RemovesCommentsAndReactions(t *testing.T) {
+	repos := newMockRepos("owner", "fan")
+	reactions := newMockReactionRepo(repos.gallery)
+	svc := NewGalleryService(repos.gallery, nil, nil)
+	ctx := context.Background()
+	id, err := svc.ShareToGallery(ctx, "owner", &model.GalleryItem{Name: "Art"})
+	require.NoError(t, err)
+	_, err = NewCommentService(repos.gallery, repos.comments, repos.users).
+		CreateComment(ctx, "fan", id, &model.Comment{Body: "nice"})
+	require.NoError(t, err)
+	require.NoError(t, NewReactionService(repos.gallery, reactions).AddReaction(ctx, "fan", id, "heart"))
+
+	require.NoError(t, svc.DeleteItem(ctx, "owner", id))
+	assert.Empty(t, repos.comments.comments)
+	assert.Empty(t, reactions.reactions)
+}
+
 func TestGalleryService_ShareToGallery_ValidationFails(t *testing.T) {
-	svc := NewGalleryService(newMockGalleryRepo())
+	svc := NewGalleryService(newMockGalleryRepo(), nil, nil)
 	_, err := svc.ShareToGallery(context.Background(), "user1", &model.GalleryItem{Name: ""})
 	assert.Error(t, err)
 }
 
 func TestGalleryService_ListItems_EmptyUID(t *testing.T) {
-	svc := NewGalleryService(newMockGalleryRepo())
-	_, err := svc.ListItems(context.Background(), "", 10, "")
+	svc := NewGalleryService(newMockGalleryRepo(), nil, nil)
+	_, err := svc.ListItems(context.Background(), "", 10, nil, nil)
 	assert.ErrorContains(t, err, "uid is required")
 }
 
 func TestGalleryService_ListItems_DefaultPageSize(t *testing.T) {
-	svc := NewGalleryService(newMockGalleryRepo())
-	_, err := svc.ListItems(context.Background(), "user1", 0, "")
+	svc := NewGalleryService(newMockGalleryRepo(), nil, nil)
+	_, err := svc.ListItems(context.Background(), "user1", 0, nil, nil)
 	require.NoError(t, err)
 }
 
 func TestGalleryService_ListItems_CapsPageSize(t *testing.T) {
-	svc := NewGalleryService(newMockGalleryRepo())
-	_, err := svc.ListItems(context.Background(), "user1", 100, "")
+	svc := NewGalleryService(newMockGalleryRepo(), nil, nil)
+	_, err := svc.ListItems(context.Background(), "user1", 100, nil, nil)
 	require.NoError(t, err)
 }
 
 func TestGalleryService_ListItems_NegativePageSize(t *testing.T) {
-	svc := NewGalleryService(newMockGalleryRepo())
-	_, err := svc.ListItems(context.Background(), "user1", -1, "")
+	svc := NewGalleryService(newMockGalleryRepo(), nil, nil)
+	_, err := svc.ListItems(context.Background(), "user1", -1, nil, nil)
 	require.NoError(t, err)
 }
 
 func TestGalleryService_CountItems(t *testing.T) {
-	svc := NewGalleryService(newMockGalleryRepo())
+	svc := NewGalleryService(newMockGalleryRepo(), nil, nil)
 
 	svc.ShareToGallery(context.Background(), "user1", &model.GalleryItem{Name: "A"})
 	svc.ShareToGallery(context.Background(), "user1", &model.GalleryItem{Name: "B"})
@@ -904,7 +1173,7 @@ func TestGalleryService_CountItems(t *testing.T) {
 }
 
 func TestGalleryService_CountItems_EmptyUID(t *testing.T) {
-	svc := NewGalleryService(newMockGalleryRepo())
+	svc := NewGalleryService(newMockGalleryRepo(), nil, nil)
 	_, err := svc.CountItems(context.Background(), "")
 	assert.ErrorContains(t, err, "uid is required")
 }
@@ -912,7 +1181,7 @@ func TestGalleryService_CountItems_EmptyUID(t *testing.T) {
 // --- NFTService tests ---
 
 func TestNFTService_CreateAndGet(t *testing.T) {
-	svc := NewNFTService(newMockNFTRepo())
+	svc := NewNFTService(newMockNFTRepo(), nil, nil)
 
 	nft := &model.NFT{Name: "CoolNFT", Price: 10.0}
 	id, err := svc.CreateNFT(context.Background(), "user1", nft)
@@ -924,7 +1193,7 @@ func TestNFTService_CreateAndGet(t *testing.T) {
 }
 
 func TestNFTService_GetNFT_Unauthorized(t *testing.T) {
-	svc := NewNFTService(newMockNFTRepo())
+	svc := NewNFTService(newMockNFTRepo(), nil, nil)
 
 	nft := &model.NFT{Name: "NFT"}
 	id, _ := svc.CreateNFT(context.Background(), "user1", nft)
@@ -933,8 +1202,20 @@ func TestNFTService_GetNFT_Unauthorized(t *testing.T) {
 	assert.ErrorContains(t, err, "unauthorized")
 }
 
+func TestNFTService_GetNFTs_OwnerOnly(t *testing.T) {
+	svc := NewNFTService(newMockNFTRepo(), nil, nil)
+	ctx := context.Background()
+	mine, _ := svc.CreateNFT(ctx, "user1", &model.NFT{Name: "Mine"})
+	theirs, _ := svc.CreateNFT(ctx, "user2", &model.NFT{Name: "Theirs"})
+
+	got, err := svc.GetNFTs(ctx, "user1", []string{mine, theirs})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "Mine", got[mine].Name)
+}
+
 func TestNFTService_DeleteNFT_Unauthorized(t *testing.T) {
-	svc := NewNFTService(newMockNFTRepo())
+	svc := NewNFTService(newMockNFTRepo(), nil, nil)
 
 	nft := &model.NFT{Name: "NFT"}
 	id, _ := svc.CreateNFT(context.Background(), "user1", nft)
@@ -944,37 +1225,37 @@ func TestNFTService_DeleteNFT_Unauthorized(t *testing.T) {
 }
 
 func TestNFTService_CreateNFT_ValidationFails(t *testing.T) {
-	svc := NewNFTService(newMockNFTRepo())
+	svc := NewNFTService(newMockNFTRepo(), nil, nil)
 	_, err := svc.CreateNFT(context.Background(), "user1", &model.NFT{Name: "", Price: -1})
 	assert.Error(t, err)
 }
 
 func TestNFTService_GetNFT_EmptyID(t *testing.T) {
-	svc := NewNFTService(newMockNFTRepo())
+	svc := NewNFTService(newMockNFTRepo(), nil, nil)
 	_, err := svc.GetNFT(context.Background(), "user1", "")
 	assert.ErrorContains(t, err, "NFT ID is required")
 }
 
 func TestNFTService_GetNFT_NotFound(t *testing.T) {
-	svc := NewNFTService(newMockNFTRepo())
+	svc := NewNFTService(newMockNFTRepo(), nil, nil)
 	_, err := svc.GetNFT(context.Background(), "user1", "nonexistent")
 	assert.Error(t, err)
 }
 
 func TestNFTService_DeleteNFT_EmptyID(t *testing.T) {
-	svc := NewNFTService(newMockNFTRepo())
+	svc := NewNFTService(newMockNFTRepo(), nil, nil)
 	err := svc.DeleteNFT(context.Background(), "user1", "")
 	assert.ErrorContains(t, err, "NFT ID is required")
 }
 
 func TestNFTService_DeleteNFT_NotFound(t *testing.T) {
-	svc := NewNFTService(newMockNFTRepo())
+	svc := NewNFTService(newMockNFTRepo(), nil, nil)
 	err := svc.DeleteNFT(context.Background(), "user1", "nonexistent")
 	assert.Error(t, err)
 }
 
 func TestNFTService_DeleteNFT_Success(t *testing.T) {
-	svc := NewNFTService(newMockNFTRepo())
+	svc := NewNFTService(newMockNFTRepo(), nil, nil)
 	id, _ := svc.CreateNFT(context.Background(), "user1", &model.NFT{Name: "NFT"})
 	err := svc.DeleteNFT(context.Background(), "user1", id)
 	require.NoError(t, err)
@@ -983,31 +1264,31 @@ func TestNFTService_DeleteNFT_Success(t *testing.T) {
 }
 
 func TestNFTService_ListNFTs_EmptyUID(t *testing.T) {
-	svc := NewNFTService(newMockNFTRepo())
-	_, err := svc.ListNFTs(context.Background(), "", 10, "")
+	svc := NewNFTService(newMockNFTRepo(), nil, nil)
+	_, err := svc.ListNFTs(context.Background(), "", 10, nil, nil)
 	assert.ErrorContains(t, err, "uid is required")
 }
 
 func TestNFTService_ListNFTs_DefaultPageSize(t *testing.T) {
-	svc := NewNFTService(newMockNFTRepo())
-	_, err := svc.ListNFTs(context.Background(), "user1", 0, "")
+	svc := NewNFTService(newMockNFTRepo(), nil, nil)
+	_, err := svc.ListNFTs(context.Background(), "user1", 0, nil, nil)
 	require.NoError(t, err)
 }
 
 func TestNFTService_ListNFTs_CapsPageSize(t *testing.T) {
-	svc := NewNFTService(newMockNFTRepo())
-	_, err := svc.ListNFTs(context.Background(), "user1", 100, "")
+	svc := NewNFTService(newMockNFTRepo(), nil, nil)
+	_, err := svc.ListNFTs(context.Background(), "user1", 100, nil, nil)
 	require.NoError(t, err)
 }
 
 func TestNFTService_ListNFTs_NegativePageSize(t *testing.T) {
-	svc := NewNFTService(newMockNFTRepo())
-	_, err := svc.ListNFTs(context.Background(), "user1", -1, "")
+	svc := NewNFTService(newMockNFTRepo(), nil, nil)
+	_, err := svc.ListNFTs(context.Background(), "user1", -1, nil, nil)
 	require.NoError(t, err)
 }
 
 func TestNFTService_CountNFTs(t *testing.T) {
-	svc := NewNFTService(newMockNFTRepo())
+	svc := NewNFTService(newMockNFTRepo(), nil, nil)
 
 	svc.CreateNFT(context.Background(), "user1", &model.NFT{Name: "A"})
 	svc.CreateNFT(context.Background(), "user1", &model.NFT{Name: "B"})
@@ -1019,7 +1300,7 @@ func TestNFTService_CountNFTs(t *testing.T) {
 }
 
 func TestNFTService_CountNFTs_EmptyUID(t *testing.T) {
-	svc := NewNFTService(newMockNFTRepo())
+	svc := NewNFTService(newMockNFTRepo(), nil, nil)
 	_, err := svc.CountNFTs(context.Background(), "")
 	assert.ErrorContains(t, err, "uid is required")
 }
@@ -1028,7 +1309,7 @@ func TestNFTService_CountNFTs_EmptyUID(t *testing.T) {
 
 func TestProjectService_UpdateProject_ValidationFails(t *testing.T) {
 	repo := newMockProjectRepo()
-	svc := NewProjectService(repo, nil)
+	svc := NewProjectService(repo, nil, nil, nil)
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art"})
 
 	longTitle := string(make([]byte, 201))
@@ -1038,7 +1319,7 @@ func TestProjectService_UpdateProject_ValidationFails(t *testing.T) {
 }
 
 func TestProjectService_CreateProject_BadThumbnail(t *testing.T) {
-	svc := NewProjectService(newMockProjectRepo(), nil)
+	svc := NewProjectService(newMockProjectRepo(), nil, nil, nil)
 	_, err := svc.CreateProject(context.Background(), "user1", &model.Project{
 		Title:         "Art",
 		ThumbnailData: "javascript:alert(1)",
@@ -1060,7 +1341,7 @@ func validPNG() []byte {
 func TestProjectService_UploadBlob_Success(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := newMockStorageClient()
-	svc := NewProjectService(repo, storage)
+	svc := NewProjectService(repo, storage, nil, nil)
 
 	hash := "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art", ContentHash: hash})
@@ -1076,7 +1357,7 @@ func TestProjectService_UploadBlob_Success(t *testing.T) {
 func TestProjectService_UploadBlob_InvalidPNG(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := newMockStorageClient()
-	svc := NewProjectService(repo, storage)
+	svc := NewProjectService(repo, storage, nil, nil)
 
 	hash := "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art", ContentHash: hash})
@@ -1089,7 +1370,7 @@ func TestProjectService_UploadBlob_InvalidPNG(t *testing.T) {
 func TestProjectService_UploadBlob_ShortBody(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := newMockStorageClient()
-	svc := NewProjectService(repo, storage)
+	svc := NewProjectService(repo, storage, nil, nil)
 
 	hash := "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art", ContentHash: hash})
@@ -1100,13 +1381,13 @@ func TestProjectService_UploadBlob_ShortBody(t *testing.T) {
 }
 
 func TestProjectService_UploadBlob_EmptyID(t *testing.T) {
-	svc := NewProjectService(newMockProjectRepo(), newMockStorageClient())
+	svc := NewProjectService(newMockProjectRepo(), newMockStorageClient(), nil, nil)
 	err := svc.UploadBlob(context.Background(), "user1", "", bytes.NewReader(validPNG()))
 	assert.ErrorContains(t, err, "project ID is required")
 }
 
 func TestProjectService_UploadBlob_NoStorage(t *testing.T) {
-	svc := NewProjectService(newMockProjectRepo(), nil)
+	svc := NewProjectService(newMockProjectRepo(), nil, nil, nil)
 	err := svc.UploadBlob(context.Background(), "user1", "proj_1", bytes.NewReader(validPNG()))
 	assert.ErrorContains(t, err, "storage is not configured")
 }
@@ -1114,7 +1395,7 @@ func TestProjectService_UploadBlob_NoStorage(t *testing.T) {
 func TestProjectService_UploadBlob_Unauthorized(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := newMockStorageClient()
-	svc := NewProjectService(repo, storage)
+	svc := NewProjectService(repo, storage, nil, nil)
 
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{
 		Title:       "Art",
@@ -1128,7 +1409,7 @@ func TestProjectService_UploadBlob_Unauthorized(t *testing.T) {
 func TestProjectService_UploadBlob_NoContentHash(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := newMockStorageClient()
-	svc := NewProjectService(repo, storage)
+	svc := NewProjectService(repo, storage, nil, nil)
 
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art"})
 
@@ -1139,7 +1420,7 @@ func TestProjectService_UploadBlob_NoContentHash(t *testing.T) {
 func TestProjectService_UploadBlob_WriteFails(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := &failingWriteObjectStorageClient{mockStorageClient: *newMockStorageClient()}
-	svc := NewProjectService(repo, storage)
+	svc := NewProjectService(repo, storage, nil, nil)
 
 	hash := "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art", ContentHash: hash})
@@ -1151,7 +1432,7 @@ func TestProjectService_UploadBlob_WriteFails(t *testing.T) {
 func TestProjectService_UploadBlob_DownloadURLFails(t *testing.T) {
 	repo := newMockProjectRepo()
 	storage := &failingDownloadURLStorageClient{mockStorageClient: *newMockStorageClient()}
-	svc := NewProjectService(repo, storage)
+	svc := NewProjectService(repo, storage, nil, nil)
 
 	hash := "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
 	result, _ := svc.CreateProject(context.Background(), "user1", &model.Project{Title: "Art", ContentHash: hash})
@@ -1163,7 +1444,7 @@ func TestProjectService_UploadBlob_DownloadURLFails(t *testing.T) {
 // --- NFT blockchain field zeroing test ---
 
 func TestNFTService_CreateNFT_ZerosBlockchainFields(t *testing.T) {
-	svc := NewNFTService(newMockNFTRepo())
+	svc := NewNFTService(newMockNFTRepo(), nil, nil)
 
 	nft := &model.NFT{
 		Name:          "FakeNFT",
@@ -1183,7 +1464,7 @@ func TestNFTService_CreateNFT_ZerosBlockchainFields(t *testing.T) {
 
 func TestProjectService_CreateProject_StorageURLStripped(t *testing.T) {
 	repo := newMockProjectRepo()
-	svc := NewProjectService(repo, nil)
+	svc := NewProjectService(repo, nil, nil, nil)
 	result, err := svc.CreateProject(context.Background(), "user1", &model.Project{
 		Title:      "Art",
 		StorageURL: "https://evil.com/malicious.png",
@@ -1221,3 +1502,1753 @@ func TestValidateStorageURL_RejectsEvilHost(t *testing.T) {
 func TestValidateStorageURL_RejectsMalformed(t *testing.T) {
 	assert.Error(t, validateStorageURL("://not-a-url"))
 }
+
+// --- FollowService tests ---
+
+func TestFollowService_FollowAndCounts(t *testing.T) {
+	repos := newMockRepos("alice", "bob", "carol")
+	svc := NewFollowService(repos.users, repos.follows, repos.gallery)
+	ctx := context.Background()
+
+	require.NoError(t, svc.Follow(ctx, "alice", "bob"))
+	require.NoError(t, svc.Follow(ctx, "alice", "bob")) // idempotent
+
+	bob, err := svc.GetPublicProfile(ctx, "alice", "bob")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), bob.FollowerCount)
+	assert.True(t, bob.IsFollowing)
+
+	alice, err := svc.GetPublicProfile(ctx, "bob", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), alice.FollowingCount)
+	assert.False(t, alice.IsFollowing)
+}
+
+func TestFollowService_Unfollow(t *testing.T) {
+	repos := newMockRepos("alice", "bob", "carol")
+	svc := NewFollowService(repos.users, repos.follows, repos.gallery)
+	ctx := context.Background()
+
+	require.NoError(t, svc.Follow(ctx, "alice", "bob"))
+	require.NoError(t, svc.Unfollow(ctx, "alice", "bob"))
+	require.NoError(t, svc.Unfollow(ctx, "alice", "bob")) // idempotent
+
+	bob, err := svc.GetPublicProfile(ctx, "alice", "bob")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), bob.FollowerCount)
+	assert.False(t, bob.IsFollowing)
+}
+
+func TestFollowService_Follow_CapHoldsUnderConcurrency(t *testing.T) {
+	repos := newMockRepos("alice", "bob", "carol")
+	svc := NewFollowService(repos.users, repos.follows, repos.gallery)
+	repos.users.users["alice"].FollowingCount = repository.MaxFollowing - 1
+
+	errs := make(chan error, 2)
+	for _, target := range []string{"bob", "carol"} {
+		go func() { errs <- svc.Follow(context.Background(), "alice", target) }()
+	}
+	var failed []error
+	for range 2 {
+		if err := <-errs; err != nil {
+			failed = append(failed, err)
+		}
+	}
+	require.Len(t, failed, 1, "only one follow fits under the cap")
+	assert.ErrorContains(t, failed[0], "cannot follow more than")
+	assert.Equal(t, int64(repository.MaxFollowing), repos.users.users["alice"].FollowingCount)
+}
+
+func TestFollowService_Follow_Self(t *testing.T) {
+	repos := newMockRepos("alice", "bob", "carol")
+	svc := NewFollowService(repos.users, repos.follows, repos.gallery)
+	err := svc.Follow(context.Background(), "alice", "alice")
+	assert.ErrorContains(t, err, "cannot follow yourself")
+}
+
+func TestFollowService_Follow_RequiresUsername(t *testing.T) {
+	repos := newMockRepos("alice", "bob", "carol")
+	svc := NewFollowService(repos.users, repos.follows, repos.gallery)
+	repos.users.users["dave"] = &model.User{UID: "dave", Email: "d@b.com"}
+	err := svc.Follow(context.Background(), "dave", "bob")
+	assert.ErrorContains(t, err, "username is required")
+}
+
+func TestFollowService_Follow_UnknownUser(t *testing.T) {
+	repos := newMockRepos("alice", "bob", "carol")
+	svc := NewFollowService(repos.users, repos.follows, repos.gallery)
+	err := svc.Follow(context.Background(), "alice", "nobody")
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestFollowService_Follow_InvalidUsername(t *testing.T) {
+	repos := newMockRepos("alice", "bob", "carol")
+	svc := NewFollowService(repos.users, repos.follows, repos.gallery)
+	err := svc.Follow(context.Background(), "alice", "../etc")
+	assert.ErrorContains(t, err, "invalid username")
+}
+
+func TestFollowService_GetPublicProfile_OmitsEmail(t *testing.T) {
+	repos := newMockRepos("alice", "bob", "carol")
+	svc := NewFollowService(repos.users, repos.follows, repos.gallery)
+	profile, err := svc.GetPublicProfile(context.Background(), "alice", "bob")
+	require.NoError(t, err)
+	assert.Equal(t, "bob", profile.UID)
+	assert.Equal(t, "bob", profile.Username)
+}
+
+func TestFollowService_GetPublicProfiles_SkipsSuspended(t *testing.T) {
+	repos := newMockRepos("alice", "bob", "carol")
+	svc := NewFollowService(repos.users, repos.follows, repos.gallery)
+	repos.users.users["carol"].Suspended = true
+
+	profiles, err := svc.GetPublicProfiles(context.Background(), []string{"alice", "carol", "nobody"})
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+	assert.Equal(t, "alice", profiles["alice"].Username)
+}
+
+func TestFollowService_ListFollowersAndFollowing(t *testing.T) {
+	repos := newMockRepos("alice", "bob", "carol")
+	svc := NewFollowService(repos.users, repos.follows, repos.gallery)
+	ctx := context.Background()
+	require.NoError(t, svc.Follow(ctx, "alice", "carol"))
+	require.NoError(t, svc.Follow(ctx, "bob", "carol"))
+
+	followers, err := svc.ListFollowers(ctx, "carol", 10, "")
+	require.NoError(t, err)
+	assert.Len(t, followers, 2)
+
+	following, err := svc.ListFollowing(ctx, "alice", 10, "")
+	require.NoError(t, err)
+	require.Len(t, following, 1)
+	assert.Equal(t, "carol", following[0].UID)
+}
+
+func TestFollowService_FollowingFeed_MergesByTime(t *testing.T) {
+	repos := newMockRepos("alice", "bob", "carol")
+	svc := NewFollowService(repos.users, repos.follows, repos.gallery)
+	ctx := context.Background()
+	require.NoError(t, svc.Follow(ctx, "alice", "bob"))
+	require.NoError(t, svc.Follow(ctx, "alice", "carol"))
+
+	now := time.Now()
+	repos.gallery.items["g1"] = &model.GalleryItem{ID: "g1", UserID: "bob", Name: "old", CreatedAt: now.Add(-3 * time.Hour)}
+	repos.gallery.items["g2"] = &model.GalleryItem{ID: "g2", UserID: "carol", Name: "mid", CreatedAt: now.Add(-2 * time.Hour)}
+	repos.gallery.items["g3"] = &model.GalleryItem{ID: "g3", UserID: "bob", Name: "new", CreatedAt: now.Add(-1 * time.Hour)}
+	repos.gallery.items["g4"] = &model.GalleryItem{ID: "g4", UserID: "alice", Name: "own", CreatedAt: now.Add(-1 * time.Minute)}
+
+	page, err := svc.FollowingFeed(ctx, "alice", 2, nil)
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, "g3", page.Items[0].ID)
+	assert.Equal(t, "g2", page.Items[1].ID)
+	assert.True(t, page.HasMore)
+
+	next, err := svc.FollowingFeed(ctx, "alice", 2, page.Next)
+	require.NoError(t, err)
+	require.Len(t, next.Items, 1)
+	assert.Equal(t, "g1", next.Items[0].ID)
+	assert.False(t, next.HasMore)
+	assert.Nil(t, next.Next)
+}
+
+func TestFollowService_FollowingFeed_PagesThroughTiedTimestamps(t *testing.T) {
+	repos := newMockRepos("alice", "bob", "carol")
+	svc := NewFollowService(repos.users, repos.follows, repos.gallery)
+	ctx := context.Background()
+	require.NoError(t, svc.Follow(ctx, "alice", "bob"))
+	require.NoError(t, svc.Follow(ctx, "alice", "carol"))
+
+	now := time.Now()
+	for i, uid := range []string{"bob", "carol", "bob", "carol", "bob"} {
+		id := fmt.Sprintf("g%d", i)
+		repos.gallery.items[id] = &model.GalleryItem{ID: id, UserID: uid, CreatedAt: now.Add(-time.Hour)}
+	}
+
+	var seen []string
+	var after *model.Cursor
+	for {
+		page, err := svc.FollowingFeed(ctx, "alice", 2, after)
+		require.NoError(t, err)
+		for _, item := range page.Items {
+			seen = append(seen, item.ID)
+		}
+		if !page.HasMore {
+			break
+		}
+		after = page.Next
+	}
+	assert.Equal(t, []string{"g4", "g3", "g2", "g1", "g0"}, seen, "items sharing a timestamp are neither skipped nor repeated")
+}
+
+func TestFollowService_FollowingFeed_FillsPageAroundHiddenItems(t *testing.T) {
+	repos := newMockRepos("alice", "bob", "carol")
+	svc := NewFollowService(repos.users, repos.follows, repos.gallery)
+	ctx := context.Background()
+	require.NoError(t, svc.Follow(ctx, "alice", "bob"))
+
+	now := time.Now()
+	for i, hidden := range []bool{true, true, false, true, false, false} {
+		id := fmt.Sprintf("g%d", i)
+		repos.gallery.items[id] = &model.GalleryItem{ID: id, UserID: "bob", CreatedAt: now.Add(-time.Duration(i) * time.Hour), Hidden: hidden}
+	}
+
+	page, err := svc.FollowingFeed(ctx, "alice", 2, nil)
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2, "hidden items do not shorten the page")
+	assert.Equal(t, "g2", page.Items[0].ID)
+	assert.Equal(t, "g4", page.Items[1].ID)
+
+	next, err := svc.FollowingFeed(ctx, "alice", 2, page.Next)
+	require.NoError(t, err)
+	require.Len(t, next.Items, 1)
+	assert.Equal(t, "g5", next.Items[0].ID)
+}
+
+func TestFollowService_FollowingFeed_SkipsSuspendedUsers(t *testing.T) {
+	repos := newMockRepos("alice", "bob", "carol")
+	svc := NewFollowService(repos.users, repos.follows, repos.gallery)
+	ctx := context.Background()
+	require.NoError(t, svc.Follow(ctx, "alice", "bob"))
+	require.NoError(t, svc.Follow(ctx, "alice", "carol"))
+	repos.users.users["bob"].Suspended = true
+
+	now := time.Now()
+	repos.gallery.items["g1"] = &model.GalleryItem{ID: "g1", UserID: "bob", CreatedAt: now.Add(-time.Hour)}
+	repos.gallery.items["g2"] = &model.GalleryItem{ID: "g2", UserID: "carol", CreatedAt: now.Add(-2 * time.Hour)}
+
+	page, err := svc.FollowingFeed(ctx, "alice", 10, nil)
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "g2", page.Items[0].ID)
+}
+
+func TestFollowService_SuspendedUserIsNotFound(t *testing.T) {
+	repos := newMockRepos("alice", "bob", "carol")
+	svc := NewFollowService(repos.users, repos.follows, repos.gallery)
+	ctx := context.Background()
+	require.NoError(t, svc.Follow(ctx, "alice", "bob"))
+	repos.users.users["bob"].Suspended = true
+
+	_, err := svc.GetPublicProfile(ctx, "alice", "bob")
+	assert.ErrorContains(t, err, "not found")
+	_, err = svc.ListFollowers(ctx, "bob", 10, "")
+	assert.ErrorContains(t, err, "not found")
+	_, err = svc.ListFollowing(ctx, "bob", 10, "")
+	assert.ErrorContains(t, err, "not found")
+	assert.ErrorContains(t, svc.Follow(ctx, "carol", "bob"), "not found")
+
+	require.NoError(t, svc.Unfollow(ctx, "alice", "bob"), "suspended users can still be unfollowed")
+}
+
+func TestFollowService_FollowingFeed_CursorOutlivesItsItem(t *testing.T) {
+	repos := newMockRepos("alice", "bob", "carol")
+	svc := NewFollowService(repos.users, repos.follows, repos.gallery)
+	ctx := context.Background()
+	require.NoError(t, svc.Follow(ctx, "alice", "bob"))
+
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		id := fmt.Sprintf("g%d", i)
+		repos.gallery.items[id] = &model.GalleryItem{ID: id, UserID: "bob", CreatedAt: now.Add(-time.Duration(i) * time.Hour)}
+	}
+	page, err := svc.FollowingFeed(ctx, "alice", 1, nil)
+	require.NoError(t, err)
+	require.Equal(t, "g0", page.Items[0].ID)
+
+	// The last item of a page is deleted before the next page is read
+	delete(repos.gallery.items, "g0")
+	next, err := svc.FollowingFeed(ctx, "alice", 1, page.Next)
+	require.NoError(t, err)
+	require.Len(t, next.Items, 1)
+	assert.Equal(t, "g1", next.Items[0].ID)
+	assert.True(t, next.HasMore)
+}
+
+func TestFollowService_FollowingFeed_RejectsListCursor(t *testing.T) {
+	repos := newMockRepos("alice", "bob", "carol")
+	svc := NewFollowService(repos.users, repos.follows, repos.gallery)
+
+	list := &model.Cursor{Sort: "createdAt", Order: model.SortDesc, Value: time.Now().Format(time.RFC3339Nano), ID: "g1"}
+	_, err := svc.FollowingFeed(context.Background(), "alice", 10, list)
+	assert.ErrorContains(t, err, "invalid cursor")
+}
+
+func TestFollowService_FollowingFeed_EmptyUID(t *testing.T) {
+	repos := newMockRepos("alice", "bob", "carol")
+	svc := NewFollowService(repos.users, repos.follows, repos.gallery)
+	_, err := svc.FollowingFeed(context.Background(), "", 10, nil)
+	assert.ErrorContains(t, err, "uid is required")
+}
+
+// --- CommentService tests ---
+
+func TestCommentService_CreateAndList(t *testing.T) {
+	repos := newMockRepos("owner", "fan", "troll")
+	repos.gallery.items["item1"] = &model.GalleryItem{ID: "item1", UserID: "owner", Name: "Art"}
+	svc := NewCommentService(repos.gallery, repos.comments, repos.users)
+	ctx := context.Background()
+
+	id, err := svc.CreateComment(ctx, "fan", "item1", &model.Comment{Body: "  Love it \n "})
+	require.NoError(t, err)
+
+	comments, err := svc.ListComments(ctx, "item1", 10, "")
+	require.NoError(t, err)
+	require.Len(t, comments, 1)
+	assert.Equal(t, id, comments[0].ID)
+	assert.Equal(t, "Love it", comments[0].Body)
+	assert.Equal(t, "fan", comments[0].Username)
+	assert.Equal(t, int64(1), repos.gallery.items["item1"].CommentCount)
+}
+
+func TestCommentService_Create_StripsControlChars(t *testing.T) {
+	repos := newMockRepos("owner", "fan", "troll")
+	repos.gallery.items["item1"] = &model.GalleryItem{ID: "item1", UserID: "owner", Name: "Art"}
+	svc := NewCommentService(repos.gallery, repos.comments, repos.users)
+	c := &model.Comment{Body: "Nice\x00 work\r"}
+	_, err := svc.CreateComment(context.Background(), "fan", "item1", c)
+	require.NoError(t, err)
+	assert.Equal(t, "Nice work", c.Body)
+}
+
+func TestCommentService_Create_TooLong(t *testing.T) {
+	repos := newMockRepos("owner", "fan", "troll")
+	repos.gallery.items["item1"] = &model.GalleryItem{ID: "item1", UserID: "owner", Name: "Art"}
+	svc := NewCommentService(repos.gallery, repos.comments, repos.users)
+	_, err := svc.CreateComment(context.Background(), "fan", "item1",
+		&model.Comment{Body: strings.Repeat("a", model.MaxCommentLen+1)})
+	assert.ErrorContains(t, err, "must be")
+}
+
+func TestCommentService_Create_Empty(t *testing.T) {
+	repos := newMockRepos("owner", "fan", "troll")
+	repos.gallery.items["item1"] = &model.GalleryItem{ID: "item1", UserID: "owner", Name: "Art"}
+	svc := NewCommentService(repos.gallery, repos.comments, repos.users)
+	_, err := svc.CreateComment(context.Background(), "fan", "item1", &model.Comment{Body: " \n "})
+	assert.ErrorContains(t, err, "is required")
+}
+
+func TestCommentService_Create_RequiresUsername(t *testing.T) {
+	repos := newMockRepos("owner", "fan", "troll")
+	repos.users.users["anon"] = &model.User{UID: "anon", Email: "anon@example.com"}
+	repos.gallery.items["item1"] = &model.GalleryItem{ID: "item1", UserID: "owner", Name: "Art"}
+	svc := NewCommentService(repos.gallery, repos.comments, repos.users)
+	_, err := svc.CreateComment(context.Background(), "anon", "item1", &model.Comment{Body: "hi"})
+	assert.ErrorContains(t, err, "username is required")
+}
+
+func TestCommentService_Create_ItemNotFound(t *testing.T) {
+	repos := newMockRepos("owner", "fan", "troll")
+	svc := NewCommentService(repos.gallery, repos.comments, repos.users)
+	_, err := svc.CreateComment(context.Background(), "fan", "missing", &model.Comment{Body: "hi"})
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestCommentService_Reply_NoNesting(t *testing.T) {
+	repos := newMockRepos("owner", "fan", "troll")
+	repos.gallery.items["item1"] = &model.GalleryItem{ID: "item1", UserID: "owner", Name: "Art"}
+	svc := NewCommentService(repos.gallery, repos.comments, repos.users)
+	ctx := context.Background()
+
+	parent, err := svc.CreateComment(ctx, "fan", "item1", &model.Comment{Body: "top"})
+	require.NoError(t, err)
+	reply, err := svc.CreateComment(ctx, "owner", "item1", &model.Comment{Body: "thanks", ParentID: parent})
+	require.NoError(t, err)
+
+	_, err = svc.CreateComment(ctx, "fan", "item1", &model.Comment{Body: "deep", ParentID: reply})
+	assert.ErrorContains(t, err, "cannot be nested")
+}
+
+func TestCommentService_Edit_AuthorOnly(t *testing.T) {
+	repos := newMockRepos("owner", "fan", "troll")
+	repos.gallery.items["item1"] = &model.GalleryItem{ID: "item1", UserID: "owner", Name: "Art"}
+	svc := NewCommentService(repos.gallery, repos.comments, repos.users)
+	ctx := context.Background()
+	id, _ := svc.CreateComment(ctx, "fan", "item1", &model.Comment{Body: "first"})
+
+	err := svc.EditComment(ctx, "owner", "item1", id, "hijacked")
+	assert.ErrorContains(t, err, "unauthorized")
+
+	require.NoError(t, svc.EditComment(ctx, "fan", "item1", id, "second"))
+	comments, _ := svc.ListComments(ctx, "item1", 10, "")
+	assert.Equal(t, "second", comments[0].Body)
+	assert.True(t, comments[0].Edited)
+}
+
+func TestCommentService_Edit_HiddenItem(t *testing.T) {
+	repos := newMockRepos("owner", "fan", "troll")
+	repos.gallery.items["item1"] = &model.GalleryItem{ID: "item1", UserID: "owner", Name: "Art"}
+	svc := NewCommentService(repos.gallery, repos.comments, repos.users)
+	ctx := context.Background()
+	id, _ := svc.CreateComment(ctx, "fan", "item1", &model.Comment{Body: "first"})
+	repos.gallery.items["item1"].Hidden = true
+
+	err := svc.EditComment(ctx, "fan", "item1", id, "second")
+	assert.ErrorContains(t, err, "not found")
+	assert.Equal(t, "first", repos.comments.comments[id].Body)
+}
+
+func TestCommentService_Edit_HiddenComment(t *testing.T) {
+	repos := newMockRepos("owner", "fan", "troll")
+	repos.gallery.items["item1"] = &model.GalleryItem{ID: "item1", UserID: "owner", Name: "Art"}
+	svc := NewCommentService(repos.gallery, repos.comments, repos.users)
+	ctx := context.Background()
+	id, _ := svc.CreateComment(ctx, "fan", "item1", &model.Comment{Body: "first"})
+	require.NoError(t, repos.comments.SetHidden(ctx, "item1", id, true))
+
+	err := svc.EditComment(ctx, "fan", "item1", id, "second")
+	assert.ErrorContains(t, err, "not found")
+	assert.Equal(t, "first", repos.comments.comments[id].Body)
+}
+
+func TestCommentService_Delete_ByAuthorOrOwner(t *testing.T) {
+	repos := newMockRepos("owner", "fan", "troll")
+	repos.gallery.items["item1"] = &model.GalleryItem{ID: "item1", UserID: "owner", Name: "Art"}
+	svc := NewCommentService(repos.gallery, repos.comments, repos.users)
+	ctx := context.Background()
+	byFan, _ := svc.CreateComment(ctx, "fan", "item1", &model.Comment{Body: "one"})
+	byTroll, _ := svc.CreateComment(ctx, "troll", "item1", &model.Comment{Body: "two"})
+	_, _ = svc.CreateComment(ctx, "fan", "item1", &model.Comment{Body: "reply", ParentID: byTroll})
+
+	assert.ErrorContains(t, svc.DeleteComment(ctx, "troll", "item1", byFan), "unauthorized")
+	require.NoError(t, svc.DeleteComment(ctx, "fan", "item1", byFan))
+	require.NoError(t, svc.DeleteComment(ctx, "owner", "item1", byTroll))
+
+	comments, err := svc.ListComments(ctx, "item1", 10, "")
+	require.NoError(t, err)
+	assert.Empty(t, comments)
+	assert.Equal(t, int64(0), repos.gallery.items["item1"].CommentCount)
+}
+
+// --- ReactionService tests ---
+
+func TestReactionService_AddRemoveAndCounts(t *testing.T) {
+	gallery := newMockGalleryRepo()
+	gallery.items["item1"] = &model.GalleryItem{ID: "item1", UserID: "owner", Name: "Art"}
+	svc := NewReactionService(gallery, newMockReactionRepo(gallery))
+	ctx := context.Background()
+
+	require.NoError(t, svc.AddReaction(ctx, "a", "item1", "heart"))
+	require.NoError(t, svc.AddReaction(ctx, "a", "item1", "heart")) // idempotent
+	require.NoError(t, svc.AddReaction(ctx, "b", "item1", "heart"))
+	require.NoError(t, svc.AddReaction(ctx, "a", "item1", "fire"))
+
+	summary, err := svc.GetReactions(ctx, "a", "item1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), summary.Counts["heart"])
+	assert.Equal(t, int64(1), summary.Counts["fire"])
+	assert.Equal(t, []string{"fire", "heart"}, summary.Mine)
+
+	require.NoError(t, svc.RemoveReaction(ctx, "a", "item1", "fire"))
+	summary, err = svc.GetReactions(ctx, "a", "item1")
+	require.NoError(t, err)
+	_, hasFire := summary.Counts["fire"]
+	assert.False(t, hasFire)
+	assert.Equal(t, []string{"heart"}, summary.Mine)
+}
+
+func TestReactionService_InvalidReaction(t *testing.T) {
+	gallery := newMockGalleryRepo()
+	gallery.items["item1"] = &model.GalleryItem{ID: "item1", UserID: "owner", Name: "Art"}
+	svc := NewReactionService(gallery, newMockReactionRepo(gallery))
+
+	err := svc.AddReaction(context.Background(), "a", "item1", "poop")
+	assert.ErrorContains(t, err, "invalid reaction")
+}
+
+func TestReactionService_ItemNotFound(t *testing.T) {
+	gallery := newMockGalleryRepo()
+	svc := NewReactionService(gallery, newMockReactionRepo(gallery))
+
+	err := svc.AddReaction(context.Background(), "a", "missing", "heart")
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestGalleryService_ShareToGallery_ResetsCounters(t *testing.T) {
+	svc := NewGalleryService(newMockGalleryRepo(), nil, nil)
+	item := &model.GalleryItem{Name: "Art", CommentCount: 99, ReactionCounts: map[string]int64{"heart": 1000}, Hidden: true}
+	_, err := svc.ShareToGallery(context.Background(), "user1", item)
+	require.NoError(t, err)
+	assert.Zero(t, item.CommentCount)
+	assert.Nil(t, item.ReactionCounts)
+	assert.False(t, item.Hidden)
+}
+
+// --- ModerationService tests ---
+
+func TestModerationService_CreateReport(t *testing.T) {
+	repos := newMockRepos("admin", "fan", "troll")
+	repos.gallery.items["item1"] = &model.GalleryItem{ID: "item1", UserID: "troll", Name: "Bad", CreatedAt: time.Now().Add(-time.Hour)}
+	svc := NewModerationService(repos.reports, repos.users, repos.gallery, repos.comments, repos.nfts, repos.audit)
+	r := &model.Report{TargetType: "Gallery", TargetID: "item1", Reason: "spam", Details: " buy now ", Status: "actioned"}
+	id, err := svc.CreateReport(context.Background(), "fan", r)
+	require.NoError(t, err)
+
+	stored := repos.reports.reports[id]
+	assert.Equal(t, "fan", stored.ReporterID)
+	assert.Equal(t, model.ReportTargetGallery, stored.TargetType)
+	assert.Equal(t, model.ReportStatusOpen, stored.Status)
+	assert.Equal(t, "buy now", stored.Details)
+}
+
+func TestModerationService_CreateReport_Duplicate(t *testing.T) {
+	repos := newMockRepos("admin", "fan", "troll")
+	svc := NewModerationService(repos.reports, repos.users, repos.gallery, repos.comments, repos.nfts, repos.audit)
+	ctx := context.Background()
+	_, err := svc.CreateReport(ctx, "fan", &model.Report{TargetType: "user", TargetID: "troll", Reason: "harassment"})
+	require.NoError(t, err)
+	_, err = svc.CreateReport(ctx, "fan", &model.Report{TargetType: "user", TargetID: "troll", Reason: "spam"})
+	assert.ErrorContains(t, err, "already exists")
+}
+
+func TestModerationService_CreateReport_Invalid(t *testing.T) {
+	repos := newMockRepos("admin", "fan", "troll")
+	repos.gallery.items["item1"] = &model.GalleryItem{ID: "item1", UserID: "troll", Name: "Bad", CreatedAt: time.Now().Add(-time.Hour)}
+	svc := NewModerationService(repos.reports, repos.users, repos.gallery, repos.comments, repos.nfts, repos.audit)
+	ctx := context.Background()
+
+	_, err := svc.CreateReport(ctx, "fan", &model.Report{TargetType: "project", TargetID: "p1", Reason: "spam"})
+	assert.ErrorContains(t, err, "invalid targetType")
+
+	_, err = svc.CreateReport(ctx, "fan", &model.Report{TargetType: "gallery", TargetID: "item1", Reason: "boring"})
+	assert.ErrorContains(t, err, "invalid reason")
+
+	_, err = svc.CreateReport(ctx, "fan", &model.Report{TargetType: "comment", TargetID: "c_001", Reason: "spam"})
+	assert.ErrorContains(t, err, "itemId is required")
+
+	_, err = svc.CreateReport(ctx, "fan", &model.Report{TargetType: "user", TargetID: "fan", Reason: "spam"})
+	assert.ErrorContains(t, err, "cannot report yourself")
+}
+
+func TestModerationService_CreateReport_TargetNotFound(t *testing.T) {
+	repos := newMockRepos("admin", "fan", "troll")
+	svc := NewModerationService(repos.reports, repos.users, repos.gallery, repos.comments, repos.nfts, repos.audit)
+	_, err := svc.CreateReport(context.Background(), "fan", &model.Report{TargetType: "nft", TargetID: "missing", Reason: "copyright"})
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestModerationService_ListAndResolveReports(t *testing.T) {
+	repos := newMockRepos("admin", "fan", "troll")
+	repos.nfts.nfts["nft1"] = &model.NFT{ID: "nft1", UserID: "troll", Name: "Bad NFT"}
+	svc := NewModerationService(repos.reports, repos.users, repos.gallery, repos.comments, repos.nfts, repos.audit)
+	ctx := context.Background()
+	id, err := svc.CreateReport(ctx, "fan", &model.Report{TargetType: "nft", TargetID: "nft1", Reason: "copyright"})
+	require.NoError(t, err)
+
+	open, err := svc.ListReports(ctx, "", 10, "")
+	require.NoError(t, err)
+	require.Len(t, open, 1)
+
+	require.NoError(t, svc.ResolveReport(ctx, "admin", id, model.ReportStatusActioned, "removed"))
+	open, err = svc.ListReports(ctx, model.ReportStatusOpen, 10, "")
+	require.NoError(t, err)
+	assert.Empty(t, open)
+	assert.Equal(t, "admin", repos.reports.reports[id].ResolvedBy)
+
+	assert.ErrorContains(t, svc.ResolveReport(ctx, "admin", id, "open", ""), "invalid status")
+	_, err = svc.ListReports(ctx, "pending", 10, "")
+	assert.ErrorContains(t, err, "invalid status")
+}
+
+func TestModerationService_HideGalleryItem_ExcludedFromFeedAndComments(t *testing.T) {
+	repos := newMockRepos("admin", "fan", "troll")
+	repos.gallery.items["item1"] = &model.GalleryItem{ID: "item1", UserID: "troll", Name: "Bad", CreatedAt: time.Now().Add(-time.Hour)}
+	svc := NewModerationService(repos.reports, repos.users, repos.gallery, repos.comments, repos.nfts, repos.audit)
+	ctx := context.Background()
+	follows := NewFollowService(repos.users, repos.follows, repos.gallery)
+	commentSvc := NewCommentService(repos.gallery, repos.comments, repos.users)
+	require.NoError(t, follows.Follow(ctx, "fan", "troll"))
+
+	feed, err := follows.FollowingFeed(ctx, "fan", 10, nil)
+	require.NoError(t, err)
+	require.Len(t, feed.Items, 1)
+
+	require.NoError(t, svc.SetGalleryItemHidden(ctx, "admin", "item1", true))
+
+	feed, err = follows.FollowingFeed(ctx, "fan", 10, nil)
+	require.NoError(t, err)
+	assert.Empty(t, feed.Items)
+	_, err = commentSvc.ListComments(ctx, "item1", 10, "")
+	assert.ErrorContains(t, err, "not found")
+	_, err = NewReactionService(repos.gallery, newMockReactionRepo(repos.gallery)).GetReactions(ctx, "fan", "item1")
+	assert.ErrorContains(t, err, "not found")
+
+	require.NoError(t, svc.SetGalleryItemHidden(ctx, "admin", "item1", false))
+	feed, err = follows.FollowingFeed(ctx, "fan", 10, nil)
+	require.NoError(t, err)
+	assert.Len(t, feed.Items, 1)
+}
+
+func TestModerationService_HideComment_ExcludedFromList(t *testing.T) {
+	repos := newMockRepos("admin", "fan", "troll")
+	repos.gallery.items["item1"] = &model.GalleryItem{ID: "item1", UserID: "troll", Name: "Bad", CreatedAt: time.Now().Add(-time.Hour)}
+	svc := NewModerationService(repos.reports, repos.users, repos.gallery, repos.comments, repos.nfts, repos.audit)
+	ctx := context.Background()
+	commentSvc := NewCommentService(repos.gallery, repos.comments, repos.users)
+	bad, err := commentSvc.CreateComment(ctx, "troll", "item1", &model.Comment{Body: "rude"})
+	require.NoError(t, err)
+	_, err = commentSvc.CreateComment(ctx, "fan", "item1", &model.Comment{Body: "nice"})
+	require.NoError(t, err)
+
+	require.NoError(t, svc.SetCommentHidden(ctx, "admin", "item1", bad, true))
+
+	comments, err := commentSvc.ListComments(ctx, "item1", 10, "")
+	require.NoError(t, err)
+	require.Len(t, comments, 1)
+	assert.Equal(t, "nice", comments[0].Body)
+}
+
+func TestModerationService_RemoveContent(t *testing.T) {
+	repos := newMockRepos("admin", "fan", "troll")
+	repos.gallery.items["item1"] = &model.GalleryItem{ID: "item1", UserID: "troll", Name: "Bad", CreatedAt: time.Now().Add(-time.Hour)}
+	repos.nfts.nfts["nft1"] = &model.NFT{ID: "nft1", UserID: "troll", Name: "Bad NFT"}
+	svc := NewModerationService(repos.reports, repos.users, repos.gallery, repos.comments, repos.nfts, repos.audit)
+	ctx := context.Background()
+	commentSvc := NewCommentService(repos.gallery, repos.comments, repos.users)
+	cid, err := commentSvc.CreateComment(ctx, "troll", "item1", &model.Comment{Body: "rude"})
+	require.NoError(t, err)
+
+	require.NoError(t, svc.RemoveComment(ctx, "admin", "item1", cid))
+	assert.Empty(t, repos.comments.comments)
+	require.NoError(t, svc.RemoveGalleryItem(ctx, "admin", "item1"))
+	assert.Empty(t, repos.gallery.items)
+	require.NoError(t, svc.RemoveNFT(ctx, "admin", "nft1"))
+	assert.Empty(t, repos.nfts.nfts)
+
+	assert.ErrorContains(t, svc.RemoveNFT(ctx, "admin", "nft1"), "not found")
+	assert.Equal(t, []string{
+		model.AuditActionCommentRemove,
+		model.AuditActionGalleryRemove,
+		model.AuditActionNFTRemove,
+	}, repos.audit.actions())
+	assert.Equal(t, "admin", repos.audit.entries[1].ActorUID)
+	assert.Equal(t, "troll", repos.audit.entries[1].Details["ownerUid"])
+}
+
+func TestModerationService_SetNFTHidden(t *testing.T) {
+	repos := newMockRepos("admin", "fan", "troll")
+	repos.nfts.nfts["nft1"] = &model.NFT{ID: "nft1", UserID: "troll", Name: "Bad NFT"}
+	svc := NewModerationService(repos.reports, repos.users, repos.gallery, repos.comments, repos.nfts, repos.audit)
+	require.NoError(t, svc.SetNFTHidden(context.Background(), "admin", "nft1", true))
+	assert.True(t, repos.nfts.nfts["nft1"].Hidden)
+}
+
+func TestModerationService_SuspendUser(t *testing.T) {
+	repos := newMockRepos("admin", "fan", "troll")
+	svc := NewModerationService(repos.reports, repos.users, repos.gallery, repos.comments, repos.nfts, repos.audit)
+	ctx := context.Background()
+	follows := NewFollowService(repos.users, repos.follows, repos.gallery)
+
+	require.NoError(t, svc.SetUserSuspended(ctx, "admin", "troll", true))
+
+	suspended, err := svc.IsSuspended(ctx, "troll")
+	require.NoError(t, err)
+	assert.True(t, suspended)
+
+	_, err = follows.GetPublicProfile(ctx, "fan", "troll")
+	assert.ErrorContains(t, err, "not found")
+	assert.ErrorContains(t, follows.Follow(ctx, "fan", "troll"), "not found")
+
+	require.NoError(t, svc.SetUserSuspended(ctx, "admin", "troll", false))
+	_, err = follows.GetPublicProfile(ctx, "fan", "troll")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{model.AuditActionUserSuspend, model.AuditActionUserUnsuspend}, repos.audit.actions())
+}
+
+func TestModerationService_SuspendSelf(t *testing.T) {
+	repos := newMockRepos("admin", "fan", "troll")
+	svc := NewModerationService(repos.reports, repos.users, repos.gallery, repos.comments, repos.nfts, repos.audit)
+	err := svc.SetUserSuspended(context.Background(), "admin", "admin", true)
+	assert.ErrorContains(t, err, "cannot suspend yourself")
+}
+
+func TestModerationService_IsSuspended_NoProfile(t *testing.T) {
+	repos := newMockRepos("admin", "fan", "troll")
+	svc := NewModerationService(repos.reports, repos.users, repos.gallery, repos.comments, repos.nfts, repos.audit)
+	suspended, err := svc.IsSuspended(context.Background(), "brand-new")
+	require.NoError(t, err)
+	assert.False(t, suspended)
+}
+
+func TestModerationService_AuditFailureDoesNotFailAction(t *testing.T) {
+	repos := newMockRepos("admin", "fan", "troll")
+	repos.nfts.nfts["nft1"] = &model.NFT{ID: "nft1", UserID: "troll", Name: "Bad NFT"}
+	svc := NewModerationService(repos.reports, repos.users, repos.gallery, repos.comments, repos.nfts, repos.audit)
+	repos.audit.err = fmt.Errorf("firestore unavailable")
+	require.NoError(t, svc.SetNFTHidden(context.Background(), "admin", "nft1", true))
+	assert.True(t, repos.nfts.nfts["nft1"].Hidden)
+}
+
+// --- AdminService tests ---
+
+func TestAdminService_LookupUser(t *testing.T) {
+	repos := newMockRepos("admin", "alice")
+	svc := NewAdminService(repos.accounts, repos.users, &mockStatsRepo{}, repos.audit)
+	ctx := context.Background()
+
+	tests := []struct {
+		name                 string
+		email, uid, username string
+	}{
+		{"by email", "ALICE@example.com", "", ""},
+		{"by uid", "", "alice", ""},
+		{"by username", "", "", " Alice "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := svc.LookupUser(ctx, "admin", tt.email, tt.uid, tt.username)
+			require.NoError(t, err)
+			assert.Equal(t, "alice", user.Account.UID)
+			require.NotNil(t, user.Profile)
+			assert.Equal(t, "alice", user.Profile.Username)
+		})
+	}
+	assert.Len(t, repos.audit.entries, 3)
+	assert.Equal(t, model.AuditActionUserLookup, repos.audit.entries[0].Action)
+}
+
+func TestAdminService_LookupUser_NoProfile(t *testing.T) {
+	repos := newMockRepos("admin", "alice")
+	repos.accounts.accounts["newbie"] = &model.Account{UID: "newbie", Email: "newbie@example.com"}
+	svc := NewAdminService(repos.accounts, repos.users, &mockStatsRepo{}, repos.audit)
+	user, err := svc.LookupUser(context.Background(), "admin", "", "newbie", "")
+	require.NoError(t, err)
+	assert.Equal(t, "newbie@example.com", user.Account.Email)
+	assert.Nil(t, user.Profile)
+}
+
+func TestAdminService_LookupUser_Invalid(t *testing.T) {
+	repos := newMockRepos("admin", "alice")
+	svc := NewAdminService(repos.accounts, repos.users, &mockStatsRepo{}, repos.audit)
+	ctx := context.Background()
+
+	_, err := svc.LookupUser(ctx, "admin", "", "", "")
+	assert.ErrorContains(t, err, "is required")
+	_, err = svc.LookupUser(ctx, "admin", "alice@example.com", "alice", "")
+	assert.ErrorContains(t, err, "is required")
+	_, err = svc.LookupUser(ctx, "admin", "nobody@example.com", "", "")
+	assert.ErrorContains(t, err, "not found")
+	assert.Empty(t, repos.audit.entries)
+}
+
+func TestAdminService_Stats(t *testing.T) {
+	repos := newMockRepos("admin", "alice")
+	svc := NewAdminService(repos.accounts, repos.users, &mockStatsRepo{stats: model.UsageStats{Users: 2, Projects: 5, OpenReports: 1}}, repos.audit)
+	stats, err := svc.Stats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), stats.Projects)
+	assert.False(t, stats.GeneratedAt.IsZero())
+}
+
+func TestAdminService_SetUserDisabled(t *testing.T) {
+	repos := newMockRepos("admin", "alice")
+	svc := NewAdminService(repos.accounts, repos.users, &mockStatsRepo{}, repos.audit)
+	ctx := context.Background()
+
+	require.NoError(t, svc.SetUserDisabled(ctx, "admin", "alice", true))
+	assert.True(t, repos.accounts.accounts["alice"].Disabled)
+	require.NoError(t, svc.SetUserDisabled(ctx, "admin", "alice", false))
+	assert.False(t, repos.accounts.accounts["alice"].Disabled)
+	assert.Equal(t, []string{model.AuditActionUserDisable, model.AuditActionUserEnable}, repos.audit.actions())
+
+	assert.ErrorContains(t, svc.SetUserDisabled(ctx, "admin", "admin", true), "cannot disable yourself")
+	assert.ErrorContains(t, svc.SetUserDisabled(ctx, "admin", "ghost", true), "not found")
+}
+
+func TestAdminService_ReleaseUsername(t *testing.T) {
+	repos := newMockRepos("admin", "alice")
+	svc := NewAdminService(repos.accounts, repos.users, &mockStatsRepo{}, repos.audit)
+	ctx := context.Background()
+
+	require.NoError(t, svc.ReleaseUsername(ctx, "admin", "alice"))
+	assert.Empty(t, repos.users.users["alice"].Username)
+	require.Len(t, repos.audit.entries, 1)
+	assert.Equal(t, model.AuditChange{Before: "alice", After: ""}, repos.audit.entries[0].Changes["uid"])
+
+	// The name can be claimed again.
+	repos.users.users["bob"] = &model.User{UID: "bob", Email: "bob@example.com"}
+	require.NoError(t, NewUserService(repos.users, nil).ClaimUsername(ctx, "bob", "alice"))
+
+	assert.ErrorContains(t, svc.ReleaseUsername(ctx, "admin", "nobody"), "not found")
+	assert.ErrorContains(t, svc.ReleaseUsername(ctx, "admin", " "), "is required")
+}
+
+func TestAdminService_ListAuditLog(t *testing.T) {
+	repos := newMockRepos("admin", "alice")
+	svc := NewAdminService(repos.accounts, repos.users, &mockStatsRepo{}, repos.audit)
+	ctx := context.Background()
+	require.NoError(t, svc.SetUserDisabled(ctx, "admin", "alice", true))
+	require.NoError(t, svc.ReleaseUsername(ctx, "admin", "alice"))
+
+	entries, err := svc.ListAuditLog(ctx, "", 10, "")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, model.AuditActionUsernameRelease, entries[0].Action)
+}
+
+func TestRolesFromClaims(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+		want   []string
+	}{
+		{"none", map[string]interface{}{"email": "a@b.com"}, nil},
+		{"roles list", map[string]interface{}{"roles": []interface{}{"admin", "support", 7}}, []string{"admin", "support"}},
+		{"legacy admin flag", map[string]interface{}{"admin": true}, []string{RoleAdmin}},
+		{"both deduped", map[string]interface{}{"roles": []interface{}{"admin"}, "admin": true}, []string{RoleAdmin}},
+		{"wrong type", map[string]interface{}{"roles": "admin"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, rolesFromClaims(tt.claims))
+		})
+	}
+}
+
+func TestUserInfo_HasRole(t *testing.T) {
+	u := &UserInfo{UID: "u1", Roles: []string{"support"}}
+	assert.True(t, u.HasRole("support"))
+	assert.False(t, u.HasRole(RoleAdmin))
+}
+
+func TestAdminService_ListAuditLog_ByActor(t *testing.T) {
+	repos := newMockRepos("admin", "alice")
+	svc := NewAdminService(repos.accounts, repos.users, &mockStatsRepo{}, repos.audit)
+	ctx := context.Background()
+	require.NoError(t, svc.SetUserDisabled(ctx, "admin", "alice", true))
+	require.NoError(t, NewUserService(repos.users, repos.audit).UpdateProfile(ctx, "alice", "alice",
+		&model.UserUpdate{Bio: strPtr("hi")}))
+
+	entries, err := svc.ListAuditLog(ctx, "alice", 10, "")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, model.AuditActionProfileUpdate, entries[0].Action)
+}
+
+// --- Audit logging tests ---
+
+func strPtr(s string) *string { return &s }
+
+func TestRecordAudit_StampsRequestMeta(t *testing.T) {
+	audit := newMockAuditLogger()
+	ctx := WithRequestMeta(context.Background(), RequestMeta{IP: "203.0.113.7", RequestID: "req-1"})
+
+	recordAudit(ctx, audit, &model.AuditEntry{ActorUID: "u1", Action: model.AuditActionNFTCreate})
+
+	require.Len(t, audit.entries, 1)
+	assert.Equal(t, "203.0.113.7", audit.entries[0].IP)
+	assert.Equal(t, "req-1", audit.entries[0].RequestID)
+}
+
+func TestRecordAudit_NilLogger(t *testing.T) {
+	assert.NotPanics(t, func() {
+		recordAudit(context.Background(), nil, &model.AuditEntry{ActorUID: "u1"})
+	})
+}
+
+func TestUserService_AuditsUsernameClaimAndProfileUpdate(t *testing.T) {
+	repo := newMockUserRepo()
+	repo.users["u1"] = &model.User{UID: "u1", Email: "a@b.com", Bio: "old"}
+	audit := newMockAuditLogger()
+	svc := NewUserService(repo, audit)
+	ctx := context.Background()
+
+	require.NoError(t, svc.ClaimUsername(ctx, "u1", "painter"))
+	require.NoError(t, svc.UpdateProfile(ctx, "u1", "u1", &model.UserUpdate{Bio: strPtr("new"), Location: strPtr("")}))
+
+	require.Equal(t, []string{model.AuditActionUsernameClaim, model.AuditActionProfileUpdate}, audit.actions())
+	assert.Equal(t, "painter", audit.entries[0].ResourceID)
+	assert.Equal(t, map[string]model.AuditChange{"bio": {Before: "old", After: "new"}}, audit.entries[1].Changes)
+}
+
+func TestUserService_ProfileUpdate_NoChangeNotAudited(t *testing.T) {
+	repo := newMockUserRepo()
+	repo.users["u1"] = &model.User{UID: "u1", Email: "a@b.com", Bio: "same"}
+	audit := newMockAuditLogger()
+
+	require.NoError(t, NewUserService(repo, audit).UpdateProfile(context.Background(), "u1", "u1",
+		&model.UserUpdate{Bio: strPtr("same")}))
+	assert.Empty(t, audit.entries)
+}
+
+func TestUserService_ListActivity(t *testing.T) {
+	repo := newMockUserRepo()
+	repo.users["u1"] = &model.User{UID: "u1", Email: "a@b.com"}
+	audit := newMockAuditLogger()
+	audit.entries = []*model.AuditEntry{
+		{ID: "a1", ActorUID: "u1", Action: model.AuditActionUsernameClaim},
+		{ID: "a2", ActorUID: "u2", Action: model.AuditActionNFTCreate},
+	}
+
+	entries, err := NewUserService(repo, audit).ListActivity(context.Background(), "u1", 0, "")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "a1", entries[0].ID)
+
+	entries, err = NewUserService(repo, nil).ListActivity(context.Background(), "u1", 10, "")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestProjectService_AuditsVisibilityAndDelete(t *testing.T) {
+	repo := newMockProjectRepo()
+	audit := newMockAuditLogger()
+	svc := NewProjectService(repo, nil, audit, nil)
+	ctx := context.Background()
+	result, err := svc.CreateProject(ctx, "u1", &model.Project{Title: "Sunset"})
+	require.NoError(t, err)
+	id := result.ProjectID
+
+	require.NoError(t, svc.UpdateProject(ctx, "u1", id, &model.ProjectUpdate{Title: strPtr("Dusk")}))
+	assert.Empty(t, audit.entries, "title-only updates are not audited")
+
+	public := true
+	require.NoError(t, svc.UpdateProject(ctx, "u1", id, &model.ProjectUpdate{IsPublic: &public}))
+	require.NoError(t, svc.DeleteProject(ctx, "u1", id))
+
+	require.Equal(t, []string{model.AuditActionProjectVisibility, model.AuditActionProjectDelete}, audit.actions())
+	assert.Equal(t, model.AuditChange{Before: false, After: true}, audit.entries[0].Changes["isPublic"])
+	assert.Equal(t, id, audit.entries[1].ResourceID)
+}
+
+func TestGalleryAndNFTService_AuditCreation(t *testing.T) {
+	audit := newMockAuditLogger()
+	ctx := context.Background()
+
+	itemID, err := NewGalleryService(newMockGalleryRepo(), audit, nil).ShareToGallery(ctx, "u1",
+		&model.GalleryItem{Name: "Art", ImageData: "data:image/png;base64,abc"})
+	require.NoError(t, err)
+	nftID, err := NewNFTService(newMockNFTRepo(), audit, nil).CreateNFT(ctx, "u1",
+		&model.NFT{Name: "Token", ImageData: "data:image/png;base64,abc"})
+	require.NoError(t, err)
+
+	require.Equal(t, []string{model.AuditActionGalleryShare, model.AuditActionNFTCreate}, audit.actions())
+	assert.Equal(t, itemID, audit.entries[0].ResourceID)
+	assert.Equal(t, nftID, audit.entries[1].ResourceID)
+}
+
+// --- APITokenService tests ---
+
+func TestAPITokenService_CreateAndVerify(t *testing.T) {
+	repo := newMockAPITokenRepo()
+	audit := newMockAuditLogger()
+	svc := NewAPITokenService(repo, audit)
+	ctx := context.Background()
+
+	days := 30
+	created, err := svc.CreateToken(ctx, "u1", &model.APITokenCreate{
+		Name:          " CI upload ",
+		Scopes:        []string{"projects:write", "PROJECTS:WRITE", "projects:read"},
+		ExpiresInDays: &days,
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.Token, model.APITokenPrefix))
+	assert.Equal(t, "CI upload", created.Name)
+	assert.Equal(t, []string{"projects:write", "projects:read"}, created.Scopes)
+	assert.True(t, strings.HasPrefix(created.Token, created.Prefix))
+	require.NotNil(t, created.ExpiresAt)
+
+	stored, err := repo.GetByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.NotContains(t, stored.Hash, created.Token)
+	assert.Equal(t, hashAPIToken(created.Token), stored.Hash)
+
+	user, err := svc.VerifyIDToken(ctx, created.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "u1", user.UID)
+	assert.Equal(t, created.ID, user.TokenID)
+	assert.True(t, user.HasScope(model.ScopeProjectsRead))
+	assert.False(t, user.HasScope(model.ScopeGalleryWrite))
+	assert.Equal(t, 1, repo.touched)
+
+	// lastUsedAt is throttled
+	_, err = svc.VerifyIDToken(ctx, created.Token)
+	require.NoError(t, err)
+	assert.Equal(t, 1, repo.touched)
+
+	assert.Equal(t, []string{model.AuditActionTokenCreate}, audit.actions())
+}
+
+func TestAPITokenService_CreateToken_Invalid(t *testing.T) {
+	svc := NewAPITokenService(newMockAPITokenRepo(), nil)
+	ctx := context.Background()
+
+	_, err := svc.CreateToken(ctx, "u1", &model.APITokenCreate{Name: "x", Scopes: []string{"admin"}})
+	assert.ErrorContains(t, err, "invalid scope")
+
+	_, err = svc.CreateToken(ctx, "u1", &model.APITokenCreate{Name: "x"})
+	assert.ErrorContains(t, err, "at least one scope is required")
+
+	days := 0
+	_, err = svc.CreateToken(ctx, "u1", &model.APITokenCreate{Name: "x", Scopes: []string{"gallery:read"}, ExpiresInDays: &days})
+	assert.ErrorContains(t, err, "expiresInDays must be")
+}
+
+func TestAPITokenService_CreateToken_Limit(t *testing.T) {
+	repo := newMockAPITokenRepo()
+	svc := NewAPITokenService(repo, nil)
+	ctx := context.Background()
+
+	for i := 0; i < model.MaxAPITokensPerUser; i++ {
+		_, err := svc.CreateToken(ctx, "u1", &model.APITokenCreate{Name: "t", Scopes: []string{"gallery:read"}})
+		require.NoError(t, err)
+	}
+	_, err := svc.CreateToken(ctx, "u1", &model.APITokenCreate{Name: "t", Scopes: []string{"gallery:read"}})
+	assert.ErrorContains(t, err, "at most")
+
+	// A configured quota replaces the default
+	svc.SetMaxPerUser(1)
+	_, err = svc.CreateToken(ctx, "u2", &model.APITokenCreate{Name: "t", Scopes: []string{"gallery:read"}})
+	require.NoError(t, err)
+	_, err = svc.CreateToken(ctx, "u2", &model.APITokenCreate{Name: "t", Scopes: []string{"gallery:read"}})
+	assert.ErrorContains(t, err, "at most 1 API tokens")
+}
+
+func TestAPITokenService_VerifyIDToken_Rejects(t *testing.T) {
+	repo := newMockAPITokenRepo()
+	svc := NewAPITokenService(repo, nil)
+	ctx := context.Background()
+
+	_, err := svc.VerifyIDToken(ctx, "eyJhbGciOiJSUzI1NiJ9.firebase")
+	assert.ErrorContains(t, err, "not an API token")
+
+	_, err = svc.VerifyIDToken(ctx, model.APITokenPrefix+"unknown")
+	assert.ErrorContains(t, err, "not found")
+
+	created, err := svc.CreateToken(ctx, "u1", &model.APITokenCreate{Name: "t", Scopes: []string{"gallery:read"}})
+	require.NoError(t, err)
+	past := time.Now().Add(-time.Minute)
+	repo.tokens[created.ID].ExpiresAt = &past
+
+	_, err = svc.VerifyIDToken(ctx, created.Token)
+	assert.ErrorContains(t, err, "expired")
+}
+
+func TestAPITokenService_ListAndRevoke(t *testing.T) {
+	repo := newMockAPITokenRepo()
+	audit := newMockAuditLogger()
+	svc := NewAPITokenService(repo, audit)
+	ctx := context.Background()
+
+	tokens, err := svc.ListTokens(ctx, "u1")
+	require.NoError(t, err)
+	assert.NotNil(t, tokens)
+	assert.Empty(t, tokens)
+
+	created, err := svc.CreateToken(ctx, "u1", &model.APITokenCreate{Name: "t", Scopes: []string{"gallery:write"}})
+	require.NoError(t, err)
+
+	err = svc.RevokeToken(ctx, "u2", created.ID)
+	assert.ErrorContains(t, err, "unauthorized")
+
+	require.NoError(t, svc.RevokeToken(ctx, "u1", created.ID))
+	_, err = svc.VerifyIDToken(ctx, created.Token)
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{model.AuditActionTokenCreate, model.AuditActionTokenRevoke}, audit.actions())
+}
+
+func TestUserInfo_HasScope(t *testing.T) {
+	firebase := &UserInfo{UID: "u1"}
+	assert.True(t, firebase.HasScope(model.ScopeGalleryWrite))
+
+	token := &UserInfo{UID: "u1", TokenID: "t1", Scopes: []string{model.ScopeGalleryRead}}
+	assert.True(t, token.HasScope(model.ScopeGalleryRead))
+	assert.False(t, token.HasScope(model.ScopeGalleryWrite))
+}
+
+func TestAuthKeysHealthCheck(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(`{"kid":"-----BEGIN CERTIFICATE-----"}`))
+	}))
+	defer server.Close()
+
+	assert.NoError(t, AuthKeysHealthCheck(context.Background(), server.Client(), server.URL))
+
+	status = http.StatusServiceUnavailable
+	assert.ErrorContains(t, AuthKeysHealthCheck(context.Background(), server.Client(), server.URL), "HTTP 503")
+
+	server.Close()
+	assert.Error(t, AuthKeysHealthCheck(context.Background(), server.Client(), server.URL))
+}
+
+// --- WebhookService tests ---
+
+// webhookReceiver records the requests sent to an httptest server and answers
+// with the queued status codes (200 once the queue is empty).
+type webhookReceiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (rcv *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	rcv.requests = append(rcv.requests, r)
+	rcv.bodies = append(rcv.bodies, body)
+	status := http.StatusOK
+	if len(rcv.statuses) > 0 {
+		status, rcv.statuses = rcv.statuses[0], rcv.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func (rcv *webhookReceiver) count() int {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	return len(rcv.requests)
+}
+
+func newTestWebhookService(repo *mockWebhookRepo) *WebhookService {
+	svc := NewWebhookService(repo, nil, true)
+	svc.backoff = time.Millisecond
+	return svc
+}
+
+func TestWebhookService_CreateListDelete(t *testing.T) {
+	repo := newMockWebhookRepo()
+	audit := newMockAuditLogger()
+	svc := NewWebhookService(repo, audit, true)
+	ctx := context.Background()
+
+	webhooks, err := svc.ListWebhooks(ctx, "u1")
+	require.NoError(t, err)
+	assert.NotNil(t, webhooks)
+	assert.Empty(t, webhooks)
+
+	created, err := svc.CreateWebhook(ctx, "u1", &model.WebhookCreate{
+		URL:    " http://localhost:9000/hook ",
+		Events: []string{"Project.Created", "project.created", "nft.created"},
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.Secret, model.WebhookSecretPrefix))
+	assert.Equal(t, "http://localhost:9000/hook", created.URL)
+	assert.Equal(t, []string{"project.created", "nft.created"}, created.Events)
+
+	webhooks, err = svc.ListWebhooks(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, webhooks, 1)
+
+	err = svc.DeleteWebhook(ctx, "u2", created.ID)
+	assert.ErrorContains(t, err, "unauthorized")
+
+	require.NoError(t, svc.DeleteWebhook(ctx, "u1", created.ID))
+	assert.Empty(t, repo.webhooks)
+
+	assert.Equal(t, []string{model.AuditActionWebhookCreate, model.AuditActionWebhookDelete}, audit.actions())
+}
+
+func TestWebhookService_CreateWebhook_Invalid(t *testing.T) {
+	svc := NewWebhookService(newMockWebhookRepo(), nil, false)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		req  model.WebhookCreate
+		want string
+	}{
+		{"no events", model.WebhookCreate{URL: "https://example.com/hook"}, "at least one event"},
+		{"unknown event", model.WebhookCreate{URL: "https://example.com/hook", Events: []string{"user.deleted"}}, "invalid event"},
+		{"ping event", model.WebhookCreate{URL: "https://example.com/hook", Events: []string{"ping"}}, "invalid event"},
+		{"plain http", model.WebhookCreate{URL: "http://example.com/hook", Events: []string{"nft.created"}}, "https"},
+		{"localhost", model.WebhookCreate{URL: "https://localhost/hook", Events: []string{"nft.created"}}, "publicly reachable"},
+		{"private ip", model.WebhookCreate{URL: "https://10.0.0.5/hook", Events: []string{"nft.created"}}, "publicly reachable"},
+		{"metadata ip", model.WebhookCreate{URL: "https://169.254.169.254/", Events: []string{"nft.created"}}, "publicly reachable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateWebhook(ctx, "u1", &tt.req)
+			assert.ErrorContains(t, err, "validation")
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+
+	_, err := svc.CreateWebhook(ctx, "u1", &model.WebhookCreate{URL: "https://example.com/hook", Events: []string{"nft.created"}})
+	assert.NoError(t, err)
+}
+
+func TestWebhookService_CreateWebhook_Limit(t *testing.T) {
+	svc := NewWebhookService(newMockWebhookRepo(), nil, true)
+	ctx := context.Background()
+
+	for i := 0; i < model.MaxWebhooksPerUser; i++ {
+		_, err := svc.CreateWebhook(ctx, "u1", &model.WebhookCreate{URL: "http://localhost/hook", Events: []string{"nft.created"}})
+		require.NoError(t, err)
+	}
+	_, err := svc.CreateWebhook(ctx, "u1", &model.WebhookCreate{URL: "http://localhost/hook", Events: []string{"nft.created"}})
+	assert.ErrorContains(t, err, "at most")
+
+	// A configured quota replaces the default
+	svc.SetMaxPerUser(1)
+	_, err = svc.CreateWebhook(ctx, "u2", &model.WebhookCreate{URL: "http://localhost/hook", Events: []string{"nft.created"}})
+	require.NoError(t, err)
+	_, err = svc.CreateWebhook(ctx, "u2", &model.WebhookCreate{URL: "http://localhost/hook", Events: []string{"nft.created"}})
+	assert.ErrorContains(t, err, "at most 1 webhooks")
+}
+
+func TestWebhookService_Publish_SignsAndFilters(t *testing.T) {
+	rcv := &webhookReceiver{}
+	server := httptest.NewServer(rcv)
+	defer server.Close()
+
+	repo := newMockWebhookRepo()
+	svc := newTestWebhookService(repo)
+	ctx := context.Background()
+
+	subscribed, err := svc.CreateWebhook(ctx, "u1", &model.WebhookCreate{URL: server.URL, Events: []string{"project.created"}})
+	require.NoError(t, err)
+	_, err = svc.CreateWebhook(ctx, "u1", &model.WebhookCreate{URL: server.URL + "/other", Events: []string{"nft.created"}})
+	require.NoError(t, err)
+	_, err = svc.CreateWebhook(ctx, "u2", &model.WebhookCreate{URL: server.URL + "/u2", Events: []string{"project.created"}})
+	require.NoError(t, err)
+
+	svc.Publish(ctx, "u1", model.WebhookEventProjectCreated, map[string]interface{}{"projectId": "p1"})
+	require.NoError(t, svc.Wait(ctx))
+
+	require.Equal(t, 1, rcv.count())
+	req, body := rcv.requests[0], rcv.bodies[0]
+	assert.Equal(t, "/", req.URL.Path)
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	assert.Equal(t, model.WebhookEventProjectCreated, req.Header.Get(WebhookEventHeader))
+
+	ts, err := strconv.ParseInt(req.Header.Get(WebhookTimestampHeader), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, SignWebhookPayload(subscribed.Secret, ts, body), req.Header.Get(WebhookSignatureHeader))
+
+	var payload model.WebhookPayload
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, req.Header.Get(WebhookDeliveryHeader), payload.ID)
+	assert.Equal(t, "p1", payload.Data.(map[string]interface{})["projectId"])
+
+	deliveries, err := svc.ListDeliveries(ctx, "u1", subscribed.ID, 0, "")
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.True(t, deliveries[0].Success)
+	assert.Equal(t, http.StatusOK, deliveries[0].StatusCode)
+}
+
+func TestWebhookService_Publish_RetriesWithBackoff(t *testing.T) {
+	rcv := &webhookReceiver{statuses: []int{http.StatusBadGateway, http.StatusTooManyRequests}}
+	server := httptest.NewServer(rcv)
+	defer server.Close()
+
+	repo := newMockWebhookRepo()
+	svc := newTestWebhookService(repo)
+	ctx := context.Background()
+
+	created, err := svc.CreateWebhook(ctx, "u1", &model.WebhookCreate{URL: server.URL, Events: []string{"gallery.shared"}})
+	require.NoError(t, err)
+
+	svc.Publish(ctx, "u1", model.WebhookEventGalleryShared, nil)
+	require.NoError(t, svc.Wait(ctx))
+
+	require.Equal(t, 3, rcv.count())
+	ids := map[string]bool{}
+	for _, r := range rcv.requests {
+		ids[r.Header.Get(WebhookDeliveryHeader)] = true
+	}
+	assert.Len(t, ids, 1, "retries reuse the delivery ID")
+
+	deliveries, err := svc.ListDeliveries(ctx, "u1", created.ID, 0, "")
+	require.NoError(t, err)
+	require.Len(t, deliveries, 3)
+	assert.Equal(t, 3, deliveries[0].Attempt)
+	assert.True(t, deliveries[0].Success)
+	assert.Equal(t, http.StatusBadGateway, deliveries[2].StatusCode)
+	assert.False(t, deliveries[2].Success)
+}
+
+func TestWebhookService_Publish_GivesUp(t *testing.T) {
+	rcv := &webhookReceiver{statuses: []int{500, 500, 500, 500, 500, 500}}
+	server := httptest.NewServer(rcv)
+	defer server.Close()
+
+	svc := newTestWebhookService(newMockWebhookRepo())
+	ctx := context.Background()
+
+	_, err := svc.CreateWebhook(ctx, "u1", &model.WebhookCreate{URL: server.URL, Events: []string{"nft.created"}})
+	require.NoError(t, err)
+
+	svc.Publish(ctx, "u1", model.WebhookEventNFTCreated, nil)
+	require.NoError(t, svc.Wait(ctx))
+	assert.Equal(t, webhookMaxAttempts, rcv.count())
+}
+
+func TestWebhookService_Publish_NoRetryOnClientError(t *testing.T) {
+	rcv := &webhookReceiver{statuses: []int{http.StatusGone}}
+	server := httptest.NewServer(rcv)
+	defer server.Close()
+
+	svc := newTestWebhookService(newMockWebhookRepo())
+	ctx := context.Background()
+
+	_, err := svc.CreateWebhook(ctx, "u1", &model.WebhookCreate{URL: server.URL, Events: []string{"nft.created"}})
+	require.NoError(t, err)
+
+	svc.Publish(ctx, "u1", model.WebhookEventNFTCreated, nil)
+	require.NoError(t, svc.Wait(ctx))
+	assert.Equal(t, 1, rcv.count())
+}
+
+func TestWebhookService_PingWebhook(t *testing.T) {
+	rcv := &webhookReceiver{statuses: []int{http.StatusServiceUnavailable}}
+	server := httptest.NewServer(rcv)
+	defer server.Close()
+
+	svc := newTestWebhookService(newMockWebhookRepo())
+	ctx := context.Background()
+
+	created, err := svc.CreateWebhook(ctx, "u1", &model.WebhookCreate{URL: server.URL, Events: []string{"nft.created"}})
+	require.NoError(t, err)
+
+	_, err = svc.PingWebhook(ctx, "u2", created.ID)
+	assert.ErrorContains(t, err, "unauthorized")
+
+	delivery, err := svc.PingWebhook(ctx, "u1", created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.WebhookEventPing, delivery.Event)
+	assert.Equal(t, http.StatusServiceUnavailable, delivery.StatusCode)
+	assert.False(t, delivery.Success)
+	assert.Equal(t, 1, rcv.count(), "pings are not retried")
+
+	_, err = svc.ListDeliveries(ctx, "u2", created.ID, 0, "")
+	assert.ErrorContains(t, err, "unauthorized")
+}
+
+func TestWebhookService_BlocksPrivateDestinations(t *testing.T) {
+	rcv := &webhookReceiver{}
+	server := httptest.NewServer(rcv)
+	defer server.Close()
+
+	repo := newMockWebhookRepo()
+	svc := NewWebhookService(repo, nil, false)
+	ctx := context.Background()
+
+	// Stored directly to simulate a hostname that passed validation but
+	// resolves to loopback at delivery time.
+	id, err := repo.Create(ctx, &model.Webhook{UID: "u1", URL: server.URL, Events: []string{"nft.created"}, Secret: "whsec_x"})
+	require.NoError(t, err)
+
+	delivery, err := svc.PingWebhook(ctx, "u1", id)
+	require.NoError(t, err)
+	assert.False(t, delivery.Success)
+	assert.Contains(t, delivery.Error, "not a public address")
+	assert.Zero(t, rcv.count())
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	sig := SignWebhookPayload("whsec_test", 1700000000, []byte(`{"event":"ping"}`))
+	assert.Equal(t, "sha256=", sig[:7])
+	assert.Len(t, sig, 7+64)
+	assert.Equal(t, sig, SignWebhookPayload("whsec_test", 1700000000, []byte(`{"event":"ping"}`)))
+	assert.NotEqual(t, sig, SignWebhookPayload("whsec_test", 1700000001, []byte(`{"event":"ping"}`)))
+	assert.NotEqual(t, sig, SignWebhookPayload("whsec_other", 1700000000, []byte(`{"event":"ping"}`)))
+}
+
+func TestServices_PublishWebhookEvents(t *testing.T) {
+	events := &mockEventPublisher{}
+	ctx := context.Background()
+
+	projects := NewProjectService(newMockProjectRepo(), nil, nil, events)
+	result, err := projects.CreateProject(ctx, "u1", &model.Project{Title: "Art"})
+	require.NoError(t, err)
+	_, err = projects.CreateProject(ctx, "u1", &model.Project{Title: "Art"}) // title upsert
+	require.NoError(t, err)
+	title := "Renamed"
+	require.NoError(t, projects.UpdateProject(ctx, "u1", result.ProjectID, &model.ProjectUpdate{Title: &title}))
+	require.NoError(t, projects.DeleteProject(ctx, "u1", result.ProjectID))
+
+	gallery := NewGalleryService(newMockGalleryRepo(), nil, events)
+	_, err = gallery.ShareToGallery(ctx, "u1", &model.GalleryItem{Name: "Sunset"})
+	require.NoError(t, err)
+
+	nfts := NewNFTService(newMockNFTRepo(), nil, events)
+	_, err = nfts.CreateNFT(ctx, "u1", &model.NFT{Name: "CoolNFT"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		model.WebhookEventProjectCreated,
+		model.WebhookEventProjectUpdated,
+		model.WebhookEventProjectUpdated,
+		model.WebhookEventProjectDeleted,
+		model.WebhookEventGalleryShared,
+		model.WebhookEventNFTCreated,
+	}, events.names())
+	assert.Equal(t, result.ProjectID, events.events[0].data["projectId"])
+	for _, e := range events.events {
+		assert.Equal(t, "u1", e.uid)
+	}
+}
+
+// --- UploadService tests ---
+
+// uploadTestBlob returns a PNG-prefixed blob that spans two chunks, and its
+// SHA-256 hex digest.
+func uploadTestBlob() ([]byte, string) {
+	blob := append(validPNG(), bytes.Repeat([]byte("x"), model.MinUploadChunkSize+1000)...)
+	sum := sha256.Sum256(blob)
+	return blob, hex.EncodeToString(sum[:])
+}
+
+type uploadTestEnv struct {
+	svc      *UploadService
+	projects *mockProjectRepo
+	sessions *mockUploadSessionRepo
+	storage  *memoryStorageClient
+	runner   *jobs.Runner
+	project  string
+}
+
+func newUploadTestEnv(t *testing.T, contentHash string) *uploadTestEnv {
+	t.Helper()
+	env := &uploadTestEnv{
+		projects: newMockProjectRepo(),
+		sessions: newMockUploadSessionRepo(),
+		storage:  newMemoryStorageClient(),
+		runner:   jobs.NewRunner(jobs.NewMemoryStore(), jobs.Config{PollInterval: 10 * time.Millisecond}),
+	}
+	env.svc = NewUploadService(env.projects, env.sessions, env.storage, env.runner)
+	id, err := env.projects.Create(context.Background(), &model.Project{UserID: "user1", Title: "Big", ContentHash: contentHash})
+	require.NoError(t, err)
+	env.project = id
+	return env
+}
+
+// writeAll uploads blob in two chunks split at MinUploadChunkSize.
+func (env *uploadTestEnv) writeAll(t *testing.T, uploadID string, blob []byte) {
+	t.Helper()
+	ctx := context.Background()
+	split := int64(model.MinUploadChunkSize)
+	total := int64(len(blob))
+	_, err := env.svc.WriteChunk(ctx, "user1", env.project, uploadID, 0, split, total, bytes.NewReader(blob[:split]))
+	require.NoError(t, err)
+	_, err = env.svc.WriteChunk(ctx, "user1", env.project, uploadID, split, total-split, total, bytes.NewReader(blob[split:]))
+	require.NoError(t, err)
+}
+
+func TestUploadService_ChunkedUploadAndFinalize(t *testing.T) {
+	blob, hash := uploadTestBlob()
+	env := newUploadTestEnv(t, hash)
+	ctx := context.Background()
+
+	session, err := env.svc.InitiateUpload(ctx, "user1", env.project, &model.UploadInit{Size: int64(len(blob)), ContentHash: hash})
+	require.NoError(t, err)
+	assert.Equal(t, model.UploadStatusOpen, session.Status)
+	assert.WithinDuration(t, time.Now().Add(model.UploadSessionTTL), session.ExpiresAt, time.Minute)
+
+	env.writeAll(t, session.ID, blob)
+
+	got, err := env.svc.GetUpload(ctx, "user1", env.project, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(blob)), got.Offset)
+
+	finalizing, err := env.svc.FinalizeUpload(ctx, "user1", env.project, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.UploadStatusFinalizing, finalizing.Status)
+	require.NotEmpty(t, finalizing.JobID)
+
+	// Finalizing again returns the same job
+	again, err := env.svc.FinalizeUpload(ctx, "user1", env.project, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, finalizing.JobID, again.JobID)
+
+	job, err := env.runner.Get(ctx, finalizing.JobID)
+	require.NoError(t, err)
+	assert.Equal(t, "user1", job.UID)
+	result, err := env.svc.finalizeJob(ctx, job)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(blob)), result["size"])
+
+	objectPath := "projects/user1/" + hash + ".png"
+	assert.Equal(t, []string{objectPath}, env.storage.paths(), "chunks should be deleted")
+	stored, _ := env.storage.ReadObject(ctx, objectPath)
+	assembled, _ := io.ReadAll(stored)
+	assert.Equal(t, blob, assembled)
+
+	project, _ := env.projects.GetByID(ctx, env.project)
+	assert.Contains(t, project.StorageURL, "alt=media")
+	assert.Equal(t, 0, env.sessions.count())
+}
+
+func TestUploadService_FinalizeRunsOnRunner(t *testing.T) {
+	blob, hash := uploadTestBlob()
+	env := newUploadTestEnv(t, hash)
+	ctx := context.Background()
+
+	env.runner.Start()
+	t.Cleanup(func() { _ = env.runner.Shutdown(context.Background()) })
+
+	session, err := env.svc.InitiateUpload(ctx, "user1", env.project, &model.UploadInit{Size: int64(len(blob)), ContentHash: hash})
+	require.NoError(t, err)
+	env.writeAll(t, session.ID, blob)
+	finalizing, err := env.svc.FinalizeUpload(ctx, "user1", env.project, session.ID)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		job, err := env.runner.Get(ctx, finalizing.JobID)
+		return err == nil && job.Status == jobs.StatusSucceeded
+	}, 5*time.Second, 10*time.Millisecond)
+
+	project, _ := env.projects.GetByID(ctx, env.project)
+	assert.NotEmpty(t, project.StorageURL)
+}
+
+func TestUploadService_InitiateUpload_Errors(t *testing.T) {
+	blob, hash := uploadTestBlob()
+	env := newUploadTestEnv(t, hash)
+	ctx := context.Background()
+
+	_, err := env.svc.InitiateUpload(ctx, "user1", env.project, &model.UploadInit{Size: 0, ContentHash: hash})
+	assert.ErrorContains(t, err, "validation")
+
+	_, err = env.svc.InitiateUpload(ctx, "user1", env.project, &model.UploadInit{Size: model.MaxResumableUploadSize + 1, ContentHash: hash})
+	assert.ErrorContains(t, err, "validation")
+
+	other := strings.Repeat("b", 64)
+	_, err = env.svc.InitiateUpload(ctx, "user1", env.project, &model.UploadInit{Size: int64(len(blob)), ContentHash: other})
+	assert.ErrorContains(t, err, "does not match")
+
+	_, err = env.svc.InitiateUpload(ctx, "attacker", env.project, &model.UploadInit{Size: int64(len(blob)), ContentHash: hash})
+	assert.ErrorContains(t, err, "unauthorized")
+
+	assert.Equal(t, 0, env.sessions.count())
+}
+
+func TestUploadService_WriteChunk_Errors(t *testing.T) {
+	blob, hash := uploadTestBlob()
+	env := newUploadTestEnv(t, hash)
+	ctx := context.Background()
+	total := int64(len(blob))
+
+	session, err := env.svc.InitiateUpload(ctx, "user1", env.project, &model.UploadInit{Size: total, ContentHash: hash})
+	require.NoError(t, err)
+	id := session.ID
+
+	tests := []struct {
+		name             string
+		uid              string
+		start, size, tot int64
+		body             []byte
+		want             string
+	}{
+		{"other user", "attacker", 0, 1024, total, blob[:1024], "unauthorized"},
+		{"wrong total", "user1", 0, 1024, total + 1, blob[:1024], "total size must be"},
+		{"wrong offset", "user1", 1024, 1024, total, blob[1024:2048], "upload offset is 0"},
+		{"too small", "user1", 0, 1024, total, blob[:1024], "at least"},
+		{"past end", "user1", 0, total + 1, total, blob, "past the declared size"},
+		{"not png", "user1", 0, model.MinUploadChunkSize, total, bytes.Repeat([]byte("x"), model.MinUploadChunkSize), "not a valid PNG"},
+		{"short body", "user1", 0, model.MinUploadChunkSize, total, blob[:1024], "received 1024 bytes"},
+		{"long body", "user1", 0, model.MinUploadChunkSize, total, blob, "received"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := env.svc.WriteChunk(ctx, tt.uid, env.project, id, tt.start, tt.size, tt.tot, bytes.NewReader(tt.body))
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+
+	got, _ := env.svc.GetUpload(ctx, "user1", env.project, id)
+	assert.Equal(t, int64(0), got.Offset)
+	assert.Empty(t, env.storage.paths(), "rejected chunks should not be kept")
+}
+
+func TestUploadService_FinalizeIncomplete(t *testing.T) {
+	blob, hash := uploadTestBlob()
+	env := newUploadTestEnv(t, hash)
+	ctx := context.Background()
+	total := int64(len(blob))
+
+	session, _ := env.svc.InitiateUpload(ctx, "user1", env.project, &model.UploadInit{Size: total, ContentHash: hash})
+	_, err := env.svc.WriteChunk(ctx, "user1", env.project, session.ID, 0, model.MinUploadChunkSize, total, bytes.NewReader(blob[:model.MinUploadChunkSize]))
+	require.NoError(t, err)
+
+	_, err = env.svc.FinalizeUpload(ctx, "user1", env.project, session.ID)
+	assert.ErrorContains(t, err, fmt.Sprintf("received %d of %d bytes", model.MinUploadChunkSize, total))
+}
+
+func TestUploadService_FinalizeHashMismatch(t *testing.T) {
+	blob, hash := uploadTestBlob()
+	env := newUploadTestEnv(t, hash)
+	ctx := context.Background()
+
+	session, _ := env.svc.InitiateUpload(ctx, "user1", env.project, &model.UploadInit{Size: int64(len(blob)), ContentHash: hash})
+
+	tampered := append([]byte(nil), blob...)
+	tampered[len(tampered)-1] = 'y'
+	env.writeAll(t, session.ID, tampered)
+
+	finalizing, err := env.svc.FinalizeUpload(ctx, "user1", env.project, session.ID)
+	require.NoError(t, err)
+	job, _ := env.runner.Get(ctx, finalizing.JobID)
+	_, err = env.svc.finalizeJob(ctx, job)
+	assert.ErrorContains(t, err, "content hash mismatch")
+	assert.True(t, jobs.IsPermanent(err))
+
+	assert.Empty(t, env.storage.paths())
+	assert.Equal(t, 0, env.sessions.count())
+	project, _ := env.projects.GetByID(ctx, env.project)
+	assert.Empty(t, project.StorageURL)
+}
+
+func TestUploadService_Expire(t *testing.T) {
+	blob, hash := uploadTestBlob()
+	env := newUploadTestEnv(t, hash)
+	ctx := context.Background()
+	total := int64(len(blob))
+
+	open, _ := env.svc.InitiateUpload(ctx, "user1", env.project, &model.UploadInit{Size: total, ContentHash: hash})
+	_, err := env.svc.WriteChunk(ctx, "user1", env.project, open.ID, 0, model.MinUploadChunkSize, total, bytes.NewReader(blob[:model.MinUploadChunkSize]))
+	require.NoError(t, err)
+
+	done, _ := env.svc.InitiateUpload(ctx, "user1", env.project, &model.UploadInit{Size: total, ContentHash: hash})
+	env.writeAll(t, done.ID, blob)
+	_, err = env.svc.FinalizeUpload(ctx, "user1", env.project, done.ID)
+	require.NoError(t, err)
+
+	expire := func(uploadID string) map[string]interface{} {
+		result, err := env.svc.expireJob(ctx, &jobs.Job{Type: JobTypeUploadExpire, Payload: map[string]interface{}{"uploadId": uploadID}})
+		require.NoError(t, err)
+		return result
+	}
+
+	assert.Equal(t, true, expire(open.ID)["expired"])
+	_, err = env.sessions.GetByID(ctx, open.ID)
+	assert.ErrorContains(t, err, "not found")
+
+	// A finalizing session is left to its finalize job
+	assert.Equal(t, false, expire(done.ID)["expired"])
+	assert.Equal(t, 1, env.sessions.count())
+	assert.Len(t, env.storage.paths(), 2)
+
+	// Already removed
+	assert.Equal(t, false, expire(open.ID)["expired"])
+}
+
+func TestUploadService_ExpiredSessionRejected(t *testing.T) {
+	blob, hash := uploadTestBlob()
+	env := newUploadTestEnv(t, hash)
+	ctx := context.Background()
+
+	session, _ := env.svc.InitiateUpload(ctx, "user1", env.project, &model.UploadInit{Size: int64(len(blob)), ContentHash: hash})
+	env.sessions.mu.Lock()
+	env.sessions.sessions[session.ID].ExpiresAt = time.Now().Add(-time.Minute)
+	env.sessions.mu.Unlock()
+
+	_, err := env.svc.WriteChunk(ctx, "user1", env.project, session.ID, 0, model.MinUploadChunkSize, int64(len(blob)), bytes.NewReader(blob[:model.MinUploadChunkSize]))
+	assert.ErrorContains(t, err, "not found")
+}
+
+// --- CursorCodec Tests ---
+
+func TestCursorCodec_RoundTrip(t *testing.T) {
+	codec := NewCursorCodec([]byte("0123456789abcdef0123456789abcdef"))
+	c := &model.Cursor{Sort: "title", Order: model.SortAsc, Filter: "abc", Value: "alpha", ID: "p1"}
+
+	token := codec.Encode(c)
+	assert.NotContains(t, token, "=", "tokens are URL-safe without padding")
+	got, err := codec.Decode(token)
+	require.NoError(t, err)
+	assert.Equal(t, c, got)
+}
+
+func TestCursorCodec_RejectsTampering(t *testing.T) {
+	codec := NewCursorCodec([]byte("0123456789abcdef0123456789abcdef"))
+	token := codec.Encode(&model.Cursor{Sort: "title", Order: model.SortAsc, Value: "alpha", ID: "p1"})
+	body, sig, _ := strings.Cut(token, ".")
+
+	forged, _ := json.Marshal(&model.Cursor{Sort: "title", Order: model.SortAsc, Value: "zulu", ID: "p9"})
+	other := NewCursorCodec([]byte("fedcba9876543210fedcba9876543210"))
+
+	for name, tok := range map[string]string{
+		"edited payload":  base64.RawURLEncoding.EncodeToString(forged) + "." + sig,
+		"no signature":    body,
+		"bad signature":   body + ".AAAA",
+		"bad base64":      "!!!." + sig,
+		"other key":       other.Encode(&model.Cursor{Sort: "title", Order: model.SortAsc, Value: "alpha", ID: "p1"}),
+		"raw document ID": "p1",
+	} {
+		_, err := codec.Decode(tok)
+		assert.EqualError(t, err, "invalid cursor", name)
+	}
+}
+
+func TestCursorCodec_EmptySecretIsProcessLocal(t *testing.T) {
+	a, b := NewCursorCodec(nil), NewCursorCodec(nil)
+	token := a.Encode(&model.Cursor{Sort: "createdAt", Order: model.SortDesc, Value: "2026-01-01T00:00:00Z", ID: "p1"})
+
+	_, err := a.Decode(token)
+	assert.NoError(t, err)
+	_, err = b.Decode(token)
+	assert.Error(t, err)
+}
+
+// --- StatsService tests ---
+
+func TestStatsService_GetStats(t *testing.T) {
+	users := newMockUserRepo()
+	users.users["alice"] = &model.User{UID: "alice", ProjectCount: 3, GalleryCount: 2, NFTCount: 1, FollowerCount: 5, FollowingCount: 4}
+	svc := NewStatsService(users, &mockStatsRepo{})
+	ctx := context.Background()
+
+	stats, err := svc.GetStats(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, &model.UserStats{Projects: 3, GalleryItems: 2, NFTs: 1, Followers: 5, Following: 4}, stats)
+
+	// No user document yet means nothing has been counted
+	stats, err = svc.GetStats(ctx, "newcomer")
+	require.NoError(t, err)
+	assert.Equal(t, &model.UserStats{}, stats)
+
+	_, err = svc.GetStats(ctx, "")
+	assert.ErrorContains(t, err, "uid is required")
+}
+
+func TestStatsService_RepairAllCounters(t *testing.T) {
+	stats := &mockStatsRepo{stored: map[string]model.UserStats{}, actual: map[string]model.UserStats{}}
+	// More users than a page, with every tenth one drifted
+	for i := 0; i < MaxPageSize+5; i++ {
+		uid := fmt.Sprintf("user%03d", i)
+		stats.stored[uid] = model.UserStats{Projects: 2}
+		stats.actual[uid] = model.UserStats{Projects: 2}
+		if i%10 == 0 {
+			stats.actual[uid] = model.UserStats{Projects: 3, Followers: 1}
+		}
+	}
+	svc := NewStatsService(newMockUserRepo(), stats)
+	ctx := context.Background()
+
+	var drifted []string
+	report := func(r *model.CounterRepair) {
+		if r.Drifted() {
+			drifted = append(drifted, r.UID)
+		}
+	}
+
+	checked, err := svc.RepairAllCounters(ctx, true, report)
+	require.NoError(t, err)
+	assert.Equal(t, MaxPageSize+5, checked)
+	assert.Equal(t, []string{"user000", "user010", "user020", "user030", "user040", "user050"}, drifted)
+	assert.Empty(t, stats.applied, "a dry run stores nothing")
+
+	drifted = nil
+	_, err = svc.RepairAllCounters(ctx, false, report)
+	require.NoError(t, err)
+	assert.Len(t, drifted, 6)
+	assert.Equal(t, drifted, stats.applied)
+	assert.Equal(t, model.UserStats{Projects: 3, Followers: 1}, stats.stored["user050"])
+
+	// Once repaired, nothing has drifted
+	drifted = nil
+	_, err = svc.RepairAllCounters(ctx, false, report)
+	require.NoError(t, err)
+	assert.Empty(t, drifted)
+
+	_, err = svc.RepairCounters(ctx, "", false)
+	assert.ErrorContains(t, err, "uid is required")
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"github.com/pandasWhoCode/paintbar/internal/repository"
+)
+
+// StatsService serves a user's counts from the counters on their user
+// document, which the repositories maintain as documents are created and
+// deleted, and repairs counters that have drifted from the documents.
+type StatsService struct {
+	users repository.UserRepository
+	stats repository.StatsRepository
+}
+
+// NewStatsService creates a new StatsService.
+func NewStatsService(users repository.UserRepository, stats repository.StatsRepository) *StatsService {
+	return &StatsService{users: users, stats: stats}
+}
+
+// GetStats returns the user's project, gallery, NFT and follow counts in
+// one document read. A user with no user document has nothing counted yet.
+func (s *StatsService) GetStats(ctx context.Context, uid string) (*model.UserStats, error) {
+	if uid == "" {
+		return nil, fmt.Errorf("uid is required")
+	}
+
+	user, err := s.users.GetByID(ctx, uid)
+	if err != nil {
+		if repository.IsNotFoundError(err) {
+			return &model.UserStats{}, nil
+		}
+		return nil, fmt.Errorf("get stats: %w", err)
+	}
+	return user.Stats(), nil
+}
+
+// RepairCounters recomputes the user's counters from count aggregations
+// and stores them if they drifted, unless dryRun is set.
+func (s *StatsService) RepairCounters(ctx context.Context, uid string, dryRun bool) (*model.CounterRepair, error) {
+	if uid == "" {
+		return nil, fmt.Errorf("uid is required")
+	}
+	return s.stats.RecountUser(ctx, uid, !dryRun)
+}
+
+// RepairAllCounters runs RepairCounters for every user document, a page
+// at a time, calling report with each result. It returns the number of
+// users checked.
+func (s *StatsService) RepairAllCounters(ctx context.Context, dryRun bool, report func(*model.CounterRepair)) (int, error) {
+	checked := 0
+	after := ""
+	for {
+		uids, err := s.stats.ListUserIDs(ctx, MaxPageSize, after)
+		if err != nil {
+			return checked, err
+		}
+		for _, uid := range uids {
+			repair, err := s.RepairCounters(ctx, uid, dryRun)
+			if err != nil {
+				return checked, err
+			}
+			checked++
+			report(repair)
+		}
+		if len(uids) < MaxPageSize {
+			return checked, nil
+		}
+		after = uids[len(uids)-1]
+	}
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"github.com/pandasWhoCode/paintbar/internal/repository"
+)
+
+// apiTokenLastUsedInterval throttles lastUsedAt writes so a busy script does
+// not turn every request into a Firestore write.
+const apiTokenLastUsedInterval = time.Hour
+
+// APITokenService manages personal access tokens and verifies them as
+// bearer credentials. It implements middleware.TokenVerifier.
+type APITokenService struct {
+	repo       repository.APITokenRepository
+	audit      repository.AuditLogger
+	maxPerUser int
+}
+
+// NewAPITokenService creates a new APITokenService.
+// audit may be nil to disable audit logging.
+func NewAPITokenService(repo repository.APITokenRepository, audit repository.AuditLogger) *APITokenService {
+	return &APITokenService{repo: repo, audit: audit, maxPerUser: model.MaxAPITokensPerUser}
+}
+
+// SetMaxPerUser caps how many tokens one user may hold
+// (model.MaxAPITokensPerUser by default).
+func (s *APITokenService) SetMaxPerUser(n int) {
+	s.maxPerUser = n
+}
+
+// CreateToken mints a new token for uid. The plaintext secret is returned
+// once and never stored.
+func (s *APITokenService) CreateToken(ctx context.Context, uid string, req *model.APITokenCreate) (*model.APITokenCreated, error) {
+	req.Sanitize()
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation: %w", err)
+	}
+
+	existing, err := s.repo.ListByUser(ctx, uid)
+	if err != nil {
+		return nil, fmt.Errorf("list api tokens: %w", err)
+	}
+	if len(existing) >= s.maxPerUser {
+		return nil, fmt.Errorf("invalid request: a user may have at most %d API tokens", s.maxPerUser)
+	}
+
+	secret, err := newAPITokenSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	token := &model.APIToken{
+		UID:    uid,
+		Name:   req.Name,
+		Prefix: secret[:len(model.APITokenPrefix)+8],
+		Hash:   hashAPIToken(secret),
+		Scopes: req.Scopes,
+	}
+	if req.ExpiresInDays != nil {
+		expires := time.Now().Add(time.Duration(*req.ExpiresInDays) * 24 * time.Hour)
+		token.ExpiresAt = &expires
+	}
+
+	id, err := s.repo.Create(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	token.ID = id
+
+	recordAudit(ctx, s.audit, &model.AuditEntry{
+		ActorUID:     uid,
+		Action:       model.AuditActionTokenCreate,
+		ResourceType: model.AuditResourceToken,
+		ResourceID:   id,
+		Details:      map[string]interface{}{"name": token.Name, "scopes": token.Scopes},
+	})
+	return &model.APITokenCreated{APIToken: token, Token: secret}, nil
+}
+
+// ListTokens returns the user's tokens, newest first, without secrets.
+func (s *APITokenService) ListTokens(ctx context.Context, uid string) ([]*model.APIToken, error) {
+	tokens, err := s.repo.ListByUser(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if tokens == nil {
+		tokens = []*model.APIToken{}
+	}
+	return tokens, nil
+}
+
+// RevokeToken deletes one of the user's tokens.
+func (s *APITokenService) RevokeToken(ctx context.Context, uid, tokenID string) error {
+	if tokenID == "" {
+		return fmt.Errorf("token ID is required")
+	}
+
+	token, err := s.repo.GetByID(ctx, tokenID)
+	if err != nil {
+		return fmt.Errorf("get api token for revoke: %w", err)
+	}
+	if token.UID != uid {
+		return fmt.Errorf("unauthorized: cannot revoke another user's token")
+	}
+
+	if err := s.repo.Delete(ctx, tokenID); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.audit, &model.AuditEntry{
+		ActorUID:     uid,
+		Action:       model.AuditActionTokenRevoke,
+		ResourceType: model.AuditResourceToken,
+		ResourceID:   tokenID,
+		Details:      map[string]interface{}{"name": token.Name},
+	})
+	return nil
+}
+
+// VerifyIDToken verifies a personal access token presented as a bearer
+// credential. Credentials without the token prefix are rejected without a
+// lookup so the next verifier in the chain can try them.
+func (s *APITokenService) VerifyIDToken(ctx context.Context, raw string) (*UserInfo, error) {
+	if !strings.HasPrefix(raw, model.APITokenPrefix) {
+		return nil, fmt.Errorf("not an API token")
+	}
+
+	token, err := s.repo.GetByHash(ctx, hashAPIToken(raw))
+	if err != nil {
+		return nil, fmt.Errorf("verify api token: %w", err)
+	}
+	now := time.Now()
+	if token.Expired(now) {
+		return nil, fmt.Errorf("api token expired")
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= apiTokenLastUsedInterval {
+		if err := s.repo.TouchLastUsed(ctx, token.ID, now); err != nil {
+			slog.Warn("api token last-used update failed", "error", err, "tokenId", token.ID)
+		}
+	}
+
+	return &UserInfo{
+		UID:     token.UID,
+		TokenID: token.ID,
+		Scopes:  token.Scopes,
+	}, nil
+}
+
+// newAPITokenSecret returns a random token: the prefix followed by 32 bytes
+// of base64url.
+func newAPITokenSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate api token: %w", err)
+	}
+	return model.APITokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashAPIToken returns the hex SHA-256 of a token. The secret has 256 bits
+// of entropy, so a fast unsalted hash is sufficient.
+func hashAPIToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
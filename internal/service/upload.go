@@ -0,0 +1,404 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/pandasWhoCode/paintbar/internal/jobs"
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"github.com/pandasWhoCode/paintbar/internal/repository"
+)
+
+// Background job types used by UploadService.
+const (
+	JobTypeUploadFinalize = "upload.finalize"
+	JobTypeUploadExpire   = "upload.expire"
+)
+
+// UploadService implements resumable project blob uploads. Each chunk is
+// stored as its own Storage object; finalizing runs as a background job that
+// verifies the content hash and assembles the chunks into the project blob,
+// so neither step is bound by the HTTP server's timeouts.
+type UploadService struct {
+	projects repository.ProjectRepository
+	sessions repository.UploadSessionRepository
+	storage  StorageClient
+	runner   *jobs.Runner
+}
+
+// NewUploadService creates a new UploadService and registers its job
+// handlers on runner, so it must be called before runner.Start. storage may
+// be nil if Storage is not configured.
+func NewUploadService(projects repository.ProjectRepository, sessions repository.UploadSessionRepository, storage StorageClient, runner *jobs.Runner) *UploadService {
+	s := &UploadService{projects: projects, sessions: sessions, storage: storage, runner: runner}
+	runner.Register(JobTypeUploadFinalize, s.finalizeJob)
+	runner.Register(JobTypeUploadExpire, s.expireJob)
+	return s
+}
+
+// InitiateUpload starts a resumable upload for a project's blob. The hash
+// must match the project's contentHash. A job is scheduled to delete the
+// session and its chunks if it has not been finalized when it expires.
+func (s *UploadService) InitiateUpload(ctx context.Context, uid, projectID string, req *model.UploadInit) (*model.UploadSession, error) {
+	if projectID == "" {
+		return nil, fmt.Errorf("project ID is required")
+	}
+	if s.storage == nil {
+		return nil, fmt.Errorf("storage is not configured")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation: %w", err)
+	}
+
+	project, err := s.projects.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("get project for upload: %w", err)
+	}
+	if project.UserID != uid {
+		return nil, fmt.Errorf("unauthorized: cannot upload to another user's project")
+	}
+	if project.ContentHash == "" {
+		return nil, fmt.Errorf("invalid upload: project has no content hash")
+	}
+	if req.ContentHash != project.ContentHash {
+		return nil, fmt.Errorf("invalid upload: contentHash does not match the project")
+	}
+
+	session := &model.UploadSession{
+		UID:         uid,
+		ProjectID:   projectID,
+		Size:        req.Size,
+		ContentHash: req.ContentHash,
+		Status:      model.UploadStatusOpen,
+		ExpiresAt:   time.Now().Add(model.UploadSessionTTL),
+	}
+	id, err := s.sessions.Create(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	session.ID = id
+
+	_, err = s.runner.Enqueue(ctx, jobs.EnqueueRequest{
+		Type:           JobTypeUploadExpire,
+		Payload:        map[string]interface{}{"uploadId": id},
+		IdempotencyKey: id,
+		RunAt:          session.ExpiresAt,
+	})
+	if err != nil {
+		if delErr := s.sessions.Delete(ctx, id); delErr != nil {
+			slog.Error("upload session cleanup failed", "error", delErr, "uploadId", id)
+		}
+		return nil, fmt.Errorf("schedule upload expiry: %w", err)
+	}
+
+	return session, nil
+}
+
+// GetUpload returns a session so the client can resume from its offset.
+func (s *UploadService) GetUpload(ctx context.Context, uid, projectID, uploadID string) (*model.UploadSession, error) {
+	return s.ownedSession(ctx, uid, projectID, uploadID)
+}
+
+// WriteChunk stores bytes [start, start+size) of the upload. The chunk must
+// begin at the session's current offset; total must equal the declared size.
+func (s *UploadService) WriteChunk(ctx context.Context, uid, projectID, uploadID string, start, size, total int64, data io.Reader) (*model.UploadSession, error) {
+	session, err := s.ownedSession(ctx, uid, projectID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != model.UploadStatusOpen {
+		return nil, fmt.Errorf("invalid upload: session is %s", session.Status)
+	}
+	if total != session.Size {
+		return nil, fmt.Errorf("invalid range: total size must be %d", session.Size)
+	}
+	if start != session.Offset {
+		return nil, fmt.Errorf("invalid range: upload offset is %d", session.Offset)
+	}
+	if size <= 0 || size > model.MaxUploadChunkSize {
+		return nil, fmt.Errorf("invalid range: chunk must be 1 to %d bytes", model.MaxUploadChunkSize)
+	}
+	if start+size > session.Size {
+		return nil, fmt.Errorf("invalid range: chunk ends past the declared size")
+	}
+	if start+size < session.Size && size < model.MinUploadChunkSize {
+		return nil, fmt.Errorf("invalid range: chunks other than the last must be at least %d bytes", model.MinUploadChunkSize)
+	}
+
+	// Reject non-PNG uploads on the first chunk rather than at finalize
+	if start == 0 {
+		header := make([]byte, len(pngMagic))
+		if _, err := io.ReadFull(data, header); err != nil || !bytes.Equal(header, pngMagic) {
+			return nil, fmt.Errorf("invalid upload: file is not a valid PNG image")
+		}
+		data = io.MultiReader(bytes.NewReader(header), data)
+	}
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, fmt.Errorf("generate chunk name: %w", err)
+	}
+	objectPath, err := repository.UploadChunkObjectPath(uid, uploadID, start, hex.EncodeToString(suffix))
+	if err != nil {
+		return nil, fmt.Errorf("build chunk path: %w", err)
+	}
+
+	counter := &countingReader{r: io.LimitReader(data, size+1)}
+	if err := s.storage.WriteObject(ctx, objectPath, counter, "application/octet-stream"); err != nil {
+		return nil, fmt.Errorf("write chunk: %w", err)
+	}
+	if counter.n != size {
+		s.deleteObject(ctx, objectPath)
+		return nil, fmt.Errorf("invalid range: received %d bytes, Content-Range declared %d", counter.n, size)
+	}
+
+	updated, err := s.sessions.AppendChunk(ctx, uploadID, model.UploadChunk{Offset: start, Size: size, Object: objectPath})
+	if err != nil {
+		s.deleteObject(ctx, objectPath)
+		return nil, err
+	}
+	return updated, nil
+}
+
+// FinalizeUpload queues assembly of a fully received upload and returns the
+// session with the job ID to poll. Repeated calls return the same job.
+func (s *UploadService) FinalizeUpload(ctx context.Context, uid, projectID, uploadID string) (*model.UploadSession, error) {
+	session, err := s.ownedSession(ctx, uid, projectID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status == model.UploadStatusFinalizing {
+		return session, nil
+	}
+	if !session.Complete() {
+		return nil, fmt.Errorf("invalid upload: received %d of %d bytes", session.Offset, session.Size)
+	}
+
+	job, err := s.runner.Enqueue(ctx, jobs.EnqueueRequest{
+		Type:           JobTypeUploadFinalize,
+		UID:            uid,
+		Payload:        map[string]interface{}{"uploadId": uploadID},
+		IdempotencyKey: uploadID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("queue upload finalize: %w", err)
+	}
+	if err := s.sessions.MarkFinalizing(ctx, uploadID, job.ID); err != nil {
+		return nil, err
+	}
+
+	session.Status = model.UploadStatusFinalizing
+	session.JobID = job.ID
+	return session, nil
+}
+
+// ownedSession loads an unexpired session and checks it belongs to uid and
+// projectID.
+func (s *UploadService) ownedSession(ctx context.Context, uid, projectID, uploadID string) (*model.UploadSession, error) {
+	if uploadID == "" {
+		return nil, fmt.Errorf("upload ID is required")
+	}
+
+	session, err := s.sessions.GetByID(ctx, uploadID)
+	if err != nil {
+		return nil, fmt.Errorf("get upload session: %w", err)
+	}
+	if session.UID != uid || session.ProjectID != projectID {
+		return nil, fmt.Errorf("unauthorized: cannot access another user's upload")
+	}
+	if session.Status == model.UploadStatusOpen && time.Now().After(session.ExpiresAt) {
+		return nil, fmt.Errorf("upload session not found: expired")
+	}
+	return session, nil
+}
+
+// uploadJobPayload is the payload of both upload job types.
+type uploadJobPayload struct {
+	UploadID string `json:"uploadId"`
+}
+
+// finalizeJob verifies the received bytes against the session's hash, writes
+// the assembled blob to the project's object path and sets its storageURL.
+// The chunks and session are removed on success and on permanent failure.
+func (s *UploadService) finalizeJob(ctx context.Context, job *jobs.Job) (map[string]interface{}, error) {
+	var p uploadJobPayload
+	if err := job.DecodePayload(&p); err != nil {
+		return nil, jobs.Permanent(err)
+	}
+
+	session, err := s.sessions.GetByID(ctx, p.UploadID)
+	if err != nil {
+		if repository.IsNotFoundError(err) {
+			return nil, jobs.Permanent(err)
+		}
+		return nil, err
+	}
+
+	result, err := s.assemble(ctx, session)
+	if err != nil && !jobs.IsPermanent(err) && job.Attempts < job.MaxAttempts {
+		return nil, err
+	}
+	s.cleanup(ctx, session)
+	return result, err
+}
+
+// assemble does the work of finalizeJob.
+func (s *UploadService) assemble(ctx context.Context, session *model.UploadSession) (map[string]interface{}, error) {
+	if s.storage == nil {
+		return nil, jobs.Permanent(fmt.Errorf("storage is not configured"))
+	}
+
+	// First pass: hash the chunks without writing anything to the
+	// content-addressed project path.
+	hasher := sha256.New()
+	n, err := io.Copy(hasher, newChunkReader(ctx, s.storage, session.Chunks))
+	if err != nil {
+		return nil, fmt.Errorf("read chunks: %w", err)
+	}
+	if n != session.Size {
+		return nil, jobs.Permanent(fmt.Errorf("assembled %d bytes, expected %d", n, session.Size))
+	}
+	if hex.EncodeToString(hasher.Sum(nil)) != session.ContentHash {
+		return nil, jobs.Permanent(fmt.Errorf("content hash mismatch"))
+	}
+
+	project, err := s.projects.GetByID(ctx, session.ProjectID)
+	if err != nil {
+		if repository.IsNotFoundError(err) {
+			return nil, jobs.Permanent(fmt.Errorf("project was deleted during upload"))
+		}
+		return nil, fmt.Errorf("get project for finalize: %w", err)
+	}
+	if project.ContentHash != session.ContentHash {
+		return nil, jobs.Permanent(fmt.Errorf("project content changed during upload"))
+	}
+
+	// Second pass: write the verified bytes
+	objectPath, err := repository.ProjectObjectPath(session.UID, session.ContentHash)
+	if err != nil {
+		return nil, jobs.Permanent(fmt.Errorf("build object path: %w", err))
+	}
+	if err := s.storage.WriteObject(ctx, objectPath, newChunkReader(ctx, s.storage, session.Chunks), "image/png"); err != nil {
+		return nil, fmt.Errorf("write blob: %w", err)
+	}
+
+	downloadURL, err := s.storage.GenerateDownloadURL(objectPath, 7*24*time.Hour)
+	if err != nil {
+		return nil, fmt.Errorf("generate download url: %w", err)
+	}
+	if err := validateStorageURL(downloadURL); err != nil {
+		return nil, jobs.Permanent(fmt.Errorf("finalize upload: %w", err))
+	}
+	err = s.projects.UpdateRaw(ctx, session.ProjectID, map[string]interface{}{
+		"storageURL": downloadURL,
+		"updatedAt":  time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("set storage url: %w", err)
+	}
+
+	return map[string]interface{}{"projectId": session.ProjectID, "size": session.Size}, nil
+}
+
+// expireJob removes a session that was never finalized. Finalizing sessions
+// are left to the finalize job, which cleans up after itself.
+func (s *UploadService) expireJob(ctx context.Context, job *jobs.Job) (map[string]interface{}, error) {
+	var p uploadJobPayload
+	if err := job.DecodePayload(&p); err != nil {
+		return nil, jobs.Permanent(err)
+	}
+
+	session, err := s.sessions.GetByID(ctx, p.UploadID)
+	if err != nil {
+		if repository.IsNotFoundError(err) {
+			return map[string]interface{}{"expired": false}, nil
+		}
+		return nil, err
+	}
+	if session.Status != model.UploadStatusOpen {
+		return map[string]interface{}{"expired": false}, nil
+	}
+
+	s.cleanup(ctx, session)
+	slog.Info("upload session expired", "uploadId", session.ID, "projectId", session.ProjectID, "received", session.Offset)
+	return map[string]interface{}{"expired": true}, nil
+}
+
+// cleanup deletes a session's chunk objects and then the session itself.
+func (s *UploadService) cleanup(ctx context.Context, session *model.UploadSession) {
+	for _, chunk := range session.Chunks {
+		s.deleteObject(ctx, chunk.Object)
+	}
+	if err := s.sessions.Delete(ctx, session.ID); err != nil {
+		slog.Error("upload session delete failed", "error", err, "uploadId", session.ID)
+	}
+}
+
+// deleteObject removes a Storage object, logging failures.
+func (s *UploadService) deleteObject(ctx context.Context, objectPath string) {
+	if s.storage == nil {
+		return
+	}
+	if err := s.storage.DeleteObject(ctx, objectPath); err != nil {
+		slog.Warn("upload chunk delete failed", "error", err, "object", objectPath)
+	}
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// chunkReader streams a session's chunk objects in order, opening each one
+// only when the previous one is exhausted.
+type chunkReader struct {
+	ctx     context.Context
+	storage StorageClient
+	chunks  []model.UploadChunk
+	cur     io.ReadCloser
+}
+
+func newChunkReader(ctx context.Context, storage StorageClient, chunks []model.UploadChunk) *chunkReader {
+	return &chunkReader{ctx: ctx, storage: storage, chunks: chunks}
+}
+
+func (c *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if c.cur == nil {
+			if len(c.chunks) == 0 {
+				return 0, io.EOF
+			}
+			rc, err := c.storage.ReadObject(c.ctx, c.chunks[0].Object)
+			if err != nil {
+				return 0, fmt.Errorf("read chunk at offset %d: %w", c.chunks[0].Offset, err)
+			}
+			c.cur = rc
+			c.chunks = c.chunks[1:]
+		}
+
+		n, err := c.cur.Read(p)
+		if err == io.EOF {
+			c.cur.Close()
+			c.cur = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
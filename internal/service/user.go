@@ -11,12 +11,14 @@ import (
 
 // UserService handles user profile business logic.
 type UserService struct {
-	repo repository.UserRepository
+	repo  repository.UserRepository
+	audit repository.AuditLogger
 }
 
-// NewUserService creates a new UserService.
-func NewUserService(repo repository.UserRepository) *UserService {
-	return &UserService{repo: repo}
+// NewUserService creates a new UserService. audit may be nil to disable
+// audit logging.
+func NewUserService(repo repository.UserRepository, audit repository.AuditLogger) *UserService {
+	return &UserService{repo: repo, audit: audit}
 }
 
 // GetProfile retrieves a user profile by UID.
@@ -74,7 +76,31 @@ func (s *UserService) UpdateProfile(ctx context.Context, requestorUID string, ta
 		return fmt.Errorf("location must be 100 characters or less")
 	}
 
-	return s.repo.Update(ctx, targetUID, update)
+	// Snapshot the current profile for the audit diff. The profile doc may
+	// not exist yet; Update creates it.
+	before := map[string]interface{}{}
+	current, err := s.repo.GetByID(ctx, targetUID)
+	if err != nil && !repository.IsNotFoundError(err) {
+		return fmt.Errorf("get profile for update: %w", err)
+	}
+	if current != nil {
+		before = current.ToProfileMap()
+	}
+
+	if err := s.repo.Update(ctx, targetUID, update); err != nil {
+		return err
+	}
+
+	if changes := model.AuditDiff(before, update.ToUpdateMap()); changes != nil {
+		recordAudit(ctx, s.audit, &model.AuditEntry{
+			ActorUID:     requestorUID,
+			Action:       model.AuditActionProfileUpdate,
+			ResourceType: model.AuditResourceUser,
+			ResourceID:   targetUID,
+			Changes:      changes,
+		})
+	}
+	return nil
 }
 
 // ClaimUsername validates and atomically claims a username for a user.
@@ -98,7 +124,29 @@ func (s *UserService) ClaimUsername(ctx context.Context, uid string, username st
 		return fmt.Errorf("username already set — usernames cannot be changed")
 	}
 
-	return s.repo.ClaimUsername(ctx, uid, username)
+	if err := s.repo.ClaimUsername(ctx, uid, username); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.audit, &model.AuditEntry{
+		ActorUID:     uid,
+		Action:       model.AuditActionUsernameClaim,
+		ResourceType: model.AuditResourceUsername,
+		ResourceID:   username,
+		Changes:      model.AuditDiff(map[string]interface{}{"username": ""}, map[string]interface{}{"username": username}),
+	})
+	return nil
+}
+
+// ListActivity returns a page of the user's own audit entries, newest first.
+func (s *UserService) ListActivity(ctx context.Context, uid string, limit int, startAfter string) ([]*model.AuditEntry, error) {
+	if uid == "" {
+		return nil, fmt.Errorf("uid is required")
+	}
+	if s.audit == nil {
+		return []*model.AuditEntry{}, nil
+	}
+	return s.audit.List(ctx, uid, clampPageSize(limit), startAfter)
 }
 
 // sanitizeUpdateField trims whitespace and strips control characters on a pointer string field.
@@ -0,0 +1,471 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pandasWhoCode/paintbar/internal/model"
+	"github.com/pandasWhoCode/paintbar/internal/repository"
+)
+
+// Webhook delivery headers.
+const (
+	WebhookEventHeader     = "X-PaintBar-Event"
+	WebhookDeliveryHeader  = "X-PaintBar-Delivery"
+	WebhookTimestampHeader = "X-PaintBar-Timestamp"
+	WebhookSignatureHeader = "X-PaintBar-Signature"
+)
+
+const (
+	// webhookMaxAttempts is the number of delivery attempts per event,
+	// including the first.
+	webhookMaxAttempts = 5
+	// webhookInitialBackoff is the wait before the first retry; it doubles
+	// on every further retry (1s, 2s, 4s, 8s).
+	webhookInitialBackoff = time.Second
+	// webhookRequestTimeout bounds a single delivery attempt.
+	webhookRequestTimeout = 10 * time.Second
+	// webhookPublishTimeout bounds all deliveries for one event, retries
+	// included.
+	webhookPublishTimeout = 2 * time.Minute
+)
+
+// EventPublisher receives domain events from the service layer after a change
+// has been persisted. Publish must not block the caller.
+type EventPublisher interface {
+	Publish(ctx context.Context, uid, event string, data map[string]interface{})
+}
+
+// publishEvent sends an event if a publisher is configured.
+func publishEvent(ctx context.Context, events EventPublisher, uid, event string, data map[string]interface{}) {
+	if events == nil {
+		return
+	}
+	events.Publish(ctx, uid, event, data)
+}
+
+// WebhookService manages webhook registrations and delivers signed events to
+// them. It implements EventPublisher.
+type WebhookService struct {
+	repo         repository.WebhookRepository
+	audit        repository.AuditLogger
+	client       *http.Client
+	allowPrivate bool
+	backoff      time.Duration
+	maxPerUser   int
+	wg           sync.WaitGroup
+}
+
+// NewWebhookService creates a new WebhookService. allowPrivate permits
+// plain-http endpoints on loopback and private networks and should only be
+// set for local development. audit may be nil to disable audit logging.
+func NewWebhookService(repo repository.WebhookRepository, audit repository.AuditLogger, allowPrivate bool) *WebhookService {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !allowPrivate {
+		// Checked at connect time so DNS rebinding cannot reach internal
+		// services after the URL passed validation.
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !isPublicAddr(addrPort.Addr()) {
+				return fmt.Errorf("webhook destination %s is not a public address", addrPort.Addr())
+			}
+			return nil
+		}
+	}
+
+	client := &http.Client{
+		Timeout: webhookRequestTimeout,
+		Transport: &http.Transport{
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConnsPerHost: 2,
+		},
+		// Redirects are not followed; a 3xx counts as a failed delivery.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	return &WebhookService{
+		repo:         repo,
+		audit:        audit,
+		client:       client,
+		allowPrivate: allowPrivate,
+		backoff:      webhookInitialBackoff,
+		maxPerUser:   model.MaxWebhooksPerUser,
+	}
+}
+
+// SetMaxPerUser caps how many webhooks one user may register
+// (model.MaxWebhooksPerUser by default).
+func (s *WebhookService) SetMaxPerUser(n int) {
+	s.maxPerUser = n
+}
+
+// CreateWebhook registers a webhook for uid. The signing secret is returned
+// once.
+func (s *WebhookService) CreateWebhook(ctx context.Context, uid string, req *model.WebhookCreate) (*model.WebhookCreated, error) {
+	req.Sanitize()
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation: %w", err)
+	}
+	if err := s.validateEndpoint(req.URL); err != nil {
+		return nil, fmt.Errorf("validation: %w", err)
+	}
+
+	existing, err := s.repo.ListByUser(ctx, uid)
+	if err != nil {
+		return nil, fmt.Errorf("list webhooks: %w", err)
+	}
+	if len(existing) >= s.maxPerUser {
+		return nil, fmt.Errorf("invalid request: a user may have at most %d webhooks", s.maxPerUser)
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := &model.Webhook{
+		UID:    uid,
+		URL:    req.URL,
+		Events: req.Events,
+		Secret: secret,
+	}
+	id, err := s.repo.Create(ctx, webhook)
+	if err != nil {
+		return nil, err
+	}
+	webhook.ID = id
+
+	recordAudit(ctx, s.audit, &model.AuditEntry{
+		ActorUID:     uid,
+		Action:       model.AuditActionWebhookCreate,
+		ResourceType: model.AuditResourceWebhook,
+		ResourceID:   id,
+		Details:      map[string]interface{}{"url": webhook.URL, "events": webhook.Events},
+	})
+	return &model.WebhookCreated{Webhook: webhook, Secret: secret}, nil
+}
+
+// ListWebhooks returns the user's webhooks, newest first, without secrets.
+func (s *WebhookService) ListWebhooks(ctx context.Context, uid string) ([]*model.Webhook, error) {
+	webhooks, err := s.repo.ListByUser(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if webhooks == nil {
+		webhooks = []*model.Webhook{}
+	}
+	return webhooks, nil
+}
+
+// DeleteWebhook removes one of the user's webhooks and its delivery log.
+func (s *WebhookService) DeleteWebhook(ctx context.Context, uid, webhookID string) error {
+	webhook, err := s.ownedWebhook(ctx, uid, webhookID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, webhookID); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.audit, &model.AuditEntry{
+		ActorUID:     uid,
+		Action:       model.AuditActionWebhookDelete,
+		ResourceType: model.AuditResourceWebhook,
+		ResourceID:   webhookID,
+		Details:      map[string]interface{}{"url": webhook.URL},
+	})
+	return nil
+}
+
+// ListDeliveries returns a page of the webhook's delivery log, newest first.
+func (s *WebhookService) ListDeliveries(ctx context.Context, uid, webhookID string, limit int, startAfter string) ([]*model.WebhookDelivery, error) {
+	if _, err := s.ownedWebhook(ctx, uid, webhookID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	deliveries, err := s.repo.ListDeliveries(ctx, webhookID, limit, startAfter)
+	if err != nil {
+		return nil, err
+	}
+	if deliveries == nil {
+		deliveries = []*model.WebhookDelivery{}
+	}
+	return deliveries, nil
+}
+
+// PingWebhook sends a single, unretried ping event and returns the logged
+// delivery so the user can see the endpoint's response immediately.
+func (s *WebhookService) PingWebhook(ctx context.Context, uid, webhookID string) (*model.WebhookDelivery, error) {
+	webhook, err := s.ownedWebhook(ctx, uid, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := newWebhookPayload(model.WebhookEventPing, map[string]interface{}{"webhookId": webhook.ID})
+	if err != nil {
+		return nil, err
+	}
+	return s.deliver(ctx, webhook, payload, 1), nil
+}
+
+// Publish implements EventPublisher. Deliveries to the user's subscribed
+// webhooks run in the background, detached from the request's cancellation.
+func (s *WebhookService) Publish(ctx context.Context, uid, event string, data map[string]interface{}) {
+	ctx = context.WithoutCancel(ctx)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ctx, cancel := context.WithTimeout(ctx, webhookPublishTimeout)
+		defer cancel()
+
+		webhooks, err := s.repo.ListByUser(ctx, uid)
+		if err != nil {
+			slog.Error("webhook lookup failed", "error", err, "uid", uid, "event", event)
+			return
+		}
+
+		var wg sync.WaitGroup
+		for _, webhook := range webhooks {
+			if !webhook.Subscribed(event) {
+				continue
+			}
+			payload, err := newWebhookPayload(event, data)
+			if err != nil {
+				slog.Error("webhook payload failed", "error", err, "event", event)
+				return
+			}
+			wg.Add(1)
+			go func(webhook *model.Webhook) {
+				defer wg.Done()
+				s.deliver(ctx, webhook, payload, webhookMaxAttempts)
+			}(webhook)
+		}
+		wg.Wait()
+	}()
+}
+
+// Wait blocks until in-flight deliveries finish or ctx is done. It is called
+// during graceful shutdown.
+func (s *WebhookService) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ownedWebhook loads a webhook and checks it belongs to uid.
+func (s *WebhookService) ownedWebhook(ctx context.Context, uid, webhookID string) (*model.Webhook, error) {
+	if webhookID == "" {
+		return nil, fmt.Errorf("webhook ID is required")
+	}
+
+	webhook, err := s.repo.GetByID(ctx, webhookID)
+	if err != nil {
+		return nil, fmt.Errorf("get webhook: %w", err)
+	}
+	if webhook.UID != uid {
+		return nil, fmt.Errorf("unauthorized: cannot access another user's webhook")
+	}
+	return webhook, nil
+}
+
+// deliver POSTs the payload, retrying network errors, 429 and 5xx responses
+// with exponential backoff. Every attempt is recorded in the delivery log;
+// the last one is returned.
+func (s *WebhookService) deliver(ctx context.Context, webhook *model.Webhook, payload *model.WebhookPayload, maxAttempts int) *model.WebhookDelivery {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return &model.WebhookDelivery{
+			DeliveryID: payload.ID,
+			Event:      payload.Event,
+			Attempt:    1,
+			Error:      fmt.Sprintf("encode payload: %v", err),
+			CreatedAt:  time.Now(),
+		}
+	}
+
+	var delivery *model.WebhookDelivery
+	backoff := s.backoff
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		var retry bool
+		delivery, retry = s.attempt(ctx, webhook, payload, body, attempt)
+
+		if err := s.repo.LogDelivery(ctx, webhook.ID, delivery); err != nil {
+			slog.Error("webhook delivery log failed", "error", err, "webhookId", webhook.ID)
+		}
+		if delivery.Success || !retry || attempt == maxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return delivery
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	if !delivery.Success {
+		slog.Warn("webhook delivery failed",
+			"webhookId", webhook.ID,
+			"deliveryId", payload.ID,
+			"event", payload.Event,
+			"attempts", delivery.Attempt,
+			"status", delivery.StatusCode,
+			"error", delivery.Error,
+		)
+	}
+	return delivery
+}
+
+// attempt makes one delivery request and reports whether a failure is
+// worth retrying.
+func (s *WebhookService) attempt(ctx context.Context, webhook *model.Webhook, payload *model.WebhookPayload, body []byte, attempt int) (*model.WebhookDelivery, bool) {
+	start := time.Now()
+	delivery := &model.WebhookDelivery{
+		DeliveryID: payload.ID,
+		Event:      payload.Event,
+		Attempt:    attempt,
+		CreatedAt:  start,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		delivery.Error = fmt.Sprintf("build request: %v", err)
+		return delivery, false
+	}
+	timestamp := start.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "PaintBar-Webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, payload.Event)
+	req.Header.Set(WebhookDeliveryHeader, payload.ID)
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(webhook.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	delivery.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery, ctx.Err() == nil
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+
+	delivery.StatusCode = resp.StatusCode
+	delivery.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
+	if !delivery.Success {
+		delivery.Error = http.StatusText(resp.StatusCode)
+	}
+	return delivery, resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// validateEndpoint applies the network policy to a webhook URL. Outside local
+// development endpoints must use https and must not name a loopback or
+// private host; the dialer re-checks resolved addresses at delivery time.
+func (s *WebhookService) validateEndpoint(rawURL string) error {
+	if s.allowPrivate {
+		return nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	if u.Scheme != "https" {
+		return errors.New("url must use https")
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") {
+		return errors.New("url must be publicly reachable")
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !isPublicAddr(addr) {
+		return errors.New("url must be publicly reachable")
+	}
+	return nil
+}
+
+// isPublicAddr reports whether addr is a globally routable unicast address.
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !addr.IsLoopback() && !addr.IsLinkLocalUnicast()
+}
+
+// SignWebhookPayload returns the signature header value for a delivery:
+// "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>" keyed
+// with the webhook secret. Receivers should recompute it and compare in
+// constant time.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newWebhookPayload builds a payload with a fresh delivery ID.
+func newWebhookPayload(event string, data map[string]interface{}) (*model.WebhookPayload, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("generate delivery id: %w", err)
+	}
+	return &model.WebhookPayload{
+		ID:        hex.EncodeToString(b),
+		Event:     event,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}, nil
+}
+
+// newWebhookSecret returns a random signing secret: the prefix followed by
+// 32 bytes of base64url.
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate webhook secret: %w", err)
+	}
+	return model.WebhookSecretPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
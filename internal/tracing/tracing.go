@@ -0,0 +1,84 @@
+// Package tracing sets up OpenTelemetry tracing: the span exporter chosen
+// by configuration, the global tracer provider, and W3C trace context
+// propagation. Instrumented code gets its tracer from Tracer, which records
+// nothing until Setup installs a provider, so tests and the "none" exporter
+// pay only for no-op spans.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pandasWhoCode/paintbar/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName identifies the server in traces unless OTEL_SERVICE_NAME
+// overrides it.
+const ServiceName = "paintbar"
+
+// instrumentationName names the tracer the server's own spans come from.
+const instrumentationName = "github.com/pandasWhoCode/paintbar"
+
+// Tracer returns the tracer for the server's spans. It follows the global
+// provider, so spans started before or without Setup are no-ops.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Setup installs the W3C trace context propagator and, unless exporter is
+// config.TraceExporterNone, a global tracer provider that batches spans to
+// it. The OTLP exporter sends over HTTP and is configured by the standard
+// OTEL_EXPORTER_OTLP_* variables; sampling follows OTEL_TRACES_SAMPLER and
+// defaults to sampling every trace. The returned function flushes and stops
+// the provider.
+func Setup(ctx context.Context, exporter, env string) (shutdown func(context.Context) error, err error) {
+	// Propagate even without an exporter, so a caller's trace continues
+	// through to Storage
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exp sdktrace.SpanExporter
+	switch exporter {
+	case config.TraceExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case config.TraceExporterStdout:
+		exp, err = stdouttrace.New()
+	case config.TraceExporterOTLP:
+		exp, err = otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create %s trace exporter: %w", exporter, err)
+	}
+
+	// Attributes from the environment take precedence over the defaults
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceName(ServiceName),
+			semconv.DeploymentEnvironmentName(env),
+		),
+		resource.WithTelemetrySDK(),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("trace resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
@@ -0,0 +1,52 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pandasWhoCode/paintbar/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// restoreGlobals puts back the global provider and propagator Setup replaces.
+func restoreGlobals(t *testing.T) {
+	tp, prop := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(tp)
+		otel.SetTextMapPropagator(prop)
+	})
+}
+
+func TestSetup_None(t *testing.T) {
+	restoreGlobals(t)
+	before := otel.GetTracerProvider()
+
+	shutdown, err := Setup(context.Background(), config.TraceExporterNone, config.EnvLocal)
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	assert.Equal(t, before, otel.GetTracerProvider(), "no provider is installed")
+	assert.Contains(t, otel.GetTextMapPropagator().Fields(), "traceparent", "trace context still propagates")
+}
+
+func TestSetup_Stdout(t *testing.T) {
+	restoreGlobals(t)
+
+	shutdown, err := Setup(context.Background(), config.TraceExporterStdout, config.EnvLocal)
+	require.NoError(t, err)
+	defer shutdown(context.Background())
+
+	assert.IsType(t, &sdktrace.TracerProvider{}, otel.GetTracerProvider())
+	assert.Contains(t, otel.GetTextMapPropagator().Fields(), "traceparent")
+}
+
+func TestSetup_UnknownExporter(t *testing.T) {
+	restoreGlobals(t)
+
+	_, err := Setup(context.Background(), "zipkin", config.EnvLocal)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown trace exporter "zipkin"`)
+}
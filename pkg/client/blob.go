@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Blob is a streamed image response. The caller must close Body.
+type Blob struct {
+	Body          io.ReadCloser
+	ContentType   string
+	ContentLength int64 // -1 if unknown
+	ETag          string
+	LastModified  time.Time
+
+	// Partial is set when the server answered a Range request with 206
+	Partial bool
+	// NotModified is set when the server answered a conditional request
+	// with 304; Body is then empty
+	NotModified bool
+}
+
+// BlobOptions make a download conditional or ranged.
+type BlobOptions struct {
+	// Version is the expected content hash. When it matches, the server
+	// marks the response immutable so shared caches can keep it.
+	Version string
+	// IfNoneMatch is an ETag from an earlier download; an unchanged blob
+	// returns NotModified.
+	IfNoneMatch string
+	// Offset resumes a download from this byte; the response is Partial.
+	Offset int64
+}
+
+// fetchBlob sends req and wraps the streamed response.
+func (c *Client) fetchBlob(ctx context.Context, req *request, opts *BlobOptions) (*Blob, error) {
+	if opts != nil {
+		req.header = http.Header{}
+		if opts.IfNoneMatch != "" {
+			req.header.Set("If-None-Match", opts.IfNoneMatch)
+		}
+		if opts.Offset > 0 {
+			req.header.Set("Range", "bytes="+strconv.FormatInt(opts.Offset, 10)+"-")
+		}
+		if opts.Version != "" {
+			req.query = url.Values{"v": {opts.Version}}
+		}
+	}
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	b := &Blob{
+		Body:          resp.Body,
+		ContentType:   resp.Header.Get("Content-Type"),
+		ContentLength: resp.ContentLength,
+		ETag:          resp.Header.Get("ETag"),
+		Partial:       resp.StatusCode == http.StatusPartialContent,
+		NotModified:   resp.StatusCode == http.StatusNotModified,
+	}
+	if t, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		b.LastModified = t
+	}
+	return b, nil
+}
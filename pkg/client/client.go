@@ -0,0 +1,311 @@
+// Package client is a Go client for the PaintBar API: profiles, projects
+// (including blob upload and download), gallery items and NFTs.
+//
+// Every method maps to one operation in api/openapi.yaml, named by its
+// operationId; the package tests fail if a route or a response type drifts
+// from the spec.
+//
+//	c := client.New("https://paintbar.art", client.WithToken(os.Getenv("PAINTBAR_TOKEN")))
+//	for p, err := range c.AllProjects(ctx, &client.ListOptions{Sort: "title"}) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(p.Title)
+//	}
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults for New.
+const (
+	DefaultMaxRetries   = 3
+	DefaultMaxRetryWait = 2 * time.Minute
+	DefaultTimeout      = 30 * time.Second
+)
+
+// Client calls the PaintBar API. It is safe for concurrent use.
+type Client struct {
+	baseURL      string // without a trailing slash
+	http         *http.Client
+	token        string
+	userAgent    string
+	maxRetries   int
+	maxRetryWait time.Duration
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithToken authenticates requests with a Bearer token: a Firebase ID
+// token or a personal access token (pbt_...).
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithHTTPClient sends requests through hc instead of a client with
+// DefaultTimeout. Blob downloads are streamed, so a client timeout bounds
+// the whole transfer.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.http = hc }
+}
+
+// WithUserAgent sets the User-Agent header.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) { c.userAgent = ua }
+}
+
+// WithRetries sets how many times a rate-limited (429) request is retried
+// and the longest Retry-After the client will wait out. A 429 asking for
+// a longer wait is returned to the caller as an error.
+func WithRetries(maxRetries int, maxWait time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.maxRetryWait = maxWait
+	}
+}
+
+// New returns a client for the API at baseURL, e.g. http://localhost:8080.
+// It panics if baseURL is not an absolute URL.
+func New(baseURL string, opts ...Option) *Client {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" || u.RawQuery != "" {
+		panic(fmt.Sprintf("client: invalid base URL %q", baseURL))
+	}
+	c := &Client{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		http:         &http.Client{Timeout: DefaultTimeout},
+		userAgent:    "paintbar-go-client",
+		maxRetries:   DefaultMaxRetries,
+		maxRetryWait: DefaultMaxRetryWait,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// operation is a documented method and path template.
+type operation struct {
+	method string
+	path   string
+}
+
+// operations are the API operations the client calls, keyed by their
+// operationId in api/openapi.yaml.
+var operations = map[string]operation{
+	"ping": {http.MethodGet, "/api/v1/ping"},
+
+	"getProfile":          {http.MethodGet, "/api/v1/profile"},
+	"updateProfile":       {http.MethodPut, "/api/v1/profile"},
+	"claimUsername":       {http.MethodPost, "/api/v1/claim-username"},
+	"listAccountActivity": {http.MethodGet, "/api/v1/account/activity"},
+
+	"getJob": {http.MethodGet, "/api/v1/jobs/{id}"},
+
+	"listProjects":        {http.MethodGet, "/api/v1/projects"},
+	"createProject":       {http.MethodPost, "/api/v1/projects"},
+	"getProjectByTitle":   {http.MethodGet, "/api/v1/projects/by-title"},
+	"countProjects":       {http.MethodGet, "/api/v1/projects/count"},
+	"getProject":          {http.MethodGet, "/api/v1/projects/{id}"},
+	"updateProject":       {http.MethodPut, "/api/v1/projects/{id}"},
+	"deleteProject":       {http.MethodDelete, "/api/v1/projects/{id}"},
+	"uploadBlob":          {http.MethodPost, "/api/v1/projects/{id}/upload-blob"},
+	"confirmUpload":       {http.MethodPost, "/api/v1/projects/{id}/confirm-upload"},
+	"downloadBlob":        {http.MethodGet, "/api/v1/projects/{id}/blob"},
+	"getProjectThumbnail": {http.MethodGet, "/api/v1/projects/{id}/thumbnail"},
+	"initiateUpload":      {http.MethodPost, "/api/v1/projects/{id}/uploads"},
+	"getUpload":           {http.MethodGet, "/api/v1/projects/{id}/uploads/{uploadId}"},
+	"writeUploadChunk":    {http.MethodPut, "/api/v1/projects/{id}/uploads/{uploadId}"},
+	"finalizeUpload":      {http.MethodPost, "/api/v1/projects/{id}/uploads/{uploadId}/finalize"},
+
+	"listGalleryItems":    {http.MethodGet, "/api/v1/gallery"},
+	"shareToGallery":      {http.MethodPost, "/api/v1/gallery"},
+	"countGalleryItems":   {http.MethodGet, "/api/v1/gallery/count"},
+	"getGalleryItem":      {http.MethodGet, "/api/v1/gallery/{id}"},
+	"deleteGalleryItem":   {http.MethodDelete, "/api/v1/gallery/{id}"},
+	"getGalleryThumbnail": {http.MethodGet, "/api/v1/gallery/{id}/thumbnail"},
+	"listComments":        {http.MethodGet, "/api/v1/gallery/{id}/comments"},
+	"createComment":       {http.MethodPost, "/api/v1/gallery/{id}/comments"},
+	"editComment":         {http.MethodPut, "/api/v1/gallery/{id}/comments/{commentId}"},
+	"deleteComment":       {http.MethodDelete, "/api/v1/gallery/{id}/comments/{commentId}"},
+	"getReactions":        {http.MethodGet, "/api/v1/gallery/{id}/reactions"},
+	"addReaction":         {http.MethodPost, "/api/v1/gallery/{id}/reactions"},
+	"removeReaction":      {http.MethodDelete, "/api/v1/gallery/{id}/reactions/{reaction}"},
+
+	"listNFTs":        {http.MethodGet, "/api/v1/nfts"},
+	"createNFT":       {http.MethodPost, "/api/v1/nfts"},
+	"countNFTs":       {http.MethodGet, "/api/v1/nfts/count"},
+	"getNFT":          {http.MethodGet, "/api/v1/nfts/{id}"},
+	"deleteNFT":       {http.MethodDelete, "/api/v1/nfts/{id}"},
+	"getNFTThumbnail": {http.MethodGet, "/api/v1/nfts/{id}/thumbnail"},
+}
+
+// request describes one API call.
+type request struct {
+	op          string            // operationId
+	path        map[string]string // path parameters
+	query       url.Values
+	header      http.Header
+	body        io.Reader // sent as is; nil with json unset sends no body
+	json        interface{}
+	contentType string
+}
+
+// send performs req and returns the response for a 2xx or 304 status;
+// other statuses are returned as *Error. Rate-limited requests are retried
+// after their Retry-After when the body can be replayed: JSON bodies and
+// bodies that implement io.Seeker.
+func (c *Client) send(ctx context.Context, req *request) (*http.Response, error) {
+	op, ok := operations[req.op]
+	if !ok {
+		panic("client: unknown operation " + req.op)
+	}
+
+	// open returns the body for one attempt and its length (-1 if unknown),
+	// or ok=false when a streamed body cannot be rewound for a retry
+	contentType := req.contentType
+	var open func(attempt int) (body io.Reader, length int64, ok bool)
+	switch {
+	case req.json != nil:
+		b, err := json.Marshal(req.json)
+		if err != nil {
+			return nil, fmt.Errorf("encode %s request: %w", req.op, err)
+		}
+		contentType = "application/json"
+		open = func(int) (io.Reader, int64, bool) { return bytes.NewReader(b), int64(len(b)), true }
+	case req.body != nil:
+		seeker, _ := req.body.(io.Seeker)
+		var start, length int64 = 0, -1
+		if seeker != nil {
+			var err error
+			if start, err = seeker.Seek(0, io.SeekCurrent); err != nil {
+				seeker = nil
+			} else if end, err := seeker.Seek(0, io.SeekEnd); err == nil {
+				length = end - start
+				if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+					return nil, fmt.Errorf("%s: rewind body: %w", req.op, err)
+				}
+			}
+		}
+		open = func(attempt int) (io.Reader, int64, bool) {
+			if attempt > 0 {
+				if seeker == nil {
+					return nil, 0, false
+				}
+				if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+					return nil, 0, false
+				}
+			}
+			return req.body, length, true
+		}
+	default:
+		open = func(int) (io.Reader, int64, bool) { return nil, 0, true }
+	}
+
+	var lastErr *Error
+	for attempt := 0; ; attempt++ {
+		body, length, ok := open(attempt)
+		if !ok {
+			return nil, lastErr
+		}
+		if body != nil {
+			// The transport closes request bodies; the caller owns theirs
+			body = io.NopCloser(body)
+		}
+		httpReq, err := http.NewRequestWithContext(ctx, op.method, c.url(op.path, req.path, req.query), body)
+		if err != nil {
+			return nil, fmt.Errorf("build %s request: %w", req.op, err)
+		}
+		if length >= 0 && body != nil {
+			httpReq.ContentLength = length
+		}
+		for k, v := range req.header {
+			httpReq.Header[k] = v
+		}
+		if contentType != "" {
+			httpReq.Header.Set("Content-Type", contentType)
+		}
+		httpReq.Header.Set("User-Agent", c.userAgent)
+		if c.token != "" {
+			httpReq.Header.Set("Authorization", "Bearer "+c.token)
+		}
+
+		resp, err := c.http.Do(httpReq)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", req.op, err)
+		}
+		if resp.StatusCode < 300 || resp.StatusCode == http.StatusNotModified {
+			return resp, nil
+		}
+
+		lastErr = newError(req.op, resp)
+		if resp.StatusCode != http.StatusTooManyRequests ||
+			attempt >= c.maxRetries || lastErr.RetryAfter > c.maxRetryWait {
+			return nil, lastErr
+		}
+
+		timer := time.NewTimer(lastErr.RetryAfter)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// call performs req and decodes the JSON response into out, if non-nil.
+func (c *Client) call(ctx context.Context, req *request, out interface{}) error {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out == nil {
+		_, err := io.Copy(io.Discard, resp.Body)
+		return err
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode %s response: %w", req.op, err)
+	}
+	return nil
+}
+
+// url expands a path template against the base URL.
+func (c *Client) url(template string, params map[string]string, query url.Values) string {
+	path := template
+	for name, value := range params {
+		path = strings.Replace(path, "{"+name+"}", url.PathEscape(value), 1)
+	}
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP
+// date. It returns 0 when the header is absent or unparseable.
+func retryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
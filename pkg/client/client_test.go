@@ -0,0 +1,393 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pandasWhoCode/paintbar/api"
+	"github.com/pandasWhoCode/paintbar/internal/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// clientPrefixes are the spec paths the client covers; every operation
+// under them must be in the route table.
+var clientPrefixes = []string{
+	"/api/v1/ping", "/api/v1/profile", "/api/v1/claim-username", "/api/v1/account/",
+	"/api/v1/jobs/", "/api/v1/projects", "/api/v1/gallery", "/api/v1/nfts",
+}
+
+func TestOperations_MatchSpec(t *testing.T) {
+	spec, err := openapi.Load(api.OpenAPISpec)
+	require.NoError(t, err)
+
+	documented := make(map[string]operation)
+	for _, op := range spec.Operations() {
+		documented[op.ID] = operation{op.Method, op.Path}
+		for _, prefix := range clientPrefixes {
+			if strings.HasPrefix(op.Path, prefix) {
+				assert.Contains(t, operations, op.ID, "%s has no client method", op)
+			}
+		}
+	}
+	for id, op := range operations {
+		assert.Equal(t, documented[id], op, "route of %s", id)
+	}
+}
+
+func TestTypes_MatchSpec(t *testing.T) {
+	type schemaDoc struct {
+		Ref        string                 `yaml:"$ref"`
+		Properties map[string]interface{} `yaml:"properties"`
+		AllOf      []schemaDoc            `yaml:"allOf"`
+	}
+	var doc struct {
+		Components struct {
+			Schemas map[string]schemaDoc `yaml:"schemas"`
+		} `yaml:"components"`
+	}
+	require.NoError(t, yaml.Unmarshal(api.OpenAPISpec, &doc))
+
+	// properties collects a schema's property names through allOf and $ref
+	var properties func(s schemaDoc, into map[string]bool)
+	properties = func(s schemaDoc, into map[string]bool) {
+		if s.Ref != "" {
+			properties(doc.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")], into)
+		}
+		for name := range s.Properties {
+			into[name] = true
+		}
+		for _, sub := range s.AllOf {
+			properties(sub, into)
+		}
+	}
+
+	for schema, v := range map[string]interface{}{
+		"User":                User{},
+		"UserUpdate":          UserUpdate{},
+		"AuditEntry":          AuditEntry{},
+		"Project":             Project{},
+		"ProjectSummary":      ProjectSummary{},
+		"ProjectCreate":       ProjectCreate{},
+		"ProjectUpdate":       ProjectUpdate{},
+		"CreateProjectResult": CreateProjectResult{},
+		"UploadSession":       UploadSession{},
+		"Job":                 Job{},
+		"GalleryItem":         GalleryItem{},
+		"GallerySummary":      GallerySummary{},
+		"GalleryItemCreate":   GalleryItemCreate{},
+		"Comment":             Comment{},
+		"CommentCreate":       CommentCreate{},
+		"ReactionSummary":     ReactionSummary{},
+		"NFT":                 NFT{},
+		"NFTSummary":          NFTSummary{},
+		"NFTCreate":           NFTCreate{},
+		"ProjectPage":         Page[ProjectSummary]{},
+		"GalleryPage":         Page[GallerySummary]{},
+		"NFTPage":             Page[NFTSummary]{},
+	} {
+		s, ok := doc.Components.Schemas[schema]
+		require.True(t, ok, "schema %s", schema)
+		names := make(map[string]bool)
+		properties(s, names)
+		var want []string
+		for name := range names {
+			want = append(want, name)
+		}
+		sort.Strings(want)
+		assert.Equal(t, want, jsonFields(v), "fields of %s", schema)
+	}
+}
+
+// jsonFields returns the sorted JSON names of a struct's fields.
+func jsonFields(v interface{}) []string {
+	typ := reflect.TypeOf(v)
+	var names []string
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestNew_InvalidBaseURL(t *testing.T) {
+	for _, u := range []string{"", "localhost:8080", "/api", "http://host?x=1"} {
+		assert.Panics(t, func() { New(u) }, u)
+	}
+	assert.Equal(t, "http://host", New("http://host/").baseURL)
+}
+
+func TestSend_AuthAndUserAgent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer pbt_secret", r.Header.Get("Authorization"))
+		assert.Equal(t, "paintbar-cli", r.Header.Get("User-Agent"))
+		assert.Equal(t, "/api/v1/projects/a%2Fb", r.URL.EscapedPath())
+		fmt.Fprint(w, `{"id":"a/b","userId":"u","title":"Sky","isPublic":true,"createdAt":"2026-01-02T03:04:05Z","updatedAt":"2026-01-02T03:04:05Z"}`)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, WithToken("pbt_secret"), WithUserAgent("paintbar-cli"))
+	p, err := c.GetProject(context.Background(), "a/b")
+	require.NoError(t, err)
+	assert.Equal(t, "Sky", p.Title)
+	assert.True(t, p.IsPublic)
+}
+
+func TestErrors_MapStatus(t *testing.T) {
+	for status, want := range map[int]error{
+		http.StatusBadRequest:            ErrBadRequest,
+		http.StatusUnauthorized:          ErrUnauthorized,
+		http.StatusForbidden:             ErrForbidden,
+		http.StatusNotFound:              ErrNotFound,
+		http.StatusConflict:              ErrConflict,
+		http.StatusRequestEntityTooLarge: ErrTooLarge,
+		http.StatusServiceUnavailable:    ErrServer,
+	} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			fmt.Fprint(w, `{"error":"project not found"}`)
+		}))
+		_, err := New(srv.URL).GetProject(context.Background(), "p1")
+		srv.Close()
+
+		assert.ErrorIs(t, err, want, status)
+		var apiErr *Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, "getProject", apiErr.Operation)
+		assert.Equal(t, status, apiErr.StatusCode)
+		assert.Equal(t, "project not found", apiErr.Message)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "<html>", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+	err := New(srv.URL).DeleteNFT(context.Background(), "n1")
+	assert.ErrorIs(t, err, ErrServer)
+	assert.EqualError(t, err, "paintbar: deleteNFT: 502 Bad Gateway")
+}
+
+func TestSend_RetriesRateLimited(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "png bytes", string(body), "the body is resent on retry")
+		if calls.Add(1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"status":"uploaded"}`)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL)
+	require.NoError(t, c.UploadBlob(context.Background(), "p1", bytes.NewReader([]byte("png bytes"))))
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestSend_RateLimitGivesUp(t *testing.T) {
+	var calls atomic.Int32
+	var retryAfter atomic.Value
+	retryAfter.Store("0")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", retryAfter.Load().(string))
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	_, err := New(srv.URL, WithRetries(2, time.Minute)).GetProfile(ctx)
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, int32(3), calls.Load(), "one attempt and two retries")
+
+	calls.Store(0)
+	retryAfter.Store("60")
+	_, err = New(srv.URL, WithRetries(2, time.Second)).GetProfile(ctx)
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, time.Minute, apiErr.RetryAfter)
+	assert.Equal(t, int32(1), calls.Load(), "a wait over the limit is not retried")
+
+	calls.Store(0)
+	retryAfter.Store("0")
+	err = New(srv.URL).UploadBlob(ctx, "p1", io.MultiReader(strings.NewReader("png")))
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, int32(1), calls.Load(), "a body that cannot be rewound is not retried")
+
+	calls.Store(0)
+	retryAfter.Store("1")
+	cancelled, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = New(srv.URL).GetProfile(cancelled)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, 60*time.Second, retryAfter("60", now))
+	assert.Equal(t, 90*time.Second, retryAfter("Fri, 01 May 2026 12:01:30 GMT", now))
+	assert.Zero(t, retryAfter("Fri, 01 May 2026 11:00:00 GMT", now))
+	assert.Zero(t, retryAfter("soon", now))
+	assert.Zero(t, retryAfter("", now))
+}
+
+func TestAllProjects_FollowsCursors(t *testing.T) {
+	pages := map[string]string{
+		"":   `{"items":[{"id":"p1"},{"id":"p2"}],"hasMore":true,"total":3,"nextCursor":"c1"}`,
+		"c1": `{"items":[{"id":"p3"}],"hasMore":false,"total":3}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "title", q.Get("sort"))
+		assert.Equal(t, "true", q.Get("isPublic"))
+		assert.Equal(t, "id,title", q.Get("fields"))
+		assert.Equal(t, "2026-01-01T00:00:00Z", q.Get("createdAfter"))
+		fmt.Fprint(w, pages[q.Get("startAfter")])
+	}))
+	defer srv.Close()
+
+	public := true
+	opts := &ListOptions{
+		Sort:         "title",
+		IsPublic:     &public,
+		CreatedAfter: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Fields:       []string{"id", "title"},
+	}
+	var ids []string
+	for p, err := range New(srv.URL).AllProjects(context.Background(), opts) {
+		require.NoError(t, err)
+		ids = append(ids, p.ID)
+	}
+	assert.Equal(t, []string{"p1", "p2", "p3"}, ids)
+	assert.Empty(t, opts.Cursor, "the caller's options are not modified")
+
+	// Breaking out of the loop stops fetching
+	var n int
+	for range New(srv.URL).AllProjects(context.Background(), opts) {
+		n++
+		break
+	}
+	assert.Equal(t, 1, n)
+}
+
+func TestAllComments_PagesByID(t *testing.T) {
+	var starts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "2", q.Get("limit"))
+		starts = append(starts, q.Get("startAfter"))
+		switch q.Get("startAfter") {
+		case "":
+			fmt.Fprint(w, `[{"id":"c1"},{"id":"c2"}]`)
+		case "c2":
+			fmt.Fprint(w, `[{"id":"c3"}]`)
+		}
+	}))
+	defer srv.Close()
+
+	var ids []string
+	for cm, err := range New(srv.URL).AllComments(context.Background(), "g1", &PageOptions{Limit: 2}) {
+		require.NoError(t, err)
+		ids = append(ids, cm.ID)
+	}
+	assert.Equal(t, []string{"c1", "c2", "c3"}, ids)
+	assert.Equal(t, []string{"", "c2"}, starts)
+}
+
+func TestAllNFTs_StopsOnError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"invalid cursor"}`, http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	var errs []error
+	for _, err := range New(srv.URL).AllNFTs(context.Background(), nil) {
+		errs = append(errs, err)
+	}
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], ErrBadRequest)
+}
+
+func TestDownloadBlob_Streams(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "abc", r.URL.Query().Get("v"))
+		w.Header().Set("ETag", `"abc"`)
+		w.Header().Set("Last-Modified", "Fri, 01 May 2026 12:00:00 GMT")
+		w.Header().Set("Content-Type", "image/png")
+		switch {
+		case r.Header.Get("If-None-Match") == `"abc"`:
+			w.WriteHeader(http.StatusNotModified)
+		case r.Header.Get("Range") == "bytes=4-":
+			w.Header().Set("Content-Range", "bytes 4-8/9")
+			w.WriteHeader(http.StatusPartialContent)
+			fmt.Fprint(w, "bytes")
+		default:
+			fmt.Fprint(w, "png bytes")
+		}
+	}))
+	defer srv.Close()
+	c := New(srv.URL)
+	ctx := context.Background()
+
+	blob, err := c.DownloadBlob(ctx, "p1", &BlobOptions{Version: "abc"})
+	require.NoError(t, err)
+	body, _ := io.ReadAll(blob.Body)
+	blob.Body.Close()
+	assert.Equal(t, "png bytes", string(body))
+	assert.Equal(t, "image/png", blob.ContentType)
+	assert.Equal(t, `"abc"`, blob.ETag)
+	assert.Equal(t, time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC), blob.LastModified)
+	assert.False(t, blob.Partial)
+
+	blob, err = c.DownloadBlob(ctx, "p1", &BlobOptions{Version: "abc", Offset: 4})
+	require.NoError(t, err)
+	body, _ = io.ReadAll(blob.Body)
+	blob.Body.Close()
+	assert.Equal(t, "bytes", string(body))
+	assert.True(t, blob.Partial)
+
+	blob, err = c.DownloadBlob(ctx, "p1", &BlobOptions{Version: "abc", IfNoneMatch: blob.ETag})
+	require.NoError(t, err)
+	blob.Body.Close()
+	assert.True(t, blob.NotModified)
+}
+
+func TestWriteUploadChunk_ContentRange(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/api/v1/projects/p1/uploads/u1", r.URL.Path)
+		assert.Equal(t, "bytes 4-6/7", r.Header.Get("Content-Range"))
+		assert.Equal(t, "application/octet-stream", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "end", string(body))
+		fmt.Fprint(w, `{"uploadId":"u1","projectId":"p1","size":7,"offset":7,"status":"open"}`)
+	}))
+	defer srv.Close()
+	c := New(srv.URL)
+
+	s := &UploadSession{UploadID: "u1", ProjectID: "p1", Size: 7, Offset: 4}
+	next, err := c.WriteUploadChunk(context.Background(), s, 4, []byte("end"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), next.Offset)
+
+	_, err = c.WriteUploadChunk(context.Background(), s, 4, []byte("toolong"))
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrBadRequest), "rejected before sending")
+}
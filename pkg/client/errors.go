@@ -0,0 +1,86 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Sentinel errors for the API's error statuses. Match them with errors.Is:
+//
+//	if errors.Is(err, client.ErrNotFound) { ... }
+var (
+	ErrBadRequest        = errors.New("bad request")           // 400
+	ErrUnauthorized      = errors.New("unauthorized")          // 401
+	ErrForbidden         = errors.New("forbidden")             // 403
+	ErrNotFound          = errors.New("not found")             // 404
+	ErrConflict          = errors.New("conflict")              // 409
+	ErrTooLarge          = errors.New("request too large")     // 413
+	ErrRangeNotSatisfied = errors.New("range not satisfiable") // 416
+	ErrRateLimited       = errors.New("rate limited")          // 429
+	ErrServer            = errors.New("server error")          // 5xx
+)
+
+// Error is a non-2xx API response.
+type Error struct {
+	Operation  string // operationId, e.g. getProject
+	StatusCode int
+	Message    string // the response's "error" field, or its status text
+
+	// RetryAfter is the wait the server asked for on a 429; 0 otherwise.
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("paintbar: %s: %d %s", e.Operation, e.StatusCode, e.Message)
+}
+
+// Unwrap returns the sentinel error for the status code, if there is one,
+// so errors.Is matches ErrNotFound and friends.
+func (e *Error) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusBadRequest:
+		return ErrBadRequest
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusRequestEntityTooLarge:
+		return ErrTooLarge
+	case http.StatusRequestedRangeNotSatisfiable:
+		return ErrRangeNotSatisfied
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
+	if e.StatusCode >= 500 {
+		return ErrServer
+	}
+	return nil
+}
+
+// newError reads an error response and closes its body.
+func newError(op string, resp *http.Response) *Error {
+	defer resp.Body.Close()
+	e := &Error{
+		Operation:  op,
+		StatusCode: resp.StatusCode,
+		Message:    http.StatusText(resp.StatusCode),
+	}
+	var body struct {
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&body); err == nil && body.Error != "" {
+		e.Message = body.Error
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		e.RetryAfter = retryAfter(resp.Header.Get("Retry-After"), time.Now())
+	}
+	return e
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"iter"
+)
+
+// ListGalleryItems returns one page of the user's gallery summaries.
+func (c *Client) ListGalleryItems(ctx context.Context, opts *ListOptions) (*Page[GallerySummary], error) {
+	var page Page[GallerySummary]
+	if err := c.call(ctx, &request{op: "listGalleryItems", query: opts.values()}, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// AllGalleryItems iterates over all of the user's gallery summaries
+// matching opts, fetching further pages as needed.
+func (c *Client) AllGalleryItems(ctx context.Context, opts *ListOptions) iter.Seq2[GallerySummary, error] {
+	return allPages(ctx, opts, c.ListGalleryItems)
+}
+
+// CountGalleryItems returns how many gallery items the user has.
+func (c *Client) CountGalleryItems(ctx context.Context) (int64, error) {
+	return c.count(ctx, "countGalleryItems")
+}
+
+// GetGalleryItem returns a gallery item by ID.
+func (c *Client) GetGalleryItem(ctx context.Context, id string) (*GalleryItem, error) {
+	var item GalleryItem
+	if err := c.call(ctx, &request{op: "getGalleryItem", path: map[string]string{"id": id}}, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// ShareToGallery shares an artwork and returns the new item's ID.
+func (c *Client) ShareToGallery(ctx context.Context, item *GalleryItemCreate) (string, error) {
+	return c.create(ctx, &request{op: "shareToGallery", json: item})
+}
+
+// DeleteGalleryItem deletes a gallery item.
+func (c *Client) DeleteGalleryItem(ctx context.Context, id string) error {
+	return c.call(ctx, &request{op: "deleteGalleryItem", path: map[string]string{"id": id}}, nil)
+}
+
+// GetGalleryThumbnail streams the item's thumbnail image. Only IfNoneMatch
+// of opts applies.
+func (c *Client) GetGalleryThumbnail(ctx context.Context, id string, opts *BlobOptions) (*Blob, error) {
+	return c.fetchBlob(ctx, &request{op: "getGalleryThumbnail", path: map[string]string{"id": id}}, conditional(opts))
+}
+
+// ListComments returns one page of the comments on a gallery item, oldest
+// first.
+func (c *Client) ListComments(ctx context.Context, itemID string, opts *PageOptions) ([]Comment, error) {
+	var comments []Comment
+	req := &request{op: "listComments", path: map[string]string{"id": itemID}, query: opts.values()}
+	if err := c.call(ctx, req, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// AllComments iterates over all comments on a gallery item, oldest first.
+func (c *Client) AllComments(ctx context.Context, itemID string, opts *PageOptions) iter.Seq2[Comment, error] {
+	return allByID(ctx, opts, func(cm Comment) string { return cm.ID },
+		func(ctx context.Context, o *PageOptions) ([]Comment, error) { return c.ListComments(ctx, itemID, o) })
+}
+
+// CreateComment comments on a gallery item and returns the comment's ID.
+func (c *Client) CreateComment(ctx context.Context, itemID string, comment *CommentCreate) (string, error) {
+	return c.create(ctx, &request{op: "createComment", path: map[string]string{"id": itemID}, json: comment})
+}
+
+// EditComment replaces the body of one of the user's comments.
+func (c *Client) EditComment(ctx context.Context, itemID, commentID, body string) error {
+	req := &request{
+		op:   "editComment",
+		path: map[string]string{"id": itemID, "commentId": commentID},
+		json: map[string]string{"body": body},
+	}
+	return c.call(ctx, req, nil)
+}
+
+// DeleteComment deletes a comment and its replies.
+func (c *Client) DeleteComment(ctx context.Context, itemID, commentID string) error {
+	req := &request{op: "deleteComment", path: map[string]string{"id": itemID, "commentId": commentID}}
+	return c.call(ctx, req, nil)
+}
+
+// GetReactions returns the reaction counts on a gallery item and the
+// user's own reactions.
+func (c *Client) GetReactions(ctx context.Context, itemID string) (*ReactionSummary, error) {
+	var s ReactionSummary
+	if err := c.call(ctx, &request{op: "getReactions", path: map[string]string{"id": itemID}}, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// AddReaction reacts to a gallery item with one of the Reaction constants.
+// Adding a reaction twice is a no-op.
+func (c *Client) AddReaction(ctx context.Context, itemID, reaction string) error {
+	req := &request{
+		op:   "addReaction",
+		path: map[string]string{"id": itemID},
+		json: map[string]string{"reaction": reaction},
+	}
+	return c.call(ctx, req, nil)
+}
+
+// RemoveReaction removes one of the user's reactions. Removing a reaction
+// that is not there is a no-op.
+func (c *Client) RemoveReaction(ctx context.Context, itemID, reaction string) error {
+	req := &request{op: "removeReaction", path: map[string]string{"id": itemID, "reaction": reaction}}
+	return c.call(ctx, req, nil)
+}
+
+// create calls one of the endpoints that answer with the new resource's ID.
+func (c *Client) create(ctx context.Context, req *request) (string, error) {
+	var res struct {
+		ID string `json:"id"`
+	}
+	if err := c.call(ctx, req, &res); err != nil {
+		return "", err
+	}
+	return res.ID, nil
+}
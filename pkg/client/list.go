@@ -0,0 +1,149 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ListOptions are the query parameters of the project, gallery and NFT
+// list endpoints. The zero value lists the newest items first with the
+// server's default page size.
+type ListOptions struct {
+	Limit  int    // items per page, 1-50; 0 uses the server default
+	Cursor string // nextCursor from the previous page
+
+	Sort  string // createdAt, updatedAt or title
+	Order string // asc or desc
+
+	Tag           string
+	IsPublic      *bool // isListed for NFTs
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+
+	// Fields trims the summaries to the named fields
+	Fields []string
+}
+
+func (o *ListOptions) values() url.Values {
+	q := url.Values{}
+	if o == nil {
+		return q
+	}
+	if o.Limit > 0 {
+		q.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Cursor != "" {
+		q.Set("startAfter", o.Cursor)
+	}
+	if o.Sort != "" {
+		q.Set("sort", o.Sort)
+	}
+	if o.Order != "" {
+		q.Set("order", o.Order)
+	}
+	if o.Tag != "" {
+		q.Set("tag", o.Tag)
+	}
+	if o.IsPublic != nil {
+		q.Set("isPublic", strconv.FormatBool(*o.IsPublic))
+	}
+	if !o.CreatedAfter.IsZero() {
+		q.Set("createdAfter", o.CreatedAfter.Format(time.RFC3339))
+	}
+	if !o.CreatedBefore.IsZero() {
+		q.Set("createdBefore", o.CreatedBefore.Format(time.RFC3339))
+	}
+	if len(o.Fields) > 0 {
+		q.Set("fields", strings.Join(o.Fields, ","))
+	}
+	return q
+}
+
+// PageOptions are the query parameters of the endpoints paged by the last
+// item's ID: account activity and comments.
+type PageOptions struct {
+	Limit      int    // items per page, 1-50; 0 uses the server default
+	StartAfter string // ID of the last item on the previous page
+}
+
+func (o *PageOptions) values() url.Values {
+	q := url.Values{}
+	if o == nil {
+		return q
+	}
+	if o.Limit > 0 {
+		q.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.StartAfter != "" {
+		q.Set("startAfter", o.StartAfter)
+	}
+	return q
+}
+
+// allPages iterates over every item of a cursor-paged list, fetching pages
+// as the loop consumes them. Iteration stops at the first error.
+func allPages[T any](ctx context.Context, opts *ListOptions, list func(context.Context, *ListOptions) (*Page[T], error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var o ListOptions
+		if opts != nil {
+			o = *opts
+		}
+		for {
+			page, err := list(ctx, &o)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if !page.HasMore || page.NextCursor == "" {
+				return
+			}
+			o.Cursor = page.NextCursor
+		}
+	}
+}
+
+// allByID iterates over every item of a list paged by the last item's ID.
+// A page shorter than the requested limit ends the list.
+func allByID[T any](ctx context.Context, opts *PageOptions, id func(T) string, list func(context.Context, *PageOptions) ([]T, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var o PageOptions
+		if opts != nil {
+			o = *opts
+		}
+		if o.Limit <= 0 {
+			o.Limit = 50
+		}
+		for {
+			items, err := list(ctx, &o)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if len(items) < o.Limit {
+				return
+			}
+			// A server that ignores startAfter would repeat the page forever
+			last := id(items[len(items)-1])
+			if last == o.StartAfter {
+				return
+			}
+			o.StartAfter = last
+		}
+	}
+}
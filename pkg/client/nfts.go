@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"iter"
+)
+
+// ListNFTs returns one page of the user's NFT summaries. IsPublic in opts
+// filters on isListed; Tag is not supported.
+func (c *Client) ListNFTs(ctx context.Context, opts *ListOptions) (*Page[NFTSummary], error) {
+	var page Page[NFTSummary]
+	if err := c.call(ctx, &request{op: "listNFTs", query: opts.values()}, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// AllNFTs iterates over all of the user's NFT summaries matching opts,
+// fetching further pages as needed.
+func (c *Client) AllNFTs(ctx context.Context, opts *ListOptions) iter.Seq2[NFTSummary, error] {
+	return allPages(ctx, opts, c.ListNFTs)
+}
+
+// CountNFTs returns how many NFTs the user has.
+func (c *Client) CountNFTs(ctx context.Context) (int64, error) {
+	return c.count(ctx, "countNFTs")
+}
+
+// GetNFT returns an NFT by ID.
+func (c *Client) GetNFT(ctx context.Context, id string) (*NFT, error) {
+	var n NFT
+	if err := c.call(ctx, &request{op: "getNFT", path: map[string]string{"id": id}}, &n); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// CreateNFT records an NFT and returns its ID. It does not mint on chain.
+func (c *Client) CreateNFT(ctx context.Context, nft *NFTCreate) (string, error) {
+	return c.create(ctx, &request{op: "createNFT", json: nft})
+}
+
+// DeleteNFT deletes an NFT record.
+func (c *Client) DeleteNFT(ctx context.Context, id string) error {
+	return c.call(ctx, &request{op: "deleteNFT", path: map[string]string{"id": id}}, nil)
+}
+
+// GetNFTThumbnail streams the NFT's thumbnail image. Only IfNoneMatch of
+// opts applies.
+func (c *Client) GetNFTThumbnail(ctx context.Context, id string, opts *BlobOptions) (*Blob, error) {
+	return c.fetchBlob(ctx, &request{op: "getNFTThumbnail", path: map[string]string{"id": id}}, conditional(opts))
+}
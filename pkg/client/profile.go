@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"iter"
+)
+
+// Ping checks that the client's token is accepted. It works for personal
+// access tokens of any scope, unlike GetProfile.
+func (c *Client) Ping(ctx context.Context) error {
+	return c.call(ctx, &request{op: "ping"}, nil)
+}
+
+// GetProfile returns the signed-in user's profile.
+func (c *Client) GetProfile(ctx context.Context) (*User, error) {
+	var u User
+	if err := c.call(ctx, &request{op: "getProfile"}, &u); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// UpdateProfile changes the non-nil fields of update.
+func (c *Client) UpdateProfile(ctx context.Context, update *UserUpdate) error {
+	return c.call(ctx, &request{op: "updateProfile", json: update}, nil)
+}
+
+// ClaimUsername sets the user's username. Usernames cannot be changed once
+// claimed; a taken name returns ErrConflict.
+func (c *Client) ClaimUsername(ctx context.Context, username string) error {
+	body := map[string]string{"username": username}
+	return c.call(ctx, &request{op: "claimUsername", json: body}, nil)
+}
+
+// ListAccountActivity returns one page of the user's audit log, newest
+// first.
+func (c *Client) ListAccountActivity(ctx context.Context, opts *PageOptions) ([]AuditEntry, error) {
+	var entries []AuditEntry
+	if err := c.call(ctx, &request{op: "listAccountActivity", query: opts.values()}, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// AllAccountActivity iterates over the user's whole audit log, newest
+// first.
+func (c *Client) AllAccountActivity(ctx context.Context, opts *PageOptions) iter.Seq2[AuditEntry, error] {
+	return allByID(ctx, opts, func(e AuditEntry) string { return e.ID }, c.ListAccountActivity)
+}
@@ -0,0 +1,187 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+)
+
+// ListProjects returns one page of the user's project summaries.
+func (c *Client) ListProjects(ctx context.Context, opts *ListOptions) (*Page[ProjectSummary], error) {
+	var page Page[ProjectSummary]
+	if err := c.call(ctx, &request{op: "listProjects", query: opts.values()}, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// AllProjects iterates over all of the user's project summaries matching
+// opts, fetching further pages as needed.
+func (c *Client) AllProjects(ctx context.Context, opts *ListOptions) iter.Seq2[ProjectSummary, error] {
+	return allPages(ctx, opts, c.ListProjects)
+}
+
+// CountProjects returns how many projects the user has.
+func (c *Client) CountProjects(ctx context.Context) (int64, error) {
+	return c.count(ctx, "countProjects")
+}
+
+// GetProject returns a project by ID.
+func (c *Client) GetProject(ctx context.Context, id string) (*Project, error) {
+	var p Project
+	if err := c.call(ctx, &request{op: "getProject", path: map[string]string{"id": id}}, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// GetProjectByTitle returns the user's project with the given title.
+func (c *Client) GetProjectByTitle(ctx context.Context, title string) (*Project, error) {
+	var p Project
+	req := &request{op: "getProjectByTitle", query: url.Values{"title": {title}}}
+	if err := c.call(ctx, req, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// CreateProject creates a project, or updates the user's project with the
+// same title. Upload the PNG with UploadBlob and then ConfirmUpload unless
+// the result is a Duplicate.
+func (c *Client) CreateProject(ctx context.Context, project *ProjectCreate) (*CreateProjectResult, error) {
+	var res CreateProjectResult
+	if err := c.call(ctx, &request{op: "createProject", json: project}, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// UpdateProject changes the non-nil fields of update.
+func (c *Client) UpdateProject(ctx context.Context, id string, update *ProjectUpdate) error {
+	return c.call(ctx, &request{op: "updateProject", path: map[string]string{"id": id}, json: update}, nil)
+}
+
+// DeleteProject deletes a project and its blob.
+func (c *Client) DeleteProject(ctx context.Context, id string) error {
+	return c.call(ctx, &request{op: "deleteProject", path: map[string]string{"id": id}}, nil)
+}
+
+// UploadBlob streams a PNG of up to 10 MB as the project's blob. If png is
+// an io.Seeker the upload is retried when rate limited.
+func (c *Client) UploadBlob(ctx context.Context, id string, png io.Reader) error {
+	return c.call(ctx, &request{
+		op:          "uploadBlob",
+		path:        map[string]string{"id": id},
+		body:        png,
+		contentType: "image/png",
+	}, nil)
+}
+
+// ConfirmUpload records the uploaded blob on the project.
+func (c *Client) ConfirmUpload(ctx context.Context, id string) error {
+	return c.call(ctx, &request{op: "confirmUpload", path: map[string]string{"id": id}}, nil)
+}
+
+// DownloadBlob streams the project's full-resolution PNG. The caller must
+// close the returned Blob's Body.
+func (c *Client) DownloadBlob(ctx context.Context, id string, opts *BlobOptions) (*Blob, error) {
+	return c.fetchBlob(ctx, &request{op: "downloadBlob", path: map[string]string{"id": id}}, opts)
+}
+
+// GetProjectThumbnail streams the project's thumbnail image. Only
+// IfNoneMatch of opts applies.
+func (c *Client) GetProjectThumbnail(ctx context.Context, id string, opts *BlobOptions) (*Blob, error) {
+	return c.fetchBlob(ctx, &request{op: "getProjectThumbnail", path: map[string]string{"id": id}}, conditional(opts))
+}
+
+// InitiateUpload starts a resumable upload of a blob of up to 50 MB.
+// contentHash must be the project's content hash.
+func (c *Client) InitiateUpload(ctx context.Context, projectID string, size int64, contentHash string) (*UploadSession, error) {
+	var s UploadSession
+	req := &request{
+		op:   "initiateUpload",
+		path: map[string]string{"id": projectID},
+		json: map[string]interface{}{"size": size, "contentHash": contentHash},
+	}
+	if err := c.call(ctx, req, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// GetUpload returns an upload session; its Offset is where the next chunk
+// must start.
+func (c *Client) GetUpload(ctx context.Context, projectID, uploadID string) (*UploadSession, error) {
+	var s UploadSession
+	req := &request{op: "getUpload", path: map[string]string{"id": projectID, "uploadId": uploadID}}
+	if err := c.call(ctx, req, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// WriteUploadChunk sends chunk as the bytes of the session's blob starting
+// at offset, which must be the session's Offset. Chunks are at most 8 MiB
+// and all but the last at least 256 KiB.
+func (c *Client) WriteUploadChunk(ctx context.Context, s *UploadSession, offset int64, chunk []byte) (*UploadSession, error) {
+	end := offset + int64(len(chunk))
+	if len(chunk) == 0 || end > s.Size {
+		return nil, fmt.Errorf("writeUploadChunk: chunk %d-%d is outside the %d byte upload", offset, end, s.Size)
+	}
+	var next UploadSession
+	req := &request{
+		op:          "writeUploadChunk",
+		path:        map[string]string{"id": s.ProjectID, "uploadId": s.UploadID},
+		header:      http.Header{"Content-Range": {fmt.Sprintf("bytes %d-%d/%d", offset, end-1, s.Size)}},
+		body:        bytes.NewReader(chunk),
+		contentType: "application/octet-stream",
+	}
+	if err := c.call(ctx, req, &next); err != nil {
+		return nil, err
+	}
+	return &next, nil
+}
+
+// FinalizeUpload queues the job that verifies and stores a fully received
+// upload. Poll GetJob with the returned session's JobID.
+func (c *Client) FinalizeUpload(ctx context.Context, projectID, uploadID string) (*UploadSession, error) {
+	var s UploadSession
+	req := &request{op: "finalizeUpload", path: map[string]string{"id": projectID, "uploadId": uploadID}}
+	if err := c.call(ctx, req, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// GetJob returns a background job the user started.
+func (c *Client) GetJob(ctx context.Context, id string) (*Job, error) {
+	var j Job
+	if err := c.call(ctx, &request{op: "getJob", path: map[string]string{"id": id}}, &j); err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+// count calls one of the count endpoints.
+func (c *Client) count(ctx context.Context, op string) (int64, error) {
+	var res struct {
+		Count int64 `json:"count"`
+	}
+	if err := c.call(ctx, &request{op: op}, &res); err != nil {
+		return 0, err
+	}
+	return res.Count, nil
+}
+
+// conditional keeps only the conditional part of opts, for the thumbnail
+// endpoints.
+func conditional(opts *BlobOptions) *BlobOptions {
+	if opts == nil {
+		return nil
+	}
+	return &BlobOptions{IfNoneMatch: opts.IfNoneMatch}
+}
@@ -0,0 +1,303 @@
+package client
+
+import "time"
+
+// Types mirror the schemas in api/openapi.yaml; the JSON field names of
+// each are checked against its schema in the package tests.
+
+// User is the signed-in user's profile (schema User).
+type User struct {
+	UID             string    `json:"uid"`
+	Email           string    `json:"email,omitempty"`
+	Username        string    `json:"username,omitempty"`
+	DisplayName     string    `json:"displayName,omitempty"`
+	Bio             string    `json:"bio,omitempty"`
+	Location        string    `json:"location,omitempty"`
+	Website         string    `json:"website,omitempty"`
+	GithubURL       string    `json:"githubUrl,omitempty"`
+	TwitterHandle   string    `json:"twitterHandle,omitempty"`
+	BlueskyHandle   string    `json:"blueskyHandle,omitempty"`
+	InstagramHandle string    `json:"instagramHandle,omitempty"`
+	HbarAddress     string    `json:"hbarAddress,omitempty"`
+	UseGravatar     bool      `json:"useGravatar,omitempty"`
+	FollowerCount   int64     `json:"followerCount,omitempty"`
+	FollowingCount  int64     `json:"followingCount,omitempty"`
+	ProjectCount    int64     `json:"projectCount,omitempty"`
+	GalleryCount    int64     `json:"galleryCount,omitempty"`
+	NFTCount        int64     `json:"nftCount,omitempty"`
+	Suspended       bool      `json:"suspended,omitempty"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+// UserUpdate is a partial profile update (schema UserUpdate). Only non-nil
+// fields are changed.
+type UserUpdate struct {
+	DisplayName     *string `json:"displayName,omitempty"`
+	Bio             *string `json:"bio,omitempty"`
+	Location        *string `json:"location,omitempty"`
+	Website         *string `json:"website,omitempty"`
+	GithubURL       *string `json:"githubUrl,omitempty"`
+	TwitterHandle   *string `json:"twitterHandle,omitempty"`
+	BlueskyHandle   *string `json:"blueskyHandle,omitempty"`
+	InstagramHandle *string `json:"instagramHandle,omitempty"`
+	HbarAddress     *string `json:"hbarAddress,omitempty"`
+	UseGravatar     *bool   `json:"useGravatar,omitempty"`
+}
+
+// AuditEntry is one entry in the account activity log (schema AuditEntry).
+type AuditEntry struct {
+	ID           string                 `json:"id"`
+	ActorUID     string                 `json:"actorUid"`
+	IP           string                 `json:"ip,omitempty"`
+	RequestID    string                 `json:"requestId,omitempty"`
+	Action       string                 `json:"action"`
+	ResourceType string                 `json:"resourceType"`
+	ResourceID   string                 `json:"resourceId"`
+	Changes      map[string]AuditChange `json:"changes,omitempty"`
+	Details      map[string]interface{} `json:"details,omitempty"`
+	CreatedAt    time.Time              `json:"createdAt"`
+}
+
+// AuditChange is the before and after value of one changed field.
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Project is a saved canvas (schema Project).
+type Project struct {
+	ID            string    `json:"id"`
+	UserID        string    `json:"userId"`
+	Title         string    `json:"title"`
+	ContentHash   string    `json:"contentHash,omitempty"`
+	StorageURL    string    `json:"storageURL,omitempty"`
+	ThumbnailData string    `json:"thumbnailData,omitempty"`
+	Width         int       `json:"width,omitempty"`
+	Height        int       `json:"height,omitempty"`
+	IsPublic      bool      `json:"isPublic"`
+	Tags          []string  `json:"tags,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// ProjectSummary is the list projection of a Project (schema
+// ProjectSummary). Fields left out by ListOptions.Fields are zero.
+type ProjectSummary struct {
+	ID           string    `json:"id,omitempty"`
+	Title        string    `json:"title,omitempty"`
+	ContentHash  string    `json:"contentHash,omitempty"`
+	Width        int       `json:"width,omitempty"`
+	Height       int       `json:"height,omitempty"`
+	IsPublic     bool      `json:"isPublic,omitempty"`
+	Tags         []string  `json:"tags,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+	ThumbnailURL string    `json:"thumbnailUrl,omitempty"`
+}
+
+// ProjectCreate creates a project, or updates the one with the same title
+// (schema ProjectCreate).
+type ProjectCreate struct {
+	Title         string   `json:"title"`
+	ContentHash   string   `json:"contentHash"`
+	ThumbnailData string   `json:"thumbnailData"`
+	Width         int      `json:"width"`
+	Height        int      `json:"height"`
+	IsPublic      bool     `json:"isPublic,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+// CreateProjectResult is returned by CreateProject (schema
+// CreateProjectResult). Duplicate is set when a project with the same
+// content hash already existed.
+type CreateProjectResult struct {
+	ProjectID string `json:"projectId"`
+	Duplicate bool   `json:"duplicate"`
+}
+
+// ProjectUpdate is a partial project update (schema ProjectUpdate). Only
+// non-nil fields are changed; an empty, non-nil Tags clears the tags.
+type ProjectUpdate struct {
+	Title    *string  `json:"title,omitempty"`
+	IsPublic *bool    `json:"isPublic,omitempty"`
+	Tags     []string `json:"tags,omitzero"`
+}
+
+// UploadSession is a resumable blob upload (schema UploadSession).
+type UploadSession struct {
+	UploadID    string    `json:"uploadId"`
+	ProjectID   string    `json:"projectId"`
+	Size        int64     `json:"size"`
+	ContentHash string    `json:"contentHash"`
+	Offset      int64     `json:"offset"`
+	Status      string    `json:"status"` // open or finalizing
+	JobID       string    `json:"jobId,omitempty"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// Job is a background job, such as an upload finalize (schema Job).
+type Job struct {
+	ID          string                 `json:"id"`
+	Type        string                 `json:"type"`
+	Status      string                 `json:"status"` // queued, running, succeeded or failed
+	Attempts    int                    `json:"attempts"`
+	MaxAttempts int                    `json:"maxAttempts"`
+	LastError   string                 `json:"lastError,omitempty"`
+	Result      map[string]interface{} `json:"result,omitempty"`
+	RunAt       time.Time              `json:"runAt"`
+	CompletedAt *time.Time             `json:"completedAt,omitempty"`
+	CreatedAt   time.Time              `json:"createdAt"`
+	UpdatedAt   time.Time              `json:"updatedAt"`
+}
+
+// Job statuses.
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+// Done reports whether the job has finished, successfully or not.
+func (j *Job) Done() bool {
+	return j.Status == JobSucceeded || j.Status == JobFailed
+}
+
+// GalleryItem is a shared artwork (schema GalleryItem).
+type GalleryItem struct {
+	ID             string           `json:"id"`
+	UserID         string           `json:"userId"`
+	ProjectID      string           `json:"projectId,omitempty"`
+	Name           string           `json:"name"`
+	Description    string           `json:"description,omitempty"`
+	ImageData      string           `json:"imageData,omitempty"`
+	ThumbnailData  string           `json:"thumbnailData,omitempty"`
+	Width          int              `json:"width,omitempty"`
+	Height         int              `json:"height,omitempty"`
+	Tags           []string         `json:"tags,omitempty"`
+	Hidden         bool             `json:"hidden,omitempty"`
+	CommentCount   int64            `json:"commentCount,omitempty"`
+	ReactionCounts map[string]int64 `json:"reactionCounts,omitempty"`
+	CreatedAt      time.Time        `json:"createdAt"`
+}
+
+// GallerySummary is the list projection of a GalleryItem (schema
+// GallerySummary). Fields left out by ListOptions.Fields are zero.
+type GallerySummary struct {
+	ID             string           `json:"id,omitempty"`
+	ProjectID      string           `json:"projectId,omitempty"`
+	Name           string           `json:"name,omitempty"`
+	Width          int              `json:"width,omitempty"`
+	Height         int              `json:"height,omitempty"`
+	Tags           []string         `json:"tags,omitempty"`
+	Hidden         bool             `json:"hidden,omitempty"`
+	CommentCount   int64            `json:"commentCount,omitempty"`
+	ReactionCounts map[string]int64 `json:"reactionCounts,omitempty"`
+	CreatedAt      time.Time        `json:"createdAt"`
+	ThumbnailURL   string           `json:"thumbnailUrl,omitempty"`
+}
+
+// GalleryItemCreate shares an artwork (schema GalleryItemCreate).
+type GalleryItemCreate struct {
+	Name          string   `json:"name"`
+	Description   string   `json:"description,omitempty"`
+	ProjectID     string   `json:"projectId,omitempty"`
+	ImageData     string   `json:"imageData,omitempty"`
+	ThumbnailData string   `json:"thumbnailData,omitempty"`
+	Width         int      `json:"width,omitempty"`
+	Height        int      `json:"height,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+// Comment is a comment on a gallery item (schema Comment).
+type Comment struct {
+	ID        string    `json:"id"`
+	ItemID    string    `json:"itemId"`
+	UserID    string    `json:"userId"`
+	Username  string    `json:"username,omitempty"`
+	Body      string    `json:"body"`
+	ParentID  string    `json:"parentId,omitempty"`
+	Edited    bool      `json:"edited,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// CommentCreate posts a comment, or a reply when ParentID is set (schema
+// CommentCreate).
+type CommentCreate struct {
+	Body     string `json:"body"`
+	ParentID string `json:"parentId,omitempty"`
+}
+
+// Reactions the API accepts (schema Reaction).
+const (
+	ReactionHeart = "heart"
+	ReactionFire  = "fire"
+	ReactionLaugh = "laugh"
+	ReactionWow   = "wow"
+	ReactionClap  = "clap"
+	ReactionSad   = "sad"
+)
+
+// ReactionSummary is the reaction counts on an item and the caller's own
+// reactions (schema ReactionSummary).
+type ReactionSummary struct {
+	Counts map[string]int64 `json:"counts"`
+	Mine   []string         `json:"mine"`
+}
+
+// NFT is a minted or pending NFT record (schema NFT).
+type NFT struct {
+	ID            string    `json:"id"`
+	UserID        string    `json:"userId"`
+	Name          string    `json:"name"`
+	Description   string    `json:"description,omitempty"`
+	ImageData     string    `json:"imageData,omitempty"`
+	ImageURL      string    `json:"imageUrl,omitempty"`
+	ThumbnailData string    `json:"thumbnailData,omitempty"`
+	TokenID       string    `json:"tokenId,omitempty"`
+	SerialNumber  int64     `json:"serialNumber,omitempty"`
+	TransactionID string    `json:"transactionId,omitempty"`
+	Metadata      string    `json:"metadata,omitempty"`
+	Price         float64   `json:"price"`
+	IsListed      bool      `json:"isListed"`
+	Hidden        bool      `json:"hidden,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// NFTSummary is the list projection of an NFT (schema NFTSummary). Fields
+// left out by ListOptions.Fields are zero.
+type NFTSummary struct {
+	ID           string    `json:"id,omitempty"`
+	Name         string    `json:"name,omitempty"`
+	ImageURL     string    `json:"imageUrl,omitempty"`
+	TokenID      string    `json:"tokenId,omitempty"`
+	SerialNumber int64     `json:"serialNumber,omitempty"`
+	Price        float64   `json:"price,omitempty"`
+	IsListed     bool      `json:"isListed,omitempty"`
+	Hidden       bool      `json:"hidden,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+	ThumbnailURL string    `json:"thumbnailUrl,omitempty"`
+}
+
+// NFTCreate records a new NFT (schema NFTCreate).
+type NFTCreate struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	ImageData   string  `json:"imageData,omitempty"`
+	Price       float64 `json:"price,omitempty"`
+}
+
+// Page is one page of a list endpoint (schemas ProjectPage, GalleryPage
+// and NFTPage).
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	HasMore    bool   `json:"hasMore"`
+	Total      int64  `json:"total"`
+}
@@ -2,7 +2,8 @@
 // Login page — handles sign-in, sign-up, and auth state
 // ============================================================
 
-import { auth, onAuthStateChanged } from "../shared/firebase-init";
+import { auth, onAuthStateChanged, signOut } from "../shared/firebase-init";
+import { startSession } from "../shared/session";
 import { showError } from "../shared/toast";
 import {
   signInWithEmailAndPassword,
@@ -53,16 +54,8 @@ loginForm.addEventListener("submit", async (e) => {
     .value;
 
   try {
-    const userCredential = await signInWithEmailAndPassword(
-      auth,
-      email,
-      password,
-    );
-    const user = userCredential.user;
-
-    if (user) {
-      window.location.href = "/profile";
-    }
+    // The auth state observer below starts the server session and redirects
+    await signInWithEmailAndPassword(auth, email, password);
   } catch (error) {
     console.error("Login error:", error);
     let errorMessage = "An error occurred during login.";
@@ -106,16 +99,8 @@ signupForm.addEventListener("submit", async (e) => {
   }
 
   try {
-    const userCredential = await createUserWithEmailAndPassword(
-      auth,
-      email,
-      password,
-    );
-    const user = userCredential.user;
-
-    if (user) {
-      window.location.href = "/profile";
-    }
+    // The auth state observer below starts the server session and redirects
+    await createUserWithEmailAndPassword(auth, email, password);
   } catch (error) {
     console.error("Signup error:", error);
     let errorMessage = "An error occurred during signup.";
@@ -138,9 +123,17 @@ signupForm.addEventListener("submit", async (e) => {
   }
 });
 
-// Auth state observer — redirect to profile if already logged in
-onAuthStateChanged(auth, (user) => {
-  if (user) {
+// Auth state observer — start the server session and redirect to profile
+// once signed in. A sign-in older than a few minutes cannot start a session,
+// so that user is signed out and asked to sign in again.
+onAuthStateChanged(auth, async (user) => {
+  if (!user) return;
+  try {
+    await startSession(user);
     window.location.href = "/profile";
+  } catch (error) {
+    console.error("Session error:", error);
+    await signOut(auth);
+    showError("Please sign in again.");
   }
 });
@@ -12,7 +12,8 @@ import { SaveManager } from "./save";
 import { ProjectManager } from "./project";
 import { ToolManager } from "./toolManager";
 import { CanvasManager } from "./canvasManager";
-import { auth, onAuthStateChanged } from "../shared/firebase-init";
+import { auth, onAuthStateChanged, signOut } from "../shared/firebase-init";
+import { apiFetch } from "../shared/api";
 import "../shared/errors";
 import type { Point, TriangleType, CanvasOptions, RGBA } from "../shared/types";
 
@@ -1462,10 +1463,7 @@ document.addEventListener("DOMContentLoaded", () => {
     const projectTitle = new URLSearchParams(window.location.search).get("project");
     if (projectTitle) {
       try {
-        const token = await user.getIdToken();
-        const res = await fetch(`/api/projects/by-title?title=${encodeURIComponent(projectTitle)}`, {
-          headers: { Authorization: `Bearer ${token}` },
-        });
+        const res = await apiFetch(`/api/v1/projects/by-title?title=${encodeURIComponent(projectTitle)}`);
         if (!res.ok) throw new Error(`Failed to load project (${res.status})`);
 
         const project = await res.json();
@@ -1480,7 +1478,7 @@ document.addEventListener("DOMContentLoaded", () => {
           window.location.href = "/profile";
         });
         document.getElementById("logoutBtn")?.addEventListener("click", () => {
-          auth.signOut().catch((error: unknown) => {
+          signOut(auth).catch((error: unknown) => {
             console.error("Error signing out:", error);
           });
         });
@@ -1488,10 +1486,12 @@ document.addEventListener("DOMContentLoaded", () => {
         const paintBar = new PaintBar({ width: w, height: h });
 
         if (project.contentHash) {
-          // Download via API proxy (avoids CORS/auth issues with direct storage URLs)
-          const blobRes = await fetch(`/api/projects/${encodeURIComponent(project.id)}/blob`, {
-            headers: { Authorization: `Bearer ${token}` },
-          });
+          // Download via API proxy (avoids CORS/auth issues with direct storage URLs).
+          // The ?v= content hash makes the URL immutable so the browser can cache it.
+          const blobURLPath =
+            `/api/v1/projects/${encodeURIComponent(project.id)}/blob` +
+            `?v=${encodeURIComponent(project.contentHash)}`;
+          const blobRes = await apiFetch(blobURLPath);
           if (blobRes.ok) {
             const imgBlob = await blobRes.blob();
             const blobURL = URL.createObjectURL(imgBlob);
@@ -1641,7 +1641,7 @@ document.addEventListener("DOMContentLoaded", () => {
     });
 
     logoutBtn?.addEventListener("click", () => {
-      auth.signOut().catch((error: unknown) => {
+      signOut(auth).catch((error: unknown) => {
         console.error("Error signing out:", error);
       });
     });
@@ -2,10 +2,10 @@
 // ProjectManager — handles saving canvas projects to the server
 // ============================================================
 
-import { auth } from "../shared/firebase-init";
+import { apiFetch } from "../shared/api";
 import type { PaintBar } from "./app";
 
-/** Response from POST /api/projects */
+/** Response from POST /api/v1/projects */
 interface CreateProjectResult {
   projectId: string;
   duplicate: boolean;
@@ -18,8 +18,8 @@ const THUMBNAIL_MAX_SIZE = 256;
  * ProjectManager handles the full save-project flow:
  * 1. Hash the canvas PNG blob (SHA-256)
  * 2. Generate a thumbnail
- * 3. POST /api/projects (dedup check + create/upsert record)
- * 4. POST /api/projects/{id}/upload-blob (server proxies to Storage)
+ * 3. POST /api/v1/projects (dedup check + create/upsert record)
+ * 4. POST /api/v1/projects/{id}/upload-blob (server proxies to Storage)
  */
 export class ProjectManager {
   private paintBar: PaintBar;
@@ -110,17 +110,6 @@ export class ProjectManager {
     if (this.closeBtn) (this.closeBtn as HTMLButtonElement).disabled = disabled;
   }
 
-  /**
-   * Get the current user's Firebase ID token for API authentication.
-   */
-  private async getIdToken(): Promise<string> {
-    const user = auth.currentUser;
-    if (!user) {
-      throw new Error("Not authenticated");
-    }
-    return user.getIdToken();
-  }
-
   /**
    * Export the canvas (drawing + opaque background) as a PNG Blob.
    */
@@ -226,13 +215,9 @@ export class ProjectManager {
 
       // Step 2: Create project via API
       this.setStatus("Creating project...", "info");
-      const token = await this.getIdToken();
-      const createRes = await fetch("/api/projects", {
+      const createRes = await apiFetch("/api/v1/projects", {
         method: "POST",
-        headers: {
-          "Content-Type": "application/json",
-          Authorization: `Bearer ${token}`,
-        },
+        headers: { "Content-Type": "application/json" },
         body: JSON.stringify({
           title,
           contentHash,
@@ -267,14 +252,11 @@ export class ProjectManager {
 
       // Step 3: Upload blob via server proxy
       this.setStatus("Uploading canvas...", "info");
-      const uploadRes = await fetch(
-        `/api/projects/${result.projectId}/upload-blob`,
+      const uploadRes = await apiFetch(
+        `/api/v1/projects/${result.projectId}/upload-blob`,
         {
           method: "POST",
-          headers: {
-            "Content-Type": "image/png",
-            Authorization: `Bearer ${token}`,
-          },
+          headers: { "Content-Type": "image/png" },
           body: blob,
         },
       );
@@ -8,6 +8,7 @@ import {
   sendPasswordResetEmail,
   signOut,
 } from "../shared/firebase-init";
+import { apiFetch } from "../shared/api";
 import { showSuccess, showError, bindUnderConstruction } from "../shared/toast";
 import { applyProfileImage, DEFAULT_PROFILE_IMAGE } from "../shared/gravatar";
 import {
@@ -366,7 +367,10 @@ async function handleAuthStateChanged(
     userDocRef,
     async (docSnapshot) => {
       try {
-        if (!docSnapshot.exists() && isFirstSnapshot) {
+        // The backend may have created the document with only its counters
+        // (e.g. after a project was created through the API), so initialize
+        // any document without a uid and merge to keep the counters.
+        if (isFirstSnapshot && !docSnapshot.data()?.uid) {
           const initialUserData: ProfileData = {
             uid: user.uid,
             email: user.email || "",
@@ -375,7 +379,7 @@ async function handleAuthStateChanged(
             createdAt: new Date(),
             updatedAt: new Date(),
           };
-          await setDoc(userDocRef, initialUserData);
+          await setDoc(userDocRef, initialUserData, { merge: true });
           isFirstSnapshot = false;
           showWelcomeModal();
           return;
@@ -991,14 +995,9 @@ async function handleDeleteProject(
   const safeTitle = title.replace(/[\x00-\x1f\x7f-\x9f]/g, "").slice(0, 50);
   if (!confirm(`Delete "${safeTitle}"? This cannot be undone.`)) return;
 
-  const user = auth.currentUser;
-  if (!user) return;
-
   try {
-    const token = await user.getIdToken();
-    const res = await fetch(`/api/projects/${encodeURIComponent(projectId)}`, {
+    const res = await apiFetch(`/api/v1/projects/${encodeURIComponent(projectId)}`, {
       method: "DELETE",
-      headers: { Authorization: `Bearer ${token}` },
     });
     if (!res.ok) {
       const err = await res.json().catch(() => ({}));
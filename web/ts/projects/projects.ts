@@ -3,6 +3,7 @@
 // ============================================================
 
 import { auth, db, signOut } from "../shared/firebase-init";
+import { apiFetch } from "../shared/api";
 import { showSuccess, showError, bindUnderConstruction } from "../shared/toast";
 import { applyProfileImage } from "../shared/gravatar";
 import {
@@ -240,14 +241,9 @@ async function handleDeleteProject(
   const safeTitle = title.replace(/[\x00-\x1f\x7f-\x9f]/g, "").slice(0, 50);
   if (!confirm(`Delete "${safeTitle}"? This cannot be undone.`)) return;
 
-  const user = auth.currentUser;
-  if (!user) return;
-
   try {
-    const token = await user.getIdToken();
-    const res = await fetch(`/api/projects/${encodeURIComponent(projectId)}`, {
+    const res = await apiFetch(`/api/v1/projects/${encodeURIComponent(projectId)}`, {
       method: "DELETE",
-      headers: { Authorization: `Bearer ${token}` },
     });
     if (!res.ok) {
       const err = await res.json().catch(() => ({}));
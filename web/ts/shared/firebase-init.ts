@@ -7,7 +7,7 @@ import {
   getAuth,
   connectAuthEmulator,
   sendPasswordResetEmail,
-  signOut,
+  signOut as firebaseSignOut,
   onAuthStateChanged,
   type Auth,
 } from "firebase/auth";
@@ -19,6 +19,7 @@ import {
   type Firestore,
 } from "firebase/firestore";
 import { firebaseConfig } from "./firebase-config";
+import { endSession } from "./session";
 
 const isLocal =
   window.location.hostname === "localhost" ||
@@ -50,5 +51,11 @@ if (!isLocal) {
     });
 }
 
+/** Signs out of Firebase and ends the server session cookie. */
+export async function signOut(a: Auth): Promise<void> {
+  await endSession();
+  await firebaseSignOut(a);
+}
+
 // Re-export auth utilities
-export { sendPasswordResetEmail, signOut, onAuthStateChanged };
+export { sendPasswordResetEmail, onAuthStateChanged };
@@ -0,0 +1,35 @@
+// ============================================================
+// Server session — exchanges the Firebase ID token for the
+// HttpOnly session cookie used by the SSR page routes
+// ============================================================
+
+import type { User } from "firebase/auth";
+
+/**
+ * Creates the server session for a freshly signed-in user. The server only
+ * accepts ID tokens from a sign-in within the last five minutes.
+ */
+export async function startSession(user: User): Promise<void> {
+  const idToken = await user.getIdToken();
+  const res = await fetch("/auth/session", {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    credentials: "same-origin",
+    body: JSON.stringify({ idToken }),
+  });
+  if (!res.ok) {
+    throw new Error(`Failed to start session (${res.status})`);
+  }
+}
+
+/** Ends the server session. Failures are logged, never thrown. */
+export async function endSession(): Promise<void> {
+  try {
+    await fetch("/auth/logout", {
+      method: "POST",
+      credentials: "same-origin",
+    });
+  } catch (error) {
+    console.error("Error ending session:", error);
+  }
+}